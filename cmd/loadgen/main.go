@@ -0,0 +1,303 @@
+// Command loadgen simulates tenants sending Telegram updates to the router
+// and reports how the platform keeps up. With -stub it also plays the
+// orchestrator and the tenant pods, so a router can be load tested on its
+// own and every update can be followed through to delivery.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// chatIDBase offsets simulated chat IDs so they never collide with real ones
+const chatIDBase = 9_000_000_000
+
+type config struct {
+	routerURL string
+	tenants   int
+	prefix    string
+	rate      float64
+	duration  time.Duration
+	drain     time.Duration
+	stub      stubConfig
+	useStub   bool
+}
+
+// loadgen sends updates and collects the results
+type loadgen struct {
+	cfg        config
+	httpClient *http.Client
+	seq        atomic.Int64
+
+	sent      atomic.Int64
+	ackErrors sync.Map // status (or "transport") -> *atomic.Int64
+	ackLat    latencies
+
+	// pending maps an update's seq to when and for which tenant it was sent;
+	// only tracked with -stub, where deliveries are observed
+	mu      sync.Mutex
+	pending map[int64]sentUpdate
+}
+
+type sentUpdate struct {
+	tenantID string
+	at       time.Time
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.routerURL, "router", "http://localhost:9090", "router base URL (add /env/<name> to target an environment)")
+	flag.IntVar(&cfg.tenants, "tenants", 10, "number of simulated tenants")
+	flag.StringVar(&cfg.prefix, "prefix", "loadgen-", "tenant ID prefix; tenants are <prefix>0001, <prefix>0002, ...")
+	flag.Float64Var(&cfg.rate, "rate", 0.2, "messages per second per tenant (Poisson arrivals)")
+	flag.DurationVar(&cfg.duration, "duration", time.Minute, "how long to send for")
+	flag.DurationVar(&cfg.drain, "drain", 30*time.Second, "with -stub, how long to wait for outstanding deliveries")
+	flag.BoolVar(&cfg.useStub, "stub", false, "serve a stub orchestrator and tenant pod (point the router's ORCHESTRATOR_ADDR at -stub-addr)")
+	flag.StringVar(&cfg.stub.addr, "stub-addr", ":18080", "stub orchestrator listen address")
+	flag.StringVar(&cfg.stub.podIP, "stub-pod-ip", "127.0.0.1", "IP the router reaches the stub pod at (port 3000)")
+	flag.DurationVar(&cfg.stub.coldStart, "cold-start", 10*time.Second, "stub wake time without a warm pod")
+	flag.DurationVar(&cfg.stub.warmStart, "warm-start", 2*time.Second, "stub wake time when a warm pod is claimed")
+	flag.Float64Var(&cfg.stub.warmRatio, "warm-ratio", 0.8, "fraction of stub wakes that claim a warm pod")
+	flag.Float64Var(&cfg.stub.wakeErrorRate, "wake-error-rate", 0, "fraction of stub wakes that fail")
+	flag.IntVar(&cfg.stub.idleTimeoutS, "idle-timeout", 60, "idle_timeout_s the stub returns; the router re-wakes a tenant after this much silence")
+	flag.Parse()
+
+	if cfg.tenants <= 0 || cfg.rate <= 0 || cfg.duration <= 0 {
+		fmt.Fprintln(os.Stderr, "loadgen: -tenants, -rate and -duration must be positive")
+		os.Exit(2)
+	}
+	cfg.routerURL = strings.TrimRight(cfg.routerURL, "/")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	lg := &loadgen{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		pending:    make(map[int64]sentUpdate),
+	}
+
+	var st *stub
+	if cfg.useStub {
+		var err error
+		st, err = startStub(cfg.stub, lg.delivered)
+		if err != nil {
+			slog.Error("start stub", "err", err)
+			os.Exit(1)
+		}
+		defer st.close()
+		slog.Info("stub orchestrator listening", "addr", cfg.stub.addr, "pod", cfg.stub.podIP+":"+stubPodPort)
+	}
+
+	slog.Info("loadgen starting", "router", cfg.routerURL, "tenants", cfg.tenants,
+		"rate_per_tenant", cfg.rate, "duration", cfg.duration)
+	lg.run(ctx)
+
+	if st != nil {
+		lg.waitDelivered(ctx)
+	}
+	lg.report(os.Stdout, st)
+}
+
+// run sends updates for every tenant until the duration elapses or ctx is
+// cancelled, logging progress every 10s.
+func (lg *loadgen) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, lg.cfg.duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 1; i <= lg.cfg.tenants; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lg.tenantLoop(ctx, i)
+		}(i)
+	}
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			slog.Info("progress", "sent", lg.sent.Load(), "ack_errors", lg.ackErrorCount(), "outstanding", lg.outstanding())
+		}
+	}
+}
+
+// tenantLoop sends one tenant's updates with exponentially distributed gaps
+// (a Poisson process), so tenants don't fire in lockstep.
+func (lg *loadgen) tenantLoop(ctx context.Context, i int) {
+	tenantID := fmt.Sprintf("%s%04d", lg.cfg.prefix, i)
+	chatID := int64(chatIDBase + i)
+	for {
+		gap := time.Duration(rand.ExpFloat64() / lg.cfg.rate * float64(time.Second))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(gap):
+		}
+		lg.send(ctx, tenantID, chatID)
+	}
+}
+
+// send posts one Telegram-shaped update to the router's webhook for tenantID
+func (lg *loadgen) send(ctx context.Context, tenantID string, chatID int64) {
+	seq := lg.seq.Add(1)
+	body, _ := json.Marshal(map[string]any{
+		"update_id": seq,
+		"message": map[string]any{
+			"message_id": seq,
+			"date":       time.Now().Unix(),
+			"chat":       map[string]any{"id": chatID, "type": "private"},
+			"from":       map[string]any{"id": chatID, "is_bot": false, "first_name": "loadgen"},
+			"text":       fmt.Sprintf("loadgen %d", seq),
+		},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/tg/%s", lg.cfg.routerURL, tenantID), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	if lg.cfg.useStub {
+		lg.mu.Lock()
+		lg.pending[seq] = sentUpdate{tenantID: tenantID, at: start}
+		lg.mu.Unlock()
+	}
+	lg.sent.Add(1)
+	resp, err := lg.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Cut off by the end of the run, not a router failure
+			lg.sent.Add(-1)
+			lg.forget(seq)
+			return
+		}
+		lg.ackError("transport")
+		lg.forget(seq)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		lg.ackError(fmt.Sprintf("%d", resp.StatusCode))
+		lg.forget(seq)
+		return
+	}
+	lg.ackLat.add(time.Since(start))
+}
+
+func (lg *loadgen) ackError(kind string) {
+	n, _ := lg.ackErrors.LoadOrStore(kind, new(atomic.Int64))
+	n.(*atomic.Int64).Add(1)
+}
+
+func (lg *loadgen) ackErrorCount() int64 {
+	var total int64
+	lg.ackErrors.Range(func(_, n any) bool {
+		total += n.(*atomic.Int64).Load()
+		return true
+	})
+	return total
+}
+
+// forget drops an update that will never be delivered
+func (lg *loadgen) forget(seq int64) {
+	lg.mu.Lock()
+	delete(lg.pending, seq)
+	lg.mu.Unlock()
+}
+
+func (lg *loadgen) outstanding() int {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return len(lg.pending)
+}
+
+// delivered is called by the stub pod for each message the router forwards.
+// It returns the update's tenant and send time, or ok=false for text that
+// loadgen did not send (or already counted).
+func (lg *loadgen) delivered(text string) (sentUpdate, bool) {
+	var seq int64
+	if _, err := fmt.Sscanf(text, "loadgen %d", &seq); err != nil {
+		return sentUpdate{}, false
+	}
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	u, ok := lg.pending[seq]
+	delete(lg.pending, seq)
+	return u, ok
+}
+
+// waitDelivered waits up to -drain for the router to deliver what was sent
+func (lg *loadgen) waitDelivered(ctx context.Context) {
+	deadline := time.After(lg.cfg.drain)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for lg.outstanding() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// report prints the run summary. Delivery and wake figures need the stub.
+func (lg *loadgen) report(w io.Writer, st *stub) {
+	sent := lg.sent.Load()
+	ackErrs := lg.ackErrorCount()
+	fmt.Fprintf(w, "\nloadgen: %d tenants × %.2f msg/s for %s against %s\n\n", lg.cfg.tenants, lg.cfg.rate, lg.cfg.duration, lg.cfg.routerURL)
+	fmt.Fprintf(w, "sent            %d\n", sent)
+	fmt.Fprintf(w, "ack errors      %d (%s)\n", ackErrs, percent(ackErrs, sent))
+	lg.ackErrors.Range(func(kind, n any) bool {
+		fmt.Fprintf(w, "  %-13s %d\n", kind, n.(*atomic.Int64).Load())
+		return true
+	})
+	fmt.Fprintf(w, "ack latency     %s\n", lg.ackLat.summary())
+
+	if st == nil {
+		fmt.Fprintln(w, "\n(delivery and wake latencies need -stub; for a real orchestrator see its \"wake: phases\" log lines)")
+		return
+	}
+	lost := int64(lg.outstanding())
+	fmt.Fprintf(w, "undelivered     %d (%s)\n", lost, percent(lost, sent-ackErrs))
+	fmt.Fprintf(w, "\ndelivery latency (send → pod)\n")
+	fmt.Fprintf(w, "  cached        %s\n", st.delivery[wakeNone].summary())
+	fmt.Fprintf(w, "  warm wake     %s\n", st.delivery[wakeWarm].summary())
+	fmt.Fprintf(w, "  cold wake     %s\n", st.delivery[wakeCold].summary())
+	fmt.Fprintf(w, "\nwakes           %d warm, %d cold, %d failed, %d already running\n",
+		st.wakes[wakeWarm].Load(), st.wakes[wakeCold].Load(), st.wakeFailures.Load(), st.wakes[wakeNone].Load())
+}
+
+func percent(n, of int64) string {
+	if of <= 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(of))
+}
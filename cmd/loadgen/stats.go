@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencies collects durations for percentile reporting. Runs are short
+// enough that keeping every sample is cheaper than a histogram's bookkeeping.
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (l *latencies) add(d time.Duration) {
+	l.mu.Lock()
+	l.samples = append(l.samples, d)
+	l.mu.Unlock()
+}
+
+// percentile returns the nearest-rank p-th percentile (0 < p <= 100) of
+// sorted, or 0 if it is empty.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// summary renders e.g. "n=120 p50=12ms p90=40ms p99=1.2s max=2.3s"
+func (l *latencies) summary() string {
+	l.mu.Lock()
+	sorted := slices.Clone(l.samples)
+	l.mu.Unlock()
+	if len(sorted) == 0 {
+		return "n=0"
+	}
+	slices.Sort(sorted)
+	return fmt.Sprintf("n=%d p50=%s p90=%s p99=%s max=%s", len(sorted),
+		round(percentile(sorted, 50)), round(percentile(sorted, 90)),
+		round(percentile(sorted, 99)), round(sorted[len(sorted)-1]))
+}
+
+// round trims a duration to a readable precision
+func round(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(100 * time.Microsecond)
+}
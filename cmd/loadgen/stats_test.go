@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 90*time.Millisecond, percentile(sorted, 90))
+	assert.Equal(t, 100*time.Millisecond, percentile(sorted, 100))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestLatenciesSummary(t *testing.T) {
+	var l latencies
+	assert.Equal(t, "n=0", l.summary())
+
+	l.add(3 * time.Millisecond)
+	l.add(1 * time.Millisecond)
+	l.add(2500 * time.Millisecond)
+	assert.Equal(t, "n=3 p50=3ms p90=2.5s p99=2.5s max=2.5s", l.summary())
+}
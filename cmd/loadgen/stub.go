@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+)
+
+// stubPodPort is fixed because the router always forwards to the agent port
+var stubPodPort = strconv.Itoa(contract.AgentPort)
+
+type stubConfig struct {
+	addr          string
+	podIP         string
+	coldStart     time.Duration
+	warmStart     time.Duration
+	warmRatio     float64
+	wakeErrorRate float64
+	idleTimeoutS  int
+}
+
+// wakeKind classifies how a delivered update reached its pod
+type wakeKind int
+
+const (
+	wakeNone wakeKind = iota // pod was already running
+	wakeWarm                 // waited for a wake that claimed a warm pod
+	wakeCold                 // waited for a cold start
+)
+
+// stubTenant is the stub orchestrator's view of one tenant's pod
+type stubTenant struct {
+	awakeUntil time.Time     // pod counts as running until then
+	waking     chan struct{} // closed when the in-flight wake finishes
+	wokeAt     time.Time     // when the last successful wake finished
+	wokeKind   wakeKind
+}
+
+// stub plays the orchestrator (wake, bot token, activity) and every tenant's
+// pod. Wakes take -cold-start or -warm-start and, like the real wake lock,
+// concurrent wakes for one tenant share a single start.
+type stub struct {
+	cfg       stubConfig
+	delivered func(text string) (sentUpdate, bool)
+	servers   []*http.Server
+
+	mu      sync.Mutex
+	tenants map[string]*stubTenant
+
+	delivery     [3]latencies // indexed by wakeKind
+	wakes        [3]atomic.Int64
+	wakeFailures atomic.Int64
+}
+
+// startStub starts the stub orchestrator on cfg.addr and the stub pod on
+// port 3000. delivered resolves a forwarded message to the update loadgen sent.
+func startStub(cfg stubConfig, delivered func(string) (sentUpdate, bool)) (*stub, error) {
+	s := &stub{cfg: cfg, delivered: delivered, tenants: make(map[string]*stubTenant)}
+
+	orch := chi.NewRouter()
+	orch.Post("/wake/{tenantID}", s.wakeHandler)
+	orch.Get("/tenants/{tenantID}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"TenantID": chi.URLParam(r, "tenantID")})
+	})
+	// No bot token, so the router never calls the real Telegram API
+	orch.Get("/tenants/{tenantID}/bot_token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"BotToken": ""})
+	})
+	orch.Put("/tenants/{tenantID}/activity", func(w http.ResponseWriter, r *http.Request) {
+		s.touch(chi.URLParam(r, "tenantID"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	pod := chi.NewRouter()
+	pod.Post(contract.AgentWebhookPath, s.podHandler)
+	pod.Post("/reset", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	for _, srv := range []struct {
+		addr    string
+		handler http.Handler
+	}{{cfg.addr, orch}, {":" + stubPodPort, pod}} {
+		ln, err := net.Listen("tcp", srv.addr)
+		if err != nil {
+			s.close()
+			return nil, fmt.Errorf("listen %s: %w", srv.addr, err)
+		}
+		hs := &http.Server{Handler: srv.handler}
+		s.servers = append(s.servers, hs)
+		go hs.Serve(ln)
+	}
+	return s, nil
+}
+
+func (s *stub) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, hs := range s.servers {
+		hs.Shutdown(ctx)
+	}
+}
+
+// tenant returns the state for id, creating it. Callers hold s.mu.
+func (s *stub) tenant(id string) *stubTenant {
+	t, ok := s.tenants[id]
+	if !ok {
+		t = &stubTenant{}
+		s.tenants[id] = t
+	}
+	return t
+}
+
+// touch extends a running pod's life, as activity resets the idle clock
+func (s *stub) touch(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tenant(tenantID)
+	if time.Now().Before(t.awakeUntil) {
+		t.awakeUntil = time.Now().Add(time.Duration(s.cfg.idleTimeoutS) * time.Second)
+	}
+}
+
+// wakeHandler: POST /wake/{tenantID}
+func (s *stub) wakeHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+
+	s.mu.Lock()
+	t := s.tenant(tenantID)
+	switch {
+	case time.Now().Before(t.awakeUntil):
+		s.mu.Unlock()
+		s.wakes[wakeNone].Add(1)
+		s.writeWake(w)
+		return
+	case t.waking != nil:
+		// Another wake is in flight; wait for it like a lock loser polls
+		ch := t.waking
+		s.mu.Unlock()
+		<-ch
+		s.mu.Lock()
+		running := time.Now().Before(t.awakeUntil)
+		s.mu.Unlock()
+		if !running {
+			http.Error(w, "wake failed", http.StatusServiceUnavailable)
+			return
+		}
+		s.writeWake(w)
+		return
+	}
+	ch := make(chan struct{})
+	t.waking = ch
+	kind, delay := wakeCold, s.cfg.coldStart
+	if rand.Float64() < s.cfg.warmRatio {
+		kind, delay = wakeWarm, s.cfg.warmStart
+	}
+	failed := rand.Float64() < s.cfg.wakeErrorRate
+	s.mu.Unlock()
+
+	time.Sleep(delay)
+
+	s.mu.Lock()
+	t.waking = nil
+	if !failed {
+		t.awakeUntil = time.Now().Add(time.Duration(s.cfg.idleTimeoutS) * time.Second)
+		t.wokeAt = time.Now()
+		t.wokeKind = kind
+	}
+	close(ch)
+	s.mu.Unlock()
+
+	if failed {
+		s.wakeFailures.Add(1)
+		http.Error(w, "wake failed", http.StatusServiceUnavailable)
+		return
+	}
+	s.wakes[kind].Add(1)
+	s.writeWake(w)
+}
+
+func (s *stub) writeWake(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"pod_ip":         s.cfg.podIP,
+		"idle_timeout_s": s.cfg.idleTimeoutS,
+	})
+}
+
+// podHandler: POST /webhook on the stub pod. An update sent before the
+// tenant's last wake finished is counted as having waited for that wake.
+func (s *stub) podHandler(w http.ResponseWriter, r *http.Request) {
+	var req contract.AgentMessage
+	json.NewDecoder(r.Body).Decode(&req)
+	if u, ok := s.delivered(req.Message); ok {
+		s.mu.Lock()
+		t := s.tenant(u.tenantID)
+		kind := wakeNone
+		if u.at.Before(t.wokeAt) {
+			kind = t.wokeKind
+		}
+		s.mu.Unlock()
+		s.delivery[kind].add(time.Since(u.at))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contract.AgentReply{})
+}
@@ -0,0 +1,19 @@
+// Command openapi prints the orchestrator's OpenAPI document, which `make
+// openapi` writes to docs/openapi.json
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+)
+
+func main() {
+	doc, err := api.OpenAPI()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(append(doc, '\n'))
+}
@@ -2,31 +2,55 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/admission"
 	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	"github.com/shawn/agentic-tenancy/internal/digest"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	"github.com/shawn/agentic-tenancy/internal/envvar"
+	"github.com/shawn/agentic-tenancy/internal/events"
 	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
 	"github.com/shawn/agentic-tenancy/internal/lifecycle"
 	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/logforward"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/outputs"
+	"github.com/shawn/agentic-tenancy/internal/podwatch"
 	"github.com/shawn/agentic-tenancy/internal/reconciler"
 	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
 	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/shawn/agentic-tenancy/internal/tenantrole"
 	"github.com/shawn/agentic-tenancy/internal/warmpool"
+	"google.golang.org/grpc"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// apiKeyCacheTTL bounds how long a key revoked in API_KEYS_TABLE keeps working
+const apiKeyCacheTTL = time.Minute
+
 func getenv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -35,19 +59,211 @@ func getenv(key, def string) string {
 }
 
 func main() {
+	bootstrap := flag.Bool("bootstrap", false, "create each environment's DynamoDB table if missing, verify IAM access, then exit")
+	dryRun := flag.Bool("dry-run", os.Getenv("CONTROLLERS_DRY_RUN") == "true", "lifecycle controller, reconciler and warm pool health checks log what they would stop, reset, repair or cordon without acting")
+	migrateTokens := flag.Bool("migrate-bot-tokens", false, "move plaintext bot tokens from each environment's registry table into the BOT_TOKEN_STORE, then exit")
+	registryDiff := flag.Bool("registry-diff", false, "compare each environment's registry table with the table it is migrating to, then exit; exits 1 on any difference")
+	registrySync := flag.Bool("registry-sync", false, "copy each environment's registry table onto the table it is migrating to, then exit")
+	flag.Parse()
+
 	// Config from env
 	dynamoTable := getenv("DYNAMODB_TABLE", "tenant-registry")
 	dynamoEndpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	// Table the default environment's registry is being moved to, written
+	// along with DYNAMODB_TABLE; reads come from DYNAMODB_READ_FROM
+	dynamoMigrateTo := os.Getenv("DYNAMODB_MIGRATE_TO")
+	dynamoReadFrom := getenv("DYNAMODB_READ_FROM", registry.ReadPrimary)
 	redisAddr := getenv("REDIS_ADDR", "localhost:6379")
 	namespace := getenv("K8S_NAMESPACE", "tenants")
 	s3Bucket := getenv("S3_BUCKET", "zeroclaw-tenant-state")
-	warmTarget, _ := strconv.Atoi(getenv("WARM_POOL_TARGET", "10"))
+	warmTarget := envvar.Int("WARM_POOL_TARGET", 10)
+	warmNamespace := os.Getenv("WARM_POOL_NAMESPACE") // empty = K8S_NAMESPACE
 	zeroClawImage := getenv("ZEROCLAW_IMAGE", "zeroclaw:latest")
+	defaultChannel := getenv("ZEROCLAW_DEFAULT_CHANNEL", "stable") // image alias for unpinned tenants
 	kataRuntime := getenv("KATA_RUNTIME_CLASS", "kata-qemu")
 	leaderID := getenv("LEADER_ELECTION_ID", "orchestrator-"+os.Getenv("POD_NAME"))
 	port := getenv("PORT", "8080")
+	// Set, PORT serves the management API alone and the routes only
+	// in-cluster components call (bot tokens, wakes, ...) move to this port
+	internalPort := os.Getenv("INTERNAL_PORT")
+	// Set, the tenancy.v1.TenantService gRPC API is served on this port
+	grpcPort := os.Getenv("GRPC_PORT")
+	// Set, the admission webhook for tenant pod edits is served over TLS on
+	// this port (deploy/03-admission.yaml)
+	admissionPort := os.Getenv("ADMISSION_PORT")
+	admissionMode := getenv("ADMISSION_MODE", admission.ModeFlag)
+	admissionCert := getenv("ADMISSION_TLS_CERT", "/etc/admission/tls.crt")
+	admissionKey := getenv("ADMISSION_TLS_KEY", "/etc/admission/tls.key")
 	localMode := os.Getenv("LOCAL_MODE") == "true" || dynamoEndpoint != ""
 	routerPublicURL := os.Getenv("ROUTER_PUBLIC_URL") // e.g. https://zeroclaw-router.example.com
+	region := os.Getenv("REGION")                     // multi-region only: this orchestrator's home region
+	graceIdle := envvar.Int64("GRACE_PERIOD_IDLE_S", 30)
+	graceDelete := envvar.Int64("GRACE_PERIOD_DELETE_S", 30)
+	graceWarmClaim := envvar.Int64("GRACE_PERIOD_WARM_CLAIM_S", 0)
+	startupBudget := envvar.Int64("STARTUP_PROBE_BUDGET_S", 300)
+	startupPeriod := envvar.Int("STARTUP_PROBE_PERIOD_S", 5)
+	startupPath := os.Getenv("STARTUP_PROBE_PATH") // e.g. /health; empty = TCP check of the agent port
+	restartDrain := envvar.Int64("RESTART_DRAIN_S", 5)
+	warmStaging := os.Getenv("WARM_POOL_STAGING_VOLUME") == "true" // experiment: pre-start the S3 CSI driver on warm nodes
+	// Set (e.g. busybox:1.36), tenant pods get containers that restore
+	// /s3-state into /zeroclaw-data on start and flush it back on stop
+	stateSyncImage := os.Getenv("STATE_SYNC_IMAGE")
+	stateSyncInterval := envvar.Int64("STATE_SYNC_INTERVAL_S", 0)
+	// Failed warm pod probes in a row before its node is cordoned; 0 disables
+	warmHealthFailures := envvar.Int("WARM_POOL_HEALTH_FAILURES", 3)
+	warmHealthPath := getenv("WARM_POOL_HEALTH_PATH", startupPath)
+	logForwardWindow := envvar.Int64("LOG_FORWARD_WINDOW_S", 300)
+	wakeHistory := envvar.Int("WAKE_HISTORY_SIZE", 20)
+	// Failed wakes in a row before a tenant is marked failed; 0 disables
+	wakeFailureLimit := envvar.Int("WAKE_FAILURE_LIMIT", 5)
+	if wakeFailureLimit == 0 {
+		wakeFailureLimit = -1
+	}
+	eventHistory := envvar.Int("EVENT_HISTORY_SIZE", 50)
+	// Pods still Terminating this long past their grace period are force-deleted; 0 disables
+	stuckTerminating := envvar.Int64("STUCK_TERMINATING_AFTER_S", 300)
+	webhookRate := envvar.Float("WEBHOOK_REGISTER_RATE", 1)
+	webhookBurst := envvar.Int("WEBHOOK_REGISTER_BURST", 5)
+	broadcastRate := envvar.Float("BROADCAST_RATE", 5)
+	// Price a week's usage in weekly digests; both 0 leaves the estimate out
+	digestPodHour := envvar.Float("DIGEST_COST_PER_POD_HOUR", 0)
+	digestMTokens := envvar.Float("DIGEST_COST_PER_MILLION_TOKENS", 0)
+	digestCurrency := getenv("DIGEST_COST_CURRENCY", "USD")
+	requireConfirm := os.Getenv("REQUIRE_CONFIRM") == "true"
+	// Wakes wait for the agent's GET /restore-status to report its state restored
+	waitAgentRestore := os.Getenv("WAIT_AGENT_RESTORE") == "true"
+	warmCooldown := envvar.Int64("WARM_CLAIM_COOLDOWN_S", 0)
+	// DynamoDB table of hashed API keys
+	apiKeysTable := os.Getenv("API_KEYS_TABLE")
+	botTokenStore := os.Getenv("BOT_TOKEN_STORE") // "secretsmanager" keeps bot tokens out of DynamoDB
+	botTokenPrefix := getenv("BOT_TOKEN_SECRET_PREFIX", "agentic-tenancy/bot-token/")
+	secretsEndpoint := os.Getenv("SECRETSMANAGER_ENDPOINT")
+	s3Endpoint := os.Getenv("S3_ENDPOINT")
+	// The cluster's IAM OIDC provider; set, it gives every tenant its own
+	// ServiceAccount and IAM role (IRSA) scoped to its S3 prefix
+	tenantRoleOIDC := os.Getenv("TENANT_ROLE_OIDC_PROVIDER_ARN")
+	tenantRolePrefix := getenv("TENANT_ROLE_PREFIX", "zeroclaw-tenant-")
+	tenantRolePolicies := strings.FieldsFunc(os.Getenv("TENANT_ROLE_POLICY_ARNS"), func(r rune) bool { return r == ',' })
+	tenantRoleBoundary := os.Getenv("TENANT_ROLE_PERMISSIONS_BOUNDARY")
+	stateSizeInterval := envvar.Int64("STATE_SIZE_INTERVAL_S", 3600)
+	// Deleted tenants can be restored for this long before their record, S3
+	// state and bot token are purged; 0 purges them on delete
+	deleteRetention := envvar.Int64("DELETE_RETENTION_S", 604800)
+	// Snapshots kept per tenant, the oldest deleted beyond it; 0 keeps them all
+	snapshotKeep := envvar.Int("SNAPSHOT_KEEP", 10)
+	if snapshotKeep == 0 {
+		snapshotKeep = -1
+	}
+	rolloutMaxUnavailable := envvar.Int("ROLLOUT_MAX_UNAVAILABLE", 1)
+	// Signs status page links; shared with the router. Empty disables them.
+	statusPageSecret := os.Getenv("STATUS_PAGE_SECRET")
+	// Signs web chat links; shared with the router. Empty disables them.
+	webChatSecret := os.Getenv("WEB_CHAT_SECRET")
+	// Signs tenant pods' key-value store tokens; pods reach the store at
+	// KV_POD_URL (e.g. http://orchestrator.tenants.svc.cluster.local:8080)
+	kvTokenSecret := os.Getenv("KV_TOKEN_SECRET")
+	kvPodURL := os.Getenv("KV_POD_URL")
+	// Where tenant lifecycle events go, e.g. https://billing.example.com/hooks,sns:<topic ARN>,redis:tenant-events
+	eventSinksSpec := os.Getenv("EVENT_SINKS")
+	eventWebhookSecret := os.Getenv("EVENT_WEBHOOK_SECRET")
+	snsEndpoint := os.Getenv("SNS_ENDPOINT")
+	// Per-tier grace for both idle and delete; the per-operation variables
+	// override it for one of them
+	graceTiers, err := k8sclient.ParseTierGrace(os.Getenv("GRACE_PERIOD_TIERS")) // e.g. premium=120,free=10
+	if err != nil {
+		slog.Error("parse GRACE_PERIOD_TIERS", "err", err)
+		os.Exit(1)
+	}
+	graceIdleTiers, err := k8sclient.ParseTierGrace(os.Getenv("GRACE_PERIOD_IDLE_TIERS"))
+	if err != nil {
+		slog.Error("parse GRACE_PERIOD_IDLE_TIERS", "err", err)
+		os.Exit(1)
+	}
+	graceDeleteTiers, err := k8sclient.ParseTierGrace(os.Getenv("GRACE_PERIOD_DELETE_TIERS"))
+	if err != nil {
+		slog.Error("parse GRACE_PERIOD_DELETE_TIERS", "err", err)
+		os.Exit(1)
+	}
+	startupTiers, err := k8sclient.ParseTierStartup(os.Getenv("STARTUP_PROBE_TIERS")) // e.g. premium=600,free=120
+	if err != nil {
+		slog.Error("parse STARTUP_PROBE_TIERS", "err", err)
+		os.Exit(1)
+	}
+	if admissionMode != admission.ModeReject && admissionMode != admission.ModeFlag {
+		slog.Error("ADMISSION_MODE must be reject or flag", "mode", admissionMode)
+		os.Exit(1)
+	}
+	// Users whose tenant pod edits are the platform's own
+	admissionUsers := strings.FieldsFunc(getenv("ADMISSION_ALLOWED_USERS", "system:serviceaccount:"+namespace+":orchestrator"),
+		func(r rune) bool { return r == ',' })
+	stateQuota, err := statesize.ParseBytes(os.Getenv("STATE_QUOTA")) // e.g. 5Gi; empty = unlimited
+	if err != nil {
+		slog.Error("parse STATE_QUOTA", "err", err)
+		os.Exit(1)
+	}
+	stateQuotaTiers, err := statesize.ParseTierQuota(os.Getenv("STATE_QUOTA_TIERS")) // e.g. free=1Gi,premium=20Gi
+	if err != nil {
+		slog.Error("parse STATE_QUOTA_TIERS", "err", err)
+		os.Exit(1)
+	}
+	stateQuotaAction, err := statesize.ParseAction(os.Getenv("STATE_QUOTA_ACTION"))
+	if err != nil {
+		slog.Error("parse STATE_QUOTA_ACTION", "err", err)
+		os.Exit(1)
+	}
+	statePolicy := statesize.QuotaPolicy{Bytes: stateQuota, TierBytes: stateQuotaTiers, Action: stateQuotaAction}
+	// Run quotas, checked on wake; 0 or an unlisted tier doesn't limit
+	maxRunning := envvar.Int("MAX_RUNNING_PODS", 0)
+	maxRunningTiers, err := api.ParseTierLimits(os.Getenv("MAX_RUNNING_PODS_TIERS")) // e.g. free=20,premium=200
+	if err != nil {
+		slog.Error("parse MAX_RUNNING_PODS_TIERS", "err", err)
+		os.Exit(1)
+	}
+	wakesPerHourTiers, err := api.ParseTierLimits(os.Getenv("WAKES_PER_HOUR_TIERS")) // e.g. free=6
+	if err != nil {
+		slog.Error("parse WAKES_PER_HOUR_TIERS", "err", err)
+		os.Exit(1)
+	}
+	minutesPerDayTiers, err := api.ParseTierLimits(os.Getenv("RUN_MINUTES_PER_DAY_TIERS")) // e.g. free=60
+	if err != nil {
+		slog.Error("parse RUN_MINUTES_PER_DAY_TIERS", "err", err)
+		os.Exit(1)
+	}
+	runQuota := api.RunQuota{
+		MaxRunning:        maxRunning,
+		TierMaxRunning:    maxRunningTiers,
+		TierWakesPerHour:  wakesPerHourTiers,
+		TierMinutesPerDay: minutesPerDayTiers,
+	}
+	// The wake history must hold an hour's worth of wakes to count them
+	wakeHistory = max(wakeHistory, runQuota.MaxWakesPerHour())
+	staticKeys, err := apikey.ParseStatic(os.Getenv("API_KEYS")) // e.g. router=<key>,ops=<key>
+	if err != nil {
+		slog.Error("parse API_KEYS", "err", err)
+		os.Exit(1)
+	}
+	warmReserve, err := k8sclient.ParseTierReserve(os.Getenv("WARM_POOL_RESERVE")) // e.g. standard=2,free=1
+	if err != nil {
+		slog.Error("parse WARM_POOL_RESERVE", "err", err)
+		os.Exit(1)
+	}
+	dnsTiers, err := k8sclient.ParseTierDNS(os.Getenv("TENANT_DNS_TIERS")) // JSON: {"tier":{"policy","nameservers","searches","options"}}
+	if err != nil {
+		slog.Error("parse TENANT_DNS_TIERS", "err", err)
+		os.Exit(1)
+	}
+	if dynamoReadFrom != registry.ReadPrimary && dynamoReadFrom != registry.ReadSecondary {
+		slog.Error("DYNAMODB_READ_FROM must be primary or secondary", "value", dynamoReadFrom)
+		os.Exit(1)
+	}
+	defaultEnv := environment.Environment{Table: dynamoTable, Namespace: namespace, WarmPoolTarget: &warmTarget,
+		WarmNamespace: warmNamespace, MigrateTo: dynamoMigrateTo}
+	extraEnvs, err := environment.Parse(os.Getenv("ENVIRONMENTS"), defaultEnv) // JSON: {"staging":{},"prod":{"table":"..."}}
+	if err != nil {
+		slog.Error("parse ENVIRONMENTS", "err", err)
+		os.Exit(1)
+	}
+	envs := append([]environment.Environment{defaultEnv}, extraEnvs...)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
@@ -78,25 +294,94 @@ func main() {
 	}
 	db := dynamodb.NewFromConfig(awsCfg, dynamoOpts...)
 
+	if *bootstrap {
+		if !bootstrapTables(ctx, db, envs) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *registryDiff || *registrySync {
+		if !syncRegistries(ctx, db, envs, *registrySync) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	var tokens secrets.Store
+	switch botTokenStore {
+	case "":
+		slog.Warn("BOT_TOKEN_STORE unset — bot tokens are stored in plaintext in DynamoDB")
+	case "secretsmanager":
+		var smOpts []func(*secretsmanager.Options)
+		if secretsEndpoint != "" {
+			smOpts = append(smOpts, func(o *secretsmanager.Options) {
+				o.BaseEndpoint = &secretsEndpoint
+			})
+		}
+		tokens = secrets.NewAWS(secretsmanager.NewFromConfig(awsCfg, smOpts...))
+	default:
+		slog.Error("BOT_TOKEN_STORE must be empty or secretsmanager", "value", botTokenStore)
+		os.Exit(1)
+	}
+
+	// Tenant state sizes are listed from S3, purged tenants' state deleted
+	// there, state snapshots and replies too long for chat kept there; local
+	// mode has no bucket unless S3_ENDPOINT points at an emulator
+	var stateSizer statesize.Sizer
+	var stateDeleter statesize.Deleter
+	var snapshots snapshot.Store
+	var outputStore outputs.Store
+	if !localMode || s3Endpoint != "" {
+		var s3Opts []func(*s3.Options)
+		if s3Endpoint != "" {
+			s3Opts = append(s3Opts, func(o *s3.Options) {
+				o.BaseEndpoint = &s3Endpoint
+				o.UsePathStyle = true
+			})
+		}
+		s3Client := s3.NewFromConfig(awsCfg, s3Opts...)
+		state := statesize.NewS3(s3Client, s3Bucket)
+		stateSizer, stateDeleter = state, state
+		snapshots = snapshot.NewS3(s3Client, s3Bucket)
+		outputStore = outputs.NewS3(s3Client, s3Bucket)
+	}
+	var tenantRoles tenantrole.Provisioner
+	if tenantRoleOIDC != "" {
+		tenantRoles = tenantrole.NewIAM(iam.NewFromConfig(awsCfg), tenantrole.IAMConfig{
+			OIDCProviderARN:     tenantRoleOIDC,
+			Bucket:              s3Bucket,
+			RolePrefix:          tenantRolePrefix,
+			PolicyARNs:          tenantRolePolicies,
+			PermissionsBoundary: tenantRoleBoundary,
+		})
+	}
+	if *migrateTokens {
+		if tokens == nil {
+			slog.Error("-migrate-bot-tokens needs BOT_TOKEN_STORE set")
+			os.Exit(1)
+		}
+		if !migrateBotTokens(ctx, db, tokens, botTokenPrefix, envs, dynamoReadFrom) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Redis
 	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
 
-	// Clients
-	reg := registry.New(db, dynamoTable)
-	locker := lock.New(rdb)
-
-	var k8s *k8sclient.Client
 	var cs kubernetes.Interface
+	var k8sCfg *rest.Config
 
 	if localMode {
 		// Local mode: use fake k8s or kubeconfig if available
 		slog.Info("running in local mode — k8s operations will be skipped or use kubeconfig")
-		cs = tryKubeconfig()
+		cs, k8sCfg = tryKubeconfig()
 		if cs == nil {
 			slog.Warn("no kubeconfig found, k8s operations disabled")
 		}
 	} else {
-		k8sCfg, err := rest.InClusterConfig()
+		k8sCfg, err = rest.InClusterConfig()
 		if err != nil {
 			slog.Error("k8s in-cluster config", "err", err)
 			os.Exit(1)
@@ -108,73 +393,406 @@ func main() {
 		}
 	}
 
-	if cs != nil {
-		k8s = k8sclient.New(cs, k8sclient.Config{
-			KataRuntimeClass: kataRuntime,
-			ZeroClawImage:    zeroClawImage,
-			S3Bucket:         s3Bucket,
-		})
+	// API keys are shared by all environments
+	var apiKeys apikey.Store
+	switch {
+	case apiKeysTable != "" && len(staticKeys) > 0:
+		apiKeys = apikey.Chain{staticKeys, apikey.NewCached(apikey.NewDynamo(db, apiKeysTable), apiKeyCacheTTL)}
+	case apiKeysTable != "":
+		apiKeys = apikey.NewCached(apikey.NewDynamo(db, apiKeysTable), apiKeyCacheTTL)
+	case len(staticKeys) > 0:
+		apiKeys = staticKeys
+	default:
+		slog.Warn("API_KEYS and API_KEYS_TABLE unset — orchestrator API is unauthenticated")
+	}
 
-		// Warm pool manager (only when k8s available)
-		if warmTarget > 0 {
-			wp := warmpool.New(k8s, namespace, warmTarget)
-			go wp.Run(ctx)
-		}
+	// Lifecycle events for external systems, shared by all environments
+	eventSinks, err := events.ParseSinks(eventSinksSpec, events.SinkOptions{
+		WebhookSecret: []byte(eventWebhookSecret),
+		Redis:         rdb,
+		AWS:           awsCfg,
+		SNSEndpoint:   snsEndpoint,
+	})
+	if err != nil {
+		slog.Error("parse EVENT_SINKS", "err", err)
+		os.Exit(1)
+	}
+	eventBus := events.New(eventSinks...)
+	go eventBus.Run(ctx)
 
-		// Lifecycle controller (leader election + idle timeout)
-		lc := lifecycle.New(reg, k8s, cs, namespace, leaderID)
-		go lc.Run(ctx)
+	// Owner notifications (agent error logs) go out through the tenant's bot
+	notifier := notify.New(telegram.New(routerPublicURL)).WithSecrets(tokens)
 
-		// Lifecycle reconciler (detects state drift between DynamoDB and k8s)
-		rec := reconciler.New(reg, k8s, rdb, namespace)
-		go rec.Run(ctx)
+	// One isolated set of components per environment; the default one keeps
+	// the legacy unprefixed routes, tables and keys.
+	mux := chi.NewRouter()
+	var internalMux *chi.Mux
+	if internalPort != "" {
+		internalMux = chi.NewRouter()
 	}
+	var grpcService *api.GRPCService
+	if grpcPort != "" {
+		grpcService = api.NewGRPCService()
+	}
+	admissionRegistries := make(map[string]admission.Recorder)
+	for _, env := range envs {
+		reg := newRegistry(db, env, dynamoReadFrom)
+		admissionRegistries[env.Namespace] = reg
+		locker := lock.NewWithPrefix(rdb, env.RedisPrefix)
+
+		var k8s *k8sclient.Client
+		var lc *lifecycle.Controller
+		var rec *reconciler.Reconciler
+		if cs != nil {
+			k8s = k8sclient.New(cs, k8sclient.Config{
+				KataRuntimeClass: kataRuntime,
+				ZeroClawImage:    zeroClawImage,
+				S3Bucket:         s3Bucket,
+				Grace: k8sclient.GracePolicy{
+					IdleS:       graceIdle,
+					DeleteS:     graceDelete,
+					WarmClaimS:  graceWarmClaim,
+					TierIdleS:   k8sclient.MergeTierGrace(graceTiers, graceIdleTiers),
+					TierDeleteS: k8sclient.MergeTierGrace(graceTiers, graceDeleteTiers),
+				},
+				TierDNS:           dnsTiers,
+				Environment:       env.Name,
+				WarmStagingVolume: warmStaging,
+				WarmPolicy: k8sclient.WarmPoolPolicy{
+					Reserve:  warmReserve,
+					Cooldown: time.Duration(warmCooldown) * time.Second,
+				},
+				Startup: k8sclient.StartupProbePolicy{
+					Path:    startupPath,
+					PeriodS: int32(startupPeriod),
+					BudgetS: startupBudget,
+					TierS:   startupTiers,
+				},
+				StateSync: k8sclient.StateSyncPolicy{
+					Image:     stateSyncImage,
+					IntervalS: stateSyncInterval,
+				},
+				REST: k8sCfg,
+			})
+
+			// Warm pool manager (only when k8s available)
+			target := warmTarget
+			if env.WarmPoolTarget != nil {
+				target = *env.WarmPoolTarget
+			}
+			if target > 0 {
+				wp := warmpool.New(k8s, env.WarmPoolNamespace(), target)
+				go wp.Run(ctx)
+			}
+
+			// Lifecycle controller (leader election + idle timeout); the lease
+			// lives in the environment's namespace. Started below, once the
+			// API handler whose webhook retries it runs exists.
+			lc = lifecycle.New(reg, k8s, cs, env.Namespace, leaderID).WithDryRun(*dryRun).WithEvents(eventBus, env.Name)
+			lc.RunWhileLeader(logforward.New(reg, k8s, notifier, env.Namespace, time.Duration(logForwardWindow)*time.Second).Run)
+			lc.RunWhileLeader(podwatch.New(reg, k8s, notifier, env.Namespace, eventHistory).Run)
+			lc.RunWhileLeader(lifecycle.NewUsageMeter(reg).Run)
+			lc.RunWhileLeader(digest.New(reg, notifier, digest.Rates{
+				PodHour: digestPodHour, MillionTokens: digestMTokens, Currency: digestCurrency,
+			}).Run)
+			if stuckTerminating > 0 {
+				lc.RunWhileLeader(lifecycle.NewStuckPodReaper(reg, k8s, env.Namespace,
+					time.Duration(stuckTerminating)*time.Second, eventHistory).WithDryRun(*dryRun).Run)
+			}
+			if target > 0 && warmHealthFailures > 0 {
+				probe := warmpool.AgentProbe(warmHealthPath, 5*time.Second)
+				lc.RunWhileLeader(warmpool.NewHealthChecker(k8s, env.WarmPoolNamespace(), probe, warmHealthFailures,
+					time.Duration(startupBudget)*time.Second).WithDryRun(*dryRun).Run)
+			}
+			if stateSizer != nil {
+				lc.RunWhileLeader(statesize.NewWatcher(reg, stateSizer, statePolicy, notifier, eventHistory,
+					time.Duration(stateSizeInterval)*time.Second).Run)
+			}
+
+			// Lifecycle reconciler (detects state drift between DynamoDB and k8s);
+			// started below, once the API handler whose sagas it resumes exists
+			rec = reconciler.New(reg, k8s, rdb, env.Namespace).WithRedisPrefix(env.RedisPrefix).WithDryRun(*dryRun)
+		}
 
-	// HTTP API (works with nil k8s in local mode — wake will return error if k8s unavailable)
-	var apiK8s *k8sclient.Client
-	if k8s != nil {
-		apiK8s = k8s
+		// HTTP API (works with nil k8s in local mode — wake will return error if k8s unavailable)
+		h := api.New(reg, k8s, locker, rdb, telegamClient(routerPublicURL, env), api.Config{
+			Namespace:             env.Namespace,
+			S3Bucket:              s3Bucket,
+			DefaultChannel:        defaultChannel,
+			RestartDrain:          time.Duration(restartDrain) * time.Second,
+			Environment:           env,
+			Region:                region,
+			WakeHistory:           wakeHistory,
+			EventHistory:          eventHistory,
+			WakeFailureLimit:      wakeFailureLimit,
+			RequireConfirm:        requireConfirm,
+			WaitAgentRestore:      waitAgentRestore,
+			APIKeys:               apiKeys,
+			WebhookRate:           webhookRate,
+			WebhookBurst:          webhookBurst,
+			Secrets:               tokens,
+			BotTokenSecretPrefix:  botTokenPrefix,
+			StateSizer:            stateSizer,
+			StateDeleter:          stateDeleter,
+			DeleteRetention:       time.Duration(deleteRetention) * time.Second,
+			Snapshots:             snapshots,
+			SnapshotKeep:          snapshotKeep,
+			StateQuota:            statePolicy,
+			RunQuota:              runQuota,
+			RolloutMaxUnavailable: rolloutMaxUnavailable,
+			StatusPageSecret:      []byte(statusPageSecret),
+			StatusPageURL:         statusPageURL(routerPublicURL, env),
+			WebChatSecret:         []byte(webChatSecret),
+			KVTokenSecret:         []byte(kvTokenSecret),
+			KVURL:                 kvURL(kvPodURL, env),
+			Events:                eventBus,
+			Outputs:               outputStore,
+			TenantRoles:           tenantRoles,
+			Notifier:              notifier,
+			BroadcastRate:         broadcastRate,
+		})
+		// The reconciler finishes the handler's interrupted sagas, and queued
+		// webhook registrations are retried and deleted tenants purged by the
+		// leader alone. Without k8s there is no leader election, so the single
+		// local replica does both.
+		if lc != nil {
+			lc.RunWhileLeader(h.RunWebhookRetries)
+			lc.RunWhileLeader(h.RunPurges)
+			go lc.Run(ctx)
+			go rec.WithSagas(h.ResumeSaga).Run(ctx)
+		} else {
+			go h.RunWebhookRetries(ctx)
+			go h.RunPurges(ctx)
+		}
+		public := h.Router()
+		if internalMux != nil {
+			public = h.PublicRouter()
+		}
+		path := env.PathPrefix()
+		if env.IsDefault() {
+			path = "/"
+		} else {
+			slog.Info("environment enabled", "env", env.Name, "table", env.Table, "namespace", env.Namespace,
+				"warm_namespace", env.WarmPoolNamespace(), "path", path)
+		}
+		mux.Mount(path, public)
+		if internalMux != nil {
+			internalMux.Mount(path, h.Router())
+		}
+		if grpcService != nil {
+			grpcService.Add(h)
+		}
 	}
-	h := api.New(reg, apiK8s, locker, rdb, telegamClient(routerPublicURL), api.Config{
-		Namespace: namespace,
-		S3Bucket:  s3Bucket,
-	})
 
-	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: h.Router(),
+	servers := []*http.Server{{Addr: ":" + port, Handler: mux}}
+	if internalMux != nil {
+		servers = append(servers, &http.Server{Addr: ":" + internalPort, Handler: internalMux})
+	}
+	for i, srv := range servers {
+		go func() {
+			slog.Info("orchestrator listening", "addr", srv.Addr, "internal", i > 0, "local_mode", localMode)
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("server error", "addr", srv.Addr, "err", err)
+			}
+		}()
 	}
 
-	go func() {
-		slog.Info("orchestrator listening", "port", port, "local_mode", localMode)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("server error", "err", err)
+	if admissionPort != "" {
+		tlsCfg, err := admission.TLSConfig(admissionCert, admissionKey)
+		if err != nil {
+			slog.Error("admission webhook", "err", err)
+			os.Exit(1)
+		}
+		webhook := http.NewServeMux()
+		webhook.Handle(admission.Path, admission.New(admission.Config{
+			Mode:         admissionMode,
+			AllowedUsers: admissionUsers,
+			Registries:   admissionRegistries,
+			EventHistory: eventHistory,
+		}))
+		srv := &http.Server{Addr: ":" + admissionPort, Handler: webhook, TLSConfig: tlsCfg}
+		servers = append(servers, srv)
+		go func() {
+			slog.Info("orchestrator admission webhook listening", "addr", srv.Addr, "mode", admissionMode, "allowed_users", admissionUsers)
+			if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				slog.Error("admission server error", "err", err)
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if grpcService != nil {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			slog.Error("grpc listen", "port", grpcPort, "err", err)
+			os.Exit(1)
 		}
-	}()
+		grpcServer = grpcService.Server()
+		go func() {
+			slog.Info("orchestrator gRPC listening", "addr", lis.Addr().String())
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("grpc server error", "err", err)
+			}
+		}()
+	}
 
 	<-ctx.Done()
 	slog.Info("shutting down")
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer shutdownCancel()
-	srv.Shutdown(shutdownCtx)
+	for _, srv := range servers {
+		srv.Shutdown(shutdownCtx)
+	}
+	if grpcServer != nil {
+		// Watches never finish on their own; cut them once the grace is up
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+}
+
+// registryTables lists the tables env's registry writes to
+func registryTables(env environment.Environment) []string {
+	if env.MigrateTo == "" {
+		return []string{env.Table}
+	}
+	return []string{env.Table, env.MigrateTo}
+}
+
+// newRegistry is env's registry: its table, or while it is migrating, that
+// table and the one it is moving to, read from the one readFrom names
+func newRegistry(db *dynamodb.Client, env environment.Environment, readFrom string) registry.Client {
+	if env.MigrateTo == "" {
+		return registry.New(db, env.Table)
+	}
+	reg, _ := registry.NewDual(registry.New(db, env.Table), registry.New(db, env.MigrateTo), readFrom) // readFrom checked at startup
+	slog.Info("registry migration: writing to both tables", "env", env.Name, "table", env.Table, "migrate_to", env.MigrateTo, "read_from", readFrom)
+	return reg
+}
+
+// syncRegistries compares every migrating environment's table with the one
+// it is moving to, copying it over with repair set, and reports whether
+// they all match (all were repaired, with repair).
+func syncRegistries(ctx context.Context, db *dynamodb.Client, envs []environment.Environment, repair bool) bool {
+	ok := true
+	for _, env := range envs {
+		if env.MigrateTo == "" {
+			continue
+		}
+		diff, err := registry.SyncTable(ctx, db, env.Table, env.MigrateTo, repair)
+		if err != nil {
+			slog.Error("registry sync failed", "env", env.Name, "table", env.Table, "migrate_to", env.MigrateTo, "err", err)
+			ok = false
+			continue
+		}
+		log := slog.Info
+		if !diff.Clean() && !repair {
+			log = slog.Warn
+			ok = false
+		}
+		log("registry compared", "env", env.Name, "table", env.Table, "migrate_to", env.MigrateTo, "repaired", repair,
+			"missing", diff.Missing, "differ", diff.Differ, "extra", diff.Extra)
+	}
+	return ok
+}
+
+// bootstrapTables prepares every environment's registry table, and any
+// table it is migrating to, and reports whether all of them are ready for
+// the orchestrator to use.
+func bootstrapTables(ctx context.Context, db *dynamodb.Client, envs []environment.Environment) bool {
+	ok := true
+	for _, env := range envs {
+		for _, table := range registryTables(env) {
+			created, err := registry.Bootstrap(ctx, db, table)
+			if err != nil {
+				slog.Error("bootstrap: table setup failed", "env", env.Name, "table", table, "err", err)
+				ok = false
+				continue
+			}
+			if created {
+				slog.Info("bootstrap: table created", "env", env.Name, "table", table)
+			} else {
+				slog.Info("bootstrap: table exists", "env", env.Name, "table", table)
+				added, err := registry.MigrateStatusIndex(ctx, db, table)
+				if err != nil {
+					slog.Error("bootstrap: status index migration failed", "env", env.Name, "table", table, "err", err)
+					ok = false
+					continue
+				}
+				if added {
+					slog.Info("bootstrap: status index added", "env", env.Name, "table", table, "index", registry.StatusIndex)
+				}
+			}
+			if err := registry.VerifyAccess(ctx, db, table); err != nil {
+				slog.Error("bootstrap: IAM check failed", "env", env.Name, "table", table, "err", err)
+				ok = false
+				continue
+			}
+			slog.Info("bootstrap: access verified", "env", env.Name, "table", table)
+		}
+	}
+	return ok
 }
 
-func tryKubeconfig() kubernetes.Interface {
+// migrateBotTokens moves every environment's plaintext bot tokens into store
+// and reports whether all of them were moved.
+func migrateBotTokens(ctx context.Context, db *dynamodb.Client, store secrets.Store, prefix string, envs []environment.Environment, readFrom string) bool {
+	ok := true
+	for _, env := range envs {
+		moved, err := secrets.MigrateBotTokens(ctx, newRegistry(db, env, readFrom), store, func(tenantID string) string {
+			return env.SecretName(prefix, tenantID)
+		})
+		if err != nil {
+			slog.Error("migrate bot tokens failed", "env", env.Name, "table", env.Table, "moved", moved, "err", err)
+			ok = false
+			continue
+		}
+		slog.Info("bot tokens migrated", "env", env.Name, "table", env.Table, "moved", moved)
+	}
+	return ok
+}
+
+func tryKubeconfig() (kubernetes.Interface, *rest.Config) {
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
 	cfg, err := clientcmd.BuildConfigFromFlags("", rules.GetDefaultFilename())
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 	cs, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		return nil
+		return nil, nil
+	}
+	return cs, cfg
+}
+
+// statusPageURL is where env's router serves status pages ("" if unknown)
+func statusPageURL(routerPublicURL string, env environment.Environment) string {
+	if routerPublicURL == "" {
+		return ""
+	}
+	return routerPublicURL + env.PathPrefix()
+}
+
+// kvURL is where tenant pods reach env's orchestrator API ("" if unknown)
+func kvURL(kvPodURL string, env environment.Environment) string {
+	if kvPodURL == "" {
+		return ""
 	}
-	return cs
+	return kvPodURL + env.PathPrefix()
 }
 
-func telegamClient(routerPublicURL string) *telegram.Client {
+// telegamClient registers webhooks under the environment's router path, so
+// updates for a tenant reach the router instance for the same environment.
+func telegamClient(routerPublicURL string, env environment.Environment) *telegram.Client {
 	if routerPublicURL == "" {
 		return nil
 	}
-	return telegram.New(routerPublicURL)
+	return telegram.New(routerPublicURL + env.PathPrefix())
 }
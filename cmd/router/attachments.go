@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+)
+
+// defaultAttachmentMaxBytes is the most the Bot API lets a bot download
+// with getFile
+const defaultAttachmentMaxBytes = 20 << 20
+
+// podMessage is the body of a forward to ZeroClaw's /webhook
+type podMessage = contract.AgentMessage
+
+// attachment is a file sent with a Telegram message, passed to the pod inline
+type attachment = contract.AgentAttachment
+
+// telegramFile is the part of a Telegram PhotoSize, Document or Voice the
+// router reads
+type telegramFile struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	FileSize int64  `json:"file_size"`
+}
+
+// mediaFile is one file of a message and what kind it is
+type mediaFile struct {
+	kind string
+	telegramFile
+}
+
+// extractMedia returns the files attached to an update's message. Of a
+// photo's sizes it picks the largest within maxBytes, or else the smallest,
+// which is then reported as too large.
+func extractMedia(body []byte, maxBytes int64) []mediaFile {
+	msg := updateMessageOf(body)
+	if msg == nil {
+		return nil
+	}
+	var files []mediaFile
+	if len(msg.Photo) > 0 {
+		// Telegram lists sizes smallest first
+		best := msg.Photo[0]
+		for _, p := range msg.Photo[1:] {
+			if p.FileSize <= maxBytes {
+				best = p
+			}
+		}
+		if best.MimeType == "" {
+			best.MimeType = "image/jpeg" // Telegram re-encodes photos as JPEG
+		}
+		files = append(files, mediaFile{"photo", best})
+	}
+	if msg.Document != nil {
+		files = append(files, mediaFile{"document", *msg.Document})
+	}
+	if msg.Voice != nil {
+		files = append(files, mediaFile{"voice", *msg.Voice})
+	}
+	return files
+}
+
+// fetchAttachments downloads the files attached to an update with the
+// tenant's bot token. Attachments are off when attachmentMaxBytes is 0.
+func (rt *Router) fetchAttachments(ctx context.Context, tenantID string, body []byte) []attachment {
+	if rt.attachmentMaxBytes <= 0 {
+		return nil
+	}
+	files := extractMedia(body, rt.attachmentMaxBytes)
+	if len(files) == 0 {
+		return nil
+	}
+	botToken := rt.getBotToken(ctx, tenantID)
+	out := make([]attachment, 0, len(files))
+	for _, f := range files {
+		a := attachment{Kind: f.kind, FileName: f.FileName, MimeType: f.MimeType, Size: f.FileSize}
+		switch {
+		case f.FileSize > rt.attachmentMaxBytes:
+			a.Error = fmt.Sprintf("file too large (limit %d bytes)", rt.attachmentMaxBytes)
+			slog.Info("attachment too large, not forwarded", "tenant", tenantID, "kind", f.kind, "size", f.FileSize)
+		case botToken == "":
+			a.Error = "bot token unavailable"
+		default:
+			filePath, data, err := rt.downloadFile(ctx, botToken, f.FileID)
+			if err != nil {
+				a.Error = "download failed"
+				slog.Warn("attachment download failed", "tenant", tenantID, "kind", f.kind, "err", err)
+				break
+			}
+			a.Data, a.Size = data, int64(len(data))
+			if a.FileName == "" {
+				a.FileName = path.Base(filePath)
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// downloadFile fetches a file with getFile, returning its path on the Bot
+// API server (e.g. photos/file_0.jpg) and its content
+func (rt *Router) downloadFile(ctx context.Context, botToken, fileID string) (string, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		rt.botAPI(botToken, "getFile")+"?file_id="+url.QueryEscape(fileID), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := rt.externalClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	var file struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Result      struct {
+			FilePath string `json:"file_path"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return "", nil, fmt.Errorf("getFile: %w", err)
+	}
+	if !file.OK || file.Result.FilePath == "" {
+		return "", nil, fmt.Errorf("getFile: %s", file.Description)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/file/bot%s/%s", rt.telegramAPIBase(), botToken, file.Result.FilePath), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err = rt.externalClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("download: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, rt.attachmentMaxBytes+1))
+	if err != nil {
+		return "", nil, err
+	}
+	if int64(len(data)) > rt.attachmentMaxBytes {
+		return "", nil, fmt.Errorf("download: larger than %d bytes", rt.attachmentMaxBytes)
+	}
+	return file.Result.FilePath, data, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMedia(t *testing.T) {
+	body := `{"message":{"chat":{"id":1},"caption":"look","photo":[
+		{"file_id":"small","file_size":100},{"file_id":"medium","file_size":1000},{"file_id":"large","file_size":5000}]}}`
+	files := extractMedia([]byte(body), 2000)
+	require.Len(t, files, 1)
+	assert.Equal(t, "photo", files[0].kind)
+	assert.Equal(t, "medium", files[0].FileID, "largest size within the limit")
+	assert.Equal(t, "image/jpeg", files[0].MimeType)
+
+	files = extractMedia([]byte(`{"message":{"chat":{"id":1},"document":{"file_id":"d","file_name":"a.pdf","mime_type":"application/pdf"}}}`), 2000)
+	require.Len(t, files, 1)
+	assert.Equal(t, "document", files[0].kind)
+	assert.Equal(t, "a.pdf", files[0].FileName)
+
+	assert.Empty(t, extractMedia([]byte(`{"message":{"chat":{"id":1},"text":"hi"}}`), 2000))
+}
+
+// TestForwardToPod_Attachments: files are downloaded with the tenant's bot
+// token and passed inline; one over the limit is reported, not fetched
+func TestForwardToPod_Attachments(t *testing.T) {
+	tg := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bot123:abc/getFile":
+			fmt.Fprintf(w, `{"ok":true,"result":{"file_path":"documents/%s.pdf"}}`, r.URL.Query().Get("file_id"))
+		case "/file/bot123:abc/documents/doc1.pdf":
+			w.Write([]byte("%PDF-1.4"))
+		default:
+			t.Errorf("unexpected Telegram call %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer tg.Close()
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+	}))
+	defer orch.Close()
+
+	var got podMessage
+	pod := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, `{"response":""}`)
+	}))
+	defer pod.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	// askPod always dials port 3000; send it to the test pod instead
+	rt := &Router{rdb: rdb, orchestratorAddr: orch.URL, attachmentMaxBytes: 1024, telegramAPI: tg.URL,
+		orchestratorClient: http.DefaultClient, externalClient: http.DefaultClient,
+		podClient: &http.Client{Transport: rewriteHost(pod.Listener.Addr().String(), "10.0.0.1:3000")}}
+
+	body := `{"message":{"chat":{"id":1},
+		"document":{"file_id":"doc1","mime_type":"application/pdf","file_size":8},
+		"voice":{"file_id":"v1","mime_type":"audio/ogg","file_size":4096}}}`
+	rt.forwardToPod(context.Background(), "10.0.0.1", "alice", []byte(body), 0)
+
+	assert.Empty(t, got.Message)
+	require.Len(t, got.Attachments, 2)
+	doc := got.Attachments[0]
+	assert.Equal(t, "document", doc.Kind)
+	assert.Equal(t, "doc1.pdf", doc.FileName)
+	assert.Equal(t, []byte("%PDF-1.4"), doc.Data)
+	assert.Empty(t, doc.Error)
+	voice := got.Attachments[1]
+	assert.Equal(t, "voice", voice.Kind)
+	assert.Nil(t, voice.Data)
+	assert.Contains(t, voice.Error, "too large")
+}
+
+// rewriteHost sends requests for from to addr; everything else goes out as is
+func rewriteHost(addr, from string) http.RoundTripper {
+	return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == from {
+			r = r.Clone(r.Context())
+			r.URL.Host = addr
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
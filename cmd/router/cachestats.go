@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+)
+
+// Reasons the router drops a cache entry (router_cache_invalidations_total)
+const (
+	invalidateForwardFailed = "forward_failed" // the pod couldn't be reached
+	invalidateResetFailed   = "reset_failed"   // the pod couldn't be reached for a reset
+	invalidateSleep         = "sleep"          // the tenant was put to sleep
+	invalidateUnauthorized  = "unauthorized"   // Telegram rejected the cached bot token
+)
+
+// cachePrefixes maps the caches the router counts to their Redis keys
+var cachePrefixes = map[string]string{
+	"endpoint":  cacheKeyPrefix,
+	"bot_token": botTokenPrefix,
+}
+
+// processStart is when this replica's cache stats started counting
+var processStart = time.Now().UTC()
+
+// cacheStats keeps this replica's cache counts for GET /admin/cache/stats,
+// alongside the Prometheus counters that aggregate them across replicas.
+// The zero value is ready to use.
+type cacheStats struct {
+	mu            sync.Mutex
+	hits          map[string]int64
+	misses        map[string]int64
+	invalidations map[string]map[string]int64 // cache → reason → count
+	wakesRunning  int64
+}
+
+// cacheSummary is one cache's entry in GET /admin/cache/stats
+type cacheSummary struct {
+	Hits          int64            `json:"hits"`
+	Misses        int64            `json:"misses"`
+	HitRatio      float64          `json:"hit_ratio"` // 0 before any lookup
+	Invalidations map[string]int64 `json:"invalidations"`
+}
+
+func (s *cacheStats) lookup(cache string, hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hits == nil {
+		s.hits, s.misses = map[string]int64{}, map[string]int64{}
+	}
+	if hit {
+		s.hits[cache]++
+	} else {
+		s.misses[cache]++
+	}
+}
+
+func (s *cacheStats) invalidate(cache, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.invalidations == nil {
+		s.invalidations = map[string]map[string]int64{}
+	}
+	if s.invalidations[cache] == nil {
+		s.invalidations[cache] = map[string]int64{}
+	}
+	s.invalidations[cache][reason]++
+}
+
+func (s *cacheStats) wakeFoundRunning() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wakesRunning++
+}
+
+// summary returns every counted cache's figures, and the number of wakes
+// that found the pod already running
+func (s *cacheStats) summary() (map[string]cacheSummary, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	caches := map[string]cacheSummary{}
+	for cache := range cachePrefixes {
+		c := cacheSummary{Hits: s.hits[cache], Misses: s.misses[cache], Invalidations: map[string]int64{}}
+		if total := c.Hits + c.Misses; total > 0 {
+			c.HitRatio = float64(c.Hits) / float64(total)
+		}
+		for reason, n := range s.invalidations[cache] {
+			c.Invalidations[reason] = n
+		}
+		caches[cache] = c
+	}
+	return caches, s.wakesRunning
+}
+
+// invalidateCache drops the tenant's entry from a cache, counting why
+func (rt *Router) invalidateCache(ctx context.Context, cache, tenantID, reason string) {
+	rt.rdb.Del(ctx, rt.key(cachePrefixes[cache], tenantID))
+	metrics.RouterCacheInvalidations.WithLabelValues(rt.env, cache, reason).Inc()
+	rt.stats.invalidate(cache, reason)
+}
+
+// dropRejectedBotToken drops the tenant's cached bot token if Telegram
+// rejected it as unauthorized: the token was revoked or rotated and the
+// orchestrator's cache delete was lost, so the next lookup fetches it again
+func (rt *Router) dropRejectedBotToken(tenantID string, err error) {
+	var apiErr *botAPIError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusUnauthorized {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		rt.invalidateCache(ctx, "bot_token", tenantID, invalidateUnauthorized)
+	}
+}
+
+// cacheStatsHandler summarizes this replica's cache effectiveness since it
+// started: GET /admin/cache/stats. Prometheus has the same figures summed
+// over replicas.
+func (rt *Router) cacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	caches, wakesRunning := rt.stats.summary()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"since":                 processStart,
+		"caches":                caches,
+		"wakes_already_running": wakesRunning,
+	})
+}
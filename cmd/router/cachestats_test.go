@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	rt := &Router{rdb: rdb, orchestratorClient: http.DefaultClient, env: "cachestats-test"}
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+
+	rt.countCacheLookup("endpoint", true)
+	rt.countCacheLookup("endpoint", true)
+	rt.countCacheLookup("endpoint", true)
+	rt.countCacheLookup("endpoint", false)
+	rt.invalidateCache(t.Context(), "endpoint", "alice", invalidateSleep)
+	rt.countWakeFoundRunning()
+	// Only Telegram's 401 means the cached token is stale
+	rt.dropRejectedBotToken("alice", errors.New("connection reset"))
+	rt.dropRejectedBotToken("alice", &botAPIError{status: http.StatusBadRequest})
+	rt.dropRejectedBotToken("alice", &botAPIError{status: http.StatusUnauthorized})
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RouterCacheInvalidations.WithLabelValues("cachestats-test", "bot_token", "unauthorized")))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/cache/stats", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Caches       map[string]cacheSummary `json:"caches"`
+		WakesRunning int64                   `json:"wakes_already_running"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, cacheSummary{Hits: 3, Misses: 1, HitRatio: 0.75, Invalidations: map[string]int64{"sleep": 1}}, got.Caches["endpoint"])
+	assert.Equal(t, cacheSummary{Invalidations: map[string]int64{"unauthorized": 1}}, got.Caches["bot_token"])
+	assert.Equal(t, int64(1), got.WakesRunning)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+)
+
+// callbackQuery is the part of a Telegram CallbackQuery (an inline keyboard
+// button press) the router reads
+type callbackQuery struct {
+	ID      string         `json:"id"`
+	Data    string         `json:"data"`
+	Message *updateMessage `json:"message"` // the message the button is under; nil if too old
+}
+
+// podCallback tells the pod which button was pressed
+type podCallback = contract.AgentCallback
+
+// callbackQueryOf returns the button press an update carries, or nil
+func callbackQueryOf(body []byte) *callbackQuery {
+	var update struct {
+		CallbackQuery *callbackQuery `json:"callback_query"`
+	}
+	if json.Unmarshal(body, &update) != nil {
+		return nil
+	}
+	return update.CallbackQuery
+}
+
+// answerCallback answers a button press, which Telegram shows as a spinner
+// on the button until it is answered. The agent's reply arrives as a
+// message, so the answer carries no text.
+func (rt *Router) answerCallback(ctx context.Context, tenantID string, body []byte) {
+	cq := callbackQueryOf(body)
+	if cq == nil || cq.ID == "" {
+		return
+	}
+	botToken := rt.getBotToken(ctx, tenantID)
+	if botToken == "" {
+		return
+	}
+	if err := rt.callBotAPI(botToken, "answerCallbackQuery", map[string]any{"callback_query_id": cq.ID}, nil); err != nil {
+		slog.Warn("telegram answerCallbackQuery failed", "tenant", tenantID, "err", err)
+	}
+}
+
+// inlineKeyboard returns the reply_markup a pod sent with its reply if it is
+// an inline keyboard, the only kind that can go on a message that is later
+// edited (as streamed replies are), else nil
+func inlineKeyboard(tenantID string, markup json.RawMessage) json.RawMessage {
+	if len(markup) == 0 || string(markup) == "null" {
+		return nil
+	}
+	var kb struct {
+		InlineKeyboard [][]json.RawMessage `json:"inline_keyboard"`
+	}
+	if err := json.Unmarshal(markup, &kb); err != nil || len(kb.InlineKeyboard) == 0 {
+		slog.Warn("pod reply_markup ignored, only inline keyboards are supported", "tenant", tenantID)
+		return nil
+	}
+	return markup
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeyboard = `{"inline_keyboard":[[{"text":"Yes","callback_data":"yes"}]]}`
+
+// TestForwardToPod_Callback: a button press reaches the pod as its
+// callback_data, marked as a callback, and the pod's inline keyboard goes
+// under its reply
+func TestForwardToPod_Callback(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg podMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		assert.Equal(t, "confirm", msg.Message)
+		assert.Equal(t, &podCallback{Data: "confirm", MessageID: 42}, msg.Callback)
+		fmt.Fprintf(w, `{"response":"Sure?","reply_markup":%s}`, testKeyboard)
+	}, tg, 0)
+
+	body := []byte(`{"callback_query":{"id":"cq1","data":"confirm","message":{"message_id":42,"chat":{"id":1}}}}`)
+	rt.answerCallback(context.Background(), "alice", body)
+	rt.forwardToPod(context.Background(), "10.0.0.1", "alice", body, 0)
+	assert.Equal(t, []string{"answer:cq1", "send:Sure?|" + testKeyboard}, tg.calls)
+}
+
+// TestReplyStream_Keyboard: a streamed reply gets its keyboard with the last
+// edit
+func TestReplyStream_Keyboard(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, nil, tg, time.Nanosecond)
+	s := rt.newReplyStream("alice", "123:abc", replyTarget{ChatID: 1})
+	s.update("Sure?")
+	s.finish("Sure?", json.RawMessage(testKeyboard))
+	assert.Equal(t, []string{"send:Sure?", "edit:1:Sure?|" + testKeyboard}, tg.calls)
+}
+
+func TestInlineKeyboard(t *testing.T) {
+	assert.JSONEq(t, testKeyboard, string(inlineKeyboard("alice", json.RawMessage(testKeyboard))))
+	assert.Nil(t, inlineKeyboard("alice", nil))
+	assert.Nil(t, inlineKeyboard("alice", json.RawMessage(`null`)))
+	assert.Nil(t, inlineKeyboard("alice", json.RawMessage(`{"keyboard":[[{"text":"A"}]]}`)), "reply keyboards can't be edited in")
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// telegramCIDRs are the ranges Telegram delivers webhooks from
+// (https://core.telegram.org/bots/webhooks#the-short-version).
+const telegramCIDRs = "149.154.160.0/20,91.108.4.0/22"
+
+// clientIPResolver recovers the real client address behind the ALB/ingress.
+// RemoteAddr is always the load balancer there, and X-Forwarded-For is
+// client-controlled up to the first hop we trust, so the header is walked
+// right to left and the first address outside the trusted proxies wins.
+type clientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// parseCIDRs parses a comma-separated list of CIDRs or bare IPs.
+func parseCIDRs(s string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", part, err)
+			}
+			out = append(out, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", part, err)
+		}
+		out = append(out, p.Masked())
+	}
+	return out, nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client that sent r. X-Forwarded-For is
+// only consulted when the direct peer is a trusted proxy.
+func (c *clientIPResolver) clientIP(r *http.Request) netip.Addr {
+	peer := parseHostAddr(r.RemoteAddr)
+	if !peer.IsValid() || !containsAddr(c.trusted, peer) {
+		return peer
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Malformed entry: nothing to its left can be trusted either.
+			return peer
+		}
+		addr = addr.Unmap()
+		if !containsAddr(c.trusted, addr) {
+			return addr
+		}
+		peer = addr
+	}
+	// Every hop is a trusted proxy — the leftmost one is the best we have.
+	return peer
+}
+
+// middleware rewrites RemoteAddr to the resolved client IP so the request
+// logger and downstream handlers see the real client.
+func (c *clientIPResolver) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr := c.clientIP(r); addr.IsValid() {
+			r.RemoteAddr = addr.String()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseHostAddr parses "host:port" or a bare host into an address.
+func parseHostAddr(s string) netip.Addr {
+	host, _, err := net.SplitHostPort(s)
+	if err != nil {
+		host = s
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// allowSources rejects requests whose client IP (already resolved by
+// clientIPResolver.middleware) is outside allowed. Used to accept Telegram
+// webhooks only from Telegram's published ranges.
+func allowSources(allowed []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr := parseHostAddr(r.RemoteAddr)
+			if !addr.IsValid() || !containsAddr(allowed, addr) {
+				slog.Warn("webhook from disallowed source", "client_ip", r.RemoteAddr, "path", r.URL.Path)
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
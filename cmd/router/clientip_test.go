@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := parseCIDRs("10.0.0.0/16, 192.168.1.1")
+	require.NoError(t, err)
+	c := &clientIPResolver{trusted: trusted}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		want       string
+	}{
+		{"no proxy", "203.0.113.7:5555", nil, "203.0.113.7"},
+		{"untrusted peer ignores header", "203.0.113.7:5555", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted peer uses header", "10.0.3.4:5555", []string{"149.154.167.1"}, "149.154.167.1"},
+		{"spoofed leftmost entry skipped", "10.0.3.4:5555", []string{"1.2.3.4, 149.154.167.1"}, "149.154.167.1"},
+		{"trusted hops skipped", "10.0.3.4:5555", []string{"149.154.167.1, 192.168.1.1, 10.0.9.9"}, "149.154.167.1"},
+		{"multiple header lines", "10.0.3.4:5555", []string{"1.2.3.4", "149.154.167.1"}, "149.154.167.1"},
+		{"all hops trusted", "10.0.3.4:5555", []string{"10.0.1.1"}, "10.0.1.1"},
+		{"malformed hop stops walk", "10.0.3.4:5555", []string{"149.154.167.1, garbage"}, "10.0.3.4"},
+		{"no header from trusted peer", "10.0.3.4:5555", nil, "10.0.3.4"},
+		{"ipv6 peer", "[2001:db8::1]:5555", nil, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/tg/alice", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			assert.Equal(t, tt.want, c.clientIP(r).String())
+		})
+	}
+}
+
+func TestParseCIDRs_Invalid(t *testing.T) {
+	_, err := parseCIDRs("10.0.0.0/33")
+	assert.Error(t, err)
+	_, err = parseCIDRs("not-an-ip")
+	assert.Error(t, err)
+}
+
+func TestAllowSources(t *testing.T) {
+	allowed, err := parseCIDRs(telegramCIDRs)
+	require.NoError(t, err)
+	c := &clientIPResolver{}
+	h := c.middleware(allowSources(allowed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for addr, want := range map[string]int{
+		"149.154.167.220:443": http.StatusOK,
+		"91.108.6.10:443":     http.StatusOK,
+		"203.0.113.7:443":     http.StatusForbidden,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/tg/alice", nil)
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code, addr)
+	}
+}
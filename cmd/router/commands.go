@@ -0,0 +1,33 @@
+package main
+
+import "strings"
+
+// parseCommands parses a comma-separated list of bot commands, e.g.
+// "/reset,/new". The leading slash is optional and matching is
+// case-insensitive.
+func parseCommands(s string) map[string]bool {
+	cmds := map[string]bool{}
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if !strings.HasPrefix(c, "/") {
+			c = "/" + c
+		}
+		cmds[c] = true
+	}
+	return cmds
+}
+
+// isCommand reports whether text is one of cmds. Only the first word counts,
+// and a "@botname" suffix (added by Telegram in group chats) is ignored, so
+// "/reset@alice_bot now" matches "/reset".
+func isCommand(cmds map[string]bool, text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return false
+	}
+	cmd, _, _ := strings.Cut(fields[0], "@")
+	return cmds[strings.ToLower(cmd)]
+}
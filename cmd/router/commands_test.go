@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommands(t *testing.T) {
+	cmds := parseCommands(" /reset, new ,/Forget,,")
+	assert.Equal(t, map[string]bool{"/reset": true, "/new": true, "/forget": true}, cmds)
+}
+
+func TestIsCommand(t *testing.T) {
+	cmds := parseCommands("/reset,/new")
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"/reset", true},
+		{"/RESET", true},
+		{"/new please", true},
+		{"/reset@alice_bot", true},
+		{"  /reset  ", true},
+		{"reset", false},
+		{"please /reset", false},
+		{"/resetting", false},
+		{"/start", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isCommand(cmds, tt.text), tt.text)
+	}
+}
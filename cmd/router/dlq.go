@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+)
+
+const (
+	// dlqPrefix holds, per tenant, the updates that couldn't be forwarded
+	// after the retries, oldest first
+	dlqPrefix = contract.DLQPrefix
+	dlqTTL    = 7 * 24 * time.Hour // counted from the latest failure
+	dlqMaxLen = 1000               // older entries are dropped beyond this
+)
+
+// retryPolicy is how a failed forward is retried (FORWARD_RETRIES,
+// FORWARD_RETRY_BACKOFF_MS, FORWARD_RETRY_JITTER_MS). The zero value
+// dead-letters an update on its first failure.
+type retryPolicy struct {
+	attempts int           // retries after the first failure
+	backoff  time.Duration // pause before the first retry; doubles for each one after
+	jitter   time.Duration // upper bound of the random pause added to each
+}
+
+var defaultForwardRetry = retryPolicy{attempts: 3, backoff: 2 * time.Second, jitter: 500 * time.Millisecond}
+
+// parseRetryPolicy reads FORWARD_RETRIES, FORWARD_RETRY_BACKOFF_MS and
+// FORWARD_RETRY_JITTER_MS
+func parseRetryPolicy(attempts, backoffMs, jitterMs string) (retryPolicy, error) {
+	var n [3]int
+	for i, v := range []struct{ name, value string }{
+		{"FORWARD_RETRIES", attempts},
+		{"FORWARD_RETRY_BACKOFF_MS", backoffMs},
+		{"FORWARD_RETRY_JITTER_MS", jitterMs},
+	} {
+		var err error
+		if n[i], err = strconv.Atoi(v.value); err != nil || n[i] < 0 {
+			return retryPolicy{}, fmt.Errorf("%s must be a non-negative integer, got %q", v.name, v.value)
+		}
+	}
+	return retryPolicy{
+		attempts: n[0],
+		backoff:  time.Duration(n[1]) * time.Millisecond,
+		jitter:   time.Duration(n[2]) * time.Millisecond,
+	}, nil
+}
+
+// delay is the pause before the given retry (1 for the first)
+func (p retryPolicy) delay(retry int) time.Duration {
+	d := p.backoff << (retry - 1)
+	if p.jitter > 0 {
+		d += rand.N(p.jitter)
+	}
+	return d
+}
+
+// deadLetter is an update kept in the tenant's dead-letter queue
+type deadLetter struct {
+	Update   json.RawMessage `json:"update"`
+	Error    string          `json:"error"`
+	Attempts int             `json:"attempts"`
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+// retryForward retries an update whose forward failed with err, waking the
+// pod again before each attempt since the one it was sent to may be gone.
+// If every attempt fails the update is dead-lettered rather than lost. A kill
+// switch pulled meanwhile stops the retries, as it would a new update. It
+// returns the text volume of the attempt that got through, or the last error
+// if none did.
+func (rt *Router) retryForward(ctx context.Context, tenantID string, body []byte, err error) (messageVolume, error) {
+	attempts := 1
+	for ; attempts <= rt.forwardRetry.attempts; attempts++ {
+		delay := rt.forwardRetry.delay(attempts)
+		slog.Warn("forward failed, retrying", "tenant", tenantID, "attempt", attempts, "retry_in", delay, "err", err)
+		select {
+		case <-ctx.Done():
+			rt.deadLetter(tenantID, body, attempts, err)
+			return messageVolume{}, err
+		case <-time.After(delay):
+		}
+		if rt.refuseKilled(ctx, tenantID, extractReplyTarget(body)) {
+			return messageVolume{}, errTenantDisabled
+		}
+		podIP, ttl, wakeErr := rt.wakePod(ctx, tenantID, nil)
+		if errors.Is(wakeErr, errTenantDisabled) {
+			slog.Info("kill switch on, not retrying", "tenant", tenantID)
+			return messageVolume{}, wakeErr
+		}
+		if wakeErr != nil {
+			err = wakeErr
+			continue
+		}
+		rt.cacheEndpoint(ctx, tenantID, podIP, ttl)
+		vol, fwdErr := rt.forwardToPod(ctx, podIP, tenantID, body, ttl)
+		if fwdErr == nil {
+			return vol, nil
+		}
+		err = fwdErr
+	}
+	rt.deadLetter(tenantID, body, attempts, err)
+	return messageVolume{}, err
+}
+
+// deadLetter appends an update to the tenant's dead-letter queue, where it
+// waits for GET /admin/dlq/{tenantID} and a replay
+func (rt *Router) deadLetter(tenantID string, body []byte, attempts int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	item, _ := json.Marshal(deadLetter{Update: body, Error: err.Error(), Attempts: attempts, FailedAt: time.Now().UTC()})
+	key := rt.key(dlqPrefix, tenantID)
+	pipe := rt.rdb.TxPipeline()
+	pipe.RPush(ctx, key, item)
+	pipe.LTrim(ctx, key, -dlqMaxLen, -1)
+	pipe.Expire(ctx, key, dlqTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("dead-letter failed, update lost", "tenant", tenantID, "err", err)
+		return
+	}
+	slog.Error("forward failed, update dead-lettered", "tenant", tenantID, "attempts", attempts, "err", err)
+}
+
+// requireAdmin lets through requests carrying ADMIN_TOKEN as a bearer token.
+// Dead letters hold users' messages, so the routes serving them need it.
+func (rt *Router) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(rt.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// deadLettersHandler lists a tenant's dead-lettered updates, oldest first:
+// GET /admin/dlq/{tenantID}
+func (rt *Router) deadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	items, err := rt.rdb.LRange(r.Context(), rt.key(dlqPrefix, tenantID), 0, -1).Result()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	letters := make([]deadLetter, 0, len(items))
+	for _, item := range items {
+		var l deadLetter
+		if json.Unmarshal([]byte(item), &l) == nil {
+			letters = append(letters, l)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"tenant_id": tenantID, "dead_letters": letters})
+}
+
+// replayDeadLettersHandler empties a tenant's dead-letter queue and
+// delivers its updates again, in order: POST /admin/dlq/{tenantID}/replay.
+// Replays skip the maximum message age, which dead letters have usually
+// outlived; an update that fails again goes back on the queue.
+func (rt *Router) replayDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	key := rt.key(dlqPrefix, tenantID)
+	pipe := rt.rdb.TxPipeline()
+	lrange := pipe.LRange(r.Context(), key, 0, -1)
+	pipe.Del(r.Context(), key)
+	if _, err := pipe.Exec(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	replayed := 0
+	for _, item := range lrange.Val() {
+		var l deadLetter
+		if json.Unmarshal([]byte(item), &l) != nil {
+			continue
+		}
+		rt.dispatchUpdate(tenantID, l.Update, dispatch{replay: true})
+		replayed++
+	}
+	slog.Info("dead letters replayed", "tenant", tenantID, "count", replayed)
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"ok":true,"replayed":%d}`, replayed)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetryForward: a forward that fails is retried after waking the pod
+// again, until it gets through or the retries run out
+func TestRetryForward(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		failures  int32
+		wantCalls int32
+		wantSent  []string
+	}{
+		{"second attempt gets through", 1, 2, []string{"send:Hi"}},
+		{"every attempt fails", 10, 4, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &telegramRecorder{}
+			var podCalls, wakes atomic.Int32
+			rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"response":"Hi"}`)
+			}, tg, 0)
+			rt.forwardRetry = retryPolicy{attempts: 3, backoff: time.Millisecond, jitter: time.Millisecond}
+			orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/wake/") {
+					wakes.Add(1)
+					fmt.Fprint(w, `{"pod_ip":"10.0.0.1"}`)
+					return
+				}
+				fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+			}))
+			t.Cleanup(orch.Close)
+			rt.orchestratorAddr = orch.URL
+			pod := rt.podClient.Transport
+			rt.podClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				if r.URL.Host == "10.0.0.1:3000" && podCalls.Add(1) <= tc.failures {
+					return nil, errors.New("connection refused")
+				}
+				return pod.RoundTrip(r)
+			})}
+
+			body := []byte(`{"message":{"chat":{"id":1},"text":"hi"}}`)
+			_, err := rt.forwardToPod(context.Background(), "10.0.0.1", "alice", body, 0)
+			assert.Error(t, err)
+			rt.retryForward(context.Background(), "alice", body, err)
+			assert.Equal(t, tc.wantCalls, podCalls.Load())
+			assert.Equal(t, tc.wantCalls-1, wakes.Load(), "the pod is woken again before each retry")
+			assert.Equal(t, tc.wantSent, tg.calls)
+		})
+	}
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	p, err := parseRetryPolicy("5", "100", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, retryPolicy{attempts: 5, backoff: 100 * time.Millisecond}, p)
+	assert.Equal(t, 400*time.Millisecond, p.delay(3), "backoff doubles for each retry")
+
+	p.jitter = 50 * time.Millisecond
+	for range 20 {
+		d := p.delay(1)
+		assert.True(t, d >= 100*time.Millisecond && d < 150*time.Millisecond, d)
+	}
+
+	_, err = parseRetryPolicy("3", "-1", "0")
+	assert.ErrorContains(t, err, "FORWARD_RETRY_BACKOFF_MS")
+	_, err = parseRetryPolicy("x", "100", "0")
+	assert.ErrorContains(t, err, "FORWARD_RETRIES")
+}
+
+// TestRetryForward_KillSwitch: a kill switch pulled during the retries stops
+// them instead of waking the pod again
+func TestRetryForward_KillSwitch(t *testing.T) {
+	var wakes atomic.Int32
+	rt := newStreamTestRouter(t, nil, &telegramRecorder{}, 0)
+	rt.forwardRetry = retryPolicy{attempts: 3, backoff: time.Millisecond}
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/wake/") {
+			wakes.Add(1)
+			w.Header().Set(contract.KillSwitchHeader, "global")
+			http.Error(w, "kill switch on", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+	}))
+	t.Cleanup(orch.Close)
+	rt.orchestratorAddr = orch.URL
+
+	_, err := rt.retryForward(context.Background(), "alice", []byte(`{"message":{"chat":{"id":1},"text":"hi"}}`), errors.New("connection refused"))
+	assert.ErrorIs(t, err, errTenantDisabled)
+	assert.Equal(t, int32(1), wakes.Load(), "no retry after the refusal")
+}
+
+// TestHandleTelegramUpdate_ReplaySkipsAgeLimit: a replayed dead letter is
+// delivered however old it is
+func TestHandleTelegramUpdate_ReplaySkipsAgeLimit(t *testing.T) {
+	var forwards atomic.Int32
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		forwards.Add(1)
+		fmt.Fprint(w, `{"response":"Hi"}`)
+	}, &telegramRecorder{}, 0)
+	rt.maxMessageAge = time.Hour
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/wake/"):
+			fmt.Fprint(w, `{"pod_ip":"10.0.0.1"}`)
+		case strings.HasSuffix(r.URL.Path, "/activity"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+		}
+	}))
+	t.Cleanup(orch.Close)
+	rt.orchestratorAddr = orch.URL
+	body := []byte(fmt.Sprintf(`{"message":{"chat":{"id":1},"date":%d,"text":"hi"}}`, time.Now().Add(-3*time.Hour).Unix()))
+
+	rt.handleTelegramUpdate("alice", body, dispatch{})
+	assert.Zero(t, forwards.Load(), "stale")
+	rt.handleTelegramUpdate("alice", body, dispatch{replay: true})
+	assert.Equal(t, int32(1), forwards.Load())
+}
+
+// TestDeadLetterRoutes_RequireAdminToken: dead letters hold users' messages,
+// so their routes need ADMIN_TOKEN and are off without one
+func TestDeadLetterRoutes_RequireAdminToken(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	r := chi.NewRouter()
+	(&Router{rdb: rdb, adminToken: "s3cret"}).routes(r, nil)
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/admin/dlq/alice"},
+		{http.MethodPost, "/admin/dlq/alice/replay"},
+	} {
+		for _, auth := range []string{"", "Bearer wrong", "s3cret"} {
+			rec := httptest.NewRecorder()
+			hr := httptest.NewRequest(req.method, req.path, nil)
+			if auth != "" {
+				hr.Header.Set("Authorization", auth)
+			}
+			r.ServeHTTP(rec, hr)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "%s %s with %q", req.method, req.path, auth)
+		}
+		rec := httptest.NewRecorder()
+		hr := httptest.NewRequest(req.method, req.path, nil)
+		hr.Header.Set("Authorization", "Bearer s3cret")
+		r.ServeHTTP(rec, hr)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "authorized, then fails on Redis being down")
+	}
+
+	r = chi.NewRouter()
+	(&Router{rdb: rdb}).routes(r, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/dlq/alice", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "off without ADMIN_TOKEN")
+}
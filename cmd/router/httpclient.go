@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// httpTuning configures the router's outbound HTTP clients. Each
+// destination gets its own client and connection pool, so agent replies
+// that take minutes can't crowd out quick Bot API calls.
+type httpTuning struct {
+	// orchestratorTimeout bounds orchestrator calls; a wake blocks for up
+	// to podReadyWait
+	orchestratorTimeout time.Duration
+	// podTimeout bounds a forward to the agent, reply included
+	podTimeout time.Duration
+	// externalTimeout bounds calls to Telegram, Slack and peer routers
+	externalTimeout     time.Duration
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	// http2 lets TLS destinations (Telegram, Slack) negotiate HTTP/2
+	http2 bool
+}
+
+var defaultHTTPTuning = httpTuning{
+	orchestratorTimeout: 320 * time.Second,
+	podTimeout:          300 * time.Second,
+	externalTimeout:     60 * time.Second,
+	maxIdleConnsPerHost: 100,
+	idleConnTimeout:     90 * time.Second,
+	http2:               true,
+}
+
+func parseHTTPTuning(orchestratorS, podS, externalS, maxIdlePerHost, idleConnS, http2 string) (httpTuning, error) {
+	var n [5]int
+	for i, v := range []struct{ name, value string }{
+		{"ORCHESTRATOR_TIMEOUT_S", orchestratorS},
+		{"POD_TIMEOUT_S", podS},
+		{"EXTERNAL_TIMEOUT_S", externalS},
+		{"HTTP_MAX_IDLE_CONNS_PER_HOST", maxIdlePerHost},
+		{"HTTP_IDLE_CONN_TIMEOUT_S", idleConnS},
+	} {
+		var err error
+		if n[i], err = strconv.Atoi(v.value); err != nil || n[i] <= 0 {
+			return httpTuning{}, fmt.Errorf("%s must be a positive integer, got %q", v.name, v.value)
+		}
+	}
+	t := httpTuning{
+		orchestratorTimeout: time.Duration(n[0]) * time.Second,
+		podTimeout:          time.Duration(n[1]) * time.Second,
+		externalTimeout:     time.Duration(n[2]) * time.Second,
+		maxIdleConnsPerHost: n[3],
+		idleConnTimeout:     time.Duration(n[4]) * time.Second,
+	}
+	if t.orchestratorTimeout <= podReadyWait {
+		return httpTuning{}, fmt.Errorf("ORCHESTRATOR_TIMEOUT_S must exceed the %s a wake may take, got %q", podReadyWait, orchestratorS)
+	}
+	var err error
+	if t.http2, err = strconv.ParseBool(http2); err != nil {
+		return httpTuning{}, fmt.Errorf("HTTP2 must be true or false, got %q", http2)
+	}
+	return t, nil
+}
+
+// transport returns a connection pool tuned for many concurrent chats: the
+// default keeps only 2 idle connections per host and 100 overall, so
+// bursts to the orchestrator or Telegram would keep dialing new ones.
+// Idle connections are capped per host and closed after idleConnTimeout.
+func (t httpTuning) transport() *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.MaxIdleConns = 0
+	tr.MaxIdleConnsPerHost = t.maxIdleConnsPerHost
+	tr.IdleConnTimeout = t.idleConnTimeout
+	tr.ForceAttemptHTTP2 = t.http2
+	if !t.http2 {
+		// A non-nil empty map is how net/http turns HTTP/2 off
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return tr
+}
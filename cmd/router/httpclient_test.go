@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHTTPTuning(t *testing.T) {
+	tuning, err := parseHTTPTuning("320", "300", "60", "100", "90", "true")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultHTTPTuning, tuning)
+
+	tuning, err = parseHTTPTuning("400", "600", "10", "500", "30", "false")
+	assert.NoError(t, err)
+	assert.Equal(t, httpTuning{orchestratorTimeout: 400 * time.Second, podTimeout: 600 * time.Second,
+		externalTimeout: 10 * time.Second, maxIdleConnsPerHost: 500, idleConnTimeout: 30 * time.Second}, tuning)
+
+	_, err = parseHTTPTuning("300", "300", "60", "100", "90", "true")
+	assert.ErrorContains(t, err, "ORCHESTRATOR_TIMEOUT_S must exceed", "a wake would time out")
+	_, err = parseHTTPTuning("320", "0", "60", "100", "90", "true")
+	assert.ErrorContains(t, err, "POD_TIMEOUT_S")
+	_, err = parseHTTPTuning("320", "300", "60", "many", "90", "true")
+	assert.ErrorContains(t, err, "HTTP_MAX_IDLE_CONNS_PER_HOST")
+	_, err = parseHTTPTuning("320", "300", "60", "100", "90", "maybe")
+	assert.ErrorContains(t, err, "HTTP2")
+}
+
+func TestHTTPTuning_Transport(t *testing.T) {
+	tr := defaultHTTPTuning.transport()
+	assert.Equal(t, 100, tr.MaxIdleConnsPerHost)
+	assert.Zero(t, tr.MaxIdleConns, "bounded per host, not overall")
+	assert.Equal(t, 90*time.Second, tr.IdleConnTimeout)
+	assert.True(t, tr.ForceAttemptHTTP2)
+	assert.Nil(t, tr.TLSNextProto)
+
+	tuning := defaultHTTPTuning
+	tuning.http2 = false
+	tr = tuning.transport()
+	assert.False(t, tr.ForceAttemptHTTP2)
+	assert.NotNil(t, tr.TLSNextProto, "an empty map turns HTTP/2 off")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+)
+
+// errTenantDisabled is returned for updates a kill switch stops: the
+// environment's, or the tenant's own (from wakePod)
+var errTenantDisabled = errors.New("stopped by kill switch")
+
+// killSwitch reports whether the environment's kill switch is on, with the
+// message users should get instead of the default. Redis errors count as
+// off; the orchestrator still refuses wakes.
+func (rt *Router) killSwitch(ctx context.Context) (message string, on bool) {
+	data, err := rt.rdb.Get(ctx, rt.key(contract.KillSwitchKey, "")).Bytes()
+	if err != nil {
+		return "", false
+	}
+	var ks struct {
+		Active  bool   `json:"active"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &ks) != nil {
+		return "", true // set but unreadable: still on
+	}
+	return ks.Message, ks.Active
+}
+
+// refuseKilled answers an update with the outage message if the kill switch
+// is on, and reports whether it did
+func (rt *Router) refuseKilled(ctx context.Context, tenantID string, to replyTarget) bool {
+	message, on := rt.killSwitch(ctx)
+	if !on {
+		return false
+	}
+	slog.Info("kill switch on, not forwarding", "tenant", tenantID)
+	botToken := rt.getBotToken(ctx, tenantID)
+	if to.ChatID != 0 && botToken != "" {
+		if message == "" {
+			message = rt.msg(ctx, tenantID, i18n.Unavailable)
+		}
+		rt.sendTelegramMessage(tenantID, botToken, to, message)
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+)
+
+const localePrefix = contract.LocalePrefix
+
+// msg renders a system message in the tenant's locale
+func (rt *Router) msg(ctx context.Context, tenantID string, key i18n.Key) string {
+	return i18n.T(rt.getLocale(ctx, tenantID), key)
+}
+
+// getLocale returns the tenant's message locale ("" for the default), cached
+// like bot tokens. The orchestrator drops the cache entry when it changes.
+func (rt *Router) getLocale(ctx context.Context, tenantID string) string {
+	key := rt.key(localePrefix, tenantID)
+	if locale, err := rt.rdb.Get(ctx, key).Result(); err == nil {
+		return locale
+	}
+	locale, err := rt.fetchLocale(ctx, tenantID)
+	if err != nil {
+		return ""
+	}
+	rt.rdb.Set(ctx, key, locale, botTokenCacheTTL)
+	return locale
+}
+
+func (rt *Router) fetchLocale(ctx context.Context, tenantID string) (string, error) {
+	rec, err := rt.orchestrator().GetTenant(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	return rec.Locale, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+)
+
+const (
+	maxReplyPrefix = contract.MaxReplyPrefix
+	// defaultMaxReplyBytes is about four full Telegram messages
+	defaultMaxReplyBytes = 16000
+	// podReplyMaxBytes is the most of a pod's reply the router reads; a
+	// larger one isn't delivered at all
+	podReplyMaxBytes = 8 << 20
+)
+
+// limitReply cuts a reply longer than the tenant's maximum short, on a
+// character boundary, and links the full reply saved by the orchestrator
+// in the tenant's S3 prefix. Without a link (no output storage, or the
+// upload failed) the reply just says it was cut.
+func (rt *Router) limitReply(ctx context.Context, tenantID, reply string) string {
+	limit := rt.maxReplyBytesFor(ctx, tenantID)
+	if limit <= 0 || int64(len(reply)) <= limit {
+		return reply
+	}
+	cut := int(limit)
+	for cut > 0 && !utf8.RuneStart(reply[cut]) {
+		cut--
+	}
+	url, err := rt.saveOutput(ctx, tenantID, reply)
+	if err != nil {
+		slog.Warn("save full reply failed, sending it cut without a link", "tenant", tenantID, "bytes", len(reply), "err", err)
+		return reply[:cut] + "\n\n" + rt.msg(ctx, tenantID, i18n.ReplyCut)
+	}
+	slog.Info("reply cut short", "tenant", tenantID, "bytes", len(reply), "max_bytes", limit)
+	return reply[:cut] + "\n\n" + i18n.T(rt.getLocale(ctx, tenantID), i18n.ReplyCutLink, url)
+}
+
+// limitProgress passes a streamed reply on to show while it is within the
+// tenant's maximum; past it the rest is left to limitReply
+func (rt *Router) limitProgress(ctx context.Context, tenantID string, show func(string)) func(string) {
+	limit := rt.maxReplyBytesFor(ctx, tenantID)
+	return func(reply string) {
+		if limit <= 0 || int64(len(reply)) <= limit {
+			show(reply)
+		}
+	}
+}
+
+// saveOutput keeps the full reply with the orchestrator and returns a link
+// to it: POST /tenants/{id}/outputs
+func (rt *Router) saveOutput(ctx context.Context, tenantID, reply string) (string, error) {
+	out, err := rt.orchestrator().SaveOutput(ctx, tenantID, reply)
+	if err != nil {
+		return "", err
+	}
+	return out.URL, nil
+}
+
+// maxReplyBytesFor returns the tenant's maximum reply length: its own
+// MaxReplyBytes, cached like its locale, else the router's. 0 means any
+// length.
+func (rt *Router) maxReplyBytesFor(ctx context.Context, tenantID string) int64 {
+	key := rt.key(maxReplyPrefix, tenantID)
+	n, err := rt.rdb.Get(ctx, key).Int64()
+	if err != nil {
+		if n, err = rt.fetchMaxReplyBytes(ctx, tenantID); err != nil {
+			return rt.maxReplyBytes
+		}
+		rt.rdb.Set(ctx, key, n, botTokenCacheTTL)
+	}
+	switch {
+	case n < 0:
+		return 0
+	case n > 0:
+		return n
+	default:
+		return rt.maxReplyBytes
+	}
+}
+
+func (rt *Router) fetchMaxReplyBytes(ctx context.Context, tenantID string) (int64, error) {
+	rec, err := rt.orchestrator().GetTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(rec.MaxReplyBytes), nil
+}
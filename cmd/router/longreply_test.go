@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestForwardToPod_LongReply: a reply over the maximum is cut on a
+// character boundary and links the full reply, or says it was cut if it
+// couldn't be saved
+func TestForwardToPod_LongReply(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		outputs  int
+		wantTail string
+	}{
+		{"saved", http.StatusOK, i18n.T("", i18n.ReplyCutLink, "https://s3.example/full")},
+		{"no output storage", http.StatusNotImplemented, i18n.T("", i18n.ReplyCut)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &telegramRecorder{}
+			rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"response":"héllo wörld"}`)
+			}, tg, 0)
+			var saved string
+			orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/tenants/alice/outputs" {
+					body, _ := io.ReadAll(r.Body)
+					saved = string(body)
+					w.WriteHeader(tc.outputs)
+					fmt.Fprint(w, `{"url":"https://s3.example/full"}`)
+					return
+				}
+				fmt.Fprint(w, `{"BotToken":"123:abc","MaxReplyBytes":9}`)
+			}))
+			defer orch.Close()
+			rt.orchestratorAddr = orch.URL
+
+			rt.forwardToPod(context.Background(), "10.0.0.1", "alice", []byte(`{"message":{"chat":{"id":1},"text":"hi"}}`), 0)
+			assert.Equal(t, "héllo wörld", saved)
+			assert.Equal(t, []string{"send:héllo w\n\n" + tc.wantTail}, tg.calls, "cut before the ö, not inside it")
+		})
+	}
+}
+
+// TestAskPod_TooLarge: a reply over podReplyMaxBytes isn't read, and the
+// chat is told so
+func TestAskPod_TooLarge(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"response":"%s"}`, strings.Repeat("x", podReplyMaxBytes))
+	}, tg, 0)
+
+	reply, err := rt.askPod(context.Background(), "10.0.0.1", "alice", podMessage{Message: "hi"}, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, i18n.T("", i18n.ReplyTooLarge), reply.Text)
+}
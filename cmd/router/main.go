@@ -4,18 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/shawn/agentic-tenancy/pkg/client"
 )
 
 func getenv(key, def string) string {
@@ -26,16 +39,68 @@ func getenv(key, def string) string {
 }
 
 const (
-	endpointCacheTTL = 5 * time.Minute
-	cacheKeyPrefix   = "router:endpoint:"
-	podReadyWait     = 5 * time.Minute // Karpenter cold-start (new metal node) can take 4+ minutes
+	endpointCacheTTL = 5 * time.Minute // fallback when the wake response carries no idle_timeout_s
+	cacheKeyPrefix   = contract.EndpointPrefix
+	chatIndexPrefix  = contract.ChatIndexPrefix
+	botTokenPrefix   = contract.BotTokenPrefix
+	botTokenCacheTTL = 10 * time.Minute    // safety net; the orchestrator deletes the key on token rotation
+	chatIndexTTL     = 30 * 24 * time.Hour // reverse lookup window for support/abuse tracing
+	podReadyWait     = 5 * time.Minute     // Karpenter cold-start (new metal node) can take 4+ minutes
+	wakePollInterval = 2 * time.Second
 )
 
 type Router struct {
 	rdb              *redis.Client
 	orchestratorAddr string
-	publicBaseURL    string // e.g. https://<YOUR_ROUTER_DOMAIN>
-	httpClient       *http.Client
+	publicBaseURL    string            // e.g. https://<YOUR_ROUTER_DOMAIN>
+	keyPrefix        string            // environment Redis prefix ("" for the default environment)
+	resetCommands    map[string]bool   // e.g. "/reset"; see isCommand
+	sleepCommands    map[string]bool   // e.g. "/sleep"
+	resetPath        string            // pod endpoint that clears conversation memory
+	region           string            // this router's region ("" = single-region)
+	peers            map[string]string // region → router base URL for relaying
+	relaySecret      string            // shared with peer routers; enables /relay
+	adminToken       string            // bearer token for /admin/dlq; empty disables it
+	env              string            // environment name, for metric labels ("" = default)
+	queue            *updateQueue      // per-tenant ordered delivery; nil delivers each update on its own goroutine
+	// attachmentMaxBytes caps the photos, documents and voice notes passed
+	// to the pod; 0 forwards text only
+	attachmentMaxBytes int64
+	telegramAPI        string // Bot API server ("" = telegram.DefaultAPIBase)
+	// statusSecret verifies status page links; empty disables /status
+	statusSecret []byte
+	// chatSecret verifies web chat links; empty disables /chat
+	chatSecret []byte
+	// streamEditInterval is how often a reply the pod streams is edited into
+	// the Telegram message showing it; 0 sends it once complete
+	streamEditInterval time.Duration
+	// replyParseMode is the Telegram parse_mode agent replies are rendered
+	// in by formatReply ("" = plain text, as written)
+	replyParseMode string
+	// maxMessageAge is how old an update may be and still be forwarded,
+	// unless the tenant overrides it (see refuseStale); 0 = any age
+	maxMessageAge time.Duration
+	stats         cacheStats // for GET /admin/cache/stats
+	// forwardRetry is how an update the pod couldn't be reached for is
+	// retried before it's dead-lettered (see retryForward)
+	forwardRetry retryPolicy
+	// maxReplyBytes is the longest agent reply sent in full, unless the
+	// tenant overrides it (see limitReply); 0 = any length
+	maxReplyBytes int64
+	// rateLimit caps each tenant's inbound Telegram updates (see
+	// allowUpdate); the zero value doesn't limit
+	rateLimit rateLimit
+
+	// orchestratorClient, podClient and externalClient (Telegram, Slack,
+	// peer routers) are tuned apart; see httpTuning
+	orchestratorClient *http.Client
+	podClient          *http.Client
+	externalClient     *http.Client
+}
+
+// key builds a Redis key in the router's environment.
+func (rt *Router) key(prefix, id string) string {
+	return rt.keyPrefix + prefix + id
 }
 
 // ── Telegram webhook receiver ────────────────────────────────────
@@ -49,6 +114,21 @@ func (rt *Router) webhookHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject updates that don't carry the secret_token we registered, so
+	// knowing /tg/{tenantID} isn't enough to inject updates
+	ok, err := rt.checkWebhookSecret(r.Context(), tenantID, r.Header.Get(telegram.SecretTokenHeader))
+	if err != nil {
+		// Telegram retries non-2xx deliveries
+		slog.Error("webhook secret lookup failed", "tenant", tenantID, "err", err)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if !ok {
+		slog.Warn("webhook secret mismatch, rejecting update", "tenant", tenantID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	// Read body — we need it twice (forward to pod later)
 	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
 	if err != nil {
@@ -56,201 +136,577 @@ func (rt *Router) webhookHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Ack Telegram immediately (must respond within 5s)
+	// Ack Telegram immediately (must respond within 5s). Updates over the
+	// rate limit are acked too, or Telegram would deliver them again.
+	allowed := rt.allowUpdate(r.Context(), tenantID)
 	w.WriteHeader(http.StatusOK)
 	if f, ok := w.(http.Flusher); ok {
 		f.Flush()
 	}
+	if !allowed {
+		go rt.refuseRateLimited(tenantID, body)
+		return
+	}
 
 	// Handle message async — Telegram doesn't wait for us
-	go rt.handleTelegramUpdate(tenantID, body)
+	rt.dispatchUpdate(tenantID, body, dispatch{})
 }
 
-func (rt *Router) handleTelegramUpdate(tenantID string, body []byte) {
+// dispatch says where an update handed to dispatchUpdate came from, other
+// than Telegram's webhook
+type dispatch struct {
+	relayed bool // by another region's router
+	replay  bool // from the dead-letter queue, so exempt from the age limit
+}
+
+// dispatchUpdate hands an update to the tenant's queue, or straight to
+// handleTelegramUpdate if queueing is off.
+func (rt *Router) dispatchUpdate(tenantID string, body []byte, d dispatch) {
+	if rt.queue == nil {
+		go rt.handleTelegramUpdate(tenantID, body, d)
+		return
+	}
+	rt.queue.push(tenantID, body, d)
+}
+
+// handleTelegramUpdate delivers one update to the tenant's pod. Updates for
+// tenants homed in another region are relayed there, unless they were
+// already relayed to us.
+func (rt *Router) handleTelegramUpdate(tenantID string, body []byte, d dispatch) {
 	ctx, cancel := context.WithTimeout(context.Background(), podReadyWait+30*time.Second)
 	defer cancel()
 
-	// Check if pod is already running (Redis cache)
-	podIP, err := rt.getCachedPodIP(ctx, tenantID)
-	if err == nil && podIP != "" {
-		// Pod is up — forward directly
-		rt.forwardToPod(ctx, podIP, tenantID, body)
-		rt.updateActivity(tenantID)
+	if !d.relayed {
+		if home := rt.remoteHome(ctx, tenantID); home != "" {
+			rt.relayToHome(ctx, home, tenantID, body)
+			return
+		}
+	}
+
+	to := extractReplyTarget(body)
+	rt.indexChat(ctx, tenantID, to.ChatID)
+	// Stop the pressed button's spinner now; waking the pod may take minutes
+	rt.answerCallback(ctx, tenantID, body)
+
+	// A backlog Telegram delivers hours late mustn't set off stale actions.
+	// Dead letters are old by nature; replaying them is an operator's call.
+	if !d.replay && rt.refuseStale(ctx, tenantID, body, to) {
 		return
 	}
 
-	// Pod not running — send "starting up" message to user via Telegram
-	chatID := extractChatID(body)
-	botToken := rt.getBotToken(ctx, tenantID)
-	if chatID != 0 && botToken != "" {
-		rt.sendTelegramMessage(botToken, chatID, "⏳ Starting up, please wait a moment...")
+	// Sleeping must not wake the pod first
+	if isCommand(rt.sleepCommands, extractMessageText(body)) {
+		rt.sleepTenant(ctx, tenantID, to)
+		return
 	}
 
-	// Wake the pod
-	podIP, err = rt.wakePod(ctx, tenantID)
+	podIP, ttl, err := rt.resolvePod(ctx, tenantID, to)
 	if err != nil {
-		slog.Error("wake failed", "tenant", tenantID, "err", err)
-		if chatID != 0 && botToken != "" {
-			rt.sendTelegramMessage(botToken, chatID, "❌ Failed to start. Please try again.")
-		}
 		return
 	}
 
+	// Reset commands are handled here so the agent doesn't have to parse them
+	var vol messageVolume
+	if isCommand(rt.resetCommands, extractMessageText(body)) {
+		rt.resetConversation(ctx, podIP, tenantID, to, ttl)
+	} else if vol, err = rt.forwardToPod(ctx, podIP, tenantID, body, ttl); err != nil {
+		if vol, err = rt.retryForward(ctx, tenantID, body, err); err != nil {
+			// Dead-lettered or stopped: nothing was delivered to meter
+			return
+		}
+	}
+	if to.ChatID != 0 {
+		vol.ChatID = strconv.FormatInt(to.ChatID, 10)
+	}
+	rt.updateActivity(tenantID, vol)
+}
+
+// resolvePod returns the tenant's pod IP from the cache, waking the pod (and
+// telling the user it is starting) on a miss. It returns the wake error if
+// the pod couldn't be started, and errTenantDisabled while the kill switch
+// is on.
+func (rt *Router) resolvePod(ctx context.Context, tenantID string, to replyTarget) (podIP string, ttl time.Duration, err error) {
+	if rt.refuseKilled(ctx, tenantID, to) {
+		return "", 0, errTenantDisabled
+	}
+
+	// The bot token is only needed, and looked up, once the pod turns out
+	// not to be running
+	var botToken *string
+	tell := func(key i18n.Key) {
+		if to.ChatID == 0 {
+			return
+		}
+		if botToken == nil {
+			token := rt.getBotToken(ctx, tenantID)
+			botToken = &token
+		}
+		if *botToken != "" {
+			rt.sendTelegramMessage(tenantID, *botToken, to, rt.msg(ctx, tenantID, key))
+		}
+	}
+	podIP, ttl, err = rt.resolveEndpoint(ctx, tenantID, tell)
+	if err != nil {
+		tell(wakeFailedMessage(err))
+	}
+	return podIP, ttl, err
+}
+
+// resolveEndpoint returns the tenant's pod IP from the cache, or wakes the
+// pod and caches its IP. tell is called with i18n.StartingUp before waking,
+// and with i18n.RestoringState if the agent then has state to restore.
+func (rt *Router) resolveEndpoint(ctx context.Context, tenantID string, tell func(i18n.Key)) (podIP string, ttl time.Duration, err error) {
+	// Check if pod is already running (Redis cache)
+	podIP, ttl, err = rt.getCachedEndpoint(ctx, tenantID)
+	hit := err == nil && podIP != ""
+	rt.countCacheLookup("endpoint", hit)
+	if hit {
+		return podIP, ttl, nil
+	}
+
+	// Pod not running — tell the user it's starting
+	tell(i18n.StartingUp)
+	podIP, ttl, err = rt.wakePod(ctx, tenantID, func() { tell(i18n.RestoringState) })
+	rt.countWake(tenantID, err)
+	if err != nil {
+		slog.Error("wake failed", "tenant", tenantID, "err", err)
+		return "", 0, err
+	}
+
 	// Cache the new pod IP
-	rt.rdb.Set(ctx, cacheKeyPrefix+tenantID, podIP, endpointCacheTTL)
+	rt.cacheEndpoint(ctx, tenantID, podIP, ttl)
+	return podIP, ttl, nil
+}
 
-	// Forward the original message
-	rt.forwardToPod(ctx, podIP, tenantID, body)
-	rt.updateActivity(tenantID)
+// wakeFailedMessage picks the message telling a user their bot didn't start
+func wakeFailedMessage(err error) i18n.Key {
+	switch {
+	case errors.Is(err, errStorageFull):
+		return i18n.StorageFull
+	case errors.Is(err, errTenantSuspended):
+		return i18n.BotPaused
+	case errors.Is(err, errRunQuota):
+		return i18n.QuotaExceeded
+	case errors.Is(err, errTenantDisabled), errors.Is(err, errTenantFailed):
+		return i18n.Unavailable
+	}
+	return i18n.StartFailed
 }
 
-func (rt *Router) forwardToPod(ctx context.Context, podIP, tenantID string, body []byte) {
+// forwardToPod sends the update's text and attachments, or button press, to
+// ZeroClaw and relays the reply to the Telegram chat it came from. It returns
+// the text volume exchanged, and an error if the pod couldn't be reached, so
+// the update can be retried.
+func (rt *Router) forwardToPod(ctx context.Context, podIP, tenantID string, body []byte, ttl time.Duration) (messageVolume, error) {
 	// Parse Telegram Update and extract message text
 	text := extractMessageText(body)
-	if text == "" {
+	attachments := rt.fetchAttachments(ctx, tenantID, body)
+	if text == "" && len(attachments) == 0 {
 		slog.Info("no text in update, skipping forward", "tenant", tenantID)
-		return
+		return messageVolume{}, nil
+	}
+	msg := podMessage{Message: text, Attachments: attachments}
+	if cq := callbackQueryOf(body); cq != nil {
+		msg.Callback = &podCallback{Data: cq.Data}
+		if cq.Message != nil {
+			msg.Callback.MessageID = cq.Message.MessageID
+		}
 	}
 
-	// ZeroClaw /webhook expects {"message": "..."}
-	payload, _ := json.Marshal(map[string]string{"message": text})
+	to := extractReplyTarget(body)
+	botToken := rt.getBotToken(ctx, tenantID)
+	if to.ChatID == 0 || botToken == "" {
+		reply, err := rt.askPod(ctx, podIP, tenantID, msg, ttl, nil)
+		return newMessageVolume(text, reply.Text), err
+	}
+	// A streamed reply is shown while it is generated; any other is sent
+	// once, by finish
+	stream := rt.newReplyStream(tenantID, botToken, to)
+	var progress func(string)
+	if rt.streamEditInterval > 0 {
+		progress = rt.limitProgress(ctx, tenantID, stream.update)
+	}
+	reply, err := rt.askPod(ctx, podIP, tenantID, msg, ttl, progress)
+	if reply.Text != "" {
+		stream.finish(rt.limitReply(ctx, tenantID, reply.Text), inlineKeyboard(tenantID, reply.ReplyMarkup))
+	}
+	return newMessageVolume(text, reply.Text), err
+}
+
+// podReply is ZeroClaw's answer to a message
+type podReply = contract.AgentReply
+
+// askPod sends a message to ZeroClaw and returns its reply (empty if none),
+// or an error if the pod couldn't be reached. A successful forward refreshes
+// the endpoint cache TTL (the pod's idle clock restarts with this message); a
+// failed one invalidates the cache entry.
+// The pod may stream its reply as server-sent events (see readReplyStream);
+// progress, if set, then sees the reply as it grows. A reply over
+// podReplyMaxBytes is replaced with a note saying so, or for a stream, read
+// only that far.
+func (rt *Router) askPod(ctx context.Context, podIP, tenantID string, msg podMessage, ttl time.Duration, progress func(string)) (podReply, error) {
+	payload, _ := json.Marshal(msg)
 
-	url := fmt.Sprintf("http://%s:3000/webhook", podIP)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, contract.AgentURL(podIP, contract.AgentWebhookPath), bytes.NewReader(payload))
 	if err != nil {
 		slog.Error("build forward request", "tenant", tenantID, "err", err)
-		return
+		return podReply{}, nil
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set(contract.AgentVersionHeader, contract.AgentVersion)
 
-	resp, err := rt.httpClient.Do(req)
+	start := time.Now()
+	resp, err := rt.podClient.Do(req)
+	rt.observeForward(tenantID, start, err)
 	if err != nil {
 		slog.Warn("forward to pod failed, invalidating cache", "tenant", tenantID, "err", err)
-		rt.rdb.Del(ctx, cacheKeyPrefix+tenantID)
-		return
+		rt.invalidateCache(ctx, "endpoint", tenantID, invalidateForwardFailed)
+		return podReply{}, err
 	}
 	defer resp.Body.Close()
+	rt.rdb.Expire(ctx, rt.key(cacheKeyPrefix, tenantID), ttl)
+	slog.Info("forwarded to pod", "tenant", tenantID, "pod_ip", podIP, "status", resp.StatusCode)
 
-	// Read response from ZeroClaw and send back to Telegram
-	var result struct {
-		Response string `json:"response"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Response != "" {
-		chatID := extractChatID(body)
-		botToken := rt.getBotToken(ctx, tenantID)
-		if chatID != 0 && botToken != "" {
-			rt.sendTelegramMessage(botToken, chatID, result.Response)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		reply, err := readReplyStream(io.LimitReader(resp.Body, podReplyMaxBytes), progress)
+		if err != nil {
+			slog.Warn("pod reply stream broken off", "tenant", tenantID, "err", err)
 		}
+		return reply, nil
 	}
-	slog.Info("forwarded to pod", "tenant", tenantID, "pod_ip", podIP, "status", resp.StatusCode)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, podReplyMaxBytes+1))
+	if err != nil {
+		return podReply{}, nil
+	}
+	if len(body) > podReplyMaxBytes {
+		slog.Warn("pod reply too large, not delivering", "tenant", tenantID, "max_bytes", podReplyMaxBytes)
+		return podReply{Text: rt.msg(ctx, tenantID, i18n.ReplyTooLarge)}, nil
+	}
+	var reply podReply
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return podReply{}, nil
+	}
+	return reply, nil
 }
 
-func (rt *Router) getCachedPodIP(ctx context.Context, tenantID string) (string, error) {
-	return rt.rdb.Get(ctx, cacheKeyPrefix+tenantID).Result()
+// indexChat records that chatID talked to tenantID so support can resolve a
+// Telegram chat back to a tenant (GET /lookup/chat/{chatID} on the orchestrator).
+func (rt *Router) indexChat(ctx context.Context, tenantID string, chatID int64) {
+	if chatID == 0 {
+		return
+	}
+	key := rt.key(chatIndexPrefix, strconv.FormatInt(chatID, 10))
+	pipe := rt.rdb.TxPipeline()
+	pipe.HSet(ctx, key, tenantID, time.Now().UTC().Format(time.RFC3339))
+	pipe.Expire(ctx, key, chatIndexTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("chat index update failed", "tenant", tenantID, "chat_id", chatID, "err", err)
+	}
 }
 
-func (rt *Router) wakePod(ctx context.Context, tenantID string) (string, error) {
-	resp, err := rt.httpClient.Post(
-		fmt.Sprintf("%s/wake/%s", rt.orchestratorAddr, tenantID),
-		"application/json", nil,
-	)
+// getCachedEndpoint returns the cached pod IP and the TTL it was cached with.
+// Endpoints are stored as a hash {pod_ip, ttl_s} so the TTL can be refreshed
+// on the cache-hit path without another orchestrator round trip.
+func (rt *Router) getCachedEndpoint(ctx context.Context, tenantID string) (string, time.Duration, error) {
+	vals, err := rt.rdb.HMGet(ctx, rt.key(cacheKeyPrefix, tenantID), contract.EndpointPodIP, contract.EndpointTTL).Result()
 	if err != nil {
-		return "", fmt.Errorf("orchestrator wake: %w", err)
+		return "", 0, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("wake status %d: %s", resp.StatusCode, body)
+	podIP, _ := vals[0].(string)
+	ttlS, _ := vals[1].(string)
+	n, _ := strconv.ParseInt(ttlS, 10, 64)
+	return podIP, cacheTTL(n), nil
+}
+
+// cacheEndpoint stores the pod IP for a tenant with the given TTL.
+func (rt *Router) cacheEndpoint(ctx context.Context, tenantID, podIP string, ttl time.Duration) {
+	key := rt.key(cacheKeyPrefix, tenantID)
+	pipe := rt.rdb.TxPipeline()
+	pipe.Del(ctx, key) // drop any legacy plain-string entry
+	pipe.HSet(ctx, key, contract.EndpointPodIP, podIP, contract.EndpointTTL, int64(ttl/time.Second))
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("cache endpoint failed", "tenant", tenantID, "err", err)
 	}
-	var result struct {
-		PodIP string `json:"pod_ip"`
+}
+
+// cacheTTL converts a tenant's idle_timeout_s into an endpoint cache TTL.
+// The pod is guaranteed to live at least idle_timeout_s after the last
+// message, so caching longer than that risks forwarding to a dead IP.
+func cacheTTL(idleTimeoutS int64) time.Duration {
+	if idleTimeoutS <= 0 {
+		return endpointCacheTTL
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode wake response: %w", err)
+	return time.Duration(idleTimeoutS) * time.Second
+}
+
+// orchestrator is the typed client for rt's orchestrator environment
+func (rt *Router) orchestrator() *client.Client {
+	return client.New(rt.orchestratorAddr, rt.orchestratorClient)
+}
+
+// errStorageFull is returned by wakePod when the orchestrator refuses the
+// wake because the tenant's state is over its storage quota
+var errStorageFull = errors.New("tenant state over storage quota")
+
+// errTenantSuspended is returned by wakePod for a suspended tenant
+var errTenantSuspended = errors.New("tenant suspended")
+
+// errTenantFailed is returned by wakePod for a tenant the orchestrator
+// marked failed after too many failed wakes; waking it again is pointless
+// until an operator resets it
+var errTenantFailed = errors.New("tenant failed")
+
+// errRunQuota is returned by wakePod when the orchestrator refuses the wake
+// because the tenant's plan has used up a run quota
+var errRunQuota = errors.New("tenant over run quota")
+
+// wakePod starts an async wake and polls the job until the pod is ready, so
+// a cold start doesn't hold an orchestrator connection open for minutes.
+// Orchestrators without async wakes answer synchronously, which is accepted
+// too. onRestoring, if set, is called once if the job reports the pod
+// running but the agent still restoring its state.
+func (rt *Router) wakePod(ctx context.Context, tenantID string, onRestoring func()) (string, time.Duration, error) {
+	job, err := rt.orchestrator().WakeAsync(ctx, tenantID)
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusMisdirectedRequest {
+			// Our cached home region is stale; look it up again next time
+			rt.rdb.Del(ctx, rt.key(homeRegionPrefix, tenantID))
+		}
+		switch apiErr.StatusCode {
+		case http.StatusInsufficientStorage:
+			return "", 0, errStorageFull
+		case http.StatusLocked:
+			return "", 0, errTenantSuspended
+		case http.StatusConflict:
+			return "", 0, errTenantFailed
+		case http.StatusTooManyRequests:
+			return "", 0, errRunQuota
+		case http.StatusServiceUnavailable:
+			if apiErr.Header.Get(contract.KillSwitchHeader) != "" {
+				return "", 0, errTenantDisabled
+			}
+		}
+		return "", 0, fmt.Errorf("wake status %d: %s", apiErr.StatusCode, apiErr.Message)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("orchestrator wake: %w", err)
+	}
+	if job.ID != "" && job.Status == "ready" {
+		// Ready from the start: the pod was running all along
+		rt.countWakeFoundRunning()
+	}
+
+	restoring := false
+	for job.ID != "" && job.Status != "ready" {
+		if job.Status == "failed" {
+			return "", 0, fmt.Errorf("wake job %s failed: %s", job.ID, job.Error)
+		}
+		if job.Status == "restoring" && !restoring {
+			restoring = true
+			if onRestoring != nil {
+				onRestoring()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", 0, fmt.Errorf("wake job %s: %w", job.ID, ctx.Err())
+		case <-time.After(wakePollInterval):
+		}
+		if job, err = rt.orchestrator().GetWakeJob(ctx, job.ID); err != nil {
+			return "", 0, fmt.Errorf("poll wake job: %w", err)
+		}
 	}
-	return result.PodIP, nil
+	return job.PodIP, cacheTTL(job.IdleTimeoutS), nil
 }
 
+// getBotToken returns the tenant's bot token, served from Redis when cached.
+// The orchestrator deletes the cache key when the token is rotated or the
+// tenant is deleted, so the TTL only bounds staleness if that delete is lost.
 func (rt *Router) getBotToken(ctx context.Context, tenantID string) string {
-	resp, err := rt.httpClient.Get(fmt.Sprintf("%s/tenants/%s/bot_token", rt.orchestratorAddr, tenantID))
-	if err != nil {
-		return ""
+	key := rt.key(botTokenPrefix, tenantID)
+	if token, err := rt.rdb.Get(ctx, key).Result(); err == nil && token != "" {
+		rt.countCacheLookup("bot_token", true)
+		return token
 	}
-	defer resp.Body.Close()
-	var rec struct {
-		BotToken string `json:"BotToken"`
+	rt.countCacheLookup("bot_token", false)
+	token := rt.fetchBotToken(ctx, tenantID)
+	if token != "" {
+		if err := rt.rdb.Set(ctx, key, token, botTokenCacheTTL).Err(); err != nil {
+			slog.Warn("cache bot token failed", "tenant", tenantID, "err", err)
+		}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&rec); err != nil {
+	return token
+}
+
+func (rt *Router) fetchBotToken(ctx context.Context, tenantID string) string {
+	rec, err := rt.orchestrator().GetBotToken(ctx, tenantID)
+	if err != nil {
 		return ""
 	}
 	return rec.BotToken
 }
 
-func (rt *Router) sendTelegramMessage(botToken string, chatID int64, text string) {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
-	payload, _ := json.Marshal(map[string]any{
-		"chat_id": chatID,
-		"text":    text,
-	})
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
-	req.Header.Set("Content-Type", "application/json")
-	rt.httpClient.Do(req)
+// fetchAllowedUpdates returns the tenant's configured update types, or nil
+// (the default subscription) if none are set or the lookup fails.
+func (rt *Router) fetchAllowedUpdates(ctx context.Context, tenantID string) []string {
+	rec, err := rt.orchestrator().GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil
+	}
+	return rec.AllowedUpdates
+}
+
+func (rt *Router) telegramAPIBase() string {
+	if rt.telegramAPI == "" {
+		return telegram.DefaultAPIBase
+	}
+	return rt.telegramAPI
 }
 
-func (rt *Router) updateActivity(tenantID string) {
+// botAPI is the URL of a Bot API method
+func (rt *Router) botAPI(botToken, method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", rt.telegramAPIBase(), botToken, method)
+}
+
+// sendTelegramMessage sends plain text to the chat, forum topic and
+// Business connection an update came from, as several messages in order if
+// it is too long for one
+func (rt *Router) sendTelegramMessage(tenantID, botToken string, to replyTarget, text string) {
+	for _, msg := range newMessageSplitter().split(text) {
+		if _, err := rt.sendTelegramMessageID(botToken, to, msg, "", nil); err != nil {
+			metrics.RouterTelegramFailures.WithLabelValues(rt.env, tenantID).Inc()
+			slog.Warn("telegram sendMessage failed", "tenant", tenantID, "chat_id", to.ChatID, "err", err)
+			rt.dropRejectedBotToken(tenantID, err)
+			return
+		}
+	}
+}
+
+// messageVolume is the text a delivered message carried each way, in
+// characters, and its chat, which the orchestrator meters for billing and
+// engagement
+type messageVolume = client.Activity
+
+func newMessageVolume(in, out string) messageVolume {
+	return messageVolume{CharsIn: int64(utf8.RuneCountInString(in)), CharsOut: int64(utf8.RuneCountInString(out))}
+}
+
+// updateActivity restarts the tenant's idle clock and reports the message
+// just delivered
+func (rt *Router) updateActivity(tenantID string, vol messageVolume) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodPut,
-		fmt.Sprintf("%s/tenants/%s/activity", rt.orchestratorAddr, tenantID), nil)
-	rt.httpClient.Do(req)
+	rt.orchestrator().UpdateActivity(ctx, tenantID, vol)
 }
 
-// extractChatID extracts chat.id from a Telegram Update JSON body.
-func extractChatID(body []byte) int64 {
+// updateMessage is the part of a Telegram Message the router reads
+type updateMessage struct {
+	MessageID int64 `json:"message_id"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text                 string         `json:"text"`
+	Caption              string         `json:"caption"`
+	Photo                []telegramFile `json:"photo"`
+	Document             *telegramFile  `json:"document"`
+	Voice                *telegramFile  `json:"voice"`
+	MessageThreadID      int64          `json:"message_thread_id"`
+	IsTopicMessage       bool           `json:"is_topic_message"`
+	BusinessConnectionID string         `json:"business_connection_id"`
+	Date                 int64          `json:"date"`      // Unix time sent
+	EditDate             int64          `json:"edit_date"` // Unix time last edited; 0 if never
+}
+
+// replyTarget is where replies to an update go: its chat, plus the forum
+// topic and Telegram Business connection it arrived through, if any
+type replyTarget struct {
+	ChatID               int64
+	ThreadID             int64 // forum topic; 0 = the chat itself
+	BusinessConnectionID string
+}
+
+// sendMessage builds a sendMessage request for text
+func (t replyTarget) sendMessage(text string) map[string]any {
+	req := map[string]any{"chat_id": t.ChatID, "text": text}
+	if t.ThreadID != 0 {
+		req["message_thread_id"] = t.ThreadID
+	}
+	if t.BusinessConnectionID != "" {
+		req["business_connection_id"] = t.BusinessConnectionID
+	}
+	return req
+}
+
+// updateMessageOf returns the message carried by an update, whichever of the
+// subscribable message types (see telegram.AllowedUpdates) it arrived as.
+func updateMessageOf(body []byte) *updateMessage {
 	var update struct {
-		Message *struct {
-			Chat struct {
-				ID int64 `json:"id"`
-			} `json:"chat"`
-		} `json:"message"`
-		CallbackQuery *struct {
-			Message *struct {
-				Chat struct {
-					ID int64 `json:"id"`
-				} `json:"chat"`
-			} `json:"message"`
-		} `json:"callback_query"`
+		Message               *updateMessage `json:"message"`
+		EditedMessage         *updateMessage `json:"edited_message"`
+		ChannelPost           *updateMessage `json:"channel_post"`
+		BusinessMessage       *updateMessage `json:"business_message"`
+		EditedBusinessMessage *updateMessage `json:"edited_business_message"`
 	}
 	if err := json.Unmarshal(body, &update); err != nil {
-		return 0
-	}
-	if update.Message != nil {
-		return update.Message.Chat.ID
+		return nil
 	}
-	if update.CallbackQuery != nil && update.CallbackQuery.Message != nil {
-		return update.CallbackQuery.Message.Chat.ID
+	switch {
+	case update.Message != nil:
+		return update.Message
+	case update.EditedMessage != nil:
+		return update.EditedMessage
+	case update.ChannelPost != nil:
+		return update.ChannelPost
+	case update.BusinessMessage != nil:
+		return update.BusinessMessage
+	default:
+		return update.EditedBusinessMessage
 	}
-	return 0
 }
 
-// extractMessageText extracts the text from a Telegram Update message.
-func extractMessageText(body []byte) string {
-	var update struct {
-		Message *struct {
-			Text    string `json:"text"`
-			Caption string `json:"caption"`
-		} `json:"message"`
+// extractReplyTarget extracts where to reply to a Telegram Update: the
+// message's chat, or for a callback query the chat of the message whose
+// button was pressed.
+func extractReplyTarget(body []byte) replyTarget {
+	msg := updateMessageOf(body)
+	if msg == nil {
+		cq := callbackQueryOf(body)
+		if cq == nil {
+			return replyTarget{}
+		}
+		msg = cq.Message
 	}
-	if err := json.Unmarshal(body, &update); err != nil {
-		return ""
+	if msg == nil {
+		return replyTarget{}
+	}
+	to := replyTarget{ChatID: msg.Chat.ID, BusinessConnectionID: msg.BusinessConnectionID}
+	// Outside forum topics message_thread_id marks a reply thread, which
+	// sendMessage doesn't accept
+	if msg.IsTopicMessage {
+		to.ThreadID = msg.MessageThreadID
 	}
-	if update.Message != nil {
-		if update.Message.Text != "" {
-			return update.Message.Text
+	return to
+}
+
+// extractChatID extracts chat.id from a Telegram Update JSON body.
+func extractChatID(body []byte) int64 {
+	return extractReplyTarget(body).ChatID
+}
+
+// extractMessageText extracts the text from a Telegram Update message. For
+// a callback query (an inline keyboard button press) it is the button's data.
+func extractMessageText(body []byte) string {
+	if msg := updateMessageOf(body); msg != nil {
+		if msg.Text != "" {
+			return msg.Text
 		}
-		return update.Message.Caption
+		return msg.Caption
+	}
+	if cq := callbackQueryOf(body); cq != nil {
+		return cq.Data
 	}
 	return ""
 }
@@ -258,14 +714,32 @@ func extractMessageText(body []byte) string {
 // ── Webhook registration ─────────────────────────────────────────
 
 // RegisterWebhook tells Telegram to push updates to our router for a tenant.
-func (rt *Router) RegisterWebhook(botToken, tenantID string) error {
+// Empty allowedUpdates subscribes to telegram.DefaultAllowedUpdates. The
+// tenant's secret_token is reused, or generated and stored in the registry
+// once Telegram has accepted it.
+func (rt *Router) RegisterWebhook(ctx context.Context, botToken, tenantID string, allowedUpdates []string) error {
+	secret, err := rt.getWebhookSecret(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("look up webhook secret: %w", err)
+	}
+	newSecret := secret == ""
+	if newSecret {
+		secret = telegram.NewWebhookSecret()
+	}
+
 	webhookURL := fmt.Sprintf("%s/tg/%s", rt.publicBaseURL, tenantID)
 	payload, _ := json.Marshal(map[string]any{
-		"url":             webhookURL,
+		"url":                  webhookURL,
 		"drop_pending_updates": true,
+		"allowed_updates":      telegram.AllowedUpdates(allowedUpdates),
+		"secret_token":         secret,
 	})
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/setWebhook", botToken)
-	resp, err := rt.httpClient.Post(url, "application/json", bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rt.botAPI(botToken, "setWebhook"), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := rt.externalClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -275,6 +749,11 @@ func (rt *Router) RegisterWebhook(botToken, tenantID string) error {
 	if ok, _ := result["ok"].(bool); !ok {
 		return fmt.Errorf("setWebhook failed: %v", result)
 	}
+	if newSecret {
+		if err := rt.storeWebhookSecret(ctx, tenantID, secret); err != nil {
+			return fmt.Errorf("store webhook secret: %w", err)
+		}
+	}
 	slog.Info("webhook registered", "tenant", tenantID, "url", webhookURL)
 	return nil
 }
@@ -287,7 +766,7 @@ func (rt *Router) registerWebhookHandler(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "tenant not found or no bot_token", http.StatusNotFound)
 		return
 	}
-	if err := rt.RegisterWebhook(botToken, tenantID); err != nil {
+	if err := rt.RegisterWebhook(r.Context(), botToken, tenantID, rt.fetchAllowedUpdates(r.Context(), tenantID)); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -295,24 +774,184 @@ func (rt *Router) registerWebhookHandler(w http.ResponseWriter, r *http.Request)
 	fmt.Fprintf(w, `{"ok":true,"webhook_url":"%s/tg/%s"}`, rt.publicBaseURL, tenantID)
 }
 
+// routes registers the router's per-environment endpoints on r.
+func (rt *Router) routes(r chi.Router, telegramAllowlist []netip.Prefix) {
+	// Telegram webhook receiver — one URL per tenant
+	if len(telegramAllowlist) > 0 {
+		r.With(allowSources(telegramAllowlist), rt.instrument("telegram")).Post("/tg/{tenantID}", rt.webhookHandler)
+	} else {
+		r.With(rt.instrument("telegram")).Post("/tg/{tenantID}", rt.webhookHandler)
+	}
+
+	// Slack Events API receiver — requests are signed, so no source allowlist
+	r.With(rt.instrument("slack")).Post("/slack/{tenantID}", rt.slackHandler)
+
+	// Updates relayed from routers in other regions
+	if rt.relaySecret != "" {
+		r.With(rt.instrument("relay")).Post("/relay/{tenantID}", rt.relayHandler)
+	}
+
+	// Public tenant status pages, reached through signed links
+	if len(rt.statusSecret) > 0 {
+		r.Get("/status/{token}", rt.statusPageHandler)
+	}
+
+	// Web chat with a tenant's agent, reached through signed links
+	if len(rt.chatSecret) > 0 {
+		r.Get("/chat/{tenantID}", rt.chatPageHandler)
+		r.With(rt.instrument("webchat")).Post("/chat/{tenantID}/messages", rt.chatMessageHandler)
+	}
+
+	// Admin: register webhook for a tenant
+	r.Post("/admin/webhook/{tenantID}", rt.registerWebhookHandler)
+
+	// Admin: updates that couldn't be forwarded
+	if rt.adminToken != "" {
+		r.With(rt.requireAdmin).Get("/admin/dlq/{tenantID}", rt.deadLettersHandler)
+		r.With(rt.requireAdmin).Post("/admin/dlq/{tenantID}/replay", rt.replayDeadLettersHandler)
+	}
+
+	// Admin: this replica's cache hit ratios and invalidations
+	r.Get("/admin/cache/stats", rt.cacheStatsHandler)
+}
+
 // ── Main ─────────────────────────────────────────────────────────
 
 func main() {
 	redisAddr := getenv("REDIS_ADDR", "localhost:6379")
-	orchestratorAddr := getenv("ORCHESTRATOR_ADDR", "http://localhost:8080")
+	orchestratorAddrs, err := parseOrchestratorAddrs(getenv("ORCHESTRATOR_ADDR", "http://localhost:8080")) // e.g. the Service, then per-replica fallbacks
+	if err != nil {
+		slog.Error("parse ORCHESTRATOR_ADDR", "err", err)
+		os.Exit(1)
+	}
+	orchestratorAddr := orchestratorAddrs[0].String()
+	// "dns" or "srv": spread calls over the replicas behind the first address
+	var discovery *orchestratorDiscovery
+	if mode := os.Getenv("ORCHESTRATOR_DISCOVERY"); mode != "" {
+		if discovery, err = newOrchestratorDiscovery(mode, orchestratorAddrs[0]); err != nil {
+			slog.Error("ORCHESTRATOR_DISCOVERY", "err", err)
+			os.Exit(1)
+		}
+	}
 	publicBaseURL := getenv("PUBLIC_BASE_URL", "https://<YOUR_ROUTER_DOMAIN>")
 	port := getenv("PORT", "9090")
+	resetCommands := parseCommands(getenv("RESET_COMMANDS", "/reset")) // e.g. /reset,/new,/forget
+	sleepCommands := parseCommands(getenv("SLEEP_COMMANDS", "/sleep"))
+	resetPath := getenv("POD_RESET_PATH", "/reset")
+	trustedProxies, err := parseCIDRs(os.Getenv("TRUSTED_PROXIES")) // e.g. VPC CIDR the ALB sits in
+	if err != nil {
+		slog.Error("parse TRUSTED_PROXIES", "err", err)
+		os.Exit(1)
+	}
+	allowedSources := os.Getenv("TELEGRAM_ALLOWED_CIDRS") // "telegram" = Telegram's published ranges
+	if allowedSources == "telegram" {
+		allowedSources = telegramCIDRs
+	}
+	telegramAllowlist, err := parseCIDRs(allowedSources)
+	if err != nil {
+		slog.Error("parse TELEGRAM_ALLOWED_CIDRS", "err", err)
+		os.Exit(1)
+	}
+	region := os.Getenv("REGION") // multi-region only: must match the local orchestrator's REGION
+	peers, err := parsePeers(os.Getenv("REGION_PEERS"))
+	if err != nil {
+		slog.Error("parse REGION_PEERS", "err", err)
+		os.Exit(1)
+	}
+	relaySecret := os.Getenv("RELAY_SECRET")
+	// Required by the dead-letter routes, which return users' messages
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	// Verifies status page links; must match the orchestrator's
+	statusSecret := os.Getenv("STATUS_PAGE_SECRET")
+	// Verifies web chat links; must match the orchestrator's
+	chatSecret := os.Getenv("WEB_CHAT_SECRET")
+	attachmentMaxBytes, err := strconv.ParseInt(getenv("ATTACHMENT_MAX_BYTES", strconv.Itoa(defaultAttachmentMaxBytes)), 10, 64) // 0 = text only
+	if err != nil || attachmentMaxBytes < 0 {
+		slog.Error("ATTACHMENT_MAX_BYTES must be a non-negative integer", "value", os.Getenv("ATTACHMENT_MAX_BYTES"))
+		os.Exit(1)
+	}
+	queueConcurrency, err := strconv.Atoi(getenv("TENANT_QUEUE_CONCURRENCY", "1")) // 0 = no queue
+	if err != nil || queueConcurrency < 0 {
+		slog.Error("TENANT_QUEUE_CONCURRENCY must be a non-negative integer", "value", os.Getenv("TENANT_QUEUE_CONCURRENCY"))
+		os.Exit(1)
+	}
+	// 0 = send streamed replies once complete
+	streamEditMs, err := strconv.Atoi(getenv("STREAM_EDIT_INTERVAL_MS", strconv.Itoa(int(defaultStreamEditInterval.Milliseconds()))))
+	if err != nil || streamEditMs < 0 {
+		slog.Error("STREAM_EDIT_INTERVAL_MS must be a non-negative integer", "value", os.Getenv("STREAM_EDIT_INTERVAL_MS"))
+		os.Exit(1)
+	}
+	// MarkdownV2 or HTML; empty sends agent replies as plain text
+	replyParseMode := os.Getenv("REPLY_PARSE_MODE")
+	if !validParseMode(replyParseMode) {
+		slog.Error("REPLY_PARSE_MODE must be MarkdownV2 or HTML", "value", replyParseMode)
+		os.Exit(1)
+	}
+	// 0 = forward updates of any age; tenants may override it
+	maxMessageAgeS, err := strconv.Atoi(getenv("MAX_MESSAGE_AGE_S", "0"))
+	if err != nil || maxMessageAgeS < 0 {
+		slog.Error("MAX_MESSAGE_AGE_S must be a non-negative integer", "value", os.Getenv("MAX_MESSAGE_AGE_S"))
+		os.Exit(1)
+	}
+	maxReplyBytes, err := strconv.ParseInt(getenv("MAX_REPLY_BYTES", strconv.Itoa(defaultMaxReplyBytes)), 10, 64)
+	if err != nil || maxReplyBytes < 0 {
+		slog.Error("MAX_REPLY_BYTES must be a non-negative integer", "value", os.Getenv("MAX_REPLY_BYTES"))
+		os.Exit(1)
+	}
+	forwardRetry, err := parseRetryPolicy(
+		getenv("FORWARD_RETRIES", strconv.Itoa(defaultForwardRetry.attempts)),
+		getenv("FORWARD_RETRY_BACKOFF_MS", strconv.Itoa(int(defaultForwardRetry.backoff.Milliseconds()))),
+		getenv("FORWARD_RETRY_JITTER_MS", strconv.Itoa(int(defaultForwardRetry.jitter.Milliseconds()))))
+	if err != nil {
+		slog.Error("parse forward retry policy", "err", err)
+		os.Exit(1)
+	}
+	// 0 = no limit; a chat flooding its bot can otherwise keep the pod busy
+	rateLimit, err := parseRateLimit(getenv("TENANT_RATE_PER_MIN", "0"), getenv("TENANT_RATE_BURST", "10"))
+	if err != nil {
+		slog.Error("parse tenant rate limit", "err", err)
+		os.Exit(1)
+	}
+	tuning, err := parseHTTPTuning(
+		getenv("ORCHESTRATOR_TIMEOUT_S", strconv.Itoa(int(defaultHTTPTuning.orchestratorTimeout.Seconds()))),
+		getenv("POD_TIMEOUT_S", strconv.Itoa(int(defaultHTTPTuning.podTimeout.Seconds()))),
+		getenv("EXTERNAL_TIMEOUT_S", strconv.Itoa(int(defaultHTTPTuning.externalTimeout.Seconds()))),
+		getenv("HTTP_MAX_IDLE_CONNS_PER_HOST", strconv.Itoa(defaultHTTPTuning.maxIdleConnsPerHost)),
+		getenv("HTTP_IDLE_CONN_TIMEOUT_S", strconv.Itoa(int(defaultHTTPTuning.idleConnTimeout.Seconds()))),
+		getenv("HTTP2", strconv.FormatBool(defaultHTTPTuning.http2)))
+	if err != nil {
+		slog.Error("parse HTTP client tuning", "err", err)
+		os.Exit(1)
+	}
+	if len(peers) > 0 && relaySecret == "" {
+		slog.Error("RELAY_SECRET is required with REGION_PEERS")
+		os.Exit(1)
+	}
+	// Must match the orchestrator's ENVIRONMENTS so Redis prefixes line up
+	extraEnvs, err := environment.Parse(os.Getenv("ENVIRONMENTS"), environment.Environment{})
+	if err != nil {
+		slog.Error("parse ENVIRONMENTS", "err", err)
+		os.Exit(1)
+	}
 
 	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
 
-	rt := &Router{
-		rdb:              rdb,
-		orchestratorAddr: orchestratorAddr,
-		publicBaseURL:    publicBaseURL,
-		httpClient:       &http.Client{Timeout: 320 * time.Second}, // must exceed podReadyWait (5m) + LLM response time
+	orchestratorClient := &http.Client{Timeout: tuning.orchestratorTimeout}
+	failover := newOrchestratorFailover(tuning.transport(), orchestratorAddrs)
+	if discovery != nil {
+		go discovery.run(context.Background(), failover)
 	}
+	orchestratorClient.Transport = failover
+	if key := os.Getenv("ORCHESTRATOR_API_KEY"); key != "" {
+		// Outside the failover, which only rewrites hosts after the key is set
+		orchestratorClient.Transport = &orchestratorAuth{base: orchestratorClient.Transport, host: orchestratorAddrs[0].Host, key: key}
+	}
+	podClient := &http.Client{Timeout: tuning.podTimeout, Transport: tuning.transport()}
+	externalClient := &http.Client{Timeout: tuning.externalTimeout, Transport: tuning.transport()}
+	ips := &clientIPResolver{trusted: trustedProxies}
 
 	r := chi.NewRouter()
+	r.Use(ips.middleware) // before Logger so access logs show the real client
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -320,12 +959,48 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ok"))
 	})
+	metrics.RegisterRouter(prometheus.DefaultRegisterer)
+	r.Handle("/metrics", promhttp.Handler())
 
-	// Telegram webhook receiver — one URL per tenant
-	r.Post("/tg/{tenantID}", rt.webhookHandler)
+	// Each environment gets its own routes under its path prefix, talking to
+	// the orchestrator API for the same environment
+	for _, env := range append([]environment.Environment{{}}, extraEnvs...) {
+		rt := &Router{
+			rdb:                rdb,
+			orchestratorAddr:   orchestratorAddr + env.PathPrefix(),
+			publicBaseURL:      publicBaseURL + env.PathPrefix(),
+			orchestratorClient: orchestratorClient,
+			podClient:          podClient,
+			externalClient:     externalClient,
+			keyPrefix:          env.RedisPrefix,
+			resetCommands:      resetCommands,
+			sleepCommands:      sleepCommands,
+			resetPath:          resetPath,
+			region:             region,
+			peers:              peersFor(peers, env),
+			relaySecret:        relaySecret,
+			adminToken:         adminToken,
+			env:                env.Name,
 
-	// Admin: register webhook for a tenant
-	r.Post("/admin/webhook/{tenantID}", rt.registerWebhookHandler)
+			attachmentMaxBytes: attachmentMaxBytes,
+			statusSecret:       []byte(statusSecret),
+			chatSecret:         []byte(chatSecret),
+			streamEditInterval: time.Duration(streamEditMs) * time.Millisecond,
+			replyParseMode:     replyParseMode,
+			maxMessageAge:      time.Duration(maxMessageAgeS) * time.Second,
+			forwardRetry:       forwardRetry,
+			maxReplyBytes:      maxReplyBytes,
+			rateLimit:          rateLimit,
+		}
+		if queueConcurrency > 0 {
+			rt.queue = &updateQueue{rdb: rdb, keyPrefix: env.RedisPrefix, concurrency: queueConcurrency, handle: rt.handleTelegramUpdate}
+		}
+		if env.IsDefault() {
+			rt.routes(r, telegramAllowlist)
+		} else {
+			r.Route(env.PathPrefix(), func(r chi.Router) { rt.routes(r, telegramAllowlist) })
+		}
+	}
 
 	srv := &http.Server{Addr: ":" + port, Handler: r}
 
@@ -344,3 +1019,12 @@ func main() {
 	defer shutdownCancel()
 	srv.Shutdown(shutdownCtx)
 }
+
+// peersFor points each peer router at the same environment's routes
+func peersFor(peers map[string]string, env environment.Environment) map[string]string {
+	out := make(map[string]string, len(peers))
+	for region, base := range peers {
+		out[region] = base + env.PathPrefix()
+	}
+	return out
+}
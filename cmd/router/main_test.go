@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		chatID int64
+		text   string
+	}{
+		{"message", `{"message":{"chat":{"id":1},"text":"hi"}}`, 1, "hi"},
+		{"caption", `{"message":{"chat":{"id":1},"caption":"a photo"}}`, 1, "a photo"},
+		{"edited message", `{"edited_message":{"chat":{"id":2},"text":"hi, edited"}}`, 2, "hi, edited"},
+		{"channel post", `{"channel_post":{"chat":{"id":-100},"text":"news"}}`, -100, "news"},
+		{"callback query", `{"callback_query":{"data":"yes","message":{"chat":{"id":3},"text":"confirm?"}}}`, 3, "yes"},
+		{"business message", `{"business_message":{"business_connection_id":"bc1","chat":{"id":4},"text":"hello"}}`, 4, "hello"},
+		{"unsupported", `{"poll":{"id":"p"}}`, 0, ""},
+		{"invalid", `not json`, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.chatID, extractChatID([]byte(tt.body)))
+			assert.Equal(t, tt.text, extractMessageText([]byte(tt.body)))
+		})
+	}
+}
+
+func TestExtractReplyTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want replyTarget
+	}{
+		{"plain", `{"message":{"chat":{"id":1},"text":"hi"}}`, replyTarget{ChatID: 1}},
+		{"forum topic", `{"message":{"chat":{"id":-100},"message_thread_id":7,"is_topic_message":true,"text":"hi"}}`, replyTarget{ChatID: -100, ThreadID: 7}},
+		{"reply thread outside a forum", `{"message":{"chat":{"id":-100},"message_thread_id":7,"text":"hi"}}`, replyTarget{ChatID: -100}},
+		{"business", `{"edited_business_message":{"business_connection_id":"bc1","chat":{"id":4},"text":"hi"}}`, replyTarget{ChatID: 4, BusinessConnectionID: "bc1"}},
+		{"callback in topic", `{"callback_query":{"data":"y","message":{"chat":{"id":-100},"message_thread_id":9,"is_topic_message":true}}}`, replyTarget{ChatID: -100, ThreadID: 9}},
+		{"none", `{"poll":{"id":"p"}}`, replyTarget{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractReplyTarget([]byte(tt.body)))
+		})
+	}
+
+	assert.Equal(t, map[string]any{"chat_id": int64(1), "text": "hi"}, replyTarget{ChatID: 1}.sendMessage("hi"))
+	assert.Equal(t, map[string]any{"chat_id": int64(4), "text": "hi", "message_thread_id": int64(7), "business_connection_id": "bc1"},
+		replyTarget{ChatID: 4, ThreadID: 7, BusinessConnectionID: "bc1"}.sendMessage("hi"))
+}
+
+// TestWakePod_Async: the router polls the wake job until the pod is ready,
+// reporting the agent's state restore once
+func TestWakePod_Async(t *testing.T) {
+	polls := 0
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/wake/alice":
+			assert.Equal(t, "true", r.URL.Query().Get("async"))
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"job_id":"j1","status":"pending"}`)
+		case r.URL.Path == "/wake-jobs/j1":
+			polls++
+			if polls < 3 {
+				fmt.Fprint(w, `{"job_id":"j1","status":"restoring","restore_progress":40}`)
+				return
+			}
+			fmt.Fprint(w, `{"job_id":"j1","status":"ready","pod_ip":"10.0.0.9","idle_timeout_s":60}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	restoring := 0
+	podIP, ttl, err := rt.wakePod(context.Background(), "alice", func() { restoring++ })
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.9", podIP)
+	assert.Equal(t, time.Minute, ttl)
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, 1, restoring)
+}
+
+// TestUpdateActivity_ReportsVolume: the activity report carries the
+// message's text volume in characters, not bytes
+func TestUpdateActivity_ReportsVolume(t *testing.T) {
+	var got messageVolume
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/tenants/alice/activity", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	rt.updateActivity("alice", newMessageVolume("héllo", "👍"))
+	assert.Equal(t, messageVolume{CharsIn: 5, CharsOut: 1}, got)
+}
+
+// TestHandleTelegramUpdate_ReportsChat: a delivered update's activity
+// report names its chat, for the orchestrator's distinct chat counts
+func TestHandleTelegramUpdate_ReportsChat(t *testing.T) {
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"response":"Hi"}`)
+	}, &telegramRecorder{}, 0)
+	activity := make(chan messageVolume, 1)
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/wake/"):
+			fmt.Fprint(w, `{"pod_ip":"10.0.0.1"}`)
+		case r.URL.Path == "/tenants/alice/activity":
+			var vol messageVolume
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&vol))
+			activity <- vol
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+		}
+	}))
+	defer orch.Close()
+	rt.orchestratorAddr = orch.URL
+
+	rt.handleTelegramUpdate("alice", []byte(`{"message":{"chat":{"id":-100123},"text":"hello"}}`), dispatch{})
+	assert.Equal(t, messageVolume{CharsIn: 5, CharsOut: 2, ChatID: "-100123"}, <-activity)
+}
+
+// TestHandleTelegramUpdate_FailedForwardNotMetered: an update that is
+// dead-lettered reports no activity, so it isn't counted as a message
+func TestHandleTelegramUpdate_FailedForwardNotMetered(t *testing.T) {
+	rt := newStreamTestRouter(t, nil, &telegramRecorder{}, 0)
+	rt.podClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+	rt.forwardRetry = retryPolicy{attempts: 1, backoff: time.Millisecond}
+	var activity atomic.Int32
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/wake/"):
+			fmt.Fprint(w, `{"pod_ip":"10.0.0.1"}`)
+		case r.URL.Path == "/tenants/alice/activity":
+			activity.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+		}
+	}))
+	defer orch.Close()
+	rt.orchestratorAddr = orch.URL
+
+	rt.handleTelegramUpdate("alice", []byte(`{"message":{"chat":{"id":1},"text":"hello"}}`), dispatch{})
+	assert.Zero(t, activity.Load())
+}
+
+// TestWakePod_StorageFull: a 507 wake refusal tells the user storage is full
+func TestWakePod_StorageFull(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "state storage quota exceeded", http.StatusInsufficientStorage)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	_, _, err := rt.wakePod(context.Background(), "alice", nil)
+	require.ErrorIs(t, err, errStorageFull)
+	assert.Equal(t, i18n.StorageFull, wakeFailedMessage(err))
+	assert.Equal(t, i18n.StartFailed, wakeFailedMessage(errors.New("wake status 503")))
+}
+
+// TestWakePod_Suspended: a 423 wake refusal tells the user the bot is paused
+func TestWakePod_Suspended(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "tenant is suspended", http.StatusLocked)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	_, _, err := rt.wakePod(context.Background(), "alice", nil)
+	require.ErrorIs(t, err, errTenantSuspended)
+	assert.Equal(t, i18n.BotPaused, wakeFailedMessage(err))
+}
+
+// TestWakePod_RunQuota: a 429 wake refusal tells the user their plan's quota
+// is used up
+func TestWakePod_RunQuota(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "run quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	_, _, err := rt.wakePod(context.Background(), "alice", nil)
+	require.ErrorIs(t, err, errRunQuota)
+	assert.Equal(t, i18n.QuotaExceeded, wakeFailedMessage(err))
+}
+
+// TestWakePod_KillSwitch: a wake refused by a kill switch tells the user the
+// bot is unavailable
+func TestWakePod_KillSwitch(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(contract.KillSwitchHeader, "tenant")
+		http.Error(w, "tenant disabled", http.StatusServiceUnavailable)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	_, _, err := rt.wakePod(context.Background(), "alice", nil)
+	require.ErrorIs(t, err, errTenantDisabled)
+	assert.Equal(t, i18n.Unavailable, wakeFailedMessage(err))
+}
+
+// TestWakePod_Failed: a wake refused because the tenant is marked failed
+// tells the user the bot is unavailable
+func TestWakePod_Failed(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "tenant failed: wait pod ready: timeout", http.StatusConflict)
+	}))
+	defer orch.Close()
+	rt := &Router{orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+
+	_, _, err := rt.wakePod(context.Background(), "alice", nil)
+	require.ErrorIs(t, err, errTenantFailed)
+	assert.Equal(t, i18n.Unavailable, wakeFailedMessage(err))
+}
+
+func TestCheckWebhookSecret(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenants/alice/bot_token":
+			fmt.Fprint(w, `{"BotToken":"123:abc","WebhookSecret":"s3cret"}`)
+		case "/tenants/legacy/bot_token":
+			fmt.Fprint(w, `{"BotToken":"123:abc","WebhookSecret":""}`)
+		case "/tenants/broken/bot_token":
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer orch.Close()
+
+	// Unreachable Redis: every cache lookup misses and falls through
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	rt := &Router{rdb: rdb, orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		tenant, secret string
+		ok             bool
+	}{
+		{"alice", "s3cret", true},
+		{"alice", "guess", false},
+		{"alice", "", false},
+		{"legacy", "", true},
+		{"unknown", "", true},
+	} {
+		ok, err := rt.checkWebhookSecret(ctx, tt.tenant, tt.secret)
+		require.NoError(t, err)
+		assert.Equal(t, tt.ok, ok, "%s/%q", tt.tenant, tt.secret)
+	}
+	_, err := rt.checkWebhookSecret(ctx, "broken", "")
+	assert.Error(t, err)
+
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+	req := httptest.NewRequest(http.MethodPost, "/tg/alice", strings.NewReader(`{"update_id":1}`))
+	req.Header.Set(telegram.SecretTokenHeader, "guess")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestMsg: system messages follow the tenant's locale, falling back to
+// English when it has none or the orchestrator is unreachable
+func TestMsg(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenants/alice":
+			fmt.Fprint(w, `{"TenantID":"alice","Locale":"de"}`)
+		case "/tenants/bob":
+			fmt.Fprint(w, `{"TenantID":"bob"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer orch.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	rt := &Router{rdb: rdb, orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+	ctx := context.Background()
+
+	assert.Equal(t, i18n.T("de", i18n.SleepDone), rt.msg(ctx, "alice", i18n.SleepDone))
+	assert.Equal(t, i18n.T("en", i18n.SleepDone), rt.msg(ctx, "bob", i18n.SleepDone))
+	assert.Equal(t, i18n.T("en", i18n.SleepDone), rt.msg(ctx, "unknown", i18n.SleepDone))
+	assert.NotEqual(t, i18n.T("de", i18n.SleepDone), i18n.T("en", i18n.SleepDone))
+}
+
+// TestRegisterWebhook_UsesBotAPIBase: webhook registration goes to the
+// configured Bot API server like every other call
+func TestRegisterWebhook_UsesBotAPIBase(t *testing.T) {
+	var got map[string]any
+	rt := newStreamTestRouter(t, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bot123:abc/setWebhook", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}), 0)
+	rt.publicBaseURL = "https://router.example.com"
+
+	require.NoError(t, rt.RegisterWebhook(context.Background(), "123:abc", "alice", nil))
+	assert.Equal(t, "https://router.example.com/tg/alice", got["url"])
+	assert.NotEmpty(t, got["secret_token"])
+}
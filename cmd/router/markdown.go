@@ -0,0 +1,271 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Telegram parse modes agent replies may be rendered in (REPLY_PARSE_MODE)
+const (
+	parseModeMarkdownV2 = "MarkdownV2"
+	parseModeHTML       = "HTML"
+)
+
+func validParseMode(mode string) bool {
+	switch mode {
+	case "", parseModeMarkdownV2, parseModeHTML:
+		return true
+	}
+	return false
+}
+
+// formatReply renders an agent's reply, written in common Markdown, for
+// Telegram's parse mode. Agents don't know Telegram's dialects: MarkdownV2
+// wants nearly every punctuation mark escaped, and HTML allows a handful of
+// tags. So the reply is parsed, not passed through: bold, italic,
+// strikethrough, inline code, code blocks, links and headings become
+// Telegram entities, and everything else is escaped to show as written,
+// including any HTML the agent wrote. Links other than http(s), tg and
+// mailto show as text.
+func formatReply(md, mode string) string {
+	f := replyFormatter{html: mode == parseModeHTML}
+	var b strings.Builder
+	lines := strings.Split(md, "\n")
+	for i := 0; i < len(lines); i++ {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "```") {
+			lang, code := line[3:], []string{}
+			if j := strings.Index(lang, "```"); j >= 0 { // ```one line```
+				b.WriteString(f.pre("", lang[:j]))
+				continue
+			}
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "```"; i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString(f.pre(strings.TrimSpace(lang), strings.Join(code, "\n")))
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(lines[i]); m != nil {
+			b.WriteString(f.bold(f.inline(m[1])))
+			continue
+		}
+		b.WriteString(f.inline(lines[i]))
+	}
+	return b.String()
+}
+
+// headingRe matches a Markdown heading; Telegram has none, so it shows bold
+var headingRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+
+// replyFormatter writes Telegram entities as MarkdownV2, or HTML
+type replyFormatter struct {
+	html bool
+}
+
+// inline renders one line's spans. Markers without a closing match show as
+// written, so "2 * 3" or snake_case stay as they are.
+func (f replyFormatter) inline(s string) string {
+	var b strings.Builder
+	plain := 0 // start of the text not yet written
+	flush := func(end int) {
+		b.WriteString(f.text(s[plain:end]))
+	}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]):
+			flush(i)
+			b.WriteString(f.text(s[i+1 : i+2]))
+			i += 2
+			plain = i
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				flush(i)
+				b.WriteString(f.code(s[i+1 : i+1+end]))
+				i += end + 2
+				plain = i
+				continue
+			}
+		case c == '[':
+			if label, link, n, ok := markdownLink(s[i:]); ok {
+				flush(i)
+				b.WriteString(f.link(f.inline(label), link))
+				i += n
+				plain = i
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if inner, marker, ok := emphasis(s, i); ok {
+				flush(i)
+				switch {
+				case marker == "~~":
+					b.WriteString(f.strike(f.inline(inner)))
+				case len(marker) == 2:
+					b.WriteString(f.bold(f.inline(inner)))
+				default:
+					b.WriteString(f.italic(f.inline(inner)))
+				}
+				i += len(inner) + 2*len(marker)
+				plain = i
+				continue
+			}
+		}
+		i++
+	}
+	flush(len(s))
+	return b.String()
+}
+
+// emphasis matches the span opening at s[i]: **bold**, __bold__, *italic*,
+// _italic_ or ~~strikethrough~~. Like Markdown, the text may not start or
+// end with a space, and _ only counts between words, not inside one.
+func emphasis(s string, i int) (inner, marker string, ok bool) {
+	c := s[i]
+	marker = string(c)
+	if i+1 < len(s) && s[i+1] == c {
+		marker += string(c)
+	} else if c == '~' {
+		return "", "", false
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", "", false
+	}
+	rest := s[i+len(marker):]
+	for from := 0; from < len(rest); {
+		end := strings.Index(rest[from:], marker)
+		if end < 0 {
+			return "", "", false
+		}
+		end += from
+		after := end + len(marker)
+		if end > 0 && rest[0] != ' ' && rest[end-1] != ' ' && rest[end-1] != c &&
+			(after == len(rest) || rest[after] != c) &&
+			(c != '_' || after == len(rest) || !isWordByte(rest[after])) {
+			return rest[:end], marker, true
+		}
+		from = end + 1
+	}
+	return "", "", false
+}
+
+// markdownLink matches the [label](url) s starts with, n bytes long
+func markdownLink(s string) (label, link string, n int, ok bool) {
+	mid := strings.Index(s, "](")
+	if mid < 1 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[mid+2:], ')')
+	if end < 1 {
+		return "", "", 0, false
+	}
+	link = strings.TrimSpace(s[mid+2 : mid+2+end])
+	if strings.ContainsAny(link, " \t") {
+		return "", "", 0, false
+	}
+	return s[1:mid], link, mid + 3 + end, true
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// isMarkdownPunct reports whether a backslash before c escapes it
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!~|<>=", c) >= 0
+}
+
+// safeLink reports whether a link may be sent as one: a scheme Telegram
+// opens that can't run anything
+func safeLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "tg", "mailto":
+		return true
+	}
+	return false
+}
+
+var (
+	// markdownV2Escaper escapes the characters MarkdownV2 reserves in text
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`)
+	// markdownV2CodeEscaper escapes the text of code entities
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	// markdownV2LinkEscaper escapes a link's URL
+	markdownV2LinkEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+	htmlEscaper           = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// codeLangRe matches a code block language Telegram can take
+var codeLangRe = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,32}$`)
+
+func (f replyFormatter) text(s string) string {
+	if f.html {
+		return htmlEscaper.Replace(s)
+	}
+	return markdownV2Escaper.Replace(s)
+}
+
+func (f replyFormatter) bold(inner string) string {
+	if f.html {
+		return "<b>" + inner + "</b>"
+	}
+	return "*" + inner + "*"
+}
+
+func (f replyFormatter) italic(inner string) string {
+	if f.html {
+		return "<i>" + inner + "</i>"
+	}
+	// \r ends the entity, so ___ can't be read as underline
+	return "_" + inner + "_\r"
+}
+
+func (f replyFormatter) strike(inner string) string {
+	if f.html {
+		return "<s>" + inner + "</s>"
+	}
+	return "~" + inner + "~"
+}
+
+func (f replyFormatter) code(s string) string {
+	if f.html {
+		return "<code>" + htmlEscaper.Replace(s) + "</code>"
+	}
+	return "`" + markdownV2CodeEscaper.Replace(s) + "`"
+}
+
+func (f replyFormatter) pre(lang, s string) string {
+	if !codeLangRe.MatchString(lang) {
+		lang = ""
+	}
+	if f.html {
+		if lang == "" {
+			return "<pre>" + htmlEscaper.Replace(s) + "</pre>"
+		}
+		return `<pre><code class="language-` + lang + `">` + htmlEscaper.Replace(s) + "</code></pre>"
+	}
+	return "```" + lang + "\n" + markdownV2CodeEscaper.Replace(s) + "\n```"
+}
+
+// link renders label as a link to target, or as text if the link isn't safe
+func (f replyFormatter) link(label, target string) string {
+	if !safeLink(target) {
+		return label + f.text(" ("+target+")")
+	}
+	if f.html {
+		return `<a href="` + htmlEscaper.Replace(target) + `">` + label + "</a>"
+	}
+	return "[" + label + "](" + markdownV2LinkEscaper.Replace(target) + ")"
+}
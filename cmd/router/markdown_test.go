@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatReply_MarkdownV2(t *testing.T) {
+	for md, want := range map[string]string{
+		"Hello, world!":                      `Hello, world\!`,
+		"**bold** and *italic* 1.5":          "*bold* and _italic_\r 1\\.5",
+		"~~gone~~ (see `a_b()`)":             "~gone~ \\(see `a_b()`\\)",
+		"snake_case_name and 2 * 3 = 6":      `snake\_case\_name and 2 \* 3 \= 6`,
+		"*a **b** c*":                        "_a *b* c_\r",
+		"# Title #":                          "*Title*",
+		`\*not italic\*`:                     `\*not italic\*`,
+		"[docs](https://x.io/a_(b))":         `[docs](https://x.io/a_(b)\)`,
+		"[run](javascript:alert(1))":         "run \\(javascript:alert\\(1\\)\\)",
+		"```go\nfmt.Println(\"`\\\\\")\n```": "```go\nfmt.Println(\"\\`\\\\\\\\\")\n```",
+		"```\nunclosed":                      "```\nunclosed\n```",
+	} {
+		assert.Equal(t, want, formatReply(md, parseModeMarkdownV2), md)
+	}
+}
+
+func TestFormatReply_HTML(t *testing.T) {
+	for md, want := range map[string]string{
+		"a < b && c > d":                   "a &lt; b &amp;&amp; c &gt; d",
+		"<script>alert(1)</script> **hi**": "&lt;script&gt;alert(1)&lt;/script&gt; <b>hi</b>",
+		"_it_ `<br>`":                      "<i>it</i> <code>&lt;br&gt;</code>",
+		`[x](https://e.com/?a=1&b="2")`:    `<a href="https://e.com/?a=1&amp;b=&quot;2&quot;">x</a>`,
+		"[x](data:text/html,hi)":           "x (data:text/html,hi)",
+		"```python\nif a<b:\n  pass\n```":  "<pre><code class=\"language-python\">if a&lt;b:\n  pass</code></pre>",
+		"```\"><i>\nx\n```":                "<pre>x</pre>",
+	} {
+		assert.Equal(t, want, formatReply(md, parseModeHTML), md)
+	}
+}
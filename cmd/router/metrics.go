@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+)
+
+// Router metrics, served on /metrics. The collectors live in the metrics
+// package alongside the dashboards and alerts that query them.
+
+func (rt *Router) countRequest(tenantID, source string, status int) {
+	metrics.RouterRequests.WithLabelValues(rt.env, tenantID, source, strconv.Itoa(status)).Inc()
+}
+
+func (rt *Router) observeForward(tenantID string, start time.Time, err error) {
+	metrics.RouterForwardDuration.WithLabelValues(rt.env, tenantID, result(err)).Observe(time.Since(start).Seconds())
+}
+
+func (rt *Router) countWake(tenantID string, err error) {
+	metrics.RouterWakes.WithLabelValues(rt.env, tenantID, result(err)).Inc()
+}
+
+func (rt *Router) countCacheLookup(cache string, hit bool) {
+	r := "miss"
+	if hit {
+		r = "hit"
+	}
+	metrics.RouterCacheLookups.WithLabelValues(rt.env, cache, r).Inc()
+	rt.stats.lookup(cache, hit)
+}
+
+func (rt *Router) countWakeFoundRunning() {
+	metrics.RouterWakesRunning.WithLabelValues(rt.env).Inc()
+	rt.stats.wakeFoundRunning()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// instrument counts a tenant route's requests by response status
+func (rt *Router) instrument(source string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			rt.countRequest(chi.URLParam(r, "tenantID"), source, status)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	// Unreachable Redis and orchestrator: secret lookups fail with 503 and
+	// every cache lookup misses
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	rt := &Router{rdb: rdb, orchestratorAddr: "http://127.0.0.1:1", orchestratorClient: http.DefaultClient, env: "metrics-test"}
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+	reg := prometheus.NewRegistry()
+	metrics.RegisterRouter(reg)
+	r.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/tg/alice", strings.NewReader(`{"update_id":1}`))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RouterRequests.WithLabelValues("metrics-test", "alice", "telegram", "503")))
+
+	rt.getBotToken(req.Context(), "alice")
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RouterCacheLookups.WithLabelValues("metrics-test", "bot_token", "miss")))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `router_requests_total{env="metrics-test",source="telegram",status="503",tenant="alice"} 1`)
+}
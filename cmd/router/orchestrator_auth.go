@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+)
+
+// orchestratorAuth adds the router's API key to requests for the
+// orchestrator. The router's HTTP client also talks to Telegram, Slack, pods
+// and peer routers, so the key is only attached for the orchestrator's host.
+type orchestratorAuth struct {
+	base http.RoundTripper
+	host string
+	key  string
+}
+
+func (t *orchestratorAuth) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.key)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// discoveryInterval is how often orchestrator replicas are looked up again.
+// CoreDNS answers for headless Services with a 5s TTL.
+const discoveryInterval = 10 * time.Second
+
+// Orchestrator discovery modes (ORCHESTRATOR_DISCOVERY)
+const (
+	discoveryDNS = "dns" // A/AAAA records of a headless Service, one per ready replica
+	discoverySRV = "srv" // SRV records, e.g. _http._tcp.<headless service>
+)
+
+// resolver is the part of net.Resolver discovery uses
+type resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// orchestratorDiscovery finds the orchestrator's replicas in DNS, so the
+// router can spread its calls over them itself. Behind a ClusterIP Service,
+// kube-proxy picks a replica per connection, and the router's keep-alive
+// connections pin most of its traffic to whichever replicas it reached
+// first.
+type orchestratorDiscovery struct {
+	mode     string
+	scheme   string
+	name     string // DNS name to look up: the first ORCHESTRATOR_ADDR's host
+	port     string // with discoveryDNS, the port every replica listens on
+	resolver resolver
+}
+
+func newOrchestratorDiscovery(mode string, addr *url.URL) (*orchestratorDiscovery, error) {
+	d := &orchestratorDiscovery{mode: mode, scheme: addr.Scheme, name: addr.Hostname(), resolver: net.DefaultResolver}
+	switch mode {
+	case discoveryDNS:
+		d.port = addr.Port()
+		if d.port == "" {
+			d.port = "80"
+			if addr.Scheme == "https" {
+				d.port = "443"
+			}
+		}
+	case discoverySRV:
+		if addr.Port() != "" {
+			return nil, fmt.Errorf("%s: SRV records carry the port; leave it out", addr.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unknown discovery mode %q, want %s or %s", mode, discoveryDNS, discoverySRV)
+	}
+	return d, nil
+}
+
+// lookup returns the replicas' host:port addresses, sorted
+func (d *orchestratorDiscovery) lookup(ctx context.Context) ([]string, error) {
+	var hosts []string
+	if d.mode == discoverySRV {
+		_, srvs, err := d.resolver.LookupSRV(ctx, "", "", d.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			target := strings.TrimSuffix(srv.Target, ".")
+			hosts = append(hosts, net.JoinHostPort(target, strconv.Itoa(int(srv.Port))))
+		}
+	} else {
+		ips, err := d.resolver.LookupIPAddr(ctx, d.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			hosts = append(hosts, net.JoinHostPort(ip.IP.String(), d.port))
+		}
+	}
+	slices.Sort(hosts)
+	return slices.Compact(hosts), nil
+}
+
+// run keeps f's replicas up to date until ctx is done. A failed lookup
+// keeps the replicas last found; finding none hands calls back to the
+// ORCHESTRATOR_ADDR list.
+func (d *orchestratorDiscovery) run(ctx context.Context, f *orchestratorFailover) {
+	for {
+		lookupCtx, cancel := context.WithTimeout(ctx, discoveryInterval)
+		hosts, err := d.lookup(lookupCtx)
+		cancel()
+		if err != nil {
+			slog.Warn("orchestrator discovery failed, keeping last replicas", "name", d.name, "err", err)
+		} else if f.setReplicas(d.scheme, hosts) {
+			slog.Info("orchestrator replicas changed", "name", d.name, "replicas", hosts)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(discoveryInterval):
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver answers lookups from fixed records
+type fakeResolver struct {
+	ips  []string
+	srvs []*net.SRV
+}
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	var addrs []net.IPAddr
+	for _, ip := range r.ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	return name, r.srvs, nil
+}
+
+func TestOrchestratorDiscovery_Lookup(t *testing.T) {
+	u, _ := url.Parse("http://orchestrator-headless.tenants.svc.cluster.local:8080")
+	d, err := newOrchestratorDiscovery(discoveryDNS, u)
+	require.NoError(t, err)
+	d.resolver = fakeResolver{ips: []string{"10.0.1.9", "10.0.1.7", "10.0.1.9"}}
+	hosts, err := d.lookup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.7:8080", "10.0.1.9:8080"}, hosts)
+
+	u, _ = url.Parse("http://_http._tcp.orchestrator-headless.tenants.svc.cluster.local")
+	d, err = newOrchestratorDiscovery(discoverySRV, u)
+	require.NoError(t, err)
+	d.resolver = fakeResolver{srvs: []*net.SRV{{Target: "10-0-1-7.orchestrator-headless.tenants.svc.cluster.local.", Port: 8080}}}
+	hosts, err = d.lookup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10-0-1-7.orchestrator-headless.tenants.svc.cluster.local:8080"}, hosts)
+
+	_, err = newOrchestratorDiscovery(discoverySRV, &url.URL{Scheme: "http", Host: "_http._tcp.orchestrator:8080"})
+	assert.Error(t, err, "SRV records carry the port")
+	_, err = newOrchestratorDiscovery("consul", u)
+	assert.Error(t, err)
+}
+
+// TestOrchestratorFailover_RoundRobin: calls take turns over the discovered
+// replicas, a failing one is retried on the next, and with none found calls
+// go to the configured address again
+func TestOrchestratorFailover_RoundRobin(t *testing.T) {
+	hits := map[string]int{}
+	replica := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits[name]++ }))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	a, b, service := replica("a"), replica("b"), replica("service")
+	addrs, err := parseOrchestratorAddrs(service.URL)
+	require.NoError(t, err)
+	f := newOrchestratorFailover(http.DefaultTransport, addrs)
+	client := &http.Client{Transport: f}
+	call := func() {
+		resp, err := client.Get(service.URL + "/tenants/alice/bot_token")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	host := func(srv *httptest.Server) string { return strings.TrimPrefix(srv.URL, "http://") }
+	assert.True(t, f.setReplicas("http", []string{host(a), host(b)}))
+	assert.False(t, f.setReplicas("http", []string{host(a), host(b)}), "unchanged")
+	for range 4 {
+		call()
+	}
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, hits)
+
+	dead := strings.TrimPrefix(deadAddr(t), "http://")
+	f.setReplicas("http", []string{dead, host(b)})
+	for range 2 {
+		call()
+	}
+	assert.Equal(t, 4, hits["b"], "the dead replica's calls are retried on the next")
+
+	f.setReplicas("http", nil)
+	call()
+	assert.Equal(t, 1, hits["service"])
+}
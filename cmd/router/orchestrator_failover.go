@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	breakerThreshold = 3                      // consecutive failures that take an orchestrator address out of rotation
+	breakerCooldown  = 30 * time.Second       // how long it stays out before being tried again
+	retryJitter      = 250 * time.Millisecond // upper bound of the random pause between attempts
+)
+
+// orchestratorFailover retries the router's orchestrator calls on other
+// addresses when one can't be reached, e.g. a replica shutting down during an
+// upgrade before the Service drops it. Requests are written against the first
+// address (Router.orchestratorAddr); others are substituted on failure, after
+// a short random pause so a burst of updates doesn't retry in lockstep.
+//
+// Only transport errors are retried: an HTTP error status means the
+// orchestrator handled the request (a wake can take minutes to fail) and
+// retrying it elsewhere would just repeat that. An address failing
+// breakerThreshold times in a row is skipped for breakerCooldown.
+//
+// With discovery (see orchestratorDiscovery), the replicas found take the
+// first address's place and calls go round robin over them, each retried on
+// the next replica and then the remaining addresses.
+type orchestratorFailover struct {
+	base      http.RoundTripper
+	endpoints []*orchestratorEndpoint
+
+	mu       sync.Mutex
+	replicas []*orchestratorEndpoint // discovered; nil without discovery or while none are found
+	next     int                     // replica the next call starts at
+}
+
+type orchestratorEndpoint struct {
+	scheme, host string
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newOrchestratorFailover(base http.RoundTripper, addrs []*url.URL) *orchestratorFailover {
+	f := &orchestratorFailover{base: base}
+	for _, u := range addrs {
+		f.endpoints = append(f.endpoints, &orchestratorEndpoint{scheme: u.Scheme, host: u.Host})
+	}
+	return f
+}
+
+// parseOrchestratorAddrs parses ORCHESTRATOR_ADDR: one or more comma-separated
+// base URLs, the first of which is tried first
+func parseOrchestratorAddrs(s string) ([]*url.URL, error) {
+	var addrs []*url.URL
+	for _, a := range strings.Split(s, ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		u, err := url.Parse(a)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("%q: want scheme://host[:port]", a)
+		}
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		addrs = append(addrs, u)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no address")
+	}
+	return addrs, nil
+}
+
+// setReplicas replaces the discovered replicas with hosts, keeping the
+// breaker state of those still there, and reports whether they changed
+func (f *orchestratorFailover) setReplicas(scheme string, hosts []string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changed := len(hosts) != len(f.replicas)
+	replicas := make([]*orchestratorEndpoint, 0, len(hosts))
+	for i, host := range hosts {
+		ep := &orchestratorEndpoint{scheme: scheme, host: host}
+		for _, old := range f.replicas {
+			if old.host == host {
+				ep = old
+			}
+		}
+		if !changed && f.replicas[i].host != host {
+			changed = true
+		}
+		replicas = append(replicas, ep)
+	}
+	if len(replicas) == 0 {
+		replicas = nil
+	}
+	f.replicas = replicas
+	return changed
+}
+
+// order returns the endpoints to try a call on, in order: the discovered
+// replicas starting at the next in turn, or else the first address, then
+// the other addresses
+func (f *orchestratorFailover) order() []*orchestratorEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.replicas) == 0 {
+		return f.endpoints
+	}
+	start := f.next % len(f.replicas)
+	f.next = start + 1
+	order := append([]*orchestratorEndpoint{}, f.replicas[start:]...)
+	order = append(order, f.replicas[:start]...)
+	return append(order, f.endpoints[1:]...)
+}
+
+func (f *orchestratorFailover) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != f.endpoints[0].host {
+		return f.base.RoundTrip(req)
+	}
+	now := time.Now()
+	order := f.order()
+	var candidates []*orchestratorEndpoint
+	for _, ep := range order {
+		if ep.available(now) {
+			candidates = append(candidates, ep)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = order // all tripped: failing outright helps no one
+	}
+	if len(candidates) == 1 {
+		// A single address is usually a Service: a retry reaches another replica
+		candidates = append(candidates, candidates[0])
+	}
+
+	var lastErr error
+	for i, ep := range candidates {
+		if i > 0 {
+			if req.Body != nil && req.GetBody == nil {
+				break // body already consumed and can't be replayed
+			}
+			select {
+			case <-req.Context().Done():
+				return nil, lastErr
+			case <-time.After(rand.N(retryJitter)):
+			}
+		}
+		r := req.Clone(req.Context())
+		r.URL.Scheme, r.URL.Host, r.Host = ep.scheme, ep.host, ep.host
+		if i > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := f.base.RoundTrip(r)
+		if err == nil {
+			ep.succeeded()
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			break // our own deadline or cancellation, not the orchestrator's fault
+		}
+		if ep.failed(time.Now()) {
+			slog.Warn("orchestrator address failing, skipping it", "addr", ep.scheme+"://"+ep.host, "for", breakerCooldown, "err", err)
+		}
+		if i < len(candidates)-1 {
+			slog.Warn("orchestrator call failed, retrying", "addr", ep.scheme+"://"+ep.host, "path", req.URL.Path, "err", err)
+		}
+	}
+	return nil, lastErr
+}
+
+func (ep *orchestratorEndpoint) available(now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return !now.Before(ep.openUntil)
+}
+
+func (ep *orchestratorEndpoint) succeeded() {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.failures = 0
+	ep.openUntil = time.Time{}
+}
+
+// failed counts a failure and reports whether it took the address out of
+// rotation
+func (ep *orchestratorEndpoint) failed(now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.failures++
+	if ep.failures < breakerThreshold {
+		return false
+	}
+	ep.failures = 0
+	ep.openUntil = now.Add(breakerCooldown)
+	return true
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadAddr is an address nothing listens on
+func deadAddr(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestParseOrchestratorAddrs(t *testing.T) {
+	addrs, err := parseOrchestratorAddrs(" http://orchestrator:8080/ , http://10.0.0.7:8080")
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	assert.Equal(t, "http://orchestrator:8080", addrs[0].String())
+	assert.Equal(t, "10.0.0.7:8080", addrs[1].Host)
+
+	for _, bad := range []string{"", " , ", "orchestrator:8080", "http://"} {
+		_, err := parseOrchestratorAddrs(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestOrchestratorFailover_RetriesOtherAddress(t *testing.T) {
+	var gotBody string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer healthy.Close()
+
+	dead := deadAddr(t)
+	addrs, err := parseOrchestratorAddrs(dead + "," + healthy.URL)
+	require.NoError(t, err)
+	client := &http.Client{Transport: newOrchestratorFailover(http.DefaultTransport, addrs)}
+
+	req, _ := http.NewRequest(http.MethodPut, dead+"/tenants/alice/webhook_secret", bytes.NewReader([]byte(`{"secret":"s"}`)))
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode, "an HTTP status is returned as is, not retried")
+	assert.Equal(t, `{"secret":"s"}`, gotBody, "the body is replayed")
+}
+
+func TestOrchestratorFailover_SkipsFailingAddress(t *testing.T) {
+	hits := 0
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer healthy.Close()
+
+	dead := deadAddr(t)
+	addrs, _ := parseOrchestratorAddrs(dead + "," + healthy.URL)
+	f := newOrchestratorFailover(http.DefaultTransport, addrs)
+	client := &http.Client{Transport: f}
+
+	for i := 0; i < breakerThreshold; i++ {
+		resp, err := client.Get(dead + "/tenants/alice")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.False(t, f.endpoints[0].available(time.Now()), "the dead address is out of rotation")
+	assert.Equal(t, breakerThreshold, hits)
+}
+
+func TestOrchestratorFailover_OtherHostsPassThrough(t *testing.T) {
+	dead := deadAddr(t)
+	addrs, _ := parseOrchestratorAddrs(dead)
+	f := newOrchestratorFailover(http.DefaultTransport, addrs)
+
+	pod := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer pod.Close()
+	resp, err := (&http.Client{Transport: f}).Get(pod.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = (&http.Client{Transport: f}).Get(dead + "/healthz")
+	assert.Error(t, err, "a single unreachable address fails after its retry")
+}
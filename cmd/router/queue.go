@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+)
+
+const (
+	queuePrefix       = contract.QueuePrefix
+	queueWorkerPrefix = "router:qworker:"
+	queueTTL          = 24 * time.Hour // an abandoned queue expires
+	// queueLease must outlast one delivery (bounded by handleTelegramUpdate's
+	// timeout); it is renewed before each one
+	queueLease = podReadyWait + time.Minute
+)
+
+// updateQueue delivers a tenant's updates in arrival order. Updates are
+// pushed onto a Redis list per tenant and drained by at most concurrency
+// workers, each holding a lease key, so bursts don't race each other into
+// parallel wakes even across router replicas. With concurrency 1 a tenant's
+// updates reach the pod strictly one at a time.
+//
+// If a router dies mid-drain its lease expires and the next update for the
+// tenant picks up whatever it left in the queue.
+type updateQueue struct {
+	rdb         *redis.Client
+	keyPrefix   string // environment Redis prefix, as Router.keyPrefix
+	concurrency int
+	handle      func(tenantID string, body []byte, d dispatch)
+}
+
+type queuedUpdate struct {
+	Update  json.RawMessage `json:"update"`
+	Relayed bool            `json:"relayed,omitempty"`
+	Replay  bool            `json:"replay,omitempty"`
+}
+
+func (q *updateQueue) listKey(tenantID string) string {
+	return q.keyPrefix + queuePrefix + tenantID
+}
+
+func (q *updateQueue) leaseKey(tenantID string, slot int) string {
+	return q.keyPrefix + queueWorkerPrefix + tenantID + ":" + strconv.Itoa(slot)
+}
+
+// push queues an update and makes sure a worker will deliver it. If Redis is
+// unavailable the update is delivered directly, unordered, rather than lost.
+func (q *updateQueue) push(tenantID string, body []byte, d dispatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	item, err := json.Marshal(queuedUpdate{Update: body, Relayed: d.relayed, Replay: d.replay})
+	if err == nil {
+		pipe := q.rdb.TxPipeline()
+		pipe.RPush(ctx, q.listKey(tenantID), item)
+		pipe.Expire(ctx, q.listKey(tenantID), queueTTL)
+		_, err = pipe.Exec(ctx)
+	}
+	if err != nil {
+		slog.Warn("queue update failed, delivering directly", "tenant", tenantID, "err", err)
+		go q.handle(tenantID, body, d)
+		return
+	}
+	go q.drain(tenantID)
+}
+
+// drain takes a free worker slot and delivers queued updates until the queue
+// is empty. If every slot is taken, a running worker delivers this update.
+func (q *updateQueue) drain(tenantID string) {
+	ctx := context.Background()
+	for slot := 0; slot < q.concurrency; slot++ {
+		lease := q.leaseKey(tenantID, slot)
+		ok, err := q.rdb.SetNX(ctx, lease, "1", queueLease).Result()
+		if err != nil {
+			slog.Warn("queue lease failed", "tenant", tenantID, "err", err)
+			return
+		}
+		if ok {
+			q.work(ctx, tenantID, lease)
+			return
+		}
+	}
+}
+
+func (q *updateQueue) work(ctx context.Context, tenantID, lease string) {
+	for {
+		item, err := q.rdb.LPop(ctx, q.listKey(tenantID)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			q.rdb.Del(ctx, lease)
+			// An update pushed between the LPOP and the DEL saw this slot
+			// taken; take the slot back if nobody else has
+			if n, err := q.rdb.LLen(ctx, q.listKey(tenantID)).Result(); err != nil || n == 0 {
+				return
+			}
+			if ok, err := q.rdb.SetNX(ctx, lease, "1", queueLease).Result(); err != nil || !ok {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			slog.Warn("queue pop failed", "tenant", tenantID, "err", err)
+			q.rdb.Del(ctx, lease)
+			return
+		}
+
+		var u queuedUpdate
+		if err := json.Unmarshal(item, &u); err != nil {
+			slog.Warn("dropping malformed queued update", "tenant", tenantID, "err", err)
+			continue
+		}
+		q.rdb.Expire(ctx, lease, queueLease)
+		q.handle(tenantID, u.Update, dispatch{relayed: u.Relayed, replay: u.Replay})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateQueue_RedisDown: without Redis updates are still delivered,
+// just not ordered
+func TestUpdateQueue_RedisDown(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+
+	type delivery struct {
+		tenantID string
+		body     string
+		d        dispatch
+	}
+	got := make(chan delivery, 1)
+	q := &updateQueue{rdb: rdb, concurrency: 1, handle: func(tenantID string, body []byte, d dispatch) {
+		got <- delivery{tenantID, string(body), d}
+	}}
+
+	q.push("alice", []byte(`{"update_id":1}`), dispatch{relayed: true})
+	select {
+	case d := <-got:
+		assert.Equal(t, delivery{"alice", `{"update_id":1}`, dispatch{relayed: true}}, d)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "update not delivered")
+	}
+}
+
+func TestUpdateQueue_Keys(t *testing.T) {
+	q := &updateQueue{keyPrefix: "staging:"}
+	assert.Equal(t, "staging:router:queue:alice", q.listKey("alice"))
+	assert.Equal(t, "staging:router:qworker:alice:12", q.leaseKey("alice", 12))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+)
+
+const (
+	// rateBucketPrefix holds each tenant's token bucket, shared by every
+	// router replica
+	rateBucketPrefix = "router:ratelimit:"
+	// rateNoticePrefix marks a chat already told to slow down, so a burst
+	// of dropped updates gets one notice, not one each
+	rateNoticePrefix = "router:ratenotice:"
+	rateNoticeWindow = time.Minute
+)
+
+// rateLimit is the per-tenant rate of inbound updates (TENANT_RATE_PER_MIN,
+// TENANT_RATE_BURST). The zero value doesn't limit.
+type rateLimit struct {
+	perMinute int // tokens added per minute; 0 = no limit
+	burst     int // bucket size: updates accepted at once after a quiet spell
+}
+
+// parseRateLimit reads TENANT_RATE_PER_MIN and TENANT_RATE_BURST
+func parseRateLimit(perMinute, burst string) (rateLimit, error) {
+	var n [2]int
+	for i, v := range []struct{ name, value string }{
+		{"TENANT_RATE_PER_MIN", perMinute},
+		{"TENANT_RATE_BURST", burst},
+	} {
+		var err error
+		if n[i], err = strconv.Atoi(v.value); err != nil || n[i] < 0 {
+			return rateLimit{}, fmt.Errorf("%s must be a non-negative integer, got %q", v.name, v.value)
+		}
+	}
+	if n[0] > 0 && n[1] == 0 {
+		return rateLimit{}, fmt.Errorf("TENANT_RATE_BURST must be at least 1 with TENANT_RATE_PER_MIN set")
+	}
+	return rateLimit{perMinute: n[0], burst: n[1]}, nil
+}
+
+// takeTokenScript takes a token from the bucket in KEYS[1], refilled at
+// ARGV[1] tokens per second up to ARGV[2], and returns 1 if there was one.
+// Redis' clock is used so router replicas agree on the refill. An idle
+// bucket expires once it would be full again.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+local b = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("EXPIRE", KEYS[1], math.ceil(burst / rate) + 1)
+return allowed
+`)
+
+// allowUpdate takes a token from the tenant's bucket and reports whether
+// the update may go on. If Redis can't be reached the update is let through:
+// a flood is better than dropping every tenant's messages.
+func (rt *Router) allowUpdate(ctx context.Context, tenantID string) bool {
+	if rt.rateLimit.perMinute <= 0 {
+		return true
+	}
+	perSecond := float64(rt.rateLimit.perMinute) / 60
+	allowed, err := takeTokenScript.Run(ctx, rt.rdb, []string{rt.key(rateBucketPrefix, tenantID)},
+		perSecond, rt.rateLimit.burst).Int()
+	if err != nil {
+		slog.Warn("rate limit check failed, allowing update", "tenant", tenantID, "err", err)
+		return true
+	}
+	return allowed == 1
+}
+
+// refuseRateLimited drops an update over the tenant's rate limit, telling
+// the chat to slow down once per rateNoticeWindow
+func (rt *Router) refuseRateLimited(tenantID string, body []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	metrics.RouterRateLimited.WithLabelValues(rt.env, tenantID).Inc()
+	to := extractReplyTarget(body)
+	slog.Warn("update over rate limit, dropping", "tenant", tenantID, "chat_id", to.ChatID)
+	if to.ChatID == 0 {
+		return
+	}
+	noticeKey := rt.key(rateNoticePrefix, tenantID+":"+strconv.FormatInt(to.ChatID, 10))
+	if first, err := rt.rdb.SetNX(ctx, noticeKey, "1", rateNoticeWindow).Result(); err == nil && !first {
+		return
+	}
+	if botToken := rt.getBotToken(ctx, tenantID); botToken != "" {
+		rt.sendTelegramMessage(tenantID, botToken, to, rt.msg(ctx, tenantID, i18n.SlowDown))
+	}
+}
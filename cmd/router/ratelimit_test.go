@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	l, err := parseRateLimit("30", "5")
+	assert.NoError(t, err)
+	assert.Equal(t, rateLimit{perMinute: 30, burst: 5}, l)
+
+	l, err = parseRateLimit("0", "0")
+	assert.NoError(t, err)
+	assert.Zero(t, l, "0 turns the limit off")
+
+	_, err = parseRateLimit("30", "0")
+	assert.ErrorContains(t, err, "TENANT_RATE_BURST")
+	_, err = parseRateLimit("-1", "5")
+	assert.ErrorContains(t, err, "TENANT_RATE_PER_MIN")
+	_, err = parseRateLimit("30", "lots")
+	assert.ErrorContains(t, err, "TENANT_RATE_BURST")
+}
+
+// TestAllowUpdate_FailsOpen: updates go through when the limit is off or
+// Redis is down
+func TestAllowUpdate_FailsOpen(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+
+	rt := &Router{rdb: rdb}
+	assert.True(t, rt.allowUpdate(context.Background(), "alice"))
+
+	rt.rateLimit = rateLimit{perMinute: 1, burst: 1}
+	for range 3 {
+		assert.True(t, rt.allowUpdate(context.Background(), "alice"))
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	homeRegionPrefix   = "router:home:"
+	homeRegionCacheTTL = 10 * time.Minute // a tenant's home region only changes by operator action
+	relaySecretHeader  = "X-Relay-Secret"
+)
+
+// parsePeers parses REGION_PEERS, a comma-separated list of region=URL pairs
+// naming the router that serves each region, e.g.
+// "eu-west-1=https://router.eu-west-1.internal,us-east-1=https://router.us-east-1.internal".
+func parsePeers(s string) (map[string]string, error) {
+	peers := map[string]string{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		region, base, ok := strings.Cut(entry, "=")
+		region, base = strings.TrimSpace(region), strings.TrimRight(strings.TrimSpace(base), "/")
+		if !ok || region == "" {
+			return nil, fmt.Errorf("peer %q: want region=url", entry)
+		}
+		if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("peer %q: invalid url", entry)
+		}
+		peers[region] = base
+	}
+	return peers, nil
+}
+
+// remoteHome returns the tenant's home region if it is not this router's
+// region, or "" when the tenant is handled locally. Single-region routers
+// (no REGION) and tenants without a home region are always local. The answer
+// is cached in this region's Redis; the orchestrator's GET /tenants/{id} is
+// the source of truth.
+func (rt *Router) remoteHome(ctx context.Context, tenantID string) string {
+	if rt.region == "" {
+		return ""
+	}
+	key := rt.key(homeRegionPrefix, tenantID)
+	home, err := rt.rdb.Get(ctx, key).Result()
+	if err != nil || home == "" {
+		home = rt.fetchHomeRegion(ctx, tenantID)
+		if home == "" {
+			return ""
+		}
+		if err := rt.rdb.Set(ctx, key, home, homeRegionCacheTTL).Err(); err != nil {
+			slog.Warn("cache home region failed", "tenant", tenantID, "err", err)
+		}
+	}
+	if home == rt.region {
+		return ""
+	}
+	return home
+}
+
+func (rt *Router) fetchHomeRegion(ctx context.Context, tenantID string) string {
+	rec, err := rt.orchestrator().GetTenant(ctx, tenantID)
+	if err != nil {
+		return ""
+	}
+	return rec.HomeRegion
+}
+
+// relayToHome hands a Telegram update to the router in the tenant's home
+// region, which owns the tenant's endpoint cache and is the only region
+// allowed to wake its pod. The body is passed through unchanged.
+func (rt *Router) relayToHome(ctx context.Context, home, tenantID string, body []byte) {
+	peer, ok := rt.peers[home]
+	if !ok {
+		slog.Error("relay failed: no router for home region", "tenant", tenantID, "home", home)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/relay/%s", peer, tenantID), bytes.NewReader(body))
+	if err != nil {
+		slog.Error("build relay request", "tenant", tenantID, "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(relaySecretHeader, rt.relaySecret)
+	resp, err := rt.externalClient.Do(req)
+	if err != nil {
+		slog.Error("relay failed", "tenant", tenantID, "home", home, "err", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("relay failed", "tenant", tenantID, "home", home, "status", resp.StatusCode)
+		return
+	}
+	slog.Info("relayed to home region", "tenant", tenantID, "home", home)
+}
+
+// relayHandler receives updates relayed by another region's router.
+// Path: POST /relay/{tenantID}
+func (rt *Router) relayHandler(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(relaySecretHeader)), []byte(rt.relaySecret)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	tenantID := chi.URLParam(r, "tenantID")
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	// Handled here even if this region disagrees about the home, so a
+	// message is never bounced between regions
+	rt.dispatchUpdate(tenantID, body, dispatch{relayed: true})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeers(t *testing.T) {
+	peers, err := parsePeers(" eu-west-1=https://router.eu.internal/ , us-east-1=http://10.0.0.5:9090")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"eu-west-1": "https://router.eu.internal",
+		"us-east-1": "http://10.0.0.5:9090",
+	}, peers)
+
+	for _, bad := range []string{"eu-west-1", "=https://x", "eu-west-1=router.eu.internal"} {
+		_, err := parsePeers(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRelayToHome(t *testing.T) {
+	var gotPath, gotSecret, gotBody string
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotSecret = r.Header.Get(relaySecretHeader)
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer peer.Close()
+
+	rt := &Router{
+		externalClient: peer.Client(),
+		region:         "us-east-1",
+		peers:          map[string]string{"eu-west-1": peer.URL + "/env/dev"},
+		relaySecret:    "s3cret",
+	}
+	rt.relayToHome(context.Background(), "eu-west-1", "alice", []byte(`{"update_id":1}`))
+
+	assert.Equal(t, "/env/dev/relay/alice", gotPath)
+	assert.Equal(t, "s3cret", gotSecret)
+	assert.Equal(t, `{"update_id":1}`, gotBody)
+}
+
+func TestRelayHandler_RejectsWrongSecret(t *testing.T) {
+	rt := &Router{relaySecret: "s3cret"}
+	req := httptest.NewRequest(http.MethodPost, "/relay/alice", nil)
+	req.Header.Set(relaySecretHeader, "guess")
+	rec := httptest.NewRecorder()
+	rt.relayHandler(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+)
+
+// resetConversation asks the tenant's pod to clear its conversation memory
+// and tells the user whether it worked. The command itself is never
+// forwarded to the agent.
+func (rt *Router) resetConversation(ctx context.Context, podIP, tenantID string, to replyTarget, ttl time.Duration) {
+	reply := i18n.ResetDone
+	if err := rt.postReset(ctx, podIP, tenantID); err != nil {
+		slog.Warn("conversation reset failed", "tenant", tenantID, "pod_ip", podIP, "err", err)
+		reply = i18n.ResetFailed
+	} else {
+		rt.rdb.Expire(ctx, rt.key(cacheKeyPrefix, tenantID), ttl)
+		slog.Info("conversation reset", "tenant", tenantID, "pod_ip", podIP)
+	}
+
+	botToken := rt.getBotToken(ctx, tenantID)
+	if to.ChatID != 0 && botToken != "" {
+		rt.sendTelegramMessage(tenantID, botToken, to, rt.msg(ctx, tenantID, reply))
+	}
+}
+
+// postReset calls the pod's reset endpoint. A connection failure means the
+// cached IP is stale, so the cache entry is dropped as in forwardToPod.
+func (rt *Router) postReset(ctx context.Context, podIP, tenantID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, contract.AgentURL(podIP, rt.resetPath), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(contract.AgentVersionHeader, contract.AgentVersion)
+	resp, err := rt.podClient.Do(req)
+	if err != nil {
+		rt.invalidateCache(ctx, "endpoint", tenantID, invalidateResetFailed)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pod returned %d", resp.StatusCode)
+	}
+	return nil
+}
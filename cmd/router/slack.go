@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/pkg/client"
+)
+
+const (
+	slackPrefix      = contract.SlackPrefix
+	slackEventPrefix = "router:slack-event:"
+	slackEventTTL    = time.Hour       // Slack retries a delivery for up to ~1h
+	slackMaxSkew     = 5 * time.Minute // replay window for signed requests
+)
+
+// slackConfig is a tenant's Slack app credentials
+type slackConfig = client.SlackConfig
+
+// slackEnvelope is the outer Events API payload
+type slackEnvelope struct {
+	Type      string     `json:"type"`
+	Challenge string     `json:"challenge"`
+	EventID   string     `json:"event_id"`
+	Event     slackEvent `json:"event"`
+}
+
+type slackEvent struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	BotID    string `json:"bot_id"`
+	Text     string `json:"text"`
+	Channel  string `json:"channel"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// slackMention matches a leading "<@U123>" user mention
+var slackMention = regexp.MustCompile(`^\s*<@[A-Z0-9]+>\s*`)
+
+// slackHandler receives Slack Events API requests for a specific tenant.
+// Path: POST /slack/{tenantID}
+func (rt *Router) slackHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+
+	cfg := rt.getSlackConfig(r.Context(), tenantID)
+	if cfg == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err := verifySlackSignature(cfg.SigningSecret, r.Header, body, time.Now()); err != nil {
+		slog.Warn("slack signature rejected", "tenant", tenantID, "err", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var env slackEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if env.Type == "url_verification" {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, env.Challenge)
+		return
+	}
+
+	// Ack Slack immediately (must respond within 3s)
+	w.WriteHeader(http.StatusOK)
+	if env.Type != "event_callback" {
+		return
+	}
+	text, ok := slackMessageText(env.Event)
+	if !ok {
+		return
+	}
+	// Slack retries deliveries it thinks timed out; handle each event once
+	if env.EventID != "" {
+		first, err := rt.rdb.SetNX(r.Context(), rt.key(slackEventPrefix, env.EventID), 1, slackEventTTL).Result()
+		if err == nil && !first {
+			return
+		}
+	}
+	go rt.handleSlackEvent(tenantID, cfg.BotToken, env.Event, text)
+}
+
+// verifySlackSignature checks Slack's v0 request signature: an HMAC-SHA256 of
+// "v0:{timestamp}:{body}" keyed with the app's signing secret.
+func verifySlackSignature(secret string, h http.Header, body []byte, now time.Time) error {
+	ts := h.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid timestamp")
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return fmt.Errorf("timestamp outside replay window")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", ts)
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(h.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// slackMessageText returns the text to forward for a user message or app
+// mention, with the leading bot mention stripped. Bot messages (including
+// our own replies) and edits/joins are ignored.
+func slackMessageText(ev slackEvent) (string, bool) {
+	if ev.Type != "message" && ev.Type != "app_mention" {
+		return "", false
+	}
+	if ev.Subtype != "" || ev.BotID != "" {
+		return "", false
+	}
+	text := strings.TrimSpace(slackMention.ReplaceAllString(ev.Text, ""))
+	return text, text != ""
+}
+
+// handleSlackEvent delivers one Slack message to the tenant's pod and posts
+// the reply in the message's thread.
+func (rt *Router) handleSlackEvent(tenantID, botToken string, ev slackEvent, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), podReadyWait+30*time.Second)
+	defer cancel()
+
+	// Slack has a single Request URL per app, so unlike Telegram there is no
+	// per-region registration to fix; point the app at the home region.
+	if home := rt.remoteHome(ctx, tenantID); home != "" {
+		slog.Warn("slack event for tenant homed in another region, dropping", "tenant", tenantID, "home", home)
+		return
+	}
+
+	thread := ev.ThreadTS
+	if thread == "" {
+		thread = ev.TS
+	}
+	if podIP, _, err := rt.getCachedEndpoint(ctx, tenantID); err != nil || podIP == "" {
+		rt.sendSlackMessage(botToken, ev.Channel, thread, rt.msg(ctx, tenantID, i18n.StartingUp))
+	}
+	podIP, ttl, err := rt.resolvePod(ctx, tenantID, replyTarget{})
+	if err != nil {
+		rt.sendSlackMessage(botToken, ev.Channel, thread, rt.msg(ctx, tenantID, wakeFailedMessage(err)))
+		return
+	}
+	reply, err := rt.askPod(ctx, podIP, tenantID, podMessage{Message: text}, ttl, nil)
+	if err != nil {
+		// Not delivered, so not metered
+		slog.Error("slack event not delivered", "tenant", tenantID, "err", err)
+		return
+	}
+	if reply.Text != "" {
+		rt.sendSlackMessage(botToken, ev.Channel, thread, rt.limitReply(ctx, tenantID, reply.Text))
+	}
+	vol := newMessageVolume(text, reply.Text)
+	vol.ChatID = ev.Channel
+	rt.updateActivity(tenantID, vol)
+}
+
+// getSlackConfig returns the tenant's Slack credentials, or nil if the
+// tenant has no Slack app. Cached like bot tokens; the orchestrator deletes
+// the key when the credentials change.
+func (rt *Router) getSlackConfig(ctx context.Context, tenantID string) *slackConfig {
+	key := rt.key(slackPrefix, tenantID)
+	if vals, err := rt.rdb.HGetAll(ctx, key).Result(); err == nil && vals["signing_secret"] != "" {
+		return &slackConfig{SigningSecret: vals["signing_secret"], BotToken: vals["bot_token"]}
+	}
+	cfg := rt.fetchSlackConfig(ctx, tenantID)
+	if cfg == nil {
+		return nil
+	}
+	pipe := rt.rdb.TxPipeline()
+	pipe.HSet(ctx, key, "signing_secret", cfg.SigningSecret, "bot_token", cfg.BotToken)
+	pipe.Expire(ctx, key, botTokenCacheTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("cache slack config failed", "tenant", tenantID, "err", err)
+	}
+	return cfg
+}
+
+func (rt *Router) fetchSlackConfig(ctx context.Context, tenantID string) *slackConfig {
+	cfg, err := rt.orchestrator().GetSlack(ctx, tenantID)
+	if err != nil || cfg.SigningSecret == "" {
+		return nil
+	}
+	return cfg
+}
+
+func (rt *Router) sendSlackMessage(botToken, channel, threadTS, text string) {
+	payload, _ := json.Marshal(map[string]any{
+		"channel":   channel,
+		"thread_ts": threadTS,
+		"text":      text,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/chat.postMessage", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+botToken)
+	resp, err := rt.externalClient.Do(req)
+	if err != nil {
+		slog.Warn("slack postMessage failed", "channel", channel, "err", err)
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func signSlack(secret string, ts time.Time, body string) http.Header {
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", strconv.FormatInt(ts.Unix(), 10))
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:%s", ts.Unix(), body)
+	h.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return h
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"type":"event_callback"}`)
+
+	assert.NoError(t, verifySlackSignature("s3cret", signSlack("s3cret", now, string(body)), body, now))
+	assert.Error(t, verifySlackSignature("s3cret", signSlack("guess", now, string(body)), body, now), "wrong secret")
+	assert.Error(t, verifySlackSignature("s3cret", signSlack("s3cret", now, `{}`), body, now), "tampered body")
+	assert.Error(t, verifySlackSignature("s3cret", signSlack("s3cret", now.Add(-10*time.Minute), string(body)), body, now), "stale")
+	assert.Error(t, verifySlackSignature("s3cret", http.Header{}, body, now), "unsigned")
+}
+
+func TestSlackMessageText(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   slackEvent
+		text string
+		ok   bool
+	}{
+		{"direct message", slackEvent{Type: "message", Text: "hi"}, "hi", true},
+		{"mention", slackEvent{Type: "app_mention", Text: "<@U0BOT> what's up?"}, "what's up?", true},
+		{"bare mention", slackEvent{Type: "app_mention", Text: "<@U0BOT>"}, "", false},
+		{"bot message", slackEvent{Type: "message", BotID: "B1", Text: "reply"}, "", false},
+		{"edit", slackEvent{Type: "message", Subtype: "message_changed", Text: "hi"}, "", false},
+		{"reaction", slackEvent{Type: "reaction_added"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok := slackMessageText(tt.ev)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestSlackHandler(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenants/alice/slack" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"signing_secret":"s3cret","bot_token":"xoxb-1"}`)
+	}))
+	defer orch.Close()
+
+	// Unreachable Redis: every cache lookup misses and falls through
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	rt := &Router{rdb: rdb, orchestratorAddr: orch.URL, orchestratorClient: orch.Client()}
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+
+	post := func(path, body string, h http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		for k, v := range h {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	challenge := `{"type":"url_verification","challenge":"abc123"}`
+	rec := post("/slack/alice", challenge, signSlack("s3cret", time.Now(), challenge))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "abc123", rec.Body.String())
+
+	rec = post("/slack/alice", challenge, signSlack("guess", time.Now(), challenge))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = post("/slack/bob", challenge, signSlack("s3cret", time.Now(), challenge))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "tenant without a Slack app")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/pkg/client"
+)
+
+// sleepTenant asks the orchestrator to hibernate the tenant's pod now rather
+// than at its idle timeout, and tells the user. The next message wakes it.
+func (rt *Router) sleepTenant(ctx context.Context, tenantID string, to replyTarget) {
+	reply := i18n.SleepDone
+	status, err := rt.postSleep(ctx, tenantID)
+	switch {
+	case err != nil:
+		slog.Warn("sleep request failed", "tenant", tenantID, "err", err)
+		reply = i18n.SleepFailed
+	case status == http.StatusConflict:
+		reply = i18n.AlreadyAsleep
+	default:
+		slog.Info("tenant put to sleep", "tenant", tenantID)
+	}
+	rt.invalidateCache(ctx, "endpoint", tenantID, invalidateSleep)
+
+	botToken := rt.getBotToken(ctx, tenantID)
+	if to.ChatID != 0 && botToken != "" {
+		rt.sendTelegramMessage(tenantID, botToken, to, rt.msg(ctx, tenantID, reply))
+	}
+}
+
+// postSleep calls POST /tenants/{id}/sleep. A 409 (not running, or a wake in
+// progress) is returned as a status rather than an error.
+func (rt *Router) postSleep(ctx context.Context, tenantID string) (int, error) {
+	_, err := rt.orchestrator().SleepTenant(ctx, tenantID)
+	var apiErr *client.Error
+	switch {
+	case err == nil:
+		return http.StatusOK, nil
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict:
+		return http.StatusConflict, nil
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode, fmt.Errorf("sleep status %d: %s", apiErr.StatusCode, apiErr.Message)
+	}
+	return 0, err
+}
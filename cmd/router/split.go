@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// telegramMaxMessageLen is the most a Telegram message holds, in UTF-16 code
+// units (what Telegram counts; emoji outside the BMP take two)
+const telegramMaxMessageLen = 4096
+
+// messageSplitter cuts text too long for one Telegram message into several.
+// Cuts fall on a paragraph, line or word break where one is reasonably
+// close to the limit. A message ending inside a ``` code block closes it,
+// and the next one reopens it with the same language, so each message is
+// Markdown that formatReply can render on its own.
+type messageSplitter struct {
+	limit int
+}
+
+func newMessageSplitter() messageSplitter {
+	return messageSplitter{limit: telegramMaxMessageLen}
+}
+
+// split returns text as the messages to send, in order
+func (sp messageSplitter) split(text string) []string {
+	var msgs []string
+	fence := ""
+	for text != "" {
+		msg, n, next := sp.next(text, fence)
+		if strings.TrimSpace(msg) != "" {
+			msgs = append(msgs, msg)
+		}
+		text, fence = text[n:], next
+	}
+	return msgs
+}
+
+// next returns the first message of text, which starts inside code block
+// fence ("" if none): all of it if it fits. n is how much of text the
+// message used, and next the code block the following message starts in.
+func (sp messageSplitter) next(text, fence string) (msg string, n int, next string) {
+	prefix := ""
+	if fence != "" {
+		prefix = fence + "\n"
+	}
+	if utf16Len(prefix)+utf16Len(text) <= sp.limit {
+		return prefix + text, len(text), ""
+	}
+	// Leave room for closing a code block the cut may fall in
+	budget := sp.limit - utf16Len(prefix) - len("\n```")
+	max := prefixWithin(text, budget)
+	cut, skip := breakBefore(text[:max])
+	piece := text[:cut]
+	if next = fenceAfter(piece, fence); next != "" {
+		piece = strings.TrimRight(piece, "\n") + "\n```"
+	}
+	return prefix + piece, cut + skip, next
+}
+
+// fenceAfter returns the code block open at the end of piece, which starts
+// inside fence
+func fenceAfter(piece, fence string) string {
+	for _, line := range strings.Split(piece, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "```") {
+			continue
+		}
+		if fence != "" {
+			fence = ""
+		} else if !strings.Contains(line[3:], "```") {
+			fence = line // "```go": the next message reopens it with the language
+		}
+	}
+	return fence
+}
+
+// breakBefore picks where to cut s, the most that fits in a message: after
+// its last paragraph, line or word break, if that keeps at least half of
+// s, else at the end of s. skip is the length of the break, which starts
+// the next message's text but isn't sent.
+func breakBefore(s string) (cut, skip int) {
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		if i := strings.LastIndex(s, sep); i > 0 && i >= len(s)/2 {
+			return i, len(sep)
+		}
+	}
+	return len(s), 0
+}
+
+// prefixWithin returns the length in bytes of the longest prefix of s
+// that is at most n UTF-16 code units, ending on a rune boundary
+func prefixWithin(s string, n int) int {
+	units := 0
+	for i, r := range s {
+		units += utf16.RuneLen(r)
+		if units > n {
+			return i
+		}
+	}
+	return len(s)
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMessage_Short(t *testing.T) {
+	assert.Equal(t, []string{"hello"}, newMessageSplitter().split("hello"))
+	assert.Empty(t, newMessageSplitter().split(""))
+}
+
+// TestSplitMessage_Breaks: cuts fall on paragraph, then line, then word
+// breaks, which are dropped
+func TestSplitMessage_Breaks(t *testing.T) {
+	sp := messageSplitter{limit: 24}
+	assert.Equal(t, []string{"first paragraph", "second one here"}, sp.split("first paragraph\n\nsecond one here"))
+	assert.Equal(t, []string{"line one is long", "line two"}, sp.split("line one is long\nline two"))
+	assert.Equal(t, []string{"some words that", "wrap around"}, sp.split("some words that wrap around"))
+	assert.Equal(t, []string{strings.Repeat("x", 20), strings.Repeat("x", 5)}, sp.split(strings.Repeat("x", 25)),
+		"no break: hard cut, leaving room for a fence")
+}
+
+// TestSplitMessage_CodeBlock: a cut inside a fence closes it and reopens it,
+// with its language, in the next message
+func TestSplitMessage_CodeBlock(t *testing.T) {
+	sp := messageSplitter{limit: 50}
+	text := "Here:\n```go\nfmt.Println(1)\nfmt.Println(2)\nfmt.Println(3)\n```\nDone"
+	msgs := sp.split(text)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "Here:\n```go\nfmt.Println(1)\nfmt.Println(2)\n```", msgs[0])
+	assert.Equal(t, "```go\nfmt.Println(3)\n```\nDone", msgs[1])
+	for _, m := range msgs {
+		assert.LessOrEqual(t, utf16Len(m), 50)
+		assert.Equal(t, 0, strings.Count(m, "```")%2, "balanced fences: %q", m)
+	}
+}
+
+// TestSplitMessage_UTF16: the limit counts UTF-16 code units, like Telegram
+func TestSplitMessage_UTF16(t *testing.T) {
+	sp := messageSplitter{limit: 10}
+	msgs := sp.split(strings.Repeat("😀", 8)) // 16 units
+	require.Len(t, msgs, 2)
+	assert.Equal(t, strings.Repeat("😀", 3), msgs[0], "a cut leaves room for a fence")
+	for _, m := range msgs {
+		assert.LessOrEqual(t, utf16Len(m), 10)
+	}
+}
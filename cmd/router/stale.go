@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+)
+
+const (
+	maxAgePrefix = contract.MaxAgePrefix
+	// staleNoticePrefix marks a chat already told its late updates were
+	// skipped, so a backlog delivered at once gets one notice, not one each
+	staleNoticePrefix = "router:stalenotice:"
+	staleNoticeWindow = 10 * time.Minute
+)
+
+// updateTime returns when an update's message was sent, or last edited for
+// an edit. Zero for updates without a date, like callback queries.
+func updateTime(body []byte) time.Time {
+	msg := updateMessageOf(body)
+	switch {
+	case msg == nil || msg.Date == 0:
+		return time.Time{}
+	case msg.EditDate != 0:
+		return time.Unix(msg.EditDate, 0)
+	default:
+		return time.Unix(msg.Date, 0)
+	}
+}
+
+// refuseStale skips an update older than the tenant's maximum age, telling
+// the chat once per backlog, and reports whether it did. Telegram keeps
+// retrying updates while the webhook is down, so after an outage a user's
+// hours-old "deploy it" could otherwise reach an agent that has moved on.
+func (rt *Router) refuseStale(ctx context.Context, tenantID string, body []byte, to replyTarget) bool {
+	sent := updateTime(body)
+	if sent.IsZero() {
+		return false
+	}
+	maxAge := rt.maxMessageAgeFor(ctx, tenantID)
+	age := time.Since(sent)
+	if maxAge <= 0 || age <= maxAge {
+		return false
+	}
+	slog.Warn("stale update, not forwarding", "tenant", tenantID, "chat_id", to.ChatID,
+		"age", age.Round(time.Second), "max_age", maxAge)
+	if to.ChatID == 0 {
+		return true
+	}
+	noticeKey := rt.key(staleNoticePrefix, tenantID+":"+strconv.FormatInt(to.ChatID, 10))
+	if first, err := rt.rdb.SetNX(ctx, noticeKey, "1", staleNoticeWindow).Result(); err == nil && !first {
+		return true
+	}
+	if botToken := rt.getBotToken(ctx, tenantID); botToken != "" {
+		rt.sendTelegramMessage(tenantID, botToken, to, rt.msg(ctx, tenantID, i18n.StaleMessages))
+	}
+	return true
+}
+
+// maxMessageAgeFor returns the tenant's maximum update age: its own
+// MaxMessageAgeS, cached like its locale, else the router's. 0 means any age.
+func (rt *Router) maxMessageAgeFor(ctx context.Context, tenantID string) time.Duration {
+	key := rt.key(maxAgePrefix, tenantID)
+	seconds, err := rt.rdb.Get(ctx, key).Int64()
+	if err != nil {
+		if seconds, err = rt.fetchMaxMessageAge(ctx, tenantID); err != nil {
+			return rt.maxMessageAge
+		}
+		rt.rdb.Set(ctx, key, seconds, botTokenCacheTTL)
+	}
+	switch {
+	case seconds < 0:
+		return 0
+	case seconds > 0:
+		return time.Duration(seconds) * time.Second
+	default:
+		return rt.maxMessageAge
+	}
+}
+
+func (rt *Router) fetchMaxMessageAge(ctx context.Context, tenantID string) (int64, error) {
+	rec, err := rt.orchestrator().GetTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(rec.MaxMessageAgeS), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTime(t *testing.T) {
+	assert.Equal(t, time.Unix(1700000000, 0), updateTime([]byte(`{"message":{"date":1700000000}}`)))
+	assert.Equal(t, time.Unix(1700000600, 0), updateTime([]byte(`{"edited_message":{"date":1700000000,"edit_date":1700000600}}`)),
+		"an edit counts from when it was made")
+	assert.True(t, updateTime([]byte(`{"callback_query":{"data":"x"}}`)).IsZero())
+}
+
+// TestRefuseStale: updates older than the router's maximum age get a notice
+// instead of being forwarded, unless the tenant turned the check off
+func TestRefuseStale(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, nil, tg, 0)
+	rt.maxMessageAge = time.Hour
+	update := func(age time.Duration) []byte {
+		return []byte(fmt.Sprintf(`{"message":{"chat":{"id":1},"date":%d,"text":"deploy it"}}`, time.Now().Add(-age).Unix()))
+	}
+	to := replyTarget{ChatID: 1}
+
+	assert.False(t, rt.refuseStale(context.Background(), "alice", update(time.Minute), to))
+	assert.Empty(t, tg.calls)
+	assert.True(t, rt.refuseStale(context.Background(), "alice", update(3*time.Hour), to))
+	assert.Equal(t, []string{"send:" + i18n.T("", i18n.StaleMessages)}, tg.calls)
+
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"BotToken":"123:abc","MaxMessageAgeS":-1}`)
+	}))
+	defer orch.Close()
+	rt.orchestratorAddr = orch.URL
+	assert.False(t, rt.refuseStale(context.Background(), "alice", update(3*time.Hour), to), "tenant override: any age")
+}
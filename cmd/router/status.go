@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+)
+
+const (
+	statusPagePrefix = "router:status:"
+	// statusPageCacheTTL keeps an embedded badge that is polled or widely
+	// shared from turning into orchestrator and DynamoDB load
+	statusPageCacheTTL = 30 * time.Second
+)
+
+var statusPageTmpl = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .BotUsername}}@{{.BotUsername}}{{else}}{{.TenantID}}{{end}} status</title>
+<style>
+body{font-family:system-ui,sans-serif;max-width:28rem;margin:2rem auto;padding:0 1rem;color:#222}
+.state{display:inline-block;padding:.2rem .6rem;border-radius:1rem;color:#fff;background:#888}
+.online{background:#2a7}.sleeping{background:#57a}.starting{background:#c90}.paused{background:#b44}.down{background:#b44}
+dt{color:#666;margin-top:.8rem}dd{margin:0}
+</style>
+</head>
+<body>
+<h1>{{if .BotUsername}}@{{.BotUsername}}{{else}}{{.TenantID}}{{end}}</h1>
+<p><span class="state {{.State}}">{{.State}}</span></p>
+<dl>
+<dt>Last activity</dt><dd>{{if .LastActiveAt.IsZero}}never{{else}}{{.LastActiveAt.Format "2006-01-02 15:04 MST"}}{{end}}</dd>
+<dt>Uptime (7 days)</dt><dd>{{printf "%.1f" .UptimePercent}}% of {{.Wakes}} starts succeeded</dd>
+<dt>Incidents (7 days)</dt><dd>{{.Incidents}}</dd>
+</dl>
+<p><small>Checked {{.CheckedAt.Format "2006-01-02 15:04:05 MST"}}</small></p>
+</body>
+</html>
+`))
+
+// statusPageHandler serves a tenant's public status to holders of a link
+// issued by POST /tenants/{id}/status-link, as HTML or, with ?format=json or
+// Accept: application/json, as JSON.
+// Path: GET /status/{token}
+func (rt *Router) statusPageHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := statuspage.Verify(rt.statusSecret, rt.env, chi.URLParam(r, "token"), time.Now())
+	if errors.Is(err, statuspage.ErrExpired) {
+		http.Error(w, "this status link has expired", http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	st, err := rt.getPublicStatus(r.Context(), tenantID)
+	if err != nil {
+		slog.Warn("status page: fetch status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "status unavailable, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Embeddable in the owner's own pages
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusPageCacheTTL.Seconds())))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(st)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPageTmpl.Execute(w, st); err != nil {
+		slog.Warn("status page: render failed", "tenant", tenantID, "err", err)
+	}
+}
+
+// getPublicStatus returns the tenant's status page data, cached for
+// statusPageCacheTTL
+func (rt *Router) getPublicStatus(ctx context.Context, tenantID string) (*statuspage.Status, error) {
+	key := rt.key(statusPagePrefix, tenantID)
+	if data, err := rt.rdb.Get(ctx, key).Bytes(); err == nil {
+		var st statuspage.Status
+		if json.Unmarshal(data, &st) == nil {
+			return &st, nil
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/tenants/%s/public-status", rt.orchestratorAddr, tenantID), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt.orchestratorClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("orchestrator returned %d", resp.StatusCode)
+	}
+	var st statuspage.Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(st); err == nil {
+		rt.rdb.Set(ctx, key, data, statusPageCacheTTL)
+	}
+	return &st, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusPage(t *testing.T) {
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenants/alice/public-status" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"tenant_id":"alice","bot_username":"alice_bot","state":"online","wakes":4,"failed_wakes":1,"uptime_percent":75,"incidents":2}`)
+	}))
+	defer orch.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	secret := []byte("s3cret")
+	rt := &Router{rdb: rdb, orchestratorAddr: orch.URL, orchestratorClient: http.DefaultClient, statusSecret: secret}
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	token := statuspage.Sign(secret, "", "alice", time.Now().Add(time.Hour))
+	rec := get("/status/"+token, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "@alice_bot")
+	assert.Contains(t, rec.Body.String(), "75.0% of 4 starts succeeded")
+
+	rec = get("/status/"+token, "application/json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"state":"online"`)
+
+	assert.Equal(t, http.StatusGone, get("/status/"+statuspage.Sign(secret, "", "alice", time.Now().Add(-time.Minute)), "").Code)
+	assert.Equal(t, http.StatusNotFound, get("/status/"+statuspage.Sign([]byte("other"), "", "alice", time.Now().Add(time.Hour)), "").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/status/"+statuspage.Sign(secret, "", "bob", time.Now().Add(time.Hour)), "").Code)
+
+	// Without a secret there are no status pages
+	r = chi.NewRouter()
+	(&Router{rdb: rdb}).routes(r, nil)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/"+token, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
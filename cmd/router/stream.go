@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+)
+
+const (
+	// defaultStreamEditInterval keeps edits of a streamed reply within
+	// Telegram's limit of about one message per second per chat
+	defaultStreamEditInterval = time.Second
+	sseMaxLineBytes           = 1 << 20
+)
+
+// streamEvent is the data of one server-sent event from the pod: a delta to
+// append to the reply, or the whole reply so far. Any event may carry the
+// reply's keyboard; the last one wins.
+type streamEvent struct {
+	Delta       string          `json:"delta"`
+	Response    *string         `json:"response"`
+	ReplyMarkup json.RawMessage `json:"reply_markup"`
+}
+
+// readReplyStream reads a text/event-stream reply from the pod until
+// "data: [DONE]" or EOF, calling progress (if set) with the reply so far
+// after each event. Data that isn't a streamEvent is taken as a plain-text
+// delta. A stream cut short returns what arrived, with the error.
+func readReplyStream(body io.Reader, progress func(string)) (podReply, error) {
+	var reply strings.Builder
+	var markup json.RawMessage
+	var data []string
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64<<10), sseMaxLineBytes)
+	dispatch := func() bool {
+		if len(data) == 0 {
+			return true
+		}
+		d := strings.Join(data, "\n")
+		data = data[:0]
+		if d == "[DONE]" {
+			return false
+		}
+		var ev streamEvent
+		switch {
+		case json.Unmarshal([]byte(d), &ev) != nil:
+			reply.WriteString(d)
+		case ev.Response != nil:
+			reply.Reset()
+			reply.WriteString(*ev.Response)
+		default:
+			reply.WriteString(ev.Delta)
+		}
+		if len(ev.ReplyMarkup) > 0 {
+			markup = ev.ReplyMarkup
+		}
+		if progress != nil {
+			progress(reply.String())
+		}
+		return true
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if !dispatch() {
+				return podReply{Text: reply.String(), ReplyMarkup: markup}, nil
+			}
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(v, " "))
+		}
+		// event:, id:, retry: and comments carry nothing the router uses
+	}
+	dispatch()
+	return podReply{Text: reply.String(), ReplyMarkup: markup}, scanner.Err()
+}
+
+// replyStream shows a reply in a Telegram chat while it is generated: the
+// first text is sent as a message, which is then edited as more arrives, at
+// most once per interval. Text beyond what a message holds continues in a
+// new one (see messageSplitter). Only finished messages are rendered in the
+// reply parse mode (see formatReply); until then Markdown may be cut off
+// mid-way, so the text goes out as written. The reply's keyboard, if any,
+// goes on its last message once finished.
+type replyStream struct {
+	rt        *Router
+	tenantID  string
+	botToken  string
+	to        replyTarget
+	interval  time.Duration
+	parseMode string
+	splitter  messageSplitter
+
+	messageID int64  // message being edited; 0 before the first send
+	done      int    // bytes of the reply already completed in earlier messages
+	fence     string // code block messageID starts inside
+	shown     string // text of messageID, before formatReply
+	shownMode string // parse mode shown was sent with
+	lastSent  time.Time
+	markup    json.RawMessage // inline keyboard for the last message, set by finish
+}
+
+func (rt *Router) newReplyStream(tenantID, botToken string, to replyTarget) *replyStream {
+	return &replyStream{rt: rt, tenantID: tenantID, botToken: botToken, to: to, interval: rt.streamEditInterval,
+		parseMode: rt.replyParseMode, splitter: newMessageSplitter()}
+}
+
+// update shows the reply so far, unless the last edit was too recent
+func (s *replyStream) update(reply string) {
+	if time.Since(s.lastSent) < s.interval {
+		return
+	}
+	s.show(reply, false)
+}
+
+// finish shows the complete reply, with its inline keyboard (nil for none).
+// Without any earlier update it is sent like the reply of a pod that
+// doesn't stream, one message after another.
+func (s *replyStream) finish(reply string, markup json.RawMessage) {
+	s.markup = markup
+	s.show(reply, true)
+}
+
+func (s *replyStream) show(reply string, final bool) {
+	if len(reply) < s.done {
+		return // the pod replaced the reply with a shorter one; keep what was sent
+	}
+	rest := reply[s.done:]
+	for {
+		msg, n, fence := s.splitter.next(rest, s.fence)
+		if n == len(rest) {
+			if strings.TrimSpace(msg) != "" {
+				s.put(msg, final, final)
+			}
+			return
+		}
+		if strings.TrimSpace(msg) != "" && !s.put(msg, true, false) {
+			return // later messages would make no sense without this one
+		}
+		s.done += n
+		s.fence = fence
+		s.messageID, s.shown, s.shownMode = 0, "", ""
+		rest = rest[n:]
+	}
+}
+
+// put sets the current message to text, sending it if there is none yet;
+// last puts the reply's keyboard under it. A finished message Telegram
+// can't parse once formatted is sent as written, in plain text.
+func (s *replyStream) put(text string, finished, last bool) bool {
+	mode := ""
+	if finished {
+		mode = s.parseMode
+	}
+	var markup json.RawMessage
+	if last {
+		markup = s.markup
+	}
+	if text == s.shown && mode == s.shownMode && markup == nil {
+		return true
+	}
+	s.lastSent = time.Now()
+	err := s.send(text, mode, markup)
+	if mode != "" && isBadMarkup(err) {
+		slog.Info("reply markup rejected, sending as plain text", "tenant", s.tenantID, "parse_mode", mode, "err", err)
+		mode = ""
+		if text != s.shown || s.shownMode != "" || markup != nil {
+			err = s.send(text, mode, markup)
+		} else {
+			err = nil
+		}
+	}
+	if err != nil {
+		method := "editMessageText"
+		if s.messageID == 0 {
+			method = "sendMessage"
+		}
+		metrics.RouterTelegramFailures.WithLabelValues(s.rt.env, s.tenantID).Inc()
+		slog.Warn("telegram "+method+" failed", "tenant", s.tenantID, "chat_id", s.to.ChatID, "err", err)
+		s.rt.dropRejectedBotToken(s.tenantID, err)
+		return false
+	}
+	s.shown, s.shownMode = text, mode
+	return true
+}
+
+// send sends or edits in the current message text, formatted for parseMode
+func (s *replyStream) send(text, parseMode string, markup json.RawMessage) error {
+	if parseMode != "" {
+		text = formatReply(text, parseMode)
+	}
+	if s.messageID != 0 {
+		return s.rt.editTelegramMessage(s.botToken, s.to, s.messageID, text, parseMode, markup)
+	}
+	id, err := s.rt.sendTelegramMessageID(s.botToken, s.to, text, parseMode, markup)
+	if err == nil {
+		s.messageID = id
+	}
+	return err
+}
+
+// sendTelegramMessageID sends one message like sendTelegramMessage, with
+// parseMode ("" for plain text) and an inline keyboard (nil for none), and
+// returns its ID
+func (rt *Router) sendTelegramMessageID(botToken string, to replyTarget, text, parseMode string, markup json.RawMessage) (int64, error) {
+	req := to.sendMessage(text)
+	if parseMode != "" {
+		req["parse_mode"] = parseMode
+	}
+	if markup != nil {
+		req["reply_markup"] = markup
+	}
+	var result struct {
+		MessageID int64 `json:"message_id"`
+	}
+	err := rt.callBotAPI(botToken, "sendMessage", req, &result)
+	return result.MessageID, err
+}
+
+// editTelegramMessage replaces the text, and keyboard if markup is set, of a
+// message the bot sent
+func (rt *Router) editTelegramMessage(botToken string, to replyTarget, messageID int64, text, parseMode string, markup json.RawMessage) error {
+	req := map[string]any{"chat_id": to.ChatID, "message_id": messageID, "text": text}
+	if to.BusinessConnectionID != "" {
+		req["business_connection_id"] = to.BusinessConnectionID
+	}
+	if parseMode != "" {
+		req["parse_mode"] = parseMode
+	}
+	if markup != nil {
+		req["reply_markup"] = markup
+	}
+	err := rt.callBotAPI(botToken, "editMessageText", req, nil)
+	var apiErr *botAPIError
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.description, "message is not modified") {
+		return nil // the same text, re-sent with a parse mode that changed nothing
+	}
+	return err
+}
+
+// botAPIError is a call the Bot API answered with ok=false
+type botAPIError struct {
+	status      int
+	description string
+}
+
+func (e *botAPIError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.status, e.description)
+}
+
+// isBadMarkup reports whether err is Telegram rejecting a message's markup
+// for its parse mode
+func isBadMarkup(err error) bool {
+	var apiErr *botAPIError
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.description, "can't parse entities")
+}
+
+// callBotAPI POSTs a Bot API method and decodes its result into out (if set)
+func (rt *Router) callBotAPI(botToken, method string, req any, out any) error {
+	payload, _ := json.Marshal(req)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, rt.botAPI(botToken, method), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := rt.externalClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if !result.OK {
+		return &botAPIError{status: resp.StatusCode, description: result.Description}
+	}
+	if out != nil {
+		return json.Unmarshal(result.Result, out)
+	}
+	return nil
+}
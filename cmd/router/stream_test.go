@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReplyStream(t *testing.T) {
+	body := ": keepalive\n\n" +
+		"data: {\"delta\":\"Hel\"}\n\n" +
+		"event: token\ndata: {\"delta\":\"lo\"}\n\n" +
+		"data: , world\n\n" +
+		"data: {\"response\":\"Hello, world!\"}\n\n" +
+		"data: [DONE]\n\n" +
+		"data: {\"delta\":\"ignored\"}\n\n"
+	var seen []string
+	reply, err := readReplyStream(strings.NewReader(body), func(s string) { seen = append(seen, s) })
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world!", reply.Text)
+	assert.Nil(t, reply.ReplyMarkup)
+	assert.Equal(t, []string{"Hel", "Hello", "Hello, world", "Hello, world!"}, seen)
+
+	reply, err = readReplyStream(strings.NewReader("data: {\"delta\":\"no done\",\"reply_markup\":{\"inline_keyboard\":[]}}"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "no done", reply.Text, "EOF ends the stream")
+	assert.JSONEq(t, `{"inline_keyboard":[]}`, string(reply.ReplyMarkup))
+}
+
+// telegramRecorder is a fake Bot API recording sendMessage and
+// editMessageText calls
+type telegramRecorder struct {
+	mu sync.Mutex
+	// "send:text" / "edit:id:text", "send(mode):text" with a parse_mode,
+	// "send:text|markup" with a reply_markup, "answer:id" for callbacks
+	calls []string
+	next  int64
+	// rejectMarkup fails every call with a parse_mode like unparseable markup
+	rejectMarkup bool
+}
+
+func (tr *telegramRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MessageID       int64           `json:"message_id"`
+		Text            string          `json:"text"`
+		ParseMode       string          `json:"parse_mode"`
+		ReplyMarkup     json.RawMessage `json:"reply_markup"`
+		CallbackQueryID string          `json:"callback_query_id"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if req.ParseMode != "" && tr.rejectMarkup {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities: unclosed bold"}`)
+		return
+	}
+	mode := ""
+	if req.ParseMode != "" {
+		mode = "(" + req.ParseMode + ")"
+	}
+	if req.ReplyMarkup != nil {
+		req.Text += "|" + string(req.ReplyMarkup)
+	}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+		tr.next++
+		tr.calls = append(tr.calls, "send"+mode+":"+req.Text)
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, tr.next)
+	case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+		tr.calls = append(tr.calls, fmt.Sprintf("edit%s:%d:%s", mode, req.MessageID, req.Text))
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	case strings.HasSuffix(r.URL.Path, "/answerCallbackQuery"):
+		tr.calls = append(tr.calls, "answer:"+req.CallbackQueryID)
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newStreamTestRouter returns a router whose pod at 10.0.0.1:3000 is served
+// by pod and whose Bot API is tg
+func newStreamTestRouter(t *testing.T, pod http.HandlerFunc, tg http.Handler, interval time.Duration) *Router {
+	tgSrv := httptest.NewServer(tg)
+	t.Cleanup(tgSrv.Close)
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"BotToken":"123:abc"}`)
+	}))
+	t.Cleanup(orch.Close)
+	podSrv := httptest.NewServer(pod)
+	t.Cleanup(podSrv.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return &Router{rdb: rdb, orchestratorAddr: orch.URL, telegramAPI: tgSrv.URL, streamEditInterval: interval,
+		orchestratorClient: http.DefaultClient, externalClient: http.DefaultClient,
+		podClient: &http.Client{Transport: rewriteHost(podSrv.Listener.Addr().String(), "10.0.0.1:3000")}}
+}
+
+// TestForwardToPod_Streaming: a streamed reply is sent once and then edited
+// as it grows, and its whole text is metered
+func TestForwardToPod_Streaming(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept"), "text/event-stream")
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, d := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"delta\":%q}\n\n", d)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}, tg, time.Nanosecond)
+
+	vol, err := rt.forwardToPod(context.Background(), "10.0.0.1", "alice", []byte(`{"message":{"chat":{"id":1},"text":"hi"}}`), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"send:Hel", "edit:1:Hello"}, tg.calls)
+	assert.Equal(t, messageVolume{CharsIn: 2, CharsOut: 5}, vol)
+}
+
+// TestForwardToPod_StreamingDisabled: with STREAM_EDIT_INTERVAL_MS=0 the
+// streamed reply is sent once complete
+func TestForwardToPod_StreamingDisabled(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"delta\":\"Hel\"}\n\ndata: {\"delta\":\"lo\"}\n\n")
+	}, tg, 0)
+
+	rt.forwardToPod(context.Background(), "10.0.0.1", "alice", []byte(`{"message":{"chat":{"id":1},"text":"hi"}}`), 0)
+	assert.Equal(t, []string{"send:Hello"}, tg.calls)
+}
+
+// TestReplyStream_Split: text beyond Telegram's message limit continues in a
+// new message
+func TestReplyStream_Split(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, nil, tg, time.Nanosecond)
+	s := rt.newReplyStream("alice", "123:abc", replyTarget{ChatID: 1})
+
+	first, second := strings.Repeat("é", 3000), strings.Repeat("y", 2000)
+	s.update("abc")
+	s.finish(first+"\n\n"+second, nil)
+	assert.Equal(t, []string{"send:abc", "edit:1:" + first, "send:" + second}, tg.calls)
+}
+
+// TestReplyStream_ParseMode: only finished messages are formatted, and ones
+// Telegram rejects are sent as written
+func TestReplyStream_ParseMode(t *testing.T) {
+	tg := &telegramRecorder{}
+	rt := newStreamTestRouter(t, nil, tg, time.Nanosecond)
+	rt.replyParseMode = parseModeMarkdownV2
+	s := rt.newReplyStream("alice", "123:abc", replyTarget{ChatID: 1})
+	s.update("**bo")
+	s.finish("**bold**!", nil)
+	assert.Equal(t, []string{"send:**bo", "edit(MarkdownV2):1:*bold*\\!"}, tg.calls)
+
+	tg = &telegramRecorder{rejectMarkup: true}
+	rt = newStreamTestRouter(t, nil, tg, 0)
+	rt.replyParseMode = parseModeHTML
+	rt.newReplyStream("alice", "123:abc", replyTarget{ChatID: 1}).finish("**bold**", nil)
+	assert.Equal(t, []string{"send:**bold**"}, tg.calls)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+)
+
+const (
+	chatMessageMaxBytes = 64 << 10
+	// webChatID is the chat web chat messages are metered under
+	webChatID = "web"
+)
+
+var chatPageTmpl = template.Must(template.New("chat").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Chat with {{.TenantID}}</title>
+<style>
+html,body{height:100%;margin:0}
+body{font-family:system-ui,sans-serif;display:flex;flex-direction:column;max-width:40rem;margin:0 auto;color:#222}
+#log{flex:1;overflow-y:auto;padding:1rem}
+.msg{white-space:pre-wrap;padding:.5rem .8rem;border-radius:.8rem;margin:.4rem 0;max-width:85%;width:fit-content}
+.user{background:#2a7;color:#fff;margin-left:auto}.agent{background:#eee}
+.status{color:#666;font-style:italic;background:none}.error{background:#fdd;color:#900}
+form{display:flex;gap:.5rem;padding:1rem;border-top:1px solid #ddd}
+textarea{flex:1;font:inherit;padding:.5rem;resize:none}
+</style>
+</head>
+<body>
+<div id="log"></div>
+<form id="form"><textarea id="text" rows="2" placeholder="Message {{.TenantID}}" autofocus></textarea><button>Send</button></form>
+<script>
+var log = document.getElementById("log"), form = document.getElementById("form"), input = document.getElementById("text");
+var endpoint = location.pathname.replace(/\/$/, "") + "/messages" + location.search;
+
+function bubble(cls, text) {
+	var el = document.createElement("div");
+	el.className = "msg " + cls;
+	el.textContent = text;
+	log.appendChild(el);
+	log.scrollTop = log.scrollHeight;
+	return el;
+}
+
+async function send(text) {
+	bubble("user", text);
+	var out = bubble("status", "…");
+	var res = await fetch(endpoint, {method: "POST", headers: {"Content-Type": "application/json"}, body: JSON.stringify({message: text})});
+	if (!res.ok) {
+		out.className = "msg error";
+		out.textContent = (await res.text()).trim() || res.statusText;
+		return;
+	}
+	var reader = res.body.pipeThrough(new TextDecoderStream()).getReader(), buf = "";
+	for (;;) {
+		var chunk = await reader.read();
+		if (chunk.done) break;
+		buf += chunk.value;
+		var i;
+		while ((i = buf.indexOf("\n\n")) >= 0) {
+			var event = "message", data = "";
+			buf.slice(0, i).split("\n").forEach(function (line) {
+				if (line.indexOf("event: ") === 0) event = line.slice(7);
+				if (line.indexOf("data: ") === 0) data += line.slice(6);
+			});
+			buf = buf.slice(i + 2);
+			var ev = JSON.parse(data);
+			if (event === "done" && !ev.text) continue;
+			if (event !== "status" && out.classList.contains("status")) {
+				out.className = "msg " + (event === "error" ? "error" : "agent");
+			}
+			out.textContent = ev.text;
+			log.scrollTop = log.scrollHeight;
+		}
+	}
+	if (out.classList.contains("status")) out.remove();
+}
+
+form.addEventListener("submit", function (e) {
+	e.preventDefault();
+	var text = input.value.trim();
+	if (!text) return;
+	input.value = "";
+	send(text).catch(function (err) { bubble("error", String(err)); });
+});
+input.addEventListener("keydown", function (e) {
+	if (e.key === "Enter" && !e.shiftKey) { e.preventDefault(); form.requestSubmit(); }
+});
+</script>
+</body>
+</html>
+`))
+
+// chatEvent is the data of one server-sent event answering a web chat
+// message. The event is "status" (starting up, restoring state), "reply"
+// (the reply so far, as the pod streams it), "done" (the whole reply) or
+// "error" (what went wrong, for the user).
+type chatEvent struct {
+	Text string `json:"text"`
+}
+
+// verifyChatLink checks the ?token= of a request for tenantID's web chat,
+// issued by POST /tenants/{id}/chat-link, and answers the request itself if
+// it isn't valid
+func (rt *Router) verifyChatLink(w http.ResponseWriter, r *http.Request, tenantID string) bool {
+	id, err := statuspage.VerifyChat(rt.chatSecret, rt.env, r.URL.Query().Get("token"), time.Now())
+	if errors.Is(err, statuspage.ErrExpired) {
+		http.Error(w, "this chat link has expired", http.StatusGone)
+		return false
+	}
+	if err != nil || id != tenantID {
+		http.NotFound(w, r)
+		return false
+	}
+	return true
+}
+
+// chatPageHandler serves a minimal web chat with the tenant's agent to
+// holders of a link issued by POST /tenants/{id}/chat-link. The page posts
+// to chatMessageHandler with the same token.
+// Path: GET /chat/{tenantID}?token=
+func (rt *Router) chatPageHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	if !rt.verifyChatLink(w, r, tenantID) {
+		return
+	}
+	// The token is in the URL: keep it out of caches and Referer headers
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := chatPageTmpl.Execute(w, struct{ TenantID string }{tenantID}); err != nil {
+		slog.Warn("chat page: render failed", "tenant", tenantID, "err", err)
+	}
+}
+
+// chatMessageHandler delivers a web chat message to the tenant's pod through
+// the same wake and forward path as Telegram updates, and streams the
+// progress and reply back as server-sent events (see chatEvent). Messages
+// count against the tenant's rate limit and are metered as chat "web".
+// Path: POST /chat/{tenantID}/messages?token= with {"message": "..."}
+func (rt *Router) chatMessageHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	if !rt.verifyChatLink(w, r, tenantID) {
+		return
+	}
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, chatMessageMaxBytes)).Decode(&req); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(req.Message)
+	if text == "" {
+		http.Error(w, "message required", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), podReadyWait+30*time.Second)
+	defer cancel()
+	// Only the home region may wake the pod, and unlike a Telegram update a
+	// browser's request can't be relayed there and answered later
+	if home := rt.remoteHome(ctx, tenantID); home != "" {
+		http.Error(w, fmt.Sprintf("this agent is served from region %s; use a chat link issued there", home), http.StatusMisdirectedRequest)
+		return
+	}
+	if !rt.allowUpdate(ctx, tenantID) {
+		metrics.RouterRateLimited.WithLabelValues(rt.env, tenantID).Inc()
+		http.Error(w, rt.msg(ctx, tenantID, i18n.SlowDown), http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no") // don't let a proxy hold the stream back
+	w.WriteHeader(http.StatusOK)
+	send := func(event, text string) {
+		data, _ := json.Marshal(chatEvent{Text: text})
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	if message, on := rt.killSwitch(ctx); on {
+		if message == "" {
+			message = rt.msg(ctx, tenantID, i18n.Unavailable)
+		}
+		send("error", message)
+		return
+	}
+	podIP, ttl, err := rt.resolveEndpoint(ctx, tenantID, func(key i18n.Key) {
+		send("status", rt.msg(ctx, tenantID, key))
+	})
+	if err != nil {
+		send("error", rt.msg(ctx, tenantID, wakeFailedMessage(err)))
+		return
+	}
+	progress := rt.limitProgress(ctx, tenantID, func(reply string) { send("reply", reply) })
+	reply, err := rt.askPod(ctx, podIP, tenantID, podMessage{Message: text}, ttl, progress)
+	if err != nil {
+		send("error", rt.msg(ctx, tenantID, i18n.StartFailed))
+		return
+	}
+	send("done", rt.limitReply(ctx, tenantID, reply.Text))
+
+	vol := newMessageVolume(text, reply.Text)
+	vol.ChatID = webChatID
+	rt.updateActivity(tenantID, vol)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebChat: a message wakes the pod and the streamed reply comes back as
+// server-sent events, metered as the web chat
+func TestWebChat(t *testing.T) {
+	rt := newStreamTestRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg podMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		assert.Equal(t, "hi", msg.Message)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, d := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"delta\":%q}\n\n", d)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}, &telegramRecorder{}, 0)
+	activity := make(chan messageVolume, 1)
+	orch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/wake/"):
+			fmt.Fprint(w, `{"pod_ip":"10.0.0.1"}`)
+		case r.URL.Path == "/tenants/alice/activity":
+			var vol messageVolume
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&vol))
+			activity <- vol
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer orch.Close()
+	rt.orchestratorAddr = orch.URL
+	secret := []byte("s3cret")
+	rt.chatSecret = secret
+	r := chi.NewRouter()
+	rt.routes(r, nil)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	token := url.QueryEscape(statuspage.SignChat(secret, "", "alice", time.Now().Add(time.Hour)))
+	rec := do(http.MethodGet, "/chat/alice?token="+token, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	rec = do(http.MethodPost, "/chat/alice/messages?token="+token, `{"message":" hi "}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "event: status\ndata: {\"text\":\"⏳ Starting up, please wait a moment...\"}\n\n"+
+		"event: reply\ndata: {\"text\":\"Hel\"}\n\n"+
+		"event: reply\ndata: {\"text\":\"Hello\"}\n\n"+
+		"event: done\ndata: {\"text\":\"Hello\"}\n\n", rec.Body.String())
+	assert.Equal(t, messageVolume{CharsIn: 2, CharsOut: 5, ChatID: "web"}, <-activity)
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/chat/alice/messages?token="+token, `{"message":" "}`).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/chat/bob?token="+token, "").Code, "a link only opens its own tenant's chat")
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/chat/alice", "").Code)
+	status := url.QueryEscape(statuspage.Sign(secret, "", "alice", time.Now().Add(time.Hour)))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/chat/alice?token="+status, "").Code, "status links don't open the chat")
+	expired := url.QueryEscape(statuspage.SignChat(secret, "", "alice", time.Now().Add(-time.Minute)))
+	assert.Equal(t, http.StatusGone, do(http.MethodPost, "/chat/alice/messages?token="+expired, `{"message":"hi"}`).Code)
+
+	// Without a secret there is no web chat
+	r = chi.NewRouter()
+	(&Router{rdb: rt.rdb}).routes(r, nil)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/chat/alice?token="+token, "").Code)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/pkg/client"
+)
+
+const webhookSecretPrefix = contract.WebhookSecretPrefix
+
+// checkWebhookSecret reports whether an update carries the tenant's webhook
+// secret_token. Tenants whose webhook predates secrets have none and pass
+// until it is re-registered. err is set if the secret couldn't be looked up.
+func (rt *Router) checkWebhookSecret(ctx context.Context, tenantID, got string) (bool, error) {
+	want, err := rt.getWebhookSecret(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+	return want == "" || subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1, nil
+}
+
+// getWebhookSecret returns the tenant's secret_token ("" if it has none),
+// cached like bot tokens. An empty secret is cached too, so legacy tenants
+// don't cost an orchestrator call per update.
+func (rt *Router) getWebhookSecret(ctx context.Context, tenantID string) (string, error) {
+	key := rt.key(webhookSecretPrefix, tenantID)
+	if secret, err := rt.rdb.Get(ctx, key).Result(); err == nil {
+		return secret, nil
+	}
+	secret, err := rt.fetchWebhookSecret(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	rt.rdb.Set(ctx, key, secret, botTokenCacheTTL)
+	return secret, nil
+}
+
+func (rt *Router) fetchWebhookSecret(ctx context.Context, tenantID string) (string, error) {
+	rec, err := rt.orchestrator().GetBotToken(ctx, tenantID)
+	if client.StatusCode(err) == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return rec.WebhookSecret, nil
+}
+
+// storeWebhookSecret saves a newly registered secret_token in the registry
+func (rt *Router) storeWebhookSecret(ctx context.Context, tenantID, secret string) error {
+	return rt.orchestrator().PutWebhookSecret(ctx, tenantID, secret)
+}
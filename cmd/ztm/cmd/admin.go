@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/shawn/agentic-tenancy/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dashboardFile  = "router-dashboard.json"
+	alertRulesFile = "router-alerts.yaml"
+)
+
+var dashboardsOutDir string
+
+func newAdminDashboardsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboards",
+		Short: "Generate the Grafana dashboard and Prometheus alert rules",
+		Long: `Write the router's Grafana dashboard (` + dashboardFile + `) and Prometheus
+alert rules (` + alertRulesFile + `) to --out.
+
+Both are generated from the same definitions as the metrics the router
+exports, so they match the running version. Import the dashboard in Grafana
+(Dashboards > New > Import) and load the rules with your Prometheus
+rule_files or a PrometheusRule resource. Nothing talks to the cluster.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			dashboard, err := metrics.DashboardJSON()
+			if err != nil {
+				return fmt.Errorf("render dashboard: %w", err)
+			}
+			rules, err := metrics.AlertRulesYAML()
+			if err != nil {
+				return fmt.Errorf("render alert rules: %w", err)
+			}
+
+			if err := os.MkdirAll(dashboardsOutDir, 0o755); err != nil {
+				return err
+			}
+			for name, data := range map[string][]byte{dashboardFile: dashboard, alertRulesFile: rules} {
+				path := filepath.Join(dashboardsOutDir, name)
+				if err := os.WriteFile(path, data, 0o644); err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to write %s: %v", path, err))
+					return err
+				}
+			}
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Wrote %s and %s to %s",
+				dashboardFile, alertRulesFile, dashboardsOutDir))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dashboardsOutDir, "out", ".", "Directory to write the files to")
+	return cmd
+}
+
+func newAdminCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Platform administration",
+	}
+
+	cmd.AddCommand(newAdminDashboardsCmd())
+	cmd.AddCommand(newAdminRolloutCmd(client))
+	cmd.AddCommand(newAdminKillSwitchCmd(client))
+	cmd.AddCommand(newAdminBroadcastCmd(client))
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// broadcastPollInterval is how often --wait checks on the broadcast
+var broadcastPollInterval = 5 * time.Second
+
+var broadcastWait bool
+
+func newAdminBroadcastCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broadcast <text>",
+		Short: "Send an announcement to every tenant's owner",
+		Long: `Send an announcement, such as a maintenance window or a new feature, to the
+owner of every tenant in the environment.
+
+Owners are reached like log forwarding notifications: through the tenant's
+--log-chat (via its own bot) and/or --log-webhook. Tenants without either are
+listed as having no channel, and owners who opted out with
+'ztm tenant update --no-announcements' are skipped. The orchestrator paces
+deliveries at BROADCAST_RATE per second; follow it with --wait or
+'ztm admin broadcast status' for the delivery report.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			broadcast, err := client.StartBroadcast(ctx, args[0])
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to start broadcast: %v", err))
+				return err
+			}
+			if !broadcastWait {
+				if outputFormat == "json" {
+					return printBroadcastJSON(cmd, broadcast)
+				}
+				styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Broadcast %s started", broadcast.ID))
+				return nil
+			}
+
+			for broadcast.Status == "running" {
+				time.Sleep(broadcastPollInterval)
+				ctx, cancel := commandContext(defaultTimeout)
+				broadcast, err = client.GetBroadcast(ctx)
+				cancel()
+				if err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get broadcast: %v", err))
+					return err
+				}
+				if outputFormat != "json" && broadcast.Status == "running" {
+					fmt.Fprintf(cmd.OutOrStdout(), "%d/%d delivered\n", len(broadcast.Sent), broadcast.Total)
+				}
+			}
+			return printBroadcast(cmd, styler, broadcast)
+		},
+	}
+	cmd.Flags().BoolVar(&broadcastWait, "wait", false, "Wait for every delivery to be tried")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the current or last broadcast's delivery report",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			broadcast, err := client.GetBroadcast(ctx)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get broadcast: %v", err))
+				return err
+			}
+			return printBroadcast(cmd, styler, broadcast)
+		},
+	})
+	return cmd
+}
+
+// printBroadcast prints a broadcast's delivery report, or its JSON with -o json
+func printBroadcast(cmd *cobra.Command, styler *output.Styler, broadcast *api.Broadcast) error {
+	if outputFormat == "json" {
+		return printBroadcastJSON(cmd, broadcast)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Broadcast:     %s\n", broadcast.ID)
+	fmt.Fprintf(out, "Status:        %s\n", broadcast.Status)
+	fmt.Fprintf(out, "Started:       %s\n", broadcast.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "Delivered:     %d/%d\n", len(broadcast.Sent), broadcast.Total)
+	printRolloutTenants(out, "Opted Out:", broadcast.OptedOut)
+	printRolloutTenants(out, "No Channel:", broadcast.NoChannel)
+	for _, f := range broadcast.Failed {
+		fmt.Fprintf(out, "%-14s %s: %s\n", "Failed:", f.TenantID, f.Error)
+	}
+	if broadcast.Error != "" {
+		styler.FprintError(cmd.OutOrStderr(), broadcast.Error)
+	}
+	if broadcast.Status == "failed" {
+		return fmt.Errorf("broadcast %s failed", broadcast.ID)
+	}
+	return nil
+}
+
+func printBroadcastJSON(cmd *cobra.Command, broadcast *api.Broadcast) error {
+	jsonStr, err := output.FormatJSON(broadcast)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+	return nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminBroadcastCommand_Wait(t *testing.T) {
+	broadcastPollInterval = time.Millisecond
+	defer func() {
+		broadcastPollInterval = 5 * time.Second
+		broadcastWait = false
+	}()
+
+	polls := 0
+	mockClient := &api.MockClient{
+		StartBroadcastFunc: func(ctx stdcontext.Context, text string) (*api.Broadcast, error) {
+			assert.Equal(t, "Maintenance Sunday 02:00 UTC", text)
+			return &api.Broadcast{ID: "b1", Status: "running", Text: text}, nil
+		},
+		GetBroadcastFunc: func(ctx stdcontext.Context) (*api.Broadcast, error) {
+			polls++
+			if polls < 2 {
+				return &api.Broadcast{ID: "b1", Status: "running", Total: 4, Sent: []string{"alice"}}, nil
+			}
+			return &api.Broadcast{ID: "b1", Status: "done", Total: 4, Sent: []string{"alice"},
+				OptedOut: []string{"bob"}, NoChannel: []string{"carol"},
+				Failed: []api.BroadcastFailure{{TenantID: "dave", Error: "telegram: chat not found"}}}, nil
+		},
+	}
+
+	cmd := newAdminBroadcastCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"Maintenance Sunday 02:00 UTC", "--wait"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, 2, polls)
+	assert.Contains(t, buf.String(), "1/4 delivered")
+	assert.Contains(t, buf.String(), "Delivered:     1/4")
+	assert.Contains(t, buf.String(), "Opted Out:     1 [bob]")
+	assert.Contains(t, buf.String(), "No Channel:    1 [carol]")
+	assert.Contains(t, buf.String(), "dave: telegram: chat not found")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	killSwitchReason  string
+	killSwitchMessage string
+)
+
+func newAdminKillSwitchCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "killswitch",
+		Short: "Show, or turn on or off, the kill switch",
+		Long: `Show whether the environment's kill switch is on.
+
+While it is on, routers stop forwarding messages to every tenant and answer
+users with an outage message (--message, or a localized "temporarily
+unavailable"), and the orchestrator refuses wakes. Running pods are left
+alone, so 'ztm admin killswitch off' resumes service where it stopped. Both
+changes are logged by the orchestrator with the API key that made them.
+
+To stop a single tenant instead, use 'ztm tenant update <id> --disabled'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			ks, err := client.GetKillSwitch(ctx)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get kill switch: %v", err))
+				return err
+			}
+			return printKillSwitch(cmd, ks)
+		},
+	}
+
+	on := &cobra.Command{
+		Use:   "on",
+		Short: "Stop all forwarding and wakes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setKillSwitch(cmd, client, &api.SetKillSwitchRequest{Active: true, Reason: killSwitchReason, Message: killSwitchMessage})
+		},
+	}
+	on.Flags().StringVar(&killSwitchReason, "reason", "", "Why, for the audit log (required)")
+	on.Flags().StringVar(&killSwitchMessage, "message", "", "Reply users get instead of the default outage message")
+	on.MarkFlagRequired("reason")
+
+	cmd.AddCommand(on)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "off",
+		Short: "Resume forwarding and wakes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setKillSwitch(cmd, client, &api.SetKillSwitchRequest{})
+		},
+	})
+	return cmd
+}
+
+func setKillSwitch(cmd *cobra.Command, client api.Client, req *api.SetKillSwitchRequest) error {
+	styler := output.NewStyler(noColor)
+
+	ctx, cancel := commandContext(defaultTimeout)
+	defer cancel()
+
+	ks, err := client.SetKillSwitch(ctx, req)
+	if err != nil {
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to set kill switch: %v", err))
+		return err
+	}
+	if outputFormat == "json" {
+		return printKillSwitch(cmd, ks)
+	}
+	if ks.Active {
+		styler.FprintSuccess(cmd.OutOrStdout(), "Kill switch on: forwarding and wakes stopped")
+	} else {
+		styler.FprintSuccess(cmd.OutOrStdout(), "Kill switch off: service resumed")
+	}
+	return nil
+}
+
+// printKillSwitch prints the kill switch's state, or its JSON with -o json
+func printKillSwitch(cmd *cobra.Command, ks *api.KillSwitch) error {
+	out := cmd.OutOrStdout()
+	if outputFormat == "json" {
+		jsonStr, err := output.FormatJSON(ks)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintln(out, jsonStr)
+		return nil
+	}
+	if !ks.Active {
+		fmt.Fprintln(out, "Kill switch:   off")
+		return nil
+	}
+	fmt.Fprintln(out, "Kill switch:   ON")
+	fmt.Fprintf(out, "Reason:        %s\n", ks.Reason)
+	if ks.Message != "" {
+		fmt.Fprintf(out, "Message:       %s\n", ks.Message)
+	}
+	if ks.ActivatedBy != "" {
+		fmt.Fprintf(out, "Activated By:  %s\n", ks.ActivatedBy)
+	}
+	fmt.Fprintf(out, "Activated At:  %s\n", ks.ActivatedAt.Format(time.RFC3339))
+	return nil
+}
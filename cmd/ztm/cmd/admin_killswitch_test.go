@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminKillSwitchCommand_On(t *testing.T) {
+	defer func() { killSwitchReason, killSwitchMessage = "", "" }()
+
+	var got *api.SetKillSwitchRequest
+	mockClient := &api.MockClient{
+		SetKillSwitchFunc: func(ctx stdcontext.Context, req *api.SetKillSwitchRequest) (*api.KillSwitch, error) {
+			got = req
+			return &api.KillSwitch{Active: true, Reason: req.Reason}, nil
+		},
+	}
+
+	cmd := newAdminKillSwitchCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"on", "--reason", "leaked token", "--message", "Back soon"})
+	require.NoError(t, cmd.Execute())
+	require.NotNil(t, got)
+	assert.True(t, got.Active)
+	assert.Equal(t, "leaked token", got.Reason)
+	assert.Equal(t, "Back soon", got.Message)
+
+	cmd = newAdminKillSwitchCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"on"})
+	assert.Error(t, cmd.Execute(), "--reason is required")
+}
+
+func TestAdminKillSwitchCommand_Status(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetKillSwitchFunc: func(ctx stdcontext.Context) (*api.KillSwitch, error) {
+			return &api.KillSwitch{Active: true, Reason: "leaked token", ActivatedBy: "oncall",
+				ActivatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}, nil
+		},
+	}
+
+	cmd := newAdminKillSwitchCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Kill switch:   ON")
+	assert.Contains(t, buf.String(), "Activated By:  oncall")
+}
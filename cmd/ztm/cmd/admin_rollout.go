@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// rolloutPollInterval is how often --wait checks on the rollout
+var rolloutPollInterval = 5 * time.Second
+
+var (
+	rolloutMaxUnavailable int
+	rolloutWait           bool
+)
+
+func newAdminRolloutCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Restart running tenants onto their current image",
+		Long: `Start a rolling restart of every running tenant whose pod runs another image
+than it would get on a fresh wake (its pin, else the default channel, else
+ZEROCLAW_IMAGE), e.g. after upgrading ZEROCLAW_IMAGE or moving a channel.
+
+Pods are replaced blue/green, like 'ztm tenant restart', --max-unavailable at
+a time (default: the orchestrator's ROLLOUT_MAX_UNAVAILABLE). Tenants busy
+waking or restarting are skipped. The rollout stops starting replacements
+after the first failure. It runs in the orchestrator; follow it with --wait
+or 'ztm admin rollout status'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			rollout, err := client.StartRollout(ctx, rolloutMaxUnavailable)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to start rollout: %v", err))
+				return err
+			}
+			if !rolloutWait {
+				if outputFormat == "json" {
+					return printRolloutJSON(cmd, rollout)
+				}
+				styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Rollout %s started (max unavailable %d)",
+					rollout.ID, rollout.MaxUnavailable))
+				return nil
+			}
+
+			for rollout.Status == "running" {
+				time.Sleep(rolloutPollInterval)
+				ctx, cancel := commandContext(defaultTimeout)
+				rollout, err = client.GetRollout(ctx)
+				cancel()
+				if err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get rollout: %v", err))
+					return err
+				}
+				if outputFormat != "json" && rollout.Status == "running" {
+					fmt.Fprintf(cmd.OutOrStdout(), "%d/%d restarted\n", len(rollout.Restarted), rollout.Total)
+				}
+			}
+			return printRollout(cmd, styler, rollout)
+		},
+	}
+	cmd.Flags().IntVar(&rolloutMaxUnavailable, "max-unavailable", 0, "Pods to replace at once (default: the orchestrator's setting)")
+	cmd.Flags().BoolVar(&rolloutWait, "wait", false, "Wait for the rollout to finish")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the current or last rollout",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			rollout, err := client.GetRollout(ctx)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get rollout: %v", err))
+				return err
+			}
+			return printRollout(cmd, styler, rollout)
+		},
+	})
+	return cmd
+}
+
+// printRollout prints a rollout's progress, or its JSON with -o json
+func printRollout(cmd *cobra.Command, styler *output.Styler, rollout *api.Rollout) error {
+	if outputFormat == "json" {
+		return printRolloutJSON(cmd, rollout)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Rollout:       %s\n", rollout.ID)
+	fmt.Fprintf(out, "Status:        %s\n", rollout.Status)
+	fmt.Fprintf(out, "Started:       %s\n", rollout.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "Restarted:     %d/%d\n", len(rollout.Restarted), rollout.Total)
+	printRolloutTenants(out, "Skipped:", rollout.Skipped)
+	printRolloutTenants(out, "Failed:", rollout.Failed)
+	if rollout.Error != "" {
+		styler.FprintError(cmd.OutOrStderr(), rollout.Error)
+	}
+	if rollout.Status == "failed" {
+		return fmt.Errorf("rollout %s failed", rollout.ID)
+	}
+	return nil
+}
+
+func printRolloutTenants(out io.Writer, label string, tenants []string) {
+	if len(tenants) > 0 {
+		fmt.Fprintf(out, "%-14s %d %v\n", label, len(tenants), tenants)
+	}
+}
+
+func printRolloutJSON(cmd *cobra.Command, rollout *api.Rollout) error {
+	jsonStr, err := output.FormatJSON(rollout)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminRolloutCommand_Wait(t *testing.T) {
+	rolloutPollInterval = time.Millisecond
+	defer func() {
+		rolloutPollInterval = 5 * time.Second
+		rolloutWait, rolloutMaxUnavailable = false, 0
+	}()
+
+	polls := 0
+	mockClient := &api.MockClient{
+		StartRolloutFunc: func(ctx stdcontext.Context, maxUnavailable int) (*api.Rollout, error) {
+			assert.Equal(t, 3, maxUnavailable)
+			return &api.Rollout{ID: "r1", Status: "running", MaxUnavailable: 3}, nil
+		},
+		GetRolloutFunc: func(ctx stdcontext.Context) (*api.Rollout, error) {
+			polls++
+			if polls < 2 {
+				return &api.Rollout{ID: "r1", Status: "running", Total: 2, Restarted: []string{"alice"}}, nil
+			}
+			return &api.Rollout{ID: "r1", Status: "done", Total: 2, Restarted: []string{"alice", "bob"}}, nil
+		},
+	}
+
+	cmd := newAdminRolloutCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--max-unavailable", "3", "--wait"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, 2, polls)
+	assert.Contains(t, buf.String(), "1/2 restarted")
+	assert.Contains(t, buf.String(), "Restarted:     2/2")
+}
+
+func TestAdminRolloutStatusCommand_Failed(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetRolloutFunc: func(ctx stdcontext.Context) (*api.Rollout, error) {
+			return &api.Rollout{ID: "r1", Status: "failed", Total: 2, Restarted: []string{},
+				Failed: []string{"alice"}, Error: "alice: replacement pod not ready"}, nil
+		},
+	}
+
+	cmd := newAdminRolloutCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"status"})
+	assert.Error(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Failed:        1 [alice]")
+}
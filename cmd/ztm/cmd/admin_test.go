@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminDashboardsCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "obs")
+
+	cmd := newAdminDashboardsCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--out", dir})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, dashboardFile))
+	require.NoError(t, err)
+	var dashboard map[string]any
+	require.NoError(t, json.Unmarshal(data, &dashboard))
+	assert.Equal(t, "agentic-tenancy-router", dashboard["uid"])
+
+	rules, err := os.ReadFile(filepath.Join(dir, alertRulesFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(rules), "alert: RouterHighErrorRate")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+)
+
+// cachePath is the tenant inventory cache `tenant list` refreshes and
+// --cached reads. Empty disables the cache.
+var cachePath = defaultCachePath()
+
+func defaultCachePath() string {
+	if p := os.Getenv("ZTM_CACHE"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ztm", "cache.json")
+}
+
+// inventory is the tenant list last fetched from one cluster and environment
+type inventory struct {
+	RefreshedAt time.Time    `json:"refreshed_at"`
+	Tenants     []api.Tenant `json:"tenants"`
+}
+
+// inventoryCache holds an inventory per kubectl context (or orchestrator
+// URL) and environment, so switching --context, --orchestrator-url or --env
+// doesn't show another control plane's tenants
+type inventoryCache struct {
+	Inventories map[string]*inventory `json:"inventories"`
+}
+
+// inventoryKey names the inventory of the current --context (or
+// --orchestrator-url) and --env
+func inventoryKey() string {
+	if orchestratorURL != "" {
+		return orchestratorURL + "/" + env
+	}
+	return context + "/" + env
+}
+
+func readInventoryCache() (*inventoryCache, error) {
+	c := &inventoryCache{Inventories: map[string]*inventory{}}
+	data, err := os.ReadFile(cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("corrupt cache %s: %w", cachePath, err)
+	}
+	if c.Inventories == nil {
+		c.Inventories = map[string]*inventory{}
+	}
+	return c, nil
+}
+
+// saveInventory replaces the current inventory with tenants
+func saveInventory(tenants []api.Tenant, now time.Time) error {
+	if cachePath == "" {
+		return nil
+	}
+	c, err := readInventoryCache()
+	if err != nil {
+		// Start over rather than keep failing on a corrupt file
+		c = &inventoryCache{Inventories: map[string]*inventory{}}
+	}
+	c.Inventories[inventoryKey()] = &inventory{RefreshedAt: now.UTC(), Tenants: tenants}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		return err
+	}
+	// Write and rename so a concurrent --cached never reads half a file
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".cache-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cachePath)
+}
+
+// loadInventory returns the current inventory, or an error saying how to
+// fill it
+func loadInventory() (*inventory, error) {
+	if cachePath == "" {
+		return nil, fmt.Errorf("no tenant cache: home directory unknown (set ZTM_CACHE)")
+	}
+	c, err := readInventoryCache()
+	if err != nil {
+		return nil, err
+	}
+	inv, ok := c.Inventories[inventoryKey()]
+	if !ok {
+		return nil, fmt.Errorf("no cached tenants for this context and environment; run 'ztm tenant list' while the orchestrator is reachable")
+	}
+	return inv, nil
+}
+
+// staleness describes how old inv is, e.g. "cached 2024-05-01T10:00:00Z, 3h12m ago"
+func (inv *inventory) staleness(now time.Time) string {
+	age := now.Sub(inv.RefreshedAt).Truncate(time.Second)
+	return fmt.Sprintf("cached %s, %s ago", inv.RefreshedAt.Format(time.RFC3339), age)
+}
+
+// sortTenants orders cached tenants as the orchestrator's ListTenants would
+func sortTenants(tenants []api.Tenant, by string, desc bool) error {
+	var less func(a, b *api.Tenant) bool
+	switch by {
+	case "", "tenant_id":
+		less = func(a, b *api.Tenant) bool { return a.TenantID < b.TenantID }
+	case "last_active_at":
+		less = func(a, b *api.Tenant) bool { return a.LastActiveAt.Before(b.LastActiveAt) }
+	case "created_at":
+		less = func(a, b *api.Tenant) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "status":
+		less = func(a, b *api.Tenant) bool { return a.Status < b.Status }
+	default:
+		return fmt.Errorf("sort must be one of tenant_id, last_active_at, created_at, status")
+	}
+	sort.SliceStable(tenants, func(i, j int) bool {
+		a, b := &tenants[i], &tenants[j]
+		if desc {
+			a, b = b, a
+		}
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return a.TenantID < b.TenantID
+	})
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain keeps every test's `tenant list` away from the real ~/.ztm
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "ztm-cache")
+	if err != nil {
+		panic(err)
+	}
+	cachePath = filepath.Join(dir, "cache.json")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestTenantList_CachedAfterOutage(t *testing.T) {
+	cachePath = filepath.Join(t.TempDir(), ".ztm", "cache.json")
+	up := true
+	mockClient := &api.MockClient{
+		ListTenantsFunc: func(ctx stdcontext.Context, opts api.ListOptions) ([]api.Tenant, error) {
+			if !up {
+				return nil, errors.New("connection refused")
+			}
+			return []api.Tenant{
+				{TenantID: "bob", Status: "idle"},
+				{TenantID: "alice", Status: "running"},
+			}, nil
+		},
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newTenantListCmd(mockClient)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	_, err := run()
+	require.NoError(t, err)
+
+	up = false
+	out, err := run()
+	assert.Error(t, err)
+	assert.Contains(t, out, "available with --cached")
+
+	out, err = run("--cached")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Orchestrator not contacted")
+	assert.Contains(t, out, "ago")
+	assert.Less(t, bytes.Index([]byte(out), []byte("alice")), bytes.Index([]byte(out), []byte("bob")),
+		"cached tenants are sorted like the orchestrator sorts them")
+}
+
+func TestTenantList_CachedPerEnvironment(t *testing.T) {
+	cachePath = filepath.Join(t.TempDir(), "cache.json")
+	defer func() { env = "" }()
+	require.NoError(t, saveInventory([]api.Tenant{{TenantID: "alice"}}, time.Now()))
+
+	env = "staging"
+	_, err := loadInventory()
+	assert.ErrorContains(t, err, "no cached tenants")
+	require.NoError(t, saveInventory([]api.Tenant{{TenantID: "carol"}}, time.Now()))
+
+	env = ""
+	inv, err := loadInventory()
+	require.NoError(t, err)
+	require.Len(t, inv.Tenants, 1)
+	assert.Equal(t, "alice", inv.Tenants[0].TenantID, "refreshing staging kept the default inventory")
+}
+
+func TestTenantGet_Cached(t *testing.T) {
+	cachePath = filepath.Join(t.TempDir(), "cache.json")
+	refreshed := time.Now().Add(-3 * time.Hour)
+	require.NoError(t, saveInventory([]api.Tenant{{TenantID: "alice", Status: "running", PodIP: "10.0.0.1"}}, refreshed))
+	mockClient := &api.MockClient{
+		GetTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
+			t.Fatal("--cached must not call the orchestrator")
+			return nil, nil
+		},
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newTenantGetCmd(mockClient)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("alice", "--cached")
+	require.NoError(t, err)
+	assert.Contains(t, out, "10.0.0.1")
+	assert.Contains(t, out, "Cached At:")
+	assert.Contains(t, out, "3h0m")
+
+	outputFormat = "json"
+	defer func() { outputFormat = "table" }()
+	out, err = run("alice", "--cached")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"cached_at"`)
+
+	_, err = run("bob", "--cached")
+	assert.ErrorContains(t, err, "not in the cache")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the orchestrator call behind a tab press; a
+// shell waiting longer than this is worse than no suggestions
+const completionTimeout = 5 * time.Second
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh|fish>",
+		Short: "Generate the shell completion script",
+		Long: `Print the completion script for bash, zsh or fish.
+
+Besides commands and flags, it completes the tenant ID of 'ztm tenant get',
+'delete', 'update' and 'wake' from the orchestrator, using the current
+profile or the --profile, --orchestrator-url or --context already typed.`,
+		Example: `  # bash (needs the bash-completion package)
+  ztm completion bash > /etc/bash_completion.d/ztm
+
+  # zsh
+  ztm completion zsh > "${fpath[1]}/_ztm"
+
+  # fish
+  ztm completion fish > ~/.config/fish/completions/ztm.fish`,
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		// Doesn't call the orchestrator
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			default:
+				return root.GenFishCompletion(out, true)
+			}
+		},
+	}
+	return cmd
+}
+
+// completeTenantIDs completes a command's <tenant-id> argument with the
+// orchestrator's tenants, described by their status. Completion runs
+// without the root's PersistentPreRunE, so the client is set up here.
+func completeTenantIDs(client api.Client) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if ref, ok := client.(*clientRef); ok && ref.Client == nil {
+			if err := ref.init(cmd.Flags()); err != nil {
+				cobra.CompDebugln(err.Error(), false)
+				return nil, cobra.ShellCompDirectiveError
+			}
+		}
+
+		ctx, cancel := commandContext(completionTimeout)
+		defer cancel()
+		tenants, err := client.ListTenants(ctx, api.ListOptions{})
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var ids []string
+		for _, t := range tenants {
+			if strings.HasPrefix(t.TenantID, toComplete) {
+				ids = append(ids, t.TenantID+"\t"+t.Status)
+			}
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteTenantIDs(t *testing.T) {
+	mockClient := &api.MockClient{
+		ListTenantsFunc: func(ctx stdcontext.Context, opts api.ListOptions) ([]api.Tenant, error) {
+			return []api.Tenant{
+				{TenantID: "alice", Status: "running"},
+				{TenantID: "albert", Status: "idle"},
+				{TenantID: "bob", Status: "idle"},
+			}, nil
+		},
+	}
+	root := &cobra.Command{Use: "ztm"}
+	root.AddCommand(newTenantCmd(mockClient))
+	complete := func(args ...string) string {
+		buf := new(bytes.Buffer)
+		root.SetOut(buf)
+		root.SetErr(new(bytes.Buffer))
+		root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+		require.NoError(t, root.Execute())
+		return buf.String()
+	}
+
+	assert.Equal(t, "alice\trunning\nalbert\tidle\n:4\n", complete("tenant", "delete", "al"))
+	assert.Equal(t, ":4\n", complete("tenant", "wake", "alice", ""), "only the first argument")
+}
+
+func TestCompletionCommand(t *testing.T) {
+	root := &cobra.Command{Use: "ztm"}
+	root.AddCommand(newCompletionCmd())
+	buf := new(bytes.Buffer)
+	root.SetOut(buf)
+	root.SetArgs([]string{"completion", "fish"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, buf.String(), "complete -c ztm")
+
+	root.SetArgs([]string{"completion", "powershell"})
+	root.SilenceUsage, root.SilenceErrors = true, true
+	assert.ErrorContains(t, root.Execute(), `invalid argument "powershell"`)
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// configPath is ztm's config file of named profiles. Empty (no home
+// directory) means there is none.
+var configPath = defaultConfigPath()
+
+// profileName selects the profile for one command, instead of the config
+// file's current-context
+var profileName string
+
+func defaultConfigPath() string {
+	if p := os.Getenv("ZTM_CONFIG"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ztm", "config.yaml")
+}
+
+// ztmConfig is the config file: a profile per control plane, one of which
+// is current
+type ztmConfig struct {
+	CurrentContext string              `json:"current-context,omitempty"`
+	Profiles       map[string]*profile `json:"profiles"`
+}
+
+// profile holds a control plane's connection settings. Each one applies
+// unless its flag or ZTM_* variable is set.
+type profile struct {
+	OrchestratorURL  string `json:"orchestrator-url,omitempty"`
+	OrchestratorPort int    `json:"orchestrator-port,omitempty"`
+	RouterURL        string `json:"router-url,omitempty"`
+	Namespace        string `json:"namespace,omitempty"`
+	KubeContext      string `json:"kube-context,omitempty"`
+	Env              string `json:"env,omitempty"`
+	APIKey           string `json:"api-key,omitempty"`
+}
+
+// readConfig returns the config file, or an empty config if there is none
+func readConfig() (*ztmConfig, error) {
+	c := &ztmConfig{Profiles: map[string]*profile{}}
+	if configPath == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", configPath, err)
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]*profile{}
+	}
+	return c, nil
+}
+
+// writeConfig replaces the config file. It holds API keys, so only the
+// user may read it.
+func writeConfig(c *ztmConfig) error {
+	if configPath == "" {
+		return fmt.Errorf("no config file: home directory unknown (set ZTM_CONFIG)")
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(configPath), ".config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), configPath)
+}
+
+// applyProfile fills the global flags from the selected profile: --profile,
+// else the config file's current-context. Flags given on the command line
+// and their ZTM_* variables take precedence.
+func applyProfile(flags *pflag.FlagSet) error {
+	c, err := readConfig()
+	if err != nil {
+		return err
+	}
+	name := profileName
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found in %s", name, configPath)
+	}
+	unset := func(flag, envVar string) bool {
+		return !flags.Changed(flag) && os.Getenv(envVar) == ""
+	}
+	for _, s := range []struct {
+		flag, envVar, value string
+		dst                 *string
+	}{
+		{"orchestrator-url", "ZTM_ORCHESTRATOR_URL", p.OrchestratorURL, &orchestratorURL},
+		{"router-url", "ZTM_ROUTER_URL", p.RouterURL, &routerURL},
+		{"namespace", "ZTM_NAMESPACE", p.Namespace, &namespace},
+		{"context", "ZTM_KUBE_CONTEXT", p.KubeContext, &context},
+		{"env", "ZTM_ENV", p.Env, &env},
+		{"api-key", "ZTM_API_KEY", p.APIKey, &apiKey},
+	} {
+		if s.value != "" && unset(s.flag, s.envVar) {
+			*s.dst = s.value
+		}
+	}
+	if p.OrchestratorPort != 0 && unset("orchestrator-port", "ZTM_ORCHESTRATOR_PORT") {
+		orchestratorPort = p.OrchestratorPort
+	}
+	return nil
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage connection profiles",
+		Long: `Manage the connection profiles in ~/.ztm/config.yaml (or $ZTM_CONFIG).
+
+A profile holds one control plane's orchestrator URL and port, router URL,
+namespace, kubectl context, environment and API key, so switching between
+dev, staging and prod is one command rather than half a dozen ZTM_*
+variables:
+
+  current-context: staging
+  profiles:
+    staging:
+      orchestrator-url: https://orchestrator.staging.example.com
+      router-url: https://router.staging.example.com
+      api-key: ...
+    prod:
+      kube-context: arn:aws:eks:us-west-2:111122223333:cluster/prod
+      namespace: tenants
+
+Every command uses the current profile, or the one named by --profile. A
+flag or ZTM_* variable still overrides the profile's value.`,
+		// Doesn't call the orchestrator, and must work while the current
+		// profile is broken
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use-context <profile>",
+		Short: "Make a profile the current one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := readConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := c.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found in %s", args[0], configPath)
+			}
+			c.CurrentContext = args[0]
+			if err := writeConfig(c); err != nil {
+				return err
+			}
+			output.NewStyler(noColor).FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Switched to profile %q", args[0]))
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "current-context",
+		Short: "Print the current profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := readConfig()
+			if err != nil {
+				return err
+			}
+			if c.CurrentContext == "" {
+				return fmt.Errorf("no current profile; set one with 'ztm config use-context'")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), c.CurrentContext)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get-contexts",
+		Short: "List the profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := readConfig()
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(c.Profiles))
+			for name := range c.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CURRENT\tNAME\tTARGET\tENV")
+			for _, name := range names {
+				p := c.Profiles[name]
+				current := ""
+				if name == c.CurrentContext {
+					current = "*"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, name, p.target(), p.Env)
+			}
+			return w.Flush()
+		},
+	})
+	return cmd
+}
+
+// target describes where a profile's commands go
+func (p *profile) target() string {
+	switch {
+	case p.OrchestratorURL != "":
+		return p.OrchestratorURL
+	case p.KubeContext != "":
+		return "kubectl " + p.KubeContext
+	default:
+		return "kubectl (current context)"
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `current-context: staging
+profiles:
+  staging:
+    orchestrator-url: https://orchestrator.staging.example.com
+    router-url: https://router.staging.example.com
+    env: staging
+    api-key: s3cret
+  prod:
+    kube-context: prod-cluster
+    namespace: tenants-prod
+    orchestrator-port: 8081
+`
+
+func useTestConfig(t *testing.T, content string) {
+	t.Helper()
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+	if content != "" {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	}
+	saved := []string{orchestratorURL, routerURL, namespace, context, env, apiKey, profileName}
+	savedPort := orchestratorPort
+	t.Cleanup(func() {
+		orchestratorURL, routerURL, namespace, context, env, apiKey, profileName = saved[0], saved[1], saved[2], saved[3], saved[4], saved[5], saved[6]
+		orchestratorPort = savedPort
+	})
+}
+
+func TestApplyProfile(t *testing.T) {
+	useTestConfig(t, testConfig)
+	t.Setenv("ZTM_API_KEY", "")
+	t.Setenv("ZTM_ROUTER_URL", "https://router.override.example.com")
+	routerURL = "https://router.override.example.com"
+	flags := pflag.NewFlagSet("ztm", pflag.ContinueOnError)
+	flags.StringVar(&env, "env", "", "")
+	require.NoError(t, flags.Parse([]string{"--env", "dev"}))
+
+	require.NoError(t, applyProfile(flags))
+	assert.Equal(t, "https://orchestrator.staging.example.com", orchestratorURL)
+	assert.Equal(t, "s3cret", apiKey)
+	assert.Equal(t, "dev", env, "a flag overrides the profile")
+	assert.Equal(t, "https://router.override.example.com", routerURL, "so does a ZTM_* variable")
+
+	profileName = "prod"
+	require.NoError(t, applyProfile(pflag.NewFlagSet("ztm", pflag.ContinueOnError)))
+	assert.Equal(t, "prod-cluster", context)
+	assert.Equal(t, "tenants-prod", namespace)
+	assert.Equal(t, 8081, orchestratorPort)
+
+	profileName = "qa"
+	assert.ErrorContains(t, applyProfile(flags), `profile "qa" not found`)
+}
+
+func TestApplyProfile_NoConfig(t *testing.T) {
+	useTestConfig(t, "")
+	orchestratorURL = ""
+	assert.NoError(t, applyProfile(pflag.NewFlagSet("ztm", pflag.ContinueOnError)))
+	assert.Empty(t, orchestratorURL)
+}
+
+func TestConfigCommands(t *testing.T) {
+	useTestConfig(t, testConfig)
+	run := func(args ...string) (string, error) {
+		cmd := newConfigCmd()
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("get-contexts")
+	require.NoError(t, err)
+	assert.Contains(t, out, "kubectl prod-cluster")
+	assert.Regexp(t, `\*\s+staging\s+https://orchestrator.staging.example.com\s+staging`, out)
+	assert.NotContains(t, out, "s3cret")
+
+	_, err = run("use-context", "prod")
+	require.NoError(t, err)
+	out, err = run("current-context")
+	require.NoError(t, err)
+	assert.Equal(t, "prod\n", out)
+
+	c, err := readConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", c.Profiles["staging"].APIKey, "the other profiles are kept")
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = run("use-context", "qa")
+	assert.ErrorContains(t, err, `profile "qa" not found`)
+}
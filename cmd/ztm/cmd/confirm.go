@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// skipConfirm is --yes on the commands that ask before deleting
+var skipConfirm bool
+
+var errNotConfirmed = errors.New("not confirmed; nothing was deleted (pass --yes to skip the prompt)")
+
+// confirm asks question on stderr and reads the answer from stdin, unless
+// --yes was given. Only "y" or "yes" go ahead: anything else, including no
+// input at all, as from a script that forgot --yes, is a refusal.
+func confirm(cmd *cobra.Command, question string) error {
+	if skipConfirm {
+		return nil
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr())
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errNotConfirmed
+}
+
+func addConfirmFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Don't ask for confirmation")
+}
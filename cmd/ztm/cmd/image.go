@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newImageListCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List image aliases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			aliases, err := client.ListImages(ctx)
+			if err != nil {
+				styler := output.NewStyler(noColor)
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to list images: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(aliases)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			// Table format
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ALIAS\tIMAGE\tUPDATED")
+			for _, a := range aliases {
+				updated := "-"
+				if !a.UpdatedAt.IsZero() {
+					updated = a.UpdatedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", a.Alias, a.Image, updated)
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+}
+
+func newImageSetCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <alias> <image>",
+		Short: "Create or repoint an image alias",
+		Long: `Create or repoint an image alias (e.g. stable, beta, v1.4).
+
+Tenants following the alias run the new image on their next wake.
+Running pods are not restarted.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias, image := args[0], args[1]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			out, err := client.SetImage(ctx, alias, image)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to set image alias: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(out)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Alias '%s' now points to %s", alias, image))
+			return nil
+		},
+	}
+}
+
+func newImageDeleteCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <alias>",
+		Short: "Delete an image alias",
+		Long: `Delete an image alias. From their next wake, tenants pinned to it run the
+ZEROCLAW_IMAGE tag of the same name instead, which may not exist.
+
+Asks for confirmation first; --yes skips the question.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias := args[0]
+			if err := confirm(cmd, fmt.Sprintf("Delete image alias '%s'?", alias)); err != nil {
+				return err
+			}
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			if err := client.DeleteImage(ctx, alias); err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to delete image alias: %v", err))
+				return err
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Alias '%s' deleted", alias))
+			return nil
+		},
+	}
+	addConfirmFlag(cmd)
+	return cmd
+}
+
+func newImageCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image",
+		Short: "Manage ZeroClaw image aliases",
+		Long: `Manage the ZeroClaw version catalog.
+
+Aliases map a channel name to an image. Tenants pin an alias or an explicit
+tag with 'ztm tenant update --image'; unpinned tenants follow the
+orchestrator's default channel (ZEROCLAW_DEFAULT_CHANNEL, default: stable).`,
+	}
+
+	cmd.AddCommand(newImageListCmd(client))
+	cmd.AddCommand(newImageSetCmd(client))
+	cmd.AddCommand(newImageDeleteCmd(client))
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageListCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ListImagesFunc: func(ctx stdcontext.Context) ([]api.ImageAlias, error) {
+			return []api.ImageAlias{
+				{Alias: "beta", Image: "zeroclaw:v1.5.0-rc1", UpdatedAt: time.Now()},
+				{Alias: "stable", Image: "zeroclaw:v1.4.0", UpdatedAt: time.Now()},
+			}, nil
+		},
+	}
+
+	cmd := newImageListCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "stable")
+	assert.Contains(t, output, "zeroclaw:v1.5.0-rc1")
+}
+
+func TestImageSetCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		SetImageFunc: func(ctx stdcontext.Context, alias, image string) (*api.ImageAlias, error) {
+			assert.Equal(t, "stable", alias)
+			assert.Equal(t, "zeroclaw:v1.5.0", image)
+			return &api.ImageAlias{Alias: alias, Image: image}, nil
+		},
+	}
+
+	cmd := newImageSetCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"stable", "zeroclaw:v1.5.0"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "stable")
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/envvar"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -13,12 +16,16 @@ var (
 	buildDate string
 
 	// Global flags
-	namespace       string
-	context         string
-	orchestratorURL string
-	routerURL       string
-	outputFormat    string
-	noColor         bool
+	namespace        string
+	context          string
+	orchestratorURL  string
+	orchestratorPort int
+	routerURL        string
+	env              string
+	apiKey           string
+	outputFormat     string
+	noColor          bool
+	timeout          time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -37,24 +44,57 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&namespace, "namespace", getEnvOrDefault("ZTM_NAMESPACE", "tenants"), "Kubernetes namespace")
 	rootCmd.PersistentFlags().StringVar(&context, "context", os.Getenv("ZTM_KUBE_CONTEXT"), "kubectl context")
 	rootCmd.PersistentFlags().StringVar(&orchestratorURL, "orchestrator-url", os.Getenv("ZTM_ORCHESTRATOR_URL"), "Orchestrator HTTP URL (bypasses kubectl)")
+	rootCmd.PersistentFlags().IntVar(&orchestratorPort, "orchestrator-port", envvar.Int("ZTM_ORCHESTRATOR_PORT", 8080), "Orchestrator port inside its pod; its INTERNAL_PORT if set")
 	rootCmd.PersistentFlags().StringVar(&routerURL, "router-url", os.Getenv("ZTM_ROUTER_URL"), "Router HTTP URL")
+	rootCmd.PersistentFlags().StringVar(&env, "env", os.Getenv("ZTM_ENV"), "Control-plane environment (empty = default)")
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", os.Getenv("ZTM_API_KEY"), "Orchestrator API key (default: $ZTM_API_KEY)")
 	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "table", "Output format: json|table")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", envvar.Duration("ZTM_TIMEOUT", 0), "Deadline for the command's API calls, e.g. 10m (default: per command)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", os.Getenv("ZTM_PROFILE"), "Profile from ~/.ztm/config.yaml (default: its current-context)")
 }
 
+// clientRef is the client commands are built with, before flags are
+// parsed; it is pointed at the client the flags select once they are
+type clientRef struct {
+	api.Client
+}
+
+// init points the ref at the client the flags select, after applying the
+// selected profile
+func (r *clientRef) init(flags *pflag.FlagSet) error {
+	if err := applyProfile(flags); err != nil {
+		return err
+	}
+	r.Client = initClient()
+	return nil
+}
+
+// initClient returns the client the global flags select: direct HTTP with
+// --orchestrator-url, else kubectl exec
 func initClient() api.Client {
-	// For now, always use kubectl client
-	// HTTP client can be added later when --orchestrator-url is provided
-	return api.NewKubectlClient(namespace, context)
+	if orchestratorURL != "" {
+		return api.NewHTTPClient(orchestratorURL, routerURL, env, apiKey)
+	}
+	kc := api.NewKubectlClient(namespace, context)
+	kc.Configure(namespace, context, env, apiKey, orchestratorPort)
+	return kc
 }
 
 func Execute() error {
 	// Wire up client for all commands
-	client := initClient()
+	client := &clientRef{}
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return client.init(cmd.Flags())
+	}
 
 	// Add command groups with client
 	rootCmd.AddCommand(newTenantCmd(client))
 	rootCmd.AddCommand(newWebhookCmd(client))
+	rootCmd.AddCommand(newImageCmd(client))
+	rootCmd.AddCommand(newAdminCmd(client))
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newCompletionCmd())
 
 	return rootCmd.Execute()
 }
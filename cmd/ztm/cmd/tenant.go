@@ -9,15 +9,34 @@ func newTenantCmd(client api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "tenant",
 		Short: "Manage tenants",
-		Long:  `Create, list, get, update, and delete tenants.`,
+		Long:  `Create, import, export, list, get, describe, update, wake, send test messages to, restart, suspend, reset, and delete tenants.`,
 	}
 
 	// Add subcommands
 	cmd.AddCommand(newTenantCreateCmd(client))
+	cmd.AddCommand(newTenantImportCmd(client))
+	cmd.AddCommand(newTenantExportCmd(client))
 	cmd.AddCommand(newTenantListCmd(client))
 	cmd.AddCommand(newTenantGetCmd(client))
+	cmd.AddCommand(newTenantDescribeCmd(client))
 	cmd.AddCommand(newTenantUpdateCmd(client))
 	cmd.AddCommand(newTenantDeleteCmd(client))
+	cmd.AddCommand(newTenantRestoreCmd(client))
+	cmd.AddCommand(newTenantSnapshotCmd(client))
+	cmd.AddCommand(newTenantWakeCmd(client))
+	cmd.AddCommand(newTenantSendCmd(client))
+	cmd.AddCommand(newTenantLogsCmd(client))
+	cmd.AddCommand(newTenantExecCmd(client))
+	cmd.AddCommand(newTenantRestartCmd(client))
+	cmd.AddCommand(newTenantSleepCmd(client))
+	cmd.AddCommand(newTenantSuspendCmd(client))
+	cmd.AddCommand(newTenantResumeCmd(client))
+	cmd.AddCommand(newTenantResetCmd(client))
+	cmd.AddCommand(newTenantUsageCmd(client))
+	cmd.AddCommand(newTenantLookupCmd(client))
+	cmd.AddCommand(newTenantLinkCmd(client))
+	cmd.AddCommand(newTenantStatusLinkCmd(client))
+	cmd.AddCommand(newTenantChatLinkCmd(client))
 
 	return cmd
 }
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+var chatLinkTTL time.Duration
+
+func newTenantChatLinkCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chat-link <tenant-id>",
+		Short: "Generate a link to a tenant's web chat page",
+		Long: `Generate a signed link to the tenant's web chat page on the router.
+
+The page is a minimal chat with the tenant's agent in the browser, going
+through the same wake and forward path as Telegram messages, for trying the
+agent out or embedding it in an iframe. Anyone with the link can talk to the
+agent — waking it and counting against its quotas — until it expires
+(--ttl, default a day, at most 90 days); rotating WEB_CHAT_SECRET revokes
+every link.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			link, err := client.CreateChatLink(ctx, tenantID, chatLinkTTL)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to generate chat link: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(link)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Link:     %s\n", link.URL)
+			fmt.Fprintf(cmd.OutOrStdout(), "Expires:  %s\n", link.ExpiresAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&chatLinkTTL, "ttl", 0, "How long the link stays valid, e.g. 1h (default a day)")
+
+	return cmd
+}
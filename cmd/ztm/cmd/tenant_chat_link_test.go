@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantChatLinkCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ChatLinkFunc: func(ctx stdcontext.Context, id string, ttl time.Duration) (*api.ChatLink, error) {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, time.Hour, ttl)
+			return &api.ChatLink{
+				TenantID:  id,
+				URL:       "https://router.example.com/chat/alice?token=YWxpY2U.1767225600.sig",
+				ExpiresAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+	}
+
+	cmd := newTenantChatLinkCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--ttl", "1h"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "https://router.example.com/chat/alice?token=YWxpY2U.1767225600.sig")
+	assert.Contains(t, buf.String(), "2026-01-01T00:00:00Z")
+}
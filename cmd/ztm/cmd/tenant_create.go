@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	stdcontext "context"
 	"fmt"
 	"time"
 
@@ -10,7 +9,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var idleTimeout int
+var (
+	idleTimeout    int
+	createTier     string
+	createImage    string
+	createKeepWarm bool
+	createHome     string
+	createUpdates  []string
+	createLocale   string
+	createTimezone string
+)
 
 func newTenantCreateCmd(client api.Client) *cobra.Command {
 	cmd := &cobra.Command{
@@ -26,22 +34,31 @@ be auto-registered if ROUTER_PUBLIC_URL is configured on the orchestrator.`,
 			botToken := args[1]
 
 			styler := output.NewStyler(noColor)
-			styler.PrintInfo(fmt.Sprintf("Creating tenant '%s'...", tenantID))
+			spinner := output.NewSpinner(cmd.ErrOrStderr(), noColor)
+			spinner.Start(fmt.Sprintf("Creating tenant '%s'", tenantID))
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
 			tenant, err := client.CreateTenant(ctx, &api.CreateTenantRequest{
-				TenantID:     tenantID,
-				BotToken:     botToken,
-				IdleTimeoutS: idleTimeout,
+				TenantID:       tenantID,
+				BotToken:       botToken,
+				IdleTimeoutS:   idleTimeout,
+				Tier:           createTier,
+				Image:          createImage,
+				KeepWarm:       createKeepWarm,
+				HomeRegion:     createHome,
+				AllowedUpdates: createUpdates,
+				Locale:         createLocale,
+				Timezone:       createTimezone,
 			})
+			elapsed := spinner.Stop()
 			if err != nil {
-				styler.PrintError(fmt.Sprintf("Failed to create tenant: %v", err))
+				styler.PrintError(fmt.Sprintf("Failed to create tenant after %s: %v", output.FormatElapsed(elapsed), err))
 				return err
 			}
 
-			styler.PrintSuccess(fmt.Sprintf("Tenant '%s' created", tenantID))
+			styler.PrintSuccess(fmt.Sprintf("Tenant '%s' created in %s", tenantID, output.FormatElapsed(elapsed)))
 
 			// Format output
 			if outputFormat == "json" {
@@ -55,6 +72,9 @@ be auto-registered if ROUTER_PUBLIC_URL is configured on the orchestrator.`,
 				fmt.Fprintf(cmd.OutOrStdout(), "\nTenant ID:     %s\n", tenant.TenantID)
 				fmt.Fprintf(cmd.OutOrStdout(), "Status:        %s\n", tenant.Status)
 				fmt.Fprintf(cmd.OutOrStdout(), "Idle Timeout:  %ds\n", tenant.IdleTimeoutS)
+				if tenant.Tier != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "Tier:          %s\n", tenant.Tier)
+				}
 				if !tenant.CreatedAt.IsZero() {
 					fmt.Fprintf(cmd.OutOrStdout(), "Created At:    %s\n", tenant.CreatedAt.Format(time.RFC3339))
 				}
@@ -65,6 +85,13 @@ be auto-registered if ROUTER_PUBLIC_URL is configured on the orchestrator.`,
 	}
 
 	cmd.Flags().IntVar(&idleTimeout, "idle-timeout", 600, "Idle timeout in seconds")
+	cmd.Flags().StringVar(&createTier, "tier", "", "Service tier (default: standard)")
+	cmd.Flags().StringVar(&createImage, "image", "", "Image alias or tag to pin (default: follow the default channel)")
+	cmd.Flags().BoolVar(&createKeepWarm, "keep-warm", false, "Never stop the pod on idle timeout")
+	cmd.Flags().StringVar(&createHome, "home-region", "", "Region whose orchestrator runs the tenant's pod (default: the orchestrator's REGION)")
+	cmd.Flags().StringSliceVar(&createUpdates, "allowed-updates", nil, "Telegram update types to subscribe the bot to (default: message)")
+	cmd.Flags().StringVar(&createLocale, "locale", "", "Language for system messages, e.g. es or pt-BR (default: en)")
+	cmd.Flags().StringVar(&createTimezone, "timezone", "", "IANA time zone for timestamps in notifications, e.g. Europe/Berlin (default: UTC)")
 
 	return cmd
 }
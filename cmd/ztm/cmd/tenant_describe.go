@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// describeErrorWidth truncates wake errors and event messages so the table stays readable;
+// --output json has the full text
+const describeErrorWidth = 60
+
+func newTenantDescribeCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <tenant-id>",
+		Short: "Show tenant details, recent wakes and pod events",
+		Long: `Show a tenant's details followed by its recent wake attempts, newest first:
+when each started, whether it claimed a warm pod or cold started, how long
+it took, and why it failed if it did.
+
+Then its recent pod events: times the agent ran out of memory (oom_killed),
+started crash-looping (crash_loop) or had its pod force-deleted after it hung
+terminating (stuck_terminating), and, with orchestrator-managed state sync,
+times its state couldn't be restored from S3 (state_restore_failed) or
+flushed back (state_sync_failed), with the admission webhook, edits made
+to its pod outside the orchestrator (pod_modified), and rollbacks of its
+state to a snapshot (state_rolled_back). Repeated OOM kills mean the tenant
+needs a tier with more memory.
+
+The orchestrator keeps the last WAKE_HISTORY_SIZE attempts (default 20) and
+EVENT_HISTORY_SIZE events (default 50).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			tenant, err := client.GetTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get tenant: %v", err))
+				return err
+			}
+			wakes, err := client.ListWakes(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get wake history: %v", err))
+				return err
+			}
+			events, err := client.ListEvents(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get events: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(struct {
+					*api.Tenant
+					Wakes  []api.WakeAttempt `json:"wakes"`
+					Events []api.TenantEvent `json:"events"`
+				}{tenant, wakes, events})
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			printTenant(cmd.OutOrStdout(), tenant)
+
+			printWakes(cmd.OutOrStdout(), wakes)
+			printEvents(cmd.OutOrStdout(), events)
+			return nil
+		},
+	}
+}
+
+func printWakes(out io.Writer, wakes []api.WakeAttempt) {
+	fmt.Fprintln(out, "\nRecent Wakes:")
+	if len(wakes) == 0 {
+		fmt.Fprintln(out, "  none recorded")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  STARTED\tSTART\tDURATION\tOUTCOME\tERROR")
+	for _, wk := range wakes {
+		start := wk.Start
+		if start == "" {
+			start = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+			wk.StartedAt.Format("2006-01-02 15:04:05"), start,
+			output.FormatElapsed(time.Duration(wk.DurationMs)*time.Millisecond),
+			wk.Outcome, truncate(wk.Error, describeErrorWidth))
+	}
+	w.Flush()
+}
+
+func printEvents(out io.Writer, events []api.TenantEvent) {
+	fmt.Fprintln(out, "\nRecent Events:")
+	if len(events) == 0 {
+		fmt.Fprintln(out, "  none recorded")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TIME\tKIND\tPOD\tRESTARTS\tMESSAGE")
+	for _, ev := range events {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\n",
+			ev.Time.Format("2006-01-02 15:04:05"), ev.Kind, ev.Pod, ev.Restarts,
+			truncate(ev.Message, describeErrorWidth))
+	}
+	w.Flush()
+}
+
+// truncate shortens s to at most n runes, marking the cut with "…"
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
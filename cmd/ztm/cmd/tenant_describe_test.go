@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantDescribeCommand(t *testing.T) {
+	started := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	mockClient := &api.MockClient{
+		GetTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
+			return &api.Tenant{TenantID: id, Status: "running", IdleTimeoutS: 600}, nil
+		},
+		ListWakesFunc: func(ctx stdcontext.Context, id string) ([]api.WakeAttempt, error) {
+			assert.Equal(t, "alice", id)
+			return []api.WakeAttempt{
+				{StartedAt: started.Add(time.Hour), DurationMs: 12400, Start: "warm", Outcome: "ok"},
+				{StartedAt: started, DurationMs: 210000, Start: "cold", Outcome: "failed", Error: "wait pod ready: pod zeroclaw-alice not ready after 3m30s"},
+			}, nil
+		},
+		ListEventsFunc: func(ctx stdcontext.Context, id string) ([]api.TenantEvent, error) {
+			return []api.TenantEvent{
+				{Time: started.Add(2 * time.Hour), Kind: "oom_killed", Pod: "zeroclaw-alice", Restarts: 3, Message: "exit code 137, memory limit 512Mi"},
+			}, nil
+		},
+	}
+
+	cmd := newTenantDescribeCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	assert.NoError(t, cmd.Execute())
+	out := buf.String()
+	assert.Contains(t, out, "Tenant ID:     alice")
+	assert.Contains(t, out, "2026-03-01 10:30:00  warm   12.4s")
+	assert.Contains(t, out, "3m30s")
+	assert.Contains(t, out, "not ready after 3m30s")
+	assert.Contains(t, out, "Recent Events:")
+	assert.Contains(t, out, "2026-03-01 11:30:00  oom_killed  zeroclaw-alice  3")
+	assert.Contains(t, out, "memory limit 512Mi")
+}
+
+func TestTenantDescribeCommand_NoWakes(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantDescribeCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, 2, strings.Count(buf.String(), "none recorded"), "no wakes and no events")
+}
+
+func TestTenantDescribeCommand_Error(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
+			return nil, fmt.Errorf("API call failed: not found")
+		},
+	}
+
+	cmd := newTenantDescribeCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"ghost"})
+
+	assert.Error(t, cmd.Execute())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abcd…", truncate("abcdefgh", 5))
+}
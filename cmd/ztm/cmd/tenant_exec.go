@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	execStdin bool
+	execTTY   bool
+)
+
+func newTenantExecCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <tenant-id> -- <command> [args...]",
+		Short: "Run a command in a running tenant's agent container",
+		Long: `Run a command inside a running tenant's agent container, for debugging its
+state inside the VM. The session is relayed by the orchestrator, so no
+cluster credentials are needed, and is logged there with the API key's name.
+
+Use -i to pass your input to the command and -t for a terminal, e.g.
+'ztm tenant exec alice -it -- sh' for an interactive shell. The command's
+exit code becomes ztm's. Exec sessions need --orchestrator-url; --timeout,
+if set, ends the session.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			var ctx stdcontext.Context
+			var cancel stdcontext.CancelFunc
+			if timeout == 0 {
+				ctx, cancel = stdcontext.WithCancel(stdcontext.Background())
+			} else {
+				ctx, cancel = commandContext(timeout)
+			}
+			defer cancel()
+
+			req := api.ExecRequest{
+				Command: args[1:],
+				Stdout:  cmd.OutOrStdout(),
+				Stderr:  cmd.ErrOrStderr(),
+				TTY:     execTTY,
+			}
+			if execStdin {
+				req.Stdin = cmd.InOrStdin()
+			}
+			if f, ok := req.Stdin.(*os.File); ok && execTTY && term.IsTerminal(int(f.Fd())) {
+				state, err := term.MakeRaw(int(f.Fd()))
+				if err != nil {
+					return fmt.Errorf("failed to set raw terminal: %w", err)
+				}
+				defer term.Restore(int(f.Fd()), state)
+				req.Resize = watchTerminalSize(ctx, int(f.Fd()))
+			}
+
+			err := client.ExecTenant(ctx, tenantID, req)
+			var exit *api.ExitError
+			if err != nil && !errors.As(err, &exit) {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Exec failed: %v", err))
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&execStdin, "stdin", "i", false, "Pass stdin to the command")
+	cmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a terminal for the command")
+	return cmd
+}
+
+// watchTerminalSize sends the terminal's size now and on every SIGWINCH
+// until ctx is done
+func watchTerminalSize(ctx stdcontext.Context, fd int) <-chan api.TerminalSize {
+	sizes := make(chan api.TerminalSize, 1)
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	go func() {
+		defer signal.Stop(winch)
+		defer close(sizes)
+		for {
+			if w, h, err := term.GetSize(fd); err == nil {
+				select {
+				case sizes <- api.TerminalSize{Width: uint16(w), Height: uint16(h)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-winch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return sizes
+}
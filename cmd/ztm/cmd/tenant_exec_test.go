@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantExecCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ExecTenantFunc: func(ctx stdcontext.Context, id string, req api.ExecRequest) error {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, []string{"ls", "-la", "/s3-state"}, req.Command)
+			assert.False(t, req.TTY)
+			require.NotNil(t, req.Stdin)
+			in, _ := io.ReadAll(req.Stdin)
+			fmt.Fprintf(req.Stdout, "got %s", in)
+			return &api.ExitError{Code: 2}
+		},
+	}
+
+	cmd := newTenantExecCmd(mockClient)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true // as under rootCmd
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetIn(strings.NewReader("input"))
+	cmd.SetArgs([]string{"alice", "-i", "--", "ls", "-la", "/s3-state"})
+
+	err := cmd.Execute()
+	var exit *api.ExitError
+	require.ErrorAs(t, err, &exit)
+	assert.Equal(t, 2, exit.Code)
+	assert.Equal(t, "got input", out.String())
+	assert.Empty(t, errOut.String(), "the command's exit code is reported by its status alone")
+}
+
+func TestTenantExecCommand_Error(t *testing.T) {
+	mockClient := &api.MockClient{
+		ExecTenantFunc: func(ctx stdcontext.Context, id string, req api.ExecRequest) error {
+			assert.Nil(t, req.Stdin, "stdin is attached only with -i")
+			return fmt.Errorf("unable to upgrade connection: tenant not running")
+		},
+	}
+
+	cmd := newTenantExecCmd(mockClient)
+	errOut := new(bytes.Buffer)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"alice", "--", "sh"})
+
+	assert.Error(t, cmd.Execute())
+	assert.Contains(t, errOut.String(), "tenant not running")
+}
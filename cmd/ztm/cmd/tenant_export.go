@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shawn/agentic-tenancy/internal/bytesize"
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// exportFile is --file on 'tenant export'
+var exportFile string
+
+func newTenantExportCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <tenant-id>",
+		Short: "Export a tenant for import into another cluster",
+		Long: `Write a bundle of a tenant's settings, key-value store and a manifest of
+its S3 state, for 'ztm tenant import' against another cluster's
+orchestrator. The bundle is JSON, written to stdout or --file.
+
+The bundle leaves out the tenant's credentials (its bot token and Slack
+app) and doesn't hold the state itself. To move a tenant:
+
+  ztm tenant suspend alice
+  ztm tenant export alice -f alice.json
+  aws s3 sync s3://<bucket>/tenants/alice/ s3://<other bucket>/tenants/alice/
+  ztm --profile other tenant import alice.json --bot-token "123456:AAH..."
+  ztm tenant delete alice
+
+Suspend the tenant first so its state doesn't change after the export.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			bundle, err := client.ExportTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to export tenant: %v", err))
+				return err
+			}
+
+			jsonStr, err := output.FormatJSON(bundle)
+			if err != nil {
+				return fmt.Errorf("failed to format output: %w", err)
+			}
+			if exportFile == "" || exportFile == "-" {
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+			// The bundle has no credentials, but its key-value store may
+			// hold anything the agent kept there
+			if err := os.WriteFile(exportFile, []byte(jsonStr+"\n"), 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", exportFile, err)
+			}
+
+			msg := fmt.Sprintf("Tenant '%s' exported to %s", tenantID, exportFile)
+			if bundle.State != nil {
+				msg += fmt.Sprintf(" (state: %d objects, %s under %s)", len(bundle.State.Objects),
+					bytesize.Format(bundle.State.Bytes), bundle.State.Prefix)
+			}
+			styler.FprintSuccess(cmd.OutOrStdout(), msg)
+			if len(bundle.Secrets) > 0 {
+				styler.FprintWarn(cmd.OutOrStdout(), "Credentials aren't exported; give the import: "+strings.Join(bundle.Secrets, ", "))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write the bundle to this file (default: stdout)")
+	return cmd
+}
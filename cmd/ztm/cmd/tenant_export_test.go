@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantExportCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ExportTenantFunc: func(ctx stdcontext.Context, id string) (*api.TenantBundle, error) {
+			return &api.TenantBundle{
+				Version: 1,
+				Tenant:  &api.Tenant{TenantID: id, Tier: "premium"},
+				Secrets: []string{"bot_token"},
+				KV:      map[string]string{"plan": "pro"},
+				State: &api.StateManifest{Prefix: "tenants/alice/", Bytes: 2048,
+					Objects: []api.StateObject{{Key: "memory.db", Size: 2048}}},
+			}, nil
+		},
+	}
+	file := filepath.Join(t.TempDir(), "alice.json")
+	t.Cleanup(func() { exportFile = "" })
+
+	cmd := newTenantExportCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "-f", file})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Tenant 'alice' exported to "+file+" (state: 1 objects, 2.0 KiB under tenants/alice/)")
+	assert.Contains(t, buf.String(), "give the import: bot_token")
+
+	// The file is a bundle 'tenant import' takes
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.True(t, isBundle(data))
+	assert.Contains(t, string(data), `"memory.db"`)
+}
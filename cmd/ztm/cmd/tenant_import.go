@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// importBatchSize is how many tenants go in one POST /tenants/bulk. The
+// batch travels as a kubectl exec argument, so it is kept well under the
+// orchestrator's limit of 100.
+const importBatchSize = 50
+
+// Flags of 'tenant import' for a bundle from 'tenant export'
+var (
+	importBotToken     string
+	importSlack        api.SlackConfig
+	importHome         string
+	importMissingState bool
+)
+
+func newTenantImportCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create tenants from a YAML file or an export bundle",
+		Long: `Create every tenant listed in a YAML (or JSON) file, "-" for stdin.
+
+The file is a list of tenants with the fields of 'ztm tenant create' and
+the API's names for them:
+
+  - tenant_id: alice
+    bot_token: "123456:AAH..."
+    tier: premium
+    locale: de
+  - tenant_id: bob
+    bot_token: "654321:AAH..."
+    keep_warm: true
+
+dns, log_forward and slack take the same objects as PATCH /tenants/{id}.
+Tenants are sent in batches of 50. One that fails (say, it already exists)
+is reported without stopping the rest; the command fails if any did, and
+running it again after fixing the file creates the ones still missing,
+reporting the others as conflicts.
+
+A file that is a single object rather than a list is a bundle from 'ztm
+tenant export', which recreates the exported tenant with its settings and
+key-value store. The bundle has no credentials: give the tenant's bot token
+with --bot-token and its Slack app with --slack-*, or set them later with
+'ztm tenant update'. Copy the tenant's S3 state first; the import is
+refused if the state here doesn't match the bundle's manifest, unless
+--allow-missing-state. The tenant runs in the orchestrator's region unless
+--home-region.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styler := output.NewStyler(noColor)
+			data, err := readImportFile(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			if isBundle(data) {
+				return importBundle(cmd, client, args[0], data)
+			}
+			reqs, err := parseTenantFile(args[0], data)
+			if err != nil {
+				return err
+			}
+
+			all := &api.BulkCreateResponse{}
+			for start := 0; start < len(reqs); start += importBatchSize {
+				batch := reqs[start:min(start+importBatchSize, len(reqs))]
+				ctx, cancel := commandContext(importTimeout)
+				resp, err := client.CreateTenants(ctx, batch)
+				cancel()
+				if err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to import tenants %d-%d: %v", start+1, start+len(batch), err))
+					if all.Created > 0 {
+						styler.FprintInfo(cmd.OutOrStderr(), fmt.Sprintf("%d tenants before them were created", all.Created))
+					}
+					return err
+				}
+				all.Created += resp.Created
+				all.Failed += resp.Failed
+				all.Results = append(all.Results, resp.Results...)
+				if outputFormat != "json" {
+					for _, r := range resp.Results {
+						if r.Error == "" {
+							styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("%s created", r.TenantID))
+						} else {
+							styler.FprintError(cmd.OutOrStdout(), fmt.Sprintf("%s: %s (%d)", r.TenantID, r.Error, r.Status))
+						}
+					}
+				}
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(all)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nCreated %d, failed %d\n", all.Created, all.Failed)
+			}
+			if all.Failed > 0 {
+				return fmt.Errorf("%d of %d tenants failed", all.Failed, len(reqs))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&importBotToken, "bot-token", "", "Bundle: the tenant's Telegram bot token")
+	cmd.Flags().StringVar(&importSlack.SigningSecret, "slack-signing-secret", "", "Bundle: the tenant's Slack app signing secret")
+	cmd.Flags().StringVar(&importSlack.BotToken, "slack-bot-token", "", "Bundle: the tenant's Slack app bot token (xoxb-...)")
+	cmd.Flags().StringVar(&importHome, "home-region", "", "Bundle: region whose orchestrator runs the tenant's pod (default: the orchestrator's REGION)")
+	cmd.Flags().BoolVar(&importMissingState, "allow-missing-state", false, "Bundle: import even if the tenant's state here doesn't match the bundle")
+	return cmd
+}
+
+func readImportFile(cmd *cobra.Command, path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// isBundle reports whether data is one object, as 'tenant export' writes,
+// rather than a list of tenants
+func isBundle(data []byte) bool {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return false
+	}
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+// importBundle recreates the tenant exported to data with POST
+// /tenants/import
+func importBundle(cmd *cobra.Command, client api.Client, path string, data []byte) error {
+	styler := output.NewStyler(noColor)
+	req := api.ImportTenantRequest{
+		BotToken:          importBotToken,
+		HomeRegion:        importHome,
+		AllowMissingState: importMissingState,
+	}
+	// Not strict: the tenant carries every field of the exporting
+	// orchestrator's record, some of which this ztm may not know
+	if err := yaml.Unmarshal(data, &req.Bundle); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if req.Bundle.Tenant == nil {
+		return fmt.Errorf("%s is not a tenant export", path)
+	}
+	if !importSlack.IsZero() {
+		slack := importSlack
+		req.Slack = &slack
+	}
+
+	ctx, cancel := commandContext(importTimeout)
+	defer cancel()
+
+	result, err := client.ImportTenant(ctx, req)
+	if err != nil {
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to import tenant '%s': %v", req.Bundle.Tenant.TenantID, err))
+		return err
+	}
+
+	if outputFormat == "json" {
+		jsonStr, err := output.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+		return nil
+	}
+
+	msg := fmt.Sprintf("Tenant '%s' imported", result.Tenant.TenantID)
+	if req.Bundle.State != nil {
+		msg += fmt.Sprintf(" (%d of %d state objects found)", result.StateObjects, len(req.Bundle.State.Objects))
+	}
+	styler.FprintSuccess(cmd.OutOrStdout(), msg)
+	if len(result.MissingState) > 0 {
+		styler.FprintWarn(cmd.OutOrStdout(), fmt.Sprintf("%d state objects missing or different, e.g. %s", len(result.MissingState), result.MissingState[0]))
+	}
+	if len(result.MissingSecrets) > 0 {
+		styler.FprintWarn(cmd.OutOrStdout(), fmt.Sprintf("Set the tenant's credentials with 'ztm tenant update': %s", strings.Join(result.MissingSecrets, ", ")))
+	}
+	return nil
+}
+
+// parseTenantFile parses the tenants to import, refusing a file with a
+// tenant missing its ID or listed twice before anything is created
+func parseTenantFile(path string, data []byte) ([]api.CreateTenantRequest, error) {
+	var reqs []api.CreateTenantRequest
+	if err := yaml.UnmarshalStrict(data, &reqs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%s lists no tenants", path)
+	}
+	seen := map[string]bool{}
+	for i, r := range reqs {
+		if r.TenantID == "" {
+			return nil, fmt.Errorf("tenant %d in %s has no tenant_id", i+1, path)
+		}
+		if seen[r.TenantID] {
+			return nil, fmt.Errorf("tenant %q is listed twice in %s", r.TenantID, path)
+		}
+		seen[r.TenantID] = true
+	}
+	return reqs, nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantImportCommand(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tenants.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+- tenant_id: alice
+  bot_token: "123:abc"
+  tier: premium
+  log_forward:
+    telegram_chat_id: 42
+- tenant_id: bob
+  locale: xx
+`), 0o600))
+
+	mockClient := &api.MockClient{
+		CreateTenantsFunc: func(ctx stdcontext.Context, reqs []api.CreateTenantRequest) (*api.BulkCreateResponse, error) {
+			require.Len(t, reqs, 2)
+			assert.Equal(t, "premium", reqs[0].Tier)
+			assert.Equal(t, int64(42), reqs[0].LogForward.TelegramChatID)
+			return &api.BulkCreateResponse{Created: 1, Failed: 1, Results: []api.BulkCreateResult{
+				{TenantID: "alice", Status: 201, Tenant: &api.Tenant{TenantID: "alice"}},
+				{TenantID: "bob", Status: 400, Error: "unsupported locale"},
+			}}, nil
+		},
+	}
+
+	cmd := newTenantImportCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{file})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "1 of 2 tenants failed")
+	assert.Contains(t, buf.String(), "alice created")
+	assert.Contains(t, buf.String(), "bob: unsupported locale (400)")
+}
+
+// TestTenantImportCommand_Batches: large files go in several calls
+func TestTenantImportCommand_Batches(t *testing.T) {
+	var file strings.Builder
+	for i := range 120 {
+		fmt.Fprintf(&file, "- tenant_id: t%d\n", i)
+	}
+	var batches []int
+	mockClient := &api.MockClient{
+		CreateTenantsFunc: func(ctx stdcontext.Context, reqs []api.CreateTenantRequest) (*api.BulkCreateResponse, error) {
+			batches = append(batches, len(reqs))
+			return &api.BulkCreateResponse{Created: len(reqs)}, nil
+		},
+	}
+
+	cmd := newTenantImportCmd(mockClient)
+	cmd.SetIn(strings.NewReader(file.String()))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"-"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, []int{50, 50, 20}, batches)
+}
+
+func TestTenantImportCommand_InvalidFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":         "[]",
+		"missing ID":    "- bot_token: x\n",
+		"duplicate":     "- tenant_id: a\n- tenant_id: a\n",
+		"unknown field": "- tenant_id: a\n  teir: premium\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cmd := newTenantImportCmd(&api.MockClient{
+				CreateTenantsFunc: func(ctx stdcontext.Context, reqs []api.CreateTenantRequest) (*api.BulkCreateResponse, error) {
+					t.Fatal("nothing is sent for an invalid file")
+					return nil, nil
+				},
+			})
+			cmd.SetIn(strings.NewReader(content))
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs([]string{"-"})
+			assert.Error(t, cmd.Execute())
+		})
+	}
+}
+
+func TestTenantImportCommand_Bundle(t *testing.T) {
+	// A bundle carries fields of the exporting orchestrator's record that
+	// ztm doesn't know
+	bundle := `{
+  "version": 1,
+  "region": "us-east-1",
+  "tenant": {"TenantID": "alice", "Tier": "premium", "S3Prefix": "", "WebhookAttempts": 0},
+  "secrets": ["bot_token", "slack"],
+  "kv": {"plan": "pro"},
+  "state": {"prefix": "tenants/alice/", "objects": [{"key": "memory.db", "size": 10}], "bytes": 10}
+}`
+	var got api.ImportTenantRequest
+	mockClient := &api.MockClient{
+		CreateTenantsFunc: func(ctx stdcontext.Context, reqs []api.CreateTenantRequest) (*api.BulkCreateResponse, error) {
+			t.Fatal("a bundle isn't a list of tenants")
+			return nil, nil
+		},
+		ImportTenantFunc: func(ctx stdcontext.Context, req api.ImportTenantRequest) (*api.ImportResult, error) {
+			got = req
+			return &api.ImportResult{Tenant: &api.Tenant{TenantID: "alice"}, StateObjects: 1, MissingSecrets: []string{"slack"}}, nil
+		},
+	}
+	t.Cleanup(func() { importBotToken, importHome = "", "" })
+
+	cmd := newTenantImportCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetIn(strings.NewReader(bundle))
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"-", "--bot-token", "123:abc", "--home-region", "eu-west-1"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "alice", got.Bundle.Tenant.TenantID)
+	assert.Equal(t, "premium", got.Bundle.Tenant.Tier)
+	assert.Equal(t, map[string]string{"plan": "pro"}, got.Bundle.KV)
+	assert.Equal(t, []api.StateObject{{Key: "memory.db", Size: 10}}, got.Bundle.State.Objects)
+	assert.Equal(t, "123:abc", got.BotToken)
+	assert.Equal(t, "eu-west-1", got.HomeRegion)
+	assert.Nil(t, got.Slack)
+	assert.Contains(t, buf.String(), "Tenant 'alice' imported (1 of 1 state objects found)")
+	assert.Contains(t, buf.String(), "'ztm tenant update': slack")
+}
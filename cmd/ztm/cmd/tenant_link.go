@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linkStart  string
+	linkQRFile string
+)
+
+func newTenantLinkCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link <tenant-id>",
+		Short: "Generate a Telegram deep link for a tenant's bot",
+		Long: `Generate a t.me deep link (and optionally a QR code) for a tenant's bot.
+
+The bot username is resolved via Telegram getMe and stored in the registry
+the first time a link is requested. Use --start to embed a payload that the
+bot receives as "/start <payload>" (1-64 chars of A-Z, a-z, 0-9, _ or -).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			link, err := client.GetLink(ctx, tenantID, linkStart)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to generate link: %v", err))
+				return err
+			}
+
+			if linkQRFile != "" {
+				png, err := client.GetLinkQR(ctx, tenantID, linkStart)
+				if err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to generate QR code: %v", err))
+					return err
+				}
+				if err := os.WriteFile(linkQRFile, png, 0o644); err != nil {
+					return fmt.Errorf("failed to write QR code: %w", err)
+				}
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(link)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Bot:   @%s\n", link.BotUsername)
+			fmt.Fprintf(cmd.OutOrStdout(), "Link:  %s\n", link.URL)
+			if linkQRFile != "" {
+				styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("QR code written to %s", linkQRFile))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&linkStart, "start", "", "Start payload passed to the bot")
+	cmd.Flags().StringVar(&linkQRFile, "qr-file", "", "Write a PNG QR code of the link to this file")
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantLinkCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetLinkFunc: func(ctx stdcontext.Context, id, start string) (*api.DeepLink, error) {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, "promo", start)
+			return &api.DeepLink{
+				TenantID:    id,
+				BotUsername: "alice_bot",
+				URL:         "https://t.me/alice_bot?start=promo",
+			}, nil
+		},
+	}
+
+	cmd := newTenantLinkCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--start", "promo"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "https://t.me/alice_bot?start=promo")
+}
+
+func TestTenantLinkCommand_QRFile(t *testing.T) {
+	qrPath := filepath.Join(t.TempDir(), "alice.png")
+	mockClient := &api.MockClient{
+		GetLinkFunc: func(ctx stdcontext.Context, id, start string) (*api.DeepLink, error) {
+			return &api.DeepLink{TenantID: id, BotUsername: "alice_bot", URL: "https://t.me/alice_bot"}, nil
+		},
+		GetLinkQRFunc: func(ctx stdcontext.Context, id, start string) ([]byte, error) {
+			return []byte("\x89PNG"), nil
+		},
+	}
+
+	cmd := newTenantLinkCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--start", "", "--qr-file", qrPath})
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+	data, err := os.ReadFile(qrPath)
+	require.NoError(t, err)
+	assert.Equal(t, "\x89PNG", string(data))
+}
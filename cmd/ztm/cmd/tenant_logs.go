@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	stdcontext "context"
+	"fmt"
+	"io"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsTail   int
+	logsFollow bool
+)
+
+func newTenantLogsCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs <tenant-id>",
+		Short: "Print a running tenant's agent logs",
+		Long: `Print the last --tail lines of a running tenant's agent logs, read by the
+orchestrator, so no cluster credentials are needed.
+
+With --follow new lines are printed as the agent writes them until the pod
+stops or you interrupt; --timeout, if set, ends it too. Following needs
+--orchestrator-url.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			var ctx stdcontext.Context
+			var cancel stdcontext.CancelFunc
+			if logsFollow && timeout == 0 {
+				ctx, cancel = stdcontext.WithCancel(stdcontext.Background())
+			} else {
+				ctx, cancel = commandContext(defaultTimeout)
+			}
+			defer cancel()
+
+			logs, err := client.StreamLogs(ctx, tenantID, logsTail, logsFollow)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get logs: %v", err))
+				return err
+			}
+			defer logs.Close()
+			if _, err := io.Copy(cmd.OutOrStdout(), logs); err != nil && ctx.Err() == nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Log stream interrupted: %v", err))
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&logsTail, "tail", 200, "Number of past lines to print")
+	cmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new lines")
+	return cmd
+}
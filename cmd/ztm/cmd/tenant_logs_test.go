@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantLogsCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		StreamLogsFunc: func(ctx stdcontext.Context, id string, tail int, follow bool) (io.ReadCloser, error) {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, 50, tail)
+			assert.True(t, follow)
+			return io.NopCloser(strings.NewReader("INFO started\nERROR boom\n")), nil
+		},
+	}
+
+	cmd := newTenantLogsCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--tail", "50", "-f"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "INFO started\nERROR boom\n", buf.String())
+}
+
+func TestTenantLogsCommand_Error(t *testing.T) {
+	mockClient := &api.MockClient{
+		StreamLogsFunc: func(ctx stdcontext.Context, id string, tail int, follow bool) (io.ReadCloser, error) {
+			return nil, fmt.Errorf("orchestrator returned 409: tenant not running")
+		},
+	}
+
+	cmd := newTenantLogsCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	assert.Error(t, cmd.Execute())
+}
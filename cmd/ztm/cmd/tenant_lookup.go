@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantLookupCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lookup <chat-id>",
+		Short: "Find tenants by Telegram chat ID",
+		Long: `Find the tenants whose bots have received messages from a Telegram chat.
+
+The chat → tenant index is maintained by the router as messages flow
+through, so only chats seen in the last 30 days can be resolved.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chatID := args[0]
+			if _, err := strconv.ParseInt(chatID, 10, 64); err != nil {
+				return fmt.Errorf("invalid chat ID %q: must be an integer", chatID)
+			}
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			entries, err := client.LookupChat(ctx, chatID)
+			if err != nil {
+				styler := output.NewStyler(noColor)
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to look up chat: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(entries)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			if len(entries) == 0 {
+				styler := output.NewStyler(noColor)
+				styler.FprintWarn(cmd.OutOrStdout(), fmt.Sprintf("No tenants found for chat %s", chatID))
+				return nil
+			}
+
+			// Table format
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TENANT ID\tLAST SEEN")
+			for _, e := range entries {
+				fmt.Fprintf(w, "%s\t%s\n", e.TenantID, e.LastSeenAt.Format("2006-01-02 15:04:05"))
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+}
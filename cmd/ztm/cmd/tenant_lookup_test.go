@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantLookupCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		LookupChatFunc: func(ctx stdcontext.Context, chatID string) ([]api.ChatTenant, error) {
+			assert.Equal(t, "-100123456", chatID)
+			return []api.ChatTenant{
+				{TenantID: "alice", LastSeenAt: time.Now()},
+			}, nil
+		},
+	}
+
+	cmd := newTenantLookupCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--", "-100123456"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "alice")
+}
+
+func TestTenantLookupCommand_InvalidChatID(t *testing.T) {
+	cmd := newTenantLookupCmd(&api.MockClient{})
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"abc"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chat ID")
+}
@@ -2,78 +2,214 @@ package cmd
 
 import (
 	stdcontext "context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/shawn/agentic-tenancy/internal/bytesize"
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
 	"github.com/shawn/agentic-tenancy/internal/cli/output"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listSort     string
+	listDesc     bool
+	listCached   bool
+	listWatch    bool
+	getCached    bool
+	deleteDryRun bool
+	deletePurge  bool
+)
+
 func newTenantListCmd(client api.Client) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all tenants",
+		Long: `List all tenants.
+
+Use --sort to order by tenant_id (default), last_active_at, created_at or
+status, and --desc to reverse the order.
+
+Every successful list refreshes the tenant cache (~/.ztm/cache.json, or
+$ZTM_CACHE). With --cached the cache is shown instead, without contacting
+the orchestrator, for triage during an outage; it is as old as the last list.
+
+With --watch the list stays open and prints a line for each tenant that
+changes status (idle -> waking -> running and so on), is added or is
+deleted, until you interrupt; with --output json every change is printed as
+a JSON line, including activity updates. Changes trail by up to the
+orchestrator's poll interval (2s). Watching needs --orchestrator-url.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			styler := output.NewStyler(noColor)
+			if listCached && listWatch {
+				return fmt.Errorf("--cached and --watch can't be combined")
+			}
+			if listCached {
+				inv, err := loadInventory()
+				if err != nil {
+					styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to read cached tenants: %v", err))
+					return err
+				}
+				if err := sortTenants(inv.Tenants, listSort, listDesc); err != nil {
+					return err
+				}
+				styler.FprintWarn(cmd.OutOrStderr(), fmt.Sprintf("Orchestrator not contacted; showing tenants %s", inv.staleness(time.Now())))
+				return printTenantList(cmd.OutOrStdout(), inv.Tenants)
+			}
 			styler.FprintInfo(cmd.OutOrStdout(), "Listing tenants...")
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
-			tenants, err := client.ListTenants(ctx)
+			opts := api.ListOptions{Sort: listSort}
+			if listDesc {
+				opts.Order = "desc"
+			}
+			tenants, err := client.ListTenants(ctx, opts)
 			if err != nil {
 				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to list tenants: %v", err))
+				suggestCached(cmd, styler)
 				return err
 			}
-
-			if outputFormat == "json" {
-				jsonStr, err := output.FormatJSON(tenants)
-				if err != nil {
-					return fmt.Errorf("failed to format output: %w", err)
-				}
-				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
-				return nil
+			if err := saveInventory(tenants, time.Now()); err != nil {
+				styler.FprintWarn(cmd.OutOrStderr(), fmt.Sprintf("Couldn't update the tenant cache: %v", err))
+			}
+			if err := printTenantList(cmd.OutOrStdout(), tenants); err != nil || !listWatch {
+				return err
 			}
+			return watchTenantList(cmd, styler, client, tenants)
+		},
+	}
 
-			// Table format
-			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "TENANT ID\tSTATUS\tLAST ACTIVE\tIDLE TIMEOUT")
-			for _, t := range tenants {
-				lastActive := "never"
-				if !t.LastActiveAt.IsZero() {
-					lastActive = t.LastActiveAt.Format("2006-01-02 15:04:05")
-				}
-				fmt.Fprintf(w, "%s\t%s\t%s\t%ds\n", t.TenantID, t.Status, lastActive, t.IdleTimeoutS)
+	cmd.Flags().StringVar(&listSort, "sort", "", "Sort by: tenant_id|last_active_at|created_at|status")
+	cmd.Flags().BoolVar(&listDesc, "desc", false, "Sort in descending order")
+	cmd.Flags().BoolVar(&listCached, "cached", false, "Show the tenants cached by the last list instead of asking the orchestrator")
+	cmd.Flags().BoolVarP(&listWatch, "watch", "w", false, "Keep printing status changes after the list")
+
+	return cmd
+}
+
+// printTenantList writes tenants as a table, or JSON with --output json
+func printTenantList(out io.Writer, tenants []api.Tenant) error {
+	if outputFormat == "json" {
+		jsonStr, err := output.FormatJSON(tenants)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintln(out, jsonStr)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TENANT ID\tSTATUS\tLAST ACTIVE\tIDLE TIMEOUT")
+	for _, t := range tenants {
+		lastActive := "never"
+		if !t.LastActiveAt.IsZero() {
+			lastActive = t.LastActiveAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%ds\n", t.TenantID, t.Status, lastActive, t.IdleTimeoutS)
+	}
+	return w.Flush()
+}
+
+// watchTenantList prints tenant changes after the list until interrupted,
+// or --timeout if set. In a table only status changes, additions and
+// deletions are shown; the watch's first burst repeats the listed tenants,
+// so those are printed only if they changed in between.
+func watchTenantList(cmd *cobra.Command, styler *output.Styler, client api.Client, listed []api.Tenant) error {
+	var ctx stdcontext.Context
+	var cancel stdcontext.CancelFunc
+	if timeout == 0 {
+		ctx, cancel = stdcontext.WithCancel(stdcontext.Background())
+	} else {
+		ctx, cancel = commandContext(timeout)
+	}
+	defer cancel()
+
+	status := make(map[string]string, len(listed))
+	for _, t := range listed {
+		status[t.TenantID] = t.Status
+	}
+	out := cmd.OutOrStdout()
+	err := client.WatchTenants(ctx, "", func(change api.TenantChange) error {
+		t := change.Tenant
+		prev, known := status[t.TenantID]
+		if outputFormat == "json" {
+			data, err := json.Marshal(change)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(data))
+		} else {
+			now := time.Now().Format("15:04:05")
+			switch {
+			case change.Type == "deleted":
+				fmt.Fprintf(out, "%s  %s  deleted\n", now, t.TenantID)
+			case !known:
+				fmt.Fprintf(out, "%s  %s  added (%s)\n", now, t.TenantID, t.Status)
+			case prev != t.Status:
+				fmt.Fprintf(out, "%s  %s  %s -> %s\n", now, t.TenantID, prev, t.Status)
 			}
-			w.Flush()
+		}
+		if change.Type == "deleted" {
+			delete(status, t.TenantID)
+		} else {
+			status[t.TenantID] = t.Status
+		}
+		return nil
+	})
+	if err != nil {
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Watch ended: %v", err))
+	}
+	return err
+}
 
-			return nil
-		},
+// suggestCached points at --cached after a failed call, if there is a cache
+func suggestCached(cmd *cobra.Command, styler *output.Styler) {
+	if inv, err := loadInventory(); err == nil {
+		styler.FprintInfo(cmd.OutOrStderr(), fmt.Sprintf("Tenants %s are available with --cached", inv.staleness(time.Now())))
 	}
 }
 
 func newTenantGetCmd(client api.Client) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "get <tenant-id>",
 		Short: "Get tenant details",
-		Args:  cobra.ExactArgs(1),
+		Long: `Get tenant details.
+
+With --cached the tenant is read from the cache the last 'ztm tenant list'
+refreshed, without contacting the orchestrator.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTenantIDs(client),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			tenantID := args[0]
+			if getCached {
+				return printCachedTenant(cmd, tenantID)
+			}
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
 			tenant, err := client.GetTenant(ctx, tenantID)
 			if err != nil {
 				styler := output.NewStyler(noColor)
 				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get tenant: %v", err))
+				suggestCached(cmd, styler)
 				return err
 			}
+			// Older orchestrators don't serve the state size; leave it out
+			state, _ := client.GetState(ctx, tenantID)
 
 			if outputFormat == "json" {
-				jsonStr, err := output.FormatJSON(tenant)
+				jsonStr, err := output.FormatJSON(struct {
+					*api.Tenant
+					State *api.TenantState `json:"state,omitempty"`
+				}{tenant, state})
 				if err != nil {
 					return fmt.Errorf("failed to format output: %w", err)
 				}
@@ -81,49 +217,330 @@ func newTenantGetCmd(client api.Client) *cobra.Command {
 				return nil
 			}
 
-			// Table format
-			fmt.Fprintf(cmd.OutOrStdout(), "Tenant ID:     %s\n", tenant.TenantID)
-			fmt.Fprintf(cmd.OutOrStdout(), "Status:        %s\n", tenant.Status)
-			fmt.Fprintf(cmd.OutOrStdout(), "Idle Timeout:  %ds\n", tenant.IdleTimeoutS)
-			if tenant.PodName != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "Pod Name:      %s\n", tenant.PodName)
+			printTenant(cmd.OutOrStdout(), tenant)
+			if state != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "State:         %s\n", formatState(state))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&getCached, "cached", false, "Read the tenant from the cache of the last list instead of asking the orchestrator")
+
+	return cmd
+}
+
+// printCachedTenant shows a tenant from the inventory cache, marked with
+// when it was cached
+func printCachedTenant(cmd *cobra.Command, tenantID string) error {
+	styler := output.NewStyler(noColor)
+	inv, err := loadInventory()
+	if err != nil {
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to read cached tenants: %v", err))
+		return err
+	}
+	var tenant *api.Tenant
+	for i := range inv.Tenants {
+		if inv.Tenants[i].TenantID == tenantID {
+			tenant = &inv.Tenants[i]
+		}
+	}
+	if tenant == nil {
+		err := fmt.Errorf("tenant %s not in the cache (%s)", tenantID, inv.staleness(time.Now()))
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get tenant: %v", err))
+		return err
+	}
+	styler.FprintWarn(cmd.OutOrStderr(), fmt.Sprintf("Orchestrator not contacted; showing the tenant %s", inv.staleness(time.Now())))
+
+	if outputFormat == "json" {
+		jsonStr, err := output.FormatJSON(struct {
+			*api.Tenant
+			CachedAt time.Time `json:"cached_at"`
+		}{tenant, inv.RefreshedAt})
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+		return nil
+	}
+	printTenant(cmd.OutOrStdout(), tenant)
+	fmt.Fprintf(cmd.OutOrStdout(), "Cached At:     %s (stale)\n", inv.RefreshedAt.Format(time.RFC3339))
+	return nil
+}
+
+// printTenant writes a tenant's details as aligned "Field: value" lines
+func printTenant(w io.Writer, tenant *api.Tenant) {
+	fmt.Fprintf(w, "Tenant ID:     %s\n", tenant.TenantID)
+	fmt.Fprintf(w, "Status:        %s\n", tenant.Status)
+	if tenant.Disabled {
+		fmt.Fprintf(w, "Disabled:      yes (kill switch)\n")
+	}
+	if tenant.LastError != "" {
+		fmt.Fprintf(w, "Last Error:    %s (%d failed wakes in a row)\n", tenant.LastError, tenant.WakeFailures)
+	}
+	fmt.Fprintf(w, "Idle Timeout:  %ds\n", tenant.IdleTimeoutS)
+	if tenant.Tier != "" {
+		fmt.Fprintf(w, "Tier:          %s\n", tenant.Tier)
+	}
+	if tenant.Resize != nil {
+		fmt.Fprintf(w, "Resize:        %s\n", formatResize(tenant.Resize))
+	}
+	if tenant.Image != "" {
+		fmt.Fprintf(w, "Image:         %s\n", tenant.Image)
+	}
+	if tenant.KeepWarm {
+		fmt.Fprintf(w, "Keep Warm:     yes\n")
+	}
+	if tenant.DNS != nil {
+		fmt.Fprintf(w, "DNS:           %s\n", formatDNS(tenant.DNS))
+	}
+	if tenant.LogForward != nil {
+		fmt.Fprintf(w, "Log Forward:   %s\n", formatLogForward(tenant.LogForward))
+	}
+	if tenant.NoAnnouncements {
+		fmt.Fprintf(w, "Announcements: off\n")
+	}
+	if tenant.WeeklyDigest {
+		fmt.Fprintf(w, "Weekly Digest: on\n")
+	}
+	if len(tenant.AllowedUpdates) > 0 {
+		fmt.Fprintf(w, "Updates:       %s\n", strings.Join(tenant.AllowedUpdates, ","))
+	}
+	if tenant.Slack != nil {
+		fmt.Fprintf(w, "Slack:         connected\n")
+	}
+	if tenant.Locale != "" {
+		fmt.Fprintf(w, "Locale:        %s\n", tenant.Locale)
+	}
+	if tenant.Timezone != "" {
+		fmt.Fprintf(w, "Timezone:      %s\n", tenant.Timezone)
+	}
+	switch {
+	case tenant.MaxMessageAgeS < 0:
+		fmt.Fprintf(w, "Max Msg Age:   off\n")
+	case tenant.MaxMessageAgeS > 0:
+		fmt.Fprintf(w, "Max Msg Age:   %ds\n", tenant.MaxMessageAgeS)
+	}
+	switch {
+	case tenant.MaxReplyBytes < 0:
+		fmt.Fprintf(w, "Max Reply:     any length\n")
+	case tenant.MaxReplyBytes > 0:
+		fmt.Fprintf(w, "Max Reply:     %d bytes\n", tenant.MaxReplyBytes)
+	}
+	if tenant.PodName != "" {
+		fmt.Fprintf(w, "Pod Name:      %s\n", tenant.PodName)
+	}
+	if tenant.PodIP != "" {
+		fmt.Fprintf(w, "Pod IP:        %s\n", tenant.PodIP)
+	}
+	if !tenant.LastActiveAt.IsZero() {
+		fmt.Fprintf(w, "Last Active:   %s\n", tenant.LastActiveAt.Format(time.RFC3339))
+	}
+	if !tenant.CreatedAt.IsZero() {
+		fmt.Fprintf(w, "Created At:    %s\n", tenant.CreatedAt.Format(time.RFC3339))
+	}
+	if !tenant.DeletedAt.IsZero() {
+		fmt.Fprintf(w, "Deleted At:    %s\n", tenant.DeletedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "Purge At:      %s\n", tenant.PurgeAt.Format(time.RFC3339))
+	}
+}
+
+// formatState renders a state size against its quota, e.g.
+// "4.2 GiB / 5.0 GiB (84%, warning)" or "300.0 MiB (no quota)"
+func formatState(s *api.TenantState) string {
+	if s.QuotaBytes == 0 {
+		return bytesize.Format(s.Bytes) + " (no quota)"
+	}
+	return fmt.Sprintf("%s / %s (%d%%, %s)", bytesize.Format(s.Bytes), bytesize.Format(s.QuotaBytes), s.UsedPercent, s.Level)
+}
+
+// formatResize renders a resize operation, e.g. "standard → premium (replacing)"
+func formatResize(op *api.ResizeOp) string {
+	s := fmt.Sprintf("%s → %s (%s)", op.FromTier, op.ToTier, op.Status)
+	if op.Error != "" {
+		s += ": " + op.Error
+	}
+	return s
+}
+
+// formatDNS renders a DNS override on one line, e.g.
+// "policy=None nameservers=10.0.0.2 searches=corp.internal options=ndots:2"
+func formatDNS(d *api.DNSConfig) string {
+	var parts []string
+	if d.Policy != "" {
+		parts = append(parts, "policy="+d.Policy)
+	}
+	if len(d.Nameservers) > 0 {
+		parts = append(parts, "nameservers="+strings.Join(d.Nameservers, ","))
+	}
+	if len(d.Searches) > 0 {
+		parts = append(parts, "searches="+strings.Join(d.Searches, ","))
+	}
+	if len(d.Options) > 0 {
+		parts = append(parts, "options="+strings.Join(d.Options, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatLogForward renders log forwarding targets on one line, e.g.
+// "telegram=123456 webhook=https://example.com/hook"
+func formatLogForward(l *api.LogForwardConfig) string {
+	var parts []string
+	if l.TelegramChatID != 0 {
+		parts = append(parts, "telegram="+strconv.FormatInt(l.TelegramChatID, 10))
+	}
+	if l.WebhookURL != "" {
+		parts = append(parts, "webhook="+l.WebhookURL)
+	}
+	return strings.Join(parts, " ")
+}
+
+func newTenantDeleteCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <tenant-id>",
+		Short: "Delete a tenant",
+		Long: `Delete a tenant: its pod, storage, router caches and Telegram webhook.
+
+The orchestrator keeps the tenant's record, S3 state and bot token for its
+retention window (DELETE_RETENTION_S), during which 'ztm tenant restore'
+brings it back; then they are purged. --purge removes them now, including
+for a tenant already deleted.
+
+Lists what will be deleted and asks for confirmation first; --yes skips the
+question. Use --dry-run to list it without deleting anything.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTenantIDs(client),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			if deleteDryRun {
+				return planDelete(cmd, client, tenantID)
 			}
-			if tenant.PodIP != "" {
-				fmt.Fprintf(cmd.OutOrStdout(), "Pod IP:        %s\n", tenant.PodIP)
+			if !skipConfirm {
+				if err := confirmDelete(cmd, client, tenantID); err != nil {
+					return err
+				}
 			}
-			if !tenant.LastActiveAt.IsZero() {
-				fmt.Fprintf(cmd.OutOrStdout(), "Last Active:   %s\n", tenant.LastActiveAt.Format(time.RFC3339))
+			styler := output.NewStyler(noColor)
+			spinner := output.NewSpinner(cmd.ErrOrStderr(), noColor)
+			spinner.Start(fmt.Sprintf("Deleting tenant '%s' (pod, storage, cache, webhook)", tenantID))
+
+			ctx, cancel := commandContext(deleteTimeout)
+			defer cancel()
+
+			var err error
+			if deletePurge {
+				err = client.PurgeTenant(ctx, tenantID)
+			} else {
+				err = client.DeleteTenant(ctx, tenantID)
 			}
-			if !tenant.CreatedAt.IsZero() {
-				fmt.Fprintf(cmd.OutOrStdout(), "Created At:    %s\n", tenant.CreatedAt.Format(time.RFC3339))
+			elapsed := spinner.Stop()
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to delete tenant after %s: %v", output.FormatElapsed(elapsed), err))
+				return err
 			}
 
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' deleted in %s", tenantID, output.FormatElapsed(elapsed)))
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&deleteDryRun, "dry-run", false, "Show what would be deleted without deleting it")
+	cmd.Flags().BoolVar(&deletePurge, "purge", false, "Also remove the record, S3 state and bot token now, so it can't be restored")
+	addConfirmFlag(cmd)
+	return cmd
 }
 
-func newTenantDeleteCmd(client api.Client) *cobra.Command {
+func newTenantRestoreCmd(client api.Client) *cobra.Command {
 	return &cobra.Command{
-		Use:   "delete <tenant-id>",
-		Short: "Delete a tenant",
-		Args:  cobra.ExactArgs(1),
+		Use:   "restore <tenant-id>",
+		Short: "Bring back a deleted tenant before it is purged",
+		Long: `Restore a deleted tenant whose retention window hasn't run out. It becomes
+idle with its S3 state and settings as they were, its Telegram webhook is
+registered again, and its next message wakes it.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			tenantID := args[0]
 			styler := output.NewStyler(noColor)
-			styler.FprintInfo(cmd.OutOrStdout(), fmt.Sprintf("Deleting tenant '%s'...", tenantID))
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
-			err := client.DeleteTenant(ctx, tenantID)
+			tenant, err := client.RestoreTenant(ctx, tenantID)
 			if err != nil {
-				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to delete tenant: %v", err))
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to restore tenant: %v", err))
 				return err
 			}
 
-			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' deleted", tenantID))
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(tenant)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' restored", tenantID))
 			return nil
 		},
 	}
 }
+
+// fetchDeletePlan asks the orchestrator what deleting tenantID, with
+// --purge or without, will remove
+func fetchDeletePlan(client api.Client, tenantID string) (*api.DeletePlan, error) {
+	ctx, cancel := commandContext(defaultTimeout)
+	defer cancel()
+	if deletePurge {
+		return client.PlanPurgeTenant(ctx, tenantID)
+	}
+	return client.PlanDeleteTenant(ctx, tenantID)
+}
+
+// printDeletePlan lists a plan's deletes, then what it keeps and until when
+func printDeletePlan(w io.Writer, plan *api.DeletePlan, verb string) {
+	fmt.Fprintf(w, "Deleting tenant '%s' %s remove:\n", plan.TenantID, verb)
+	for _, d := range plan.Deletes {
+		fmt.Fprintf(w, "  - %s\n", d)
+	}
+	if len(plan.Keeps) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "and keep for a restore until %s:\n", plan.PurgeAt.Local().Format(time.RFC3339))
+	for _, k := range plan.Keeps {
+		fmt.Fprintf(w, "  - %s\n", k)
+	}
+}
+
+// confirmDelete lists what deleting tenantID will remove and asks whether
+// to go ahead. The plan comes from the orchestrator, so a mistyped ID fails
+// here as not found.
+func confirmDelete(cmd *cobra.Command, client api.Client, tenantID string) error {
+	plan, err := fetchDeletePlan(client, tenantID)
+	if err != nil {
+		output.NewStyler(noColor).FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to plan delete: %v", err))
+		return err
+	}
+	printDeletePlan(cmd.ErrOrStderr(), plan, "will")
+	return confirm(cmd, fmt.Sprintf("Delete tenant '%s'?", tenantID))
+}
+
+// planDelete prints what deleting tenantID would remove
+func planDelete(cmd *cobra.Command, client api.Client, tenantID string) error {
+	styler := output.NewStyler(noColor)
+	plan, err := fetchDeletePlan(client, tenantID)
+	if err != nil {
+		styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to plan delete: %v", err))
+		return err
+	}
+	if outputFormat == "json" {
+		jsonStr, err := output.FormatJSON(plan)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+		return nil
+	}
+	printDeletePlan(cmd.OutOrStdout(), plan, "would")
+	return nil
+}
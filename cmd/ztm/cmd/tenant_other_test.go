@@ -3,15 +3,20 @@ package cmd
 import (
 	"bytes"
 	stdcontext "context"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTenantListCommand(t *testing.T) {
 	mockClient := &api.MockClient{
-		ListTenantsFunc: func(ctx stdcontext.Context) ([]api.Tenant, error) {
+		ListTenantsFunc: func(ctx stdcontext.Context, opts api.ListOptions) ([]api.Tenant, error) {
 			return []api.Tenant{
 				{TenantID: "alice", Status: "running", IdleTimeoutS: 3600},
 				{TenantID: "bob", Status: "idle", IdleTimeoutS: 600},
@@ -34,6 +39,24 @@ func TestTenantListCommand(t *testing.T) {
 	assert.Contains(t, output, "idle")
 }
 
+func TestTenantListCommand_Sort(t *testing.T) {
+	mockClient := &api.MockClient{
+		ListTenantsFunc: func(ctx stdcontext.Context, opts api.ListOptions) ([]api.Tenant, error) {
+			assert.Equal(t, "last_active_at", opts.Sort)
+			assert.Equal(t, "desc", opts.Order)
+			return []api.Tenant{}, nil
+		},
+	}
+
+	cmd := newTenantListCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--sort", "last_active_at", "--desc"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
 func TestTenantGetCommand(t *testing.T) {
 	mockClient := &api.MockClient{
 		GetTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
@@ -45,6 +68,9 @@ func TestTenantGetCommand(t *testing.T) {
 				PodIP:        "10.0.1.5",
 			}, nil
 		},
+		GetStateFunc: func(ctx stdcontext.Context, id string) (*api.TenantState, error) {
+			return &api.TenantState{TenantID: id, Bytes: 900 << 20, QuotaBytes: 1 << 30, UsedPercent: 87, Level: "warning"}, nil
+		},
 	}
 
 	cmd := newTenantGetCmd(mockClient)
@@ -59,6 +85,7 @@ func TestTenantGetCommand(t *testing.T) {
 	assert.Contains(t, output, "alice")
 	assert.Contains(t, output, "running")
 	assert.Contains(t, output, "10.0.1.5")
+	assert.Contains(t, output, "State:         900.0 MiB / 1.0 GiB (87%, warning)")
 }
 
 func TestTenantDeleteCommand(t *testing.T) {
@@ -69,12 +96,16 @@ func TestTenantDeleteCommand(t *testing.T) {
 			deleted = true
 			return nil
 		},
+		PlanDeleteFunc: func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+			return &api.DeletePlan{TenantID: id, Deletes: []string{"pod tenants/zeroclaw-alice"}}, nil
+		},
 	}
 
 	cmd := newTenantDeleteCmd(mockClient)
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
+	cmd.SetIn(strings.NewReader("y\n"))
 	cmd.SetArgs([]string{"alice"})
 
 	err := cmd.Execute()
@@ -82,5 +113,170 @@ func TestTenantDeleteCommand(t *testing.T) {
 	assert.True(t, deleted)
 
 	output := buf.String()
+	assert.Contains(t, output, "will remove:\n  - pod tenants/zeroclaw-alice")
+	assert.Contains(t, output, "Delete tenant 'alice'? [y/N]")
 	assert.Contains(t, output, "deleted")
 }
+
+func TestTenantDeleteCommand_Confirmation(t *testing.T) {
+	mockClient := &api.MockClient{
+		DeleteTenantFunc: func(ctx stdcontext.Context, id string) error {
+			t.Fatal("an unconfirmed delete must not delete")
+			return nil
+		},
+		PlanDeleteFunc: func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+			return nil, errors.New(`tenant "alcie" not found`)
+		},
+	}
+	run := func(in string, args ...string) error {
+		cmd := newTenantDeleteCmd(mockClient)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetIn(strings.NewReader(in))
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	assert.ErrorContains(t, run("y\n", "alcie"), "not found", "a mistyped ID fails before the prompt")
+
+	mockClient.PlanDeleteFunc = func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+		return &api.DeletePlan{TenantID: id}, nil
+	}
+	assert.ErrorIs(t, run("n\n", "alice"), errNotConfirmed)
+	assert.ErrorIs(t, run("", "alice"), errNotConfirmed, "no input refuses")
+
+	var deleted bool
+	mockClient.DeleteTenantFunc = func(ctx stdcontext.Context, id string) error {
+		deleted = true
+		return nil
+	}
+	mockClient.PlanDeleteFunc = nil
+	assert.NoError(t, run("", "alice", "--yes"))
+	assert.True(t, deleted, "--yes skips the plan and the prompt")
+}
+
+func TestTenantDeleteCommand_DryRun(t *testing.T) {
+	mockClient := &api.MockClient{
+		DeleteTenantFunc: func(ctx stdcontext.Context, id string) error {
+			t.Fatal("dry run must not delete")
+			return nil
+		},
+		PlanDeleteFunc: func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+			return &api.DeletePlan{TenantID: id, Deletes: []string{"pod tenants/zeroclaw-alice", "pvc and registry record"}}, nil
+		},
+	}
+
+	cmd := newTenantDeleteCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"alice", "--dry-run"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "would remove")
+	assert.Contains(t, buf.String(), "pod tenants/zeroclaw-alice")
+}
+
+func TestTenantDeleteCommand_Purge(t *testing.T) {
+	purgeAt := time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC)
+	var purged bool
+	mockClient := &api.MockClient{
+		DeleteTenantFunc: func(ctx stdcontext.Context, id string) error {
+			t.Fatal("--purge must purge")
+			return nil
+		},
+		PlanDeleteFunc: func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+			return &api.DeletePlan{TenantID: id, Deletes: []string{"pod tenants/zeroclaw-alice"},
+				Keeps: []string{"registry record"}, PurgeAt: purgeAt}, nil
+		},
+		PlanPurgeFunc: func(ctx stdcontext.Context, id string) (*api.DeletePlan, error) {
+			return &api.DeletePlan{TenantID: id, Deletes: []string{"pod tenants/zeroclaw-alice", "registry record"}}, nil
+		},
+		PurgeTenantFunc: func(ctx stdcontext.Context, id string) error {
+			purged = true
+			return nil
+		},
+	}
+	run := func(args ...string) string {
+		cmd := newTenantDeleteCmd(mockClient)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetIn(strings.NewReader("y\n"))
+		cmd.SetArgs(args)
+		assert.NoError(t, cmd.Execute())
+		return buf.String()
+	}
+
+	out := run("alice", "--dry-run")
+	assert.Contains(t, out, "and keep for a restore until "+purgeAt.Local().Format(time.RFC3339)+":\n  - registry record")
+
+	out = run("alice", "--purge")
+	assert.True(t, purged)
+	assert.Contains(t, out, "will remove:\n  - pod tenants/zeroclaw-alice\n  - registry record\n")
+	assert.NotContains(t, out, "keep for a restore")
+}
+
+func TestTenantRestoreCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		RestoreTenantFunc: func(ctx stdcontext.Context, id string) (*api.Tenant, error) {
+			assert.Equal(t, "alice", id)
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantRestoreCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Tenant 'alice' restored")
+}
+
+func TestTenantListCommand_Watch(t *testing.T) {
+	cachePath = filepath.Join(t.TempDir(), "cache.json")
+	defer func() { listWatch = false }()
+	mockClient := &api.MockClient{
+		ListTenantsFunc: func(ctx stdcontext.Context, opts api.ListOptions) ([]api.Tenant, error) {
+			return []api.Tenant{
+				{TenantID: "alice", Status: "idle"},
+				{TenantID: "bob", Status: "running"},
+			}, nil
+		},
+		WatchTenantsFunc: func(ctx stdcontext.Context, tenantID string, fn func(api.TenantChange) error) error {
+			for _, change := range []api.TenantChange{
+				{Type: "added", Tenant: api.Tenant{TenantID: "alice", Status: "idle"}},
+				{Type: "added", Tenant: api.Tenant{TenantID: "bob", Status: "running"}},
+				{Type: "modified", Tenant: api.Tenant{TenantID: "alice", Status: "provisioning"}},
+				{Type: "modified", Tenant: api.Tenant{TenantID: "alice", Status: "provisioning", PodIP: "10.0.0.1"}},
+				{Type: "modified", Tenant: api.Tenant{TenantID: "alice", Status: "running"}},
+				{Type: "added", Tenant: api.Tenant{TenantID: "carol", Status: "idle"}},
+				{Type: "deleted", Tenant: api.Tenant{TenantID: "bob"}},
+			} {
+				if err := fn(change); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd := newTenantListCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--watch"})
+	require.NoError(t, cmd.Execute())
+
+	var changes []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[4:] { // after the progress line, header and list
+		changes = append(changes, line[len("15:04:05  "):])
+	}
+	assert.Equal(t, []string{
+		"alice  idle -> provisioning",
+		"alice  provisioning -> running",
+		"carol  added (idle)",
+		"bob  deleted",
+	}, changes)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantRestartCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart <tenant-id>",
+		Short: "Replace a running tenant pod without downtime",
+		Long: `Restart a running tenant with a blue/green pod replacement.
+
+A new pod is started alongside the current one; once it is ready, the
+registry and router cache are switched to it and the old pod is drained and
+deleted. Use this to roll out tier, image or DNS changes to a running pod
+(e.g. keep-warm tenants) without losing messages.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+			spinner := output.NewSpinner(cmd.ErrOrStderr(), noColor)
+			spinner.Start(fmt.Sprintf("Starting replacement pod for tenant '%s'", tenantID))
+
+			ctx, cancel := commandContext(wakeTimeout)
+			defer cancel()
+
+			result, err := client.RestartTenant(ctx, tenantID)
+			elapsed := spinner.Stop()
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to restart tenant after %s: %v", output.FormatElapsed(elapsed), err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(result)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' restarted in %s", tenantID, output.FormatElapsed(elapsed)))
+			fmt.Fprintf(cmd.OutOrStdout(), "Pod Name:      %s\n", result.PodName)
+			fmt.Fprintf(cmd.OutOrStdout(), "Pod IP:        %s\n", result.PodIP)
+			fmt.Fprintf(cmd.OutOrStdout(), "Replaced:      %s\n", result.PreviousPod)
+			return nil
+		},
+	}
+}
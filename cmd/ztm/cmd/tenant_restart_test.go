@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantRestartCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		RestartTenantFunc: func(ctx stdcontext.Context, id string) (*api.RestartResult, error) {
+			assert.Equal(t, "alice", id)
+			return &api.RestartResult{
+				TenantID:    "alice",
+				PodName:     "zeroclaw-alice-abc123",
+				PodIP:       "10.0.0.6",
+				PreviousPod: "zeroclaw-alice",
+			}, nil
+		},
+	}
+
+	cmd := newTenantRestartCmd(mockClient)
+	buf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(errBuf)
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "zeroclaw-alice-abc123")
+	assert.Contains(t, buf.String(), "10.0.0.6")
+	assert.Contains(t, errBuf.String(), "Starting replacement pod for tenant 'alice'")
+}
+
+func TestTenantRestartCommand_Error(t *testing.T) {
+	mockClient := &api.MockClient{
+		RestartTenantFunc: func(ctx stdcontext.Context, id string) (*api.RestartResult, error) {
+			return nil, fmt.Errorf("tenant alice is not running")
+		},
+	}
+
+	cmd := newTenantRestartCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
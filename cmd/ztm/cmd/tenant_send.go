@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantSendCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "send <tenant-id> <text>",
+		Short: "Send a tenant's agent a test message and print its reply",
+		Long: `Send a message to a tenant's agent the way the router forwards a Telegram
+update, waking the pod first if needed, and print the agent's reply. Nothing
+is sent to Telegram and the message isn't metered, so it is safe for smoke
+tests after a deployment.
+
+Fails if the wake is refused or the agent doesn't answer, so it can gate a
+CI pipeline.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID, text := args[0], args[1]
+			styler := output.NewStyler(noColor)
+			spinner := output.NewSpinner(cmd.ErrOrStderr(), noColor)
+			spinner.Start(fmt.Sprintf("Sending test message to '%s'", tenantID))
+
+			for _, p := range wakePhases {
+				t := time.AfterFunc(p.after, func() { spinner.Phase(p.msg) })
+				defer t.Stop()
+			}
+
+			ctx, cancel := commandContext(wakeTimeout)
+			defer cancel()
+
+			resp, err := client.SendTestMessage(ctx, tenantID, text)
+			elapsed := spinner.Stop()
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Test message failed after %s: %v", output.FormatElapsed(elapsed), err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(resp)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			start := "warm"
+			if resp.ColdStart {
+				start = "cold start"
+			}
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' replied (%s)", tenantID, output.FormatElapsed(elapsed)))
+			fmt.Fprintf(cmd.OutOrStdout(), "Pod IP:        %s\n", resp.PodIP)
+			fmt.Fprintf(cmd.OutOrStdout(), "Wake:          %s (%s)\n", time.Duration(resp.WakeMs)*time.Millisecond, start)
+			fmt.Fprintf(cmd.OutOrStdout(), "Reply:         %s\n", time.Duration(resp.ReplyMs)*time.Millisecond)
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s\n", resp.Reply)
+			return nil
+		},
+	}
+}
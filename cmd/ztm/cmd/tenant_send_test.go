@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"errors"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantSendCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		SendTestMessageFunc: func(ctx stdcontext.Context, id, text string) (*api.TestMessageResult, error) {
+			assert.Equal(t, "alice", id)
+			if text == "fail" {
+				return nil, errors.New("agent: status 500")
+			}
+			return &api.TestMessageResult{TenantID: id, PodIP: "10.0.0.5", ColdStart: true, WakeMs: 4200, ReplyMs: 800, Reply: "pong"}, nil
+		},
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newTenantSendCmd(mockClient)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("alice", "ping")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "pong")
+	assert.Contains(t, out, "4.2s (cold start)")
+
+	_, err = run("alice", "fail")
+	assert.ErrorContains(t, err, "status 500")
+}
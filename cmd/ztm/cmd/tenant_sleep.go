@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantSleepCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sleep <tenant-id>",
+		Short: "Hibernate a running tenant now",
+		Long: `Stop a running tenant's pod without waiting for its idle timeout.
+
+The pod gets the idle grace period to flush its state, exactly as when the
+idle timeout fires, and the tenant becomes idle. The next message wakes it.
+Users can do the same by sending /sleep to their bot.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			result, err := client.SleepTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to put tenant to sleep: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(result)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' is asleep (stopped pod %s)", tenantID, result.PodName))
+			return nil
+		},
+	}
+}
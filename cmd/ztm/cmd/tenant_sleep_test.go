@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantSleepCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		SleepTenantFunc: func(ctx stdcontext.Context, id string) (*api.SleepResult, error) {
+			assert.Equal(t, "alice", id)
+			return &api.SleepResult{TenantID: "alice", PodName: "zeroclaw-alice"}, nil
+		},
+	}
+
+	cmd := newTenantSleepCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "zeroclaw-alice")
+}
+
+func TestTenantSleepCommand_Error(t *testing.T) {
+	mockClient := &api.MockClient{
+		SleepTenantFunc: func(ctx stdcontext.Context, id string) (*api.SleepResult, error) {
+			return nil, fmt.Errorf("API call failed: tenant not running")
+		},
+	}
+
+	cmd := newTenantSleepCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
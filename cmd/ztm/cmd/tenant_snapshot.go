@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/shawn/agentic-tenancy/internal/bytesize"
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// snapshotQuiesce is --quiesce on 'tenant snapshot create'
+var snapshotQuiesce bool
+
+func newTenantSnapshotCreateCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <tenant-id>",
+		Short: "Snapshot a tenant's state",
+		Long: `Copy a tenant's S3 state to a new snapshot.
+
+Without --quiesce the state is copied as the pod last wrote it to S3, which
+may be mid-write. --quiesce stops a running pod first, with the idle grace
+period so it can flush its state; the next message wakes it again. The
+oldest snapshots beyond the orchestrator's limit (SNAPSHOT_KEEP) are deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(snapshotTimeout)
+			defer cancel()
+
+			result, err := client.CreateSnapshot(ctx, tenantID, snapshotQuiesce)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to snapshot tenant: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(result)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			msg := fmt.Sprintf("Snapshot %s of tenant '%s' taken (%d objects, %s)", result.Snapshot.ID, tenantID,
+				result.Snapshot.Objects, bytesize.Format(result.Snapshot.Bytes))
+			if result.PodName != "" {
+				msg += fmt.Sprintf("; stopped pod %s", result.PodName)
+			}
+			styler.FprintSuccess(cmd.OutOrStdout(), msg)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&snapshotQuiesce, "quiesce", false, "Stop a running pod first so the copy is consistent")
+	return cmd
+}
+
+func newTenantSnapshotListCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <tenant-id>",
+		Short: "List a tenant's snapshots, newest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			snaps, err := client.ListSnapshots(ctx, args[0])
+			if err != nil {
+				styler := output.NewStyler(noColor)
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to list snapshots: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(snaps)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			// Table format
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SNAPSHOT\tCREATED\tOBJECTS\tSIZE")
+			for _, s := range snaps {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", s.ID, s.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+					s.Objects, bytesize.Format(s.Bytes))
+			}
+			w.Flush()
+
+			return nil
+		},
+	}
+}
+
+func newTenantSnapshotRestoreCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <tenant-id> <snapshot>",
+		Short: "Roll a tenant's state back to a snapshot",
+		Long: `Replace a tenant's S3 state with a snapshot, e.g. after its agent corrupted
+its own memory.
+
+A running pod is stopped first, with the idle grace period, and its current
+state is kept as a new snapshot so the restore can be undone. The next
+message wakes the tenant on the restored state.
+
+Asks for confirmation first; --yes skips the question.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID, snapshotID := args[0], args[1]
+			if err := confirm(cmd, fmt.Sprintf("Replace the state of tenant '%s' with snapshot %s?", tenantID, snapshotID)); err != nil {
+				return err
+			}
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(snapshotTimeout)
+			defer cancel()
+
+			result, err := client.RestoreSnapshot(ctx, tenantID, snapshotID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to restore snapshot: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(result)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' restored to snapshot %s (%d objects)",
+				tenantID, result.Snapshot, result.Objects))
+			if result.Backup != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "The state it replaced is snapshot %s\n", result.Backup)
+			}
+			return nil
+		},
+	}
+	addConfirmFlag(cmd)
+	return cmd
+}
+
+func newTenantSnapshotDeleteCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <tenant-id> <snapshot>",
+		Short: "Delete a snapshot",
+		Long: `Delete one of a tenant's snapshots.
+
+Asks for confirmation first; --yes skips the question.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID, snapshotID := args[0], args[1]
+			if err := confirm(cmd, fmt.Sprintf("Delete snapshot %s of tenant '%s'?", snapshotID, tenantID)); err != nil {
+				return err
+			}
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			if err := client.DeleteSnapshot(ctx, tenantID, snapshotID); err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to delete snapshot: %v", err))
+				return err
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Snapshot %s deleted", snapshotID))
+			return nil
+		},
+	}
+	addConfirmFlag(cmd)
+	return cmd
+}
+
+func newTenantSnapshotCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Snapshot a tenant's state and roll it back",
+		Long: `Manage point-in-time copies of a tenant's S3 state.
+
+Snapshots are kept outside the tenant's own prefix, so its pod can't change
+them, and are deleted with the tenant when it is purged.`,
+	}
+
+	cmd.AddCommand(newTenantSnapshotCreateCmd(client))
+	cmd.AddCommand(newTenantSnapshotListCmd(client))
+	cmd.AddCommand(newTenantSnapshotRestoreCmd(client))
+	cmd.AddCommand(newTenantSnapshotDeleteCmd(client))
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantSnapshotCommands(t *testing.T) {
+	const id = "20261015T090000.000Z"
+	var restored string
+	mockClient := &api.MockClient{
+		CreateSnapshotFunc: func(ctx stdcontext.Context, tenantID string, quiesce bool) (*api.SnapshotResult, error) {
+			assert.True(t, quiesce)
+			return &api.SnapshotResult{TenantID: tenantID, PodName: "zeroclaw-alice",
+				Snapshot: api.Snapshot{ID: id, Objects: 3, Bytes: 2048}}, nil
+		},
+		ListSnapshotsFunc: func(ctx stdcontext.Context, tenantID string) ([]api.Snapshot, error) {
+			return []api.Snapshot{{ID: id, CreatedAt: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC), Objects: 3, Bytes: 2048}}, nil
+		},
+		RestoreSnapshotFunc: func(ctx stdcontext.Context, tenantID, snapshotID string) (*api.SnapshotRestoreResult, error) {
+			restored = snapshotID
+			return &api.SnapshotRestoreResult{TenantID: tenantID, Snapshot: snapshotID, Backup: "20261015T100000.000Z", Objects: 3}, nil
+		},
+	}
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := newTenantSnapshotCmd(mockClient)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("", "create", "alice", "--quiesce")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Snapshot "+id+" of tenant 'alice' taken (3 objects, 2.0 KiB); stopped pod zeroclaw-alice")
+
+	out, err = run("", "list", "alice")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "SNAPSHOT")
+	assert.Contains(t, out, id)
+
+	_, err = run("n\n", "restore", "alice", id)
+	assert.ErrorIs(t, err, errNotConfirmed)
+	assert.Empty(t, restored)
+
+	out, err = run("y\n", "restore", "alice", id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, restored)
+	assert.Contains(t, out, "The state it replaced is snapshot 20261015T100000.000Z")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+var statusLinkTTL time.Duration
+
+func newTenantStatusLinkCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status-link <tenant-id>",
+		Short: "Generate a shareable status page link for a tenant",
+		Long: `Generate a signed link to the tenant's public status page on the router.
+
+The page shows whether the agent is online, its last activity, and how many
+of its starts succeeded and how many incidents (OOM kills, crash loops) it
+had over the last 7 days — nothing about its configuration. Anyone with the
+link can open it until it expires (--ttl, default 30 days, at most a year);
+rotating STATUS_PAGE_SECRET revokes every link. Append ?format=json for a
+machine-readable version.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			link, err := client.CreateStatusLink(ctx, tenantID, statusLinkTTL)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to generate status link: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(link)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Link:     %s\n", link.URL)
+			fmt.Fprintf(cmd.OutOrStdout(), "Expires:  %s\n", link.ExpiresAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&statusLinkTTL, "ttl", 0, "How long the link stays valid, e.g. 168h (default 30 days)")
+
+	return cmd
+}
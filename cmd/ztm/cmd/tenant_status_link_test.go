@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantStatusLinkCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		StatusLinkFunc: func(ctx stdcontext.Context, id string, ttl time.Duration) (*api.StatusLink, error) {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, 7*24*time.Hour, ttl)
+			return &api.StatusLink{
+				TenantID:  id,
+				URL:       "https://router.example.com/status/YWxpY2U.1767225600.sig",
+				ExpiresAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+	}
+
+	cmd := newTenantStatusLinkCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--ttl", "168h"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "https://router.example.com/status/YWxpY2U.1767225600.sig")
+	assert.Contains(t, buf.String(), "2026-01-01T00:00:00Z")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantSuspendCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "suspend <tenant-id>",
+		Short: "Suspend a tenant without deleting it",
+		Long: `Put a tenant on hold, e.g. for billing delinquency.
+
+Its pod is stopped with the idle grace period so it can flush its state, and
+until it is resumed its users get a "bot paused" reply instead of a wake.
+Nothing is deleted: the tenant record, volume and S3 state are kept.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			result, err := client.SuspendTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to suspend tenant: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				return printSuspendJSON(cmd, result)
+			}
+
+			msg := fmt.Sprintf("Tenant '%s' suspended", tenantID)
+			if result.PodName != "" {
+				msg += fmt.Sprintf(" (stopped pod %s)", result.PodName)
+			}
+			styler.FprintSuccess(cmd.OutOrStdout(), msg)
+			return nil
+		},
+	}
+}
+
+func newTenantResumeCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume <tenant-id>",
+		Short: "Lift a tenant's suspension",
+		Long: `Resume a suspended tenant. It becomes idle and its next message wakes it
+as usual.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			result, err := client.ResumeTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to resume tenant: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				return printSuspendJSON(cmd, result)
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' resumed", tenantID))
+			return nil
+		},
+	}
+}
+
+func newTenantResetCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset <tenant-id>",
+		Short: "Clear a failed tenant so it can be woken again",
+		Long: `Reset a tenant marked failed after too many wakes in a row failed.
+
+'ztm tenant get' shows its last error and 'ztm tenant describe' the wake
+history; fix the cause first. The tenant becomes idle and its next message
+wakes it as usual.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			result, err := client.ResetTenant(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to reset tenant: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				return printSuspendJSON(cmd, result)
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' reset", tenantID))
+			return nil
+		},
+	}
+}
+
+func printSuspendJSON(cmd *cobra.Command, result *api.SuspendResult) error {
+	jsonStr, err := output.FormatJSON(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantSuspendCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		SuspendTenantFunc: func(ctx stdcontext.Context, id string) (*api.SuspendResult, error) {
+			assert.Equal(t, "alice", id)
+			return &api.SuspendResult{TenantID: "alice", Status: "suspended", PodName: "zeroclaw-alice"}, nil
+		},
+	}
+
+	cmd := newTenantSuspendCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "suspended (stopped pod zeroclaw-alice)")
+}
+
+func TestTenantResumeCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ResumeTenantFunc: func(ctx stdcontext.Context, id string) (*api.SuspendResult, error) {
+			return nil, fmt.Errorf("API call failed: tenant not suspended")
+		},
+	}
+
+	cmd := newTenantResumeCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
+
+func TestTenantResetCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		ResetTenantFunc: func(ctx stdcontext.Context, id string) (*api.SuspendResult, error) {
+			assert.Equal(t, "alice", id)
+			return &api.SuspendResult{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantResetCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Tenant 'alice' reset")
+}
@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	stdcontext "context"
 	"fmt"
-	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
 	"github.com/shawn/agentic-tenancy/internal/cli/output"
@@ -13,24 +11,144 @@ import (
 var (
 	updateBotToken    string
 	updateIdleTimeout int
+	updateTier        string
+	updateImage       string
+	updateDNS         api.DNSConfig
+	updateClearDNS    bool
+	updateKeepWarm    bool
+	updateNoAnnounce  bool
+	updateDigest      bool
+	updateLogForward  api.LogForwardConfig
+	updateClearLog    bool
+	updateAllowed     []string
+	updateSlack       api.SlackConfig
+	updateClearSlack  bool
+	updateLocale      string
+	updateTimezone    string
+	updateResizeNow   bool
+	updateDisabled    bool
+	updateMaxAge      int
+	updateMaxReply    int
 	updateBotTokenSet bool
 	updateTimeoutSet  bool
+	updateTierSet     bool
+	updateImageSet    bool
+	updateDNSSet      bool
+	updateKeepSet     bool
+	updateAnnounceSet bool
+	updateDigestSet   bool
+	updateLogSet      bool
+	updateAllowedSet  bool
+	updateSlackSet    bool
+	updateLocaleSet   bool
+	updateTimezoneSet bool
+	updateDisabledSet bool
+	updateMaxAgeSet   bool
+	updateMaxReplySet bool
 )
 
 func newTenantUpdateCmd(client api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update <tenant-id>",
 		Short: "Update tenant configuration",
-		Long: `Update bot token and/or idle timeout for an existing tenant.
+		Long: `Update bot token, idle timeout, tier, image pin, DNS settings, keep-warm,
+log forwarding, subscribed Telegram update types, the Slack app and/or the
+locale and time zone of system messages for an existing tenant.
 
-At least one of --bot-token or --idle-timeout must be specified.`,
-		Args: cobra.ExactArgs(1),
+At least one of --bot-token, --idle-timeout, --tier, --image, --keep-warm,
+--no-announcements, --weekly-digest, --allowed-updates, --locale, --timezone,
+--disabled, --max-message-age, --max-reply-bytes, a --dns-*, --log-* or
+--slack-* flag must be specified. Use --image "" to unpin a tenant so it follows the
+default channel. The --dns-* flags replace the tenant's DNS override as a
+whole; --clear-dns reverts to the tier's settings. Tier, image and DNS
+changes apply the next time the tenant's pod starts — run 'ztm tenant
+restart' to apply them to a running pod without downtime.
+
+A tier change is tracked as a resize (shown by 'ztm tenant get'). With
+--resize-now, a running keep-warm tenant is moved to the new tier right away
+by a blue/green replacement; other tenants pick it up at their next wake.
+
+--log-chat and --log-webhook forward the agent's error logs to the tenant
+owner (via the tenant's own bot, and/or as JSON POSTs to an https URL). They
+replace the forwarding targets as a whole; --clear-log-forward disables it.
+The same targets receive 'ztm admin broadcast' announcements unless
+--no-announcements is set, and with --weekly-digest a summary of the
+tenant's messages, uptime, estimated cost and errors every Monday morning
+in its time zone.
+
+--allowed-updates re-registers the bot's webhook for the given update types
+(message, edited_message, channel_post, callback_query, business_message,
+edited_business_message); --allowed-updates ""
+restores the default (message only).
+
+--slack-signing-secret and --slack-bot-token connect a Slack app (both are
+required); point the app's Event Subscriptions Request URL at
+https://<router>/slack/<tenant-id>. --clear-slack disconnects it.
+
+--locale sets the language of the router's own messages ("starting up",
+/reset and /sleep replies) and of log forwarding notifications; --timezone
+sets the time zone their timestamps are shown in. Pass "" to restore the
+defaults (en, UTC).
+
+--disabled is the tenant's kill switch, for security incidents: the router
+stops forwarding its messages and answers users with an outage message, and
+wakes are refused. A running pod is left alone. --disabled=false restores
+service.
+
+--max-message-age overrides the router's MAX_MESSAGE_AGE_S for the tenant:
+messages older than this many seconds when they reach the router (say, a
+backlog Telegram delivers after an outage) are skipped, and the user is told
+to resend them. -1 forwards messages of any age; 0 restores the router's
+default.
+
+--max-reply-bytes overrides the router's MAX_REPLY_BYTES for the tenant:
+agent replies longer than this are cut short in chat, with a link to the
+full reply saved in the tenant's S3 prefix. -1 sends replies of any length;
+0 restores the router's default.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTenantIDs(client),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			updateBotTokenSet = cmd.Flags().Changed("bot-token")
 			updateTimeoutSet = cmd.Flags().Changed("idle-timeout")
+			updateTierSet = cmd.Flags().Changed("tier")
+			updateImageSet = cmd.Flags().Changed("image")
+			updateDNSSet = updateClearDNS || cmd.Flags().Changed("dns-policy") ||
+				cmd.Flags().Changed("dns-nameserver") || cmd.Flags().Changed("dns-search") ||
+				cmd.Flags().Changed("dns-option")
+
+			updateKeepSet = cmd.Flags().Changed("keep-warm")
+			updateAnnounceSet = cmd.Flags().Changed("no-announcements")
+			updateDigestSet = cmd.Flags().Changed("weekly-digest")
+			updateLogSet = updateClearLog || cmd.Flags().Changed("log-chat") || cmd.Flags().Changed("log-webhook")
+			updateAllowedSet = cmd.Flags().Changed("allowed-updates")
+			updateSlackSet = updateClearSlack || cmd.Flags().Changed("slack-signing-secret") || cmd.Flags().Changed("slack-bot-token")
 
-			if !updateBotTokenSet && !updateTimeoutSet {
-				return fmt.Errorf("at least one of --bot-token or --idle-timeout must be specified")
+			updateLocaleSet = cmd.Flags().Changed("locale")
+			updateTimezoneSet = cmd.Flags().Changed("timezone")
+			updateDisabledSet = cmd.Flags().Changed("disabled")
+			updateMaxAgeSet = cmd.Flags().Changed("max-message-age")
+			updateMaxReplySet = cmd.Flags().Changed("max-reply-bytes")
+
+			if !updateBotTokenSet && !updateTimeoutSet && !updateTierSet && !updateImageSet && !updateDNSSet && !updateKeepSet && !updateAnnounceSet && !updateDigestSet && !updateLogSet && !updateAllowedSet && !updateSlackSet && !updateLocaleSet && !updateTimezoneSet && !updateDisabledSet && !updateMaxAgeSet && !updateMaxReplySet {
+				return fmt.Errorf("at least one of --bot-token, --idle-timeout, --tier, --image, --keep-warm, --no-announcements, --weekly-digest, --allowed-updates, --locale, --timezone, --disabled, --max-message-age, --max-reply-bytes, --dns-*, --log-* or --slack-* must be specified")
+			}
+			if updateMaxAgeSet && updateMaxAge < -1 {
+				return fmt.Errorf("--max-message-age must be -1 (off), 0 (router default) or a number of seconds")
+			}
+			if updateMaxReplySet && updateMaxReply < -1 {
+				return fmt.Errorf("--max-reply-bytes must be -1 (any length), 0 (router default) or a number of bytes")
+			}
+			if updateResizeNow && !updateTierSet {
+				return fmt.Errorf("--resize-now requires --tier")
+			}
+			if updateClearDNS && !updateDNS.IsZero() {
+				return fmt.Errorf("--clear-dns cannot be combined with other --dns-* flags")
+			}
+			if updateClearLog && !updateLogForward.IsZero() {
+				return fmt.Errorf("--clear-log-forward cannot be combined with --log-chat or --log-webhook")
+			}
+			if updateClearSlack && !updateSlack.IsZero() {
+				return fmt.Errorf("--clear-slack cannot be combined with --slack-signing-secret or --slack-bot-token")
 			}
 			return nil
 		},
@@ -46,8 +164,51 @@ At least one of --bot-token or --idle-timeout must be specified.`,
 			if updateTimeoutSet {
 				req.IdleTimeoutS = &updateIdleTimeout
 			}
+			if updateTierSet {
+				req.Tier = &updateTier
+				req.Resize = updateResizeNow
+			}
+			if updateImageSet {
+				req.Image = &updateImage
+			}
+			if updateDNSSet {
+				req.DNS = &updateDNS
+			}
+			if updateKeepSet {
+				req.KeepWarm = &updateKeepWarm
+			}
+			if updateAnnounceSet {
+				req.NoAnnouncements = &updateNoAnnounce
+			}
+			if updateDigestSet {
+				req.WeeklyDigest = &updateDigest
+			}
+			if updateLogSet {
+				req.LogForward = &updateLogForward
+			}
+			if updateAllowedSet {
+				req.AllowedUpdates = &updateAllowed
+			}
+			if updateSlackSet {
+				req.Slack = &updateSlack
+			}
+			if updateLocaleSet {
+				req.Locale = &updateLocale
+			}
+			if updateTimezoneSet {
+				req.Timezone = &updateTimezone
+			}
+			if updateDisabledSet {
+				req.Disabled = &updateDisabled
+			}
+			if updateMaxAgeSet {
+				req.MaxMessageAgeS = &updateMaxAge
+			}
+			if updateMaxReplySet {
+				req.MaxReplyBytes = &updateMaxReply
+			}
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
 			tenant, err := client.UpdateTenant(ctx, tenantID, req)
@@ -69,6 +230,18 @@ At least one of --bot-token or --idle-timeout must be specified.`,
 				fmt.Fprintf(cmd.OutOrStdout(), "\nTenant ID:     %s\n", tenant.TenantID)
 				fmt.Fprintf(cmd.OutOrStdout(), "Status:        %s\n", tenant.Status)
 				fmt.Fprintf(cmd.OutOrStdout(), "Idle Timeout:  %ds\n", tenant.IdleTimeoutS)
+				if tenant.Tier != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "Tier:          %s\n", tenant.Tier)
+				}
+				if tenant.Image != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "Image:         %s\n", tenant.Image)
+				}
+				if updateTierSet && tenant.Resize != nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "Resize:        %s\n", formatResize(tenant.Resize))
+				}
+				if tenant.Disabled {
+					fmt.Fprintf(cmd.OutOrStdout(), "Disabled:      yes (kill switch)\n")
+				}
 			}
 
 			return nil
@@ -77,6 +250,29 @@ At least one of --bot-token or --idle-timeout must be specified.`,
 
 	cmd.Flags().StringVar(&updateBotToken, "bot-token", "", "New Telegram bot token")
 	cmd.Flags().IntVar(&updateIdleTimeout, "idle-timeout", 0, "New idle timeout in seconds")
+	cmd.Flags().StringVar(&updateTier, "tier", "", "New service tier")
+	cmd.Flags().BoolVar(&updateResizeNow, "resize-now", false, "Replace a running keep-warm tenant's pod on the new tier now")
+	cmd.Flags().StringVar(&updateImage, "image", "", "Image alias or tag to pin (empty to unpin)")
+	cmd.Flags().StringVar(&updateDNS.Policy, "dns-policy", "", "Pod dnsPolicy: ClusterFirst|ClusterFirstWithHostNet|Default|None")
+	cmd.Flags().StringSliceVar(&updateDNS.Nameservers, "dns-nameserver", nil, "DNS nameserver IP (repeatable, max 3)")
+	cmd.Flags().StringSliceVar(&updateDNS.Searches, "dns-search", nil, "DNS search domain (repeatable)")
+	cmd.Flags().StringSliceVar(&updateDNS.Options, "dns-option", nil, "resolv.conf option, e.g. ndots:2 (repeatable)")
+	cmd.Flags().BoolVar(&updateClearDNS, "clear-dns", false, "Remove the tenant's DNS override")
+	cmd.Flags().BoolVar(&updateKeepWarm, "keep-warm", false, "Never stop the pod on idle timeout (--keep-warm=false to revert)")
+	cmd.Flags().BoolVar(&updateNoAnnounce, "no-announcements", false, "Leave the owner out of fleet-wide announcements (--no-announcements=false to revert)")
+	cmd.Flags().BoolVar(&updateDigest, "weekly-digest", false, "Send the owner a weekly usage digest via the log forwarding targets (--weekly-digest=false to stop)")
+	cmd.Flags().Int64Var(&updateLogForward.TelegramChatID, "log-chat", 0, "Telegram chat ID to receive agent error logs")
+	cmd.Flags().StringVar(&updateLogForward.WebhookURL, "log-webhook", "", "https URL to POST agent error logs to")
+	cmd.Flags().BoolVar(&updateClearLog, "clear-log-forward", false, "Stop forwarding agent error logs")
+	cmd.Flags().StringSliceVar(&updateAllowed, "allowed-updates", nil, "Telegram update types to subscribe the bot to (empty for the default)")
+	cmd.Flags().StringVar(&updateSlack.SigningSecret, "slack-signing-secret", "", "Slack app signing secret")
+	cmd.Flags().StringVar(&updateSlack.BotToken, "slack-bot-token", "", "Slack app bot token (xoxb-...)")
+	cmd.Flags().BoolVar(&updateClearSlack, "clear-slack", false, "Disconnect the tenant's Slack app")
+	cmd.Flags().StringVar(&updateLocale, "locale", "", "Language for system messages, e.g. es (empty for the default)")
+	cmd.Flags().StringVar(&updateTimezone, "timezone", "", "IANA time zone for notification timestamps (empty for UTC)")
+	cmd.Flags().BoolVar(&updateDisabled, "disabled", false, "Stop forwarding and wakes for the tenant (--disabled=false to restore)")
+	cmd.Flags().IntVar(&updateMaxAge, "max-message-age", 0, "Skip messages older than this many seconds (-1 = never, 0 = router default)")
+	cmd.Flags().IntVar(&updateMaxReply, "max-reply-bytes", 0, "Cut replies longer than this many bytes short, linking the full reply (-1 = never, 0 = router default)")
 
 	return cmd
 }
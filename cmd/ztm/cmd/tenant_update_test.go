@@ -90,3 +90,272 @@ func TestTenantUpdateCommand_NoFlags(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "at least one")
 }
+
+func TestTenantUpdateCommand_Tier(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			assert.Nil(t, req.BotToken)
+			assert.Nil(t, req.IdleTimeoutS)
+			assert.NotNil(t, req.Tier)
+			assert.Equal(t, "premium", *req.Tier)
+			return &api.Tenant{TenantID: id, Status: "idle", Tier: "premium"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--tier", "premium"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "premium")
+}
+
+func TestTenantUpdateCommand_ResizeNow(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			assert.Equal(t, "premium", *req.Tier)
+			assert.True(t, req.Resize)
+			return &api.Tenant{TenantID: id, Status: "running", Tier: "premium",
+				Resize: &api.ResizeOp{FromTier: "standard", ToTier: "premium", Status: "replacing"}}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--tier", "premium", "--resize-now"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "standard → premium (replacing)")
+
+	cmd = newTenantUpdateCmd(mockClient)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"alice", "--keep-warm", "--resize-now"})
+	err = cmd.Execute()
+	assert.ErrorContains(t, err, "--resize-now requires --tier")
+}
+
+func TestTenantUpdateCommand_Unpin(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.Image) {
+				assert.Equal(t, "", *req.Image)
+			}
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--image", ""})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestTenantUpdateCommand_DNS(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.DNS) {
+				assert.Equal(t, "None", req.DNS.Policy)
+				assert.Equal(t, []string{"10.0.0.2", "10.0.0.3"}, req.DNS.Nameservers)
+				assert.Equal(t, []string{"ndots:2"}, req.DNS.Options)
+			}
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--dns-policy", "None", "--dns-nameserver", "10.0.0.2,10.0.0.3", "--dns-option", "ndots:2"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestTenantUpdateCommand_KeepWarm(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.KeepWarm) {
+				assert.False(t, *req.KeepWarm)
+			}
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--keep-warm=false"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestTenantUpdateCommand_WeeklyDigest(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.WeeklyDigest) {
+				assert.True(t, *req.WeeklyDigest)
+			}
+			assert.Nil(t, req.KeepWarm)
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--weekly-digest"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestTenantUpdateCommand_LogForward(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.LogForward) {
+				assert.Equal(t, int64(123456), req.LogForward.TelegramChatID)
+				assert.Equal(t, "https://example.com/hook", req.LogForward.WebhookURL)
+			}
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--log-chat", "123456", "--log-webhook", "https://example.com/hook"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestTenantUpdateCommand_AllowedUpdates(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"alice", "--allowed-updates", "message,callback_query"}, []string{"message", "callback_query"}},
+		{[]string{"alice", "--allowed-updates", ""}, []string{}},
+	}
+	for _, tt := range tests {
+		mockClient := &api.MockClient{
+			UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+				if assert.NotNil(t, req.AllowedUpdates) {
+					assert.Equal(t, tt.want, *req.AllowedUpdates)
+				}
+				return &api.Tenant{TenantID: id, Status: "idle"}, nil
+			},
+		}
+
+		cmd := newTenantUpdateCmd(mockClient)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(tt.args)
+
+		assert.NoError(t, cmd.Execute())
+	}
+}
+
+func TestTenantUpdateCommand_Slack(t *testing.T) {
+	tests := []struct {
+		args []string
+		want api.SlackConfig
+	}{
+		{[]string{"alice", "--slack-signing-secret", "s3cret", "--slack-bot-token", "xoxb-1"}, api.SlackConfig{SigningSecret: "s3cret", BotToken: "xoxb-1"}},
+		{[]string{"alice", "--clear-slack"}, api.SlackConfig{}},
+	}
+	for _, tt := range tests {
+		mockClient := &api.MockClient{
+			UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+				if assert.NotNil(t, req.Slack) {
+					assert.Equal(t, tt.want, *req.Slack)
+				}
+				return &api.Tenant{TenantID: id, Status: "idle"}, nil
+			},
+		}
+
+		cmd := newTenantUpdateCmd(mockClient)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(tt.args)
+
+		assert.NoError(t, cmd.Execute())
+	}
+
+	cmd := newTenantUpdateCmd(&api.MockClient{})
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--clear-slack", "--slack-bot-token", "xoxb-1"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestTenantUpdateCommand_Locale(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.Locale) && assert.NotNil(t, req.Timezone) {
+				assert.Equal(t, "es", *req.Locale)
+				assert.Equal(t, "Europe/Madrid", *req.Timezone)
+			}
+			assert.Nil(t, req.BotToken)
+			return &api.Tenant{TenantID: id, Status: "idle"}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--locale", "es", "--timezone", "Europe/Madrid"})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestTenantUpdateCommand_MaxMessageAge(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.MaxMessageAgeS) {
+				assert.Equal(t, -1, *req.MaxMessageAgeS)
+			}
+			assert.Nil(t, req.Disabled)
+			return &api.Tenant{TenantID: id, Status: "idle", MaxMessageAgeS: -1}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--max-message-age=-1"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = newTenantUpdateCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--max-message-age=-5"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestTenantUpdateCommand_MaxReplyBytes(t *testing.T) {
+	mockClient := &api.MockClient{
+		UpdateTenantFunc: func(ctx stdcontext.Context, id string, req *api.UpdateTenantRequest) (*api.Tenant, error) {
+			if assert.NotNil(t, req.MaxReplyBytes) {
+				assert.Equal(t, 50000, *req.MaxReplyBytes)
+			}
+			assert.Nil(t, req.MaxMessageAgeS)
+			return &api.Tenant{TenantID: id, Status: "idle", MaxReplyBytes: 50000}, nil
+		},
+	}
+
+	cmd := newTenantUpdateCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--max-reply-bytes=50000"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = newTenantUpdateCmd(mockClient)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice", "--max-reply-bytes=-5"})
+	assert.Error(t, cmd.Execute())
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+func newTenantUsageCmd(client api.Client) *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "usage <tenant-id>",
+		Short: "Show a tenant's daily usage and active chats",
+		Long: `Show a tenant's usage per day: messages, distinct chats, wakes, pod time
+and estimated tokens, followed by the distinct chats of each month.
+
+Days default to the current month so far (UTC). Chat counts need the
+orchestrator's Redis and are estimates, accurate to about 1%.`,
+		Example: `  ztm tenant usage alice
+  ztm tenant usage alice --from 2026-09-01 --to 2026-09-30`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, d := range []string{from, to} {
+				if _, err := time.Parse("2006-01-02", d); d != "" && err != nil {
+					return fmt.Errorf("invalid date %q: must be YYYY-MM-DD", d)
+				}
+			}
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			report, err := client.GetUsage(ctx, args[0], from, to)
+			if err != nil {
+				styler := output.NewStyler(noColor)
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get usage: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(report)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			// Table format
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "DATE\tMESSAGES\tCHATS\tWAKES\tPOD TIME\tTOKENS IN\tTOKENS OUT")
+			for _, d := range report.Days {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%d\t%d\n", d.Date, d.Messages, d.Chats, d.Wakes,
+					time.Duration(d.PodSeconds)*time.Second, d.TokensIn, d.TokensOut)
+			}
+			t := report.Total
+			fmt.Fprintf(w, "TOTAL\t%d\t\t%d\t%s\t%d\t%d\n", t.Messages, t.Wakes,
+				time.Duration(t.PodSeconds)*time.Second, t.TokensIn, t.TokensOut)
+			w.Flush()
+
+			if len(report.Months) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout())
+				w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "MONTH\tACTIVE CHATS\tMESSAGES")
+				for _, m := range report.Months {
+					fmt.Fprintf(w, "%s\t%d\t%d\n", m.Month, m.Chats, m.Messages)
+				}
+				w.Flush()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "First day, YYYY-MM-DD (default: the first of this month)")
+	cmd.Flags().StringVar(&to, "to", "", "Last day, YYYY-MM-DD (default: today)")
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantUsageCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		GetUsageFunc: func(ctx stdcontext.Context, id, from, to string) (*api.UsageReport, error) {
+			assert.Equal(t, "alice", id)
+			assert.Equal(t, "2026-09-30", from)
+			assert.Empty(t, to)
+			return &api.UsageReport{
+				TenantID: "alice", From: "2026-09-30", To: "2026-10-01",
+				Days: []api.UsageDay{
+					{Date: "2026-09-30", Usage: api.Usage{Messages: 12, PodSeconds: 90}, Chats: 3},
+					{Date: "2026-10-01", Usage: api.Usage{Messages: 5, Wakes: 1}, Chats: 2},
+				},
+				Total: api.Usage{Messages: 17, Wakes: 1, PodSeconds: 90},
+				Months: []api.UsageMonth{
+					{Month: "2026-09", Chats: 41, Messages: 12},
+					{Month: "2026-10", Chats: 2, Messages: 5},
+				},
+			}, nil
+		},
+	}
+
+	cmd := newTenantUsageCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--from", "2026-09-30"})
+
+	require.NoError(t, cmd.Execute())
+	out := buf.String()
+	assert.Regexp(t, `2026-09-30\s+12\s+3\s+0\s+1m30s`, out)
+	assert.Regexp(t, `TOTAL\s+17\s+1\s+1m30s`, out)
+	assert.Regexp(t, `2026-09\s+41\s+12`, out, "monthly chats aren't the sum of daily ones")
+}
+
+func TestTenantUsageCommand_InvalidDate(t *testing.T) {
+	cmd := newTenantUsageCmd(&api.MockClient{})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"alice", "--to", "October"})
+
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, `invalid date "October"`)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/shawn/agentic-tenancy/internal/cli/output"
+	"github.com/spf13/cobra"
+)
+
+// wakePhases are progress hints shown while the single wake call is pending.
+var wakePhases = []struct {
+	after time.Duration
+	msg   string
+}{
+	{15 * time.Second, "Waiting for pod to become ready"},
+	{60 * time.Second, "Still waiting — warm pool miss, a new node may be provisioning (up to ~4m)"},
+}
+
+func newTenantWakeCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "wake <tenant-id>",
+		Short: "Start a tenant pod without a Telegram message",
+		Long: `Wake a tenant: ensure its pod is running and print the pod IP, e.g. to
+pre-warm a tenant before a demo.
+
+Returns immediately if the pod is already running. A warm start takes
+seconds; a cold start (no warm pool capacity) can take several minutes.
+With --orchestrator-url the wake is started asynchronously and polled, so
+an ingress idle timeout doesn't cut a cold start short.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTenantIDs(client),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+			spinner := output.NewSpinner(cmd.ErrOrStderr(), noColor)
+			spinner.Start(fmt.Sprintf("Waking tenant '%s'", tenantID))
+
+			for _, p := range wakePhases {
+				t := time.AfterFunc(p.after, func() { spinner.Phase(p.msg) })
+				defer t.Stop()
+			}
+
+			ctx, cancel := commandContext(wakeTimeout)
+			defer cancel()
+
+			resp, err := client.WakeTenant(ctx, tenantID)
+			elapsed := spinner.Stop()
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to wake tenant after %s: %v", output.FormatElapsed(elapsed), err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(resp)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			if resp.AlreadyRunning {
+				styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' was already running", tenantID))
+			} else {
+				styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Tenant '%s' is running (%s)", tenantID, output.FormatElapsed(elapsed)))
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Pod IP:        %s\n", resp.PodIP)
+			fmt.Fprintf(cmd.OutOrStdout(), "Idle Timeout:  %ds\n", resp.IdleTimeoutS)
+			return nil
+		},
+	}
+}
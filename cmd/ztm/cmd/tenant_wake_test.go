@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantWakeCommand(t *testing.T) {
+	mockClient := &api.MockClient{
+		WakeTenantFunc: func(ctx stdcontext.Context, id string) (*api.WakeResponse, error) {
+			assert.Equal(t, "alice", id)
+			return &api.WakeResponse{PodIP: "10.0.0.5", IdleTimeoutS: 300}, nil
+		},
+	}
+
+	cmd := newTenantWakeCmd(mockClient)
+	buf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(errBuf)
+	cmd.SetArgs([]string{"alice"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "10.0.0.5")
+	assert.Contains(t, buf.String(), "Idle Timeout:  300s")
+	assert.Contains(t, errBuf.String(), "Waking tenant 'alice'")
+}
+
+func TestTenantWakeCommand_AlreadyRunning(t *testing.T) {
+	mockClient := &api.MockClient{
+		WakeTenantFunc: func(ctx stdcontext.Context, id string) (*api.WakeResponse, error) {
+			return &api.WakeResponse{PodIP: "10.0.0.5", IdleTimeoutS: 300, AlreadyRunning: true}, nil
+		},
+	}
+
+	cmd := newTenantWakeCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"alice"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Tenant 'alice' was already running")
+}
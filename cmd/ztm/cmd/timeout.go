@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	stdcontext "context"
+	"time"
+)
+
+// Command deadlines, unless --timeout overrides them
+const (
+	defaultTimeout = 30 * time.Second
+	// wakeTimeout covers a cold start: the orchestrator holds the wake lock
+	// for up to 240s while Karpenter provisions a node and the pod becomes
+	// ready
+	wakeTimeout = 5 * time.Minute
+	// deleteTimeout covers stopping the pod, which gets the idle grace
+	// period to flush its state, and clearing the tenant's S3 prefix
+	deleteTimeout = 3 * time.Minute
+	// snapshotTimeout covers stopping the pod and copying the tenant's S3
+	// state, twice for a restore
+	snapshotTimeout = 5 * time.Minute
+	// importTimeout covers a batch of 'tenant import', each tenant of which
+	// calls Telegram twice
+	importTimeout = 5 * time.Minute
+)
+
+// commandContext returns the context a command's API calls run under: the
+// --timeout deadline if set, else the command's own default
+func commandContext(def time.Duration) (stdcontext.Context, stdcontext.CancelFunc) {
+	if timeout > 0 {
+		def = timeout
+	}
+	return stdcontext.WithTimeout(stdcontext.Background(), def)
+}
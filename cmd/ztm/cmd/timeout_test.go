@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCommandTimeout: calls get the command's own deadline, unless
+// --timeout overrides it
+func TestCommandTimeout(t *testing.T) {
+	var remaining time.Duration
+	mockClient := &api.MockClient{
+		RestartTenantFunc: func(ctx stdcontext.Context, id string) (*api.RestartResult, error) {
+			deadline, _ := ctx.Deadline()
+			remaining = time.Until(deadline)
+			return &api.RestartResult{TenantID: id, PodIP: "10.0.0.5"}, nil
+		},
+	}
+	restart := func() {
+		cmd := newTenantRestartCmd(mockClient)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"alice"})
+		assert.NoError(t, cmd.Execute())
+	}
+
+	restart()
+	assert.InDelta(t, wakeTimeout.Seconds(), remaining.Seconds(), 1)
+
+	timeout = 20 * time.Minute
+	defer func() { timeout = 0 }()
+	restart()
+	assert.InDelta(t, (20 * time.Minute).Seconds(), remaining.Seconds(), 1)
+}
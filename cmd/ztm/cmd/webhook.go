@@ -1,9 +1,8 @@
 package cmd
 
 import (
-	stdcontext "context"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
 	"github.com/shawn/agentic-tenancy/internal/cli/output"
@@ -25,7 +24,7 @@ re-register a webhook.`,
 			styler := output.NewStyler(noColor)
 			styler.PrintInfo(fmt.Sprintf("Registering webhook for tenant '%s'...", tenantID))
 
-			ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), 30*time.Second)
+			ctx, cancel := commandContext(defaultTimeout)
 			defer cancel()
 
 			resp, err := client.RegisterWebhook(ctx, tenantID)
@@ -56,14 +55,105 @@ re-register a webhook.`,
 	}
 }
 
+func newWebhookInfoCmd(client api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <tenant-id>",
+		Short: "Show a tenant's Telegram webhook",
+		Long: `Show the tenant bot's webhook as Telegram sees it: the URL it points at,
+how many updates are waiting for delivery and the last delivery error.
+
+A URL other than the router's means updates go somewhere else; re-register
+with 'ztm webhook register'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			info, err := client.GetWebhookInfo(ctx, tenantID)
+			if err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to get webhook: %v", err))
+				return err
+			}
+
+			if outputFormat == "json" {
+				jsonStr, err := output.FormatJSON(info)
+				if err != nil {
+					return fmt.Errorf("failed to format output: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), jsonStr)
+				return nil
+			}
+
+			out := cmd.OutOrStdout()
+			url := info.URL
+			if url == "" {
+				url = "(none)"
+			}
+			fmt.Fprintf(out, "URL:             %s\n", url)
+			fmt.Fprintf(out, "Pending Updates: %d\n", info.PendingUpdateCount)
+			if info.LastErrorAt != nil {
+				fmt.Fprintf(out, "Last Error:      %s (%s)\n", info.LastErrorMessage, info.LastErrorAt.Local().Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Fprintf(out, "Last Error:      -\n")
+			}
+			if len(info.AllowedUpdates) > 0 {
+				fmt.Fprintf(out, "Allowed Updates: %s\n", strings.Join(info.AllowedUpdates, ", "))
+			}
+			if info.URL != info.ExpectedURL {
+				fmt.Fprintln(out)
+				styler.FprintWarn(out, fmt.Sprintf("Webhook does not point at the router (%s); run 'ztm webhook register %s'", info.ExpectedURL, tenantID))
+			}
+			return nil
+		},
+	}
+}
+
+func newWebhookDeleteCmd(client api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <tenant-id>",
+		Short: "Delete a tenant's Telegram webhook",
+		Long: `Delete the Telegram webhook for a tenant. The bot stops receiving messages;
+Telegram keeps undelivered updates for up to 24 hours, and they arrive once
+the webhook is registered again with 'ztm webhook register'.
+
+Asks for confirmation first; --yes skips the question.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			if err := confirm(cmd, fmt.Sprintf("Delete the webhook of tenant '%s'?", tenantID)); err != nil {
+				return err
+			}
+			styler := output.NewStyler(noColor)
+
+			ctx, cancel := commandContext(defaultTimeout)
+			defer cancel()
+
+			if err := client.DeleteWebhook(ctx, tenantID); err != nil {
+				styler.FprintError(cmd.OutOrStderr(), fmt.Sprintf("Failed to delete webhook: %v", err))
+				return err
+			}
+
+			styler.FprintSuccess(cmd.OutOrStdout(), fmt.Sprintf("Webhook for tenant '%s' deleted", tenantID))
+			return nil
+		},
+	}
+	addConfirmFlag(cmd)
+	return cmd
+}
+
 func newWebhookCmd(client api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "webhook",
 		Short: "Manage Telegram webhooks",
-		Long:  `Register Telegram webhooks for tenants.`,
+		Long:  `Register, inspect and delete Telegram webhooks for tenants.`,
 	}
 
 	cmd.AddCommand(newWebhookRegisterCmd(client))
+	cmd.AddCommand(newWebhookInfoCmd(client))
+	cmd.AddCommand(newWebhookDeleteCmd(client))
 
 	return cmd
 }
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	stdcontext "context"
 	"testing"
+	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWebhookRegisterCommand(t *testing.T) {
@@ -32,3 +34,51 @@ func TestWebhookRegisterCommand(t *testing.T) {
 	output := buf.String()
 	assert.Contains(t, output, "registered")
 }
+
+func TestWebhookInfoCommand(t *testing.T) {
+	lastErr := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockClient := &api.MockClient{
+		GetWebhookInfoFunc: func(ctx stdcontext.Context, tenantID string) (*api.WebhookInfo, error) {
+			assert.Equal(t, "alice", tenantID)
+			return &api.WebhookInfo{
+				TenantID:           "alice",
+				URL:                "https://old.example.com/tg/alice",
+				ExpectedURL:        "https://router.example.com/tg/alice",
+				PendingUpdateCount: 12,
+				LastErrorAt:        &lastErr,
+				LastErrorMessage:   "Connection timed out",
+			}, nil
+		},
+	}
+
+	cmd := newWebhookInfoCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice"})
+
+	require.NoError(t, cmd.Execute())
+	out := buf.String()
+	assert.Contains(t, out, "https://old.example.com/tg/alice")
+	assert.Contains(t, out, "Pending Updates: 12")
+	assert.Contains(t, out, "Connection timed out")
+	assert.Contains(t, out, "does not point at the router")
+}
+
+func TestWebhookDeleteCommand(t *testing.T) {
+	var deleted string
+	mockClient := &api.MockClient{
+		DeleteWebhookFunc: func(ctx stdcontext.Context, tenantID string) error {
+			deleted = tenantID
+			return nil
+		},
+	}
+
+	cmd := newWebhookDeleteCmd(mockClient)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"alice", "--yes"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "alice", deleted)
+	assert.Contains(t, buf.String(), "deleted")
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/shawn/agentic-tenancy/cmd/ztm/cmd"
+	"github.com/shawn/agentic-tenancy/internal/cli/api"
 )
 
 var (
@@ -16,6 +18,11 @@ var (
 func main() {
 	cmd.SetVersion(version, commit, buildDate)
 	if err := cmd.Execute(); err != nil {
+		// An exec'd command's failure is its own to report
+		var exit *api.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.Code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
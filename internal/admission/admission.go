@@ -0,0 +1,233 @@
+// Package admission serves a Kubernetes admission webhook for tenant pods
+// (app=zeroclaw). A pod edited outside the orchestrator, e.g. with kubectl
+// edit changing its image, no longer matches what the registry says the
+// tenant runs, and nothing shows it. The webhook either rejects such edits
+// or lets them through flagged: the pod is labeled and the edit recorded in
+// the tenant's event log.
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Modes
+const (
+	// ModeReject denies edits made outside the orchestrator. Register the
+	// webhook in a ValidatingWebhookConfiguration.
+	ModeReject = "reject"
+	// ModeFlag admits them, labels the pod and records the edit. Register
+	// the webhook in a MutatingWebhookConfiguration, which may patch pods.
+	ModeFlag = "flag"
+)
+
+const (
+	// ModifiedLabel marks a tenant pod edited outside the orchestrator,
+	// until the pod is replaced
+	ModifiedLabel = "zeroclaw.io/modified"
+	// ModifiedByAnnotation says who made the latest such edit, and what it
+	// changed
+	ModifiedByAnnotation = "zeroclaw.io/modified-by"
+)
+
+// Path is where the webhook is served
+const Path = "/admission/pods"
+
+// watchedLabels are the tenant pod labels the platform relies on
+var watchedLabels = []string{"app", "tenant", ModifiedLabel}
+
+// Recorder appends to a tenant's event log; registry.Client implements it.
+type Recorder interface {
+	RecordEvent(ctx context.Context, tenantID string, ev registry.TenantEvent, keep int) error
+}
+
+// Config configures the webhook.
+type Config struct {
+	Mode string // ModeReject or ModeFlag
+	// AllowedUsers make the platform's own changes, e.g. the orchestrator's
+	// ServiceAccount (system:serviceaccount:tenants:orchestrator)
+	AllowedUsers []string
+	// Registries maps each environment's tenant namespace to its registry,
+	// where flagged edits are recorded. A namespace missing here is only
+	// labeled.
+	Registries   map[string]Recorder
+	EventHistory int // events kept per tenant
+}
+
+// Handler reviews tenant pod creates and updates.
+type Handler struct {
+	cfg     Config
+	allowed map[string]bool
+}
+
+// New creates a webhook handler
+func New(cfg Config) *Handler {
+	allowed := make(map[string]bool, len(cfg.AllowedUsers))
+	for _, u := range cfg.AllowedUsers {
+		allowed[u] = true
+	}
+	return &Handler{cfg: cfg, allowed: allowed}
+}
+
+// ServeHTTP answers an AdmissionReview
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 3<<20)).Decode(&review); err != nil || review.Request == nil {
+		http.Error(w, "invalid AdmissionReview", http.StatusBadRequest)
+		return
+	}
+	resp := h.Review(r.Context(), review.Request)
+	resp.UID = review.Request.UID
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+		TypeMeta: review.TypeMeta,
+		Response: resp,
+	})
+}
+
+// Review decides on one request. Requests by an allowed user, and edits
+// that leave what the platform relies on alone, are admitted as they are.
+func (h *Handler) Review(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allow := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Kind.Kind != "Pod" || req.SubResource != "" || h.allowed[req.UserInfo.Username] {
+		return allow
+	}
+	var pod, old corev1.Pod
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		return deny(http.StatusBadRequest, fmt.Sprintf("decode pod: %v", err))
+	}
+	var changed []string
+	switch req.Operation {
+	case admissionv1.Create:
+		changed = []string{"created outside the orchestrator"}
+	case admissionv1.Update:
+		if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+			return deny(http.StatusBadRequest, fmt.Sprintf("decode old pod: %v", err))
+		}
+		changed = changes(&old, &pod)
+	}
+	if len(changed) == 0 {
+		return allow
+	}
+
+	tenantID := pod.Labels["tenant"]
+	if tenantID == "" {
+		tenantID = old.Labels["tenant"]
+	}
+	what := strings.Join(changed, "; ")
+	user := req.UserInfo.Username
+	if h.cfg.Mode == ModeReject {
+		slog.Warn("admission: rejected tenant pod edit", "tenant", tenantID, "pod", req.Name, "user", user, "changes", what)
+		return deny(http.StatusForbidden, fmt.Sprintf(
+			"tenant pods are managed by the orchestrator; %s may not change %s (use ztm or the orchestrator API)", user, what))
+	}
+
+	slog.Warn("admission: flagged tenant pod edit", "tenant", tenantID, "pod", req.Name, "user", user, "changes", what)
+	if rec := h.cfg.Registries[req.Namespace]; rec != nil && tenantID != "" && !isDryRun(req) {
+		ev := registry.TenantEvent{
+			Time:    time.Now().UTC(),
+			Kind:    registry.EventPodModified,
+			Pod:     req.Name,
+			Message: fmt.Sprintf("%s changed %s", user, what),
+		}
+		if err := rec.RecordEvent(ctx, tenantID, ev, h.cfg.EventHistory); err != nil {
+			slog.Error("admission: record event failed", "tenant", tenantID, "err", err)
+		}
+	}
+	patch, err := json.Marshal(flagPatch(&pod, user+": "+what))
+	if err != nil {
+		return deny(http.StatusInternalServerError, err.Error())
+	}
+	pt := admissionv1.PatchTypeJSONPatch
+	allow.Patch, allow.PatchType = patch, &pt
+	return allow
+}
+
+func deny(code int32, msg string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Result: &metav1.Status{Code: code, Message: msg}}
+}
+
+func isDryRun(req *admissionv1.AdmissionRequest) bool {
+	return req.DryRun != nil && *req.DryRun
+}
+
+// changes lists what an update changes of what the platform relies on: the
+// containers' images, commands, env and resources, and the watched labels
+func changes(old, pod *corev1.Pod) []string {
+	var out []string
+	out = append(out, containerChanges(old.Spec.InitContainers, pod.Spec.InitContainers)...)
+	out = append(out, containerChanges(old.Spec.Containers, pod.Spec.Containers)...)
+	for _, l := range watchedLabels {
+		before, after := old.Labels[l], pod.Labels[l]
+		if before != after {
+			out = append(out, fmt.Sprintf("label %s %q → %q", l, before, after))
+		}
+	}
+	return out
+}
+
+func containerChanges(old, cur []corev1.Container) []string {
+	byName := make(map[string]corev1.Container, len(old))
+	for _, c := range old {
+		byName[c.Name] = c
+	}
+	var out []string
+	for _, c := range cur {
+		o, ok := byName[c.Name]
+		if !ok {
+			out = append(out, "added container "+c.Name)
+			continue
+		}
+		if o.Image != c.Image {
+			out = append(out, fmt.Sprintf("image of %s %s → %s", c.Name, o.Image, c.Image))
+		}
+		if !reflect.DeepEqual(o.Command, c.Command) || !reflect.DeepEqual(o.Args, c.Args) {
+			out = append(out, "command of "+c.Name)
+		}
+		if !reflect.DeepEqual(o.Env, c.Env) || !reflect.DeepEqual(o.EnvFrom, c.EnvFrom) {
+			out = append(out, "env of "+c.Name)
+		}
+		if !reflect.DeepEqual(o.Resources, c.Resources) {
+			out = append(out, "resources of "+c.Name)
+		}
+	}
+	return out
+}
+
+type patchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// flagPatch labels pod as modified and annotates it with by
+func flagPatch(pod *corev1.Pod, by string) []patchOp {
+	var ops []patchOp
+	if pod.Labels == nil {
+		ops = append(ops, patchOp{"add", "/metadata/labels", map[string]string{ModifiedLabel: "true"}})
+	} else {
+		ops = append(ops, patchOp{"add", "/metadata/labels/" + escape(ModifiedLabel), "true"})
+	}
+	if pod.Annotations == nil {
+		ops = append(ops, patchOp{"add", "/metadata/annotations", map[string]string{ModifiedByAnnotation: by}})
+	} else {
+		ops = append(ops, patchOp{"add", "/metadata/annotations/" + escape(ModifiedByAnnotation), by})
+	}
+	return ops
+}
+
+// escape makes a map key a JSON Pointer token (RFC 6901)
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
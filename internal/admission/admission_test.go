@@ -0,0 +1,136 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const orchestrator = "system:serviceaccount:tenants:orchestrator"
+
+func tenantPod(image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "zeroclaw-alice",
+			Namespace:   "tenants",
+			Labels:      map[string]string{"app": "zeroclaw", "tenant": "alice"},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "zeroclaw", Image: image}}},
+	}
+}
+
+func request(t *testing.T, op admissionv1.Operation, user string, old, pod *corev1.Pod) *admissionv1.AdmissionRequest {
+	t.Helper()
+	raw := func(p *corev1.Pod) runtime.RawExtension {
+		if p == nil {
+			return runtime.RawExtension{}
+		}
+		b, err := json.Marshal(p)
+		require.NoError(t, err)
+		return runtime.RawExtension{Raw: b}
+	}
+	return &admissionv1.AdmissionRequest{
+		UID:       "uid-1",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Operation: op,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+		Object:    raw(pod),
+		OldObject: raw(old),
+	}
+}
+
+func TestReview_Reject(t *testing.T) {
+	h := New(Config{Mode: ModeReject, AllowedUsers: []string{orchestrator}})
+	ctx := context.Background()
+	old, edited := tenantPod("zeroclaw:1.0"), tenantPod("zeroclaw:1.1")
+
+	resp := h.Review(ctx, request(t, admissionv1.Update, "alice@example.com", old, edited))
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "image of zeroclaw zeroclaw:1.0 → zeroclaw:1.1")
+
+	assert.True(t, h.Review(ctx, request(t, admissionv1.Update, orchestrator, old, edited)).Allowed,
+		"the orchestrator's own edits")
+	assert.False(t, h.Review(ctx, request(t, admissionv1.Create, "alice@example.com", nil, edited)).Allowed)
+
+	annotated := tenantPod("zeroclaw:1.0")
+	annotated.Annotations["note"] = "checked"
+	resp = h.Review(ctx, request(t, admissionv1.Update, "alice@example.com", old, annotated))
+	assert.True(t, resp.Allowed, "edits the platform doesn't rely on")
+	assert.Nil(t, resp.Patch)
+}
+
+func TestReview_Flag(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice"}))
+	h := New(Config{Mode: ModeFlag, AllowedUsers: []string{orchestrator},
+		Registries: map[string]Recorder{"tenants": reg}, EventHistory: 10})
+
+	old, edited := tenantPod("zeroclaw:1.0"), tenantPod("zeroclaw:1.0")
+	edited.Labels["tenant"] = "bob"
+	edited.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "DEBUG", Value: "1"}}
+	resp := h.Review(ctx, request(t, admissionv1.Update, "alice@example.com", old, edited))
+	require.True(t, resp.Allowed)
+	require.NotNil(t, resp.PatchType)
+	assert.Equal(t, admissionv1.PatchTypeJSONPatch, *resp.PatchType)
+	var patch []patchOp
+	require.NoError(t, json.Unmarshal(resp.Patch, &patch))
+	assert.Equal(t, []patchOp{
+		{"add", "/metadata/labels/zeroclaw.io~1modified", "true"},
+		{"add", "/metadata/annotations", map[string]any{ // the pod has none
+			ModifiedByAnnotation: `alice@example.com: env of zeroclaw; label tenant "alice" → "bob"`,
+		}},
+	}, patch)
+
+	events, err := reg.ListEvents(ctx, "bob")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, registry.EventPodModified, events[0].Kind)
+	assert.Equal(t, "zeroclaw-alice", events[0].Pod)
+
+	dryRun := true
+	req := request(t, admissionv1.Update, "alice@example.com", old, edited)
+	req.DryRun = &dryRun
+	assert.NotNil(t, h.Review(ctx, req).Patch)
+	events, _ = reg.ListEvents(ctx, "bob")
+	assert.Len(t, events, 1, "dry runs aren't recorded")
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := New(Config{Mode: ModeReject})
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  request(t, admissionv1.Create, "alice@example.com", nil, tenantPod("zeroclaw:1.0")),
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got admissionv1.AdmissionReview
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, "AdmissionReview", got.Kind)
+	require.NotNil(t, got.Response)
+	assert.EqualValues(t, "uid-1", got.Response.UID)
+	assert.False(t, got.Response.Allowed)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
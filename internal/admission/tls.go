@@ -0,0 +1,57 @@
+package admission
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSConfig serves the certificate in certFile and keyFile, reading them
+// again once they change, so a certificate renewed into a mounted Secret
+// (e.g. by cert-manager) is picked up without a restart.
+func TLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	l := &certLoader{certFile: certFile, keyFile: keyFile}
+	if _, err := l.get(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return l.get() },
+	}, nil
+}
+
+type certLoader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// get returns the certificate, reloading it if the certificate file is
+// newer than the loaded one. A failed reload keeps serving the old one.
+func (l *certLoader) get() (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	info, err := os.Stat(l.certFile)
+	if err != nil {
+		if l.cert != nil {
+			return l.cert, nil
+		}
+		return nil, fmt.Errorf("admission certificate: %w", err)
+	}
+	if l.cert != nil && !info.ModTime().After(l.modTime) {
+		return l.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		if l.cert != nil {
+			return l.cert, nil
+		}
+		return nil, fmt.Errorf("admission certificate: %w", err)
+	}
+	l.cert, l.modTime = &cert, info.ModTime()
+	return l.cert, nil
+}
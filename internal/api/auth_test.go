@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRequireAPIKey(t *testing.T) {
+	keys, err := apikey.ParseStatic("ops=s3cret")
+	require.NoError(t, err)
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(registry.NewMock(), k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		APIKeys:      keys,
+	})
+
+	do := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, do("/healthz", nil).Code, "health checks stay open")
+
+	rec := do("/tenants", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+	assert.Equal(t, http.StatusUnauthorized, do("/tenants", map[string]string{"Authorization": "Bearer wrong"}).Code)
+	assert.Equal(t, http.StatusOK, do("/tenants", map[string]string{"Authorization": "Bearer s3cret"}).Code)
+	assert.Equal(t, http.StatusOK, do("/tenants", map[string]string{api.APIKeyHeader: "s3cret"}).Code)
+}
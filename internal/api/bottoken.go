@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+)
+
+// errTenantNotFound is returned by setBotToken for an unknown tenant
+var errTenantNotFound = errors.New("tenant not found")
+
+// botToken resolves the tenant's bot token, from the secret store if the
+// record references one
+func (h *Handler) botToken(ctx context.Context, rec *registry.TenantRecord) (string, error) {
+	return secrets.BotToken(ctx, h.cfg.Secrets, rec)
+}
+
+// botTokenSecretName names the secret holding a tenant's bot token
+func (h *Handler) botTokenSecretName(tenantID string) string {
+	return h.cfg.Environment.SecretName(h.cfg.BotTokenSecretPrefix, tenantID)
+}
+
+// setBotToken replaces a tenant's bot token. With a secret store the token is
+// written there and the record keeps only the reference; an empty token
+// removes both. Without one the token is stored in the record as before.
+func (h *Handler) setBotToken(ctx context.Context, tenantID, token string) error {
+	if h.cfg.Secrets == nil {
+		return h.reg.UpdateBotToken(ctx, tenantID, token)
+	}
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		return errTenantNotFound
+	}
+	name := h.botTokenSecretName(tenantID)
+	if token == "" {
+		if err := h.reg.UpdateBotTokenRef(ctx, tenantID, ""); err != nil {
+			return err
+		}
+		return h.cfg.Secrets.Delete(ctx, name)
+	}
+	if err := h.cfg.Secrets.Put(ctx, name, token); err != nil {
+		return err
+	}
+	return h.reg.UpdateBotTokenRef(ctx, tenantID, name)
+}
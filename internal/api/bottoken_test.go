@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotToken_SecretStoreLifecycle(t *testing.T) {
+	bot := &fakeBotAPI{}
+	store := secrets.NewMemory()
+	h, reg := newWebhookTestHandler(t, bot, api.Config{Secrets: store})
+	ctx := context.Background()
+	const ref = "agentic-tenancy/bot-token/alice"
+
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok-a").Code)
+	rec, _ := reg.GetTenant(ctx, "alice")
+	assert.Empty(t, rec.BotToken, "no plaintext token in the registry")
+	assert.Equal(t, ref, rec.BotTokenRef)
+	token, err := store.Get(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, "tok-a", token)
+	assert.Equal(t, []string{"tok-a"}, bot.registered)
+
+	// The router still gets the token itself
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/bot_token", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "tok-a", got["BotToken"])
+
+	// Rotating the token replaces the secret's value and re-registers
+	body, _ := json.Marshal(map[string]string{"bot_token": "tok-b"})
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	token, _ = store.Get(ctx, ref)
+	assert.Equal(t, "tok-b", token)
+	assert.Equal(t, []string{"tok-a", "tok-b"}, bot.registered)
+
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tenants/alice", nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	_, err = store.Get(ctx, ref)
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+}
+
+func TestCreateTenant_FailedWebhookRemovesSecret(t *testing.T) {
+	bot := &fakeBotAPI{reject: map[string]string{"tok-x": `{"ok":false,"error_code":401,"description":"Unauthorized"}`}}
+	store := secrets.NewMemory()
+	h, reg := newWebhookTestHandler(t, bot, api.Config{Secrets: store})
+	ctx := context.Background()
+
+	require.Equal(t, http.StatusBadGateway, createWithBot(t, h, "alice", "tok-x").Code)
+	rec, _ := reg.GetTenant(ctx, "alice")
+	assert.Nil(t, rec)
+	_, err := store.Get(ctx, "agentic-tenancy/bot-token/alice")
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"golang.org/x/time/rate"
+)
+
+const (
+	broadcastKey = "broadcast:current"
+	// broadcastTTL is how long a finished broadcast's report can still be read
+	broadcastTTL = 7 * 24 * time.Hour
+	// broadcastStaleAfter is how long a running broadcast may go without
+	// progress before another may start: a few slow deliveries
+	broadcastStaleAfter = 2 * time.Minute
+	// broadcastMaxChars keeps an announcement within one Telegram message
+	broadcastMaxChars = 4000
+	// notificationAnnouncement is the Kind of broadcast notifications
+	notificationAnnouncement = "announcement"
+)
+
+// BroadcastStatus is the state of a broadcast
+type BroadcastStatus string
+
+const (
+	BroadcastRunning BroadcastStatus = "running"
+	BroadcastDone    BroadcastStatus = "done"   // every tenant was tried
+	BroadcastFailed  BroadcastStatus = "failed" // the tenants couldn't be listed
+)
+
+// Broadcast is an announcement to every tenant's owner and its delivery
+// report, as returned by POST /admin/broadcast and GET /admin/broadcast
+type Broadcast struct {
+	ID     string          `json:"broadcast_id"`
+	Status BroadcastStatus `json:"status"`
+	Text   string          `json:"text"`
+	// Total is the number of tenants in the environment
+	Total    int      `json:"total"`
+	Sent     []string `json:"sent"`
+	OptedOut []string `json:"opted_out,omitempty"` // no_announcements is set
+	// NoChannel are tenants without a log forwarding target to reach the
+	// owner through
+	NoChannel []string           `json:"no_channel,omitempty"`
+	Failed    []BroadcastFailure `json:"failed,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	StartedAt time.Time          `json:"started_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// BroadcastFailure is a tenant the announcement couldn't be delivered to
+type BroadcastFailure struct {
+	TenantID string `json:"tenant_id"`
+	Error    string `json:"error"`
+}
+
+// broadcastMemory holds the broadcast when there is no Redis (local mode)
+type broadcastMemory struct {
+	mu      sync.Mutex
+	current *Broadcast
+}
+
+// StartBroadcast sends an announcement (a maintenance window, a new
+// feature) to every tenant's owner: POST /admin/broadcast with
+// {"text": "..."}. Owners are reached like log forwarding notifications,
+// through their log_forward chat and/or webhook; tenants without one, and
+// those whose owner set no_announcements, are listed in the report instead.
+// Deliveries are paced at Config.BroadcastRate in the background; the answer
+// is 202 with the broadcast to poll at GET /admin/broadcast. Only one
+// broadcast runs at a time.
+func (h *Handler) StartBroadcast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.cfg.Notifier == nil {
+		http.Error(w, "notifications not configured", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		http.Error(w, `body must be {"text": "..."}`, http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(req.Text) > broadcastMaxChars {
+		http.Error(w, "text must be at most 4000 characters", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.loadBroadcast(ctx)
+	if err != nil {
+		slog.Error("load broadcast", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if current != nil && current.Status == BroadcastRunning && time.Since(current.UpdatedAt) < broadcastStaleAfter {
+		http.Error(w, "broadcast "+current.ID+" already running", http.StatusConflict)
+		return
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	now := time.Now().UTC()
+	bc := Broadcast{ID: hex.EncodeToString(b), Status: BroadcastRunning, Text: req.Text,
+		Sent: []string{}, StartedAt: now, UpdatedAt: now}
+	if err := h.saveBroadcast(ctx, bc); err != nil {
+		slog.Error("save broadcast", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	go h.runBroadcast(bc)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(bc)
+}
+
+// GetBroadcast reports the current or last broadcast's deliveries
+func (h *Handler) GetBroadcast(w http.ResponseWriter, r *http.Request) {
+	bc, err := h.loadBroadcast(r.Context())
+	if err != nil {
+		slog.Error("load broadcast", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if bc == nil {
+		http.Error(w, "no broadcast", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bc)
+}
+
+// runBroadcast notifies each tenant's owner in turn, recording progress
+// after each one
+func (h *Handler) runBroadcast(bc Broadcast) {
+	ctx := context.Background()
+	tenants, err := h.reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("broadcast: list tenants failed", "broadcast", bc.ID, "err", err)
+		bc.Status, bc.Error = BroadcastFailed, err.Error()
+		bc.UpdatedAt = time.Now().UTC()
+		h.saveBroadcastLogged(ctx, bc)
+		return
+	}
+	// Deleted tenants' owners aren't told about the platform anymore
+	tenants = slices.DeleteFunc(tenants, func(rec *registry.TenantRecord) bool { return rec.Status == registry.StatusTerminated })
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].TenantID < tenants[j].TenantID })
+	bc.Total = len(tenants)
+	bc.UpdatedAt = time.Now().UTC()
+	h.saveBroadcastLogged(ctx, bc)
+	slog.Info("broadcast: starting", "broadcast", bc.ID, "tenants", len(tenants))
+
+	limit := h.cfg.BroadcastRate
+	if limit <= 0 {
+		limit = 1
+	}
+	limiter := rate.NewLimiter(rate.Limit(limit), 1)
+	for _, rec := range tenants {
+		switch {
+		case rec.NoAnnouncements:
+			bc.OptedOut = append(bc.OptedOut, rec.TenantID)
+		case !ownerReachable(rec):
+			bc.NoChannel = append(bc.NoChannel, rec.TenantID)
+		default:
+			limiter.Wait(ctx)
+			n := notify.Notification{TenantID: rec.TenantID, Kind: notificationAnnouncement, Title: bc.Text,
+				Time: time.Now().UTC(), Locale: rec.Locale}
+			if err := h.cfg.Notifier.Notify(ctx, rec, n); err != nil {
+				slog.Warn("broadcast: delivery failed", "broadcast", bc.ID, "tenant", rec.TenantID, "err", err)
+				bc.Failed = append(bc.Failed, BroadcastFailure{TenantID: rec.TenantID, Error: err.Error()})
+			} else {
+				bc.Sent = append(bc.Sent, rec.TenantID)
+			}
+		}
+		bc.UpdatedAt = time.Now().UTC()
+		h.saveBroadcastLogged(ctx, bc)
+	}
+
+	bc.Status = BroadcastDone
+	bc.UpdatedAt = time.Now().UTC()
+	h.saveBroadcastLogged(ctx, bc)
+	slog.Info("broadcast: finished", "broadcast", bc.ID, "sent", len(bc.Sent),
+		"opted_out", len(bc.OptedOut), "no_channel", len(bc.NoChannel), "failed", len(bc.Failed))
+}
+
+// ownerReachable reports whether rec has a notification target: a log
+// forwarding chat its bot can message, or a webhook
+func ownerReachable(rec *registry.TenantRecord) bool {
+	f := rec.LogForward
+	return f != nil && ((f.TelegramChatID != 0 && rec.HasBot()) || f.WebhookURL != "")
+}
+
+func (h *Handler) saveBroadcastLogged(ctx context.Context, bc Broadcast) {
+	if err := h.saveBroadcast(ctx, bc); err != nil {
+		slog.Warn("save broadcast", "broadcast", bc.ID, "err", err)
+	}
+}
+
+// saveBroadcast stores the broadcast in Redis, so any replica can answer polls
+func (h *Handler) saveBroadcast(ctx context.Context, bc Broadcast) error {
+	if h.rdb == nil {
+		h.broadcasts.mu.Lock()
+		defer h.broadcasts.mu.Unlock()
+		h.broadcasts.current = &bc
+		return nil
+	}
+	data, err := json.Marshal(bc)
+	if err != nil {
+		return err
+	}
+	return h.rdb.Set(ctx, h.redisKey(broadcastKey, ""), data, broadcastTTL).Err()
+}
+
+// loadBroadcast returns nil if no broadcast ran within broadcastTTL
+func (h *Handler) loadBroadcast(ctx context.Context) (*Broadcast, error) {
+	if h.rdb == nil {
+		h.broadcasts.mu.Lock()
+		defer h.broadcasts.mu.Unlock()
+		if h.broadcasts.current == nil {
+			return nil, nil
+		}
+		bc := *h.broadcasts.current
+		return &bc, nil
+	}
+	data, err := h.rdb.Get(ctx, h.redisKey(broadcastKey, "")).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bc Broadcast
+	if err := json.Unmarshal(data, &bc); err != nil {
+		return nil, err
+	}
+	return &bc, nil
+}
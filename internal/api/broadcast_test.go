@@ -0,0 +1,107 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifier records notifications, failing those for tenants in fail
+type fakeNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+	fail map[string]bool
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, rec *registry.TenantRecord, n notify.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail[rec.TenantID] {
+		return errors.New("telegram: chat not found")
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestBroadcast_DeliveryReport(t *testing.T) {
+	reg := registry.NewMock()
+	notifier := &fakeNotifier{fail: map[string]bool{"dave": true}}
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{Notifier: notifier, BroadcastRate: 1000})
+	ctx := context.Background()
+	chat := &registry.LogForwardConfig{TelegramChatID: 42}
+	for _, rec := range []*registry.TenantRecord{
+		{TenantID: "alice", BotToken: "tok-a", LogForward: chat, Locale: "es"},
+		{TenantID: "bob", BotToken: "tok-b", LogForward: chat, NoAnnouncements: true},
+		{TenantID: "carol", BotToken: "tok-c"},
+		{TenantID: "dave", LogForward: &registry.LogForwardConfig{WebhookURL: "https://hooks.example.com/dave"}},
+		{TenantID: "erin", LogForward: chat}, // a chat, but no bot to reach it through
+	} {
+		require.NoError(t, reg.CreateTenant(ctx, rec))
+	}
+
+	body, _ := json.Marshal(map[string]string{"text": "Maintenance Sunday 02:00 UTC"})
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/broadcast", bytes.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var bc api.Broadcast
+	require.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/broadcast", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bc))
+		return bc.Status == api.BroadcastDone
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 5, bc.Total)
+	assert.Equal(t, []string{"alice"}, bc.Sent)
+	assert.Equal(t, []string{"bob"}, bc.OptedOut)
+	assert.Equal(t, []string{"carol", "erin"}, bc.NoChannel)
+	require.Len(t, bc.Failed, 1)
+	assert.Equal(t, "dave", bc.Failed[0].TenantID)
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "announcement", notifier.sent[0].Kind)
+	assert.Equal(t, "Maintenance Sunday 02:00 UTC", notifier.sent[0].Title)
+	assert.Equal(t, "es", notifier.sent[0].Locale)
+}
+
+func TestBroadcast_Refused(t *testing.T) {
+	h := api.New(registry.NewMock(), nil, lock.NewMock(), nil, nil, api.Config{})
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/broadcast", bytes.NewReader([]byte(`{"text":"hi"}`))))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "no notifier")
+
+	h = api.New(registry.NewMock(), nil, lock.NewMock(), nil, nil, api.Config{Notifier: &fakeNotifier{}})
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/broadcast", bytes.NewReader([]byte(`{"text":" "}`))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/broadcast", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdateTenant_NoAnnouncements(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice"}))
+
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(`{"no_announcements":true}`))))
+	require.Equal(t, http.StatusOK, w.Code)
+	rec, _ := reg.GetTenant(ctx, "alice")
+	assert.True(t, rec.NoAnnouncements)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// bulkMaxTenants is the most tenants POST /tenants/bulk creates at once
+const bulkMaxTenants = 100
+
+// bulkResult is one tenant's outcome in POST /tenants/bulk: the status
+// POST /tenants would have answered, with the record or the error
+type bulkResult struct {
+	TenantID string                 `json:"tenant_id"`
+	Status   int                    `json:"status"`
+	Error    string                 `json:"error,omitempty"`
+	Tenant   *registry.TenantRecord `json:"tenant,omitempty"`
+}
+
+// CreateTenants creates several tenants, each as POST /tenants would:
+// POST /tenants/bulk with a JSON array of tenant definitions. A tenant that
+// fails doesn't stop the rest; the response lists every tenant's outcome,
+// in order, with counts: {"created", "failed", "results"}. It is 200 unless
+// the batch itself is malformed.
+func (h *Handler) CreateTenants(w http.ResponseWriter, r *http.Request) {
+	var reqs []createTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		http.Error(w, "body must be a JSON array of tenants", http.StatusBadRequest)
+		return
+	}
+	if len(reqs) == 0 || len(reqs) > bulkMaxTenants {
+		http.Error(w, fmt.Sprintf("between 1 and %d tenants per request", bulkMaxTenants), http.StatusBadRequest)
+		return
+	}
+	results := make([]bulkResult, len(reqs))
+	created := 0
+	for i, req := range reqs {
+		results[i].TenantID = req.TenantID
+		if r.Context().Err() != nil {
+			results[i].Status, results[i].Error = http.StatusServiceUnavailable, "request canceled before this tenant"
+			continue
+		}
+		rec, err := h.createTenant(r.Context(), req)
+		if err != nil {
+			var ce *createError
+			errors.As(err, &ce)
+			results[i].Status, results[i].Error = ce.status, ce.msg
+			continue
+		}
+		results[i].Status, results[i].Tenant = http.StatusCreated, rec
+		created++
+	}
+	slog.Info("bulk create", "tenants", len(reqs), "created", created, "failed", len(reqs)-created)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"created": created, "failed": len(reqs) - created, "results": results})
+}
@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateTenants: each tenant is created on its own, and one that fails
+// is reported without stopping the rest
+func TestCreateTenants(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/bulk", bytes.NewReader([]byte(body))))
+		return rec
+	}
+
+	rec := post(`[
+		{"tenant_id": "alice", "tier": "premium"},
+		{"tenant_id": "bob", "locale": "xx"},
+		{"tenant_id": "alice"},
+		{"tenant_id": "carol", "bot_token": "123:abc"}
+	]`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp struct {
+		Created int `json:"created"`
+		Failed  int `json:"failed"`
+		Results []struct {
+			TenantID string          `json:"tenant_id"`
+			Status   int             `json:"status"`
+			Error    string          `json:"error"`
+			Tenant   json.RawMessage `json:"tenant"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.Created)
+	assert.Equal(t, 2, resp.Failed)
+	require.Len(t, resp.Results, 4)
+	assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+	assert.NotEmpty(t, resp.Results[1].Error)
+	assert.Equal(t, http.StatusConflict, resp.Results[2].Status, "a duplicate in the batch fails like a second POST /tenants")
+	assert.Equal(t, http.StatusCreated, resp.Results[3].Status)
+	assert.NotContains(t, string(resp.Results[3].Tenant), "123:abc", "bot tokens are redacted")
+
+	alice, _ := reg.GetTenant(context.Background(), "alice")
+	require.NotNil(t, alice)
+	assert.Equal(t, "premium", alice.Tier)
+	bob, _ := reg.GetTenant(context.Background(), "bob")
+	assert.Nil(t, bob)
+
+	assert.Equal(t, http.StatusBadRequest, post(`[]`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"tenant_id": "dave"}`).Code)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+const (
+	// chatsKeyPrefix namespaces the distinct chat counters,
+	// "chats:<tenant>:<YYYY-MM-DD or YYYY-MM>"
+	chatsKeyPrefix = "chats:"
+	// A counter only grows during its period; the registry keeps the count
+	chatsDayTTL   = 48 * time.Hour
+	chatsMonthTTL = 32 * 24 * time.Hour
+)
+
+// countChat adds chatID to the distinct chats that messaged the tenant's
+// agent on the UTC day and month of at. The counters are Redis
+// HyperLogLogs: at most 12 KB each, about 1% off, and they keep no chat
+// IDs. A count that changed is stored on the tenant's usage items, which
+// outlive the counters. Without Redis (local mode) chats aren't counted.
+func (h *Handler) countChat(ctx context.Context, tenantID, chatID string, at time.Time) {
+	if h.rdb == nil {
+		return
+	}
+	at = at.UTC()
+	for _, c := range []struct {
+		period string
+		ttl    time.Duration
+	}{
+		{at.Format(registry.UsageDateFormat), chatsDayTTL},
+		{at.Format(registry.UsageMonthFormat), chatsMonthTTL},
+	} {
+		key := h.redisKey(chatsKeyPrefix, tenantID+":"+c.period)
+		var added, count *redis.IntCmd
+		_, err := h.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			added = pipe.PFAdd(ctx, key, chatID)
+			count = pipe.PFCount(ctx, key)
+			pipe.Expire(ctx, key, c.ttl)
+			return nil
+		})
+		if err != nil {
+			slog.Warn("count chat failed", "tenant", tenantID, "err", err)
+			return
+		}
+		if added.Val() == 0 {
+			continue
+		}
+		if err := h.reg.SetChats(ctx, tenantID, c.period, count.Val()); err != nil {
+			slog.Warn("record distinct chats failed", "tenant", tenantID, "period", c.period, "err", err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	confirmTokenPrefix = "confirm:"
+	confirmTokenTTL    = 5 * time.Minute
+	// ConfirmHeader names the resource a destructive call is meant for
+	ConfirmHeader = "X-Confirm"
+)
+
+// issueConfirmToken returns a one-time token that confirms action on target
+// for confirmTokenTTL. Dry runs of destructive endpoints hand it out.
+func (h *Handler) issueConfirmToken(ctx context.Context, action, target string) (string, error) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+	if err := h.rdb.Set(ctx, h.redisKey(confirmTokenPrefix, action+":"+target), token, confirmTokenTTL).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// confirmed reports whether a destructive action on target may proceed. A
+// request confirms it with "X-Confirm: <target>" or with ?confirm_token= from
+// a dry run (consumed on use). Unconfirmed requests pass unless
+// RequireConfirm is set, but a confirmation that names a different target
+// or a stale token always fails.
+func (h *Handler) confirmed(r *http.Request, action, target string) bool {
+	if v := r.Header.Get(ConfirmHeader); v != "" {
+		return v == target
+	}
+	if token := r.URL.Query().Get("confirm_token"); token != "" {
+		if h.rdb == nil {
+			return false
+		}
+		want, err := h.rdb.GetDel(r.Context(), h.redisKey(confirmTokenPrefix, action+":"+target)).Result()
+		return err == nil && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+	}
+	return !h.cfg.RequireConfirm
+}
@@ -0,0 +1,64 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnvironment_ScopesStorage(t *testing.T) {
+	env := environment.Environment{Name: "staging", Namespace: "tenants-staging", RedisPrefix: "staging:"}
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{
+		ZeroClawImage: "zeroclaw:test",
+		S3Bucket:      "test-bucket",
+		Environment:   env.Name,
+	})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    env.Namespace,
+		PodReadyWait: 5 * time.Second,
+		Environment:  env,
+	})
+	ctx := context.Background()
+
+	body, _ := json.Marshal(map[string]interface{}{"tenant_id": "alice"})
+	req := httptest.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	tenant, err := reg.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "staging/tenants/alice/", tenant.S3Prefix)
+	assert.Equal(t, "tenants-staging", tenant.Namespace)
+
+	simulatePodReady(cs, "alice", "tenants-staging", "10.0.0.1")
+	req = httptest.NewRequest(http.MethodPost, "/wake/alice", nil)
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// PVs are cluster-scoped, so the name must not collide with another
+	// environment's tenant "alice"
+	pv, err := cs.CoreV1().PersistentVolumes().Get(ctx, "pv-tenant-staging-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "staging/tenants/alice", pv.Spec.CSI.VolumeAttributes["subPath"])
+	assert.Equal(t, "tenant-staging-alice", pv.Spec.CSI.VolumeHandle)
+	_, err = cs.CoreV1().PersistentVolumeClaims("tenants-staging").Get(ctx, "pvc-tenant-alice", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// recordingSink keeps the events it is sent
+type recordingSink struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (s *recordingSink) Publish(_ context.Context, ev events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *recordingSink) String() string { return "recording" }
+
+func (s *recordingSink) types() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var types []string
+	for _, ev := range s.events {
+		types = append(types, ev.Type+" "+ev.Env+"/"+ev.TenantID)
+	}
+	return types
+}
+
+// TestLifecycleEvents: creating, waking, sleeping and deleting a tenant
+// each publish an event
+func TestLifecycleEvents(t *testing.T) {
+	sink := &recordingSink{}
+	bus := events.New(sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(registry.NewMock(), k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		Environment:  environment.Environment{Name: "staging"},
+		Events:       bus,
+	})
+	do := func(method, path, body string) int {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/tenants", `{"tenant_id":"alice"}`))
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/wake/alice", ""))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/tenants/alice/sleep", ""))
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice", ""))
+
+	want := []string{
+		events.TenantCreated + " staging/alice",
+		events.TenantWoken + " staging/alice",
+		events.TenantIdle + " staging/alice",
+		events.TenantDeleted + " staging/alice",
+		events.TenantPurged + " staging/alice", // no retention
+	}
+	assert.Eventually(t, func() bool { return len(sink.types()) == len(want) }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, want, sink.types())
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// ExecTenant opens an exec session into a running tenant's agent container,
+// for debugging agent state inside its VM without cluster credentials:
+// POST /tenants/{tenantID}/exec?command=sh&stdin=true&tty=true, with the
+// command and its arguments as repeated command parameters. The request is
+// a SPDY upgrade, as made by client-go's remotecommand (ztm tenant exec,
+// kubectl exec); it is relayed to the API server's pods/exec under the
+// orchestrator's service account, and the connection stays open until the
+// command exits.
+func (h *Handler) ExecTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "k8s not available in local mode", http.StatusServiceUnavailable)
+		return
+	}
+	q := r.URL.Query()
+	opts := k8sclient.ExecOptions{Command: q["command"]}
+	if len(opts.Command) == 0 || opts.Command[0] == "" {
+		http.Error(w, "command is required", http.StatusBadRequest)
+		return
+	}
+	for _, flag := range []struct {
+		name string
+		dst  *bool
+	}{{"stdin", &opts.Stdin}, {"tty", &opts.TTY}} {
+		if v := q.Get(flag.name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				http.Error(w, flag.name+" must be true or false", http.StatusBadRequest)
+				return
+			}
+			*flag.dst = b
+		}
+	}
+
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec.Status != registry.StatusRunning || rec.PodName == "" {
+		http.Error(w, "tenant not running", http.StatusConflict)
+		return
+	}
+	ns := rec.Namespace
+	if ns == "" {
+		ns = h.cfg.Namespace
+	}
+
+	proxy, err := h.k8s.ExecProxy(rec.PodName, ns, opts)
+	if errors.Is(err, k8sclient.ErrExecDisabled) {
+		http.Error(w, "exec not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		slog.Error("exec proxy failed", "tenant", tenantID, "pod", rec.PodName, "err", err)
+		http.Error(w, "exec unavailable", http.StatusBadGateway)
+		return
+	}
+	// The hijacked connection keeps the server's deadlines; a session
+	// lasts as long as the operator needs it
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+	slog.Info("exec session", "tenant", tenantID, "pod", rec.PodName, "caller", caller(r),
+		"command", opts.Command, "tty", opts.TTY)
+	proxy.ServeHTTP(w, r)
+}
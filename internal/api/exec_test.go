@@ -0,0 +1,134 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// execRequest is what fakeExecAPIServer saw of an exec session
+type execRequest struct {
+	path, auth string
+	query      url.Values
+}
+
+// fakeExecAPIServer answers pods/exec like the API server: it echoes stdin
+// to stdout and reports exit code 3
+func fakeExecAPIServer(t *testing.T, seen chan<- execRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen <- execRequest{path: r.URL.Path, auth: r.Header.Get("Authorization"), query: r.URL.Query()}
+		if _, err := httpstream.Handshake(r, w, []string{"v4.channel.k8s.io"}); err != nil {
+			return
+		}
+		streams := make(chan httpstream.Stream, 4)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(s httpstream.Stream, _ <-chan struct{}) error {
+			streams <- s
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		byType := map[string]httpstream.Stream{}
+		for len(byType) < 4 {
+			select {
+			case s := <-streams:
+				byType[s.Headers().Get("streamType")] = s
+			case <-time.After(5 * time.Second):
+				t.Error("exec streams not opened")
+				return
+			}
+		}
+		io.Copy(byType["stdout"], byType["stdin"])
+		byType["stdout"].Close()
+		byType["stderr"].Close()
+		status, _ := json.Marshal(metav1.Status{Status: metav1.StatusFailure, Reason: "NonZeroExitCode",
+			Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{Type: "ExitCode", Message: "3"}}}})
+		byType["error"].Write(status)
+		byType["error"].Close()
+		<-conn.CloseChan()
+	}))
+}
+
+func TestExecTenant_RelaysSession(t *testing.T) {
+	seen := make(chan execRequest, 1)
+	apiServer := fakeExecAPIServer(t, seen)
+	defer apiServer.Close()
+
+	reg := registry.NewMock()
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{
+		ZeroClawImage: "zeroclaw:test",
+		REST:          &rest.Config{Host: apiServer.URL, BearerToken: "orchestrator-token"},
+	})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{Namespace: "tenants"})
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice", Namespace: "tenants",
+	}))
+	srv := httptest.NewServer(h.Router())
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL + "/tenants/alice/exec?command=sh&command=-c&command=cat&stdin=true")
+	executor, err := remotecommand.NewSPDYExecutor(&rest.Config{Host: srv.URL, BearerToken: "caller-key"}, http.MethodPost, u)
+	require.NoError(t, err)
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdin: strings.NewReader("hello from ztm\n"), Stdout: &stdout, Stderr: &stderr,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit code 3")
+	assert.Equal(t, "hello from ztm\n", stdout.String())
+
+	req := <-seen
+	assert.Equal(t, "/api/v1/namespaces/tenants/pods/zeroclaw-alice/exec", req.path)
+	assert.Equal(t, "Bearer orchestrator-token", req.auth, "the caller's key isn't forwarded")
+	assert.Equal(t, []string{"sh", "-c", "cat"}, req.query["command"])
+	assert.Equal(t, "zeroclaw", req.query.Get("container"))
+	assert.Equal(t, "true", req.query.Get("stdin"))
+	assert.Equal(t, "true", req.query.Get("stderr"))
+}
+
+func TestExecTenant_Refused(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "idle", Status: registry.StatusIdle}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice",
+	}))
+
+	for _, tc := range []struct {
+		name, path string
+		want       int
+	}{
+		{"no command", "/tenants/alice/exec", http.StatusBadRequest},
+		{"bad tty", "/tenants/alice/exec?command=sh&tty=maybe", http.StatusBadRequest},
+		{"unknown tenant", "/tenants/nobody/exec?command=sh", http.StatusNotFound},
+		{"not running", "/tenants/idle/exec?command=sh", http.StatusConflict},
+		{"no API server config", "/tenants/alice/exec?command=sh", http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, nil))
+			assert.Equal(t, tc.want, w.Code, w.Body.String())
+		})
+	}
+}
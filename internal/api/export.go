@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+)
+
+// bundleVersion is the format of the bundles GET /tenants/{id}/export
+// writes; POST /tenants/import refuses any other
+const bundleVersion = 1
+
+// Credentials a bundle leaves out, named in TenantBundle.Secrets
+const (
+	secretBotToken = "bot_token"
+	secretSlack    = "slack"
+)
+
+// TenantBundle is a portable copy of a tenant, for moving it to another
+// cluster or region: the response of GET /tenants/{tenantID}/export and the
+// bundle POST /tenants/import takes
+type TenantBundle struct {
+	Version     int       `json:"version"`
+	ExportedAt  time.Time `json:"exported_at"`
+	Environment string    `json:"environment,omitempty"`
+	Region      string    `json:"region,omitempty"` // the exporting orchestrator's
+	// Tenant is the registry record's settings, without credentials or
+	// anything about the tenant's pod, webhook or state measurements
+	Tenant *registry.TenantRecord `json:"tenant"`
+	// Secrets names the credentials the tenant had, which the import has to
+	// be given again: bot_token and/or slack
+	Secrets []string `json:"secrets,omitempty"`
+	// KV is the tenant's key-value store
+	KV map[string]string `json:"kv,omitempty"`
+	// State lists the tenant's S3 state as exported, for the import to
+	// check it was copied. Nil if the exporting orchestrator has no S3.
+	State *StateManifest `json:"state,omitempty"`
+}
+
+// StateManifest lists the objects under a tenant's S3 prefix
+type StateManifest struct {
+	Prefix  string            `json:"prefix"`
+	Objects []snapshot.Object `json:"objects"`
+	Bytes   int64             `json:"bytes"`
+}
+
+// importRequest is the body of POST /tenants/import
+type importRequest struct {
+	Bundle TenantBundle `json:"bundle"`
+	// BotToken and Slack replace the credentials the bundle left out
+	BotToken string                `json:"bot_token"`
+	Slack    *registry.SlackConfig `json:"slack"`
+	// HomeRegion is where the tenant will run; empty means this
+	// orchestrator's region, not the bundle's
+	HomeRegion string `json:"home_region"`
+	// AllowMissingState imports the tenant even if its state here doesn't
+	// match the bundle's manifest
+	AllowMissingState bool `json:"allow_missing_state"`
+}
+
+// ImportResult is the response of POST /tenants/import
+type ImportResult struct {
+	Tenant *registry.TenantRecord `json:"tenant"`
+	// StateObjects counts the manifest's objects found under the tenant's
+	// prefix here with the same size, and MissingState lists the others
+	StateObjects int      `json:"state_objects"`
+	MissingState []string `json:"missing_state,omitempty"`
+	// MissingSecrets names the bundle's credentials the import wasn't
+	// given; set them with PATCH /tenants/{id}
+	MissingSecrets []string `json:"missing_secrets,omitempty"`
+}
+
+// portableRecord copies the settings of rec that move with the tenant. A
+// Slack app shows up as an empty slack object, as in redact.
+func portableRecord(rec *registry.TenantRecord) *registry.TenantRecord {
+	out := &registry.TenantRecord{
+		TenantID:        rec.TenantID,
+		BotUsername:     rec.BotUsername,
+		CreatedAt:       rec.CreatedAt,
+		LastActiveAt:    rec.LastActiveAt,
+		IdleTimeoutS:    rec.IdleTimeoutS,
+		Tier:            rec.Tier,
+		Image:           rec.Image,
+		DNS:             rec.DNS,
+		KeepWarm:        rec.KeepWarm,
+		LogForward:      rec.LogForward,
+		HomeRegion:      rec.HomeRegion,
+		AllowedUpdates:  rec.AllowedUpdates,
+		Locale:          rec.Locale,
+		Timezone:        rec.Timezone,
+		Disabled:        rec.Disabled,
+		MaxMessageAgeS:  rec.MaxMessageAgeS,
+		MaxReplyBytes:   rec.MaxReplyBytes,
+		NoAnnouncements: rec.NoAnnouncements,
+		WeeklyDigest:    rec.WeeklyDigest,
+	}
+	if rec.Slack != nil {
+		out.Slack = &registry.SlackConfig{}
+	}
+	return out
+}
+
+// ExportTenant returns a bundle of the tenant's settings, key-value store
+// and a manifest of its S3 state, which POST /tenants/import recreates it
+// from in another cluster. The state itself isn't in the bundle: copy the
+// tenant's prefix to the other cluster's bucket. Suspend the tenant first
+// so its state doesn't change after the export.
+func (h *Handler) ExportTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if rec.Status == registry.StatusTerminated {
+		writeTerminated(w)
+		return
+	}
+
+	b := TenantBundle{
+		Version:     bundleVersion,
+		ExportedAt:  time.Now().UTC(),
+		Environment: h.cfg.Environment.Name,
+		Region:      h.cfg.Region,
+		Tenant:      portableRecord(rec),
+	}
+	if rec.HasBot() {
+		b.Secrets = append(b.Secrets, secretBotToken)
+	}
+	if rec.Slack != nil {
+		b.Secrets = append(b.Secrets, secretSlack)
+	}
+	if b.KV, err = h.reg.ListKV(r.Context(), tenantID); err != nil {
+		slog.Error("export: list kv failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if h.cfg.Snapshots != nil {
+		prefix := rec.S3Prefix
+		if prefix == "" {
+			prefix = h.cfg.Environment.S3Prefix(tenantID)
+		}
+		objects, err := h.cfg.Snapshots.Objects(r.Context(), prefix)
+		if err != nil {
+			slog.Error("export: list state failed", "tenant", tenantID, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		b.State = &StateManifest{Prefix: prefix, Objects: objects}
+		if b.State.Objects == nil {
+			b.State.Objects = []snapshot.Object{}
+		}
+		for _, obj := range objects {
+			b.State.Bytes += obj.Size
+		}
+	}
+	slog.Info("tenant exported", "tenant", tenantID, "by", caller(r))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", tenantID+".json"))
+	json.NewEncoder(w).Encode(b)
+}
+
+// ImportTenant creates a tenant from a bundle exported by another cluster,
+// as POST /tenants would: its bot token is stored and its webhook pointed
+// at this cluster's router. The tenant's state must already be under its
+// prefix here; with a manifest in the bundle and S3 configured, an import
+// whose state doesn't match is refused with 409 unless allow_missing_state
+// is set.
+// POST /tenants/import
+func (h *Handler) ImportTenant(w http.ResponseWriter, r *http.Request) {
+	var req importRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	b := req.Bundle
+	if b.Version != bundleVersion {
+		http.Error(w, fmt.Sprintf("unsupported bundle version %d, want %d", b.Version, bundleVersion), http.StatusBadRequest)
+		return
+	}
+	if b.Tenant == nil || b.Tenant.TenantID == "" {
+		http.Error(w, "bundle has no tenant", http.StatusBadRequest)
+		return
+	}
+	t := b.Tenant
+	var res ImportResult
+
+	if b.State != nil && h.cfg.Snapshots != nil {
+		prefix := h.cfg.Environment.S3Prefix(t.TenantID)
+		have, err := h.cfg.Snapshots.Objects(r.Context(), prefix)
+		if err != nil {
+			slog.Error("import: list state failed", "tenant", t.TenantID, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		sizes := make(map[string]int64, len(have))
+		for _, obj := range have {
+			sizes[obj.Key] = obj.Size
+		}
+		for _, obj := range b.State.Objects {
+			if size, ok := sizes[obj.Key]; ok && size == obj.Size {
+				res.StateObjects++
+			} else {
+				res.MissingState = append(res.MissingState, obj.Key)
+			}
+		}
+		if len(res.MissingState) > 0 && !req.AllowMissingState {
+			http.Error(w, fmt.Sprintf("%d of %d state objects missing or different under %s; copy the state first or set allow_missing_state",
+				len(res.MissingState), len(b.State.Objects), prefix), http.StatusConflict)
+			return
+		}
+	}
+
+	_, err := h.createTenant(r.Context(), createTenantRequest{
+		TenantID:       t.TenantID,
+		IdleTimeoutS:   t.IdleTimeoutS,
+		BotToken:       req.BotToken,
+		Tier:           t.Tier,
+		Image:          t.Image,
+		DNS:            t.DNS,
+		KeepWarm:       t.KeepWarm,
+		LogForward:     t.LogForward,
+		HomeRegion:     req.HomeRegion,
+		AllowedUpdates: t.AllowedUpdates,
+		Slack:          req.Slack,
+		Locale:         t.Locale,
+		Timezone:       t.Timezone,
+	})
+	if err != nil {
+		var ce *createError
+		errors.As(err, &ce)
+		http.Error(w, ce.msg, ce.status)
+		return
+	}
+	if err := h.importSettings(r, t, b.KV); err != nil {
+		slog.Error("import: restoring settings failed", "tenant", t.TenantID, "err", err)
+		http.Error(w, "tenant created, but restoring its settings failed; set them with PATCH /tenants/"+t.TenantID, http.StatusInternalServerError)
+		return
+	}
+	for _, secret := range b.Secrets {
+		if secret == secretBotToken && req.BotToken == "" || secret == secretSlack && req.Slack == nil {
+			res.MissingSecrets = append(res.MissingSecrets, secret)
+		}
+	}
+	if res.Tenant, err = h.reg.GetTenant(r.Context(), t.TenantID); err != nil || res.Tenant == nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	redact(res.Tenant)
+	slog.Info("tenant imported", "tenant", t.TenantID, "by", caller(r), "from_region", b.Region,
+		"state_objects", res.StateObjects, "missing_state", len(res.MissingState), "missing_secrets", res.MissingSecrets)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+// importSettings restores the settings of t that creating a tenant doesn't
+// take, and its key-value store
+func (h *Handler) importSettings(r *http.Request, t *registry.TenantRecord, kv map[string]string) error {
+	ctx := r.Context()
+	if t.MaxMessageAgeS != 0 {
+		if err := h.reg.UpdateMaxMessageAge(ctx, t.TenantID, t.MaxMessageAgeS); err != nil {
+			return fmt.Errorf("max_message_age_s: %w", err)
+		}
+	}
+	if t.MaxReplyBytes != 0 {
+		if err := h.reg.UpdateMaxReplyBytes(ctx, t.TenantID, t.MaxReplyBytes); err != nil {
+			return fmt.Errorf("max_reply_bytes: %w", err)
+		}
+	}
+	if t.NoAnnouncements {
+		if err := h.reg.UpdateNoAnnouncements(ctx, t.TenantID, true); err != nil {
+			return fmt.Errorf("no_announcements: %w", err)
+		}
+	}
+	if t.WeeklyDigest {
+		if err := h.reg.UpdateWeeklyDigest(ctx, t.TenantID, true); err != nil {
+			return fmt.Errorf("weekly_digest: %w", err)
+		}
+	}
+	if t.Disabled {
+		if err := h.setTenantDisabled(r, t.TenantID, true); err != nil {
+			return fmt.Errorf("disabled: %w", err)
+		}
+	}
+	for key, value := range kv {
+		if err := h.reg.PutKV(ctx, t.TenantID, key, value, kvMaxKeys); err != nil {
+			return fmt.Errorf("kv %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	srcReg := registry.NewMock()
+	srcStore := snapshot.NewMemory()
+	src := api.New(srcReg, nil, lock.NewMock(), nil, nil, api.Config{Region: "us-east-1", Snapshots: srcStore})
+	require.NoError(t, srcReg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusIdle, S3Prefix: "tenants/alice/", BotToken: "123:abc",
+		Tier: "premium", Locale: "de", IdleTimeoutS: 900, HomeRegion: "us-east-1",
+		Slack:           &registry.SlackConfig{SigningSecret: "s3cret", BotToken: "xoxb-1"},
+		MaxReplyBytes:   2000,
+		NoAnnouncements: true,
+		WebhookSecret:   "whsec-1",
+	}))
+	require.NoError(t, srcReg.PutKV(ctx, "alice", "plan", "pro", 64))
+	srcStore.Put("tenants/alice/memory.db", 100)
+	srcStore.Put("tenants/alice/notes/a.md", 5)
+	do := func(h *api.Handler, method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(src, http.MethodGet, "/tenants/alice/export", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	raw := rec.Body.String()
+	assert.NotContains(t, raw, "123:abc")
+	assert.NotContains(t, raw, "s3cret")
+	assert.NotContains(t, raw, "whsec-1")
+	var bundle api.TenantBundle
+	require.NoError(t, json.Unmarshal([]byte(raw), &bundle))
+	assert.Equal(t, 1, bundle.Version)
+	assert.Equal(t, "us-east-1", bundle.Region)
+	assert.Equal(t, []string{"bot_token", "slack"}, bundle.Secrets)
+	assert.Equal(t, map[string]string{"plan": "pro"}, bundle.KV)
+	assert.Equal(t, []snapshot.Object{{Key: "memory.db", Size: 100}, {Key: "notes/a.md", Size: 5}}, bundle.State.Objects)
+	assert.EqualValues(t, 105, bundle.State.Bytes)
+
+	dstReg := registry.NewMock()
+	dstStore := snapshot.NewMemory()
+	dst := api.New(dstReg, nil, lock.NewMock(), nil, nil, api.Config{Region: "eu-west-1", Snapshots: dstStore})
+	body := func(extra string) string {
+		return `{"bundle":` + raw + extra + `}`
+	}
+
+	// Refused until the state is copied
+	rec = do(dst, http.MethodPost, "/tenants/import", body(""))
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "2 of 2 state objects")
+	dstStore.Put("tenants/alice/memory.db", 100)
+	dstStore.Put("tenants/alice/notes/a.md", 4)
+	assert.Equal(t, http.StatusConflict, do(dst, http.MethodPost, "/tenants/import", body("")).Code, "a different size")
+	dstStore.Put("tenants/alice/notes/a.md", 5)
+
+	rec = do(dst, http.MethodPost, "/tenants/import", body(`,"bot_token":"456:def"`))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var res api.ImportResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, 2, res.StateObjects)
+	assert.Equal(t, []string{"slack"}, res.MissingSecrets)
+	assert.Empty(t, res.Tenant.BotToken, "redacted")
+
+	tenant, _ := dstReg.GetTenant(ctx, "alice")
+	require.NotNil(t, tenant)
+	assert.Equal(t, "456:def", tenant.BotToken)
+	assert.Nil(t, tenant.Slack)
+	assert.Equal(t, "premium", tenant.Tier)
+	assert.Equal(t, "de", tenant.Locale)
+	assert.EqualValues(t, 900, tenant.IdleTimeoutS)
+	assert.Equal(t, "eu-west-1", tenant.HomeRegion, "the importing orchestrator's region")
+	assert.EqualValues(t, 2000, tenant.MaxReplyBytes)
+	assert.True(t, tenant.NoAnnouncements)
+	kv, _ := dstReg.ListKV(ctx, "alice")
+	assert.Equal(t, map[string]string{"plan": "pro"}, kv)
+
+	assert.Equal(t, http.StatusConflict, do(dst, http.MethodPost, "/tenants/import", body("")).Code, "already exists")
+}
+
+func TestImport_MissingStateAllowed(t *testing.T) {
+	reg := registry.NewMock()
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{Snapshots: snapshot.NewMemory()})
+	bundle := `{"version":1,"tenant":{"TenantID":"bob"},"state":{"prefix":"tenants/bob/","objects":[{"key":"memory.db","size":1}],"bytes":1}}`
+	do := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/import", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(`{"bundle":` + bundle + `,"allow_missing_state":true}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var res api.ImportResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, []string{"memory.db"}, res.MissingState)
+
+	assert.Equal(t, http.StatusBadRequest, do(`{"bundle":{"version":2,"tenant":{"TenantID":"carol"}}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(`{"bundle":{"version":1}}`).Code)
+}
+
+func TestExport_Refused(t *testing.T) {
+	reg := registry.NewMock()
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "bob", Status: registry.StatusTerminated}))
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{})
+	for path, want := range map[string]int{"/tenants/bob/export": http.StatusGone, "/tenants/carol/export": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Code, path)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// FailedError refuses a wake of a tenant marked failed after too many
+// failed wakes in a row
+type FailedError struct {
+	LastError string
+}
+
+func (e *FailedError) Error() string {
+	if e.LastError == "" {
+		return "tenant failed"
+	}
+	return "tenant failed: " + e.LastError
+}
+
+// writeFailed answers 409, which the router turns into an outage reply
+// instead of trying to start the pod again
+func writeFailed(w http.ResponseWriter, err *FailedError) {
+	http.Error(w, err.Error()+"; reset it with POST /tenants/{id}/reset", http.StatusConflict)
+}
+
+// checkFailed refuses to wake a tenant marked failed
+func checkFailed(rec *registry.TenantRecord) error {
+	if rec != nil && rec.Status == registry.StatusFailed {
+		return &FailedError{LastError: rec.LastError}
+	}
+	return nil
+}
+
+// recordWakeFailure counts a failed wake against the tenant, marking it
+// failed once WakeFailureLimit wakes in a row have failed. A wake that lost
+// a race with a suspend or delete didn't fail.
+func (h *Handler) recordWakeFailure(ctx context.Context, tenantID string, wakeErr error) {
+	if errors.Is(wakeErr, registry.ErrSuspended) || errors.Is(wakeErr, registry.ErrTerminated) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	failed, err := h.reg.RecordWakeFailure(ctx, tenantID, wakeErr.Error(), h.cfg.WakeFailureLimit)
+	if err != nil {
+		slog.Warn("record wake failure failed", "tenant", tenantID, "err", err)
+		return
+	}
+	if failed {
+		slog.Error("tenant marked failed: too many failed wakes", "tenant", tenantID, "limit", h.cfg.WakeFailureLimit, "last_error", wakeErr)
+		if h.rdb != nil {
+			h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, tenantID))
+		}
+		h.cfg.Events.Publish(events.TenantFailed, h.cfg.Environment.Name, tenantID, map[string]any{"error": wakeErr.Error()})
+	}
+}
+
+// ResetTenant clears a failed tenant's wake failures after an operator has
+// fixed their cause. The tenant becomes idle and its next message wakes it
+// as usual.
+func (h *Handler) ResetTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	err = h.reg.ResetTenant(ctx, tenantID)
+	if errors.Is(err, registry.ErrNotFailed) {
+		http.Error(w, "tenant not failed", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error("reset: update status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("reset: tenant reset", "tenant", tenantID, "last_error", rec.LastError)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuspendResult{TenantID: tenantID, Status: registry.StatusIdle})
+}
@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestWakeFailures_MarkTenantFailed: after WakeFailureLimit failed wakes in
+// a row the tenant is failed and wakes are refused until it is reset
+func TestWakeFailures_MarkTenantFailed(t *testing.T) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:        "tenants",
+		PodReadyWait:     50 * time.Millisecond, // pods never become ready
+		WakeFailureLimit: 2,
+	})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants"}))
+	post := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, post("/wake/alice").Code)
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+	assert.Equal(t, 1, tenant.WakeFailures)
+	assert.Contains(t, tenant.LastError, "wait pod ready")
+
+	assert.Equal(t, http.StatusServiceUnavailable, post("/wake/alice").Code)
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusFailed, tenant.Status)
+	assert.Equal(t, 2, tenant.WakeFailures)
+
+	// Further wakes are refused without trying to start a pod
+	for _, path := range []string{"/wake/alice", "/wake/alice?async=true"} {
+		rec := post(path)
+		assert.Equal(t, http.StatusConflict, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), "wait pod ready", path)
+	}
+	wakes, _ := reg.ListWakes(ctx, "alice")
+	assert.Len(t, wakes, 2)
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice", nil))
+	var got registry.TenantRecord
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, registry.StatusFailed, got.Status)
+	assert.Contains(t, got.LastError, "wait pod ready")
+
+	rec = post("/tenants/alice/reset")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+	assert.Zero(t, tenant.WakeFailures)
+	assert.Empty(t, tenant.LastError)
+
+	assert.Equal(t, http.StatusConflict, post("/tenants/alice/reset").Code)
+	assert.Equal(t, http.StatusNotFound, post("/tenants/bob/reset").Code)
+
+	// A successful wake clears the count
+	_, _ = reg.RecordWakeFailure(ctx, "alice", "boom", 2)
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+	h = api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+	})
+	assert.Equal(t, http.StatusOK, post("/wake/alice").Code)
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusRunning, tenant.Status)
+	assert.Zero(t, tenant.WakeFailures)
+}
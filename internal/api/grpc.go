@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api/tenancyv1"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EnvironmentMetadata is the gRPC metadata key naming the environment a call
+// is for. Calls without it go to the default environment.
+const EnvironmentMetadata = "x-environment"
+
+// GRPCService serves tenancy.v1.TenantService (proto/tenancy/v1) for
+// in-cluster callers that want typed clients and a streaming watch instead
+// of polling the REST API. It shares the REST handlers' logic, so creates
+// and wakes behave the same on both.
+type GRPCService struct {
+	tenancyv1.UnimplementedTenantServiceServer
+	handlers map[string]*Handler // by environment name; "" is the default
+	// WatchInterval is how often WatchTenants polls the registry for changes
+	WatchInterval time.Duration
+}
+
+// NewGRPCService returns a service with no environments; see Add
+func NewGRPCService() *GRPCService {
+	return &GRPCService{handlers: map[string]*Handler{}, WatchInterval: 2 * time.Second}
+}
+
+// Add serves h's environment
+func (s *GRPCService) Add(h *Handler) {
+	s.handlers[h.cfg.Environment.Name] = h
+}
+
+// Server returns a gRPC server with the service registered behind the same
+// API key auth as the REST API
+func (s *GRPCService) Server(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+			ctx, err := s.authenticate(ctx, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+			ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+			if err != nil {
+				return err
+			}
+			return next(srv, &authedStream{ss, ctx})
+		}),
+	)
+	srv := grpc.NewServer(opts...)
+	tenancyv1.RegisterTenantServiceServer(srv, s)
+	return srv
+}
+
+type grpcHandlerKey struct{}
+
+// authedStream carries the context authenticate derived to the stream's
+// handler
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
+
+// authenticate picks the call's environment and, like requireAPIKey, checks
+// its API key when the environment has a key store
+func (s *GRPCService) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	env := first(EnvironmentMetadata)
+	h, ok := s.handlers[env]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown environment %q", env)
+	}
+	ctx = context.WithValue(ctx, grpcHandlerKey{}, h)
+	if h.cfg.APIKeys == nil {
+		return ctx, nil
+	}
+	key := first(strings.ToLower(APIKeyHeader))
+	if auth := first("authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return nil, status.Error(codes.Unauthenticated, "api key required")
+	}
+	name, ok, err := h.cfg.APIKeys.Lookup(ctx, key)
+	if err != nil {
+		slog.Error("api key lookup failed", "err", err)
+		return nil, status.Error(codes.Unavailable, "auth unavailable")
+	}
+	if !ok {
+		slog.Warn("api key rejected", "method", method, "grpc", true)
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	return context.WithValue(ctx, callerKey{}, name), nil
+}
+
+func grpcHandler(ctx context.Context) *Handler {
+	return ctx.Value(grpcHandlerKey{}).(*Handler)
+}
+
+func (s *GRPCService) CreateTenant(ctx context.Context, req *tenancyv1.CreateTenantRequest) (*tenancyv1.Tenant, error) {
+	rec, err := grpcHandler(ctx).createTenant(ctx, createTenantRequest{
+		TenantID:       req.TenantId,
+		IdleTimeoutS:   req.IdleTimeoutS,
+		BotToken:       req.BotToken,
+		Tier:           req.Tier,
+		Image:          req.Image,
+		KeepWarm:       req.KeepWarm,
+		HomeRegion:     req.HomeRegion,
+		AllowedUpdates: req.AllowedUpdates,
+		Locale:         req.Locale,
+		Timezone:       req.Timezone,
+	})
+	if err != nil {
+		var ce *createError
+		errors.As(err, &ce)
+		return nil, status.Error(grpcCode(ce.status), ce.msg)
+	}
+	return tenantProto(rec), nil
+}
+
+func (s *GRPCService) GetTenant(ctx context.Context, req *tenancyv1.GetTenantRequest) (*tenancyv1.Tenant, error) {
+	if req.TenantId == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id required")
+	}
+	rec, err := grpcHandler(ctx).reg.GetTenant(ctx, req.TenantId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	if rec == nil {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return tenantProto(rec), nil
+}
+
+func (s *GRPCService) ListTenants(ctx context.Context, req *tenancyv1.ListTenantsRequest) (*tenancyv1.ListTenantsResponse, error) {
+	records, err := grpcHandler(ctx).reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("list tenants failed", "err", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	resp := &tenancyv1.ListTenantsResponse{}
+	for _, rec := range sortedByID(records) {
+		if req.Status == "" || string(rec.Status) == req.Status {
+			resp.Tenants = append(resp.Tenants, tenantProto(rec))
+		}
+	}
+	return resp, nil
+}
+
+// WatchTenants polls the registry every WatchInterval and sends what changed
+// since the last poll. The first poll's tenants are all ADDED.
+func (s *GRPCService) WatchTenants(req *tenancyv1.WatchTenantsRequest, stream grpc.ServerStreamingServer[tenancyv1.TenantEvent]) error {
+	ctx := stream.Context()
+	types := map[string]tenancyv1.TenantEvent_Type{
+		TenantAdded:    tenancyv1.TenantEvent_TYPE_ADDED,
+		TenantModified: tenancyv1.TenantEvent_TYPE_MODIFIED,
+		TenantDeleted:  tenancyv1.TenantEvent_TYPE_DELETED,
+	}
+	sameProto := func(a, b *registry.TenantRecord) bool { return proto.Equal(tenantProto(a), tenantProto(b)) }
+	err := grpcHandler(ctx).pollTenants(ctx, req.TenantId, s.WatchInterval, sameProto, func(typ string, rec *registry.TenantRecord) error {
+		if typ == "" {
+			return nil
+		}
+		t := tenantProto(rec)
+		if typ == TenantDeleted {
+			t = &tenancyv1.Tenant{TenantId: rec.TenantID}
+		}
+		return stream.Send(&tenancyv1.TenantEvent{Type: types[typ], Tenant: t})
+	})
+	var registryErr *watchError
+	if errors.As(err, &registryErr) {
+		slog.Error("watch tenants failed", "tenant", req.TenantId, "err", err)
+		return status.Error(codes.Unavailable, "registry unavailable")
+	}
+	return err
+}
+
+// watched returns the tenants a watch on tenantID (empty = all) covers
+func watched(ctx context.Context, h *Handler, tenantID string) ([]*registry.TenantRecord, error) {
+	if tenantID == "" {
+		return h.reg.ListAll(ctx)
+	}
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return []*registry.TenantRecord{rec}, nil
+}
+
+func (s *GRPCService) WakeTenant(ctx context.Context, req *tenancyv1.WakeTenantRequest) (*tenancyv1.WakeTenantResponse, error) {
+	if req.TenantId == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id required")
+	}
+	rec, err := grpcHandler(ctx).wakeOrGet(ctx, req.TenantId)
+	if err != nil {
+		return nil, wakeStatus(req.TenantId, err)
+	}
+	return &tenancyv1.WakeTenantResponse{PodIp: rec.PodIP, IdleTimeoutS: rec.IdleTimeoutS}, nil
+}
+
+// wakeStatus is the gRPC counterpart of Wake's error responses
+func wakeStatus(tenantID string, err error) error {
+	var misdirected *MisdirectedError
+	var overQuota *QuotaExceededError
+	var overRun *RunQuotaError
+	var disabled *DisabledError
+	var failed *FailedError
+	switch {
+	case errors.As(err, &misdirected):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &overQuota):
+		slog.Warn("wake refused: state quota exceeded", "tenant", tenantID, "bytes", overQuota.Bytes, "quota_bytes", overQuota.QuotaBytes)
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.As(err, &overRun):
+		slog.Warn("wake refused: run quota exceeded", "tenant", tenantID, "limit", overRun.Limit, "max", overRun.Max)
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, registry.ErrSuspended):
+		slog.Info("wake refused: tenant suspended", "tenant", tenantID)
+		return status.Error(codes.FailedPrecondition, "tenant suspended")
+	case errors.Is(err, registry.ErrTerminated):
+		slog.Info("wake refused: tenant deleted", "tenant", tenantID)
+		return status.Error(codes.NotFound, "tenant deleted")
+	case errors.As(err, &disabled):
+		slog.Info("wake refused: kill switch", "tenant", tenantID, "global", disabled.Global)
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.As(err, &failed):
+		slog.Info("wake refused: tenant failed", "tenant", tenantID, "last_error", failed.LastError)
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	slog.Error("wake failed", "tenant", tenantID, "err", err)
+	return status.Error(codes.Unavailable, "failed to wake tenant")
+}
+
+// grpcCode maps the HTTP status a REST handler would answer to a gRPC code
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+func sortedByID(records []*registry.TenantRecord) []*registry.TenantRecord {
+	sort.Slice(records, func(i, j int) bool { return records[i].TenantID < records[j].TenantID })
+	return records
+}
+
+// tenantProto converts rec, leaving out its credentials
+func tenantProto(rec *registry.TenantRecord) *tenancyv1.Tenant {
+	timestamp := func(t time.Time) *timestamppb.Timestamp {
+		if t.IsZero() {
+			return nil
+		}
+		return timestamppb.New(t)
+	}
+	return &tenancyv1.Tenant{
+		TenantId:     rec.TenantID,
+		Status:       string(rec.Status),
+		PodName:      rec.PodName,
+		PodIp:        rec.PodIP,
+		Namespace:    rec.Namespace,
+		Tier:         rec.Tier,
+		Image:        rec.Image,
+		IdleTimeoutS: rec.IdleTimeoutS,
+		KeepWarm:     rec.KeepWarm,
+		HomeRegion:   rec.HomeRegion,
+		BotUsername:  rec.BotUsername,
+		Locale:       rec.Locale,
+		Timezone:     rec.Timezone,
+		Disabled:     rec.Disabled,
+		CreatedAt:    timestamp(rec.CreatedAt),
+		LastActiveAt: timestamp(rec.LastActiveAt),
+	}
+}
@@ -0,0 +1,156 @@
+package api_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/api/tenancyv1"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newGRPCClient serves svc over an in-memory listener
+func newGRPCClient(t *testing.T, svc *api.GRPCService) tenancyv1.TenantServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := svc.Server()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return tenancyv1.NewTenantServiceClient(conn)
+}
+
+func TestGRPC_CreateGetList(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	svc := api.NewGRPCService()
+	svc.Add(h)
+	client := newGRPCClient(t, svc)
+	ctx := context.Background()
+
+	created, err := client.CreateTenant(ctx, &tenancyv1.CreateTenantRequest{TenantId: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "idle", created.Status)
+	assert.EqualValues(t, 300, created.IdleTimeoutS)
+
+	_, err = client.CreateTenant(ctx, &tenancyv1.CreateTenantRequest{TenantId: "alice"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.CreateTenant(ctx, &tenancyv1.CreateTenantRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	got, err := client.GetTenant(ctx, &tenancyv1.GetTenantRequest{TenantId: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.TenantId)
+	_, err = client.GetTenant(ctx, &tenancyv1.GetTenantRequest{TenantId: "nobody"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusRunning, BotToken: "secret"})
+	list, err := client.ListTenants(ctx, &tenancyv1.ListTenantsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Tenants, 2)
+	assert.Equal(t, "alice", list.Tenants[0].TenantId)
+	list, err = client.ListTenants(ctx, &tenancyv1.ListTenantsRequest{Status: "running"})
+	require.NoError(t, err)
+	require.Len(t, list.Tenants, 1)
+	assert.Equal(t, "bob", list.Tenants[0].TenantId)
+}
+
+func TestGRPC_WakeTenant(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	svc := api.NewGRPCService()
+	svc.Add(h)
+	client := newGRPCClient(t, svc)
+	ctx := context.Background()
+
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants", IdleTimeoutS: 600})
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+	resp, err := client.WakeTenant(ctx, &tenancyv1.WakeTenantRequest{TenantId: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", resp.PodIp)
+	assert.EqualValues(t, 600, resp.IdleTimeoutS)
+
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusSuspended, Namespace: "tenants"})
+	_, err = client.WakeTenant(ctx, &tenancyv1.WakeTenantRequest{TenantId: "bob"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGRPC_WatchTenants(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	svc := api.NewGRPCService()
+	svc.WatchInterval = 10 * time.Millisecond
+	svc.Add(h)
+	client := newGRPCClient(t, svc)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+	stream, err := client.WatchTenants(ctx, &tenancyv1.WatchTenantsRequest{})
+	require.NoError(t, err)
+
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, tenancyv1.TenantEvent_TYPE_ADDED, ev.Type)
+	assert.Equal(t, "alice", ev.Tenant.TenantId)
+
+	reg.UpdateStatus(ctx, "alice", registry.StatusRunning, "zeroclaw-alice", "10.0.0.1")
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, tenancyv1.TenantEvent_TYPE_MODIFIED, ev.Type)
+	assert.Equal(t, "running", ev.Tenant.Status)
+	assert.Equal(t, "10.0.0.1", ev.Tenant.PodIp)
+
+	reg.DeleteTenant(ctx, "alice")
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, tenancyv1.TenantEvent_TYPE_DELETED, ev.Type)
+	assert.Equal(t, "alice", ev.Tenant.TenantId)
+}
+
+func TestGRPC_Auth(t *testing.T) {
+	keys, err := apikey.ParseStatic("ops=s3cret")
+	require.NoError(t, err)
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(registry.NewMock(), k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		APIKeys:      keys,
+	})
+	svc := api.NewGRPCService()
+	svc.Add(h)
+	client := newGRPCClient(t, svc)
+
+	list := func(md ...string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), md...)
+		_, err := client.ListTenants(ctx, &tenancyv1.ListTenantsRequest{})
+		return err
+	}
+	assert.Equal(t, codes.Unauthenticated, status.Code(list()))
+	assert.Equal(t, codes.Unauthenticated, status.Code(list("authorization", "Bearer wrong")))
+	assert.NoError(t, list("authorization", "Bearer s3cret"))
+	assert.NoError(t, list("x-api-key", "s3cret"))
+	assert.Equal(t, codes.NotFound, status.Code(list("x-api-key", "s3cret", api.EnvironmentMetadata, "staging")),
+		"unknown environment")
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	stream, err := client.WatchTenants(ctx, &tenancyv1.WatchTenantsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "watches are authenticated too")
+}
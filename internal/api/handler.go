@@ -3,28 +3,143 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
 	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
 	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/outputs"
 	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/saga"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
 	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/shawn/agentic-tenancy/internal/tenantrole"
+	"golang.org/x/time/rate"
 )
 
-const routerEndpointCachePrefix = "router:endpoint:"
-
 // Config holds orchestrator API configuration
 type Config struct {
 	Namespace    string
 	S3Bucket     string
 	WakeLockTTL  time.Duration
 	PodReadyWait time.Duration
+	// DefaultChannel is the image alias followed by tenants without a pin
+	DefaultChannel string
+	// RestartDrain is how long a blue/green restart keeps the old pod after
+	// switching traffic, so in-flight messages can complete
+	RestartDrain time.Duration
+	// Environment scopes Redis keys and S3 prefixes when several control-plane
+	// environments share infrastructure; zero value is the legacy layout
+	Environment environment.Environment
+	// Region is this orchestrator's region in a multi-region deployment. New
+	// tenants are homed here, and tenants homed elsewhere are never woken
+	// here. Empty disables region checks.
+	Region string
+	// WakeHistory is how many wake attempts are kept per tenant
+	WakeHistory int
+	// EventHistory is how many events are kept per tenant (default 50)
+	EventHistory int
+	// WakeFailureLimit is how many wakes in a row may fail before the
+	// tenant is marked failed and further wakes are refused until it is
+	// reset. Zero means 5; negative never marks tenants failed.
+	WakeFailureLimit int
+	// RequireConfirm makes destructive endpoints refuse requests without an
+	// X-Confirm header or dry-run confirm token
+	RequireConfirm bool
+	// APIKeys authenticates every route but /healthz. Nil disables auth.
+	APIKeys apikey.Store
+	// WebhookRate and WebhookBurst size the token bucket that paces
+	// setWebhook calls (per second). Registrations over the limit are queued
+	// for RunWebhookRetries rather than sent.
+	WebhookRate  float64
+	WebhookBurst int
+	// Secrets holds tenants' bot tokens, each named BotTokenSecretPrefix +
+	// tenant ID (scoped by environment); the registry keeps only the name.
+	// Nil stores tokens in the registry record.
+	Secrets              secrets.Store
+	BotTokenSecretPrefix string
+	// StateSizer measures tenant state for GET /tenants/{id}/state. Nil
+	// serves the size last recorded by the state size watcher.
+	StateSizer statesize.Sizer
+	// StateDeleter removes a purged tenant's S3 state. Nil leaves it in the
+	// bucket.
+	StateDeleter statesize.Deleter
+	// Snapshots copies tenants' S3 state to snapshots and back for
+	// /tenants/{id}/snapshots. Nil refuses snapshots.
+	Snapshots snapshot.Store
+	// SnapshotKeep is how many snapshots are kept per tenant, the oldest
+	// deleted beyond it. Zero means 10; negative keeps them all.
+	SnapshotKeep int
+	// DeleteRetention is how long a deleted tenant can be restored before
+	// it is purged. Zero purges it on delete.
+	DeleteRetention time.Duration
+	// StateQuota holds the per-tier storage quotas enforced on wake
+	StateQuota statesize.QuotaPolicy
+	// RunQuota caps running pods, wakes and pod time, enforced on wake
+	RunQuota RunQuota
+	// RolloutMaxUnavailable is how many pods POST /admin/rollout replaces at
+	// once unless the request says otherwise
+	RolloutMaxUnavailable int
+	// StatusPageSecret signs the links POST /tenants/{id}/status-link
+	// issues; the router verifies them with the same secret. Empty disables
+	// status links.
+	StatusPageSecret []byte
+	// StatusPageURL is the router's public base URL for this environment
+	StatusPageURL string
+	// WebChatSecret signs the links POST /tenants/{id}/chat-link issues to
+	// the tenant's web chat page on the router (at StatusPageURL). Empty
+	// disables chat links.
+	WebChatSecret []byte
+	// KVTokenSecret signs the tokens tenant pods use to reach their
+	// key-value store at KVURL, this environment's orchestrator API as pods
+	// see it. Pods get no KV access unless both are set.
+	KVTokenSecret []byte
+	KVURL         string
+	// Events publishes tenant lifecycle events to external sinks. Nil
+	// publishes none.
+	Events *events.Bus
+	// Outputs keeps replies the router cuts short, for
+	// POST /tenants/{id}/outputs. Nil refuses them, and the router sends cut
+	// replies without a link.
+	Outputs outputs.Store
+	// PodClient calls tenant pods for POST /tenants/{id}/test-message and
+	// restore status checks. Nil uses http.DefaultClient.
+	PodClient *http.Client
+	// WaitAgentRestore makes wakes and restarts wait, within PodReadyWait,
+	// for the agent to report its state restored at GET /restore-status
+	// before the pod takes messages. Otherwise a running pod is ready.
+	WaitAgentRestore bool
+	// TenantRoles gives each tenant its own ServiceAccount and an IAM role
+	// limited to its S3 prefix, created and deleted with the tenant. Nil runs
+	// every tenant pod as the shared zeroclaw-tenant ServiceAccount.
+	TenantRoles tenantrole.Provisioner
+	// Notifier delivers POST /admin/broadcast announcements to tenant
+	// owners, paced at BroadcastRate per second (default 1). Nil refuses
+	// broadcasts.
+	Notifier      notify.Notifier
+	BroadcastRate float64
+	// WatchInterval is how often GET /tenants/watch polls the registry
+	// (default 2s)
+	WatchInterval time.Duration
 }
 
 // Handler is the main orchestrator HTTP handler
@@ -35,6 +150,12 @@ type Handler struct {
 	rdb  *redis.Client
 	tg   *telegram.Client // nil if ROUTER_PUBLIC_URL not set
 	cfg  Config
+
+	wakeJobs       wakeJobMemory // async wake jobs when rdb is nil
+	rollouts       rolloutMemory // the image rollout when rdb is nil
+	broadcasts     broadcastMemory
+	killSwitch     killSwitchMemory
+	webhookLimiter *rate.Limiter
 }
 
 func New(reg registry.Client, k8s *k8sclient.Client, locker lock.Locker, rdb *redis.Client, tg *telegram.Client, cfg Config) *Handler {
@@ -44,27 +165,159 @@ func New(reg registry.Client, k8s *k8sclient.Client, locker lock.Locker, rdb *re
 	if cfg.PodReadyWait == 0 {
 		cfg.PodReadyWait = 210 * time.Second
 	}
-	return &Handler{reg: reg, k8s: k8s, lock: locker, rdb: rdb, tg: tg, cfg: cfg}
+	if cfg.DefaultChannel == "" {
+		cfg.DefaultChannel = "stable"
+	}
+	if cfg.RestartDrain == 0 {
+		cfg.RestartDrain = 5 * time.Second
+	}
+	if cfg.WakeHistory == 0 {
+		cfg.WakeHistory = 20
+	}
+	if cfg.EventHistory == 0 {
+		cfg.EventHistory = 50
+	}
+	if cfg.SnapshotKeep == 0 {
+		cfg.SnapshotKeep = 10
+	}
+	if cfg.WakeFailureLimit == 0 {
+		cfg.WakeFailureLimit = 5
+	}
+	if cfg.WebhookRate == 0 {
+		cfg.WebhookRate = 1
+	}
+	if cfg.WebhookBurst == 0 {
+		cfg.WebhookBurst = 5
+	}
+	if cfg.RolloutMaxUnavailable == 0 {
+		cfg.RolloutMaxUnavailable = 1
+	}
+	if cfg.WatchInterval == 0 {
+		cfg.WatchInterval = 2 * time.Second
+	}
+	if cfg.BotTokenSecretPrefix == "" {
+		cfg.BotTokenSecretPrefix = "agentic-tenancy/bot-token/"
+	}
+	return &Handler{reg: reg, k8s: k8s, lock: locker, rdb: rdb, tg: tg, cfg: cfg,
+		webhookLimiter: rate.NewLimiter(rate.Limit(cfg.WebhookRate), cfg.WebhookBurst)}
+}
+
+// redisKey builds a router cache key in this handler's environment.
+func (h *Handler) redisKey(prefix, id string) string {
+	return h.cfg.Environment.RedisPrefix + prefix + id
 }
 
-// Router returns the chi router with all routes registered
+// Router returns the chi router with all routes registered: the management
+// API plus the internal routes only in-cluster components call. When the
+// orchestrator splits its listeners it serves the internal port, and
+// PublicRouter the public one.
 func (h *Handler) Router() http.Handler {
+	r := newMux()
+	r.Get("/healthz", h.Healthz)
+	r.Get("/openapi.json", h.GetOpenAPI)
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireAPIKey)
+		h.managementRoutes(r)
+		h.internalRoutes(r)
+	})
+	h.kvRoutes(r)
+	return r
+}
+
+// PublicRouter serves the management API alone, for a listener that may be
+// exposed through an ingress. Bot tokens, wakes, exec sessions and the other
+// internal routes are left to Router on the internal listener.
+func (h *Handler) PublicRouter() http.Handler {
+	r := newMux()
+	r.Get("/healthz", h.Healthz)
+	r.Get("/openapi.json", h.GetOpenAPI)
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireAPIKey)
+		h.managementRoutes(r)
+	})
+	return r
+}
+
+func newMux() *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
+	return r
+}
 
-	r.Get("/healthz", h.Healthz)
+// managementRoutes are the operator API: tenant CRUD, images, rollouts and
+// the kill switch. They need an API key (if configured).
+func (h *Handler) managementRoutes(r chi.Router) {
 	r.Post("/tenants", h.CreateTenant)
+	r.Post("/tenants/bulk", h.CreateTenants)
+	r.Post("/tenants/import", h.ImportTenant)
 	r.Get("/tenants", h.ListTenants)
+	r.Get("/tenants/watch", h.WatchTenants)
 	r.Get("/tenants/{tenantID}", h.GetTenant)
-	r.Get("/tenants/{tenantID}/bot_token", h.GetBotToken)
+	r.Get("/tenants/{tenantID}/webhook", h.GetWebhook)
+	r.Delete("/tenants/{tenantID}/webhook", h.DeleteWebhook)
+	r.Get("/tenants/{tenantID}/link", h.GetLink)
+	r.Get("/tenants/{tenantID}/link/qr", h.GetLinkQR)
 	r.Patch("/tenants/{tenantID}", h.UpdateTenant)
 	r.Delete("/tenants/{tenantID}", h.DeleteTenant)
+	r.Post("/tenants/{tenantID}/restore", h.RestoreTenant)
+	r.Get("/tenants/{tenantID}/usage", h.GetUsage)
+	r.Get("/tenants/{tenantID}/wakes", h.ListWakes)
+	r.Get("/tenants/{tenantID}/events", h.ListEvents)
+	r.Get("/tenants/{tenantID}/state", h.GetState)
+	r.Get("/tenants/{tenantID}/export", h.ExportTenant)
+	r.Post("/tenants/{tenantID}/snapshots", h.CreateSnapshot)
+	r.Get("/tenants/{tenantID}/snapshots", h.ListSnapshots)
+	r.Post("/tenants/{tenantID}/snapshots/{snapshotID}/restore", h.RestoreSnapshot)
+	r.Delete("/tenants/{tenantID}/snapshots/{snapshotID}", h.DeleteSnapshot)
+	r.Post("/tenants/{tenantID}/status-link", h.CreateStatusLink)
+	r.Post("/tenants/{tenantID}/chat-link", h.CreateChatLink)
+	r.Post("/tenants/{tenantID}/restart", h.RestartTenant)
+	r.Post("/tenants/{tenantID}/sleep", h.SleepTenant)
+	r.Post("/tenants/{tenantID}/suspend", h.SuspendTenant)
+	r.Post("/tenants/{tenantID}/resume", h.ResumeTenant)
+	r.Post("/tenants/{tenantID}/reset", h.ResetTenant)
+	r.Get("/lookup/chat/{chatID}", h.LookupChat)
+	r.Post("/images", h.PutImage)
+	r.Get("/images", h.ListImages)
+	r.Delete("/images/{alias}", h.DeleteImage)
+	r.Post("/admin/rollout", h.StartRollout)
+	r.Get("/admin/rollout", h.GetRollout)
+	r.Post("/admin/killswitch", h.SetKillSwitch)
+	r.Get("/admin/killswitch", h.GetKillSwitch)
+	r.Post("/admin/broadcast", h.StartBroadcast)
+	r.Get("/admin/broadcast", h.GetBroadcast)
+}
+
+// internalRoutes are called by the router alone: they hand out bot tokens
+// and Slack secrets, start pods and record activity. Logs, exec sessions and
+// test messages reach into a tenant's agent, so ztm gets them here as well.
+// They need an API key (if configured) too.
+func (h *Handler) internalRoutes(r chi.Router) {
+	r.Get("/tenants/{tenantID}/bot_token", h.GetBotToken)
+	r.Get("/tenants/{tenantID}/slack", h.GetSlack)
+	r.Put("/tenants/{tenantID}/webhook_secret", h.PutWebhookSecret)
 	r.Put("/tenants/{tenantID}/activity", h.UpdateActivity)
+	r.Post("/tenants/{tenantID}/outputs", h.SaveOutput)
+	r.Get("/tenants/{tenantID}/public-status", h.GetPublicStatus)
 	r.Post("/wake/{tenantID}", h.Wake)
+	r.Get("/wake-jobs/{jobID}", h.GetWakeJob)
+	r.Get("/tenants/{tenantID}/logs", h.GetLogs)
+	r.Post("/tenants/{tenantID}/exec", h.ExecTenant)
+	r.Post("/tenants/{tenantID}/test-message", h.TestMessage)
+}
 
-	return r
+// kvRoutes are tenants' key-value stores, also open to each tenant's own
+// pod, so they are internal too
+func (h *Handler) kvRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireKVAccess)
+		r.Get("/tenants/{tenantID}/kv", h.ListKV)
+		r.Get("/tenants/{tenantID}/kv/{key}", h.GetKV)
+		r.Put("/tenants/{tenantID}/kv/{key}", h.PutKV)
+		r.Delete("/tenants/{tenantID}/kv/{key}", h.DeleteKV)
+	})
 }
 
 // Healthz returns 200 OK
@@ -73,55 +326,169 @@ func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok"))
 }
 
+// createTenantRequest is the body of POST /tenants, and one tenant of
+// POST /tenants/bulk
+type createTenantRequest struct {
+	TenantID     string                     `json:"tenant_id"`
+	IdleTimeoutS int64                      `json:"idle_timeout_s"`
+	BotToken     string                     `json:"bot_token"`
+	Tier         string                     `json:"tier"`
+	Image        string                     `json:"image"`
+	DNS          *registry.DNSConfig        `json:"dns"`
+	KeepWarm     bool                       `json:"keep_warm"`
+	LogForward   *registry.LogForwardConfig `json:"log_forward"`
+	HomeRegion   string                     `json:"home_region"`
+	// AllowedUpdates are the Telegram update types to subscribe the bot to
+	AllowedUpdates []string              `json:"allowed_updates"`
+	Slack          *registry.SlackConfig `json:"slack"`
+	Locale         string                `json:"locale"`
+	Timezone       string                `json:"timezone"`
+}
+
+// createError is why a tenant wasn't created, with the status to answer
+type createError struct {
+	status int
+	msg    string
+}
+
+func (e *createError) Error() string { return e.msg }
+
 // CreateTenant creates a new tenant record
 func (h *Handler) CreateTenant(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		TenantID     string `json:"tenant_id"`
-		IdleTimeoutS int64  `json:"idle_timeout_s"`
-		BotToken     string `json:"bot_token"`
-	}
+	var req createTenantRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
-	if req.TenantID == "" {
-		http.Error(w, "tenant_id required", http.StatusBadRequest)
+	rec, err := h.createTenant(r.Context(), req)
+	if err != nil {
+		var ce *createError
+		errors.As(err, &ce)
+		http.Error(w, ce.msg, ce.status)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rec)
+}
+
+// createTenant validates and creates one tenant, returning its redacted
+// record or a *createError
+func (h *Handler) createTenant(ctx context.Context, req createTenantRequest) (*registry.TenantRecord, error) {
+	badRequest := func(msg string) error { return &createError{http.StatusBadRequest, msg} }
+	if req.TenantID == "" {
+		return nil, badRequest("tenant_id required")
+	}
 	if req.IdleTimeoutS == 0 {
 		req.IdleTimeoutS = 300
 	}
+	if req.Tier == "" {
+		req.Tier = registry.DefaultTier
+	}
+	if req.HomeRegion == "" {
+		req.HomeRegion = h.cfg.Region
+	}
+	if req.Image != "" && !imageRefRe.MatchString(req.Image) {
+		return nil, badRequest("image must be an alias, tag or image reference")
+	}
+	dns, err := normalizeDNS(req.DNS)
+	if err != nil {
+		return nil, badRequest(err.Error())
+	}
+	logForward, err := normalizeLogForward(req.LogForward)
+	if err != nil {
+		return nil, badRequest(err.Error())
+	}
+	if err := telegram.ValidateAllowedUpdates(req.AllowedUpdates); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	slack, err := normalizeSlack(req.Slack)
+	if err != nil {
+		return nil, badRequest(err.Error())
+	}
+	if err := validateLocale(req.Locale, req.Timezone); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	// The record, its bot token secret, its role and the webhook are created
+	// together or not at all
+	s := saga.New(sagaCreateTenant, req.TenantID)
 	rec := &registry.TenantRecord{
-		TenantID:     req.TenantID,
-		Status:       registry.StatusIdle,
-		Namespace:    h.cfg.Namespace,
-		S3Prefix:     fmt.Sprintf("tenants/%s/", req.TenantID),
-		BotToken:     req.BotToken,
-		CreatedAt:    time.Now().UTC(),
-		LastActiveAt: time.Now().UTC(),
-		IdleTimeoutS: req.IdleTimeoutS,
-	}
-	if err := h.reg.CreateTenant(r.Context(), rec); err != nil {
-		slog.Error("create tenant failed", "tenant", req.TenantID, "err", err)
-		http.Error(w, "conflict", http.StatusConflict)
-		return
+		TenantID:       req.TenantID,
+		Status:         registry.StatusIdle,
+		Namespace:      h.cfg.Namespace,
+		S3Prefix:       h.cfg.Environment.S3Prefix(req.TenantID),
+		BotUsername:    h.lookupBotUsername(ctx, req.TenantID, req.BotToken),
+		CreatedAt:      s.StartedAt,
+		LastActiveAt:   s.StartedAt,
+		IdleTimeoutS:   req.IdleTimeoutS,
+		Tier:           req.Tier,
+		Image:          req.Image,
+		DNS:            dns,
+		KeepWarm:       req.KeepWarm,
+		LogForward:     logForward,
+		HomeRegion:     req.HomeRegion,
+		AllowedUpdates: req.AllowedUpdates,
+		Slack:          slack,
+		Locale:         req.Locale,
+		Timezone:       req.Timezone,
 	}
-	// Auto-register Telegram webhook if router URL is configured and bot token provided
-	if h.tg != nil && req.BotToken != "" {
-		if err := h.tg.RegisterWebhook(r.Context(), req.BotToken, req.TenantID); err != nil {
-			slog.Warn("webhook registration failed (tenant created, fix manually)", "tenant", req.TenantID, "err", err)
-		} else {
-			slog.Info("webhook registered", "tenant", req.TenantID)
+	if req.BotToken != "" && h.cfg.Secrets != nil {
+		rec.BotTokenRef = h.botTokenSecretName(req.TenantID)
+	} else {
+		rec.BotToken = req.BotToken
+	}
+	if err := saga.Run(ctx, h.reg, s, h.createTenantSteps(s, rec, req.BotToken)); err != nil {
+		slog.Error("create tenant failed", "tenant", req.TenantID, "err", err)
+		var stepErr *saga.StepError
+		switch {
+		case errors.As(err, &stepErr) && stepErr.Step == stepRegistry:
+			return nil, &createError{http.StatusConflict, "conflict"}
+		case errors.As(err, &stepErr) && stepErr.Step == stepSecret:
+			return nil, &createError{http.StatusInternalServerError, "storing bot token failed"}
+		case errors.As(err, &stepErr) && stepErr.Step == stepIdentity:
+			return nil, &createError{http.StatusInternalServerError, "creating tenant role failed"}
+		case errors.As(err, &stepErr) && stepErr.Step == stepWebhook:
+			return nil, &createError{http.StatusBadGateway, "telegram webhook registration failed"}
+		default:
+			return nil, &createError{http.StatusInternalServerError, "internal error"}
 		}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	rec.BotToken = "" // redact in response
-	json.NewEncoder(w).Encode(rec)
+	h.cfg.Events.Publish(events.TenantCreated, h.cfg.Environment.Name, rec.TenantID, map[string]any{"tier": rec.Tier})
+	redact(rec)
+	return rec, nil
 }
 
-// ListTenants returns all tenant records (BotToken redacted)
+// redact clears credentials from a record before it is returned. A
+// configured Slack app shows up as an empty slack object.
+func redact(rec *registry.TenantRecord) {
+	rec.BotToken = ""
+	rec.WebhookSecret = ""
+	if rec.Slack != nil {
+		rec.Slack = &registry.SlackConfig{}
+	}
+}
+
+// ListTenants returns all tenant records (BotToken redacted).
+// Optional query params: sort=tenant_id|last_active_at|created_at|status
+// (default tenant_id) and order=asc|desc (default asc). There is no paging:
+// every call scans the whole registry and sorts in memory, so its cost grows
+// with the number of tenants, whatever the sort.
 func (h *Handler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = "tenant_id"
+	}
+	less, ok := tenantSorters[sortBy]
+	if !ok {
+		http.Error(w, "sort must be one of tenant_id, last_active_at, created_at, status", http.StatusBadRequest)
+		return
+	}
+	order := r.URL.Query().Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		http.Error(w, "order must be asc or desc", http.StatusBadRequest)
+		return
+	}
+
 	records, err := h.reg.ListAll(r.Context())
 	if err != nil {
 		slog.Error("list tenants failed", "err", err)
@@ -131,14 +498,43 @@ func (h *Handler) ListTenants(w http.ResponseWriter, r *http.Request) {
 	if records == nil {
 		records = []*registry.TenantRecord{}
 	}
-	// Redact BotToken from all records
 	for _, rec := range records {
-		rec.BotToken = ""
+		redact(rec)
 	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if order == "desc" {
+			return less(records[j], records[i])
+		}
+		return less(records[i], records[j])
+	})
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(records)
 }
 
+// tenantSorters maps ListTenants sort keys to comparators. Ties fall back to
+// tenant_id so output is deterministic.
+var tenantSorters = map[string]func(a, b *registry.TenantRecord) bool{
+	"tenant_id": func(a, b *registry.TenantRecord) bool { return a.TenantID < b.TenantID },
+	"last_active_at": func(a, b *registry.TenantRecord) bool {
+		if !a.LastActiveAt.Equal(b.LastActiveAt) {
+			return a.LastActiveAt.Before(b.LastActiveAt)
+		}
+		return a.TenantID < b.TenantID
+	},
+	"created_at": func(a, b *registry.TenantRecord) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.TenantID < b.TenantID
+	},
+	"status": func(a, b *registry.TenantRecord) bool {
+		if a.Status != b.Status {
+			return a.Status < b.Status
+		}
+		return a.TenantID < b.TenantID
+	},
+}
+
 // GetTenant returns a tenant record (BotToken redacted)
 func (h *Handler) GetTenant(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
@@ -151,12 +547,13 @@ func (h *Handler) GetTenant(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
-	rec.BotToken = ""
+	redact(rec)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rec)
 }
 
-// GetBotToken returns only the bot_token for a tenant (internal use by Router)
+// GetBotToken returns the bot_token and webhook secret for a tenant
+// (internal use by Router)
 func (h *Handler) GetBotToken(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
 	rec, err := h.reg.GetTenant(r.Context(), tenantID)
@@ -168,35 +565,131 @@ func (h *Handler) GetBotToken(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
+	token, err := h.botToken(r.Context(), rec)
+	if err != nil {
+		slog.Error("read bot_token failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"BotToken": rec.BotToken})
+	json.NewEncoder(w).Encode(map[string]string{"BotToken": token, "WebhookSecret": rec.WebhookSecret})
 }
 
-// UpdateTenant updates mutable tenant fields (currently: bot_token, idle_timeout_s)
+// UpdateTenant updates mutable tenant fields (currently: bot_token, idle_timeout_s, tier, image, dns, keep_warm, log_forward, allowed_updates, slack).
+// A tier change is tracked as a resize; with resize set it is applied to a
+// running keep-warm tenant right away (see beginResize). An empty image unpins the tenant so it follows the default channel; an
+// empty dns, log_forward or slack object removes the tenant's override, and
+// an empty allowed_updates list restores the default subscription. A zero
+// max_message_age_s restores the router's default, and -1 turns it off;
+// likewise max_reply_bytes, where -1 lets replies of any length through.
+// A request with any invalid field changes nothing.
 func (h *Handler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
 	var req struct {
-		BotToken     *string `json:"bot_token"`
-		IdleTimeoutS *int64  `json:"idle_timeout_s"`
+		BotToken        *string                    `json:"bot_token"`
+		IdleTimeoutS    *int64                     `json:"idle_timeout_s"`
+		Tier            *string                    `json:"tier"`
+		Image           *string                    `json:"image"`
+		DNS             *registry.DNSConfig        `json:"dns"`
+		KeepWarm        *bool                      `json:"keep_warm"`
+		NoAnnouncements *bool                      `json:"no_announcements"`
+		WeeklyDigest    *bool                      `json:"weekly_digest"`
+		LogForward      *registry.LogForwardConfig `json:"log_forward"`
+		AllowedUpdates  *[]string                  `json:"allowed_updates"`
+		Slack           *registry.SlackConfig      `json:"slack"`
+		Locale          *string                    `json:"locale"`
+		Timezone        *string                    `json:"timezone"`
+		Resize          bool                       `json:"resize"`
+		Disabled        *bool                      `json:"disabled"`
+		MaxMessageAgeS  *int64                     `json:"max_message_age_s"`
+		MaxReplyBytes   *int64                     `json:"max_reply_bytes"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
+	// Validate every field before storing any, so a bad one leaves the
+	// tenant as it was
+	if req.Tier != nil && *req.Tier == "" {
+		http.Error(w, "tier must not be empty", http.StatusBadRequest)
+		return
+	}
+	if req.Tier == nil && req.Resize {
+		http.Error(w, "resize requires a tier change", http.StatusBadRequest)
+		return
+	}
+	if req.Image != nil && *req.Image != "" && !imageRefRe.MatchString(*req.Image) {
+		http.Error(w, "image must be an alias, tag or image reference", http.StatusBadRequest)
+		return
+	}
+	var dns *registry.DNSConfig
+	if req.DNS != nil {
+		var err error
+		if dns, err = normalizeDNS(req.DNS); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var logForward *registry.LogForwardConfig
+	if req.LogForward != nil {
+		var err error
+		if logForward, err = normalizeLogForward(req.LogForward); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.AllowedUpdates != nil {
+		if err := telegram.ValidateAllowedUpdates(*req.AllowedUpdates); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var slack *registry.SlackConfig
+	if req.Slack != nil {
+		var err error
+		if slack, err = normalizeSlack(req.Slack); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Locale != nil {
+		if err := validateLocale(*req.Locale, ""); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Timezone != nil {
+		if err := validateLocale("", *req.Timezone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.MaxMessageAgeS != nil && *req.MaxMessageAgeS < -1 {
+		http.Error(w, "max_message_age_s must be -1 (off), 0 (router default) or a number of seconds", http.StatusBadRequest)
+		return
+	}
+	if req.MaxReplyBytes != nil && *req.MaxReplyBytes < -1 {
+		http.Error(w, "max_reply_bytes must be -1 (any length), 0 (router default) or a number of bytes", http.StatusBadRequest)
+		return
+	}
+
 	if req.BotToken != nil {
-		if err := h.reg.UpdateBotToken(r.Context(), tenantID, *req.BotToken); err != nil {
+		if err := h.setBotToken(r.Context(), tenantID, *req.BotToken); err != nil {
 			slog.Error("update bot_token failed", "tenant", tenantID, "err", err)
 			http.Error(w, "not found or internal error", http.StatusNotFound)
 			return
 		}
-		// Re-register webhook with new token
-		if h.tg != nil && *req.BotToken != "" {
-			if err := h.tg.RegisterWebhook(r.Context(), *req.BotToken, tenantID); err != nil {
-				slog.Warn("webhook re-registration failed (token updated, fix manually)", "tenant", tenantID, "err", err)
-			} else {
-				slog.Info("webhook re-registered", "tenant", tenantID)
+		// Routers cache tokens in Redis — drop the old one so replies use the new bot
+		if h.rdb != nil {
+			if err := h.rdb.Del(r.Context(), h.redisKey(contract.BotTokenPrefix, tenantID)).Err(); err != nil {
+				slog.Warn("update bot_token: failed to invalidate router token cache", "tenant", tenantID, "err", err)
 			}
 		}
+		// The stored username belongs to the old bot — refresh or clear it
+		username := h.lookupBotUsername(r.Context(), tenantID, *req.BotToken)
+		if err := h.reg.UpdateBotUsername(r.Context(), tenantID, username); err != nil {
+			slog.Warn("update bot_username failed", "tenant", tenantID, "err", err)
+		}
 	}
 	if req.IdleTimeoutS != nil {
 		if err := h.reg.UpdateIdleTimeout(r.Context(), tenantID, *req.IdleTimeoutS); err != nil {
@@ -205,104 +698,425 @@ func (h *Handler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	// resizeFrom is the tenant before a tier change, resized once the other
+	// fields of the request are stored so one replacement applies them all
+	var resizeFrom *registry.TenantRecord
+	if req.Tier != nil {
+		before, err := h.reg.GetTenant(r.Context(), tenantID)
+		if err != nil || before == nil {
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		if err := h.reg.UpdateTier(r.Context(), tenantID, *req.Tier); err != nil {
+			slog.Error("update tier failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		if before.Tier != *req.Tier {
+			resizeFrom = before
+		}
+	}
+	if req.Image != nil {
+		if err := h.reg.UpdateImage(r.Context(), tenantID, *req.Image); err != nil {
+			slog.Error("update image failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.DNS != nil {
+		if err := h.reg.UpdateDNS(r.Context(), tenantID, dns); err != nil {
+			slog.Error("update dns failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.KeepWarm != nil {
+		if err := h.reg.UpdateKeepWarm(r.Context(), tenantID, *req.KeepWarm); err != nil {
+			slog.Error("update keep_warm failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.NoAnnouncements != nil {
+		if err := h.reg.UpdateNoAnnouncements(r.Context(), tenantID, *req.NoAnnouncements); err != nil {
+			slog.Error("update no_announcements failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.WeeklyDigest != nil {
+		if err := h.reg.UpdateWeeklyDigest(r.Context(), tenantID, *req.WeeklyDigest); err != nil {
+			slog.Error("update weekly_digest failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.LogForward != nil {
+		if err := h.reg.UpdateLogForward(r.Context(), tenantID, logForward); err != nil {
+			slog.Error("update log_forward failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.AllowedUpdates != nil {
+		if err := h.reg.UpdateAllowedUpdates(r.Context(), tenantID, *req.AllowedUpdates); err != nil {
+			slog.Error("update allowed_updates failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.Slack != nil {
+		if err := h.reg.UpdateSlack(r.Context(), tenantID, slack); err != nil {
+			slog.Error("update slack failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		// Routers cache Slack credentials like bot tokens
+		if h.rdb != nil {
+			if err := h.rdb.Del(r.Context(), h.redisKey(contract.SlackPrefix, tenantID)).Err(); err != nil {
+				slog.Warn("update slack: failed to invalidate router cache", "tenant", tenantID, "err", err)
+			}
+		}
+	}
+	if req.Locale != nil {
+		if err := h.reg.UpdateLocale(r.Context(), tenantID, *req.Locale); err != nil {
+			slog.Error("update locale failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		// Routers cache the locale for their own messages
+		if h.rdb != nil {
+			if err := h.rdb.Del(r.Context(), h.redisKey(contract.LocalePrefix, tenantID)).Err(); err != nil {
+				slog.Warn("update locale: failed to invalidate router cache", "tenant", tenantID, "err", err)
+			}
+		}
+	}
+	if req.Timezone != nil {
+		if err := h.reg.UpdateTimezone(r.Context(), tenantID, *req.Timezone); err != nil {
+			slog.Error("update timezone failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if req.MaxMessageAgeS != nil {
+		if err := h.reg.UpdateMaxMessageAge(r.Context(), tenantID, *req.MaxMessageAgeS); err != nil {
+			slog.Error("update max_message_age_s failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		// Routers cache the tenant's maximum update age like its locale
+		if h.rdb != nil {
+			if err := h.rdb.Del(r.Context(), h.redisKey(contract.MaxAgePrefix, tenantID)).Err(); err != nil {
+				slog.Warn("update max_message_age_s: failed to invalidate router cache", "tenant", tenantID, "err", err)
+			}
+		}
+	}
+	if req.MaxReplyBytes != nil {
+		if err := h.reg.UpdateMaxReplyBytes(r.Context(), tenantID, *req.MaxReplyBytes); err != nil {
+			slog.Error("update max_reply_bytes failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+		if h.rdb != nil {
+			if err := h.rdb.Del(r.Context(), h.redisKey(contract.MaxReplyPrefix, tenantID)).Err(); err != nil {
+				slog.Warn("update max_reply_bytes: failed to invalidate router cache", "tenant", tenantID, "err", err)
+			}
+		}
+	}
+	if req.Disabled != nil {
+		if err := h.setTenantDisabled(r, tenantID, *req.Disabled); err != nil {
+			slog.Error("update disabled failed", "tenant", tenantID, "err", err)
+			http.Error(w, "not found or internal error", http.StatusNotFound)
+			return
+		}
+	}
+	if resizeFrom != nil {
+		h.beginResize(r.Context(), resizeFrom, *req.Tier, req.Resize)
+	}
 	rec, err := h.reg.GetTenant(r.Context(), tenantID)
 	if err != nil || rec == nil {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
-	rec.BotToken = "" // redact in response
+	// Re-register the webhook for a new token and/or new update types
+	if h.tg != nil && rec.HasBot() && (req.BotToken != nil || req.AllowedUpdates != nil) {
+		if err := h.registerOrQueueWebhook(r.Context(), rec); err != nil {
+			slog.Warn("webhook re-registration failed (tenant updated, fix manually)", "tenant", tenantID, "err", err)
+		}
+	}
+	redact(rec)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rec)
 }
 
-// DeleteTenant removes a tenant and all its resources
+// DeleteTenant stops a tenant and removes its resources, keeping its record,
+// S3 state and bot token for POST /tenants/{id}/restore until the purger
+// removes them DeleteRetention later. ?purge=true, or a zero retention,
+// purges them right away, including from an already deleted tenant. With
+// ?dry_run=true it only reports what would be removed, along with a confirm
+// token.
 func (h *Handler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
-	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	ctx := r.Context()
+	rec, err := h.reg.GetTenant(ctx, tenantID)
 	if err != nil {
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
+	purge := h.cfg.DeleteRetention <= 0 || r.URL.Query().Get("purge") == "true"
+	if r.URL.Query().Get("dry_run") == "true" {
+		h.planDeleteTenant(w, r, rec, purge)
+		return
+	}
+	if !h.confirmed(r, "delete-tenant", tenantID) {
+		http.Error(w, "confirmation required: send "+ConfirmHeader+": "+tenantID+
+			" or the confirm_token from ?dry_run=true", http.StatusPreconditionRequired)
+		return
+	}
 	if rec == nil {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	if rec.PodName != "" && h.k8s != nil {
-		if err := h.k8s.DeletePod(r.Context(), rec.PodName, rec.Namespace, 30); err != nil {
-			slog.Error("delete pod failed", "tenant", tenantID, "err", err)
+	if rec.Status != registry.StatusTerminated {
+		// Mark it deleted first, so a wake that read the record before
+		// can't mark it running again
+		now := time.Now().UTC()
+		purgeAt := now.Add(h.cfg.DeleteRetention)
+		if purge {
+			purgeAt = now
 		}
+		if err := h.reg.TerminateTenant(ctx, tenantID, now, purgeAt); err != nil {
+			slog.Error("delete tenant: mark deleted failed", "tenant", tenantID, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		h.teardownTenant(ctx, rec)
+		slog.Info("tenant deleted", "tenant", tenantID, "purge_at", purgeAt)
+		h.cfg.Events.Publish(events.TenantDeleted, h.cfg.Environment.Name, tenantID, nil)
 	}
-	if h.k8s != nil {
-		if err := h.k8s.DeletePVC(r.Context(), tenantID, rec.Namespace); err != nil {
-			slog.Error("delete PVC failed", "tenant", tenantID, "err", err)
+	if purge {
+		if err := h.purgeTenant(ctx, rec); err != nil {
+			slog.Error("delete tenant: purge failed", "tenant", tenantID, "err", err)
+			http.Error(w, "tenant deleted but not purged; retry with ?purge=true", http.StatusInternalServerError)
+			return
 		}
 	}
-	if err := h.reg.DeleteTenant(r.Context(), tenantID); err != nil {
-		http.Error(w, "internal error", http.StatusInternalServerError)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deletePlan is the dry-run response of DeleteTenant
+type deletePlan struct {
+	TenantID string   `json:"tenant_id"`
+	Deletes  []string `json:"deletes"`
+	// Keeps is what stays for a restore until PurgeAt
+	Keeps        []string  `json:"keeps,omitempty"`
+	PurgeAt      time.Time `json:"purge_at,omitempty"`
+	ConfirmToken string    `json:"confirm_token,omitempty"`
+	ExpiresInS   int       `json:"expires_in_s,omitempty"`
+}
+
+func (h *Handler) planDeleteTenant(w http.ResponseWriter, r *http.Request, rec *registry.TenantRecord, purge bool) {
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
-	// Clear Redis endpoint cache so Router doesn't serve stale IP
-	if h.rdb != nil {
-		cacheKey := routerEndpointCachePrefix + tenantID
-		if err := h.rdb.Del(r.Context(), cacheKey).Err(); err != nil {
-			slog.Warn("delete tenant: failed to clear Redis cache", "tenant", tenantID, "err", err)
+	plan := deletePlan{TenantID: rec.TenantID, Deletes: []string{}}
+	if rec.Status != registry.StatusTerminated {
+		if rec.PodName != "" {
+			plan.Deletes = append(plan.Deletes, "pod "+rec.Namespace+"/"+rec.PodName)
+		}
+		plan.Deletes = append(plan.Deletes, "pvc and pod bot token secret", "router caches")
+		if h.tenantIdentities() {
+			plan.Deletes = append(plan.Deletes, "service account "+rec.Namespace+"/"+contract.ServiceAccountName(rec.TenantID)+
+				" and its IAM role")
 		}
+		if h.tg != nil && rec.HasBot() {
+			plan.Deletes = append(plan.Deletes, "telegram webhook")
+		}
+	}
+	kept := []string{"registry record"}
+	if h.cfg.StateDeleter != nil && rec.S3Prefix != "" {
+		kept = append(kept, "S3 state "+rec.S3Prefix)
 	}
-	// Remove Telegram webhook
-	if h.tg != nil && rec.BotToken != "" {
-		if err := h.tg.DeleteWebhook(r.Context(), rec.BotToken); err != nil {
-			slog.Warn("delete tenant: failed to remove webhook", "tenant", tenantID, "err", err)
-		} else {
-			slog.Info("webhook deleted", "tenant", tenantID)
+	if rec.BotTokenRef != "" && h.cfg.Secrets != nil {
+		kept = append(kept, "bot token secret "+rec.BotTokenRef)
+	}
+	switch {
+	case purge:
+		plan.Deletes = append(plan.Deletes, kept...)
+	case rec.Status == registry.StatusTerminated:
+		plan.Keeps, plan.PurgeAt = kept, rec.PurgeAt
+	default:
+		plan.Keeps, plan.PurgeAt = kept, time.Now().UTC().Add(h.cfg.DeleteRetention)
+	}
+	if h.rdb != nil {
+		token, err := h.issueConfirmToken(r.Context(), "delete-tenant", rec.TenantID)
+		if err != nil {
+			slog.Error("issue confirm token", "tenant", rec.TenantID, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
 		}
+		plan.ConfirmToken = token
+		plan.ExpiresInS = int(confirmTokenTTL / time.Second)
 	}
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
 }
 
-// UpdateActivity updates last_active_at for a tenant
+// UpdateActivity updates last_active_at for a tenant and meters the message
+// the router just delivered, counting its chat among the distinct chats
 func (h *Handler) UpdateActivity(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
+	// The message's text volume and chat; routers that don't send them
+	// report none
+	var report struct {
+		CharsIn  int64  `json:"chars_in"`
+		CharsOut int64  `json:"chars_out"`
+		ChatID   string `json:"chat_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if report.CharsIn < 0 || report.CharsOut < 0 {
+		http.Error(w, "chars_in and chars_out must not be negative", http.StatusBadRequest)
+		return
+	}
 	if err := h.reg.UpdateActivity(r.Context(), tenantID); err != nil {
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
+	// The router reports activity once per message it delivers
+	now := time.Now()
+	usage := registry.MessageUsage(report.CharsIn, report.CharsOut)
+	if err := h.reg.AddUsage(r.Context(), tenantID, now, usage); err != nil {
+		slog.Warn("record message usage failed", "tenant", tenantID, "err", err)
+	}
+	if report.ChatID != "" {
+		h.countChat(r.Context(), tenantID, report.ChatID, now)
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// Wake ensures a tenant pod is running and returns its IP
+// Wake ensures a tenant pod is running and returns its IP. With ?async=true
+// it returns a wake job to poll instead of holding the connection open.
 func (h *Handler) Wake(w http.ResponseWriter, r *http.Request) {
 	tenantID := chi.URLParam(r, "tenantID")
 	ctx := r.Context()
+	if r.URL.Query().Get("async") == "true" {
+		h.wakeAsync(w, r, tenantID)
+		return
+	}
+
+	rec, err := h.wakeOrGet(ctx, tenantID)
+	if writeWakeError(w, tenantID, err) {
+		return
+	}
+
+	// idle_timeout_s lets the Router align its endpoint cache TTL with the
+	// tenant's idle timeout instead of caching a soon-to-be-dead pod IP.
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"pod_ip":         rec.PodIP,
+		"idle_timeout_s": rec.IdleTimeoutS,
+	})
+}
 
-	podIP, err := h.wakeOrGet(ctx, tenantID)
+// writeWakeError answers a failed wake with the status the router maps to
+// its reply (421, 507, 429, 423, 409, 503), reporting whether err was one
+func writeWakeError(w http.ResponseWriter, tenantID string, err error) bool {
+	var misdirected *MisdirectedError
+	if errors.As(err, &misdirected) {
+		writeMisdirected(w, misdirected)
+		return true
+	}
+	var overQuota *QuotaExceededError
+	if errors.As(err, &overQuota) {
+		slog.Warn("wake refused: state quota exceeded", "tenant", tenantID, "bytes", overQuota.Bytes, "quota_bytes", overQuota.QuotaBytes)
+		writeQuotaExceeded(w, overQuota)
+		return true
+	}
+	var overRun *RunQuotaError
+	if errors.As(err, &overRun) {
+		slog.Warn("wake refused: run quota exceeded", "tenant", tenantID, "limit", overRun.Limit, "max", overRun.Max)
+		writeRunQuotaExceeded(w, overRun)
+		return true
+	}
+	if errors.Is(err, registry.ErrSuspended) {
+		slog.Info("wake refused: tenant suspended", "tenant", tenantID)
+		writeSuspended(w)
+		return true
+	}
+	if errors.Is(err, registry.ErrTerminated) {
+		slog.Info("wake refused: tenant deleted", "tenant", tenantID)
+		writeTerminated(w)
+		return true
+	}
+	var disabled *DisabledError
+	if errors.As(err, &disabled) {
+		slog.Info("wake refused: kill switch", "tenant", tenantID, "global", disabled.Global)
+		writeDisabled(w, disabled)
+		return true
+	}
+	var failed *FailedError
+	if errors.As(err, &failed) {
+		slog.Info("wake refused: tenant failed", "tenant", tenantID, "last_error", failed.LastError)
+		writeFailed(w, failed)
+		return true
+	}
 	if err != nil {
 		slog.Error("wake failed", "tenant", tenantID, "err", err)
 		http.Error(w, "failed to wake tenant", http.StatusServiceUnavailable)
-		return
+		return true
 	}
+	return false
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"pod_ip": podIP})
+// wakeOrGet returns the running tenant record, starting the pod if needed
+func (h *Handler) wakeOrGet(ctx context.Context, tenantID string) (*registry.TenantRecord, error) {
+	return h.wake(ctx, tenantID, nil)
 }
 
-// wakeOrGet returns the pod IP, starting the pod if needed
-func (h *Handler) wakeOrGet(ctx context.Context, tenantID string) (string, error) {
+// wake is wakeOrGet, calling onRestore (if set) while a pod it started
+// restores the agent's state
+func (h *Handler) wake(ctx context.Context, tenantID string, onRestore func(RestoreStatus)) (*registry.TenantRecord, error) {
 	if h.k8s == nil {
-		return "", fmt.Errorf("k8s not available in local mode")
+		return nil, fmt.Errorf("k8s not available in local mode")
 	}
 	// Fast path: already running
 	rec, err := h.reg.GetTenant(ctx, tenantID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if err := h.checkHome(rec); err != nil {
+		return nil, err
+	}
+	if rec != nil && rec.Status == registry.StatusSuspended {
+		return nil, registry.ErrSuspended
+	}
+	if rec != nil && rec.Status == registry.StatusTerminated {
+		return nil, registry.ErrTerminated
+	}
+	if err := checkFailed(rec); err != nil {
+		return nil, err
+	}
+	if err := h.checkDisabled(ctx, rec); err != nil {
+		return nil, err
 	}
 	if rec != nil && rec.Status == registry.StatusRunning && rec.PodIP != "" {
-		return rec.PodIP, nil
+		return rec, nil
+	}
+	if err := h.checkStateQuota(rec); err != nil {
+		return nil, err
+	}
+	if err := h.checkRunQuota(ctx, rec, tenantID); err != nil {
+		return nil, err
 	}
 
 	// Slow path: try to acquire wake lock
 	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, h.cfg.WakeLockTTL)
 	if err != nil {
-		return "", fmt.Errorf("acquire lock: %w", err)
+		return nil, fmt.Errorf("acquire lock: %w", err)
 	}
 
 	if !acquired {
@@ -318,63 +1132,248 @@ func (h *Handler) wakeOrGet(ctx context.Context, tenantID string) (string, error
 			TenantID:     tenantID,
 			Status:       registry.StatusProvisioning,
 			Namespace:    h.cfg.Namespace,
-			S3Prefix:     fmt.Sprintf("tenants/%s/", tenantID),
+			S3Prefix:     h.cfg.Environment.S3Prefix(tenantID),
 			CreatedAt:    time.Now().UTC(),
 			LastActiveAt: time.Now().UTC(),
 			IdleTimeoutS: 300,
+			Tier:         registry.DefaultTier,
+			HomeRegion:   h.cfg.Region,
 		}
 		_ = h.reg.CreateTenant(ctx, rec)
 	}
 
+	attempt := &registry.WakeAttempt{StartedAt: time.Now().UTC()}
+	rec, err = h.startPod(ctx, rec, attempt, onRestore)
+	h.recordWake(ctx, tenantID, attempt, err)
+	return rec, err
+}
+
+// startPod starts the tenant's pod and marks it running once the agent has
+// restored its state. The caller holds the wake lock. attempt.Start is set
+// once the warm pool has been checked.
+func (h *Handler) startPod(ctx context.Context, rec *registry.TenantRecord, attempt *registry.WakeAttempt, onRestore func(RestoreStatus)) (*registry.TenantRecord, error) {
+	tenantID := rec.TenantID
 	ns := h.cfg.Namespace
 	if rec.Namespace != "" {
 		ns = rec.Namespace
 	}
 
-	// Ensure PVC
-	if err := h.k8s.CreatePVC(ctx, tenantID, ns); err != nil {
-		return "", fmt.Errorf("create PVC: %w", err)
+	image, err := h.resolveImage(ctx, rec.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wake phase timings, logged once the pod is ready. "ready" covers
+	// scheduling, the S3 CSI mount and container start on the node.
+	timer := newPhaseTimer()
+
+	// Ensure PV/PVC — repairs volumes deleted out-of-band before the pod mounts them
+	repaired, err := h.k8s.EnsureTenantVolume(ctx, tenantID, ns, rec.S3Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("ensure volume: %w", err)
+	}
+	if len(repaired) > 0 {
+		slog.Info("wake: provisioned tenant volume", "tenant", tenantID, "actions", repaired)
+	}
+	timer.lap("volume")
+
+	// Per-tenant identity — created here for tenants that predate it
+	serviceAccount, err := h.tenantServiceAccount(ctx, rec, ns)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check for a warm pod — if one is available, delete it and pin the
-	// tenant pod to the same node to skip Karpenter provisioning.
+	// tenant pod to the same node to skip Karpenter provisioning. The warm
+	// pool may run in its own namespace.
 	nodeName := ""
-	if warmPod, err := h.k8s.GetWarmPod(ctx, ns); err == nil && warmPod != nil {
+	warmNS := h.cfg.Environment.WarmPoolNamespace()
+	warmPod, err := h.k8s.GetWarmPod(ctx, warmNS, k8sclient.WarmClaim{TenantID: tenantID, Tier: rec.Tier, Namespace: ns})
+	if err == nil && warmPod != nil {
 		nodeName = warmPod.Spec.NodeName
 		slog.Info("warm pool hit: reusing node", "tenant", tenantID, "node", nodeName, "warm_pod", warmPod.Name)
 		// Delete the warm pod to free resources before creating tenant pod
-		_ = h.k8s.DeletePod(ctx, warmPod.Name, ns, 0)
+		_ = h.k8s.DeletePod(ctx, warmPod.Name, warmPod.Namespace, h.k8s.GracePeriod(k8sclient.OpWarmClaim, rec.Tier))
+		attempt.Start = "warm"
 	} else {
-		slog.Info("warm pool miss: cold start", "tenant", tenantID)
+		attrs := []any{"tenant", tenantID}
+		if err != nil {
+			attrs = append(attrs, "reason", err)
+		}
+		slog.Info("warm pool miss: cold start", attrs...)
+		attempt.Start = "cold"
 	}
+	timer.lap("claim")
 
 	// Create pod (pinned to warm node if available)
-	pod, err := h.k8s.CreateTenantPod(ctx, tenantID, ns, k8sclient.PVCName(tenantID), rec.BotToken, nodeName)
+	token, err := h.botToken(ctx, rec)
 	if err != nil {
-		return "", fmt.Errorf("create pod: %w", err)
+		return nil, err
 	}
+	pod, err := h.k8s.CreateTenantPod(ctx, tenantID, ns, contract.PVCName(tenantID), token, k8sclient.TenantPodOptions{
+		NodeName:       nodeName,
+		Tier:           rec.Tier,
+		Image:          image,
+		DNS:            (*k8sclient.DNSSettings)(rec.DNS),
+		StateReadOnly:  h.stateReadOnly(rec),
+		KV:             h.kvAccess(tenantID),
+		ServiceAccount: serviceAccount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create pod: %w", err)
+	}
+	timer.lap("create")
 
-	// Wait ready
+	// Wait ready: the pod running, then the agent's state restored
+	readyBy := time.Now().Add(h.cfg.PodReadyWait)
 	podIP, err := h.k8s.WaitPodReady(ctx, tenantID, ns, h.cfg.PodReadyWait)
 	if err != nil {
-		return "", fmt.Errorf("wait pod ready: %w", err)
+		h.recordRestoreFailure(ctx, tenantID, err)
+		return nil, fmt.Errorf("wait pod ready: %w", err)
 	}
+	timer.lap("ready")
+	h.waitAgentRestored(ctx, tenantID, podIP, readyBy, onRestore)
+	timer.lap("restore")
+	slog.Info("wake: phases", append([]any{"tenant", tenantID, "warm", nodeName != "",
+		"warm_staging", h.k8s.WarmStagingEnabled()}, timer.attrs()...)...)
 
-	// Update registry
+	// Update registry. A tenant suspended or deleted while its pod started
+	// loses the pod.
 	if err := h.reg.UpdateStatus(ctx, tenantID, registry.StatusRunning, pod.Name, podIP); err != nil {
-		return "", fmt.Errorf("update status: %w", err)
+		if errors.Is(err, registry.ErrSuspended) || errors.Is(err, registry.ErrTerminated) {
+			_ = h.k8s.DeletePod(ctx, pod.Name, ns, h.k8s.GracePeriod(k8sclient.OpIdle, rec.Tier))
+		}
+		return nil, fmt.Errorf("update status: %w", err)
+	}
+
+	rec.Status = registry.StatusRunning
+	rec.PodName = pod.Name
+	rec.PodIP = podIP
+	h.completeResize(ctx, rec)
+	return rec, nil
+}
+
+// recordWake completes attempt with its duration and outcome and appends it
+// to the tenant's wake history. It runs even if the waking request was
+// cancelled, since a failed wake is what the history is most often read for.
+func (h *Handler) recordWake(ctx context.Context, tenantID string, attempt *registry.WakeAttempt, wakeErr error) {
+	attempt.DurationMs = time.Since(attempt.StartedAt).Milliseconds()
+	attempt.Outcome = registry.WakeOK
+	if wakeErr != nil {
+		attempt.Outcome = registry.WakeFailed
+		attempt.Error = wakeErr.Error()
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := h.reg.RecordWake(ctx, tenantID, *attempt, h.cfg.WakeHistory); err != nil {
+		slog.Warn("record wake failed", "tenant", tenantID, "err", err)
+	}
+	if wakeErr == nil {
+		if err := h.reg.AddUsage(ctx, tenantID, attempt.StartedAt, registry.Usage{Wakes: 1}); err != nil {
+			slog.Warn("record wake usage failed", "tenant", tenantID, "err", err)
+		}
+	}
+	if wakeErr != nil {
+		h.recordWakeFailure(ctx, tenantID, wakeErr)
+		h.cfg.Events.Publish(events.WakeFailed, h.cfg.Environment.Name, tenantID, map[string]any{"duration_ms": attempt.DurationMs, "error": attempt.Error})
+	} else {
+		h.cfg.Events.Publish(events.TenantWoken, h.cfg.Environment.Name, tenantID, map[string]any{"duration_ms": attempt.DurationMs, "start": attempt.Start})
+	}
+}
+
+// ListWakes returns the tenant's recent wake attempts, newest first
+func (h *Handler) ListWakes(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	wakes, err := h.reg.ListWakes(r.Context(), tenantID)
+	if err != nil {
+		slog.Error("list wakes failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wakes)
+}
+
+// ListEvents returns the tenant's event log (OOM kills, crash loops), newest first
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	events, err := h.reg.ListEvents(r.Context(), tenantID)
+	if err != nil {
+		slog.Error("list events failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// normalizeDNS validates a tenant DNS override; an empty one means "no override"
+func normalizeDNS(d *registry.DNSConfig) (*registry.DNSConfig, error) {
+	if d == nil {
+		return nil, nil
+	}
+	settings := k8sclient.DNSSettings(*d)
+	if settings.IsZero() {
+		return nil, nil
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// normalizeLogForward validates a log forwarding target; an empty one means
+// "opt out"
+func normalizeLogForward(f *registry.LogForwardConfig) (*registry.LogForwardConfig, error) {
+	if f == nil || (f.TelegramChatID == 0 && f.WebhookURL == "") {
+		return nil, nil
+	}
+	if f.WebhookURL != "" {
+		u, err := url.Parse(f.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("log_forward.webhook_url must be an https URL")
+		}
 	}
+	return f, nil
+}
 
-	return podIP, nil
+// validateLocale checks a tenant's message locale and time zone; empty
+// values mean the defaults (English, UTC)
+func validateLocale(locale, tz string) error {
+	if !i18n.Supported(locale) {
+		return fmt.Errorf("unsupported locale %q, supported: %s", locale, strings.Join(i18n.Locales(), ", "))
+	}
+	if tz != "" {
+		return i18n.ValidateTimezone(tz)
+	}
+	return nil
 }
 
 // pollUntilRunning waits for another replica to finish waking the tenant
-func (h *Handler) pollUntilRunning(ctx context.Context, tenantID string) (string, error) {
+func (h *Handler) pollUntilRunning(ctx context.Context, tenantID string) (*registry.TenantRecord, error) {
 	deadline := time.Now().Add(h.cfg.WakeLockTTL)
 	for time.Now().Before(deadline) {
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
+			return nil, ctx.Err()
 		case <-time.After(2 * time.Second):
 		}
 		rec, err := h.reg.GetTenant(ctx, tenantID)
@@ -382,8 +1381,45 @@ func (h *Handler) pollUntilRunning(ctx context.Context, tenantID string) (string
 			continue
 		}
 		if rec != nil && rec.Status == registry.StatusRunning && rec.PodIP != "" {
-			return rec.PodIP, nil
+			return rec, nil
 		}
 	}
-	return "", fmt.Errorf("timeout waiting for tenant %s to become running", tenantID)
+	return nil, fmt.Errorf("timeout waiting for tenant %s to become running", tenantID)
+}
+
+// ChatTenant is one entry of the chat → tenant reverse index
+type ChatTenant struct {
+	TenantID   string    `json:"tenant_id"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// LookupChat returns the tenants whose bots have received messages from a
+// Telegram chat ID. The index is maintained by the Router in Redis.
+func (h *Handler) LookupChat(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(chi.URLParam(r, "chatID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid chat id", http.StatusBadRequest)
+		return
+	}
+	if h.rdb == nil {
+		http.Error(w, "chat index unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	entries, err := h.rdb.HGetAll(r.Context(), h.redisKey(contract.ChatIndexPrefix, strconv.FormatInt(chatID, 10))).Result()
+	if err != nil {
+		slog.Error("lookup chat failed", "chat_id", chatID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	result := make([]ChatTenant, 0, len(entries))
+	for tenantID, seen := range entries {
+		lastSeen, _ := time.Parse(time.RFC3339, seen)
+		result = append(result, ChatTenant{TenantID: tenantID, LastSeenAt: lastSeen})
+	}
+	// Most recently seen first
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeenAt.After(result[j].LastSeenAt)
+	})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/environment"
 	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
 	"github.com/shawn/agentic-tenancy/internal/lock"
 	"github.com/shawn/agentic-tenancy/internal/registry"
@@ -19,7 +22,9 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func newTestHandler(t *testing.T) (*api.Handler, *registry.MockClient, *lock.MockLocker, *fake.Clientset) {
@@ -62,6 +67,44 @@ func TestHealthz(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+// TestPublicRouter_OmitsInternalRoutes: the public listener serves the
+// management API but not bot tokens, wakes, exec sessions or tenants'
+// key-value stores
+func TestPublicRouter_OmitsInternalRoutes(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, BotToken: "tok"})
+	public := h.PublicRouter()
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/healthz"},
+		{http.MethodGet, "/tenants/alice"},
+		{http.MethodGet, "/tenants"},
+	} {
+		rec := httptest.NewRecorder()
+		public.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, tc.path)
+	}
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/tenants/alice/bot_token"},
+		{http.MethodGet, "/tenants/alice/slack"},
+		{http.MethodPut, "/tenants/alice/activity"},
+		{http.MethodPost, "/wake/alice"},
+		{http.MethodGet, "/tenants/alice/logs"},
+		{http.MethodPost, "/tenants/alice/exec"},
+		{http.MethodPost, "/tenants/alice/test-message"},
+		{http.MethodGet, "/tenants/alice/kv"},
+	} {
+		rec := httptest.NewRecorder()
+		public.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, tc.path)
+	}
+
+	// The internal listener still serves them
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice/bot_token", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
 func TestCreateTenant(t *testing.T) {
 	h, reg, _, _ := newTestHandler(t)
 
@@ -84,7 +127,7 @@ func TestCreateTenant(t *testing.T) {
 }
 
 func TestCreateTenant_Conflict(t *testing.T) {
-	h, _, _, _ := newTestHandler(t)
+	h, reg, _, _ := newTestHandler(t)
 
 	body, _ := json.Marshal(map[string]interface{}{"tenant_id": "dup-tenant"})
 	req1 := httptest.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body))
@@ -98,6 +141,13 @@ func TestCreateTenant_Conflict(t *testing.T) {
 	h.Router().ServeHTTP(rec2, req2)
 
 	assert.Equal(t, http.StatusConflict, rec2.Code)
+
+	// Rolling back the failed create leaves the existing tenant alone
+	tenant, err := reg.GetTenant(context.Background(), "dup-tenant")
+	require.NoError(t, err)
+	assert.NotNil(t, tenant)
+	sagas, _ := reg.ListSagas(context.Background())
+	assert.Empty(t, sagas)
 }
 
 // TestWakeTenant_NewTenant: first wake creates PVC + Pod + registry record
@@ -121,6 +171,42 @@ func TestWakeTenant_NewTenant(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, registry.StatusRunning, tenant.Status)
 	assert.Equal(t, "10.0.0.1", tenant.PodIP)
+
+	// The wake is metered
+	days, err := reg.ListUsage(context.Background(), tenantID, time.Now(), time.Now())
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, int64(1), days[0].Wakes)
+}
+
+// TestWakeTenant_WarmPoolNamespace: a warm pod in the warm pool's own
+// namespace is claimed, and the tenant pod lands on its node in the tenants'
+func TestWakeTenant_WarmPoolNamespace(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "warm-pool-abc", Namespace: "warm",
+			Labels: map[string]string{"app": "warm-pool", "warm": "true"},
+		},
+		Spec:   corev1.PodSpec{NodeName: "node-7"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.9.9"},
+	})
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test", S3Bucket: "test-bucket"})
+	h := api.New(registry.NewMock(), k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		Environment:  environment.Environment{Namespace: "tenants", WarmNamespace: "warm"},
+	})
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	pod, err := cs.CoreV1().Pods("tenants").Get(context.Background(), "zeroclaw-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "node-7", pod.Spec.NodeName)
+	_, err = cs.CoreV1().Pods("warm").Get(context.Background(), "warm-pool-abc", metav1.GetOptions{})
+	assert.Error(t, err, "the claimed warm pod is deleted")
 }
 
 // TestWakeTenant_AlreadyRunning: returns IP immediately, no new Pod created
@@ -153,6 +239,35 @@ func TestWakeTenant_AlreadyRunning(t *testing.T) {
 	assert.Len(t, pods.Items, 0, "no new pods should be created for already-running tenant")
 }
 
+// TestWakeTenant_ReturnsIdleTimeout: wake response carries idle_timeout_s for Router cache TTL
+func TestWakeTenant_ReturnsIdleTimeout(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	tenantID := "short-idle-tenant"
+
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     tenantID,
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-" + tenantID,
+		PodIP:        "10.0.0.6",
+		Namespace:    "tenants",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 60,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/wake/"+tenantID, nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result struct {
+		PodIP        string `json:"pod_ip"`
+		IdleTimeoutS int64  `json:"idle_timeout_s"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.Equal(t, "10.0.0.6", result.PodIP)
+	assert.Equal(t, int64(60), result.IdleTimeoutS)
+}
+
 // TestWakeTenant_IdleTenant: reuses PVC, creates new Pod
 func TestWakeTenant_IdleTenant(t *testing.T) {
 	h, reg, _, cs := newTestHandler(t)
@@ -183,6 +298,80 @@ func TestWakeTenant_IdleTenant(t *testing.T) {
 	assert.Equal(t, registry.StatusRunning, tenant.Status)
 }
 
+// TestWakeTenant_RecordsHistory: each wake attempt lands in GET /tenants/{id}/wakes, newest first
+func TestWakeTenant_RecordsHistory(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants", IdleTimeoutS: 300,
+	})
+
+	wake := func() int {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+		return rec.Code
+	}
+
+	// Pod creation is rejected once: that attempt is recorded as failed
+	rejected := false
+	cs.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if rejected {
+			return false, nil, nil
+		}
+		rejected = true
+		return true, nil, errors.New("exceeded quota")
+	})
+	require.NotEqual(t, http.StatusOK, wake())
+
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.9")
+	require.Equal(t, http.StatusOK, wake())
+	// Already running: not a wake attempt
+	require.Equal(t, http.StatusOK, wake())
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice/wakes", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var wakes []registry.WakeAttempt
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&wakes))
+	require.Len(t, wakes, 2)
+
+	assert.Equal(t, registry.WakeOK, wakes[0].Outcome)
+	assert.Equal(t, "cold", wakes[0].Start)
+	assert.Empty(t, wakes[0].Error)
+
+	assert.Equal(t, registry.WakeFailed, wakes[1].Outcome)
+	assert.Equal(t, "cold", wakes[1].Start)
+	assert.Contains(t, wakes[1].Error, "exceeded quota")
+	assert.False(t, wakes[1].StartedAt.After(wakes[0].StartedAt))
+}
+
+func TestListWakes_NotFound(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/nobody/wakes", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestListEvents(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusRunning}))
+	require.NoError(t, reg.RecordEvent(ctx, "alice", registry.TenantEvent{Kind: registry.EventOOMKilled, Pod: "zeroclaw-alice", Restarts: 1}, 10))
+	require.NoError(t, reg.RecordEvent(ctx, "alice", registry.TenantEvent{Kind: registry.EventCrashLoop, Pod: "zeroclaw-alice", Restarts: 4}, 10))
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice/events", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []registry.TenantEvent
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&events))
+	require.Len(t, events, 2)
+	assert.Equal(t, registry.EventCrashLoop, events[0].Kind)
+	assert.Equal(t, registry.EventOOMKilled, events[1].Kind)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/nobody/events", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 // TestWakeTenant_ConcurrentWake: 10 goroutines wake same tenant → only 1 Pod created
 func TestWakeTenant_ConcurrentWake(t *testing.T) {
 	h, _, _, cs := newTestHandler(t)
@@ -217,7 +406,7 @@ func TestWakeTenant_ConcurrentWake(t *testing.T) {
 }
 
 func TestDeleteTenant(t *testing.T) {
-	h, reg, _, _ := newTestHandler(t)
+	h, reg, _, cs := newTestHandler(t)
 	tenantID := "delete-me"
 
 	reg.CreateTenant(context.Background(), &registry.TenantRecord{
@@ -225,6 +414,9 @@ func TestDeleteTenant(t *testing.T) {
 		Status:    registry.StatusIdle,
 		Namespace: "tenants",
 	})
+	cs.CoreV1().Secrets("tenants").Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: contract.BotTokenSecretName(tenantID), Namespace: "tenants"},
+	}, metav1.CreateOptions{})
 
 	req := httptest.NewRequest(http.MethodDelete, "/tenants/"+tenantID, nil)
 	rec := httptest.NewRecorder()
@@ -233,6 +425,52 @@ func TestDeleteTenant(t *testing.T) {
 
 	tenant, _ := reg.GetTenant(context.Background(), tenantID)
 	assert.Nil(t, tenant)
+	secrets, _ := cs.CoreV1().Secrets("tenants").List(context.Background(), metav1.ListOptions{})
+	assert.Empty(t, secrets.Items, "pod bot token secret removed")
+}
+
+// TestDeleteTenant_Confirm: with RequireConfirm, deletes need an X-Confirm
+// header naming the tenant; dry runs report the plan and delete nothing
+func TestDeleteTenant_Confirm(t *testing.T) {
+	reg := registry.NewMock()
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{Namespace: "tenants", RequireConfirm: true})
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:  "alice",
+		Status:    registry.StatusRunning,
+		Namespace: "tenants",
+		PodName:   "zeroclaw-alice",
+	})
+
+	del := func(path, confirm string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		if confirm != "" {
+			req.Header.Set(api.ConfirmHeader, confirm)
+		}
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := del("/tenants/alice?dry_run=true", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var plan struct {
+		TenantID string   `json:"tenant_id"`
+		Deletes  []string `json:"deletes"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&plan))
+	assert.Equal(t, "alice", plan.TenantID)
+	assert.Contains(t, plan.Deletes, "pod tenants/zeroclaw-alice")
+	assert.Equal(t, http.StatusNotFound, del("/tenants/bob?dry_run=true", "").Code)
+
+	assert.Equal(t, http.StatusPreconditionRequired, del("/tenants/alice", "").Code)
+	assert.Equal(t, http.StatusPreconditionRequired, del("/tenants/alice", "bob").Code)
+	assert.Equal(t, http.StatusPreconditionRequired, del("/tenants/alice?confirm_token=guess", "").Code)
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	require.NotNil(t, tenant)
+
+	assert.Equal(t, http.StatusNoContent, del("/tenants/alice", "alice").Code)
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Nil(t, tenant)
 }
 
 func TestUpdateActivity(t *testing.T) {
@@ -255,3 +493,285 @@ func TestUpdateActivity(t *testing.T) {
 	tenant, _ := reg.GetTenant(context.Background(), tenantID)
 	assert.True(t, tenant.LastActiveAt.After(before))
 }
+
+func TestLookupChat_InvalidID(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/lookup/chat/not-a-number", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLookupChat_NoRedis(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/lookup/chat/123456789", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestListTenants_Sorted(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	now := time.Now()
+	for i, id := range []string{"bravo", "alpha", "charlie"} {
+		reg.CreateTenant(context.Background(), &registry.TenantRecord{
+			TenantID:     id,
+			Status:       registry.StatusIdle,
+			LastActiveAt: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	list := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/tenants"+query, nil)
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var records []registry.TenantRecord
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&records))
+		var ids []string
+		for _, r := range records {
+			ids = append(ids, r.TenantID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"alpha", "bravo", "charlie"}, list(""))
+	assert.Equal(t, []string{"charlie", "alpha", "bravo"}, list("?sort=last_active_at&order=desc"))
+}
+
+func TestListTenants_InvalidSort(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	for _, query := range []string{"?sort=bot_token", "?order=sideways"} {
+		req := httptest.NewRequest(http.MethodGet, "/tenants"+query, nil)
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+// TestUpdateTenant_DNS: DNS overrides are validated, stored, and cleared with an empty object
+func TestUpdateTenant_DNS(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"dns":{"policy":"None"}}`))
+
+	require.Equal(t, http.StatusOK, patch(`{"dns":{"searches":["corp.internal"],"options":["ndots:2"]}}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	require.NotNil(t, tenant.DNS)
+	assert.Equal(t, []string{"corp.internal"}, tenant.DNS.Searches)
+
+	require.Equal(t, http.StatusOK, patch(`{"dns":{}}`))
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Nil(t, tenant.DNS)
+}
+
+func TestUpdateTenant_LogForward(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"log_forward":{"webhook_url":"http://insecure.example.com"}}`))
+
+	require.Equal(t, http.StatusOK, patch(`{"log_forward":{"telegram_chat_id":12345,"webhook_url":"https://hooks.example.com/zc"}}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	require.NotNil(t, tenant.LogForward)
+	assert.Equal(t, int64(12345), tenant.LogForward.TelegramChatID)
+	assert.Equal(t, "https://hooks.example.com/zc", tenant.LogForward.WebhookURL)
+
+	require.Equal(t, http.StatusOK, patch(`{"log_forward":{}}`))
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Nil(t, tenant.LogForward)
+}
+
+func TestUpdateTenant_AllowedUpdates(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"allowed_updates":["message","inline_query"]}`))
+
+	require.Equal(t, http.StatusOK, patch(`{"allowed_updates":["message","callback_query"]}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, []string{"message", "callback_query"}, tenant.AllowedUpdates)
+
+	require.Equal(t, http.StatusOK, patch(`{"allowed_updates":[]}`))
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Empty(t, tenant.AllowedUpdates)
+}
+
+// TestUpdateTenant_Slack: Slack credentials are stored, redacted from
+// tenant responses and served to the router by GET /tenants/{id}/slack
+func TestUpdateTenant_Slack(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/tenants/alice/slack", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPatch, "/tenants/alice", `{"slack":{"signing_secret":"s3cret"}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPatch, "/tenants/alice", `{"slack":{"signing_secret":"s3cret","bot_token":"xoxp-1"}}`).Code)
+
+	rec := do(http.MethodPatch, "/tenants/alice", `{"slack":{"signing_secret":"s3cret","bot_token":"xoxb-1"}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cret")
+	assert.NotContains(t, rec.Body.String(), "xoxb-1")
+
+	rec = do(http.MethodGet, "/tenants/alice/slack", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got registry.SlackConfig
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, registry.SlackConfig{SigningSecret: "s3cret", BotToken: "xoxb-1"}, got)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, "/tenants/alice", `{"slack":{}}`).Code)
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Nil(t, tenant.Slack)
+}
+
+// TestPutWebhookSecret: a router-registered secret is stored, served with
+// the bot token and redacted from tenant responses
+func TestPutWebhookSecret(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, BotToken: "123:abc"})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/tenants/alice/webhook_secret", `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/tenants/bob/webhook_secret", `{"webhook_secret":"s3cret"}`).Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPut, "/tenants/alice/webhook_secret", `{"webhook_secret":"s3cret"}`).Code)
+
+	rec := do(http.MethodGet, "/tenants/alice/bot_token", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got map[string]string
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, "s3cret", got["WebhookSecret"])
+
+	rec = do(http.MethodGet, "/tenants/alice", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cret")
+}
+
+// TestUpdateTenant_Locale: locale and time zone are validated, stored and
+// cleared with an empty string
+func TestUpdateTenant_Locale(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"locale":"xx"}`))
+	assert.Equal(t, http.StatusBadRequest, patch(`{"timezone":"Mars/Olympus"}`))
+	assert.Equal(t, http.StatusBadRequest, patch(`{"timezone":"Local"}`))
+
+	require.Equal(t, http.StatusOK, patch(`{"locale":"pt-BR","timezone":"America/Sao_Paulo"}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, "pt-BR", tenant.Locale)
+	assert.Equal(t, "America/Sao_Paulo", tenant.Timezone)
+
+	require.Equal(t, http.StatusOK, patch(`{"locale":"","timezone":""}`))
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Empty(t, tenant.Locale)
+	assert.Empty(t, tenant.Timezone)
+}
+
+// TestUpdateTenant_MaxMessageAge: the override is stored, -1 turns it off,
+// and anything below is refused
+func TestUpdateTenant_MaxMessageAge(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"max_message_age_s":-2}`))
+	require.Equal(t, http.StatusOK, patch(`{"max_message_age_s":600}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, int64(600), tenant.MaxMessageAgeS)
+
+	require.Equal(t, http.StatusOK, patch(`{"max_message_age_s":-1}`))
+	tenant, _ = reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, int64(-1), tenant.MaxMessageAgeS)
+}
+
+// TestUpdateTenant_BadFieldStoresNothing: a request with one invalid field
+// is rejected before any of its fields are stored
+func TestUpdateTenant_BadFieldStoresNothing(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Tier: "free", IdleTimeoutS: 300})
+
+	for _, body := range []string{
+		`{"idle_timeout_s":900,"dns":{"policy":"None"}}`,
+		`{"idle_timeout_s":900,"tier":"premium","max_reply_bytes":-2}`,
+	} {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, int64(300), tenant.IdleTimeoutS)
+	assert.Equal(t, "free", tenant.Tier)
+}
+
+// TestWakeTenant_AppliesDNS: the tenant's DNS override reaches the pod spec
+func TestWakeTenant_AppliesDNS(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     "alice",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+		DNS:          &registry.DNSConfig{Policy: "None", Nameservers: []string{"10.0.0.2"}},
+	})
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.7")
+
+	req := httptest.NewRequest(http.MethodPost, "/wake/alice", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	pod, err := cs.CoreV1().Pods("tenants").Get(context.Background(), "zeroclaw-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.DNSNone, pod.Spec.DNSPolicy)
+	require.NotNil(t, pod.Spec.DNSConfig)
+	assert.Equal(t, []string{"10.0.0.2"}, pod.Spec.DNSConfig.Nameservers)
+}
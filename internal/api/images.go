@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+var (
+	// Alias names never contain '/', ':' or '@', so they can't be mistaken
+	// for an explicit image reference.
+	imageAliasRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,62}$`)
+	imageRefRe   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/:@-]{0,254}$`)
+)
+
+// PutImage creates or repoints an image alias. Tenants following the alias
+// pick up the new image on their next wake; running pods are not restarted.
+func (h *Handler) PutImage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Alias string `json:"alias"`
+		Image string `json:"image"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if !imageAliasRe.MatchString(req.Alias) {
+		http.Error(w, "alias must be lowercase alphanumerics, '.', '_' or '-'", http.StatusBadRequest)
+		return
+	}
+	if !imageRefRe.MatchString(req.Image) {
+		http.Error(w, "image must be an image reference or tag", http.StatusBadRequest)
+		return
+	}
+	alias := &registry.ImageAlias{Alias: req.Alias, Image: req.Image, UpdatedAt: time.Now().UTC()}
+	if err := h.reg.PutImageAlias(r.Context(), alias); err != nil {
+		slog.Error("put image alias failed", "alias", req.Alias, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("image alias updated", "alias", req.Alias, "image", req.Image)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alias)
+}
+
+// ListImages returns the image catalog sorted by alias
+func (h *Handler) ListImages(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.reg.ListImageAliases(r.Context())
+	if err != nil {
+		slog.Error("list image aliases failed", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if aliases == nil {
+		aliases = []*registry.ImageAlias{}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aliases)
+}
+
+// DeleteImage removes an image alias. Tenants pinned to it fall back to
+// treating the alias name as a tag.
+func (h *Handler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	alias := chi.URLParam(r, "alias")
+	if err := h.reg.DeleteImageAlias(r.Context(), alias); err != nil {
+		slog.Error("delete image alias failed", "alias", alias, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveImage maps a tenant's image pin to the image (or bare tag) to run.
+// An empty pin follows the default channel; a pin naming a catalog alias
+// resolves to that alias's current image; anything else is an explicit tag
+// or reference. Returns "" to run the orchestrator's ZEROCLAW_IMAGE.
+func (h *Handler) resolveImage(ctx context.Context, pin string) (string, error) {
+	name := pin
+	if name == "" {
+		name = h.cfg.DefaultChannel
+	}
+	alias, err := h.reg.GetImageAlias(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("get image alias %q: %w", name, err)
+	}
+	if alias != nil {
+		return alias.Image, nil
+	}
+	return pin, nil
+}
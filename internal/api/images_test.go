@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPutAndListImages(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	for _, a := range []map[string]string{
+		{"alias": "stable", "image": "zeroclaw:v1.4.0"},
+		{"alias": "beta", "image": "zeroclaw:v1.5.0-rc1"},
+	} {
+		body, _ := json.Marshal(a)
+		req := httptest.NewRequest(http.MethodPost, "/images", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/images", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var aliases []registry.ImageAlias
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&aliases))
+	require.Len(t, aliases, 2)
+	assert.Equal(t, "beta", aliases[0].Alias)
+	assert.Equal(t, "stable", aliases[1].Alias)
+	assert.Equal(t, "zeroclaw:v1.4.0", aliases[1].Image)
+}
+
+func TestPutImage_InvalidAlias(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+
+	body, _ := json.Marshal(map[string]string{"alias": "repo/stable", "image": "zeroclaw:v1"})
+	req := httptest.NewRequest(http.MethodPost, "/images", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDeleteImage(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.PutImageAlias(context.Background(), &registry.ImageAlias{Alias: "beta", Image: "zeroclaw:beta"})
+
+	req := httptest.NewRequest(http.MethodDelete, "/images/beta", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	got, _ := reg.GetImageAlias(context.Background(), "beta")
+	assert.Nil(t, got)
+}
+
+// TestWake_ImageResolution: unpinned tenants follow the default channel,
+// pinned tenants follow their alias, and unknown pins are treated as tags.
+func TestWake_ImageResolution(t *testing.T) {
+	cases := []struct {
+		name string
+		pin  string
+		want string
+	}{
+		{"default channel", "", "zeroclaw:v1.4.0"},
+		{"alias", "beta", "zeroclaw:v1.5.0-rc1"},
+		{"explicit tag", "v1.3.2", "zeroclaw:v1.3.2"},
+		{"explicit image", "mirror.example.com/zeroclaw:v1.3.2", "mirror.example.com/zeroclaw:v1.3.2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, reg, _, cs := newTestHandler(t)
+			ctx := context.Background()
+			reg.PutImageAlias(ctx, &registry.ImageAlias{Alias: "stable", Image: "zeroclaw:v1.4.0"})
+			reg.PutImageAlias(ctx, &registry.ImageAlias{Alias: "beta", Image: "zeroclaw:v1.5.0-rc1"})
+			reg.CreateTenant(ctx, &registry.TenantRecord{
+				TenantID:     "alice",
+				Status:       registry.StatusIdle,
+				Namespace:    "tenants",
+				LastActiveAt: time.Now(),
+				IdleTimeoutS: 300,
+				Image:        tc.pin,
+			})
+			simulatePodReady(cs, "alice", "tenants", "10.0.0.9")
+
+			req := httptest.NewRequest(http.MethodPost, "/wake/alice", nil)
+			rec := httptest.NewRecorder()
+			h.Router().ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			pod, err := cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, pod.Spec.Containers[0].Image)
+		})
+	}
+}
+
+func TestUpdateTenant_Image(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Image: "beta"})
+
+	body, _ := json.Marshal(map[string]string{"image": ""})
+	req := httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Empty(t, tenant.Image, "empty image should unpin the tenant")
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// KillSwitch stops all message forwarding and wakes in this environment, for
+// use during security incidents. It is returned by GET and POST
+// /admin/killswitch.
+type KillSwitch struct {
+	Active bool `json:"active"`
+	// Reason is for the audit log; users never see it
+	Reason string `json:"reason,omitempty"`
+	// Message replaces the default "temporarily unavailable" reply users get
+	Message     string    `json:"message,omitempty"`
+	ActivatedBy string    `json:"activated_by,omitempty"` // API key name, if auth is on
+	ActivatedAt time.Time `json:"activated_at,omitempty"`
+}
+
+// killSwitchMemory holds the kill switch when there is no Redis (local mode)
+type killSwitchMemory struct {
+	mu      sync.Mutex
+	current *KillSwitch
+}
+
+// DisabledError refuses a wake while the kill switch is on (Global) or the
+// tenant is disabled
+type DisabledError struct {
+	Global bool
+}
+
+func (e *DisabledError) Error() string {
+	if e.Global {
+		return "kill switch active"
+	}
+	return "tenant disabled"
+}
+
+// writeDisabled answers 503 with contract.KillSwitchHeader, which the router turns
+// into an outage reply instead of a generic start failure
+func writeDisabled(w http.ResponseWriter, err *DisabledError) {
+	scope := "tenant"
+	if err.Global {
+		scope = "global"
+	}
+	w.Header().Set(contract.KillSwitchHeader, scope)
+	http.Error(w, err.Error(), http.StatusServiceUnavailable)
+}
+
+// killSwitchRequest is the body of POST /admin/killswitch
+type killSwitchRequest struct {
+	Active  *bool  `json:"active"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// SetKillSwitch turns the environment's kill switch on or off. Turning it on
+// needs a reason. Routers stop forwarding on their next update and wakes are
+// refused; running pods are left alone so turning it off resumes service
+// where it stopped. Both changes are logged with the caller's API key name
+// and address.
+func (h *Handler) SetKillSwitch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req killSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.Active == nil {
+		http.Error(w, "active is required", http.StatusBadRequest)
+		return
+	}
+	ks := KillSwitch{Active: *req.Active}
+	if ks.Active {
+		ks.Reason = strings.TrimSpace(req.Reason)
+		if ks.Reason == "" {
+			http.Error(w, "reason is required to activate the kill switch", http.StatusBadRequest)
+			return
+		}
+		ks.Message = strings.TrimSpace(req.Message)
+		ks.ActivatedBy = caller(r)
+		ks.ActivatedAt = time.Now().UTC()
+		if err := h.saveKillSwitch(ctx, &ks); err != nil {
+			slog.Error("save kill switch", "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		slog.Warn("killswitch: activated", "reason", ks.Reason, "by", ks.ActivatedBy, "remote", r.RemoteAddr)
+	} else {
+		prev, err := h.loadKillSwitch(ctx)
+		if err != nil {
+			slog.Error("load kill switch", "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if err := h.saveKillSwitch(ctx, nil); err != nil {
+			slog.Error("save kill switch", "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if prev != nil {
+			slog.Warn("killswitch: deactivated", "by", caller(r), "remote", r.RemoteAddr,
+				"active_for", time.Since(prev.ActivatedAt).Round(time.Second))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ks)
+}
+
+// GetKillSwitch reports whether the kill switch is on
+func (h *Handler) GetKillSwitch(w http.ResponseWriter, r *http.Request) {
+	ks, err := h.loadKillSwitch(r.Context())
+	if err != nil {
+		slog.Error("load kill switch", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if ks == nil {
+		ks = &KillSwitch{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ks)
+}
+
+// setTenantDisabled sets a tenant's kill switch. Disabling drops the router's
+// endpoint cache, so its next message for the tenant asks for a wake, which
+// is refused.
+func (h *Handler) setTenantDisabled(r *http.Request, tenantID string, disabled bool) error {
+	ctx := r.Context()
+	if err := h.reg.UpdateDisabled(ctx, tenantID, disabled); err != nil {
+		return err
+	}
+	if disabled && h.rdb != nil {
+		if err := h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, tenantID)).Err(); err != nil {
+			slog.Warn("killswitch: failed to invalidate router endpoint cache", "tenant", tenantID, "err", err)
+		}
+	}
+	if disabled {
+		slog.Warn("killswitch: tenant disabled", "tenant", tenantID, "by", caller(r), "remote", r.RemoteAddr)
+	} else {
+		slog.Warn("killswitch: tenant enabled", "tenant", tenantID, "by", caller(r), "remote", r.RemoteAddr)
+	}
+	return nil
+}
+
+// checkDisabled refuses to wake rec while it or the environment is switched
+// off
+func (h *Handler) checkDisabled(ctx context.Context, rec *registry.TenantRecord) error {
+	if rec != nil && rec.Disabled {
+		return &DisabledError{}
+	}
+	ks, err := h.loadKillSwitch(ctx)
+	if err != nil {
+		return err
+	}
+	if ks != nil {
+		return &DisabledError{Global: true}
+	}
+	return nil
+}
+
+// saveKillSwitch stores the kill switch in Redis, where routers and every
+// replica see it; nil turns it off
+func (h *Handler) saveKillSwitch(ctx context.Context, ks *KillSwitch) error {
+	if h.rdb == nil {
+		h.killSwitch.mu.Lock()
+		defer h.killSwitch.mu.Unlock()
+		h.killSwitch.current = ks
+		return nil
+	}
+	if ks == nil {
+		return h.rdb.Del(ctx, h.redisKey(contract.KillSwitchKey, "")).Err()
+	}
+	data, err := json.Marshal(ks)
+	if err != nil {
+		return err
+	}
+	return h.rdb.Set(ctx, h.redisKey(contract.KillSwitchKey, ""), data, 0).Err()
+}
+
+// loadKillSwitch returns nil while the kill switch is off
+func (h *Handler) loadKillSwitch(ctx context.Context) (*KillSwitch, error) {
+	if h.rdb == nil {
+		h.killSwitch.mu.Lock()
+		defer h.killSwitch.mu.Unlock()
+		if h.killSwitch.current == nil {
+			return nil, nil
+		}
+		ks := *h.killSwitch.current
+		return &ks, nil
+	}
+	data, err := h.rdb.Get(ctx, h.redisKey(contract.KillSwitchKey, "")).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ks KillSwitch
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
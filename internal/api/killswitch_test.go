@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKillSwitch(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice", PodIP: "10.0.0.1", Namespace: "tenants",
+	}))
+	set := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/killswitch", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := set(`{"active":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "activating needs a reason")
+
+	rec = set(`{"active":true,"reason":"leaked token","message":"Down for maintenance"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var ks api.KillSwitch
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&ks))
+	assert.True(t, ks.Active)
+	assert.Equal(t, "Down for maintenance", ks.Message)
+	assert.False(t, ks.ActivatedAt.IsZero())
+
+	// Even a running tenant's wake is refused
+	for _, path := range []string{"/wake/alice", "/wake/alice?async=true"} {
+		rec = httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, path)
+		assert.Equal(t, "global", rec.Header().Get(contract.KillSwitchHeader), path)
+	}
+
+	rec = set(`{"active":false}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/killswitch", nil))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&ks))
+	assert.False(t, ks.Active)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestTenantDisabled(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice", PodIP: "10.0.0.1", Namespace: "tenants",
+	}))
+	patch := func(body string) {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/tenants/alice", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	patch(`{"disabled":true}`)
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.True(t, tenant.Disabled)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice?async=true", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "tenant", rec.Header().Get(contract.KillSwitchHeader))
+
+	patch(`{"disabled":false}`)
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
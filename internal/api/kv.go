@@ -0,0 +1,170 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// Limits of a tenant's key-value store, which lives in one DynamoDB item
+// (at most 400KB)
+const (
+	kvMaxKeys       = 64
+	kvMaxValueBytes = 4096
+)
+
+var kvKeyRe = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// kvToken is the token a tenant's pod authenticates to its key-value store
+// with: an HMAC over the environment and tenant keyed with KV_TOKEN_SECRET.
+// It isn't stored anywhere but the tenant's Secret; rotating the secret
+// revokes every token, and pods get new ones when next started.
+func kvToken(secret []byte, env, tenantID string) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte("kv\n" + env + "\n" + tenantID))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// kvAccess is what a tenant's new pod needs to reach its key-value store,
+// or nil if pod access isn't configured (KV_TOKEN_SECRET, KV_POD_URL)
+func (h *Handler) kvAccess(tenantID string) *k8sclient.KVAccess {
+	if len(h.cfg.KVTokenSecret) == 0 || h.cfg.KVURL == "" {
+		return nil
+	}
+	return &k8sclient.KVAccess{
+		URL:   h.cfg.KVURL + "/tenants/" + tenantID + "/kv",
+		Token: kvToken(h.cfg.KVTokenSecret, h.cfg.Environment.Name, tenantID),
+	}
+}
+
+// requireKVAccess lets a tenant's pod, holding its KV token, at its own
+// store; everyone else needs an API key
+func (h *Handler) requireKVAccess(next http.Handler) http.Handler {
+	withKey := h.requireAPIKey(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if len(h.cfg.KVTokenSecret) > 0 && token != "" &&
+			hmac.Equal([]byte(token), []byte(kvToken(h.cfg.KVTokenSecret, h.cfg.Environment.Name, chi.URLParam(r, "tenantID")))) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		withKey.ServeHTTP(w, r)
+	})
+}
+
+// ListKV returns all of the tenant's key-value pairs:
+// GET /tenants/{tenantID}/kv
+func (h *Handler) ListKV(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	kv, ok := h.tenantKV(w, r, tenantID)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"tenant_id": tenantID, "kv": kv})
+}
+
+// GetKV returns one of the tenant's values: GET /tenants/{tenantID}/kv/{key}
+func (h *Handler) GetKV(w http.ResponseWriter, r *http.Request) {
+	tenantID, key := chi.URLParam(r, "tenantID"), chi.URLParam(r, "key")
+	kv, ok := h.tenantKV(w, r, tenantID)
+	if !ok {
+		return
+	}
+	value, found := kv[key]
+	if !found {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"key": key, "value": value})
+}
+
+// PutKV sets one of the tenant's values: PUT /tenants/{tenantID}/kv/{key}
+// with body {"value": "..."}
+func (h *Handler) PutKV(w http.ResponseWriter, r *http.Request) {
+	tenantID, key := chi.URLParam(r, "tenantID"), chi.URLParam(r, "key")
+	if !kvKeyRe.MatchString(key) {
+		http.Error(w, "key must be 1-128 letters, digits, '_', '.' or '-'", http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Value *string `json:"value"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*kvMaxValueBytes)).Decode(&req); err != nil || req.Value == nil {
+		http.Error(w, `body must be {"value": "..."}`, http.StatusBadRequest)
+		return
+	}
+	if len(*req.Value) > kvMaxValueBytes {
+		http.Error(w, "value over 4096 bytes", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if _, ok := h.kvTenant(w, r, tenantID); !ok {
+		return
+	}
+	err := h.reg.PutKV(r.Context(), tenantID, key, *req.Value, kvMaxKeys)
+	if errors.Is(err, registry.ErrKVFull) {
+		http.Error(w, "tenant already has 64 keys", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error("put kv failed", "tenant", tenantID, "key", key, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteKV removes one of the tenant's values:
+// DELETE /tenants/{tenantID}/kv/{key}
+func (h *Handler) DeleteKV(w http.ResponseWriter, r *http.Request) {
+	tenantID, key := chi.URLParam(r, "tenantID"), chi.URLParam(r, "key")
+	if _, ok := h.kvTenant(w, r, tenantID); !ok {
+		return
+	}
+	if err := h.reg.DeleteKV(r.Context(), tenantID, key); err != nil {
+		slog.Error("delete kv failed", "tenant", tenantID, "key", key, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tenantKV loads the tenant's pairs, writing the error response if it can't
+func (h *Handler) tenantKV(w http.ResponseWriter, r *http.Request, tenantID string) (map[string]string, bool) {
+	if _, ok := h.kvTenant(w, r, tenantID); !ok {
+		return nil, false
+	}
+	kv, err := h.reg.ListKV(r.Context(), tenantID)
+	if err != nil {
+		slog.Error("list kv failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return kv, true
+}
+
+// kvTenant checks the tenant exists, so a deleted tenant's pod can't leave
+// pairs behind
+func (h *Handler) kvTenant(w http.ResponseWriter, r *http.Request, tenantID string) (*registry.TenantRecord, bool) {
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return nil, false
+	}
+	return rec, true
+}
@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestKV: a woken pod is told where its key-value store is and gets a token
+// that opens that store and no other; operators use their API key
+func TestKV(t *testing.T) {
+	keys, err := apikey.ParseStatic("ops=s3cret")
+	require.NoError(t, err)
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:     "tenants",
+		PodReadyWait:  5 * time.Second,
+		APIKeys:       keys,
+		KVTokenSecret: []byte("kv-secret"),
+		KVURL:         "http://orchestrator.tenants.svc.cluster.local:8080",
+	})
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "bob", Status: registry.StatusIdle, Namespace: "tenants"})
+
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/wake/alice", "s3cret", "").Code)
+	pod, err := cs.CoreV1().Pods("tenants").Get(context.Background(), "zeroclaw-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, pod.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: "AGENT_KV_URL", Value: "http://orchestrator.tenants.svc.cluster.local:8080/tenants/alice/kv"})
+	secret, err := cs.CoreV1().Secrets("tenants").Get(context.Background(), contract.BotTokenSecretName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	token := string(secret.Data["kv-token"])
+	require.NotEmpty(t, token)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPut, "/tenants/alice/kv/model", token, `{"value":"sonnet"}`).Code)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPut, "/tenants/bob/kv/model", "s3cret", `{"value":"haiku"}`).Code)
+	rec := do(http.MethodGet, "/tenants/alice/kv", token, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		KV map[string]string `json:"kv"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&list))
+	assert.Equal(t, map[string]string{"model": "sonnet"}, list.KV)
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/tenants/bob/kv/model", token, "").Code, "another tenant's store")
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/tenants", token, "").Code, "a KV token is no API key")
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice/kv/model", token, "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/tenants/alice/kv/model", token, "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/tenants/carol/kv/model", "s3cret", `{"value":"x"}`).Code)
+}
+
+func TestPutKV_Limits(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants"})
+	put := func(key, body string) int {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/tenants/alice/kv/"+key, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, put("a%20b", `{"value":"x"}`))
+	assert.Equal(t, http.StatusBadRequest, put("k", `"x"`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, put("k", `{"value":"`+strings.Repeat("x", 4097)+`"}`))
+	for i := range 64 {
+		require.Equal(t, http.StatusNoContent, put("k"+strconv.Itoa(i), `{"value":"x"}`))
+	}
+	assert.Equal(t, http.StatusConflict, put("one-too-many", `{"value":"x"}`))
+	assert.Equal(t, http.StatusNoContent, put("k0", `{"value":"y"}`), "existing keys can still change")
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Telegram allows up to 64 characters of A-Z, a-z, 0-9, _ and - in a start payload.
+var startPayloadRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// DeepLink is the response of GET /tenants/{tenantID}/link
+type DeepLink struct {
+	TenantID    string `json:"tenant_id"`
+	BotUsername string `json:"bot_username"`
+	URL         string `json:"url"`
+}
+
+// GetLink returns a t.me deep link for the tenant's bot.
+// Optional query param: start=<payload> (passed to the bot as /start <payload>).
+func (h *Handler) GetLink(w http.ResponseWriter, r *http.Request) {
+	link, status, msg := h.deepLink(r)
+	if link == nil {
+		http.Error(w, msg, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(link)
+}
+
+// GetLinkQR renders the tenant's deep link as a PNG QR code.
+// Optional query params: start=<payload>, size=<pixels> (default 256).
+func (h *Handler) GetLinkQR(w http.ResponseWriter, r *http.Request) {
+	link, status, msg := h.deepLink(r)
+	if link == nil {
+		http.Error(w, msg, status)
+		return
+	}
+	size := 256
+	if v := r.URL.Query().Get("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 64 || n > 1024 {
+			http.Error(w, "size must be between 64 and 1024", http.StatusBadRequest)
+			return
+		}
+		size = n
+	}
+	png, err := qrcode.Encode(link.URL, qrcode.Medium, size)
+	if err != nil {
+		slog.Error("encode QR failed", "tenant", link.TenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// deepLink builds the link for the request's tenant, resolving and storing the
+// bot username on first use. On failure it returns nil with an HTTP status and message.
+func (h *Handler) deepLink(r *http.Request) (*DeepLink, int, string) {
+	tenantID := chi.URLParam(r, "tenantID")
+	start := r.URL.Query().Get("start")
+	if start != "" && !startPayloadRe.MatchString(start) {
+		return nil, http.StatusBadRequest, "start must be 1-64 characters of A-Z, a-z, 0-9, _ or -"
+	}
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "internal error"
+	}
+	if rec == nil {
+		return nil, http.StatusNotFound, "not found"
+	}
+	if rec.BotUsername == "" {
+		token, err := h.botToken(r.Context(), rec)
+		if err != nil {
+			slog.Warn("read bot_token failed", "tenant", tenantID, "err", err)
+		}
+		rec.BotUsername = h.lookupBotUsername(r.Context(), tenantID, token)
+		if rec.BotUsername == "" {
+			return nil, http.StatusConflict, "bot username unknown (no bot_token, or Telegram getMe unavailable)"
+		}
+		if err := h.reg.UpdateBotUsername(r.Context(), tenantID, rec.BotUsername); err != nil {
+			slog.Warn("store bot_username failed", "tenant", tenantID, "err", err)
+		}
+	}
+	return &DeepLink{
+		TenantID:    tenantID,
+		BotUsername: rec.BotUsername,
+		URL:         deepLinkURL(rec.BotUsername, start),
+	}, 0, ""
+}
+
+func deepLinkURL(botUsername, start string) string {
+	u := "https://t.me/" + botUsername
+	if start != "" {
+		u += "?start=" + url.QueryEscape(start)
+	}
+	return u
+}
+
+// lookupBotUsername resolves the bot username via Telegram getMe.
+// Returns "" if Telegram is not configured, the token is empty, or the call fails.
+func (h *Handler) lookupBotUsername(ctx context.Context, tenantID, botToken string) string {
+	if h.tg == nil || botToken == "" {
+		return ""
+	}
+	username, err := h.tg.GetMe(ctx, botToken)
+	if err != nil {
+		slog.Warn("telegram getMe failed", "tenant", tenantID, "err", err)
+		return ""
+	}
+	return username
+}
@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLink(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:    "alice",
+		Status:      registry.StatusIdle,
+		BotUsername: "alice_bot",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/alice/link?start=onboard-42", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var link map[string]string
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&link))
+	assert.Equal(t, "alice_bot", link["bot_username"])
+	assert.Equal(t, "https://t.me/alice_bot?start=onboard-42", link["url"])
+}
+
+func TestGetLink_InvalidStartPayload(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", BotUsername: "alice_bot"})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/alice/link?start=has%20space", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetLink_UnknownUsername(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "bob"})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/bob/link", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestGetLinkQR(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", BotUsername: "alice_bot"})
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/alice/link/qr", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "\x89PNG", rec.Body.String()[:4])
+}
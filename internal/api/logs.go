@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+const (
+	defaultLogTail = 200
+	maxLogTail     = 10000
+	// maxLogLine bounds a single log line; the agent's are far shorter
+	maxLogLine = 1 << 20
+)
+
+// GetLogs streams a running tenant's agent container logs, so operators and
+// support staff can read them without cluster credentials:
+// GET /tenants/{tenantID}/logs?tail=200&follow=true. tail is the number of
+// past lines to start with (default 200); with follow the response stays
+// open and each new line is flushed as it is written. Lines are sent as
+// chunked text/plain, or as server-sent events (one data field per line)
+// when the client accepts text/event-stream.
+func (h *Handler) GetLogs(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "k8s not available in local mode", http.StatusServiceUnavailable)
+		return
+	}
+	tail := int64(defaultLogTail)
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 || n > maxLogTail {
+			http.Error(w, fmt.Sprintf("tail must be between 1 and %d", maxLogTail), http.StatusBadRequest)
+			return
+		}
+		tail = n
+	}
+	follow := false
+	if v := r.URL.Query().Get("follow"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "follow must be true or false", http.StatusBadRequest)
+			return
+		}
+		follow = b
+	}
+
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec.Status != registry.StatusRunning || rec.PodName == "" {
+		http.Error(w, "tenant not running", http.StatusConflict)
+		return
+	}
+	ns := rec.Namespace
+	if ns == "" {
+		ns = h.cfg.Namespace
+	}
+
+	logs, err := h.k8s.StreamPodLogs(ctx, rec.PodName, ns, tail, follow)
+	if err != nil {
+		slog.Error("stream logs failed", "tenant", tenantID, "pod", rec.PodName, "err", err)
+		http.Error(w, "logs unavailable", http.StatusBadGateway)
+		return
+	}
+	defer logs.Close()
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keep ingress-nginx from buffering a followed stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	rc := http.NewResponseController(w)
+	if follow {
+		// A followed stream outlives any server write timeout
+		rc.SetWriteDeadline(time.Time{})
+	}
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+	for scanner.Scan() {
+		var err error
+		if sse {
+			_, err = fmt.Fprintf(w, "data: %s\n\n", scanner.Bytes())
+		} else {
+			_, err = fmt.Fprintf(w, "%s\n", scanner.Bytes())
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return // the client went away
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		slog.Warn("stream logs interrupted", "tenant", tenantID, "pod", rec.PodName, "err", err)
+	}
+}
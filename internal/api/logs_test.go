@@ -0,0 +1,47 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogs(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice", Namespace: "tenants",
+	}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusIdle}))
+
+	// The fake clientset answers every log request with "fake logs"
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/logs?tail=50&follow=true", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "fake logs\n", w.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/alice/logs", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "data: fake logs\n\n", w.Body.String())
+
+	for path, code := range map[string]int{
+		"/tenants/alice/logs?tail=0":      http.StatusBadRequest,
+		"/tenants/alice/logs?follow=sure": http.StatusBadRequest,
+		"/tenants/bob/logs":               http.StatusConflict,
+		"/tenants/nobody/logs":            http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+}
@@ -0,0 +1,363 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/pkg/client"
+)
+
+// mediaType is a non-JSON body in a routeDoc
+type mediaType string
+
+const (
+	textPlain       mediaType = "text/plain"
+	imagePNG        mediaType = "image/png"
+	textEventStream mediaType = "text/event-stream"
+)
+
+// routeDoc describes a route for the OpenAPI document. Bodies are pkg/client
+// types, so the document and the client can't drift apart.
+type routeDoc struct {
+	id      string
+	summary string
+	query   []string // optional query parameters
+	body    any      // request body: a client type, or a mediaType
+	status  int      // success status; 0 means 200
+	resp    any      // success body: a client type, a mediaType or nil
+	also    map[int]any
+}
+
+// routeDocs documents every route of Router; OpenAPI fails on a route
+// missing here, or an entry for a route that's gone
+var routeDocs = map[string]routeDoc{
+	"GET /healthz":      {id: "healthz", summary: "Liveness check", resp: textPlain},
+	"GET /openapi.json": {id: "getOpenAPI", summary: "This document", resp: map[string]any{}},
+
+	"POST /tenants": {id: "createTenant", summary: "Create a tenant",
+		body: client.CreateTenantRequest{}, status: http.StatusCreated, resp: client.Tenant{}},
+	"POST /tenants/bulk": {id: "createTenants", summary: "Create several tenants, reporting each one's result",
+		body: []client.CreateTenantRequest{}, resp: client.BulkCreateResponse{}},
+	"POST /tenants/import": {id: "importTenant",
+		summary: "Create a tenant from a bundle exported by another cluster. Its state must already be copied under its prefix here: a state that doesn't match the bundle's manifest is refused with 409 unless allow_missing_state",
+		body:    client.ImportTenantRequest{}, status: http.StatusCreated, resp: client.ImportResult{}},
+	"GET /tenants": {id: "listTenants", summary: "List tenants",
+		query: []string{"sort", "order"}, resp: []client.Tenant{}},
+	"GET /tenants/{tenantID}": {id: "getTenant", summary: "Get a tenant", resp: client.Tenant{}},
+	"PATCH /tenants/{tenantID}": {id: "updateTenant", summary: "Update a tenant's settings",
+		body: client.UpdateTenantRequest{}, resp: client.Tenant{}},
+	"DELETE /tenants/{tenantID}": {id: "deleteTenant",
+		summary: "Delete a tenant, confirmed by the X-Confirm header or a confirm_token; dry_run=true returns the plan and a token instead. Its record, S3 state and bot token are kept for a restore until purged; purge=true purges them now",
+		query:   []string{"dry_run", "confirm_token", "purge"}, status: http.StatusNoContent,
+		also: map[int]any{http.StatusOK: client.DeletePlan{}}},
+	"POST /tenants/{tenantID}/restore":   {id: "restoreTenant", summary: "Bring back a deleted tenant before it is purged", resp: client.Tenant{}},
+	"GET /tenants/{tenantID}/webhook":    {id: "getWebhook", summary: "Get Telegram's view of the tenant's webhook", resp: client.WebhookInfo{}},
+	"DELETE /tenants/{tenantID}/webhook": {id: "deleteWebhook", summary: "Remove the tenant's Telegram webhook", status: http.StatusNoContent},
+	"GET /tenants/{tenantID}/link": {id: "getLink", summary: "Get a t.me deep link to the tenant's bot",
+		query: []string{"start"}, resp: client.DeepLink{}},
+	"GET /tenants/{tenantID}/link/qr": {id: "getLinkQR", summary: "Get the deep link as a QR code",
+		query: []string{"start", "size"}, resp: imagePNG},
+	"GET /tenants/{tenantID}/usage": {id: "getUsage", summary: "Get the tenant's daily usage from from to to (YYYY-MM-DD)",
+		query: []string{"from", "to"}, resp: client.UsageReport{}},
+	"GET /tenants/{tenantID}/wakes":  {id: "listWakes", summary: "List the tenant's recent wakes", resp: []client.WakeAttempt{}},
+	"GET /tenants/{tenantID}/events": {id: "listEvents", summary: "List the tenant's recent lifecycle events", resp: []client.TenantEvent{}},
+	"GET /tenants/{tenantID}/state":  {id: "getState", summary: "Get the size of the tenant's state against its quota", resp: client.TenantState{}},
+	"GET /tenants/{tenantID}/export": {id: "exportTenant",
+		summary: "Export the tenant's settings, key-value store and a manifest of its S3 state, without credentials, for POST /tenants/import in another cluster",
+		resp:    client.TenantBundle{}},
+	"POST /tenants/{tenantID}/snapshots": {id: "createSnapshot",
+		summary: "Copy the tenant's S3 state to a new snapshot; quiesce=true stops a running pod first. The oldest snapshots beyond the orchestrator's limit are deleted",
+		body:    client.SnapshotRequest{}, status: http.StatusCreated, resp: client.SnapshotResult{}},
+	"GET /tenants/{tenantID}/snapshots": {id: "listSnapshots", summary: "List the tenant's snapshots, newest first", resp: []client.Snapshot{}},
+	"POST /tenants/{tenantID}/snapshots/{snapshotID}/restore": {id: "restoreSnapshot",
+		summary: "Roll the tenant's state back to a snapshot, confirmed by the X-Confirm header. A running pod is stopped first, and the state replaced is kept as a new snapshot",
+		resp:    client.SnapshotRestoreResult{}},
+	"DELETE /tenants/{tenantID}/snapshots/{snapshotID}": {id: "deleteSnapshot", summary: "Delete one of the tenant's snapshots", status: http.StatusNoContent},
+	"GET /tenants/watch": {id: "watchTenants",
+		summary: "Stream tenant changes as server-sent events whose data is {\"type\", \"tenant\"}: the current tenants as added, then modified and deleted as the registry changes. tenant_id watches one tenant",
+		query:   []string{"tenant_id"}, resp: textEventStream},
+	"GET /tenants/{tenantID}/logs": {id: "getLogs",
+		summary: "Stream the tenant's agent logs from the last tail lines (default 200); follow=true keeps the stream open. Server-sent events when the client accepts text/event-stream",
+		query:   []string{"tail", "follow"}, resp: textPlain},
+	"POST /tenants/{tenantID}/exec": {id: "execTenant",
+		summary: "Open an exec session into the tenant's agent container: a SPDY upgrade (as made by kubectl exec) running the repeated command parameters, with stdin=true and tty=true to attach them",
+		query:   []string{"command", "stdin", "tty"}, status: http.StatusSwitchingProtocols},
+	"POST /tenants/{tenantID}/status-link": {id: "createStatusLink", summary: "Sign a link to the tenant's public status page",
+		body: client.StatusLinkRequest{}, resp: client.StatusLink{}},
+	"POST /tenants/{tenantID}/chat-link": {id: "createChatLink", summary: "Sign a link to the tenant's web chat page",
+		body: client.ChatLinkRequest{}, resp: client.ChatLink{}},
+	"POST /tenants/{tenantID}/restart": {id: "restartTenant", summary: "Replace the tenant's pod", resp: client.RestartResult{}},
+	"POST /tenants/{tenantID}/sleep":   {id: "sleepTenant", summary: "Stop the tenant's pod now", resp: client.SleepResult{}},
+	"POST /tenants/{tenantID}/suspend": {id: "suspendTenant", summary: "Stop the tenant's pod and refuse wakes", resp: client.SuspendResult{}},
+	"POST /tenants/{tenantID}/resume":  {id: "resumeTenant", summary: "Allow a suspended tenant to wake again", resp: client.SuspendResult{}},
+	"POST /tenants/{tenantID}/reset":   {id: "resetTenant", summary: "Clear a failed tenant's wake failures so it can wake again", resp: client.SuspendResult{}},
+	"POST /tenants/{tenantID}/test-message": {id: "sendTestMessage", summary: "Wake the tenant and return its agent's reply to a message, without Telegram",
+		body: client.TestMessageRequest{}, resp: client.TestMessageResult{}},
+	"GET /lookup/chat/{chatID}": {id: "lookupChat", summary: "List the tenants a Telegram chat has talked to, most recent first", resp: []client.ChatTenant{}},
+	"POST /images": {id: "putImage", summary: "Point an image alias at an image",
+		body: client.ImageAlias{}, resp: client.ImageAlias{}},
+	"GET /images":            {id: "listImages", summary: "List image aliases", resp: []client.ImageAlias{}},
+	"DELETE /images/{alias}": {id: "deleteImage", summary: "Delete an image alias", status: http.StatusNoContent},
+	"POST /admin/rollout": {id: "startRollout", summary: "Restart running tenants onto their current image",
+		body: client.StartRolloutRequest{}, status: http.StatusAccepted, resp: client.Rollout{}},
+	"GET /admin/rollout": {id: "getRollout", summary: "Get the latest rollout", resp: client.Rollout{}},
+	"POST /admin/killswitch": {id: "setKillSwitch", summary: "Turn the environment's kill switch on or off",
+		body: client.SetKillSwitchRequest{}, resp: client.KillSwitch{}},
+	"GET /admin/killswitch": {id: "getKillSwitch", summary: "Get the kill switch", resp: client.KillSwitch{}},
+	"POST /admin/broadcast": {id: "startBroadcast", summary: "Send an announcement to every tenant's owner",
+		body: client.StartBroadcastRequest{}, status: http.StatusAccepted, resp: client.Broadcast{}},
+	"GET /admin/broadcast": {id: "getBroadcast", summary: "Get the latest broadcast's delivery report", resp: client.Broadcast{}},
+
+	"GET /tenants/{tenantID}/bot_token": {id: "getBotToken", summary: "Get the tenant's bot token and webhook secret", resp: client.BotToken{}},
+	"GET /tenants/{tenantID}/slack":     {id: "getSlack", summary: "Get the tenant's Slack credentials", resp: client.SlackConfig{}},
+	"PUT /tenants/{tenantID}/webhook_secret": {id: "putWebhookSecret", summary: "Store the secret_token the tenant's webhook was registered with",
+		body: client.WebhookSecretRequest{}, status: http.StatusNoContent},
+	"PUT /tenants/{tenantID}/activity": {id: "updateActivity", summary: "Restart the tenant's idle clock and meter a delivered message",
+		body: client.Activity{}, status: http.StatusNoContent},
+	"POST /tenants/{tenantID}/outputs": {id: "saveOutput", summary: "Save a reply too long for a chat message",
+		body: textPlain, resp: client.Output{}},
+	"GET /tenants/{tenantID}/public-status": {id: "getPublicStatus", summary: "Get the data behind the tenant's public status page", resp: client.PublicStatus{}},
+	"POST /wake/{tenantID}": {id: "wake", summary: "Wake the tenant's pod; async=true returns a job to poll instead of waiting",
+		query: []string{"async"}, resp: client.WakeJob{}, also: map[int]any{http.StatusAccepted: client.WakeJob{}}},
+	"GET /wake-jobs/{jobID}": {id: "getWakeJob", summary: "Get an async wake", resp: client.WakeJob{}},
+
+	"GET /tenants/{tenantID}/kv":          {id: "listKV", summary: "List the tenant's key-value pairs", resp: client.KVList{}},
+	"GET /tenants/{tenantID}/kv/{key}":    {id: "getKV", summary: "Get a key", resp: client.KVPair{}},
+	"PUT /tenants/{tenantID}/kv/{key}":    {id: "putKV", summary: "Set a key", body: client.PutKVRequest{}, status: http.StatusNoContent},
+	"DELETE /tenants/{tenantID}/kv/{key}": {id: "deleteKV", summary: "Delete a key", status: http.StatusNoContent},
+}
+
+// unauthenticated routes need no API key
+var unauthenticated = map[string]bool{"GET /healthz": true, "GET /openapi.json": true}
+
+var pathParamRe = regexp.MustCompile(`\{(\w+)\}`)
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+	openAPIErr  error
+)
+
+// OpenAPI returns the OpenAPI 3 document of Router's routes. Routes
+// PublicRouter doesn't serve are tagged internal.
+func OpenAPI() ([]byte, error) {
+	openAPIOnce.Do(func() { openAPIDoc, openAPIErr = buildOpenAPI() })
+	return openAPIDoc, openAPIErr
+}
+
+// GetOpenAPI serves OpenAPI: GET /openapi.json
+func (h *Handler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	doc, err := OpenAPI()
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(doc)
+}
+
+// routeKeys returns "METHOD /path" of every route in r
+func routeKeys(r http.Handler) (map[string]bool, error) {
+	keys := map[string]bool{}
+	err := chi.Walk(r.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		keys[method+" "+strings.TrimSuffix(route, "/")] = true
+		return nil
+	})
+	return keys, err
+}
+
+func buildOpenAPI() ([]byte, error) {
+	h := &Handler{}
+	all, err := routeKeys(h.Router())
+	if err != nil {
+		return nil, err
+	}
+	public, err := routeKeys(h.PublicRouter())
+	if err != nil {
+		return nil, err
+	}
+	schemas := schemaSet{}
+	paths := map[string]map[string]any{}
+	for key := range all {
+		doc, ok := routeDocs[key]
+		if !ok {
+			return nil, fmt.Errorf("route %s has no routeDocs entry", key)
+		}
+		method, path, _ := strings.Cut(key, " ")
+		op := map[string]any{
+			"operationId": doc.id,
+			"summary":     doc.summary,
+			"tags":        []string{"management"},
+		}
+		if !public[key] {
+			op["tags"] = []string{"internal"}
+		}
+		if unauthenticated[key] {
+			op["security"] = []any{}
+		}
+		var params []any
+		for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+			params = append(params, map[string]any{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, q := range doc.query {
+			params = append(params, map[string]any{
+				"name": q, "in": "query", "schema": map[string]any{"type": "string"},
+			})
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if doc.body != nil {
+			op["requestBody"] = map[string]any{"required": true, "content": schemas.content(doc.body)}
+		}
+		status := doc.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		responses := map[string]any{
+			"default": map[string]any{
+				"description": "An error, explained in plain text",
+				"content":     schemas.content(textPlain),
+			},
+		}
+		responses[fmt.Sprint(status)] = response(status, doc.resp, schemas)
+		for code, body := range doc.also {
+			responses[fmt.Sprint(code)] = response(code, body, schemas)
+		}
+		op["responses"] = responses
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(method)] = op
+	}
+	for key := range routeDocs {
+		if !all[key] {
+			return nil, fmt.Errorf("routeDocs entry %s has no route", key)
+		}
+	}
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "agentic-tenancy orchestrator",
+			"version": "1",
+			"description": "The orchestrator's HTTP API. Named environments serve it under /env/{name}. " +
+				"Internal routes are served only on the internal listener when INTERNAL_PORT is set.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": APIKeyHeader},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearer": []string{}},
+			map[string]any{"apiKey": []string{}},
+		},
+	}, "", "  ")
+}
+
+func response(status int, body any, schemas schemaSet) map[string]any {
+	r := map[string]any{"description": http.StatusText(status)}
+	if body != nil {
+		r["content"] = schemas.content(body)
+	}
+	return r
+}
+
+// schemaSet holds the named schemas ("components") of the document
+type schemaSet map[string]any
+
+func (s schemaSet) content(body any) map[string]any {
+	if mt, ok := body.(mediaType); ok {
+		schema := map[string]any{"type": "string"}
+		if mt == imagePNG {
+			schema["format"] = "binary"
+		}
+		return map[string]any{string(mt): map[string]any{"schema": schema}}
+	}
+	return map[string]any{"application/json": map[string]any{"schema": s.schema(reflect.TypeOf(body))}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns t's JSON schema, naming structs in s
+func (s schemaSet) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		if _, ok := s[t.Name()]; !ok {
+			s[t.Name()] = nil // placeholder while the fields are walked
+			s[t.Name()] = s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	}
+	return map[string]any{}
+}
+
+// object is a struct's schema, with its fields as encoding/json names them.
+// Fields without omitempty are required.
+func (s schemaSet) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				walk(f.Type)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = s.schema(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	walk(t)
+	obj := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		sort.Strings(required)
+		obj["required"] = required
+	}
+	return obj
+}
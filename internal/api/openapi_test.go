@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/apikey"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestOpenAPI_Document(t *testing.T) {
+	doc, err := api.OpenAPI()
+	require.NoError(t, err, "every route needs a routeDocs entry")
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string              `json:"operationId"`
+			Tags        []string            `json:"tags"`
+			Security    []any               `json:"security"`
+			Responses   map[string]struct{} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(doc, &spec))
+
+	get := spec.Paths["/tenants/{tenantID}"]["get"]
+	assert.Equal(t, "getTenant", get.OperationID)
+	assert.Equal(t, []string{"management"}, get.Tags)
+	assert.Equal(t, []string{"internal"}, spec.Paths["/wake/{tenantID}"]["post"].Tags)
+	assert.Contains(t, spec.Paths["/wake/{tenantID}"]["post"].Responses, "202")
+	assert.NotNil(t, spec.Paths["/healthz"]["get"].Security, "health checks need no key")
+
+	// Tenants are encoded with their Go field names, and the document says so
+	assert.Contains(t, spec.Components.Schemas["Tenant"].Properties, "TenantID")
+	assert.Contains(t, spec.Components.Schemas["BotToken"].Properties, "BotToken")
+	assert.Contains(t, spec.Components.Schemas["UsageDay"].Properties, "pod_seconds", "embedded fields are flattened")
+}
+
+func TestOpenAPI_CommittedDocumentIsCurrent(t *testing.T) {
+	committed, err := os.ReadFile("../../docs/openapi.json")
+	require.NoError(t, err)
+	doc, err := api.OpenAPI()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(doc), string(committed), "run make openapi")
+}
+
+func TestOpenAPI_ServedWithoutKey(t *testing.T) {
+	keys, err := apikey.ParseStatic("ops=s3cret")
+	require.NoError(t, err)
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(registry.NewMock(), k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		APIKeys:      keys,
+	})
+
+	for _, router := range []http.Handler{h.Router(), h.PublicRouter()} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.True(t, json.Valid(w.Body.Bytes()))
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// outputMaxBytes is the longest reply kept: the most the router reads from
+// a pod
+const outputMaxBytes = 8 << 20
+
+// SaveOutput keeps a reply the router cut short under the tenant's S3
+// prefix and returns a link to it: POST /tenants/{tenantID}/outputs with
+// the full reply as the body
+func (h *Handler) SaveOutput(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	if h.cfg.Outputs == nil {
+		http.Error(w, "output storage not configured", http.StatusNotImplemented)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, outputMaxBytes))
+	if err != nil {
+		http.Error(w, "output over 8MB", http.StatusRequestEntityTooLarge)
+		return
+	}
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	url, expires, err := h.cfg.Outputs.Save(r.Context(), rec.S3Prefix, body)
+	if err != nil {
+		slog.Error("save output failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("output saved", "tenant", tenantID, "bytes", len(body))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"url": url, "expires_at": expires.UTC().Format(time.RFC3339)})
+}
@@ -0,0 +1,77 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/outputs"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type memOutputs map[string][]byte
+
+func (m memOutputs) Save(_ context.Context, prefix string, body []byte) (string, time.Time, error) {
+	m[prefix] = body
+	return "https://s3.example/" + prefix, time.Now().Add(outputs.LinkTTL), nil
+}
+
+// TestSaveOutput: a reply is kept under the tenant's prefix and linked, and
+// refused without output storage
+func TestSaveOutput(t *testing.T) {
+	store := memOutputs{}
+	newHandler := func(store outputs.Store) (*api.Handler, *registry.MockClient) {
+		reg := registry.NewMock()
+		k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{ZeroClawImage: "zeroclaw:test", S3Bucket: "test-bucket"})
+		return api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{Namespace: "tenants", Outputs: store}), reg
+	}
+	save := func(h *api.Handler, tenantID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/"+tenantID+"/outputs", bytes.NewReader([]byte("the full reply"))))
+		return rec
+	}
+
+	h, reg := newHandler(store)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", S3Prefix: "tenants/alice/", Status: registry.StatusIdle})
+	rec := save(h, "alice")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "https://s3.example/tenants/alice/", resp.URL)
+	assert.WithinDuration(t, time.Now().Add(outputs.LinkTTL), resp.ExpiresAt, time.Minute)
+	assert.Equal(t, "the full reply", string(store["tenants/alice/"]))
+
+	assert.Equal(t, http.StatusNotFound, save(h, "bob").Code)
+	h, _ = newHandler(nil)
+	assert.Equal(t, http.StatusNotImplemented, save(h, "alice").Code)
+}
+
+// TestUpdateTenant_MaxReplyBytes: the override is stored, -1 allows any
+// length, and anything below is refused
+func TestUpdateTenant_MaxReplyBytes(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+	patch := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/tenants/alice", bytes.NewReader([]byte(body))))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"max_reply_bytes":-2}`))
+	require.Equal(t, http.StatusOK, patch(`{"max_reply_bytes":50000}`))
+	tenant, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, int64(50000), tenant.MaxReplyBytes)
+}
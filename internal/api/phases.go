@@ -0,0 +1,27 @@
+package api
+
+import "time"
+
+// phaseTimer records how long each step of a multi-step operation took
+type phaseTimer struct {
+	start time.Time
+	last  time.Time
+	kv    []any
+}
+
+func newPhaseTimer() *phaseTimer {
+	now := time.Now()
+	return &phaseTimer{start: now, last: now}
+}
+
+// lap closes the current phase under name and starts the next one
+func (t *phaseTimer) lap(name string) {
+	now := time.Now()
+	t.kv = append(t.kv, name+"_ms", now.Sub(t.last).Milliseconds())
+	t.last = now
+}
+
+// attrs returns the recorded phases plus the total as slog key/value pairs
+func (t *phaseTimer) attrs() []any {
+	return append(t.kv, "total_ms", t.last.Sub(t.start).Milliseconds())
+}
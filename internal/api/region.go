@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// homeRegionHeader tells the router which region owns a misdirected tenant
+const homeRegionHeader = "X-Home-Region"
+
+// MisdirectedError is returned when a tenant is homed in another region. Each
+// tenant has a single home pod, run by its home region's orchestrator; other
+// regions must hand the request over instead of starting a second pod.
+type MisdirectedError struct {
+	HomeRegion string
+}
+
+func (e *MisdirectedError) Error() string {
+	return fmt.Sprintf("tenant is homed in region %s", e.HomeRegion)
+}
+
+// checkHome returns a MisdirectedError if rec belongs to another region.
+// Single-region deployments (no Region configured) and tenants without a
+// home region are always local.
+func (h *Handler) checkHome(rec *registry.TenantRecord) error {
+	if h.cfg.Region == "" || rec == nil || rec.HomeRegion == "" || rec.HomeRegion == h.cfg.Region {
+		return nil
+	}
+	return &MisdirectedError{HomeRegion: rec.HomeRegion}
+}
+
+// writeMisdirected answers 421 with the tenant's home region in a header
+func writeMisdirected(w http.ResponseWriter, e *MisdirectedError) {
+	w.Header().Set(homeRegionHeader, e.HomeRegion)
+	http.Error(w, e.Error(), http.StatusMisdirectedRequest)
+}
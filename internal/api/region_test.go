@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newRegionHandler(region string) (*api.Handler, *registry.MockClient, *fake.Clientset) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test", S3Bucket: "test-bucket"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		Region:       region,
+	})
+	return h, reg, cs
+}
+
+func TestCreateTenant_HomesInLocalRegion(t *testing.T) {
+	h, reg, _ := newRegionHandler("us-east-1")
+
+	body, _ := json.Marshal(map[string]interface{}{"tenant_id": "alice"})
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	tenant, err := reg.GetTenant(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", tenant.HomeRegion)
+}
+
+func TestWake_RemoteHomeIsMisdirected(t *testing.T) {
+	h, reg, cs := newRegionHandler("us-east-1")
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "bob",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+		HomeRegion:   "eu-west-1",
+	}))
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/bob", nil))
+	assert.Equal(t, http.StatusMisdirectedRequest, rec.Code)
+	assert.Equal(t, "eu-west-1", rec.Header().Get("X-Home-Region"))
+
+	// No pod may be started outside the home region
+	pods, err := cs.CoreV1().Pods("tenants").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pods.Items)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// beginResize records a tier change as a resize operation. With now set, a
+// running keep-warm tenant is moved to the new tier's resources right away
+// by a blue/green replacement in the background; every other tenant picks
+// them up with its next pod (next wake, restart or rollout).
+func (h *Handler) beginResize(ctx context.Context, rec *registry.TenantRecord, tier string, now bool) {
+	at := time.Now().UTC()
+	op := &registry.ResizeOp{
+		FromTier:    rec.Tier,
+		ToTier:      tier,
+		Status:      registry.ResizePending,
+		RequestedAt: at,
+		UpdatedAt:   at,
+	}
+	live := now && h.k8s != nil && rec.KeepWarm &&
+		rec.Status == registry.StatusRunning && rec.PodName != "" && h.checkHome(rec) == nil
+	if live {
+		op.Status = registry.ResizeReplacing
+	}
+	if err := h.reg.UpdateResize(ctx, rec.TenantID, op); err != nil {
+		slog.Warn("resize: record operation failed", "tenant", rec.TenantID, "err", err)
+		return
+	}
+	slog.Info("resize: tier changed", "tenant", rec.TenantID, "from", op.FromTier, "to", op.ToTier, "status", op.Status)
+	if live {
+		go h.runResize(rec.TenantID, *op)
+	}
+}
+
+// runResize replaces a keep-warm tenant's pod under its wake lock, exactly
+// like POST /tenants/{id}/restart, and records the outcome on op
+func (h *Handler) runResize(tenantID string, op registry.ResizeOp) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.rolloutStaleAfter())
+	defer cancel()
+
+	fail := func(msg string) {
+		op.Status, op.Error, op.UpdatedAt = registry.ResizeFailed, msg, time.Now().UTC()
+		if err := h.reg.UpdateResize(context.WithoutCancel(ctx), tenantID, &op); err != nil {
+			slog.Warn("resize: record operation failed", "tenant", tenantID, "err", err)
+		}
+	}
+
+	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, h.cfg.WakeLockTTL)
+	if err != nil {
+		slog.Error("resize: acquire wake lock failed", "tenant", tenantID, "err", err)
+		fail(err.Error())
+		return
+	}
+	if !acquired {
+		slog.Warn("resize: wake or restart in progress, not replacing", "tenant", tenantID)
+		fail("wake or restart already in progress")
+		return
+	}
+	defer h.lock.ReleaseWakeLock(context.WithoutCancel(ctx), tenantID)
+
+	// The pod may have gone away since the update, or the tier changed again
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	if rec == nil || rec.Resize == nil || !rec.Resize.RequestedAt.Equal(op.RequestedAt) {
+		return
+	}
+	if rec.Status != registry.StatusRunning || rec.PodName == "" {
+		op.Status, op.UpdatedAt = registry.ResizePending, time.Now().UTC()
+		if err := h.reg.UpdateResize(ctx, tenantID, &op); err != nil {
+			slog.Warn("resize: record operation failed", "tenant", tenantID, "err", err)
+		}
+		return
+	}
+	if _, err := h.replacePod(ctx, rec); err != nil {
+		slog.Error("resize: replacement failed, old pod kept", "tenant", tenantID, "pod", rec.PodName, "err", err)
+		fail(err.Error())
+	}
+}
+
+// completeResize marks the tenant's resize done once a pod with the new
+// tier's resources is running. A failed live resize also completes here,
+// when a later wake or restart applies the tier after all.
+func (h *Handler) completeResize(ctx context.Context, rec *registry.TenantRecord) {
+	op := rec.Resize
+	if op == nil || op.Status == registry.ResizeDone || op.ToTier != rec.Tier {
+		return
+	}
+	done := *op
+	done.Status, done.Error, done.UpdatedAt = registry.ResizeDone, "", time.Now().UTC()
+	if err := h.reg.UpdateResize(ctx, rec.TenantID, &done); err != nil {
+		slog.Warn("resize: record operation failed", "tenant", rec.TenantID, "err", err)
+		return
+	}
+	rec.Resize = &done
+	slog.Info("resize: done", "tenant", rec.TenantID, "from", done.FromTier, "to", done.ToTier)
+}
@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func patchTenant(h *api.Handler, tenantID, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/tenants/"+tenantID, strings.NewReader(body)))
+	return rec
+}
+
+// TestResize_KeepWarmLive: a running keep-warm tenant is replaced blue/green
+// on the new tier when the update asks for it
+func TestResize_KeepWarmLive(t *testing.T) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	// The tier shows in the pod's grace period
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test",
+		Grace: k8sclient.GracePolicy{TierIdleS: map[string]int64{"premium": 120}}})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		RestartDrain: time.Millisecond,
+	})
+	ctx := context.Background()
+
+	_, err := cs.CoreV1().Pods("tenants").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:  "alice",
+		Status:    registry.StatusRunning,
+		PodName:   "zeroclaw-alice",
+		PodIP:     "10.0.0.1",
+		Namespace: "tenants",
+		Tier:      "standard",
+		KeepWarm:  true,
+	}))
+	simulateReplacementReady(cs, "tenants", "10.0.0.2")
+
+	rec := patchTenant(h, "alice", `{"tier":"premium","resize":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var got registry.TenantRecord
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.NotNil(t, got.Resize)
+	assert.Equal(t, "standard", got.Resize.FromTier)
+	assert.Equal(t, "premium", got.Resize.ToTier)
+
+	require.Eventually(t, func() bool {
+		alice, _ := reg.GetTenant(ctx, "alice")
+		return alice.Resize.Status != registry.ResizeReplacing
+	}, 5*time.Second, 50*time.Millisecond)
+	alice, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.ResizeDone, alice.Resize.Status, alice.Resize.Error)
+	assert.NotEqual(t, "zeroclaw-alice", alice.PodName)
+	assert.Equal(t, "10.0.0.2", alice.PodIP)
+	pod, err := cs.CoreV1().Pods("tenants").Get(ctx, alice.PodName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), *pod.Spec.TerminationGracePeriodSeconds)
+}
+
+// TestResize_AtNextWake: without a keep-warm pod the resize waits for the
+// tenant's next wake
+func TestResize_AtNextWake(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:  "bob",
+		Status:    registry.StatusIdle,
+		Namespace: "tenants",
+		Tier:      "standard",
+	}))
+
+	rec := patchTenant(h, "bob", `{"tier":"premium","resize":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	bob, _ := reg.GetTenant(ctx, "bob")
+	require.NotNil(t, bob.Resize)
+	assert.Equal(t, registry.ResizePending, bob.Resize.Status)
+
+	simulatePodReady(cs, "bob", "tenants", "10.0.0.3")
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/bob", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	bob, _ = reg.GetTenant(ctx, "bob")
+	assert.Equal(t, registry.ResizeDone, bob.Resize.Status)
+}
+
+func TestResize_RequiresTierChange(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "carol", Tier: "standard"}))
+
+	assert.Equal(t, http.StatusBadRequest, patchTenant(h, "carol", `{"resize":true}`).Code)
+
+	rec := patchTenant(h, "carol", `{"tier":"standard"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	carol, _ := reg.GetTenant(context.Background(), "carol")
+	assert.Nil(t, carol.Resize, "an unchanged tier is not a resize")
+}
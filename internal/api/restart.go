@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// switchEndpointScript repoints a cached router endpoint in place, keeping the
+// entry's TTL. A missing entry is left missing: the router's next message
+// wakes through the orchestrator and reads the new pod from the registry.
+var switchEndpointScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+end
+return 0
+`)
+
+// RestartResult is the response of POST /tenants/{tenantID}/restart
+type RestartResult struct {
+	TenantID    string `json:"tenant_id"`
+	PodName     string `json:"pod_name"`
+	PodIP       string `json:"pod_ip"`
+	PreviousPod string `json:"previous_pod"`
+}
+
+// RestartTenant replaces a running tenant's pod blue/green: start the new pod
+// alongside the old one, wait until it is ready, switch the registry and the
+// router's endpoint cache to it, then drain and delete the old pod. Messages
+// keep flowing to the old pod until the switch, so config and image changes
+// roll out without message loss.
+func (h *Handler) RestartTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "k8s not available in local mode", http.StatusServiceUnavailable)
+		return
+	}
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec.Status != registry.StatusRunning || rec.PodName == "" {
+		http.Error(w, "tenant not running; changes apply on next wake", http.StatusConflict)
+		return
+	}
+
+	// The wake lock also serialises restarts against wakes and each other
+	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, h.cfg.WakeLockTTL)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !acquired {
+		http.Error(w, "wake or restart already in progress", http.StatusConflict)
+		return
+	}
+	defer h.lock.ReleaseWakeLock(ctx, tenantID)
+
+	result, err := h.replacePod(ctx, rec)
+	if err != nil {
+		slog.Error("restart failed, old pod kept", "tenant", tenantID, "pod", rec.PodName, "err", err)
+		http.Error(w, "restart failed: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *Handler) replacePod(ctx context.Context, rec *registry.TenantRecord) (*RestartResult, error) {
+	ns := rec.Namespace
+	if ns == "" {
+		ns = h.cfg.Namespace
+	}
+	image, err := h.resolveImage(ctx, rec.Image)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.k8s.EnsureTenantVolume(ctx, rec.TenantID, ns, rec.S3Prefix); err != nil {
+		return nil, err
+	}
+	serviceAccount, err := h.tenantServiceAccount(ctx, rec, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.botToken(ctx, rec)
+	if err != nil {
+		return nil, err
+	}
+	newName := k8sclient.ReplacementPodName(rec.TenantID)
+	slog.Info("restart: starting replacement pod", "tenant", rec.TenantID, "old_pod", rec.PodName, "new_pod", newName)
+	if _, err := h.k8s.CreateTenantPod(ctx, rec.TenantID, ns, contract.PVCName(rec.TenantID), token, k8sclient.TenantPodOptions{
+		Tier:           rec.Tier,
+		Image:          image,
+		DNS:            (*k8sclient.DNSSettings)(rec.DNS),
+		PodName:        newName,
+		StateReadOnly:  h.stateReadOnly(rec),
+		KV:             h.kvAccess(rec.TenantID),
+		ServiceAccount: serviceAccount,
+	}); err != nil {
+		return nil, err
+	}
+	readyBy := time.Now().Add(h.cfg.PodReadyWait)
+	podIP, err := h.k8s.WaitNamedPodReady(ctx, newName, ns, h.cfg.PodReadyWait)
+	if err != nil {
+		h.recordRestoreFailure(ctx, rec.TenantID, err)
+		_ = h.k8s.DeletePod(ctx, newName, ns, 0)
+		return nil, err
+	}
+	// The old pod keeps serving while the new one restores its state
+	h.waitAgentRestored(ctx, rec.TenantID, podIP, readyBy, nil)
+
+	// Switch: registry first so a router cache miss already resolves to the
+	// new pod, then the cached endpoint itself.
+	if err := h.reg.UpdateStatus(ctx, rec.TenantID, registry.StatusRunning, newName, podIP); err != nil {
+		_ = h.k8s.DeletePod(ctx, newName, ns, 0)
+		return nil, err
+	}
+	if h.rdb != nil {
+		key := h.redisKey(contract.EndpointPrefix, rec.TenantID)
+		if err := switchEndpointScript.Run(ctx, h.rdb, []string{key}, contract.EndpointPodIP, podIP).Err(); err != nil {
+			slog.Warn("restart: switch endpoint cache failed, clearing it", "tenant", rec.TenantID, "err", err)
+			h.rdb.Del(ctx, key)
+		}
+	}
+
+	// Let requests already forwarded to the old pod finish before SIGTERM
+	select {
+	case <-ctx.Done():
+	case <-time.After(h.cfg.RestartDrain):
+	}
+	grace := h.k8s.GracePeriod(k8sclient.OpIdle, rec.Tier)
+	if err := h.k8s.DeletePod(context.WithoutCancel(ctx), rec.PodName, ns, grace); err != nil {
+		slog.Error("restart: delete old pod failed", "tenant", rec.TenantID, "pod", rec.PodName, "err", err)
+	}
+	slog.Info("restart: switched to replacement pod", "tenant", rec.TenantID, "pod", newName, "pod_ip", podIP)
+	h.completeResize(context.WithoutCancel(ctx), rec)
+
+	return &RestartResult{
+		TenantID:    rec.TenantID,
+		PodName:     newName,
+		PodIP:       podIP,
+		PreviousPod: rec.PodName,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// simulateReplacementReady marks the first non-running tenant pod as Running
+func simulateReplacementReady(cs *fake.Clientset, namespace, ip string) {
+	go func() {
+		for i := 0; i < 50; i++ {
+			time.Sleep(100 * time.Millisecond)
+			pods, err := cs.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				continue
+			}
+			for _, pod := range pods.Items {
+				if pod.Status.Phase != corev1.PodRunning {
+					pod.Status.Phase = corev1.PodRunning
+					pod.Status.PodIP = ip
+					cs.CoreV1().Pods(namespace).UpdateStatus(context.Background(), &pod, metav1.UpdateOptions{})
+					return
+				}
+			}
+		}
+	}()
+}
+
+func TestRestartTenant_BlueGreen(t *testing.T) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		RestartDrain: time.Millisecond,
+	})
+	ctx := context.Background()
+
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+	_, err := cs.CoreV1().Pods("tenants").Create(ctx, oldPod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:  "alice",
+		Status:    registry.StatusRunning,
+		PodName:   "zeroclaw-alice",
+		PodIP:     "10.0.0.1",
+		Namespace: "tenants",
+		KeepWarm:  true,
+	})
+	simulateReplacementReady(cs, "tenants", "10.0.0.2")
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/alice/restart", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var result api.RestartResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.Equal(t, "zeroclaw-alice", result.PreviousPod)
+	assert.NotEqual(t, "zeroclaw-alice", result.PodName)
+	assert.Equal(t, "10.0.0.2", result.PodIP)
+
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, result.PodName, tenant.PodName)
+	assert.Equal(t, "10.0.0.2", tenant.PodIP)
+
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	assert.Error(t, err, "old pod should be deleted after the switch")
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, result.PodName, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestRestartTenant_NotRunning(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle})
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/alice/restart", nil)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// restorePollInterval is how often a wake asks the agent whether its state
+// is restored
+var restorePollInterval = time.Second
+
+// RestoreStatus is the agent's answer to GET /restore-status
+type RestoreStatus = contract.AgentRestoreStatus
+
+// waitAgentRestored polls the agent in a running pod until it reports its
+// state restored, calling onRestore (if set) with each answer that isn't
+// ready yet. Agents without the endpoint (404) count as ready, as they load
+// their state before listening. At deadline the agent is taken as ready
+// anyway: the router retries forwards, and a stuck restore shouldn't fail
+// every wake.
+func (h *Handler) waitAgentRestored(ctx context.Context, tenantID, podIP string, deadline time.Time, onRestore func(RestoreStatus)) {
+	if !h.cfg.WaitAgentRestore {
+		return
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	for {
+		status, err := h.agentRestoreStatus(ctx, podIP)
+		switch {
+		case err == nil && status.Ready:
+			return
+		case err == nil && onRestore != nil:
+			onRestore(*status)
+		case err != nil:
+			slog.Debug("restore status unavailable", "tenant", tenantID, "pod_ip", podIP, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Warn("agent did not report its state restored, treating it as ready", "tenant", tenantID, "pod_ip", podIP)
+			return
+		case <-time.After(restorePollInterval):
+		}
+	}
+}
+
+// agentRestoreStatus asks the agent for its restore status; an error means
+// it isn't answering yet
+func (h *Handler) agentRestoreStatus(ctx context.Context, podIP string) (*RestoreStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, contract.AgentURL(podIP, contract.AgentRestoreStatusPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(contract.AgentVersionHeader, contract.AgentVersion)
+	client := h.cfg.PodClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return &RestoreStatus{Ready: true}, nil
+	default:
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var status RestoreStatus
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&status); err != nil {
+		return nil, fmt.Errorf("unreadable restore status: %w", err)
+	}
+	return &status, nil
+}
+
+// recordRestoreFailure adds a state_restore_failed event to the tenant's log
+// if err is a pod's failed state restore (see k8sclient.StateSyncPolicy)
+func (h *Handler) recordRestoreFailure(ctx context.Context, tenantID string, err error) {
+	var restoreErr *k8sclient.StateRestoreError
+	if !errors.As(err, &restoreErr) {
+		return
+	}
+	ev := registry.TenantEvent{
+		Time:    time.Now().UTC(),
+		Kind:    registry.EventStateRestoreFailed,
+		Pod:     restoreErr.Pod,
+		Message: restoreErr.Message,
+	}
+	if err := h.reg.RecordEvent(ctx, tenantID, ev, h.cfg.EventHistory); err != nil {
+		slog.Warn("record state restore failure", "tenant", tenantID, "err", err)
+	}
+}
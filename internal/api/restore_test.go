@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestWake_WaitsForAgentRestore: an async wake reports the agent's restore
+// progress, and the tenant is running only once the agent is ready
+func TestWake_WaitsForAgentRestore(t *testing.T) {
+	var checks atomic.Int32
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/restore-status", r.URL.Path)
+		if checks.Add(1) < 3 {
+			json.NewEncoder(w).Encode(api.RestoreStatus{Progress: 40, Message: "loading memory"})
+			return
+		}
+		json.NewEncoder(w).Encode(api.RestoreStatus{Ready: true})
+	}))
+	defer agent.Close()
+	agentURL, _ := url.Parse(agent.URL)
+
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:        "tenants",
+		PodReadyWait:     10 * time.Second,
+		PodClient:        &http.Client{Transport: &agentTransport{agent: agentURL}},
+		WaitAgentRestore: true,
+	})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants"}))
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.7")
+
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wake/alice?async=true", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var job api.WakeJob
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+
+	poll := func() api.WakeJob {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/wake-jobs/"+job.ID, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var got api.WakeJob
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		return got
+	}
+	require.Eventually(t, func() bool { return poll().Status == api.WakeJobRestoring }, 5*time.Second, 20*time.Millisecond)
+	restoring := poll()
+	assert.Equal(t, 40, restoring.RestoreProgress)
+	assert.Equal(t, "loading memory", restoring.RestoreMessage)
+	rec, _ := reg.GetTenant(ctx, "alice")
+	assert.NotEqual(t, registry.StatusRunning, rec.Status, "not running until the agent is ready")
+
+	require.Eventually(t, func() bool { return poll().Status == api.WakeJobReady }, 5*time.Second, 20*time.Millisecond)
+	ready := poll()
+	assert.Equal(t, "10.0.0.7", ready.PodIP)
+	assert.Zero(t, ready.RestoreProgress)
+	rec, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusRunning, rec.Status)
+}
+
+// TestWake_AgentWithoutRestoreStatus: agents that don't serve
+// /restore-status are ready as soon as their pod runs
+func TestWake_AgentWithoutRestoreStatus(t *testing.T) {
+	agent := httptest.NewServer(http.NotFoundHandler())
+	defer agent.Close()
+	agentURL, _ := url.Parse(agent.URL)
+
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:        "tenants",
+		PodReadyWait:     10 * time.Second,
+		PodClient:        &http.Client{Transport: &agentTransport{agent: agentURL}},
+		WaitAgentRestore: true,
+	})
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants"}))
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.7")
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+const (
+	rolloutKey = "rollout:current"
+	// rolloutTTL is how long a finished rollout can still be read
+	rolloutTTL = 24 * time.Hour
+)
+
+// RolloutStatus is the state of an image rollout
+type RolloutStatus string
+
+const (
+	RolloutRunning RolloutStatus = "running"
+	RolloutDone    RolloutStatus = "done"   // every outdated pod was replaced (or skipped)
+	RolloutFailed  RolloutStatus = "failed" // stopped at the first failed replacement
+)
+
+// Rollout is a rolling restart of running tenants onto their current image,
+// as returned by POST /admin/rollout and GET /admin/rollout
+type Rollout struct {
+	ID             string        `json:"rollout_id"`
+	Status         RolloutStatus `json:"status"`
+	MaxUnavailable int           `json:"max_unavailable"`
+	// Total is the number of running tenants whose pod runs an outdated image
+	Total     int       `json:"total"`
+	Restarted []string  `json:"restarted"`
+	Skipped   []string  `json:"skipped,omitempty"` // a wake or restart held the tenant, or it stopped meanwhile
+	Failed    []string  `json:"failed,omitempty"`
+	Error     string    `json:"error,omitempty"` // the failure that stopped the rollout
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// rolloutMemory holds the rollout when there is no Redis (local mode)
+type rolloutMemory struct {
+	mu      sync.Mutex
+	current *Rollout
+}
+
+// rolloutStartRequest is the optional body of POST /admin/rollout
+type rolloutStartRequest struct {
+	// MaxUnavailable is how many pods are replaced at once (default
+	// Config.RolloutMaxUnavailable)
+	MaxUnavailable int `json:"max_unavailable"`
+}
+
+// StartRollout replaces, blue/green, every running pod whose image isn't the
+// one its tenant would get on a fresh wake (its pin, else the default channel,
+// else ZEROCLAW_IMAGE). Pods are replaced max_unavailable at a time in the
+// background; the answer is 202 with the rollout to poll at GET
+// /admin/rollout. Only one rollout runs at a time.
+func (h *Handler) StartRollout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "k8s not available in local mode", http.StatusServiceUnavailable)
+		return
+	}
+	var req rolloutStartRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.MaxUnavailable < 0 {
+		http.Error(w, "max_unavailable must be positive", http.StatusBadRequest)
+		return
+	}
+	if req.MaxUnavailable == 0 {
+		req.MaxUnavailable = h.cfg.RolloutMaxUnavailable
+	}
+
+	current, err := h.loadRollout(ctx)
+	if err != nil {
+		slog.Error("load rollout", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	// A rollout whose replica died stops being updated; don't wait on it forever
+	if current != nil && current.Status == RolloutRunning && time.Since(current.UpdatedAt) < h.rolloutStaleAfter() {
+		http.Error(w, "rollout "+current.ID+" already running", http.StatusConflict)
+		return
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	now := time.Now().UTC()
+	ro := Rollout{ID: hex.EncodeToString(b), Status: RolloutRunning, MaxUnavailable: req.MaxUnavailable,
+		Restarted: []string{}, StartedAt: now, UpdatedAt: now}
+	if err := h.saveRollout(ctx, ro); err != nil {
+		slog.Error("save rollout", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	go h.runRollout(ro)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(ro)
+}
+
+// GetRollout reports the current or last rollout's progress
+func (h *Handler) GetRollout(w http.ResponseWriter, r *http.Request) {
+	ro, err := h.loadRollout(r.Context())
+	if err != nil {
+		slog.Error("load rollout", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if ro == nil {
+		http.Error(w, "no rollout", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ro)
+}
+
+// rolloutStaleAfter is how long a running rollout may go without progress:
+// one replacement's wake lock, readiness wait and drain
+func (h *Handler) rolloutStaleAfter() time.Duration {
+	return h.cfg.WakeLockTTL + h.cfg.PodReadyWait + h.cfg.RestartDrain
+}
+
+// runRollout replaces outdated pods and records progress after each one
+func (h *Handler) runRollout(ro Rollout) {
+	ctx := context.Background()
+	targets, err := h.outdatedTenants(ctx)
+	if err != nil {
+		slog.Error("rollout: list tenants failed", "rollout", ro.ID, "err", err)
+		ro.Status, ro.Error = RolloutFailed, err.Error()
+		ro.UpdatedAt = time.Now().UTC()
+		h.saveRolloutLogged(ctx, ro)
+		return
+	}
+	ro.Total = len(targets)
+	ro.UpdatedAt = time.Now().UTC()
+	h.saveRolloutLogged(ctx, ro)
+	slog.Info("rollout: starting", "rollout", ro.ID, "outdated", len(targets), "max_unavailable", ro.MaxUnavailable)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		stopped bool
+	)
+	sem := make(chan struct{}, ro.MaxUnavailable)
+	for _, rec := range targets {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := stopped
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(rec *registry.TenantRecord) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			restarted, err := h.rolloutTenant(ctx, rec)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				slog.Error("rollout: restart failed, stopping", "rollout", ro.ID, "tenant", rec.TenantID, "err", err)
+				ro.Failed = append(ro.Failed, rec.TenantID)
+				if !stopped {
+					ro.Error = rec.TenantID + ": " + err.Error()
+				}
+				stopped = true
+			case restarted:
+				slog.Info("rollout: tenant restarted", "rollout", ro.ID, "tenant", rec.TenantID)
+				ro.Restarted = append(ro.Restarted, rec.TenantID)
+			default:
+				ro.Skipped = append(ro.Skipped, rec.TenantID)
+			}
+			ro.UpdatedAt = time.Now().UTC()
+			h.saveRolloutLogged(ctx, ro)
+		}(rec)
+	}
+	wg.Wait()
+
+	ro.Status = RolloutDone
+	if stopped {
+		ro.Status = RolloutFailed
+	}
+	ro.UpdatedAt = time.Now().UTC()
+	h.saveRolloutLogged(ctx, ro)
+	slog.Info("rollout: finished", "rollout", ro.ID, "status", ro.Status,
+		"restarted", len(ro.Restarted), "skipped", len(ro.Skipped), "failed", len(ro.Failed))
+}
+
+// outdatedTenants returns the running tenants homed here whose pod runs
+// another image than they would get on a fresh wake
+func (h *Handler) outdatedTenants(ctx context.Context) ([]*registry.TenantRecord, error) {
+	running, err := h.reg.ListByStatus(ctx, registry.StatusRunning)
+	if err != nil {
+		return nil, err
+	}
+	var out []*registry.TenantRecord
+	for _, rec := range running {
+		if rec.PodName == "" || h.checkHome(rec) != nil {
+			continue
+		}
+		image, err := h.resolveImage(ctx, rec.Image)
+		if err != nil {
+			return nil, err
+		}
+		ns := rec.Namespace
+		if ns == "" {
+			ns = h.cfg.Namespace
+		}
+		pod, err := h.k8s.GetPod(ctx, rec.PodName, ns)
+		if err != nil {
+			return nil, err
+		}
+		if pod != nil && !h.k8s.RunsImage(pod, image) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// rolloutTenant restarts one tenant under its wake lock, exactly like
+// POST /tenants/{id}/restart. It reports false, without an error, if the
+// tenant was busy or has stopped since the rollout listed it.
+func (h *Handler) rolloutTenant(ctx context.Context, rec *registry.TenantRecord) (bool, error) {
+	acquired, err := h.lock.AcquireWakeLock(ctx, rec.TenantID, h.cfg.WakeLockTTL)
+	if err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer h.lock.ReleaseWakeLock(ctx, rec.TenantID)
+
+	// The pod may have gone idle (or been restarted) since it was listed
+	rec, err = h.reg.GetTenant(ctx, rec.TenantID)
+	if err != nil {
+		return false, err
+	}
+	if rec == nil || rec.Status != registry.StatusRunning || rec.PodName == "" {
+		return false, nil
+	}
+	if _, err := h.replacePod(ctx, rec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *Handler) saveRolloutLogged(ctx context.Context, ro Rollout) {
+	if err := h.saveRollout(ctx, ro); err != nil {
+		slog.Warn("save rollout", "rollout", ro.ID, "err", err)
+	}
+}
+
+// saveRollout stores the rollout in Redis, so any replica can answer polls
+func (h *Handler) saveRollout(ctx context.Context, ro Rollout) error {
+	if h.rdb == nil {
+		h.rollouts.mu.Lock()
+		defer h.rollouts.mu.Unlock()
+		h.rollouts.current = &ro
+		return nil
+	}
+	data, err := json.Marshal(ro)
+	if err != nil {
+		return err
+	}
+	return h.rdb.Set(ctx, h.redisKey(rolloutKey, ""), data, rolloutTTL).Err()
+}
+
+// loadRollout returns nil if no rollout ran within rolloutTTL
+func (h *Handler) loadRollout(ctx context.Context) (*Rollout, error) {
+	if h.rdb == nil {
+		h.rollouts.mu.Lock()
+		defer h.rollouts.mu.Unlock()
+		if h.rollouts.current == nil {
+			return nil, nil
+		}
+		ro := *h.rollouts.current
+		return &ro, nil
+	}
+	data, err := h.rdb.Get(ctx, h.redisKey(rolloutKey, "")).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ro Rollout
+	if err := json.Unmarshal(data, &ro); err != nil {
+		return nil, err
+	}
+	return &ro, nil
+}
@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestRollout: only pods on an outdated image are replaced
+func TestRollout(t *testing.T) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:v2"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		RestartDrain: time.Millisecond,
+	})
+	ctx := context.Background()
+
+	for tenant, image := range map[string]string{"alice": "zeroclaw:v1", "bob": "zeroclaw:v2"} {
+		_, err := cs.CoreV1().Pods("tenants").Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-" + tenant, Namespace: "tenants"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "zeroclaw", Image: image}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+			TenantID:  tenant,
+			Status:    registry.StatusRunning,
+			PodName:   "zeroclaw-" + tenant,
+			PodIP:     "10.0.0.1",
+			Namespace: "tenants",
+		}))
+	}
+	simulateReplacementReady(cs, "tenants", "10.0.0.2")
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/rollout", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/rollout", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var ro api.Rollout
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&ro))
+	assert.Equal(t, 1, ro.MaxUnavailable)
+
+	// Only one rollout at a time
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/rollout", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	require.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/rollout", nil))
+		json.NewDecoder(rec.Body).Decode(&ro)
+		return ro.Status != api.RolloutRunning
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, api.RolloutDone, ro.Status, ro.Error)
+	assert.Equal(t, 1, ro.Total)
+	assert.Equal(t, []string{"alice"}, ro.Restarted)
+
+	alice, _ := reg.GetTenant(ctx, "alice")
+	assert.NotEqual(t, "zeroclaw-alice", alice.PodName)
+	assert.Equal(t, "10.0.0.2", alice.PodIP)
+	pod, err := cs.CoreV1().Pods("tenants").Get(ctx, alice.PodName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "zeroclaw:v2", pod.Spec.Containers[0].Image)
+	bob, _ := reg.GetTenant(ctx, "bob")
+	assert.Equal(t, "zeroclaw-bob", bob.PodName)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// Run quota limits, as named in RunQuotaError.Limit
+const (
+	LimitMaxRunning     = "max_running"      // running pods across all tiers
+	LimitTierMaxRunning = "tier_max_running" // running pods of the tenant's tier
+	LimitWakesPerHour   = "wakes_per_hour"   // the tenant's successful wakes in the last hour
+	LimitMinutesPerDay  = "minutes_per_day"  // the tenant's pod-minutes today (UTC)
+)
+
+// runningRetryAfter is the Retry-After suggested when pods are at their cap,
+// since when one will stop isn't known
+const runningRetryAfter = time.Minute
+
+// RunQuota caps how much tenants may run, so runaway tenants can't take up
+// the Kata nodes. Tier maps are keyed by tier (plan); a missing tier or a
+// zero value doesn't limit. Checked on wake only: a pod already running is
+// never stopped for being over a quota.
+type RunQuota struct {
+	MaxRunning        int
+	TierMaxRunning    map[string]int
+	TierWakesPerHour  map[string]int
+	TierMinutesPerDay map[string]int
+}
+
+// MaxWakesPerHour returns the largest wakes-per-hour limit of any tier. The
+// wake history must keep at least that many attempts to count them.
+func (q RunQuota) MaxWakesPerHour() int {
+	n := 0
+	for _, v := range q.TierWakesPerHour {
+		n = max(n, v)
+	}
+	return n
+}
+
+// RunQuotaError is returned when waking a tenant would exceed a run quota.
+// RetryAfter is when trying again may succeed.
+type RunQuotaError struct {
+	Limit      string
+	Max        int
+	RetryAfter time.Duration
+}
+
+func (e *RunQuotaError) Error() string {
+	return fmt.Sprintf("run quota exceeded: %s %d", e.Limit, e.Max)
+}
+
+// checkRunQuota returns a RunQuotaError if starting rec's pod would exceed
+// a run quota. Concurrent wakes are checked independently, so the running
+// caps can be overshot by the wakes in flight.
+func (h *Handler) checkRunQuota(ctx context.Context, rec *registry.TenantRecord, tenantID string) error {
+	q := h.cfg.RunQuota
+	tier := registry.DefaultTier
+	if rec != nil && rec.Tier != "" {
+		tier = rec.Tier
+	}
+	now := time.Now()
+
+	if tierMax := q.TierMaxRunning[tier]; q.MaxRunning > 0 || tierMax > 0 {
+		running, err := h.reg.ListByStatus(ctx, registry.StatusRunning)
+		if err != nil {
+			return fmt.Errorf("count running tenants: %w", err)
+		}
+		inTier := 0
+		for _, t := range running {
+			if t.Tier == tier || (t.Tier == "" && tier == registry.DefaultTier) {
+				inTier++
+			}
+		}
+		if q.MaxRunning > 0 && len(running) >= q.MaxRunning {
+			return &RunQuotaError{Limit: LimitMaxRunning, Max: q.MaxRunning, RetryAfter: runningRetryAfter}
+		}
+		if tierMax > 0 && inTier >= tierMax {
+			return &RunQuotaError{Limit: LimitTierMaxRunning, Max: tierMax, RetryAfter: runningRetryAfter}
+		}
+	}
+
+	if perHour := q.TierWakesPerHour[tier]; perHour > 0 {
+		wakes, err := h.reg.ListWakes(ctx, tenantID)
+		if err != nil {
+			return fmt.Errorf("list wakes: %w", err)
+		}
+		var inHour []time.Time // newest first, like the history
+		for _, wk := range wakes {
+			if wk.Outcome == registry.WakeOK && now.Sub(wk.StartedAt) < time.Hour {
+				inHour = append(inHour, wk.StartedAt)
+			}
+		}
+		if len(inHour) >= perHour {
+			// A slot frees when the oldest wake counted leaves the window
+			oldest := inHour[perHour-1]
+			return &RunQuotaError{Limit: LimitWakesPerHour, Max: perHour, RetryAfter: oldest.Add(time.Hour).Sub(now)}
+		}
+	}
+
+	if perDay := q.TierMinutesPerDay[tier]; perDay > 0 {
+		days, err := h.reg.ListUsage(ctx, tenantID, now, now)
+		if err != nil {
+			return fmt.Errorf("read usage: %w", err)
+		}
+		if len(days) > 0 && days[0].PodSeconds >= int64(perDay)*60 {
+			midnight := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+			return &RunQuotaError{Limit: LimitMinutesPerDay, Max: perDay, RetryAfter: midnight.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// writeRunQuotaExceeded answers 429 with Retry-After, which the router turns
+// into a "quota exceeded" reply instead of a generic start failure
+func writeRunQuotaExceeded(w http.ResponseWriter, e *RunQuotaError) {
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((e.RetryAfter+time.Second-1)/time.Second)))
+	}
+	http.Error(w, e.Error(), http.StatusTooManyRequests)
+}
+
+// ParseTierLimits parses per-tier limits such as "free=10,standard=50"
+func ParseTierLimits(s string) (map[string]int, error) {
+	out := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tier, v, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid tier limit %q: want tier=n", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid tier limit %q: want a non-negative integer", part)
+		}
+		out[strings.TrimSpace(tier)] = n
+	}
+	return out, nil
+}
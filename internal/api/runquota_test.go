@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newRunQuotaHandler(q api.RunQuota) (*api.Handler, *registry.MockClient, *fake.Clientset) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test", S3Bucket: "test-bucket"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		WakeHistory:  20,
+		RunQuota:     q,
+	})
+	return h, reg, cs
+}
+
+func createIdle(t *testing.T, reg *registry.MockClient, id, tier string) {
+	t.Helper()
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     id,
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		Tier:         tier,
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+	}))
+}
+
+// assertRunQuotaRefused checks that both wake paths answer 429 with a
+// Retry-After and create no pod
+func assertRunQuotaRefused(t *testing.T, h *api.Handler, cs *fake.Clientset, id string) {
+	t.Helper()
+	for _, path := range []string{"/wake/" + id, "/wake/" + id + "?async=true"} {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, path)
+		retry, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+		require.NoError(t, err, path)
+		assert.Positive(t, retry, path)
+	}
+	_, err := cs.CoreV1().Pods("tenants").Get(context.Background(), "zeroclaw-"+id, metav1.GetOptions{})
+	assert.Error(t, err, "no pod should be created")
+}
+
+func TestWake_TierRunningCap(t *testing.T) {
+	h, reg, cs := newRunQuotaHandler(api.RunQuota{TierMaxRunning: map[string]int{"free": 1}})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, Tier: "free", PodIP: "10.0.0.1",
+	}))
+	createIdle(t, reg, "bob", "free")
+	createIdle(t, reg, "carol", "standard")
+
+	assertRunQuotaRefused(t, h, cs, "bob")
+
+	// Other tiers aren't held back by the free tier's cap
+	simulatePodReady(cs, "carol", "tenants", "10.0.0.3")
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/carol", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestWake_GlobalRunningCap(t *testing.T) {
+	h, reg, cs := newRunQuotaHandler(api.RunQuota{MaxRunning: 1})
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, Tier: "premium", PodIP: "10.0.0.1",
+	}))
+	createIdle(t, reg, "bob", "standard")
+
+	assertRunQuotaRefused(t, h, cs, "bob")
+}
+
+func TestWake_WakesPerHour(t *testing.T) {
+	h, reg, cs := newRunQuotaHandler(api.RunQuota{TierWakesPerHour: map[string]int{"free": 2}})
+	ctx := context.Background()
+	createIdle(t, reg, "bob", "free")
+	now := time.Now()
+	// A wake over an hour ago and a failed one don't count
+	for _, wk := range []registry.WakeAttempt{
+		{StartedAt: now.Add(-2 * time.Hour), Outcome: registry.WakeOK},
+		{StartedAt: now.Add(-30 * time.Minute), Outcome: registry.WakeOK},
+		{StartedAt: now.Add(-20 * time.Minute), Outcome: registry.WakeFailed},
+	} {
+		require.NoError(t, reg.RecordWake(ctx, "bob", wk, 20))
+	}
+	simulatePodReady(cs, "bob", "tenants", "10.0.0.2")
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/bob", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// The wake just made is the second in the hour
+	require.NoError(t, cs.CoreV1().Pods("tenants").Delete(ctx, "zeroclaw-bob", metav1.DeleteOptions{}))
+	require.NoError(t, reg.UpdateStatus(ctx, "bob", registry.StatusIdle, "", ""))
+	assertRunQuotaRefused(t, h, cs, "bob")
+}
+
+func TestWake_MinutesPerDay(t *testing.T) {
+	h, reg, cs := newRunQuotaHandler(api.RunQuota{TierMinutesPerDay: map[string]int{registry.DefaultTier: 10}})
+	createIdle(t, reg, "bob", "")
+	require.NoError(t, reg.AddUsage(context.Background(), "bob", time.Now(), registry.Usage{PodSeconds: 600}))
+
+	assertRunQuotaRefused(t, h, cs, "bob")
+}
+
+func TestParseTierLimits(t *testing.T) {
+	got, err := api.ParseTierLimits(" free=10, premium=0 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"free": 10, "premium": 0}, got)
+
+	got, err = api.ParseTierLimits("")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	for _, bad := range []string{"free", "=3", "free=-1", "free=lots"} {
+		_, err := api.ParseTierLimits(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/saga"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+)
+
+// Saga kinds and their step names
+const (
+	sagaCreateTenant = "create-tenant"
+
+	stepRegistry = "registry"
+	stepSecret   = "secret"
+	stepIdentity = "identity"
+	stepWebhook  = "webhook"
+)
+
+// createTenantSteps creates the tenant record, stores botToken in the secret
+// store if the record references one, creates the tenant's ServiceAccount
+// and IAM role if Config.TenantRoles is set, then points the bot at the
+// router (or queues that, see registerOrQueueWebhook). rec is nil when the steps are
+// only needed for their Undo (see ResumeSaga).
+//
+// The undos only touch a record this saga created, recognised by its
+// created_at matching the saga's start: a create that conflicts with an
+// existing tenant must not delete it.
+func (h *Handler) createTenantSteps(s *registry.Saga, rec *registry.TenantRecord, botToken string) []saga.Step {
+	own := func(ctx context.Context) (*registry.TenantRecord, error) {
+		cur, err := h.reg.GetTenant(ctx, s.TenantID)
+		if err != nil || cur == nil || !cur.CreatedAt.Equal(s.StartedAt) {
+			return nil, err
+		}
+		return cur, nil
+	}
+	return []saga.Step{
+		{
+			Name: stepRegistry,
+			Do:   func(ctx context.Context) error { return h.reg.CreateTenant(ctx, rec) },
+			Undo: func(ctx context.Context) error {
+				cur, err := own(ctx)
+				if err != nil || cur == nil {
+					return err
+				}
+				return h.reg.DeleteTenant(ctx, s.TenantID)
+			},
+		},
+		{
+			Name: stepSecret,
+			Do: func(ctx context.Context) error {
+				if rec.BotTokenRef == "" {
+					return nil
+				}
+				return h.cfg.Secrets.Put(ctx, rec.BotTokenRef, botToken)
+			},
+			Undo: func(ctx context.Context) error {
+				cur, err := own(ctx)
+				if err != nil || cur == nil || cur.BotTokenRef == "" || h.cfg.Secrets == nil {
+					return err
+				}
+				return h.cfg.Secrets.Delete(ctx, cur.BotTokenRef)
+			},
+		},
+		{
+			Name: stepIdentity,
+			Do: func(ctx context.Context) error {
+				if !h.tenantIdentities() {
+					return nil
+				}
+				return h.ensureTenantIdentity(ctx, rec, rec.Namespace)
+			},
+			Undo: func(ctx context.Context) error {
+				if !h.tenantIdentities() {
+					return nil
+				}
+				cur, err := own(ctx)
+				if err != nil || cur == nil {
+					return err
+				}
+				return h.deleteTenantIdentity(ctx, cur.TenantID, cur.Namespace)
+			},
+		},
+		{
+			Name: stepWebhook,
+			Do: func(ctx context.Context) error {
+				if h.tg == nil || !rec.HasBot() {
+					return nil
+				}
+				return h.registerOrQueueWebhook(ctx, rec)
+			},
+			Undo: func(ctx context.Context) error {
+				if h.tg == nil {
+					return nil
+				}
+				cur, err := own(ctx)
+				if err != nil || cur == nil || !cur.HasBot() {
+					return err
+				}
+				token, err := h.botToken(ctx, cur)
+				if errors.Is(err, secrets.ErrNotFound) {
+					// The secret step never ran, so neither did this one
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				// A token Telegram rejects (e.g. revoked) has no webhook to remove
+				if err := h.tg.DeleteWebhook(ctx, token); telegram.Retryable(err) {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// ResumeSaga undoes what is left of an unfinished saga, e.g. one whose
+// orchestrator crashed mid-create. The reconciler calls it for sagas that
+// saga.Resumable reports.
+func (h *Handler) ResumeSaga(ctx context.Context, s *registry.Saga) error {
+	switch s.Kind {
+	case sagaCreateTenant:
+		return saga.Compensate(ctx, h.reg, s, h.createTenantSteps(s, nil, ""))
+	default:
+		return fmt.Errorf("unknown saga kind %q", s.Kind)
+	}
+}
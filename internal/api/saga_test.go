@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResumeSaga_CreateTenant undoes a create abandoned after its record was
+// written, as the reconciler does after an orchestrator crash
+func TestResumeSaga_CreateTenant(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+
+	s := saga.New("create-tenant", "alice")
+	s.Steps = []string{"registry", "webhook"}
+	require.NoError(t, reg.PutSaga(ctx, s))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, CreatedAt: s.StartedAt}))
+
+	require.NoError(t, h.ResumeSaga(ctx, s))
+
+	rec, err := reg.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+	sagas, _ := reg.ListSagas(ctx)
+	assert.Empty(t, sagas)
+}
+
+// TestResumeSaga_KeepsOtherTenant never deletes a record the saga didn't
+// create, e.g. when the create conflicted with an existing tenant
+func TestResumeSaga_KeepsOtherTenant(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusRunning, CreatedAt: time.Now().Add(-time.Hour)}))
+	s := saga.New("create-tenant", "bob")
+	s.Steps = []string{"registry"}
+
+	require.NoError(t, h.ResumeSaga(ctx, s))
+
+	rec, err := reg.GetTenant(ctx, "bob")
+	require.NoError(t, err)
+	assert.NotNil(t, rec)
+}
+
+func TestResumeSaga_UnknownKind(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	assert.Error(t, h.ResumeSaga(context.Background(), saga.New("teleport", "alice")))
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// GetSlack returns a tenant's Slack app credentials (internal use by Router).
+// 404 if the tenant has no Slack app.
+func (h *Handler) GetSlack(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil || rec.Slack == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rec.Slack)
+}
+
+// normalizeSlack validates Slack app credentials; an empty object means
+// "disconnect Slack"
+func normalizeSlack(s *registry.SlackConfig) (*registry.SlackConfig, error) {
+	if s == nil || (s.SigningSecret == "" && s.BotToken == "") {
+		return nil, nil
+	}
+	if s.SigningSecret == "" || s.BotToken == "" {
+		return nil, fmt.Errorf("slack requires both signing_secret and bot_token")
+	}
+	if !strings.HasPrefix(s.BotToken, "xoxb-") {
+		return nil, fmt.Errorf("slack.bot_token must be a bot token (xoxb-...)")
+	}
+	return s, nil
+}
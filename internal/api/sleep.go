@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// SleepResult is the response of POST /tenants/{tenantID}/sleep
+type SleepResult struct {
+	TenantID string `json:"tenant_id"`
+	PodName  string `json:"pod_name"`
+}
+
+// SleepTenant hibernates a running tenant on request (the router's /sleep
+// command, or the agent itself) instead of waiting for the idle timeout. The
+// pod is stopped exactly as the lifecycle controller stops an idle one, with
+// the idle grace period so it can flush its state; the next message wakes it.
+func (h *Handler) SleepTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "k8s not available in local mode", http.StatusServiceUnavailable)
+		return
+	}
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec.Status != registry.StatusRunning || rec.PodName == "" {
+		http.Error(w, "tenant not running", http.StatusConflict)
+		return
+	}
+
+	// Don't stop a pod a wake or restart is still working on
+	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, h.cfg.WakeLockTTL)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !acquired {
+		http.Error(w, "wake or restart in progress", http.StatusConflict)
+		return
+	}
+	defer h.lock.ReleaseWakeLock(ctx, tenantID)
+
+	ns := rec.Namespace
+	if ns == "" {
+		ns = h.cfg.Namespace
+	}
+	grace := h.k8s.GracePeriod(k8sclient.OpIdle, rec.Tier)
+	if err := h.k8s.DeletePod(ctx, rec.PodName, ns, grace); err != nil {
+		slog.Error("sleep: delete pod failed", "tenant", tenantID, "pod", rec.PodName, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if err := h.reg.UpdateStatus(ctx, tenantID, registry.StatusIdle, "", ""); err != nil {
+		slog.Error("sleep: update status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if h.rdb != nil {
+		h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, tenantID))
+	}
+	slog.Info("sleep: tenant hibernated on request", "tenant", tenantID, "pod", rec.PodName)
+	h.cfg.Events.Publish(events.TenantIdle, h.cfg.Environment.Name, tenantID, map[string]any{"reason": "sleep"})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SleepResult{TenantID: tenantID, PodName: rec.PodName})
+}
@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSleepTenant(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	ctx := context.Background()
+
+	_, err := cs.CoreV1().Pods("tenants").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:  "alice",
+		Status:    registry.StatusRunning,
+		PodName:   "zeroclaw-alice",
+		PodIP:     "10.0.0.1",
+		Namespace: "tenants",
+	})
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/sleep", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+	assert.Empty(t, tenant.PodIP)
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	assert.Error(t, err, "pod should be deleted")
+}
+
+func TestSleepTenant_NotRunning(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:  "bob",
+		Status:    registry.StatusIdle,
+		Namespace: "tenants",
+	})
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/bob/sleep", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
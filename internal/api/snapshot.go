@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+)
+
+// podGoneMargin is how long past its grace period a stopped pod may take to
+// go away before a snapshot or restore gives up on it
+const podGoneMargin = 30 * time.Second
+
+// snapshotTimeout bounds a snapshot or restore. Both run on if the client
+// goes away, so a copy is never left half done, and hold the wake lock as
+// long, so it can't expire during a large copy and let a pod start.
+const snapshotTimeout = 30 * time.Minute
+
+// SnapshotResult is the response of POST /tenants/{tenantID}/snapshots
+type SnapshotResult struct {
+	TenantID string            `json:"tenant_id"`
+	Snapshot snapshot.Snapshot `json:"snapshot"`
+	PodName  string            `json:"pod_name,omitempty"` // the pod stopped to quiesce the tenant
+}
+
+// SnapshotRestoreResult is the response of
+// POST /tenants/{tenantID}/snapshots/{snapshotID}/restore
+type SnapshotRestoreResult struct {
+	TenantID string `json:"tenant_id"`
+	Snapshot string `json:"snapshot"`
+	// Backup is a snapshot of the state the restore replaced, taken first
+	// so the restore can be undone; empty if the tenant had no state
+	Backup  string `json:"backup,omitempty"`
+	Objects int    `json:"objects"`
+	PodName string `json:"pod_name,omitempty"` // the pod stopped for the restore
+}
+
+// snapshotRequest is the optional body of POST /tenants/{tenantID}/snapshots
+type snapshotRequest struct {
+	// Quiesce stops a running pod first, so its state is flushed and
+	// unchanging while it is copied. Otherwise the state is copied as the
+	// pod last wrote it to S3.
+	Quiesce bool `json:"quiesce"`
+}
+
+// snapshotTenant looks up the tenant of a snapshot request, answering the
+// request itself if it can't go ahead
+func (h *Handler) snapshotTenant(w http.ResponseWriter, r *http.Request) (*registry.TenantRecord, bool) {
+	if h.cfg.Snapshots == nil {
+		http.Error(w, "snapshots not configured", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	rec, err := h.reg.GetTenant(r.Context(), chi.URLParam(r, "tenantID"))
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return nil, false
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return nil, false
+	}
+	if rec.Status == registry.StatusTerminated {
+		writeTerminated(w)
+		return nil, false
+	}
+	if rec.S3Prefix == "" {
+		rec.S3Prefix = h.cfg.Environment.S3Prefix(rec.TenantID)
+	}
+	return rec, true
+}
+
+// lockForSnapshot takes the tenant's wake lock, so no pod starts (and
+// writes state) while it is copied
+func (h *Handler) lockForSnapshot(ctx context.Context, w http.ResponseWriter, tenantID string) bool {
+	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, snapshotTimeout)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return false
+	}
+	if !acquired {
+		http.Error(w, "wake or restart in progress", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// quiesce stops a running tenant's pod as an idle stop does and waits until
+// it is gone, its state flushed. It returns the stopped pod's name, or ""
+// if none was running.
+func (h *Handler) quiesce(ctx context.Context, rec *registry.TenantRecord, reason string) (string, error) {
+	if rec.Status != registry.StatusRunning || rec.PodName == "" || h.k8s == nil {
+		return "", nil
+	}
+	ns := rec.Namespace
+	if ns == "" {
+		ns = h.cfg.Namespace
+	}
+	grace := h.k8s.GracePeriod(k8sclient.OpIdle, rec.Tier)
+	if err := h.k8s.DeletePod(ctx, rec.PodName, ns, grace); err != nil {
+		return "", fmt.Errorf("delete pod %s: %w", rec.PodName, err)
+	}
+	if err := h.reg.UpdateStatus(ctx, rec.TenantID, registry.StatusIdle, "", ""); err != nil {
+		return "", fmt.Errorf("update status: %w", err)
+	}
+	if h.rdb != nil {
+		h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, rec.TenantID))
+	}
+	slog.Info("snapshot: tenant stopped", "tenant", rec.TenantID, "pod", rec.PodName, "reason", reason)
+	h.cfg.Events.Publish(events.TenantIdle, h.cfg.Environment.Name, rec.TenantID, map[string]any{"reason": reason})
+	rec.Status = registry.StatusIdle
+	if err := h.k8s.WaitPodGone(ctx, rec.PodName, ns, time.Duration(grace)*time.Second+podGoneMargin); err != nil {
+		return rec.PodName, err
+	}
+	return rec.PodName, nil
+}
+
+// takeSnapshot copies the tenant's state to a new snapshot. A tenant
+// without state gets none (zero objects).
+func (h *Handler) takeSnapshot(ctx context.Context, rec *registry.TenantRecord) (snapshot.Snapshot, error) {
+	prefix := h.cfg.Environment.SnapshotPrefix(rec.TenantID)
+	snaps, err := h.cfg.Snapshots.List(ctx, prefix)
+	if err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("list snapshots: %w", err)
+	}
+	// IDs have millisecond precision; one taken in the same millisecond as
+	// the newest (e.g. a restore's backup) would overwrite it
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if len(snaps) > 0 && !now.After(snaps[0].CreatedAt) {
+		now = snaps[0].CreatedAt.Add(time.Millisecond)
+	}
+	snap := snapshot.Snapshot{ID: snapshot.NewID(now), CreatedAt: now}
+	objects, bytes, err := h.cfg.Snapshots.Copy(ctx, rec.S3Prefix, prefix+snap.ID+"/")
+	if err != nil {
+		return snap, fmt.Errorf("copy %s: %w", rec.S3Prefix, err)
+	}
+	snap.Objects, snap.Bytes = objects, bytes
+	return snap, nil
+}
+
+// pruneSnapshots deletes the tenant's oldest snapshots beyond SnapshotKeep,
+// sparing the one named spare. Failures are logged.
+func (h *Handler) pruneSnapshots(ctx context.Context, tenantID, spare string) {
+	if h.cfg.SnapshotKeep < 0 {
+		return
+	}
+	prefix := h.cfg.Environment.SnapshotPrefix(tenantID)
+	snaps, err := h.cfg.Snapshots.List(ctx, prefix)
+	if err != nil {
+		slog.Warn("snapshot: list for pruning failed", "tenant", tenantID, "err", err)
+		return
+	}
+	for i := h.cfg.SnapshotKeep; i < len(snaps); i++ {
+		if snaps[i].ID == spare {
+			continue
+		}
+		if _, err := h.cfg.Snapshots.Delete(ctx, prefix+snaps[i].ID+"/"); err != nil {
+			slog.Warn("snapshot: prune failed", "tenant", tenantID, "snapshot", snaps[i].ID, "err", err)
+		}
+	}
+}
+
+// CreateSnapshot copies the tenant's S3 state to a new snapshot. With
+// {"quiesce": true} a running pod is stopped first, as an idle stop does, so
+// the copy is consistent; the next message wakes it again.
+func (h *Handler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req snapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	rec, ok := h.snapshotTenant(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), snapshotTimeout)
+	defer cancel()
+	tenantID := rec.TenantID
+	if !h.lockForSnapshot(ctx, w, tenantID) {
+		return
+	}
+	defer h.lock.ReleaseWakeLock(context.WithoutCancel(ctx), tenantID)
+
+	var pod string
+	if req.Quiesce {
+		var err error
+		if pod, err = h.quiesce(ctx, rec, "snapshot"); err != nil {
+			slog.Error("snapshot: quiesce failed", "tenant", tenantID, "err", err)
+			http.Error(w, "could not stop the tenant's pod: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	snap, err := h.takeSnapshot(ctx, rec)
+	if err != nil {
+		slog.Error("snapshot failed", "tenant", tenantID, "err", err)
+		http.Error(w, "snapshot failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if snap.Objects == 0 {
+		http.Error(w, "tenant has no state to snapshot", http.StatusConflict)
+		return
+	}
+	slog.Info("snapshot taken", "tenant", tenantID, "snapshot", snap.ID, "objects", snap.Objects, "bytes", snap.Bytes)
+	h.pruneSnapshots(ctx, tenantID, "")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(SnapshotResult{TenantID: tenantID, Snapshot: snap, PodName: pod})
+}
+
+// ListSnapshots returns the tenant's snapshots, newest first
+func (h *Handler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	rec, ok := h.snapshotTenant(w, r)
+	if !ok {
+		return
+	}
+	snaps, err := h.cfg.Snapshots.List(r.Context(), h.cfg.Environment.SnapshotPrefix(rec.TenantID))
+	if err != nil {
+		slog.Error("list snapshots failed", "tenant", rec.TenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snaps)
+}
+
+// findSnapshot answers 404 unless the URL's snapshot exists
+func (h *Handler) findSnapshot(w http.ResponseWriter, r *http.Request, rec *registry.TenantRecord) (string, bool) {
+	id := chi.URLParam(r, "snapshotID")
+	if !snapshot.ValidID(id) {
+		http.Error(w, "snapshot not found", http.StatusNotFound)
+		return "", false
+	}
+	snaps, err := h.cfg.Snapshots.List(r.Context(), h.cfg.Environment.SnapshotPrefix(rec.TenantID))
+	if err != nil {
+		slog.Error("list snapshots failed", "tenant", rec.TenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return "", false
+	}
+	for _, s := range snaps {
+		if s.ID == id {
+			return id, true
+		}
+	}
+	http.Error(w, "snapshot not found", http.StatusNotFound)
+	return "", false
+}
+
+// RestoreSnapshot rolls the tenant's state back to a snapshot. A running
+// pod is stopped and waited out first, so its final flush can't overwrite
+// the restored state, and the current state is snapshotted so the restore
+// can itself be undone. The next message wakes the tenant on the restored
+// state. The snapshot itself is kept.
+func (h *Handler) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	rec, ok := h.snapshotTenant(w, r)
+	if !ok {
+		return
+	}
+	tenantID := rec.TenantID
+	if !h.confirmed(r, "restore-snapshot", tenantID) {
+		http.Error(w, "confirmation required: send "+ConfirmHeader+": "+tenantID, http.StatusPreconditionRequired)
+		return
+	}
+	id, ok := h.findSnapshot(w, r, rec)
+	if !ok {
+		return
+	}
+	// A restore stopped halfway leaves the tenant with partial state
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), snapshotTimeout)
+	defer cancel()
+	if !h.lockForSnapshot(ctx, w, tenantID) {
+		return
+	}
+	defer h.lock.ReleaseWakeLock(context.WithoutCancel(ctx), tenantID)
+
+	pod, err := h.quiesce(ctx, rec, "snapshot restore")
+	if err != nil {
+		slog.Error("snapshot restore: quiesce failed", "tenant", tenantID, "err", err)
+		http.Error(w, "could not stop the tenant's pod: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	backup, err := h.takeSnapshot(ctx, rec)
+	if err != nil {
+		slog.Error("snapshot restore: backup failed", "tenant", tenantID, "err", err)
+		http.Error(w, "backup of the current state failed, nothing restored: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result := SnapshotRestoreResult{TenantID: tenantID, Snapshot: id, PodName: pod}
+	if backup.Objects > 0 {
+		result.Backup = backup.ID
+	}
+
+	// Clear the prefix first, so files created since the snapshot go too
+	if _, err := h.cfg.Snapshots.Delete(ctx, rec.S3Prefix); err != nil {
+		slog.Error("snapshot restore: clear state failed", "tenant", tenantID, "err", err)
+		http.Error(w, "clearing the current state failed; restore again, or restore backup "+result.Backup,
+			http.StatusInternalServerError)
+		return
+	}
+	src := h.cfg.Environment.SnapshotPrefix(tenantID) + id + "/"
+	result.Objects, _, err = h.cfg.Snapshots.Copy(ctx, src, rec.S3Prefix)
+	if err != nil {
+		slog.Error("snapshot restore: copy failed", "tenant", tenantID, "snapshot", id, "err", err)
+		http.Error(w, "copying the snapshot failed; restore again, or restore backup "+result.Backup,
+			http.StatusInternalServerError)
+		return
+	}
+	slog.Info("snapshot restored", "tenant", tenantID, "snapshot", id, "backup", result.Backup, "objects", result.Objects)
+	h.pruneSnapshots(ctx, tenantID, id)
+	ev := registry.TenantEvent{
+		Time:    time.Now().UTC(),
+		Kind:    registry.EventStateRolledBack,
+		Pod:     pod,
+		Message: fmt.Sprintf("state restored from snapshot %s", id),
+	}
+	if result.Backup != "" {
+		ev.Message += fmt.Sprintf("; the state it replaced is snapshot %s", result.Backup)
+	}
+	if err := h.reg.RecordEvent(ctx, tenantID, ev, h.cfg.EventHistory); err != nil {
+		slog.Error("snapshot restore: record event failed", "tenant", tenantID, "err", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// DeleteSnapshot deletes one of the tenant's snapshots
+func (h *Handler) DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	rec, ok := h.snapshotTenant(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.findSnapshot(w, r, rec)
+	if !ok {
+		return
+	}
+	if _, err := h.cfg.Snapshots.Delete(r.Context(), h.cfg.Environment.SnapshotPrefix(rec.TenantID)+id+"/"); err != nil {
+		slog.Error("delete snapshot failed", "tenant", rec.TenantID, "snapshot", id, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("snapshot deleted", "tenant", rec.TenantID, "snapshot", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,114 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSnapshots_CreateRestoreDelete(t *testing.T) {
+	reg := registry.NewMock()
+	store := snapshot.NewMemory()
+	cs := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"}})
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{Namespace: "tenants", Snapshots: store, SnapshotKeep: 2})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, PodName: "zeroclaw-alice", Namespace: "tenants", S3Prefix: "tenants/alice/",
+	}))
+	store.Put("tenants/alice/memory.db", 100)
+	do := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A quiesced snapshot stops the running pod first
+	rec := do(http.MethodPost, "/tenants/alice/snapshots", `{"quiesce":true}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created api.SnapshotResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+	assert.Equal(t, "zeroclaw-alice", created.PodName)
+	assert.Equal(t, 1, created.Snapshot.Objects)
+	assert.EqualValues(t, 100, created.Snapshot.Bytes)
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+	exists, _ := k8s.PodExists(ctx, "zeroclaw-alice", "tenants")
+	assert.False(t, exists)
+
+	// The agent corrupts its memory and adds a file
+	store.Put("tenants/alice/memory.db", 5)
+	store.Put("tenants/alice/junk.txt", 1)
+
+	path := "/tenants/alice/snapshots/" + created.Snapshot.ID + "/restore"
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/tenants/alice/snapshots/latest/restore", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/tenants/alice/snapshots/20200101T000000.000Z/restore", "", nil).Code)
+	rec = do(http.MethodPost, path, "", http.Header{"X-Confirm": {"alice"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var restored api.SnapshotRestoreResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&restored))
+	assert.Equal(t, created.Snapshot.ID, restored.Snapshot)
+	assert.NotEmpty(t, restored.Backup)
+	assert.Equal(t, 1, restored.Objects)
+	assert.Equal(t, []string{"tenants/alice/memory.db"}, store.Keys("tenants/alice/"))
+	stored, _ := store.List(ctx, "snapshots/alice/")
+	require.Len(t, stored, 2)
+	assert.Equal(t, restored.Backup, stored[0].ID)
+	assert.EqualValues(t, 6, stored[0].Bytes, "the backup holds the replaced state")
+	events, _ := reg.ListEvents(ctx, "alice")
+	require.Len(t, events, 1)
+	assert.Equal(t, registry.EventStateRolledBack, events[0].Kind)
+
+	// A third snapshot prunes the oldest, down to SnapshotKeep
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/tenants/alice/snapshots", "", nil).Code)
+	rec = do(http.MethodGet, "/tenants/alice/snapshots", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var snaps []snapshot.Snapshot
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&snaps))
+	require.Len(t, snaps, 2)
+	assert.Equal(t, restored.Backup, snaps[1].ID)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice/snapshots/"+restored.Backup, "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/tenants/alice/snapshots/"+restored.Backup, "", nil).Code)
+	assert.Len(t, store.Keys("snapshots/alice/"), 1)
+}
+
+func TestSnapshots_Refused(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, S3Prefix: "tenants/alice/"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusTerminated, S3Prefix: "tenants/bob/"}))
+	do := func(h *api.Handler, method, path string) int {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	off := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{})
+	assert.Equal(t, http.StatusServiceUnavailable, do(off, http.MethodPost, "/tenants/alice/snapshots"))
+
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{Snapshots: snapshot.NewMemory(), RequireConfirm: true})
+	assert.Equal(t, http.StatusConflict, do(h, http.MethodPost, "/tenants/alice/snapshots"), "no state")
+	assert.Equal(t, http.StatusGone, do(h, http.MethodPost, "/tenants/bob/snapshots"))
+	assert.Equal(t, http.StatusNotFound, do(h, http.MethodGet, "/tenants/carol/snapshots"))
+	assert.Equal(t, http.StatusPreconditionRequired,
+		do(h, http.MethodPost, "/tenants/alice/snapshots/20260101T000000.000Z/restore"))
+}
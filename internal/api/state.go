@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/bytesize"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
+)
+
+// QuotaExceededError is returned when waking a tenant whose S3 state has
+// reached its tier's quota and the quota action is to refuse.
+type QuotaExceededError struct {
+	Bytes      int64
+	QuotaBytes int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("state storage quota exceeded: %s of %s",
+		bytesize.Format(e.Bytes), bytesize.Format(e.QuotaBytes))
+}
+
+// checkStateQuota returns a QuotaExceededError if rec's last measured state
+// size is over its quota and such tenants aren't started
+func (h *Handler) checkStateQuota(rec *registry.TenantRecord) error {
+	if rec == nil || h.cfg.StateQuota.Action == statesize.ActionReadOnly {
+		return nil
+	}
+	level, limit := h.cfg.StateQuota.Check(rec.StateBytes, rec.Tier)
+	if level != statesize.LevelExceeded {
+		return nil
+	}
+	return &QuotaExceededError{Bytes: rec.StateBytes, QuotaBytes: limit}
+}
+
+// stateReadOnly reports whether rec's pod gets its state mounted read-only
+func (h *Handler) stateReadOnly(rec *registry.TenantRecord) bool {
+	if h.cfg.StateQuota.Action != statesize.ActionReadOnly {
+		return false
+	}
+	level, _ := h.cfg.StateQuota.Check(rec.StateBytes, rec.Tier)
+	return level == statesize.LevelExceeded
+}
+
+// writeQuotaExceeded answers 507, which the router turns into a "storage
+// full" reply instead of a generic start failure
+func writeQuotaExceeded(w http.ResponseWriter, e *QuotaExceededError) {
+	http.Error(w, e.Error(), http.StatusInsufficientStorage)
+}
+
+// GetState returns the tenant's S3 state size against its quota. With a
+// sizer configured the state is measured now (and the measurement stored);
+// otherwise the last measurement is returned.
+func (h *Handler) GetState(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if h.cfg.StateSizer != nil && rec.S3Prefix != "" {
+		size, err := h.cfg.StateSizer.Size(r.Context(), rec.S3Prefix)
+		if err != nil {
+			slog.Error("measure state failed", "tenant", tenantID, "err", err)
+			http.Error(w, "state size unavailable", http.StatusBadGateway)
+			return
+		}
+		now := time.Now().UTC()
+		// The quota level is left for the state size watcher, which tells
+		// the owner when it rises
+		if err := h.reg.UpdateStateSize(r.Context(), tenantID, size, rec.StateQuotaLevel, now); err != nil {
+			slog.Warn("record state size failed", "tenant", tenantID, "err", err)
+		}
+		rec.StateBytes, rec.StateMeasuredAt = size, now
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.cfg.StateQuota.Report(rec))
+}
@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newQuotaHandler(sizer statesize.Sizer, action statesize.Action) (*api.Handler, *registry.MockClient, *fake.Clientset) {
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test", S3Bucket: "test-bucket"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		StateSizer:   sizer,
+		StateQuota: statesize.QuotaPolicy{
+			Bytes:     1000,
+			TierBytes: map[string]int64{"premium": 0},
+			Action:    action,
+		},
+	})
+	return h, reg, cs
+}
+
+func TestGetState_MeasuresAgainstQuota(t *testing.T) {
+	sizer := statesize.NewMemory()
+	sizer.Put("tenants/alice/memory.db", 700)
+	sizer.Put("tenants/alice/config.toml", 150)
+	h, reg, _ := newQuotaHandler(sizer, statesize.ActionRefuse)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", S3Prefix: "tenants/alice/", Tier: "standard"}))
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice/state", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report statesize.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, int64(850), report.Bytes)
+	assert.Equal(t, int64(1000), report.QuotaBytes)
+	assert.Equal(t, 85, report.UsedPercent)
+	assert.Equal(t, statesize.LevelWarning, report.Level)
+	assert.False(t, report.MeasuredAt.IsZero())
+
+	// The size is stored, but the level is left for the watcher to raise
+	tenant, err := reg.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(850), tenant.StateBytes)
+	assert.Empty(t, tenant.StateQuotaLevel)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/nobody/state", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWake_OverQuotaRefused(t *testing.T) {
+	h, reg, cs := newQuotaHandler(nil, statesize.ActionRefuse)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "bob",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		Tier:         "standard",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+		StateBytes:   1200,
+	}))
+
+	for _, path := range []string{"/wake/bob", "/wake/bob?async=true"} {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusInsufficientStorage, rec.Code, path)
+	}
+	pods, err := cs.CoreV1().Pods("tenants").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pods.Items)
+}
+
+func TestWake_UnlimitedTierNotRefused(t *testing.T) {
+	h, reg, cs := newQuotaHandler(nil, statesize.ActionRefuse)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "carol",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		Tier:         "premium",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+		StateBytes:   1 << 40,
+	}))
+	simulatePodReady(cs, "carol", "tenants", "10.0.0.9")
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/carol", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestWake_OverQuotaReadOnly(t *testing.T) {
+	h, reg, cs := newQuotaHandler(nil, statesize.ActionReadOnly)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "dave",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		Tier:         "standard",
+		LastActiveAt: time.Now(),
+		IdleTimeoutS: 300,
+		StateBytes:   1000,
+	}))
+	simulatePodReady(cs, "dave", "tenants", "10.0.0.10")
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/dave", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	pod, err := cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-dave", metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, m := range pod.Spec.Containers[0].VolumeMounts {
+		if m.Name == "s3-state" {
+			assert.True(t, m.ReadOnly)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+)
+
+const (
+	statusLinkDefaultTTL = 30 * 24 * time.Hour
+	statusLinkMaxTTL     = 365 * 24 * time.Hour
+)
+
+// StatusLink is the response of POST /tenants/{tenantID}/status-link
+type StatusLink struct {
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateStatusLink issues a signed link to the tenant's public status page on
+// the router. Optional body: {"ttl_s": N} (default 30 days, at most a year).
+func (h *Handler) CreateStatusLink(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	if len(h.cfg.StatusPageSecret) == 0 || h.cfg.StatusPageURL == "" {
+		http.Error(w, "status pages not configured (STATUS_PAGE_SECRET, ROUTER_PUBLIC_URL)", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		TTLS int64 `json:"ttl_s"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	ttl := statusLinkDefaultTTL
+	if req.TTLS != 0 {
+		ttl = time.Duration(req.TTLS) * time.Second
+		if ttl < time.Minute || ttl > statusLinkMaxTTL {
+			http.Error(w, "ttl_s must be between 60 and 31536000", http.StatusBadRequest)
+			return
+		}
+	}
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	expires := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	token := statuspage.Sign(h.cfg.StatusPageSecret, h.cfg.Environment.Name, tenantID, expires)
+	slog.Info("status link issued", "tenant", tenantID, "expires_at", expires)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(StatusLink{
+		TenantID:  tenantID,
+		URL:       h.cfg.StatusPageURL + "/status/" + token,
+		ExpiresAt: expires,
+	})
+}
+
+// GetPublicStatus returns what the tenant's status page shows. The router
+// serves it to holders of a status link.
+func (h *Handler) GetPublicStatus(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil || rec.Status == registry.StatusTerminated {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	wakes, err := h.reg.ListWakes(r.Context(), tenantID)
+	if err != nil {
+		slog.Error("list wakes failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	events, err := h.reg.ListEvents(r.Context(), tenantID)
+	if err != nil {
+		slog.Error("list events failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(publicStatus(rec, wakes, events, time.Now().UTC()))
+}
+
+// publicStatus summarizes rec's wake history and event log over
+// statuspage.Window
+func publicStatus(rec *registry.TenantRecord, wakes []registry.WakeAttempt, events []registry.TenantEvent, now time.Time) statuspage.Status {
+	s := statuspage.Status{
+		TenantID:      rec.TenantID,
+		BotUsername:   rec.BotUsername,
+		LastActiveAt:  rec.LastActiveAt,
+		UptimePercent: 100,
+		CheckedAt:     now,
+	}
+	switch rec.Status {
+	case registry.StatusRunning:
+		s.State = statuspage.StateOnline
+	case registry.StatusProvisioning:
+		s.State = statuspage.StateStarting
+	case registry.StatusSuspended:
+		s.State = statuspage.StatePaused
+	case registry.StatusFailed:
+		s.State = statuspage.StateDown
+	default:
+		s.State = statuspage.StateSleeping
+	}
+	since := now.Add(-statuspage.Window)
+	for _, w := range wakes {
+		if w.StartedAt.Before(since) {
+			continue
+		}
+		s.Wakes++
+		if w.Outcome != registry.WakeOK {
+			s.FailedWakes++
+		}
+	}
+	if s.Wakes > 0 {
+		s.UptimePercent = float64(s.Wakes-s.FailedWakes) * 100 / float64(s.Wakes)
+	}
+	for _, ev := range events {
+		if !ev.Time.Before(since) {
+			s.Incidents++
+		}
+	}
+	return s
+}
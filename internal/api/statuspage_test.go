@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStatusLink(t *testing.T) {
+	reg := registry.NewMock()
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{TenantID: "alice"}))
+	secret := []byte("s3cret")
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{
+		StatusPageSecret: secret,
+		StatusPageURL:    "https://router.example.com",
+	})
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/status-link", strings.NewReader(`{"ttl_s":3600}`)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var link api.StatusLink
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&link))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), link.ExpiresAt, 5*time.Second)
+	token, ok := strings.CutPrefix(link.URL, "https://router.example.com/status/")
+	require.True(t, ok, link.URL)
+	tenant, err := statuspage.Verify(secret, "", token, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "alice", tenant)
+
+	// No body: the default TTL
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/status-link", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/status-link", strings.NewReader(`{"ttl_s":1}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/nobody/status-link", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Not configured
+	h = api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{})
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/status-link", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+// TestGetPublicStatus: only the window's wakes and events count
+func TestGetPublicStatus(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusRunning, BotUsername: "alice_bot",
+		PodIP: "10.0.0.1", LastActiveAt: now.Add(-time.Minute),
+	}))
+	for _, w := range []registry.WakeAttempt{
+		{StartedAt: now.Add(-30 * 24 * time.Hour), Outcome: registry.WakeFailed},
+		{StartedAt: now.Add(-2 * time.Hour), Outcome: registry.WakeOK},
+		{StartedAt: now.Add(-time.Hour), Outcome: registry.WakeOK},
+		{StartedAt: now.Add(-time.Hour), Outcome: registry.WakeOK},
+		{StartedAt: now.Add(-time.Minute), Outcome: registry.WakeFailed},
+	} {
+		require.NoError(t, reg.RecordWake(ctx, "alice", w, 20))
+	}
+	require.NoError(t, reg.RecordEvent(ctx, "alice", registry.TenantEvent{Time: now.Add(-time.Hour), Kind: registry.EventOOMKilled}, 50))
+
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{})
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenants/alice/public-status", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "10.0.0.1")
+	var st statuspage.Status
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&st))
+	assert.Equal(t, statuspage.StateOnline, st.State)
+	assert.Equal(t, "alice_bot", st.BotUsername)
+	assert.Equal(t, 4, st.Wakes)
+	assert.Equal(t, 1, st.FailedWakes)
+	assert.InDelta(t, 75.0, st.UptimePercent, 0.01)
+	assert.Equal(t, 1, st.Incidents)
+}
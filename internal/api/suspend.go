@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// SuspendResult is the response of POST /tenants/{tenantID}/suspend,
+// /resume and /reset
+type SuspendResult struct {
+	TenantID string                `json:"tenant_id"`
+	Status   registry.TenantStatus `json:"status"`
+	PodName  string                `json:"pod_name,omitempty"` // the pod stopped by a suspend
+}
+
+// writeSuspended answers 423, which the router turns into a "bot paused"
+// reply instead of a generic start failure
+func writeSuspended(w http.ResponseWriter) {
+	http.Error(w, registry.ErrSuspended.Error(), http.StatusLocked)
+}
+
+// writeTerminated answers 410 for a deleted tenant
+func writeTerminated(w http.ResponseWriter) {
+	http.Error(w, registry.ErrTerminated.Error(), http.StatusGone)
+}
+
+// SuspendTenant puts a tenant on hold (e.g. for billing delinquency) without
+// deleting anything: its pod is stopped with the idle grace period so it can
+// flush its state, and wakes are refused until it is resumed. Suspending a
+// suspended tenant is a no-op.
+func (h *Handler) SuspendTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec.Status == registry.StatusTerminated {
+		http.Error(w, "tenant deleted", http.StatusConflict)
+		return
+	}
+	if rec.Status == registry.StatusSuspended {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SuspendResult{TenantID: tenantID, Status: rec.Status})
+		return
+	}
+
+	// Don't stop a pod a wake or restart is still working on
+	acquired, err := h.lock.AcquireWakeLock(ctx, tenantID, h.cfg.WakeLockTTL)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !acquired {
+		http.Error(w, "wake or restart in progress", http.StatusConflict)
+		return
+	}
+	defer h.lock.ReleaseWakeLock(ctx, tenantID)
+
+	// Suspend first, so a wake that read the record before the lock was
+	// taken can't mark the tenant running again
+	if err := h.reg.UpdateStatus(ctx, tenantID, registry.StatusSuspended, "", ""); err != nil {
+		slog.Error("suspend: update status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if h.rdb != nil {
+		h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, tenantID))
+	}
+	if rec.PodName != "" && h.k8s != nil {
+		ns := rec.Namespace
+		if ns == "" {
+			ns = h.cfg.Namespace
+		}
+		grace := h.k8s.GracePeriod(k8sclient.OpIdle, rec.Tier)
+		if err := h.k8s.DeletePod(ctx, rec.PodName, ns, grace); err != nil {
+			// The tenant stays suspended; the reconciler doesn't look at
+			// suspended tenants, so say so rather than leave the pod running
+			slog.Error("suspend: delete pod failed", "tenant", tenantID, "pod", rec.PodName, "err", err)
+			http.Error(w, "tenant suspended but its pod could not be stopped", http.StatusInternalServerError)
+			return
+		}
+	}
+	slog.Info("suspend: tenant suspended", "tenant", tenantID, "pod", rec.PodName)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuspendResult{TenantID: tenantID, Status: registry.StatusSuspended, PodName: rec.PodName})
+}
+
+// ResumeTenant lifts a suspension. The tenant becomes idle and its next
+// message wakes it as usual.
+func (h *Handler) ResumeTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	err = h.reg.ResumeTenant(ctx, tenantID)
+	if errors.Is(err, registry.ErrNotSuspended) {
+		http.Error(w, "tenant not suspended", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error("resume: update status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("resume: tenant resumed", "tenant", tenantID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuspendResult{TenantID: tenantID, Status: registry.StatusIdle})
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSuspendAndResumeTenant(t *testing.T) {
+	h, reg, _, cs := newTestHandler(t)
+	ctx := context.Background()
+
+	_, err := cs.CoreV1().Pods("tenants").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:  "alice",
+		Status:    registry.StatusRunning,
+		PodName:   "zeroclaw-alice",
+		PodIP:     "10.0.0.1",
+		Namespace: "tenants",
+	}))
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/suspend", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result api.SuspendResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.Equal(t, registry.StatusSuspended, result.Status)
+	assert.Equal(t, "zeroclaw-alice", result.PodName)
+
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusSuspended, tenant.Status)
+	assert.Empty(t, tenant.PodIP)
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	assert.Error(t, err, "pod should be deleted")
+
+	// Suspended tenants can't be woken, and nothing else moves them out of
+	// the status
+	for _, path := range []string{"/wake/alice", "/wake/alice?async=true"} {
+		rec = httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusLocked, rec.Code, path)
+	}
+	assert.ErrorIs(t, reg.UpdateStatus(ctx, "alice", registry.StatusIdle, "", ""), registry.ErrSuspended)
+
+	// Suspending again is a no-op
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/suspend", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/resume", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/alice/resume", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestSuspendTenant_WakeInProgress(t *testing.T) {
+	h, reg, locker, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusProvisioning, Namespace: "tenants"}))
+	acquired, err := locker.AcquireWakeLock(ctx, "bob", 0)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/bob/suspend", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	tenant, _ := reg.GetTenant(ctx, "bob")
+	assert.Equal(t, registry.StatusProvisioning, tenant.Status)
+
+	rec = httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/nobody/suspend", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
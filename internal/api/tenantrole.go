@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/tenantrole"
+)
+
+// tenantRoleKey names a tenant's IAM role. Environments share the AWS
+// account, so a named environment's roles carry its name.
+func (h *Handler) tenantRoleKey(tenantID string) string {
+	if h.cfg.Environment.IsDefault() {
+		return tenantID
+	}
+	return h.cfg.Environment.Name + "." + tenantID
+}
+
+// tenantIdentities reports whether tenants get their own ServiceAccount and
+// IAM role
+func (h *Handler) tenantIdentities() bool {
+	return h.cfg.TenantRoles != nil && h.k8s != nil
+}
+
+// ensureTenantIdentity creates or updates the tenant's IAM role, then the
+// ServiceAccount in ns annotated to assume it. Records without a prefix get
+// the environment's, never one covering the whole bucket.
+func (h *Handler) ensureTenantIdentity(ctx context.Context, rec *registry.TenantRecord, ns string) error {
+	prefix := rec.S3Prefix
+	if prefix == "" {
+		prefix = h.cfg.Environment.S3Prefix(rec.TenantID)
+	}
+	arn, err := h.cfg.TenantRoles.Ensure(ctx, tenantrole.Role{
+		Key:            h.tenantRoleKey(rec.TenantID),
+		TenantID:       rec.TenantID,
+		Namespace:      ns,
+		ServiceAccount: contract.ServiceAccountName(rec.TenantID),
+		S3Prefix:       prefix,
+	})
+	if err != nil {
+		return fmt.Errorf("tenant role: %w", err)
+	}
+	if err := h.k8s.EnsureTenantServiceAccount(ctx, rec.TenantID, ns, arn); err != nil {
+		return fmt.Errorf("service account: %w", err)
+	}
+	return nil
+}
+
+// tenantServiceAccount returns the ServiceAccount the tenant's pods run as
+// ("" for the shared one), first creating it and its role if they are
+// missing, e.g. for a tenant created before per-tenant roles were enabled
+func (h *Handler) tenantServiceAccount(ctx context.Context, rec *registry.TenantRecord, ns string) (string, error) {
+	if !h.tenantIdentities() {
+		return "", nil
+	}
+	ok, err := h.k8s.TenantServiceAccountExists(ctx, rec.TenantID, ns)
+	if err != nil {
+		return "", fmt.Errorf("service account: %w", err)
+	}
+	if !ok {
+		if err := h.ensureTenantIdentity(ctx, rec, ns); err != nil {
+			return "", err
+		}
+		slog.Info("provisioned tenant service account", "tenant", rec.TenantID, "namespace", ns)
+	}
+	return contract.ServiceAccountName(rec.TenantID), nil
+}
+
+// deleteTenantIdentity removes the tenant's ServiceAccount, then its role
+func (h *Handler) deleteTenantIdentity(ctx context.Context, tenantID, ns string) error {
+	if err := h.k8s.DeleteTenantServiceAccount(ctx, tenantID, ns); err != nil {
+		return fmt.Errorf("service account: %w", err)
+	}
+	if err := h.cfg.TenantRoles.Delete(ctx, h.tenantRoleKey(tenantID)); err != nil {
+		return fmt.Errorf("tenant role: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/environment"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/tenantrole"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTenantRoles_Lifecycle(t *testing.T) {
+	roles := tenantrole.NewMemory()
+	cs := fake.NewSimpleClientset()
+	h := api.New(registry.NewMock(), k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		Environment:  environment.Environment{Name: "staging"},
+		PodReadyWait: 5 * time.Second,
+		TenantRoles:  roles,
+	})
+	ctx := context.Background()
+
+	body, _ := json.Marshal(map[string]string{"tenant_id": "alice"})
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	role, ok := roles.Get("staging.alice")
+	require.True(t, ok, "roles are named per environment")
+	assert.Equal(t, "staging/tenants/alice/", role.S3Prefix)
+	assert.Equal(t, "tenants", role.Namespace)
+	assert.Equal(t, "zeroclaw-alice", role.ServiceAccount)
+	sa, err := cs.CoreV1().ServiceAccounts("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, tenantrole.MemoryARN("staging.alice"), sa.Annotations["eks.amazonaws.com/role-arn"])
+
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wake/alice", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	pod, err := cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "zeroclaw-alice", pod.Spec.ServiceAccountName)
+
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tenants/alice?dry_run=true", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "service account tenants/zeroclaw-alice and its IAM role")
+
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tenants/alice", nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	_, ok = roles.Get("staging.alice")
+	assert.False(t, ok)
+	_, err = cs.CoreV1().ServiceAccounts("tenants").Get(ctx, "zeroclaw-alice", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+// TestTenantRoles_WakeProvisionsExistingTenant: tenants created before roles
+// were enabled get theirs on their next wake
+func TestTenantRoles_WakeProvisionsExistingTenant(t *testing.T) {
+	roles := tenantrole.NewMemory()
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		TenantRoles:  roles,
+	})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "bob", Status: registry.StatusIdle, Namespace: "tenants", S3Prefix: "tenants/bob/",
+	}))
+
+	simulatePodReady(cs, "bob", "tenants", "10.0.0.2")
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wake/bob", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	role, ok := roles.Get("bob")
+	require.True(t, ok)
+	assert.Equal(t, "tenants/bob/", role.S3Prefix)
+	pod, err := cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-bob", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "zeroclaw-bob", pod.Spec.ServiceAccountName)
+}
+
+// TestTenantRoles_EmptyPrefixFallsBack: a record without an S3 prefix must
+// not yield a role scoped to the whole bucket
+func TestTenantRoles_EmptyPrefixFallsBack(t *testing.T) {
+	roles := tenantrole.NewMemory()
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		Environment:  environment.Environment{Name: "staging"},
+		PodReadyWait: 5 * time.Second,
+		TenantRoles:  roles,
+	})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "carol", Status: registry.StatusIdle, Namespace: "tenants",
+	}))
+
+	simulatePodReady(cs, "carol", "tenants", "10.0.0.3")
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/wake/carol", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	role, ok := roles.Get("staging.carol")
+	require.True(t, ok)
+	assert.Equal(t, "staging/tenants/carol/", role.S3Prefix)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// testReplyMaxBytes caps the agent reply a test message returns
+const testReplyMaxBytes = 1 << 20
+
+// TestMessageResult is the agent's answer to a test message, with how long
+// the wake and the reply took
+type TestMessageResult struct {
+	TenantID  string `json:"tenant_id"`
+	PodIP     string `json:"pod_ip"`
+	ColdStart bool   `json:"cold_start"` // the pod wasn't running
+	WakeMs    int64  `json:"wake_ms"`
+	ReplyMs   int64  `json:"reply_ms"`
+	Reply     string `json:"reply"`
+}
+
+// TestMessage wakes the tenant's pod and sends its agent a message, as the
+// router would for a Telegram update, returning the reply instead of
+// delivering it anywhere: POST /tenants/{tenantID}/test-message with
+// {"text": "..."}. It is meant for smoke tests after a deployment, so the
+// message isn't metered. Refused wakes get Wake's statuses; an agent that
+// can't be reached or answers with an error is a 502.
+func (h *Handler) TestMessage(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		http.Error(w, `body must be {"text": "..."}`, http.StatusBadRequest)
+		return
+	}
+	before, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if before == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	start := time.Now()
+	rec, err := h.wakeOrGet(ctx, tenantID)
+	if writeWakeError(w, tenantID, err) {
+		return
+	}
+	result := TestMessageResult{
+		TenantID:  tenantID,
+		PodIP:     rec.PodIP,
+		ColdStart: before.Status != registry.StatusRunning || before.PodIP == "",
+		WakeMs:    time.Since(start).Milliseconds(),
+	}
+
+	start = time.Now()
+	reply, err := h.askAgent(ctx, rec.PodIP, req.Text)
+	if err != nil {
+		slog.Warn("test message failed", "tenant", tenantID, "pod_ip", rec.PodIP, "err", err)
+		http.Error(w, "agent: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	result.ReplyMs = time.Since(start).Milliseconds()
+	result.Reply = reply
+	slog.Info("test message answered", "tenant", tenantID, "cold_start", result.ColdStart, "wake_ms", result.WakeMs, "reply_ms", result.ReplyMs)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// askAgent posts text to the agent's webhook as the router does, asking for
+// a plain JSON reply rather than a stream
+func (h *Handler) askAgent(ctx context.Context, podIP, text string) (string, error) {
+	payload, _ := json.Marshal(contract.AgentMessage{Message: text})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, contract.AgentURL(podIP, contract.AgentWebhookPath), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(contract.AgentVersionHeader, contract.AgentVersion)
+	req.Header.Set("Accept", "application/json")
+	client := h.cfg.PodClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, testReplyMaxBytes))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var reply contract.AgentReply
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return "", fmt.Errorf("unreadable reply: %w", err)
+	}
+	return reply.Text, nil
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// agentTransport sends every pod request to agent, recording the host the
+// handler dialled
+type agentTransport struct {
+	agent *url.URL
+	hosts []string
+}
+
+func (t *agentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.hosts = append(t.hosts, req.URL.Host)
+	req.URL.Scheme, req.URL.Host = t.agent.Scheme, t.agent.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTestMessage(t *testing.T) {
+	var got map[string]string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		if got["message"] == "fail" {
+			http.Error(w, "model unavailable", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"response": "pong"})
+	}))
+	defer agent.Close()
+	agentURL, _ := url.Parse(agent.URL)
+	transport := &agentTransport{agent: agentURL}
+
+	reg := registry.NewMock()
+	cs := fake.NewSimpleClientset()
+	h := api.New(reg, k8sclient.New(cs, k8sclient.Config{ZeroClawImage: "zeroclaw:test"}), lock.NewMock(), nil, nil, api.Config{
+		Namespace:    "tenants",
+		PodReadyWait: 5 * time.Second,
+		PodClient:    &http.Client{Transport: transport},
+	})
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusSuspended, Namespace: "tenants"}))
+
+	send := func(id, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tenants/"+id+"/test-message", strings.NewReader(body)))
+		return rec
+	}
+
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.7")
+	rec := send("alice", `{"text": "ping"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result api.TestMessageResult
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.Equal(t, "pong", result.Reply)
+	assert.Equal(t, "10.0.0.7", result.PodIP)
+	assert.True(t, result.ColdStart)
+	assert.Equal(t, "ping", got["message"])
+	assert.Equal(t, []string{"10.0.0.7:3000"}, transport.hosts)
+
+	// The pod is running now, so the second message is warm
+	rec = send("alice", `{"text": "ping"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+	assert.False(t, result.ColdStart)
+
+	rec = send("alice", `{"text": "fail"}`)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "model unavailable")
+
+	assert.Equal(t, http.StatusBadRequest, send("alice", `{"text": " "}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("carol", `{"text": "ping"}`).Code)
+	assert.Equal(t, http.StatusLocked, send("bob", `{"text": "ping"}`).Code)
+}
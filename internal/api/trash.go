@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/events"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// purgeInterval is how often the purger looks for deleted tenants whose
+// retention has run out
+const purgeInterval = 10 * time.Minute
+
+// teardownTenant removes what a deleted tenant runs on: its pod, volume,
+// identity, router caches and queued updates, and its Telegram webhook. What
+// a restore needs (the record, S3 state and bot token) is left to
+// purgeTenant. Failures are logged; the tenant is deleted regardless.
+func (h *Handler) teardownTenant(ctx context.Context, rec *registry.TenantRecord) {
+	tenantID := rec.TenantID
+	if rec.PodName != "" && h.k8s != nil {
+		grace := h.k8s.GracePeriod(k8sclient.OpDelete, rec.Tier)
+		if err := h.k8s.DeletePod(ctx, rec.PodName, rec.Namespace, grace); err != nil {
+			slog.Error("delete pod failed", "tenant", tenantID, "err", err)
+		}
+	}
+	if h.k8s != nil {
+		if err := h.k8s.DeletePVC(ctx, tenantID, rec.Namespace); err != nil {
+			slog.Error("delete PVC failed", "tenant", tenantID, "err", err)
+		}
+		if err := h.k8s.DeleteBotTokenSecret(ctx, tenantID, rec.Namespace); err != nil {
+			slog.Error("delete bot token secret failed", "tenant", tenantID, "err", err)
+		}
+	}
+	if h.tenantIdentities() {
+		if err := h.deleteTenantIdentity(ctx, tenantID, rec.Namespace); err != nil {
+			slog.Error("delete tenant identity failed", "tenant", tenantID, "err", err)
+		}
+	}
+	// Clear Redis endpoint and token caches so Router doesn't serve stale
+	// entries, and drop updates still queued or dead-lettered for the tenant
+	if h.rdb != nil {
+		if err := h.rdb.Del(ctx, h.redisKey(contract.EndpointPrefix, tenantID), h.redisKey(contract.BotTokenPrefix, tenantID),
+			h.redisKey(contract.SlackPrefix, tenantID), h.redisKey(contract.WebhookSecretPrefix, tenantID),
+			h.redisKey(contract.LocalePrefix, tenantID), h.redisKey(contract.MaxAgePrefix, tenantID),
+			h.redisKey(contract.MaxReplyPrefix, tenantID),
+			h.redisKey(contract.QueuePrefix, tenantID), h.redisKey(contract.DLQPrefix, tenantID)).Err(); err != nil {
+			slog.Warn("delete tenant: failed to clear Redis cache", "tenant", tenantID, "err", err)
+		}
+	}
+	// Remove the Telegram webhook; a restore registers it again
+	if h.tg != nil && rec.HasBot() {
+		token, err := h.botToken(ctx, rec)
+		if err == nil {
+			err = h.tg.DeleteWebhook(ctx, token)
+		}
+		if err != nil {
+			slog.Warn("delete tenant: failed to remove webhook", "tenant", tenantID, "err", err)
+		} else {
+			slog.Info("webhook deleted", "tenant", tenantID)
+		}
+	}
+}
+
+// purgeTenant removes a deleted tenant for good: its bot token secret, its
+// S3 state and snapshots, then its record. It stops at the first failure, leaving the
+// record for the purger to retry.
+func (h *Handler) purgeTenant(ctx context.Context, rec *registry.TenantRecord) error {
+	tenantID := rec.TenantID
+	if rec.BotTokenRef != "" && h.cfg.Secrets != nil {
+		if err := h.cfg.Secrets.Delete(ctx, rec.BotTokenRef); err != nil {
+			return fmt.Errorf("delete bot token secret %s: %w", rec.BotTokenRef, err)
+		}
+	}
+	objects := 0
+	if h.cfg.StateDeleter != nil && rec.S3Prefix != "" {
+		n, err := h.cfg.StateDeleter.Delete(ctx, rec.S3Prefix)
+		if err != nil {
+			return fmt.Errorf("delete state %s: %w", rec.S3Prefix, err)
+		}
+		objects = n
+	}
+	if h.cfg.Snapshots != nil {
+		prefix := h.cfg.Environment.SnapshotPrefix(tenantID)
+		if _, err := h.cfg.Snapshots.Delete(ctx, prefix); err != nil {
+			return fmt.Errorf("delete snapshots %s: %w", prefix, err)
+		}
+	}
+	if err := h.reg.DeleteTenant(ctx, tenantID); err != nil {
+		return fmt.Errorf("delete record: %w", err)
+	}
+	slog.Info("tenant purged", "tenant", tenantID, "state_objects", objects)
+	h.cfg.Events.Publish(events.TenantPurged, h.cfg.Environment.Name, tenantID, nil)
+	return nil
+}
+
+// RestoreTenant brings back a deleted tenant before it is purged. It
+// becomes idle with its state and settings as they were, its webhook is
+// registered again, and its next message wakes it.
+func (h *Handler) RestoreTenant(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	ctx := r.Context()
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	err = h.reg.RestoreTenant(ctx, tenantID)
+	if errors.Is(err, registry.ErrNotTerminated) {
+		http.Error(w, "tenant not deleted", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error("restore tenant: update status failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	rec.Status = registry.StatusIdle
+	rec.DeletedAt, rec.PurgeAt = time.Time{}, time.Time{}
+	if h.tg != nil && rec.HasBot() {
+		if err := h.registerOrQueueWebhook(ctx, rec); err != nil {
+			slog.Warn("restore tenant: webhook registration failed (tenant restored, fix manually)", "tenant", tenantID, "err", err)
+		}
+	}
+	slog.Info("tenant restored", "tenant", tenantID)
+	h.cfg.Events.Publish(events.TenantRestored, h.cfg.Environment.Name, tenantID, nil)
+
+	redact(rec)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rec)
+}
+
+// RunPurges purges deleted tenants whose retention has run out, every 10
+// minutes until ctx is cancelled. Run it on the lifecycle leader only.
+func (h *Handler) RunPurges(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		h.purgeDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDue makes one pass over the deleted tenants
+func (h *Handler) purgeDue(ctx context.Context, now time.Time) {
+	tenants, err := h.reg.ListByStatus(ctx, registry.StatusTerminated)
+	if err != nil {
+		slog.Error("purger: failed to list deleted tenants", "err", err)
+		return
+	}
+	for _, rec := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if rec.PurgeAt.After(now) {
+			continue
+		}
+		if err := h.purgeTenant(ctx, rec); err != nil {
+			slog.Error("purger: purge failed", "tenant", rec.TenantID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTrashHandler(t *testing.T) (*api.Handler, *registry.MockClient, *statesize.Memory) {
+	t.Helper()
+	reg := registry.NewMock()
+	state := statesize.NewMemory()
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{ZeroClawImage: "zeroclaw:test"})
+	h := api.New(reg, k8s, lock.NewMock(), nil, nil, api.Config{
+		Namespace:       "tenants",
+		StateDeleter:    state,
+		DeleteRetention: 7 * 24 * time.Hour,
+	})
+	return h, reg, state
+}
+
+func TestDeleteTenant_KeptUntilPurged(t *testing.T) {
+	h, reg, state := newTrashHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID: "alice", Status: registry.StatusIdle, Namespace: "tenants", S3Prefix: "tenants/alice/",
+	}))
+	state.Put("tenants/alice/memory.db", 100)
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	rec := do(http.MethodDelete, "/tenants/alice?dry_run=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var plan struct {
+		Keeps   []string  `json:"keeps"`
+		PurgeAt time.Time `json:"purge_at"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&plan))
+	assert.Equal(t, []string{"registry record", "S3 state tenants/alice/"}, plan.Keeps[:2])
+	assert.False(t, plan.PurgeAt.IsZero())
+
+	before := time.Now()
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice").Code)
+	tenant, err := reg.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	require.NotNil(t, tenant, "the record is kept for a restore")
+	assert.Equal(t, registry.StatusTerminated, tenant.Status)
+	assert.WithinDuration(t, before.Add(7*24*time.Hour), tenant.PurgeAt, time.Minute)
+	size, _ := state.Size(ctx, "tenants/alice/")
+	assert.EqualValues(t, 100, size, "state is kept for a restore")
+
+	// A deleted tenant can't be woken or suspended, and deleting it again
+	// is a no-op
+	assert.Equal(t, http.StatusGone, do(http.MethodPost, "/wake/alice").Code)
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/tenants/alice/suspend").Code)
+	assert.ErrorIs(t, reg.UpdateStatus(ctx, "alice", registry.StatusIdle, "", ""), registry.ErrTerminated)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice").Code)
+
+	rec = do(http.MethodPost, "/tenants/alice/restore")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.StatusIdle, tenant.Status)
+	assert.True(t, tenant.PurgeAt.IsZero())
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/tenants/alice/restore").Code, "not deleted")
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/tenants/bob/restore").Code)
+
+	// purge=true deletes everything at once
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/tenants/alice?purge=true").Code)
+	tenant, _ = reg.GetTenant(ctx, "alice")
+	assert.Nil(t, tenant)
+	size, _ = state.Size(ctx, "tenants/alice/")
+	assert.Zero(t, size)
+}
+
+func TestRunPurges(t *testing.T) {
+	h, reg, state := newTrashHandler(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now()
+	for _, rec := range []*registry.TenantRecord{
+		{TenantID: "alice", Status: registry.StatusTerminated, S3Prefix: "tenants/alice/", PurgeAt: now.Add(-time.Minute)},
+		{TenantID: "bob", Status: registry.StatusTerminated, S3Prefix: "tenants/bob/", PurgeAt: now.Add(time.Hour)},
+		{TenantID: "carol", Status: registry.StatusIdle, S3Prefix: "tenants/carol/"},
+	} {
+		require.NoError(t, reg.CreateTenant(ctx, rec))
+		state.Put(rec.S3Prefix+"memory.db", 100)
+	}
+
+	go h.RunPurges(ctx)
+
+	assert.Eventually(t, func() bool {
+		tenant, _ := reg.GetTenant(ctx, "alice")
+		return tenant == nil
+	}, 2*time.Second, 10*time.Millisecond, "retention ran out")
+	size, _ := state.Size(ctx, "tenants/alice/")
+	assert.Zero(t, size)
+	for _, id := range []string{"bob", "carol"} {
+		tenant, _ := reg.GetTenant(ctx, id)
+		assert.NotNil(t, tenant, id)
+		size, _ := state.Size(ctx, "tenants/"+id+"/")
+		assert.EqualValues(t, 100, size, id)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// usageResponse is a tenant's usage over a range of UTC days
+type usageResponse struct {
+	TenantID string              `json:"tenant_id"`
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Total    registry.Usage      `json:"total"`
+	Days     []registry.UsageDay `json:"days"` // only days with usage
+	// Months are each calendar month the range touches: its distinct chats
+	// over the whole month, and the messages of its days within the range
+	Months []registry.UsageMonth `json:"months"`
+}
+
+// GetUsage returns the tenant's metered usage (messages delivered, distinct
+// chats, wakes and pod-seconds) per UTC day, with the total and the monthly
+// active chats, for billing and engagement:
+// GET /tenants/{tenantID}/usage?from=YYYY-MM-DD&to=YYYY-MM-DD. Both ends are
+// inclusive; from defaults to the first of the current month and to to
+// today. Deleted tenants' usage stays readable.
+func (h *Handler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	now := time.Now().UTC()
+	from, err := usageDate(r.URL.Query().Get("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		http.Error(w, "from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := usageDate(r.URL.Query().Get("to"), now.Truncate(24*time.Hour))
+	if err != nil {
+		http.Error(w, "to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if to.Before(from) {
+		http.Error(w, "to is before from", http.StatusBadRequest)
+		return
+	}
+	if days := int(to.Sub(from)/(24*time.Hour)) + 1; days > registry.MaxUsageDays {
+		http.Error(w, fmt.Sprintf("range is %d days; at most %d", days, registry.MaxUsageDays), http.StatusBadRequest)
+		return
+	}
+
+	days, err := h.reg.ListUsage(r.Context(), tenantID, from, to)
+	if err != nil {
+		slog.Error("list usage failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	months, err := h.reg.ListChatMonths(r.Context(), tenantID, from, to)
+	if err != nil {
+		slog.Error("list chat months failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	resp := usageResponse{
+		TenantID: tenantID,
+		From:     from.Format(registry.UsageDateFormat),
+		To:       to.Format(registry.UsageDateFormat),
+		Days:     days,
+		Months:   usageByMonth(days, months),
+	}
+	if resp.Days == nil {
+		resp.Days = []registry.UsageDay{}
+	}
+	for _, d := range days {
+		resp.Total = resp.Total.Add(d.Usage)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// usageByMonth returns a month for each of months and each month of days,
+// oldest first, with the messages of its days
+func usageByMonth(days []registry.UsageDay, months []registry.UsageMonth) []registry.UsageMonth {
+	result := []registry.UsageMonth{}
+	index := map[string]int{}
+	for _, m := range months {
+		index[m.Month] = len(result)
+		result = append(result, m)
+	}
+	for _, d := range days {
+		month := d.Date[:len(registry.UsageMonthFormat)]
+		i, ok := index[month]
+		if !ok {
+			i = len(result)
+			index[month] = i
+			result = append(result, registry.UsageMonth{Month: month})
+		}
+		result[i].Messages += d.Messages
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Month < result[j].Month })
+	return result
+}
+
+// usageDate parses a YYYY-MM-DD query parameter, or returns def if it is empty
+func usageDate(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.Parse(registry.UsageDateFormat, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want YYYY-MM-DD, got %q", s)
+	}
+	return d, nil
+}
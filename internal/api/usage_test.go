@@ -0,0 +1,83 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsage(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusRunning}))
+
+	// Each activity report from the router is one delivered message, with
+	// its text volume if the router sends it
+	for _, body := range []string{"", `{"chars_in":10,"chars_out":7}`, `{"chars_in":1}`} {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/tenants/alice/activity", strings.NewReader(body)))
+		require.Equal(t, http.StatusNoContent, w.Code, body)
+	}
+	for _, body := range []string{`{"chars_in":-1}`, `not json`} {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/tenants/alice/activity", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	require.NoError(t, reg.AddUsage(ctx, "alice", yesterday, registry.Usage{Wakes: 1, PodSeconds: 600}))
+	require.NoError(t, reg.SetChats(ctx, "alice", yesterday.Format(registry.UsageDateFormat), 2))
+	require.NoError(t, reg.SetChats(ctx, "alice", yesterday.Format(registry.UsageMonthFormat), 7))
+
+	from := yesterday.Format(registry.UsageDateFormat)
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/usage?from="+from, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var got struct {
+		From   string                `json:"from"`
+		Total  registry.Usage        `json:"total"`
+		Days   []registry.UsageDay   `json:"days"`
+		Months []registry.UsageMonth `json:"months"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, from, got.From)
+	// Tokens are rounded up per message: 10 chars are 3, 7 are 2, 1 is 1
+	assert.Equal(t, registry.Usage{
+		Messages: 3, Wakes: 1, PodSeconds: 600,
+		CharsIn: 11, CharsOut: 7, TokensIn: 4, TokensOut: 2,
+	}, got.Total)
+	require.Len(t, got.Days, 2)
+	assert.Equal(t, int64(600), got.Days[0].PodSeconds)
+	assert.Equal(t, int64(2), got.Days[0].Chats)
+	assert.Equal(t, int64(3), got.Days[1].Messages)
+
+	// Yesterday may fall in last month, so the months are checked together
+	var messages int64
+	chats := map[string]int64{}
+	for _, m := range got.Months {
+		messages += m.Messages
+		chats[m.Month] = m.Chats
+	}
+	assert.Equal(t, int64(3), messages)
+	assert.Equal(t, int64(7), chats[yesterday.Format(registry.UsageMonthFormat)])
+}
+
+func TestGetUsage_BadRange(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	for _, q := range []string{
+		"from=March",
+		"from=2026-03-02&to=2026-03-01",
+		"from=2024-01-01&to=2026-01-01",
+	} {
+		w := httptest.NewRecorder()
+		h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/usage?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
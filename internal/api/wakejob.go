@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+const (
+	wakeJobPrefix = "wakejob:"
+	// wakeJobTTL is how long a job can be polled after it was last updated
+	wakeJobTTL = 15 * time.Minute
+)
+
+// WakeJobStatus is the state of an async wake
+type WakeJobStatus string
+
+const (
+	WakeJobPending      WakeJobStatus = "pending"      // accepted, not started yet
+	WakeJobProvisioning WakeJobStatus = "provisioning" // pod being started (or another replica's wake awaited)
+	WakeJobRestoring    WakeJobStatus = "restoring"    // pod running, agent restoring its state
+	WakeJobReady        WakeJobStatus = "ready"        // pod running; pod_ip is set
+	WakeJobFailed       WakeJobStatus = "failed"       // see error
+)
+
+// WakeJob is an async wake, as returned by POST /wake/{id}?async=true and
+// GET /wake-jobs/{jobID}
+type WakeJob struct {
+	ID           string        `json:"job_id"`
+	TenantID     string        `json:"tenant_id"`
+	Status       WakeJobStatus `json:"status"`
+	PodIP        string        `json:"pod_ip,omitempty"`
+	IdleTimeoutS int64         `json:"idle_timeout_s,omitempty"`
+	// RestoreProgress and RestoreMessage are the agent's last restore
+	// status while restoring
+	RestoreProgress int       `json:"restore_progress,omitempty"`
+	RestoreMessage  string    `json:"restore_message,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// wakeJobMemory holds jobs when there is no Redis (local mode). Jobs are
+// then only visible to the replica that created them.
+type wakeJobMemory struct {
+	mu   sync.Mutex
+	jobs map[string]WakeJob
+}
+
+// wakeAsync starts a wake in the background and answers 202 with the job to
+// poll. Region, suspension, kill switch and quota checks happen up front so
+// misdirected wakes still get 421, suspended tenants 423, switched-off ones
+// 503 and tenants over their storage quota 507.
+func (h *Handler) wakeAsync(w http.ResponseWriter, r *http.Request, tenantID string) {
+	ctx := r.Context()
+	if h.k8s == nil {
+		http.Error(w, "failed to wake tenant", http.StatusServiceUnavailable)
+		return
+	}
+	rec, err := h.reg.GetTenant(ctx, tenantID)
+	if err != nil {
+		slog.Error("wake failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	var misdirected *MisdirectedError
+	if errors.As(h.checkHome(rec), &misdirected) {
+		writeMisdirected(w, misdirected)
+		return
+	}
+	if rec != nil && rec.Status == registry.StatusSuspended {
+		slog.Info("wake refused: tenant suspended", "tenant", tenantID)
+		writeSuspended(w)
+		return
+	}
+	if rec != nil && rec.Status == registry.StatusTerminated {
+		slog.Info("wake refused: tenant deleted", "tenant", tenantID)
+		writeTerminated(w)
+		return
+	}
+	var failed *FailedError
+	if errors.As(checkFailed(rec), &failed) {
+		slog.Info("wake refused: tenant failed", "tenant", tenantID, "last_error", failed.LastError)
+		writeFailed(w, failed)
+		return
+	}
+	var disabled *DisabledError
+	if err := h.checkDisabled(ctx, rec); errors.As(err, &disabled) {
+		slog.Info("wake refused: kill switch", "tenant", tenantID, "global", disabled.Global)
+		writeDisabled(w, disabled)
+		return
+	} else if err != nil {
+		slog.Error("wake failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	running := rec != nil && rec.Status == registry.StatusRunning && rec.PodIP != ""
+	var overQuota *QuotaExceededError
+	if !running && errors.As(h.checkStateQuota(rec), &overQuota) {
+		slog.Warn("wake refused: state quota exceeded", "tenant", tenantID, "bytes", overQuota.Bytes, "quota_bytes", overQuota.QuotaBytes)
+		writeQuotaExceeded(w, overQuota)
+		return
+	}
+	if !running {
+		var overRun *RunQuotaError
+		if err := h.checkRunQuota(ctx, rec, tenantID); errors.As(err, &overRun) {
+			slog.Warn("wake refused: run quota exceeded", "tenant", tenantID, "limit", overRun.Limit, "max", overRun.Max)
+			writeRunQuotaExceeded(w, overRun)
+			return
+		} else if err != nil {
+			slog.Error("wake failed", "tenant", tenantID, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	now := time.Now().UTC()
+	job := WakeJob{ID: hex.EncodeToString(b), TenantID: tenantID, Status: WakeJobPending, CreatedAt: now, UpdatedAt: now}
+	if running {
+		job.Status, job.PodIP, job.IdleTimeoutS = WakeJobReady, rec.PodIP, rec.IdleTimeoutS
+	}
+	if err := h.saveWakeJob(ctx, job); err != nil {
+		slog.Error("save wake job", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !running {
+		go h.runWakeJob(job)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// runWakeJob wakes the tenant and records the outcome on the job
+func (h *Handler) runWakeJob(job WakeJob) {
+	// Long enough to wait out another replica's wake, then run our own
+	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.WakeLockTTL+h.cfg.PodReadyWait)
+	defer cancel()
+
+	job.Status, job.UpdatedAt = WakeJobProvisioning, time.Now().UTC()
+	if err := h.saveWakeJob(ctx, job); err != nil {
+		slog.Warn("save wake job", "tenant", job.TenantID, "job", job.ID, "err", err)
+	}
+
+	rec, err := h.wake(ctx, job.TenantID, func(status RestoreStatus) {
+		job.Status, job.UpdatedAt = WakeJobRestoring, time.Now().UTC()
+		job.RestoreProgress, job.RestoreMessage = status.Progress, status.Message
+		if err := h.saveWakeJob(ctx, job); err != nil {
+			slog.Warn("save wake job", "tenant", job.TenantID, "job", job.ID, "err", err)
+		}
+	})
+	if err != nil {
+		slog.Error("async wake failed", "tenant", job.TenantID, "job", job.ID, "err", err)
+		job.Status, job.Error = WakeJobFailed, err.Error()
+	} else {
+		job.Status, job.PodIP, job.IdleTimeoutS = WakeJobReady, rec.PodIP, rec.IdleTimeoutS
+	}
+	job.RestoreProgress, job.RestoreMessage = 0, ""
+	job.UpdatedAt = time.Now().UTC()
+
+	// Poll clients are waiting on this, even if the wake used up ctx
+	saveCtx, saveCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer saveCancel()
+	if err := h.saveWakeJob(saveCtx, job); err != nil {
+		slog.Error("save wake job", "tenant", job.TenantID, "job", job.ID, "err", err)
+	}
+}
+
+// GetWakeJob reports an async wake's progress
+func (h *Handler) GetWakeJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.loadWakeJob(r.Context(), chi.URLParam(r, "jobID"))
+	if err != nil {
+		slog.Error("load wake job", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "wake job not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// saveWakeJob stores the job in Redis, so any replica can answer polls
+func (h *Handler) saveWakeJob(ctx context.Context, job WakeJob) error {
+	if h.rdb == nil {
+		h.wakeJobs.mu.Lock()
+		defer h.wakeJobs.mu.Unlock()
+		if h.wakeJobs.jobs == nil {
+			h.wakeJobs.jobs = make(map[string]WakeJob)
+		}
+		for id, j := range h.wakeJobs.jobs {
+			if time.Since(j.UpdatedAt) > wakeJobTTL {
+				delete(h.wakeJobs.jobs, id)
+			}
+		}
+		h.wakeJobs.jobs[job.ID] = job
+		return nil
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return h.rdb.Set(ctx, h.redisKey(wakeJobPrefix, job.ID), data, wakeJobTTL).Err()
+}
+
+// loadWakeJob returns nil for unknown or expired jobs
+func (h *Handler) loadWakeJob(ctx context.Context, id string) (*WakeJob, error) {
+	if h.rdb == nil {
+		h.wakeJobs.mu.Lock()
+		defer h.wakeJobs.mu.Unlock()
+		job, ok := h.wakeJobs.jobs[id]
+		if !ok || time.Since(job.UpdatedAt) > wakeJobTTL {
+			return nil, nil
+		}
+		return &job, nil
+	}
+	data, err := h.rdb.Get(ctx, h.redisKey(wakeJobPrefix, id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job WakeJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,64 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWakeAsync(t *testing.T) {
+	h, _, _, cs := newTestHandler(t)
+	router := h.Router()
+	simulatePodReady(cs, "alice", "tenants", "10.0.0.1")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/alice?async=true", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var job api.WakeJob
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	require.NotEmpty(t, job.ID)
+	assert.Equal(t, "alice", job.TenantID)
+	assert.Equal(t, api.WakeJobPending, job.Status)
+
+	// Poll until the background wake finishes
+	require.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wake-jobs/"+job.ID, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+		return job.Status == api.WakeJobReady
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "10.0.0.1", job.PodIP)
+}
+
+// TestWakeAsync_AlreadyRunning: the job is ready from the start
+func TestWakeAsync_AlreadyRunning(t *testing.T) {
+	h, reg, _, _ := newTestHandler(t)
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID: "bob", Status: registry.StatusRunning, PodIP: "10.0.0.5", Namespace: "tenants", IdleTimeoutS: 300,
+	})
+
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wake/bob?async=true", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var job api.WakeJob
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&job))
+	assert.Equal(t, api.WakeJobReady, job.Status)
+	assert.Equal(t, "10.0.0.5", job.PodIP)
+	assert.Equal(t, int64(300), job.IdleTimeoutS)
+}
+
+func TestGetWakeJob_NotFound(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wake-jobs/nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// Types of tenant change a watch reports
+const (
+	TenantAdded    = "added"
+	TenantModified = "modified"
+	TenantDeleted  = "deleted"
+)
+
+// watchKeepAlive is how long a quiet watch stream goes before a comment
+// line keeps proxies from closing it
+const watchKeepAlive = 15 * time.Second
+
+// TenantChange is an event of GET /tenants/watch. A deleted tenant carries
+// only its TenantID.
+type TenantChange struct {
+	Type   string                 `json:"type"`
+	Tenant *registry.TenantRecord `json:"tenant"`
+}
+
+// WatchTenants streams tenant changes as server-sent events, so operators
+// can follow wakes and idle stops live: GET /tenants/watch, or
+// ?tenant_id=alice for one tenant. Each event's data is a TenantChange. The
+// current tenants come first as added, then every change as modified or
+// deleted. The registry is polled every Config.WatchInterval, so changes
+// trail by up to that much and those in between are coalesced.
+func (h *Handler) WatchTenants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keep ingress-nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	rc := http.NewResponseController(w)
+	// A watch outlives any server write timeout
+	rc.SetWriteDeadline(time.Time{})
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	lastWrite := time.Now()
+	err := h.pollTenants(ctx, r.URL.Query().Get("tenant_id"), h.cfg.WatchInterval, nil,
+		func(typ string, rec *registry.TenantRecord) error {
+			if typ == "" {
+				if time.Since(lastWrite) < watchKeepAlive {
+					return nil
+				}
+				fmt.Fprint(w, ": keepalive\n\n")
+			} else {
+				data, _ := json.Marshal(TenantChange{Type: typ, Tenant: rec})
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+			lastWrite = time.Now()
+			return rc.Flush()
+		})
+	if err != nil && ctx.Err() == nil {
+		slog.Warn("watch tenants ended", "err", err)
+	}
+}
+
+// pollTenants polls the registry every interval until ctx is done, calling
+// send with each tenant a watch on tenantID (empty = all) covers that was
+// added, modified or deleted since the last poll; the first poll's tenants
+// are all added. Records are redacted first. same decides whether a tenant
+// changed (nil compares the records' JSON). After a poll without changes
+// send is called once with an empty type, for keepalives. It returns send's
+// error, or the registry's wrapped in a *watchError.
+func (h *Handler) pollTenants(ctx context.Context, tenantID string, interval time.Duration,
+	same func(a, b *registry.TenantRecord) bool, send func(typ string, rec *registry.TenantRecord) error) error {
+	if same == nil {
+		same = func(a, b *registry.TenantRecord) bool {
+			x, _ := json.Marshal(a)
+			y, _ := json.Marshal(b)
+			return bytes.Equal(x, y)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	seen := map[string]*registry.TenantRecord{}
+	for {
+		records, err := watched(ctx, h, tenantID)
+		if err != nil {
+			return &watchError{err}
+		}
+		current := make(map[string]*registry.TenantRecord, len(records))
+		changed := false
+		for _, rec := range sortedByID(records) {
+			redact(rec)
+			current[rec.TenantID] = rec
+			typ := TenantModified
+			if prev, ok := seen[rec.TenantID]; !ok {
+				typ = TenantAdded
+			} else if same(prev, rec) {
+				continue
+			}
+			changed = true
+			if err := send(typ, rec); err != nil {
+				return err
+			}
+		}
+		var deleted []string
+		for id := range seen {
+			if _, ok := current[id]; !ok {
+				deleted = append(deleted, id)
+			}
+		}
+		sort.Strings(deleted)
+		for _, id := range deleted {
+			changed = true
+			if err := send(TenantDeleted, &registry.TenantRecord{TenantID: id}); err != nil {
+				return err
+			}
+		}
+		if !changed {
+			if err := send("", nil); err != nil {
+				return err
+			}
+		}
+		seen = current
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchError is the registry failing under a watch
+type watchError struct{ err error }
+
+func (e *watchError) Error() string { return "list tenants: " + e.err.Error() }
+func (e *watchError) Unwrap() error { return e.err }
@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchTenants(t *testing.T) {
+	reg := registry.NewMock()
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{WatchInterval: 10 * time.Millisecond})
+	srv := httptest.NewServer(h.Router())
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle, BotToken: "123:secret"})
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusIdle})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/tenants/watch?tenant_id=alice", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	next := func() api.TenantChange {
+		t.Helper()
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				var change api.TenantChange
+				require.NoError(t, json.Unmarshal([]byte(data), &change))
+				return change
+			}
+		}
+		t.Fatalf("stream ended: %v", scanner.Err())
+		return api.TenantChange{}
+	}
+
+	change := next()
+	assert.Equal(t, api.TenantAdded, change.Type)
+	assert.Equal(t, "alice", change.Tenant.TenantID)
+	assert.Empty(t, change.Tenant.BotToken, "records are redacted")
+
+	reg.UpdateStatus(ctx, "bob", registry.StatusRunning, "zeroclaw-bob", "10.0.0.2")
+	reg.UpdateStatus(ctx, "alice", registry.StatusRunning, "zeroclaw-alice", "10.0.0.1")
+	change = next()
+	assert.Equal(t, api.TenantModified, change.Type)
+	assert.Equal(t, "alice", change.Tenant.TenantID, "only the watched tenant")
+	assert.Equal(t, registry.StatusRunning, change.Tenant.Status)
+
+	reg.DeleteTenant(ctx, "alice")
+	change = next()
+	assert.Equal(t, api.TenantDeleted, change.Type)
+	assert.Equal(t, "alice", change.Tenant.TenantID)
+}
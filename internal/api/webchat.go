@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+)
+
+const (
+	// chatLinkDefaultTTL is short: unlike a status link, a chat link lets
+	// its holder wake the agent and spend the tenant's quota
+	chatLinkDefaultTTL = 24 * time.Hour
+	chatLinkMaxTTL     = 90 * 24 * time.Hour
+)
+
+// ChatLink is the response of POST /tenants/{tenantID}/chat-link
+type ChatLink struct {
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateChatLink issues a signed link to the tenant's web chat page on the
+// router. Optional body: {"ttl_s": N} (default a day, at most 90 days).
+func (h *Handler) CreateChatLink(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	if len(h.cfg.WebChatSecret) == 0 || h.cfg.StatusPageURL == "" {
+		http.Error(w, "web chat not configured (WEB_CHAT_SECRET, ROUTER_PUBLIC_URL)", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		TTLS int64 `json:"ttl_s"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	ttl := chatLinkDefaultTTL
+	if req.TTLS != 0 {
+		ttl = time.Duration(req.TTLS) * time.Second
+		if ttl < time.Minute || ttl > chatLinkMaxTTL {
+			http.Error(w, "ttl_s must be between 60 and 7776000", http.StatusBadRequest)
+			return
+		}
+	}
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil || rec.Status == registry.StatusTerminated {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	expires := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	token := statuspage.SignChat(h.cfg.WebChatSecret, h.cfg.Environment.Name, tenantID, expires)
+	slog.Info("chat link issued", "tenant", tenantID, "expires_at", expires)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ChatLink{
+		TenantID:  tenantID,
+		URL:       h.cfg.StatusPageURL + "/chat/" + url.PathEscape(tenantID) + "?token=" + token,
+		ExpiresAt: expires,
+	})
+}
@@ -0,0 +1,55 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/statuspage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateChatLink(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Status: registry.StatusTerminated}))
+	secret := []byte("s3cret")
+	h := api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{
+		WebChatSecret: secret,
+		StatusPageURL: "https://router.example.com",
+	})
+	post := func(h *api.Handler, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(h, "/tenants/alice/chat-link", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var link api.ChatLink
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&link))
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), link.ExpiresAt, 5*time.Second)
+	token, ok := strings.CutPrefix(link.URL, "https://router.example.com/chat/alice?token=")
+	require.True(t, ok, link.URL)
+	tenant, err := statuspage.VerifyChat(secret, "", token, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "alice", tenant)
+
+	assert.Equal(t, http.StatusOK, post(h, "/tenants/alice/chat-link", `{"ttl_s":3600}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(h, "/tenants/alice/chat-link", `{"ttl_s":31536000}`).Code)
+	assert.Equal(t, http.StatusNotFound, post(h, "/tenants/nobody/chat-link", "").Code)
+	assert.Equal(t, http.StatusNotFound, post(h, "/tenants/bob/chat-link", "").Code)
+
+	// A status page secret alone doesn't enable chat links
+	h = api.New(reg, nil, lock.NewMock(), nil, nil, api.Config{StatusPageSecret: secret, StatusPageURL: "https://router.example.com"})
+	assert.Equal(t, http.StatusServiceUnavailable, post(h, "/tenants/alice/chat-link", "").Code)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+)
+
+// registerWebhook points the tenant's bot at the router, generating a
+// secret_token if the tenant has none yet. A new secret is stored only once
+// Telegram has accepted it, so the router never expects a secret Telegram
+// isn't sending.
+func (h *Handler) registerWebhook(ctx context.Context, rec *registry.TenantRecord) error {
+	token, err := h.botToken(ctx, rec)
+	if err != nil {
+		return err
+	}
+	secret := rec.WebhookSecret
+	if secret == "" {
+		secret = telegram.NewWebhookSecret()
+	}
+	if err := h.tg.RegisterWebhook(ctx, token, rec.TenantID, rec.AllowedUpdates, secret); err != nil {
+		return err
+	}
+	if secret == rec.WebhookSecret {
+		return nil
+	}
+	if err := h.storeWebhookSecret(ctx, rec.TenantID, secret); err != nil {
+		return err
+	}
+	rec.WebhookSecret = secret
+	return nil
+}
+
+// storeWebhookSecret records a tenant's secret_token and drops the routers'
+// cached copy
+func (h *Handler) storeWebhookSecret(ctx context.Context, tenantID, secret string) error {
+	if err := h.reg.UpdateWebhookSecret(ctx, tenantID, secret); err != nil {
+		return fmt.Errorf("store webhook secret: %w", err)
+	}
+	if h.rdb != nil {
+		if err := h.rdb.Del(ctx, h.redisKey(contract.WebhookSecretPrefix, tenantID)).Err(); err != nil {
+			slog.Warn("webhook secret: failed to invalidate router cache", "tenant", tenantID, "err", err)
+		}
+	}
+	return nil
+}
+
+// PutWebhookSecret records the secret_token a router registered the
+// tenant's webhook with (internal use by Router).
+func (h *Handler) PutWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	var req struct {
+		WebhookSecret string `json:"webhook_secret"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WebhookSecret == "" {
+		http.Error(w, "webhook_secret required", http.StatusBadRequest)
+		return
+	}
+	if err := h.storeWebhookSecret(r.Context(), tenantID, req.WebhookSecret); err != nil {
+		slog.Error("update webhook_secret failed", "tenant", tenantID, "err", err)
+		http.Error(w, "not found or internal error", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webhookBot loads the tenant and its bot token for the webhook endpoints,
+// writing the error response if it can't
+func (h *Handler) webhookBot(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if h.tg == nil {
+		http.Error(w, "webhooks not managed here (ROUTER_PUBLIC_URL not set)", http.StatusNotImplemented)
+		return "", false
+	}
+	tenantID := chi.URLParam(r, "tenantID")
+	rec, err := h.reg.GetTenant(r.Context(), tenantID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return "", false
+	}
+	if rec == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return "", false
+	}
+	if !rec.HasBot() {
+		http.Error(w, "tenant has no bot", http.StatusNotFound)
+		return "", false
+	}
+	token, err := h.botToken(r.Context(), rec)
+	if err != nil {
+		slog.Error("webhook: bot token lookup failed", "tenant", tenantID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return "", false
+	}
+	return token, true
+}
+
+// writeTelegramError answers a failed Bot API call: Telegram's own
+// complaint for a rejected request, 502 if it couldn't be asked
+func writeTelegramError(w http.ResponseWriter, err error) {
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) {
+		http.Error(w, apiErr.Error(), http.StatusBadGateway)
+		return
+	}
+	http.Error(w, "telegram unreachable", http.StatusBadGateway)
+}
+
+// GetWebhook returns the tenant bot's webhook as Telegram sees it
+// (getWebhookInfo), with the URL it should point at:
+// GET /tenants/{tenantID}/webhook
+func (h *Handler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	token, ok := h.webhookBot(w, r)
+	if !ok {
+		return
+	}
+	info, err := h.tg.GetWebhookInfo(r.Context(), token)
+	if err != nil {
+		slog.Warn("telegram getWebhookInfo failed", "tenant", tenantID, "err", err)
+		writeTelegramError(w, err)
+		return
+	}
+	resp := map[string]any{
+		"tenant_id":            tenantID,
+		"url":                  info.URL,
+		"expected_url":         h.tg.WebhookURL(tenantID),
+		"pending_update_count": info.PendingUpdateCount,
+		"allowed_updates":      info.AllowedUpdates,
+		"max_connections":      info.MaxConnections,
+		"ip_address":           info.IPAddress,
+	}
+	if info.LastErrorDate != 0 {
+		resp["last_error_at"] = time.Unix(info.LastErrorDate, 0).UTC()
+		resp["last_error_message"] = info.LastErrorMessage
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteWebhook stops Telegram delivering the tenant's updates, which wait
+// with Telegram (for up to a day) until a webhook is set again:
+// DELETE /tenants/{tenantID}/webhook. POST /admin/webhook/{tenantID} on the
+// router registers it again.
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantID")
+	token, ok := h.webhookBot(w, r)
+	if !ok {
+		return
+	}
+	if err := h.tg.DeleteWebhook(r.Context(), token); err != nil {
+		slog.Warn("telegram deleteWebhook failed", "tenant", tenantID, "err", err)
+		writeTelegramError(w, err)
+		return
+	}
+	slog.Info("webhook deleted", "tenant", tenantID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWebhook(t *testing.T) {
+	bot := &fakeBotAPI{webhookInfo: `{"ok":true,"result":{"url":"https://old.example.com/tg/alice","pending_update_count":12,"last_error_date":1700000000,"last_error_message":"Wrong response from the webhook: 502 Bad Gateway"}}`}
+	h, _ := newWebhookTestHandler(t, bot, api.Config{})
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok-a").Code)
+
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/webhook", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "https://old.example.com/tg/alice", got["url"])
+	assert.Equal(t, "https://router.example.com/tg/alice", got["expected_url"])
+	assert.Equal(t, float64(12), got["pending_update_count"])
+	assert.Equal(t, "2023-11-14T22:13:20Z", got["last_error_at"])
+	assert.Equal(t, "Wrong response from the webhook: 502 Bad Gateway", got["last_error_message"])
+
+	w = httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/nobody/webhook", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetWebhook_TelegramRejects(t *testing.T) {
+	bot := &fakeBotAPI{}
+	h, _ := newWebhookTestHandler(t, bot, api.Config{})
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok-a").Code)
+	bot.mu.Lock()
+	bot.reject = map[string]string{"tok-a": `{"ok":false,"error_code":401,"description":"Unauthorized"}`}
+	bot.mu.Unlock()
+
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/alice/webhook", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "Unauthorized")
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	bot := &fakeBotAPI{}
+	h, _ := newWebhookTestHandler(t, bot, api.Config{})
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok-a").Code)
+
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tenants/alice/webhook", nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"tok-a"}, bot.deleted)
+}
+
+func TestDeleteWebhook_NoTelegram(t *testing.T) {
+	h, _, _, _ := newTestHandler(t)
+	w := httptest.NewRecorder()
+	h.Router().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tenants/alice/webhook", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
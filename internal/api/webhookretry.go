@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+)
+
+const (
+	// webhookRetryInterval is how often RunWebhookRetries looks for due
+	// registrations
+	webhookRetryInterval = 30 * time.Second
+	// Backoff between attempts doubles from webhookRetryBase up to
+	// webhookRetryMax; after webhookMaxAttempts the webhook is failed
+	webhookRetryBase   = 30 * time.Second
+	webhookRetryMax    = time.Hour
+	webhookMaxAttempts = 10
+)
+
+// errWebhookRateLimited is recorded on registrations queued by the limiter
+var errWebhookRateLimited = errors.New("queued by orchestrator rate limit")
+
+// registerOrQueueWebhook registers the tenant's webhook now if the rate
+// limit allows, and otherwise queues it for RunWebhookRetries. Only failures
+// a retry won't fix, such as a revoked bot token, are returned; the outcome
+// is recorded in rec and the registry either way.
+func (h *Handler) registerOrQueueWebhook(ctx context.Context, rec *registry.TenantRecord) error {
+	if !h.webhookLimiter.Allow() {
+		h.setWebhookState(ctx, rec, registry.WebhookState{
+			Status:  registry.WebhookPending,
+			Error:   errWebhookRateLimited.Error(),
+			RetryAt: time.Now().UTC(),
+		})
+		slog.Info("webhook registration queued", "tenant", rec.TenantID, "reason", "rate limit")
+		return nil
+	}
+	return h.attemptWebhook(ctx, rec, 0)
+}
+
+// attemptWebhook registers the webhook; attempts is the number of earlier
+// failed tries. Retryable failures are queued with backoff, honouring
+// Telegram's retry_after.
+func (h *Handler) attemptWebhook(ctx context.Context, rec *registry.TenantRecord, attempts int) error {
+	err := h.registerWebhook(ctx, rec)
+	if err == nil {
+		h.setWebhookState(ctx, rec, registry.WebhookState{Status: registry.WebhookRegistered})
+		slog.Info("webhook registered", "tenant", rec.TenantID)
+		return nil
+	}
+	attempts++
+	if !telegram.Retryable(err) || attempts >= webhookMaxAttempts {
+		h.setWebhookState(ctx, rec, registry.WebhookState{Status: registry.WebhookFailed, Error: err.Error(), Attempts: attempts})
+		return err
+	}
+	delay := min(webhookRetryBase<<(attempts-1), webhookRetryMax)
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	h.setWebhookState(ctx, rec, registry.WebhookState{
+		Status:   registry.WebhookPending,
+		Error:    err.Error(),
+		Attempts: attempts,
+		RetryAt:  time.Now().UTC().Add(delay),
+	})
+	slog.Warn("webhook registration failed, will retry", "tenant", rec.TenantID, "attempt", attempts, "retry_in", delay, "err", err)
+	return nil
+}
+
+// setWebhookState records st on rec and in the registry. A failed write is
+// only logged: the registration itself has happened (or not) regardless.
+func (h *Handler) setWebhookState(ctx context.Context, rec *registry.TenantRecord, st registry.WebhookState) {
+	rec.WebhookStatus, rec.WebhookError, rec.WebhookAttempts, rec.WebhookRetryAt = st.Status, st.Error, st.Attempts, st.RetryAt
+	if err := h.reg.UpdateWebhookState(ctx, rec.TenantID, st); err != nil {
+		slog.Error("record webhook status failed", "tenant", rec.TenantID, "status", st.Status, "err", err)
+	}
+}
+
+// RunWebhookRetries retries queued webhook registrations until ctx is
+// cancelled, paced by the same rate limit as new ones. Run it on one replica
+// only (the lifecycle leader), so a bot is never registered twice at once
+// with different secrets.
+func (h *Handler) RunWebhookRetries(ctx context.Context) {
+	if h.tg == nil {
+		return
+	}
+	ticker := time.NewTicker(webhookRetryInterval)
+	defer ticker.Stop()
+	for {
+		h.retryWebhooks(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retryWebhooks makes one pass over the tenants whose retry is due
+func (h *Handler) retryWebhooks(ctx context.Context) {
+	tenants, err := h.reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("webhook retry: failed to list tenants", "err", err)
+		return
+	}
+	now := time.Now()
+	for _, rec := range tenants {
+		if rec.WebhookStatus != registry.WebhookPending || rec.WebhookRetryAt.After(now) || !rec.HasBot() ||
+			rec.Status == registry.StatusTerminated {
+			continue
+		}
+		if err := h.webhookLimiter.Wait(ctx); err != nil {
+			return
+		}
+		if err := h.attemptWebhook(ctx, rec, rec.WebhookAttempts); err != nil {
+			slog.Error("webhook registration failed, giving up", "tenant", rec.TenantID, "attempts", rec.WebhookAttempts, "err", err)
+		}
+	}
+}
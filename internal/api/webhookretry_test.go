@@ -0,0 +1,128 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/api"
+	"github.com/shawn/agentic-tenancy/internal/lock"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotAPI answers every call with ok, or with reject's response for bot
+// tokens it lists, and records the bots whose webhook was set or deleted.
+// getWebhookInfo answers with webhookInfo when set.
+type fakeBotAPI struct {
+	mu          sync.Mutex
+	registered  []string // bot tokens
+	deleted     []string // bot tokens
+	reject      map[string]string
+	webhookInfo string
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(r.URL.Path, "/") // "", "bot<token>", method
+	token := strings.TrimPrefix(parts[1], "bot")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if resp, ok := f.reject[token]; ok {
+		w.Write([]byte(resp))
+		return
+	}
+	switch parts[2] {
+	case "setWebhook":
+		f.registered = append(f.registered, token)
+	case "deleteWebhook":
+		f.deleted = append(f.deleted, token)
+	case "getWebhookInfo":
+		if f.webhookInfo != "" {
+			w.Write([]byte(f.webhookInfo))
+			return
+		}
+	}
+	w.Write([]byte(`{"ok":true,"result":{"username":"test_bot"}}`))
+}
+
+func newWebhookTestHandler(t *testing.T, bot *fakeBotAPI, cfg api.Config) (*api.Handler, *registry.MockClient) {
+	t.Helper()
+	srv := httptest.NewServer(bot)
+	t.Cleanup(srv.Close)
+	reg := registry.NewMock()
+	tg := telegram.New("https://router.example.com").WithAPIBase(srv.URL)
+	return api.New(reg, nil, lock.NewMock(), nil, tg, cfg), reg
+}
+
+func createWithBot(t *testing.T, h *api.Handler, tenantID, botToken string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"tenant_id": tenantID, "bot_token": botToken})
+	req := httptest.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateTenant_WebhookRateLimitQueuesAndRetries(t *testing.T) {
+	bot := &fakeBotAPI{}
+	h, reg := newWebhookTestHandler(t, bot, api.Config{WebhookRate: 1, WebhookBurst: 1})
+	ctx := context.Background()
+
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok-a").Code)
+	rec := createWithBot(t, h, "bob", "tok-b")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created registry.TenantRecord
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, registry.WebhookPending, created.WebhookStatus)
+
+	alice, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, registry.WebhookRegistered, alice.WebhookStatus)
+	bob, _ := reg.GetTenant(ctx, "bob")
+	assert.Equal(t, registry.WebhookPending, bob.WebhookStatus)
+
+	retryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go h.RunWebhookRetries(retryCtx)
+	assert.Eventually(t, func() bool {
+		bob, _ := reg.GetTenant(ctx, "bob")
+		return bob.WebhookStatus == registry.WebhookRegistered && bob.WebhookSecret != ""
+	}, 5*time.Second, 50*time.Millisecond)
+	bot.mu.Lock()
+	assert.Equal(t, []string{"tok-a", "tok-b"}, bot.registered)
+	bot.mu.Unlock()
+}
+
+func TestCreateTenant_WebhookFloodLimitRetriesLater(t *testing.T) {
+	bot := &fakeBotAPI{reject: map[string]string{
+		"tok": `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 120","parameters":{"retry_after":120}}`,
+	}}
+	h, reg := newWebhookTestHandler(t, bot, api.Config{})
+
+	require.Equal(t, http.StatusCreated, createWithBot(t, h, "alice", "tok").Code)
+	alice, _ := reg.GetTenant(context.Background(), "alice")
+	assert.Equal(t, registry.WebhookPending, alice.WebhookStatus)
+	assert.Equal(t, 1, alice.WebhookAttempts)
+	assert.WithinDuration(t, time.Now().Add(120*time.Second), alice.WebhookRetryAt, 5*time.Second, "Telegram's retry_after wins over the backoff")
+}
+
+func TestCreateTenant_WebhookRejectedRollsBack(t *testing.T) {
+	bot := &fakeBotAPI{reject: map[string]string{
+		"bad": `{"ok":false,"error_code":401,"description":"Unauthorized"}`,
+	}}
+	h, reg := newWebhookTestHandler(t, bot, api.Config{})
+
+	assert.Equal(t, http.StatusBadGateway, createWithBot(t, h, "alice", "bad").Code)
+	alice, err := reg.GetTenant(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Nil(t, alice, "the half-created tenant is removed")
+	sagas, _ := reg.ListSagas(context.Background())
+	assert.Empty(t, sagas)
+}
@@ -0,0 +1,178 @@
+// Package apikey resolves the API keys callers present to the orchestrator.
+// Keys come from static configuration (API_KEYS) and/or a DynamoDB table
+// that stores only their SHA-256 hashes, so keys can be issued and revoked
+// without a redeploy.
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Store resolves an API key to the name it was issued under. ok is false
+// for unknown or revoked keys; err is set if the store couldn't be asked.
+type Store interface {
+	Lookup(ctx context.Context, key string) (name string, ok bool, err error)
+}
+
+// Hash is how keys are stored in the api_keys table
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Static is a fixed set of keys, as parsed by ParseStatic
+type Static []staticKey
+
+type staticKey struct {
+	name string
+	key  string
+}
+
+// ParseStatic parses "name=key,name=key" (e.g. "router=abc,ops=def"). A
+// bare key is named after its position ("key-1", ...).
+func ParseStatic(s string) (Static, error) {
+	var out Static
+	for i, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(part, "=")
+		if !ok {
+			name, key = fmt.Sprintf("key-%d", i+1), part
+		}
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if name == "" || key == "" {
+			return nil, fmt.Errorf("invalid API key %q: want name=key", part)
+		}
+		out = append(out, staticKey{name: name, key: key})
+	}
+	return out, nil
+}
+
+// Lookup compares key against every static key in constant time
+func (s Static) Lookup(_ context.Context, key string) (string, bool, error) {
+	for _, k := range s {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k.key)) == 1 {
+			return k.name, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Record is an item in the api_keys table
+type Record struct {
+	KeyHash   string    `dynamodbav:"key_hash"`
+	Name      string    `dynamodbav:"name"`
+	Disabled  bool      `dynamodbav:"disabled,omitempty"`
+	CreatedAt time.Time `dynamodbav:"created_at"`
+}
+
+// Dynamo looks keys up in a DynamoDB table keyed by key_hash
+type Dynamo struct {
+	db        *dynamodb.Client
+	tableName string
+}
+
+func NewDynamo(db *dynamodb.Client, tableName string) *Dynamo {
+	return &Dynamo{db: db, tableName: tableName}
+}
+
+// Lookup fetches the key's record; disabled keys are not ok
+func (d *Dynamo) Lookup(ctx context.Context, key string) (string, bool, error) {
+	out, err := d.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"key_hash": &types.AttributeValueMemberS{Value: Hash(key)},
+		},
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	if out.Item == nil {
+		return "", false, nil
+	}
+	var rec Record
+	if err := attributevalue.UnmarshalMap(out.Item, &rec); err != nil {
+		return "", false, fmt.Errorf("unmarshal api key: %w", err)
+	}
+	return rec.Name, !rec.Disabled, nil
+}
+
+// Chain asks each store in turn and accepts the first that knows the key
+type Chain []Store
+
+func (c Chain) Lookup(ctx context.Context, key string) (string, bool, error) {
+	var firstErr error
+	for _, s := range c {
+		name, ok, err := s.Lookup(ctx, key)
+		if ok {
+			return name, true, nil
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", false, firstErr
+}
+
+// Cached remembers lookups for a while, so a busy caller (the router calls
+// the orchestrator on every message) doesn't cost a table read per request.
+// Revoking a key takes effect within the TTL.
+type Cached struct {
+	store Store
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedLookup
+}
+
+type cachedLookup struct {
+	name    string
+	ok      bool
+	expires time.Time
+}
+
+// maxCached bounds the cache against callers presenting random keys
+const maxCached = 1024
+
+func NewCached(store Store, ttl time.Duration) *Cached {
+	return &Cached{store: store, ttl: ttl, entries: make(map[string]cachedLookup)}
+}
+
+// Lookup serves from the cache, asking the store on a miss. Failed lookups
+// are not cached.
+func (c *Cached) Lookup(ctx context.Context, key string) (string, bool, error) {
+	h := Hash(key)
+	now := time.Now()
+	c.mu.Lock()
+	e, hit := c.entries[h]
+	c.mu.Unlock()
+	if hit && now.Before(e.expires) {
+		return e.name, e.ok, nil
+	}
+
+	name, ok, err := c.store.Lookup(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+	c.mu.Lock()
+	if len(c.entries) >= maxCached {
+		clear(c.entries)
+	}
+	c.entries[h] = cachedLookup{name: name, ok: ok, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return name, ok, nil
+}
@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatic(t *testing.T) {
+	s, err := ParseStatic("router=abc, def,")
+	require.NoError(t, err)
+
+	name, ok, err := s.Lookup(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "router", name)
+
+	name, ok, _ = s.Lookup(context.Background(), "def")
+	assert.True(t, ok)
+	assert.Equal(t, "key-2", name)
+
+	_, ok, _ = s.Lookup(context.Background(), "nope")
+	assert.False(t, ok)
+
+	_, err = ParseStatic("ops=")
+	assert.Error(t, err)
+}
+
+// countingStore counts lookups and fails while err is set
+type countingStore struct {
+	calls int
+	err   error
+}
+
+func (c *countingStore) Lookup(_ context.Context, key string) (string, bool, error) {
+	c.calls++
+	return "dyn", key == "good", c.err
+}
+
+func TestChainAndCached(t *testing.T) {
+	static, _ := ParseStatic("ops=s3cret")
+	dyn := &countingStore{}
+	store := NewCached(Chain{static, dyn}, time.Minute)
+	ctx := context.Background()
+
+	name, ok, err := store.Lookup(ctx, "good")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "dyn", name)
+	store.Lookup(ctx, "good")
+	assert.Equal(t, 1, dyn.calls, "second lookup is served from the cache")
+
+	name, ok, _ = store.Lookup(ctx, "s3cret")
+	assert.True(t, ok)
+	assert.Equal(t, "ops", name)
+
+	// Errors surface and aren't cached
+	dyn.err = errors.New("throttled")
+	_, ok, err = store.Lookup(ctx, "other")
+	assert.False(t, ok)
+	assert.Error(t, err)
+	dyn.err = nil
+	_, ok, err = store.Lookup(ctx, "other")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
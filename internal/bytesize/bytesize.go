@@ -0,0 +1,19 @@
+// Package bytesize renders byte counts for people. It has no dependencies,
+// so both the orchestrator and ztm use it.
+package bytesize
+
+import "fmt"
+
+// Format renders a size in binary units, e.g. "1.5 GiB"
+func Format(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,13 @@
+package bytesize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "512 B", Format(512))
+	assert.Equal(t, "1.5 KiB", Format(1536))
+	assert.Equal(t, "5.0 GiB", Format(5<<30))
+}
@@ -2,16 +2,59 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 )
 
 // Client is the interface for interacting with Orchestrator and Router APIs
 type Client interface {
 	// Orchestrator APIs
 	CreateTenant(ctx context.Context, req *CreateTenantRequest) (*Tenant, error)
+	CreateTenants(ctx context.Context, reqs []CreateTenantRequest) (*BulkCreateResponse, error)
 	DeleteTenant(ctx context.Context, id string) error
-	ListTenants(ctx context.Context) ([]Tenant, error)
+	PlanDeleteTenant(ctx context.Context, id string) (*DeletePlan, error)
+	PurgeTenant(ctx context.Context, id string) error
+	PlanPurgeTenant(ctx context.Context, id string) (*DeletePlan, error)
+	RestoreTenant(ctx context.Context, id string) (*Tenant, error)
+	ListTenants(ctx context.Context, opts ListOptions) ([]Tenant, error)
+	WatchTenants(ctx context.Context, tenantID string, fn func(TenantChange) error) error
 	GetTenant(ctx context.Context, id string) (*Tenant, error)
 	UpdateTenant(ctx context.Context, id string, req *UpdateTenantRequest) (*Tenant, error)
+	WakeTenant(ctx context.Context, id string) (*WakeResponse, error)
+	RestartTenant(ctx context.Context, id string) (*RestartResult, error)
+	SleepTenant(ctx context.Context, id string) (*SleepResult, error)
+	SuspendTenant(ctx context.Context, id string) (*SuspendResult, error)
+	ResumeTenant(ctx context.Context, id string) (*SuspendResult, error)
+	ResetTenant(ctx context.Context, id string) (*SuspendResult, error)
+	SendTestMessage(ctx context.Context, id, text string) (*TestMessageResult, error)
+	ListWakes(ctx context.Context, id string) ([]WakeAttempt, error)
+	ListEvents(ctx context.Context, id string) ([]TenantEvent, error)
+	GetUsage(ctx context.Context, id, from, to string) (*UsageReport, error)
+	GetState(ctx context.Context, id string) (*TenantState, error)
+	CreateSnapshot(ctx context.Context, id string, quiesce bool) (*SnapshotResult, error)
+	ListSnapshots(ctx context.Context, id string) ([]Snapshot, error)
+	RestoreSnapshot(ctx context.Context, id, snapshotID string) (*SnapshotRestoreResult, error)
+	DeleteSnapshot(ctx context.Context, id, snapshotID string) error
+	ExportTenant(ctx context.Context, id string) (*TenantBundle, error)
+	ImportTenant(ctx context.Context, req ImportTenantRequest) (*ImportResult, error)
+	StreamLogs(ctx context.Context, id string, tail int, follow bool) (io.ReadCloser, error)
+	ExecTenant(ctx context.Context, id string, req ExecRequest) error
+	LookupChat(ctx context.Context, chatID string) ([]ChatTenant, error)
+	GetLink(ctx context.Context, id, start string) (*DeepLink, error)
+	GetLinkQR(ctx context.Context, id, start string) ([]byte, error)
+	CreateStatusLink(ctx context.Context, id string, ttl time.Duration) (*StatusLink, error)
+	CreateChatLink(ctx context.Context, id string, ttl time.Duration) (*ChatLink, error)
+	ListImages(ctx context.Context) ([]ImageAlias, error)
+	SetImage(ctx context.Context, alias, image string) (*ImageAlias, error)
+	DeleteImage(ctx context.Context, alias string) error
+	StartRollout(ctx context.Context, maxUnavailable int) (*Rollout, error)
+	GetRollout(ctx context.Context) (*Rollout, error)
+	SetKillSwitch(ctx context.Context, req *SetKillSwitchRequest) (*KillSwitch, error)
+	GetKillSwitch(ctx context.Context) (*KillSwitch, error)
+	StartBroadcast(ctx context.Context, text string) (*Broadcast, error)
+	GetBroadcast(ctx context.Context) (*Broadcast, error)
+	GetWebhookInfo(ctx context.Context, tenantID string) (*WebhookInfo, error)
+	DeleteWebhook(ctx context.Context, tenantID string) error
 
 	// Router APIs
 	RegisterWebhook(ctx context.Context, tenantID string) (*WebhookResponse, error)
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/pkg/client"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// HTTPClient calls the orchestrator and router over HTTP(S) directly, for
+// port-forwards, ingresses and CI, where kubectl exec isn't available.
+// Orchestrator calls go through pkg/client.
+type HTTPClient struct {
+	*client.Client
+	orchestratorURL string
+	apiKey          string
+	routerURL       string
+	http            *http.Client
+}
+
+// NewHTTPClient returns a client for the orchestrator at orchestratorURL and
+// the router at routerURL, which only webhook registration needs. An empty
+// env addresses the default environment. apiKey is sent to the orchestrator
+// as a bearer token; empty sends none.
+func NewHTTPClient(orchestratorURL, routerURL, env, apiKey string) *HTTPClient {
+	prefix := ""
+	if env != "" {
+		prefix = "/env/" + env
+	}
+	hc := &http.Client{}
+	if apiKey != "" {
+		hc.Transport = &bearerAuth{base: http.DefaultTransport, key: apiKey}
+	}
+	if routerURL != "" {
+		routerURL = strings.TrimSuffix(routerURL, "/") + prefix
+	}
+	orchestratorURL = strings.TrimSuffix(orchestratorURL, "/") + prefix
+	return &HTTPClient{
+		Client:          client.New(orchestratorURL, hc),
+		orchestratorURL: orchestratorURL,
+		apiKey:          apiKey,
+		routerURL:       routerURL,
+		http:            &http.Client{},
+	}
+}
+
+// bearerAuth adds an API key to every request
+type bearerAuth struct {
+	base http.RoundTripper
+	key  string
+}
+
+func (t *bearerAuth) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.key)
+	return t.base.RoundTrip(req)
+}
+
+// wakePollInterval is how often WakeTenant polls an async wake
+var wakePollInterval = 2 * time.Second
+
+// WakeTenant starts an async wake and polls it until the pod is ready, so a
+// cold start doesn't hold a connection open through an ingress or load
+// balancer for minutes. Orchestrators without async wakes answer at once,
+// and a job the polled replica doesn't know (no Redis) is waited out with a
+// synchronous wake.
+func (c *HTTPClient) WakeTenant(ctx context.Context, id string) (*WakeResponse, error) {
+	job, err := c.WakeAsync(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	running := job.ID != "" && job.Status == "ready"
+	for job.ID != "" && job.Status != "ready" {
+		if job.Status == "failed" {
+			return nil, fmt.Errorf("wake failed: %s", job.Error)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wake job %s: %w", job.ID, ctx.Err())
+		case <-time.After(wakePollInterval):
+		}
+		polled, err := c.GetWakeJob(ctx, job.ID)
+		if client.StatusCode(err) == http.StatusNotFound {
+			polled, err = c.Wake(ctx, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		job = polled
+	}
+	return &WakeResponse{PodIP: job.PodIP, IdleTimeoutS: int(job.IdleTimeoutS), AlreadyRunning: running}, nil
+}
+
+// ExecTenant runs a command in the tenant's agent container through the
+// orchestrator's exec relay, copying the streams until it exits. A non-zero
+// exit is returned as an *ExitError.
+func (c *HTTPClient) ExecTenant(ctx context.Context, id string, req ExecRequest) error {
+	u, err := url.Parse(c.orchestratorURL + "/tenants/" + url.PathEscape(id) + "/exec")
+	if err != nil {
+		return err
+	}
+	query := url.Values{"command": req.Command}
+	query.Set("stdin", strconv.FormatBool(req.Stdin != nil))
+	query.Set("tty", strconv.FormatBool(req.TTY))
+	u.RawQuery = query.Encode()
+
+	executor, err := remotecommand.NewSPDYExecutor(&rest.Config{Host: c.orchestratorURL, BearerToken: c.apiKey}, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+	opts := remotecommand.StreamOptions{Stdin: req.Stdin, Stdout: req.Stdout, Tty: req.TTY}
+	if !req.TTY {
+		opts.Stderr = req.Stderr
+	}
+	if req.TTY && req.Resize != nil {
+		opts.TerminalSizeQueue = sizeQueue(req.Resize)
+	}
+	err = executor.StreamWithContext(ctx, opts)
+	var exit exec.ExitError
+	if errors.As(err, &exit) && exit.Exited() {
+		return &ExitError{Code: exit.ExitStatus()}
+	}
+	return err
+}
+
+// sizeQueue hands ExecRequest.Resize to remotecommand
+type sizeQueue <-chan TerminalSize
+
+func (q sizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+}
+
+func (c *HTTPClient) RegisterWebhook(ctx context.Context, tenantID string) (*WebhookResponse, error) {
+	if c.routerURL == "" {
+		return nil, fmt.Errorf("--router-url is required to register webhooks with --orchestrator-url")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.routerURL+"/admin/webhook/"+url.PathEscape(tenantID), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("router returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var webhook WebhookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &webhook, nil
+}
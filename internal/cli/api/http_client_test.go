@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_Orchestrator(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization")+" "+r.Header.Get("X-Confirm"))
+		switch r.URL.Path {
+		case "/env/staging/tenants/alice":
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Write([]byte(`{"TenantID":"alice","Status":"running","PodIP":"10.0.0.1"}`))
+		case "/env/staging/wake/alice":
+			w.Write([]byte(`{"pod_ip":"10.0.0.1","idle_timeout_s":600}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var c Client = NewHTTPClient(srv.URL+"/", "", "staging", "s3cret")
+	ctx := context.Background()
+
+	tenant, err := c.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", tenant.PodIP)
+
+	wake, err := c.WakeTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 600, wake.IdleTimeoutS)
+
+	require.NoError(t, c.DeleteTenant(ctx, "alice"))
+
+	_, err = c.GetState(ctx, "alice")
+	assert.EqualError(t, err, "orchestrator returned 404: not found")
+
+	assert.Equal(t, []string{
+		"GET /env/staging/tenants/alice Bearer s3cret ",
+		"POST /env/staging/wake/alice?async=true Bearer s3cret ",
+		"DELETE /env/staging/tenants/alice Bearer s3cret alice",
+		"GET /env/staging/tenants/alice/state Bearer s3cret ",
+	}, got)
+}
+
+func TestHTTPClient_RegisterWebhook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/webhook/alice", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"), "the API key is the orchestrator's")
+		w.Write([]byte(`{"success":true,"url":"https://bots.example.com/tg/alice"}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := NewHTTPClient("http://orchestrator:8080", "", "", "s3cret").RegisterWebhook(ctx, "alice")
+	assert.ErrorContains(t, err, "--router-url is required")
+
+	resp, err := NewHTTPClient("http://orchestrator:8080", srv.URL, "", "s3cret").RegisterWebhook(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "https://bots.example.com/tg/alice", resp.URL)
+}
+
+func TestHTTPClient_WakeTenantPollsAsyncWake(t *testing.T) {
+	wakePollInterval = time.Millisecond
+	defer func() { wakePollInterval = 2 * time.Second }()
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/wake/alice?async=true":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"job_id":"j1","status":"pending"}`))
+		case "/wake/bob?async=true":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"job_id":"j2","status":"ready","pod_ip":"10.0.0.2","idle_timeout_s":300}`))
+		case "/wake/carol?async=true":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"job_id":"j3","status":"pending"}`))
+		case "/wake/carol":
+			w.Write([]byte(`{"pod_ip":"10.0.0.3","idle_timeout_s":300}`))
+		case "/wake-jobs/j1":
+			polls++
+			if polls < 3 {
+				w.Write([]byte(`{"job_id":"j1","status":"provisioning"}`))
+				return
+			}
+			w.Write([]byte(`{"job_id":"j1","status":"ready","pod_ip":"10.0.0.1","idle_timeout_s":600}`))
+		default:
+			// j3 was created on a replica this one can't see
+			http.Error(w, "wake job not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := NewHTTPClient(srv.URL, "", "", "")
+	ctx := context.Background()
+
+	wake, err := c.WakeTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", wake.PodIP)
+	assert.Equal(t, 600, wake.IdleTimeoutS)
+	assert.False(t, wake.AlreadyRunning)
+	assert.Equal(t, 3, polls)
+
+	wake, err = c.WakeTenant(ctx, "bob")
+	require.NoError(t, err)
+	assert.True(t, wake.AlreadyRunning)
+
+	wake, err = c.WakeTenant(ctx, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.3", wake.PodIP)
+}
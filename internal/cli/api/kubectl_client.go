@@ -1,9 +1,13 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"time"
 
 	"github.com/shawn/agentic-tenancy/internal/cli/k8s"
 )
@@ -20,6 +24,25 @@ func NewKubectlClient(namespace, context string) *KubectlClient {
 	}
 }
 
+// Configure points the client at a cluster and control-plane environment.
+// An empty env addresses the default environment. apiKey authenticates
+// orchestrator calls; empty sends none. orchestratorPort is the port called
+// inside the orchestrator pod: its internal listener's, if it has one, since
+// wakes aren't served on the public one.
+func (c *KubectlClient) Configure(namespace, context, env, apiKey string, orchestratorPort int) {
+	prefix := ""
+	if env != "" {
+		prefix = "/env/" + env
+	}
+	for _, cfg := range []*k8s.Config{c.orchestratorCfg, c.routerCfg} {
+		cfg.Namespace = namespace
+		cfg.Context = context
+		cfg.PathPrefix = prefix
+	}
+	c.orchestratorCfg.APIKey = apiKey
+	c.orchestratorCfg.Port = orchestratorPort
+}
+
 func (c *KubectlClient) CreateTenant(ctx context.Context, req *CreateTenantRequest) (*Tenant, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -39,17 +62,99 @@ func (c *KubectlClient) CreateTenant(ctx context.Context, req *CreateTenantReque
 	return &tenant, nil
 }
 
+// CreateTenants creates a batch of tenants with one call; the response
+// reports each tenant's outcome
+func (c *KubectlClient) CreateTenants(ctx context.Context, reqs []CreateTenantRequest) (*BulkCreateResponse, error) {
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/tenants/bulk", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result BulkCreateResponse
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// DeleteTenant confirms the deletion with X-Confirm, as the orchestrator may
+// require (REQUIRE_CONFIRM); callers are expected to have confirmed it.
 func (c *KubectlClient) DeleteTenant(ctx context.Context, id string) error {
 	path := fmt.Sprintf("/tenants/%s", id)
-	_, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "DELETE", path, nil)
+	_, err := k8s.ExecAPICallWithHeaders(ctx, c.orchestratorCfg, "DELETE", path, map[string]string{"X-Confirm": id}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete tenant: %w", err)
 	}
 	return nil
 }
 
-func (c *KubectlClient) ListTenants(ctx context.Context) ([]Tenant, error) {
-	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", "/tenants", nil)
+func (c *KubectlClient) PlanDeleteTenant(ctx context.Context, id string) (*DeletePlan, error) {
+	return c.planDelete(ctx, fmt.Sprintf("/tenants/%s?dry_run=true", id))
+}
+
+// PurgeTenant confirms the purge with X-Confirm, like DeleteTenant
+func (c *KubectlClient) PurgeTenant(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/tenants/%s?purge=true", id)
+	_, err := k8s.ExecAPICallWithHeaders(ctx, c.orchestratorCfg, "DELETE", path, map[string]string{"X-Confirm": id}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to purge tenant: %w", err)
+	}
+	return nil
+}
+
+func (c *KubectlClient) PlanPurgeTenant(ctx context.Context, id string) (*DeletePlan, error) {
+	return c.planDelete(ctx, fmt.Sprintf("/tenants/%s?purge=true&dry_run=true", id))
+}
+
+func (c *KubectlClient) planDelete(ctx context.Context, path string) (*DeletePlan, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "DELETE", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var plan DeletePlan
+	if err := json.Unmarshal(resp, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &plan, nil
+}
+
+func (c *KubectlClient) RestoreTenant(ctx context.Context, id string) (*Tenant, error) {
+	path := fmt.Sprintf("/tenants/%s/restore", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var tenant Tenant
+	if err := json.Unmarshal(resp, &tenant); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &tenant, nil
+}
+
+func (c *KubectlClient) ListTenants(ctx context.Context, opts ListOptions) ([]Tenant, error) {
+	query := url.Values{}
+	if opts.Sort != "" {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Order != "" {
+		query.Set("order", opts.Order)
+	}
+	path := "/tenants"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("API call failed: %w", err)
 	}
@@ -62,6 +167,12 @@ func (c *KubectlClient) ListTenants(ctx context.Context) ([]Tenant, error) {
 	return tenants, nil
 }
 
+// WatchTenants needs a stream kubectl exec can't relay: it returns the
+// response only when it ends
+func (c *KubectlClient) WatchTenants(ctx context.Context, tenantID string, fn func(TenantChange) error) error {
+	return fmt.Errorf("watching tenants needs --orchestrator-url")
+}
+
 func (c *KubectlClient) GetTenant(ctx context.Context, id string) (*Tenant, error) {
 	path := fmt.Sprintf("/tenants/%s", id)
 	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
@@ -97,6 +208,557 @@ func (c *KubectlClient) UpdateTenant(ctx context.Context, id string, req *Update
 	return &tenant, nil
 }
 
+func (c *KubectlClient) WakeTenant(ctx context.Context, id string) (*WakeResponse, error) {
+	path := fmt.Sprintf("/wake/%s", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var wake WakeResponse
+	if err := json.Unmarshal(resp, &wake); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &wake, nil
+}
+
+func (c *KubectlClient) RestartTenant(ctx context.Context, id string) (*RestartResult, error) {
+	path := fmt.Sprintf("/tenants/%s/restart", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result RestartResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) SleepTenant(ctx context.Context, id string) (*SleepResult, error) {
+	path := fmt.Sprintf("/tenants/%s/sleep", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SleepResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) SuspendTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	path := fmt.Sprintf("/tenants/%s/suspend", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SuspendResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) ResumeTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	path := fmt.Sprintf("/tenants/%s/resume", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SuspendResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) ResetTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	path := fmt.Sprintf("/tenants/%s/reset", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SuspendResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) SendTestMessage(ctx context.Context, id, text string) (*TestMessageResult, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/tenants/%s/test-message", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result TestMessageResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) ListWakes(ctx context.Context, id string) ([]WakeAttempt, error) {
+	path := fmt.Sprintf("/tenants/%s/wakes", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var wakes []WakeAttempt
+	if err := json.Unmarshal(resp, &wakes); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return wakes, nil
+}
+
+func (c *KubectlClient) GetUsage(ctx context.Context, id, from, to string) (*UsageReport, error) {
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	path := fmt.Sprintf("/tenants/%s/usage", id)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var report UsageReport
+	if err := json.Unmarshal(resp, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (c *KubectlClient) ListEvents(ctx context.Context, id string) ([]TenantEvent, error) {
+	path := fmt.Sprintf("/tenants/%s/events", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var events []TenantEvent
+	if err := json.Unmarshal(resp, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return events, nil
+}
+
+func (c *KubectlClient) GetState(ctx context.Context, id string) (*TenantState, error) {
+	path := fmt.Sprintf("/tenants/%s/state", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var state TenantState
+	if err := json.Unmarshal(resp, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &state, nil
+}
+
+func (c *KubectlClient) CreateSnapshot(ctx context.Context, id string, quiesce bool) (*SnapshotResult, error) {
+	body, err := json.Marshal(SnapshotRequest{Quiesce: quiesce})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/tenants/%s/snapshots", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", path, body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SnapshotResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) ListSnapshots(ctx context.Context, id string) ([]Snapshot, error) {
+	path := fmt.Sprintf("/tenants/%s/snapshots", id)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var snaps []Snapshot
+	if err := json.Unmarshal(resp, &snaps); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return snaps, nil
+}
+
+// RestoreSnapshot confirms the restore with X-Confirm, like DeleteTenant
+func (c *KubectlClient) RestoreSnapshot(ctx context.Context, id, snapshotID string) (*SnapshotRestoreResult, error) {
+	path := fmt.Sprintf("/tenants/%s/snapshots/%s/restore", id, snapshotID)
+	resp, err := k8s.ExecAPICallWithHeaders(ctx, c.orchestratorCfg, "POST", path, map[string]string{"X-Confirm": id}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result SnapshotRestoreResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *KubectlClient) DeleteSnapshot(ctx context.Context, id, snapshotID string) error {
+	path := fmt.Sprintf("/tenants/%s/snapshots/%s", id, snapshotID)
+	if _, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
+
+func (c *KubectlClient) ExportTenant(ctx context.Context, id string) (*TenantBundle, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", fmt.Sprintf("/tenants/%s/export", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var bundle TenantBundle
+	if err := json.Unmarshal(resp, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &bundle, nil
+}
+
+func (c *KubectlClient) ImportTenant(ctx context.Context, req ImportTenantRequest) (*ImportResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/tenants/import", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var result ImportResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// StreamLogs reads the tenant's recent logs in one call. kubectl exec
+// returns the response only when it ends, so following needs
+// --orchestrator-url.
+func (c *KubectlClient) StreamLogs(ctx context.Context, id string, tail int, follow bool) (io.ReadCloser, error) {
+	if follow {
+		return nil, fmt.Errorf("following logs needs --orchestrator-url")
+	}
+	path := fmt.Sprintf("/tenants/%s/logs", id)
+	if tail > 0 {
+		path += fmt.Sprintf("?tail=%d", tail)
+	}
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	return io.NopCloser(bytes.NewReader(resp)), nil
+}
+
+// ExecTenant can't relay a session: kubectl exec into the orchestrator
+// returns its response only when it ends. Use --orchestrator-url, or
+// kubectl exec into the tenant pod directly.
+func (c *KubectlClient) ExecTenant(ctx context.Context, id string, req ExecRequest) error {
+	return fmt.Errorf("exec sessions need --orchestrator-url")
+}
+
+func (c *KubectlClient) LookupChat(ctx context.Context, chatID string) ([]ChatTenant, error) {
+	path := fmt.Sprintf("/lookup/chat/%s", chatID)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var entries []ChatTenant
+	if err := json.Unmarshal(resp, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (c *KubectlClient) GetLink(ctx context.Context, id, start string) (*DeepLink, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", linkPath(id, "/link", start), nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var link DeepLink
+	if err := json.Unmarshal(resp, &link); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &link, nil
+}
+
+func (c *KubectlClient) GetLinkQR(ctx context.Context, id, start string) ([]byte, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", linkPath(id, "/link/qr", start), nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *KubectlClient) CreateStatusLink(ctx context.Context, id string, ttl time.Duration) (*StatusLink, error) {
+	var body []byte
+	if ttl > 0 {
+		var err error
+		if body, err = json.Marshal(map[string]int64{"ttl_s": int64(ttl.Seconds())}); err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", fmt.Sprintf("/tenants/%s/status-link", id), body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var link StatusLink
+	if err := json.Unmarshal(resp, &link); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &link, nil
+}
+
+func (c *KubectlClient) CreateChatLink(ctx context.Context, id string, ttl time.Duration) (*ChatLink, error) {
+	var body []byte
+	if ttl > 0 {
+		var err error
+		if body, err = json.Marshal(map[string]int64{"ttl_s": int64(ttl.Seconds())}); err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", fmt.Sprintf("/tenants/%s/chat-link", id), body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var link ChatLink
+	if err := json.Unmarshal(resp, &link); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &link, nil
+}
+
+func linkPath(id, suffix, start string) string {
+	path := fmt.Sprintf("/tenants/%s%s", id, suffix)
+	if start != "" {
+		path += "?start=" + url.QueryEscape(start)
+	}
+	return path
+}
+
+func (c *KubectlClient) ListImages(ctx context.Context) ([]ImageAlias, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", "/images", nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var aliases []ImageAlias
+	if err := json.Unmarshal(resp, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return aliases, nil
+}
+
+func (c *KubectlClient) SetImage(ctx context.Context, alias, image string) (*ImageAlias, error) {
+	body, err := json.Marshal(ImageAlias{Alias: alias, Image: image})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/images", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var out ImageAlias
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &out, nil
+}
+
+func (c *KubectlClient) DeleteImage(ctx context.Context, alias string) error {
+	path := fmt.Sprintf("/images/%s", alias)
+	_, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "DELETE", path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete image alias: %w", err)
+	}
+	return nil
+}
+
+func (c *KubectlClient) StartRollout(ctx context.Context, maxUnavailable int) (*Rollout, error) {
+	body, err := json.Marshal(map[string]int{"max_unavailable": maxUnavailable})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/admin/rollout", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var rollout Rollout
+	if err := json.Unmarshal(resp, &rollout); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &rollout, nil
+}
+
+func (c *KubectlClient) GetRollout(ctx context.Context) (*Rollout, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", "/admin/rollout", nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var rollout Rollout
+	if err := json.Unmarshal(resp, &rollout); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &rollout, nil
+}
+
+func (c *KubectlClient) StartBroadcast(ctx context.Context, text string) (*Broadcast, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/admin/broadcast", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var broadcast Broadcast
+	if err := json.Unmarshal(resp, &broadcast); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &broadcast, nil
+}
+
+func (c *KubectlClient) GetBroadcast(ctx context.Context) (*Broadcast, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", "/admin/broadcast", nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var broadcast Broadcast
+	if err := json.Unmarshal(resp, &broadcast); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &broadcast, nil
+}
+
+func (c *KubectlClient) SetKillSwitch(ctx context.Context, req *SetKillSwitchRequest) (*KillSwitch, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "POST", "/admin/killswitch", body)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var ks KillSwitch
+	if err := json.Unmarshal(resp, &ks); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &ks, nil
+}
+
+func (c *KubectlClient) GetKillSwitch(ctx context.Context) (*KillSwitch, error) {
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", "/admin/killswitch", nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var ks KillSwitch
+	if err := json.Unmarshal(resp, &ks); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &ks, nil
+}
+
+func (c *KubectlClient) GetWebhookInfo(ctx context.Context, tenantID string) (*WebhookInfo, error) {
+	path := fmt.Sprintf("/tenants/%s/webhook", tenantID)
+	resp, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("API call failed: %w", err)
+	}
+
+	var info WebhookInfo
+	if err := json.Unmarshal(resp, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &info, nil
+}
+
+func (c *KubectlClient) DeleteWebhook(ctx context.Context, tenantID string) error {
+	path := fmt.Sprintf("/tenants/%s/webhook", tenantID)
+	if _, err := k8s.ExecAPICall(ctx, c.orchestratorCfg, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
 func (c *KubectlClient) RegisterWebhook(ctx context.Context, tenantID string) (*WebhookResponse, error) {
 	path := fmt.Sprintf("/admin/webhook/%s", tenantID)
 	resp, err := k8s.ExecAPICall(ctx, c.routerCfg, "POST", path, nil)
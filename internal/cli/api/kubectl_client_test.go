@@ -47,12 +47,13 @@ func TestKubectlClient_BuildUpdateTenantBody(t *testing.T) {
 }
 
 func TestKubectlClient_ParseTenant(t *testing.T) {
+	// The orchestrator encodes registry records with their Go field names
 	responseJSON := `{
-		"tenant_id": "alice",
-		"status": "running",
-		"idle_timeout_s": 3600,
-		"pod_name": "zeroclaw-alice",
-		"pod_ip": "10.0.1.5"
+		"TenantID": "alice",
+		"Status": "running",
+		"IdleTimeoutS": 3600,
+		"PodName": "zeroclaw-alice",
+		"PodIP": "10.0.1.5"
 	}`
 
 	var tenant Tenant
@@ -61,4 +62,5 @@ func TestKubectlClient_ParseTenant(t *testing.T) {
 	assert.Equal(t, "alice", tenant.TenantID)
 	assert.Equal(t, "running", tenant.Status)
 	assert.Equal(t, 3600, tenant.IdleTimeoutS)
+	assert.Equal(t, "10.0.1.5", tenant.PodIP)
 }
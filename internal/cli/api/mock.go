@@ -2,15 +2,59 @@ package api
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
 )
 
 // MockClient for testing
 type MockClient struct {
 	CreateTenantFunc    func(ctx context.Context, req *CreateTenantRequest) (*Tenant, error)
+	CreateTenantsFunc   func(ctx context.Context, reqs []CreateTenantRequest) (*BulkCreateResponse, error)
 	DeleteTenantFunc    func(ctx context.Context, id string) error
-	ListTenantsFunc     func(ctx context.Context) ([]Tenant, error)
+	PlanDeleteFunc      func(ctx context.Context, id string) (*DeletePlan, error)
+	PurgeTenantFunc     func(ctx context.Context, id string) error
+	PlanPurgeFunc       func(ctx context.Context, id string) (*DeletePlan, error)
+	RestoreTenantFunc   func(ctx context.Context, id string) (*Tenant, error)
+	ListTenantsFunc     func(ctx context.Context, opts ListOptions) ([]Tenant, error)
+	WatchTenantsFunc    func(ctx context.Context, tenantID string, fn func(TenantChange) error) error
 	GetTenantFunc       func(ctx context.Context, id string) (*Tenant, error)
 	UpdateTenantFunc    func(ctx context.Context, id string, req *UpdateTenantRequest) (*Tenant, error)
+	WakeTenantFunc      func(ctx context.Context, id string) (*WakeResponse, error)
+	RestartTenantFunc   func(ctx context.Context, id string) (*RestartResult, error)
+	SleepTenantFunc     func(ctx context.Context, id string) (*SleepResult, error)
+	SuspendTenantFunc   func(ctx context.Context, id string) (*SuspendResult, error)
+	ResumeTenantFunc    func(ctx context.Context, id string) (*SuspendResult, error)
+	ResetTenantFunc     func(ctx context.Context, id string) (*SuspendResult, error)
+	SendTestMessageFunc func(ctx context.Context, id, text string) (*TestMessageResult, error)
+	ListWakesFunc       func(ctx context.Context, id string) ([]WakeAttempt, error)
+	GetUsageFunc        func(ctx context.Context, id, from, to string) (*UsageReport, error)
+	ListEventsFunc      func(ctx context.Context, id string) ([]TenantEvent, error)
+	GetStateFunc        func(ctx context.Context, id string) (*TenantState, error)
+	CreateSnapshotFunc  func(ctx context.Context, id string, quiesce bool) (*SnapshotResult, error)
+	ListSnapshotsFunc   func(ctx context.Context, id string) ([]Snapshot, error)
+	RestoreSnapshotFunc func(ctx context.Context, id, snapshotID string) (*SnapshotRestoreResult, error)
+	DeleteSnapshotFunc  func(ctx context.Context, id, snapshotID string) error
+	ExportTenantFunc    func(ctx context.Context, id string) (*TenantBundle, error)
+	ImportTenantFunc    func(ctx context.Context, req ImportTenantRequest) (*ImportResult, error)
+	StreamLogsFunc      func(ctx context.Context, id string, tail int, follow bool) (io.ReadCloser, error)
+	ExecTenantFunc      func(ctx context.Context, id string, req ExecRequest) error
+	LookupChatFunc      func(ctx context.Context, chatID string) ([]ChatTenant, error)
+	GetLinkFunc         func(ctx context.Context, id, start string) (*DeepLink, error)
+	GetLinkQRFunc       func(ctx context.Context, id, start string) ([]byte, error)
+	StatusLinkFunc      func(ctx context.Context, id string, ttl time.Duration) (*StatusLink, error)
+	ChatLinkFunc        func(ctx context.Context, id string, ttl time.Duration) (*ChatLink, error)
+	ListImagesFunc      func(ctx context.Context) ([]ImageAlias, error)
+	SetImageFunc        func(ctx context.Context, alias, image string) (*ImageAlias, error)
+	DeleteImageFunc     func(ctx context.Context, alias string) error
+	StartRolloutFunc    func(ctx context.Context, maxUnavailable int) (*Rollout, error)
+	GetRolloutFunc      func(ctx context.Context) (*Rollout, error)
+	SetKillSwitchFunc   func(ctx context.Context, req *SetKillSwitchRequest) (*KillSwitch, error)
+	GetKillSwitchFunc   func(ctx context.Context) (*KillSwitch, error)
+	StartBroadcastFunc  func(ctx context.Context, text string) (*Broadcast, error)
+	GetBroadcastFunc    func(ctx context.Context) (*Broadcast, error)
+	GetWebhookInfoFunc  func(ctx context.Context, tenantID string) (*WebhookInfo, error)
+	DeleteWebhookFunc   func(ctx context.Context, tenantID string) error
 	RegisterWebhookFunc func(ctx context.Context, tenantID string) (*WebhookResponse, error)
 }
 
@@ -21,6 +65,13 @@ func (m *MockClient) CreateTenant(ctx context.Context, req *CreateTenantRequest)
 	return nil, nil
 }
 
+func (m *MockClient) CreateTenants(ctx context.Context, reqs []CreateTenantRequest) (*BulkCreateResponse, error) {
+	if m.CreateTenantsFunc != nil {
+		return m.CreateTenantsFunc(ctx, reqs)
+	}
+	return nil, nil
+}
+
 func (m *MockClient) DeleteTenant(ctx context.Context, id string) error {
 	if m.DeleteTenantFunc != nil {
 		return m.DeleteTenantFunc(ctx, id)
@@ -28,13 +79,48 @@ func (m *MockClient) DeleteTenant(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *MockClient) ListTenants(ctx context.Context) ([]Tenant, error) {
+func (m *MockClient) PlanDeleteTenant(ctx context.Context, id string) (*DeletePlan, error) {
+	if m.PlanDeleteFunc != nil {
+		return m.PlanDeleteFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) PurgeTenant(ctx context.Context, id string) error {
+	if m.PurgeTenantFunc != nil {
+		return m.PurgeTenantFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockClient) PlanPurgeTenant(ctx context.Context, id string) (*DeletePlan, error) {
+	if m.PlanPurgeFunc != nil {
+		return m.PlanPurgeFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) RestoreTenant(ctx context.Context, id string) (*Tenant, error) {
+	if m.RestoreTenantFunc != nil {
+		return m.RestoreTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ListTenants(ctx context.Context, opts ListOptions) ([]Tenant, error) {
 	if m.ListTenantsFunc != nil {
-		return m.ListTenantsFunc(ctx)
+		return m.ListTenantsFunc(ctx, opts)
 	}
 	return nil, nil
 }
 
+func (m *MockClient) WatchTenants(ctx context.Context, tenantID string, fn func(TenantChange) error) error {
+	if m.WatchTenantsFunc != nil {
+		return m.WatchTenantsFunc(ctx, tenantID, fn)
+	}
+	return nil
+}
+
 func (m *MockClient) GetTenant(ctx context.Context, id string) (*Tenant, error) {
 	if m.GetTenantFunc != nil {
 		return m.GetTenantFunc(ctx, id)
@@ -49,6 +135,251 @@ func (m *MockClient) UpdateTenant(ctx context.Context, id string, req *UpdateTen
 	return nil, nil
 }
 
+func (m *MockClient) WakeTenant(ctx context.Context, id string) (*WakeResponse, error) {
+	if m.WakeTenantFunc != nil {
+		return m.WakeTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) SendTestMessage(ctx context.Context, id, text string) (*TestMessageResult, error) {
+	if m.SendTestMessageFunc != nil {
+		return m.SendTestMessageFunc(ctx, id, text)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) RestartTenant(ctx context.Context, id string) (*RestartResult, error) {
+	if m.RestartTenantFunc != nil {
+		return m.RestartTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) SleepTenant(ctx context.Context, id string) (*SleepResult, error) {
+	if m.SleepTenantFunc != nil {
+		return m.SleepTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) SuspendTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	if m.SuspendTenantFunc != nil {
+		return m.SuspendTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ResumeTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	if m.ResumeTenantFunc != nil {
+		return m.ResumeTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ResetTenant(ctx context.Context, id string) (*SuspendResult, error) {
+	if m.ResetTenantFunc != nil {
+		return m.ResetTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetUsage(ctx context.Context, id, from, to string) (*UsageReport, error) {
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(ctx, id, from, to)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ListWakes(ctx context.Context, id string) ([]WakeAttempt, error) {
+	if m.ListWakesFunc != nil {
+		return m.ListWakesFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ListEvents(ctx context.Context, id string) ([]TenantEvent, error) {
+	if m.ListEventsFunc != nil {
+		return m.ListEventsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetState(ctx context.Context, id string) (*TenantState, error) {
+	if m.GetStateFunc != nil {
+		return m.GetStateFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) CreateSnapshot(ctx context.Context, id string, quiesce bool) (*SnapshotResult, error) {
+	if m.CreateSnapshotFunc != nil {
+		return m.CreateSnapshotFunc(ctx, id, quiesce)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ListSnapshots(ctx context.Context, id string) ([]Snapshot, error) {
+	if m.ListSnapshotsFunc != nil {
+		return m.ListSnapshotsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) RestoreSnapshot(ctx context.Context, id, snapshotID string) (*SnapshotRestoreResult, error) {
+	if m.RestoreSnapshotFunc != nil {
+		return m.RestoreSnapshotFunc(ctx, id, snapshotID)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) DeleteSnapshot(ctx context.Context, id, snapshotID string) error {
+	if m.DeleteSnapshotFunc != nil {
+		return m.DeleteSnapshotFunc(ctx, id, snapshotID)
+	}
+	return nil
+}
+
+func (m *MockClient) ExportTenant(ctx context.Context, id string) (*TenantBundle, error) {
+	if m.ExportTenantFunc != nil {
+		return m.ExportTenantFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ImportTenant(ctx context.Context, req ImportTenantRequest) (*ImportResult, error) {
+	if m.ImportTenantFunc != nil {
+		return m.ImportTenantFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) StreamLogs(ctx context.Context, id string, tail int, follow bool) (io.ReadCloser, error) {
+	if m.StreamLogsFunc != nil {
+		return m.StreamLogsFunc(ctx, id, tail, follow)
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (m *MockClient) ExecTenant(ctx context.Context, id string, req ExecRequest) error {
+	if m.ExecTenantFunc != nil {
+		return m.ExecTenantFunc(ctx, id, req)
+	}
+	return nil
+}
+
+func (m *MockClient) LookupChat(ctx context.Context, chatID string) ([]ChatTenant, error) {
+	if m.LookupChatFunc != nil {
+		return m.LookupChatFunc(ctx, chatID)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetLink(ctx context.Context, id, start string) (*DeepLink, error) {
+	if m.GetLinkFunc != nil {
+		return m.GetLinkFunc(ctx, id, start)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetLinkQR(ctx context.Context, id, start string) ([]byte, error) {
+	if m.GetLinkQRFunc != nil {
+		return m.GetLinkQRFunc(ctx, id, start)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) CreateStatusLink(ctx context.Context, id string, ttl time.Duration) (*StatusLink, error) {
+	if m.StatusLinkFunc != nil {
+		return m.StatusLinkFunc(ctx, id, ttl)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) CreateChatLink(ctx context.Context, id string, ttl time.Duration) (*ChatLink, error) {
+	if m.ChatLinkFunc != nil {
+		return m.ChatLinkFunc(ctx, id, ttl)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) ListImages(ctx context.Context) ([]ImageAlias, error) {
+	if m.ListImagesFunc != nil {
+		return m.ListImagesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) SetImage(ctx context.Context, alias, image string) (*ImageAlias, error) {
+	if m.SetImageFunc != nil {
+		return m.SetImageFunc(ctx, alias, image)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) DeleteImage(ctx context.Context, alias string) error {
+	if m.DeleteImageFunc != nil {
+		return m.DeleteImageFunc(ctx, alias)
+	}
+	return nil
+}
+
+func (m *MockClient) StartRollout(ctx context.Context, maxUnavailable int) (*Rollout, error) {
+	if m.StartRolloutFunc != nil {
+		return m.StartRolloutFunc(ctx, maxUnavailable)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetRollout(ctx context.Context) (*Rollout, error) {
+	if m.GetRolloutFunc != nil {
+		return m.GetRolloutFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) SetKillSwitch(ctx context.Context, req *SetKillSwitchRequest) (*KillSwitch, error) {
+	if m.SetKillSwitchFunc != nil {
+		return m.SetKillSwitchFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetKillSwitch(ctx context.Context) (*KillSwitch, error) {
+	if m.GetKillSwitchFunc != nil {
+		return m.GetKillSwitchFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) StartBroadcast(ctx context.Context, text string) (*Broadcast, error) {
+	if m.StartBroadcastFunc != nil {
+		return m.StartBroadcastFunc(ctx, text)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetBroadcast(ctx context.Context) (*Broadcast, error) {
+	if m.GetBroadcastFunc != nil {
+		return m.GetBroadcastFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetWebhookInfo(ctx context.Context, tenantID string) (*WebhookInfo, error) {
+	if m.GetWebhookInfoFunc != nil {
+		return m.GetWebhookInfoFunc(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) DeleteWebhook(ctx context.Context, tenantID string) error {
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(ctx, tenantID)
+	}
+	return nil
+}
+
 func (m *MockClient) RegisterWebhook(ctx context.Context, tenantID string) (*WebhookResponse, error) {
 	if m.RegisterWebhookFunc != nil {
 		return m.RegisterWebhookFunc(ctx, tenantID)
@@ -1,33 +1,93 @@
 package api
 
 import (
-	"time"
+	"fmt"
+	"io"
+
+	"github.com/shawn/agentic-tenancy/pkg/client"
+)
+
+// The orchestrator's types live in pkg/client, shared with the router
+type (
+	Tenant                = client.Tenant
+	CreateTenantRequest   = client.CreateTenantRequest
+	BulkCreateResult      = client.BulkCreateResult
+	BulkCreateResponse    = client.BulkCreateResponse
+	ListOptions           = client.ListOptions
+	UpdateTenantRequest   = client.UpdateTenantRequest
+	ResizeOp              = client.ResizeOp
+	DNSConfig             = client.DNSConfig
+	LogForwardConfig      = client.LogForwardConfig
+	SlackConfig           = client.SlackConfig
+	WakeResponse          = client.WakeResponse
+	RestartResult         = client.RestartResult
+	SleepResult           = client.SleepResult
+	SuspendResult         = client.SuspendResult
+	TestMessageResult     = client.TestMessageResult
+	WakeAttempt           = client.WakeAttempt
+	TenantEvent           = client.TenantEvent
+	UsageReport           = client.UsageReport
+	Usage                 = client.Usage
+	UsageDay              = client.UsageDay
+	UsageMonth            = client.UsageMonth
+	TenantChange          = client.TenantChange
+	TenantState           = client.TenantState
+	Snapshot              = client.Snapshot
+	SnapshotRequest       = client.SnapshotRequest
+	SnapshotResult        = client.SnapshotResult
+	SnapshotRestoreResult = client.SnapshotRestoreResult
+	TenantBundle          = client.TenantBundle
+	StateManifest         = client.StateManifest
+	StateObject           = client.StateObject
+	ImportTenantRequest   = client.ImportTenantRequest
+	ImportResult          = client.ImportResult
+	DeletePlan            = client.DeletePlan
+	WebhookInfo           = client.WebhookInfo
+	ChatTenant            = client.ChatTenant
+	DeepLink              = client.DeepLink
+	StatusLink            = client.StatusLink
+	ChatLink              = client.ChatLink
+	ImageAlias            = client.ImageAlias
+	Rollout               = client.Rollout
+	KillSwitch            = client.KillSwitch
+	Broadcast             = client.Broadcast
+	BroadcastFailure      = client.BroadcastFailure
+	SetKillSwitchRequest  = client.SetKillSwitchRequest
 )
 
-type Tenant struct {
-	TenantID      string    `json:"tenant_id"`
-	Status        string    `json:"status"`
-	BotToken      string    `json:"bot_token,omitempty"` // Redacted in most responses
-	IdleTimeoutS  int       `json:"idle_timeout_s"`
-	PodName       string    `json:"pod_name,omitempty"`
-	PodIP         string    `json:"pod_ip,omitempty"`
-	LastActiveAt  time.Time `json:"last_active_at,omitempty"`
-	CreatedAt     time.Time `json:"created_at,omitempty"`
+// WebhookResponse is the router's answer to a webhook registration
+type WebhookResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	URL     string `json:"url,omitempty"`
 }
 
-type CreateTenantRequest struct {
-	TenantID     string `json:"tenant_id"`
-	BotToken     string `json:"bot_token"`
-	IdleTimeoutS int    `json:"idle_timeout_s"`
+// ExecRequest is a command to run in a tenant's agent container and the
+// streams to attach to it
+type ExecRequest struct {
+	Command []string
+	// Stdin is sent to the command; nil attaches none
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Stderr receives the command's stderr unless TTY is set, when it is
+	// merged into Stdout
+	Stderr io.Writer
+	TTY    bool
+	// Resize delivers the local terminal's size, first on start and then
+	// on each change, while TTY is set. Nil leaves the remote default.
+	Resize <-chan TerminalSize
 }
 
-type UpdateTenantRequest struct {
-	BotToken     *string `json:"bot_token,omitempty"`
-	IdleTimeoutS *int    `json:"idle_timeout_s,omitempty"`
+// TerminalSize is a terminal's width and height in characters
+type TerminalSize struct {
+	Width, Height uint16
 }
 
-type WebhookResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	URL     string `json:"url,omitempty"`
+// ExitError is a command in an exec session that exited non-zero
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command terminated with exit code %d", e.Code)
 }
@@ -4,9 +4,14 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"os/exec"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Config holds the kubectl execution configuration.
@@ -15,19 +20,40 @@ type Config struct {
 	Context    string
 	Deployment string
 	Port       int
+	// PathPrefix is prepended to every API path, e.g. "/env/staging" to
+	// address a non-default control-plane environment.
+	PathPrefix string
+	// APIKey is sent as a bearer token when set
+	APIKey string
 }
 
 // ExecAPICall executes a kubectl exec command to call an API endpoint on a deployment.
 func ExecAPICall(ctx context.Context, cfg *Config, method, path string, body []byte) ([]byte, error) {
-	args := buildKubectlArgs(cfg, method, path, body)
+	return ExecAPICallWithHeaders(ctx, cfg, method, path, nil, body)
+}
+
+// ExecAPICallWithHeaders is ExecAPICall with extra request headers.
+// kubectl is killed at ctx's deadline, and wget in the pod is given the
+// same deadline so it doesn't outlive the call.
+func ExecAPICallWithHeaders(ctx context.Context, cfg *Config, method, path string, headers map[string]string, body []byte) ([]byte, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	args := buildKubectlArgs(cfg, method, path, headers, body, timeout)
 
 	cmd := exec.CommandContext(ctx, "kubectl", args...)
 	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("no response within %s (raise it with --timeout): %w", timeout.Round(time.Second), ctx.Err())
+	}
 
 	return parseResponse(output, err)
 }
 
-func buildKubectlArgs(cfg *Config, method, path string, body []byte) []string {
+// buildKubectlArgs builds the kubectl exec call. A timeout above zero
+// limits wget to a single try of at most that long.
+func buildKubectlArgs(cfg *Config, method, path string, headers map[string]string, body []byte, timeout time.Duration) []string {
 	var args []string
 
 	// Add context if specified
@@ -46,6 +72,23 @@ func buildKubectlArgs(cfg *Config, method, path string, body []byte) []string {
 		fmt.Sprintf("--method=%s", method),
 	)
 
+	if timeout > 0 {
+		// wget's timeout is per network operation, in whole seconds; a retry
+		// would come too late
+		args = append(args, fmt.Sprintf("--timeout=%d", int(math.Ceil(timeout.Seconds()))), "--tries=1")
+	}
+
+	if cfg.APIKey != "" {
+		headers = maps.Clone(headers)
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		args = append(args, fmt.Sprintf("--header=%s: %s", k, headers[k]))
+	}
+
 	// Add headers and body for POST/PATCH/PUT
 	if body != nil && len(body) > 0 {
 		args = append(args,
@@ -55,7 +98,7 @@ func buildKubectlArgs(cfg *Config, method, path string, body []byte) []string {
 	}
 
 	// Target URL (pod-local)
-	url := fmt.Sprintf("http://localhost:%d%s", cfg.Port, path)
+	url := fmt.Sprintf("http://localhost:%d%s%s", cfg.Port, cfg.PathPrefix, path)
 	args = append(args, url)
 
 	return args
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,7 +15,7 @@ func TestBuildKubectlArgs_Basic(t *testing.T) {
 		Port:       8080,
 	}
 
-	args := buildKubectlArgs(cfg, "GET", "/tenants", nil)
+	args := buildKubectlArgs(cfg, "GET", "/tenants", nil, nil, 0)
 
 	assert.Contains(t, args, "exec")
 	assert.Contains(t, args, "-n")
@@ -33,7 +34,7 @@ func TestBuildKubectlArgs_WithContext(t *testing.T) {
 		Port:       8080,
 	}
 
-	args := buildKubectlArgs(cfg, "GET", "/tenants", nil)
+	args := buildKubectlArgs(cfg, "GET", "/tenants", nil, nil, 0)
 
 	assert.Equal(t, "--context", args[0])
 	assert.Equal(t, "prod-cluster", args[1])
@@ -47,12 +48,40 @@ func TestBuildKubectlArgs_WithBody(t *testing.T) {
 	}
 
 	body := []byte(`{"tenant_id":"alice"}`)
-	args := buildKubectlArgs(cfg, "POST", "/tenants", body)
+	args := buildKubectlArgs(cfg, "POST", "/tenants", nil, body, 0)
 
 	assert.Contains(t, args, "--header=Content-Type: application/json")
 	assert.Contains(t, args, "--body-data={\"tenant_id\":\"alice\"}")
 }
 
+func TestBuildKubectlArgs_WithPathPrefix(t *testing.T) {
+	cfg := &Config{
+		Namespace:  "tenants",
+		Deployment: "orchestrator",
+		Port:       8080,
+		PathPrefix: "/env/staging",
+	}
+
+	args := buildKubectlArgs(cfg, "GET", "/tenants", nil, nil, 0)
+
+	assert.Equal(t, "http://localhost:8080/env/staging/tenants", args[len(args)-1])
+}
+
+func TestBuildKubectlArgs_WithTimeout(t *testing.T) {
+	cfg := &Config{
+		Namespace:  "tenants",
+		Deployment: "orchestrator",
+		Port:       8080,
+	}
+
+	args := buildKubectlArgs(cfg, "DELETE", "/tenants/alice", nil, nil, 179500*time.Millisecond)
+
+	assert.Contains(t, args, "--timeout=180")
+	assert.Contains(t, args, "--tries=1")
+	assert.Equal(t, "http://localhost:8080/tenants/alice", args[len(args)-1])
+	assert.NotContains(t, buildKubectlArgs(cfg, "GET", "/tenants", nil, nil, 0), "--tries=1")
+}
+
 func TestParseResponse_Success(t *testing.T) {
 	output := []byte(`{"tenant_id":"alice","status":"idle"}`)
 
@@ -71,3 +100,27 @@ func TestParseResponse_KubectlError(t *testing.T) {
 	assert.Contains(t, err.Error(), "kubectl exec failed")
 	assert.Contains(t, err.Error(), "NotFound")
 }
+
+func TestBuildKubectlArgs_WithHeaders(t *testing.T) {
+	cfg := &Config{
+		Namespace:  "tenants",
+		Deployment: "orchestrator",
+		Port:       8080,
+	}
+
+	args := buildKubectlArgs(cfg, "DELETE", "/tenants/alice", map[string]string{"X-Confirm": "alice"}, nil, 0)
+
+	assert.Contains(t, args, "--header=X-Confirm: alice")
+	assert.Equal(t, "http://localhost:8080/tenants/alice", args[len(args)-1])
+}
+
+func TestBuildKubectlArgs_APIKey(t *testing.T) {
+	headers := map[string]string{"X-Confirm": "alice"}
+	cfg := &Config{Namespace: "tenants", Deployment: "orchestrator", Port: 8080, APIKey: "s3cret"}
+
+	args := buildKubectlArgs(cfg, "DELETE", "/tenants/alice", headers, nil, 0)
+
+	assert.Contains(t, args, "--header=Authorization: Bearer s3cret")
+	assert.Contains(t, args, "--header=X-Confirm: alice")
+	assert.Len(t, headers, 1, "caller's headers are not modified")
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows progress for long-running operations: an animated line with
+// the current phase and elapsed time on a terminal, or one plain line per
+// phase when writing to a pipe or log file.
+type Spinner struct {
+	w      io.Writer
+	styler *Styler
+	tty    bool
+	start  time.Time
+
+	mu   sync.Mutex
+	msg  string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSpinner creates a Spinner writing to w. Animation is only enabled when
+// w is a terminal; noColor disables ANSI colors either way.
+func NewSpinner(w io.Writer, noColor bool) *Spinner {
+	return &Spinner{w: w, styler: NewStyler(noColor), tty: IsTerminal(w)}
+}
+
+// IsTerminal reports whether w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Start begins timing and shows msg as the first phase.
+func (s *Spinner) Start(msg string) {
+	s.start = time.Now()
+	s.msg = msg
+	if !s.tty {
+		s.styler.FprintInfo(s.w, msg+"...")
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+// Phase replaces the current phase message.
+func (s *Spinner) Phase(msg string) {
+	s.mu.Lock()
+	s.msg = msg
+	s.mu.Unlock()
+	if !s.tty {
+		s.styler.FprintInfo(s.w, fmt.Sprintf("%s... (%s)", msg, FormatElapsed(s.Elapsed())))
+	}
+}
+
+// Stop halts the animation, clears the spinner line and returns the elapsed time.
+func (s *Spinner) Stop() time.Duration {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+	}
+	return s.Elapsed()
+}
+
+// Elapsed returns the time since Start.
+func (s *Spinner) Elapsed() time.Duration {
+	return time.Since(s.start)
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		msg := s.msg
+		s.mu.Unlock()
+
+		frame := spinnerFrames[i%len(spinnerFrames)]
+		if !s.styler.noColor {
+			frame = colorCyan + frame + colorReset
+		}
+		fmt.Fprintf(s.w, "\r\033[K%s %s (%s)", frame, msg, FormatElapsed(s.Elapsed()))
+
+		select {
+		case <-s.stop:
+			fmt.Fprint(s.w, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FormatElapsed renders a duration as "4.2s" under a minute and "2m05s" above.
+func FormatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinner_NonTTY(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSpinner(&buf, true)
+
+	s.Start("Waking tenant")
+	s.Phase("Waiting for pod")
+	s.Stop()
+
+	out := buf.String()
+	assert.Contains(t, out, "ℹ Waking tenant...\n")
+	assert.Contains(t, out, "ℹ Waiting for pod... (")
+	assert.NotContains(t, out, "\r", "non-TTY output must not animate")
+	assert.NotContains(t, out, "\033[", "noColor output must not contain ANSI codes")
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "4.2s", FormatElapsed(4200*time.Millisecond))
+	assert.Equal(t, "2m05s", FormatElapsed(125*time.Second))
+}
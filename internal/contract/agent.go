@@ -0,0 +1,77 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The agent's HTTP API, served by ZeroClaw in each tenant pod
+const (
+	AgentPort = 3000
+	// AgentWebhookPath takes an AgentMessage and answers with an
+	// AgentReply, or the reply as server-sent events
+	AgentWebhookPath = "/webhook"
+	// AgentRestoreStatusPath answers with an AgentRestoreStatus. Optional:
+	// agents without it load their state before listening.
+	AgentRestoreStatusPath = "/restore-status"
+)
+
+// AgentVersion is the version of the payloads below, sent to the agent in
+// the AgentVersionHeader of every request. Bump it when a payload changes
+// in a way an agent can't ignore.
+const (
+	AgentVersion       = "1"
+	AgentVersionHeader = "X-Agent-Contract-Version"
+)
+
+// AgentURL is path on the agent in the pod at podIP
+func AgentURL(podIP, path string) string {
+	return fmt.Sprintf("http://%s:%d%s", podIP, AgentPort, path)
+}
+
+// AgentMessage is the body of a forward to AgentWebhookPath. Without
+// attachments or a callback it is the plain {"message": "..."} agents
+// always got.
+type AgentMessage struct {
+	Message     string            `json:"message"`
+	Attachments []AgentAttachment `json:"attachments,omitempty"`
+	// Callback marks Message as the callback_data of an inline keyboard
+	// button the user pressed, rather than text they typed
+	Callback *AgentCallback `json:"callback,omitempty"`
+}
+
+// AgentAttachment is a file sent with a message, passed inline. A file
+// that couldn't be fetched keeps its metadata and carries Error instead of
+// Data, so the agent can tell the user.
+type AgentAttachment struct {
+	Kind     string `json:"kind"` // photo, document or voice
+	FileName string `json:"file_name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int64  `json:"size"`
+	Data     []byte `json:"data,omitempty"` // base64 in JSON
+	Error    string `json:"error,omitempty"`
+}
+
+// AgentCallback tells the agent which button was pressed: its
+// callback_data, and the message carrying the keyboard
+type AgentCallback struct {
+	Data      string `json:"data"`
+	MessageID int64  `json:"message_id,omitempty"`
+}
+
+// AgentReply is the agent's answer to a message: its text, and optionally
+// a Telegram inline keyboard to attach
+type AgentReply struct {
+	Text        string          `json:"response"`
+	ReplyMarkup json.RawMessage `json:"reply_markup"`
+}
+
+// AgentRestoreStatus is the agent's answer at AgentRestoreStatusPath:
+// whether it has rehydrated its state from the S3 mount and is ready for
+// messages, and if not, how far along it is
+type AgentRestoreStatus struct {
+	Ready bool `json:"ready"`
+	// Progress is the share restored so far, 0-100; optional
+	Progress int    `json:"progress,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
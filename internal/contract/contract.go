@@ -0,0 +1,52 @@
+// Package contract holds the names and payloads that separately deployed
+// components rely on matching: the Redis keys the orchestrator clears and
+// repoints under the router, the Kubernetes object names the orchestrator,
+// reconciler and warm pool look tenants' resources up by, and the HTTP API
+// the router and orchestrator call on the agent in each tenant pod.
+//
+// A change here changes a wire format or a stored name. Old and new
+// versions of the router and orchestrator run side by side during a
+// deploy, so keep changes backward compatible, or bump the version of the
+// payload they touch.
+//
+// The package has no dependencies, so the router can import it.
+package contract
+
+// Redis key prefixes the router owns and the orchestrator also writes or
+// clears. A key is the environment's Redis prefix, the prefix here, then
+// the ID (a tenant ID unless noted).
+const (
+	// EndpointPrefix caches the tenant's pod as a hash of EndpointPodIP and
+	// EndpointTTL, refreshed by the router on every forward
+	EndpointPrefix = "router:endpoint:"
+	// ChatIndexPrefix maps a Telegram chat ID to a hash of the tenants it
+	// talked to and when
+	ChatIndexPrefix     = "router:chat:"
+	BotTokenPrefix      = "router:bottoken:"
+	WebhookSecretPrefix = "router:whsecret:"
+	SlackPrefix         = "router:slack:"
+	LocalePrefix        = "router:locale:"
+	MaxAgePrefix        = "router:maxage:"
+	MaxReplyPrefix      = "router:maxreply:"
+	QueuePrefix         = "router:queue:"
+	DLQPrefix           = "router:dlq:"
+)
+
+// Fields of an EndpointPrefix hash
+const (
+	EndpointPodIP = "pod_ip"
+	// EndpointTTL is the TTL in seconds the entry was cached with, so a
+	// cache hit can refresh it without asking the orchestrator
+	EndpointTTL = "ttl_s"
+)
+
+// The kill switch, set by the orchestrator's POST /admin/killswitch and
+// obeyed by the router
+const (
+	// KillSwitchKey holds the environment's kill switch while it is on
+	// (the ID is empty). Routers read it before every forward.
+	KillSwitchKey = "killswitch"
+	// KillSwitchHeader on a 503 wake response tells the router the wake was
+	// refused by a kill switch: "global" or "tenant"
+	KillSwitchHeader = "X-Kill-Switch"
+)
@@ -0,0 +1,48 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The payloads are read by agents built against version 1; these pin their
+// encoding so a field rename shows up here rather than in production.
+
+func TestAgentMessage_Wire(t *testing.T) {
+	data, err := json.Marshal(AgentMessage{Message: "hi"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"hi"}`, string(data), "a plain message is what agents always got")
+
+	data, err = json.Marshal(AgentMessage{
+		Message:     "yes",
+		Attachments: []AgentAttachment{{Kind: "photo", MimeType: "image/jpeg", Size: 3, Data: []byte("abc")}},
+		Callback:    &AgentCallback{Data: "yes", MessageID: 7},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"yes",
+		"attachments":[{"kind":"photo","mime_type":"image/jpeg","size":3,"data":"YWJj"}],
+		"callback":{"data":"yes","message_id":7}}`, string(data))
+}
+
+func TestAgentReply_Wire(t *testing.T) {
+	var reply AgentReply
+	require.NoError(t, json.Unmarshal([]byte(`{"response":"hello","reply_markup":{"inline_keyboard":[]}}`), &reply))
+	assert.Equal(t, "hello", reply.Text)
+	assert.JSONEq(t, `{"inline_keyboard":[]}`, string(reply.ReplyMarkup))
+
+	var status AgentRestoreStatus
+	require.NoError(t, json.Unmarshal([]byte(`{"ready":false,"progress":40,"message":"loading memory"}`), &status))
+	assert.Equal(t, AgentRestoreStatus{Progress: 40, Message: "loading memory"}, status)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "http://10.0.0.1:3000/webhook", AgentURL("10.0.0.1", AgentWebhookPath))
+	assert.Equal(t, "zeroclaw-alice", PodName("alice"))
+	assert.Equal(t, "pvc-tenant-alice", PVCName("alice"))
+	assert.Equal(t, "zeroclaw-alice-bot-token", BotTokenSecretName("alice"))
+	assert.Equal(t, "zeroclaw-alice", ServiceAccountName("alice"))
+	assert.Equal(t, "router:endpoint:", EndpointPrefix)
+}
@@ -0,0 +1,17 @@
+package contract
+
+// Names of a tenant's Kubernetes objects. The orchestrator creates them;
+// the reconciler, warm pool and operators find them by these names.
+
+// PodName is the tenant's pod, unless a blue/green restart gave it a
+// suffixed name; the registry's pod_name is authoritative.
+func PodName(tenantID string) string { return "zeroclaw-" + tenantID }
+
+// PVCName is the claim on the tenant's S3 state volume
+func PVCName(tenantID string) string { return "pvc-tenant-" + tenantID }
+
+// BotTokenSecretName is the Secret holding a tenant pod's bot token
+func BotTokenSecretName(tenantID string) string { return "zeroclaw-" + tenantID + "-bot-token" }
+
+// ServiceAccountName is a tenant's own ServiceAccount
+func ServiceAccountName(tenantID string) string { return "zeroclaw-" + tenantID }
@@ -0,0 +1,196 @@
+// Package digest sends the owners of tenants that opted in
+// (TenantRecord.WeeklyDigest) a weekly summary of the tenant's messages,
+// uptime, estimated cost and errors, over the same channels as their
+// notifications (TenantRecord.LogForward). It runs on the lifecycle leader
+// only.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// Kind is the notification kind webhook receivers see
+const Kind = "weekly_digest"
+
+const (
+	checkInterval = time.Hour
+	// A week's digest is due at sendHour on Monday in the tenant's time zone
+	sendHour = 9
+)
+
+// Rates price a week's usage for the digest's cost estimate. A zero Rates
+// leaves the estimate out.
+type Rates struct {
+	PodHour       float64 // per hour a tenant's pod runs
+	MillionTokens float64 // per million estimated tokens, in and out
+	Currency      string  // shown after the amount, e.g. "USD"
+}
+
+// Enabled reports whether digests include a cost estimate
+func (r Rates) Enabled() bool {
+	return r.PodHour > 0 || r.MillionTokens > 0
+}
+
+// Cost estimates what u cost
+func (r Rates) Cost(u registry.Usage) float64 {
+	return float64(u.PodSeconds)/3600*r.PodHour + float64(u.TokensIn+u.TokensOut)/1e6*r.MillionTokens
+}
+
+// Sender checks hourly for tenants whose digest is due and sends it.
+type Sender struct {
+	reg      registry.Client
+	notifier notify.Notifier
+	rates    Rates
+}
+
+// New creates a sender pricing usage at rates
+func New(reg registry.Client, notifier notify.Notifier, rates Rates) *Sender {
+	return &Sender{reg: reg, notifier: notifier, rates: rates}
+}
+
+// Run checks once, then hourly until ctx is cancelled.
+func (s *Sender) Run(ctx context.Context) {
+	slog.Info("digest sender: starting")
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		s.Check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check sends every digest due at now
+func (s *Sender) Check(ctx context.Context, now time.Time) {
+	tenants, err := s.reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("digest sender: list tenants failed", "err", err)
+		return
+	}
+	for _, t := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if !t.WeeklyDigest || t.LogForward == nil || t.Status == registry.StatusTerminated {
+			continue
+		}
+		end := weekEnd(now, i18n.Location(t.Timezone))
+		if !t.DigestSentAt.Before(end) {
+			continue
+		}
+		s.send(ctx, t, end, now)
+	}
+}
+
+// send builds and delivers the digest of the week before end. The week is
+// marked sent even if a target failed, so the targets that worked don't get
+// it again an hour later.
+func (s *Sender) send(ctx context.Context, t *registry.TenantRecord, end, now time.Time) {
+	n, err := s.Build(ctx, t, end)
+	if err != nil {
+		slog.Warn("digest sender: build failed", "tenant", t.TenantID, "err", err)
+		return
+	}
+	n.Time = now.UTC()
+	if err := s.notifier.Notify(ctx, t, n); err != nil {
+		slog.Warn("digest sender: notify failed", "tenant", t.TenantID, "err", err)
+	}
+	if err := s.reg.UpdateDigestSentAt(ctx, t.TenantID, now.UTC()); err != nil {
+		slog.Error("digest sender: record sent failed", "tenant", t.TenantID, "err", err)
+	}
+}
+
+// Build summarizes the tenant's week before end. Usage is recorded per UTC
+// day, so it is counted for the UTC days with the week's dates; uptime is the
+// share of successful wakes, as on the status page.
+func (s *Sender) Build(ctx context.Context, t *registry.TenantRecord, end time.Time) (notify.Notification, error) {
+	loc := i18n.Location(t.Timezone)
+	start := end.AddDate(0, 0, -7)
+	first, last := utcDate(start.In(loc)), utcDate(end.In(loc).AddDate(0, 0, -1))
+	days, err := s.reg.ListUsage(ctx, t.TenantID, first, last)
+	if err != nil {
+		return notify.Notification{}, fmt.Errorf("list usage: %w", err)
+	}
+	wakes, err := s.reg.ListWakes(ctx, t.TenantID)
+	if err != nil {
+		return notify.Notification{}, fmt.Errorf("list wakes: %w", err)
+	}
+	events, err := s.reg.ListEvents(ctx, t.TenantID)
+	if err != nil {
+		return notify.Notification{}, fmt.Errorf("list events: %w", err)
+	}
+
+	var total registry.Usage
+	for _, d := range days {
+		total = total.Add(d.Usage)
+	}
+	attempts, failed := 0, 0
+	for _, w := range wakes {
+		if w.StartedAt.Before(start) || !w.StartedAt.Before(end) {
+			continue
+		}
+		attempts++
+		if w.Outcome != registry.WakeOK {
+			failed++
+		}
+	}
+	uptime := 100.0
+	if attempts > 0 {
+		uptime = float64(attempts-failed) * 100 / float64(attempts)
+	}
+	incidents := 0
+	for _, ev := range events {
+		if !ev.Time.Before(start) && ev.Time.Before(end) {
+			incidents++
+		}
+	}
+
+	locale := t.Locale
+	lines := []string{
+		i18n.T(locale, i18n.DigestActivity, total.Messages, total.Wakes),
+		i18n.T(locale, i18n.DigestUptime, fmt.Sprintf("%.1f%%", uptime), time.Duration(total.PodSeconds)*time.Second),
+	}
+	if s.rates.Enabled() {
+		lines = append(lines, i18n.T(locale, i18n.DigestCost, fmt.Sprintf("%.2f %s", s.rates.Cost(total), s.rates.Currency)))
+	}
+	if failed > 0 || incidents > 0 {
+		lines = append(lines, i18n.T(locale, i18n.DigestErrors, failed, incidents))
+	} else {
+		lines = append(lines, i18n.T(locale, i18n.DigestNoErrors))
+	}
+	return notify.Notification{
+		TenantID: t.TenantID,
+		Kind:     Kind,
+		Title: i18n.T(locale, i18n.DigestTitle, t.TenantID,
+			first.Format(registry.UsageDateFormat), last.Format(registry.UsageDateFormat)),
+		Lines:  lines,
+		Locale: locale,
+	}, nil
+}
+
+// weekEnd returns the last Monday sendHour:00 in loc at or before now, which
+// ends the newest week due a digest
+func weekEnd(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	end := time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, sendHour, 0, 0, 0, loc)
+	if end.After(now) {
+		end = end.AddDate(0, 0, -7)
+	}
+	return end
+}
+
+// utcDate returns midnight UTC of t's calendar date
+func utcDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,79 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct{ sent []notify.Notification }
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *registry.TenantRecord, n notify.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestWeekEnd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Monday 2026-10-12 09:00 CEST is 07:00 UTC
+	monday := time.Date(2026, 10, 12, 7, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, monday, weekEnd(monday, berlin).UTC())
+	assert.Equal(t, monday, weekEnd(monday.Add(3*24*time.Hour), berlin).UTC())
+	assert.Equal(t, monday.AddDate(0, 0, -7), weekEnd(monday.Add(-time.Minute), berlin).UTC(),
+		"before 09:00 on Monday the newest week is the one before")
+}
+
+func TestCheck_SendsDueDigestOnce(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	chat := &registry.LogForwardConfig{TelegramChatID: 42}
+	for _, rec := range []*registry.TenantRecord{
+		{TenantID: "alice", BotToken: "tok", LogForward: chat, WeeklyDigest: true},
+		{TenantID: "bob", BotToken: "tok", LogForward: chat},     // not opted in
+		{TenantID: "carol", BotToken: "tok", WeeklyDigest: true}, // nowhere to send it
+	} {
+		require.NoError(t, reg.CreateTenant(ctx, rec))
+	}
+
+	end := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	day := end.AddDate(0, 0, -2)
+	require.NoError(t, reg.AddUsage(ctx, "alice", day, registry.Usage{Messages: 40, Wakes: 3, PodSeconds: 7200, TokensIn: 300_000, TokensOut: 700_000}))
+	require.NoError(t, reg.AddUsage(ctx, "alice", end.AddDate(0, 0, -10), registry.Usage{Messages: 1000}))
+	for _, outcome := range []string{registry.WakeOK, registry.WakeOK, registry.WakeOK, registry.WakeFailed} {
+		require.NoError(t, reg.RecordWake(ctx, "alice", registry.WakeAttempt{StartedAt: day, Outcome: outcome}, 20))
+	}
+	require.NoError(t, reg.RecordEvent(ctx, "alice", registry.TenantEvent{Time: day, Kind: registry.EventOOMKilled}, 50))
+
+	n := &fakeNotifier{}
+	s := New(reg, n, Rates{PodHour: 0.05, MillionTokens: 2, Currency: "USD"})
+	now := end.Add(30 * time.Minute)
+	s.Check(ctx, now)
+	require.Len(t, n.sent, 1)
+	got := n.sent[0]
+	assert.Equal(t, "alice", got.TenantID)
+	assert.Equal(t, Kind, got.Kind)
+	assert.Equal(t, "📊 alice, week of 2026-10-05 to 2026-10-11", got.Title)
+	assert.Equal(t, []string{
+		"💬 40 message(s), 3 wake(s)",
+		"✅ Uptime 75.0%, running for 2h0m0s",
+		"💰 Estimated cost: 2.10 USD",
+		"⚠️ 1 failed wake(s), 1 pod incident(s)",
+	}, got.Lines)
+
+	rec, err := reg.GetTenant(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, now, rec.DigestSentAt)
+
+	s.Check(ctx, now.Add(time.Hour))
+	assert.Len(t, n.sent, 1, "a week's digest is sent once")
+	s.Check(ctx, now.AddDate(0, 0, 7))
+	assert.Len(t, n.sent, 2)
+	assert.Equal(t, []string{"💬 0 message(s), 0 wake(s)", "✅ Uptime 100.0%, running for 0s", "💰 Estimated cost: 0.00 USD", "👍 No errors"}, n.sent[1].Lines)
+}
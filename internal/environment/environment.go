@@ -0,0 +1,166 @@
+// Package environment describes isolated control-plane environments (e.g.
+// dev/staging/prod) served by a single orchestrator and router deployment.
+//
+// Every environment has its own DynamoDB table, Kubernetes namespace, Redis
+// key prefix and URL path prefix, so tenants with the same ID in different
+// environments never share state. The unnamed default environment keeps the
+// legacy unprefixed layout.
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// nameRe keeps names usable in namespaces, PV names and URL paths.
+var nameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,18}[a-z0-9])?$`)
+
+// Environment is one isolated set of control-plane resources. Zero-valued
+// fields are derived from the default environment by Parse.
+type Environment struct {
+	Name           string `json:"-"`
+	Table          string `json:"table,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	RedisPrefix    string `json:"redis_prefix,omitempty"`
+	WarmPoolTarget *int   `json:"warm_pool_target,omitempty"`
+	// WarmNamespace is where the environment's warm pool runs, so it can
+	// have its own quotas and policies. Empty = Namespace.
+	WarmNamespace string `json:"warm_namespace,omitempty"`
+	// MigrateTo is a table the registry is being moved to: written along
+	// with Table, and read instead of it once DYNAMODB_READ_FROM says so
+	MigrateTo string `json:"migrate_to,omitempty"`
+}
+
+// WarmPoolNamespace is the namespace of the environment's warm pool
+func (e Environment) WarmPoolNamespace() string {
+	if e.WarmNamespace != "" {
+		return e.WarmNamespace
+	}
+	return e.Namespace
+}
+
+// IsDefault reports whether e is the unnamed legacy environment.
+func (e Environment) IsDefault() bool { return e.Name == "" }
+
+// PathPrefix is where the environment's API is mounted on the orchestrator
+// and router ("" for the default environment).
+func (e Environment) PathPrefix() string {
+	if e.IsDefault() {
+		return ""
+	}
+	return "/env/" + e.Name
+}
+
+// S3Prefix is the key prefix for a tenant's state in the shared bucket.
+func (e Environment) S3Prefix(tenantID string) string {
+	if e.IsDefault() {
+		return fmt.Sprintf("tenants/%s/", tenantID)
+	}
+	return fmt.Sprintf("%s/tenants/%s/", e.Name, tenantID)
+}
+
+// SnapshotPrefix is the key prefix for a tenant's state snapshots. It lies
+// outside S3Prefix, so a tenant's IAM role, scoped to its own prefix, can't
+// reach them.
+func (e Environment) SnapshotPrefix(tenantID string) string {
+	if e.IsDefault() {
+		return fmt.Sprintf("snapshots/%s/", tenantID)
+	}
+	return fmt.Sprintf("%s/snapshots/%s/", e.Name, tenantID)
+}
+
+// SecretName names a tenant's secret under prefix (e.g. a bot token in
+// Secrets Manager).
+func (e Environment) SecretName(prefix, tenantID string) string {
+	if e.IsDefault() {
+		return prefix + tenantID
+	}
+	return prefix + e.Name + "/" + tenantID
+}
+
+// Parse parses the ENVIRONMENTS JSON object, e.g.
+//
+//	{"staging":{},"prod":{"table":"tenant-registry-prod","warm_pool_target":20}}
+//
+// Unset fields default to "<base table>-<name>", "<base namespace>-<name>"
+// and "<name>:", and the warm namespace to "<base warm namespace>-<name>" if
+// base has one. The result is sorted by name and excludes base itself. An
+// empty string yields no environments.
+func Parse(s string, base Environment) ([]Environment, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var raw map[string]Environment
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("parse environments: %w", err)
+	}
+
+	tables := map[string]string{base.Table: "default"}
+	if base.MigrateTo != "" {
+		tables[base.MigrateTo] = "default"
+	}
+	namespaces := map[string]string{base.Namespace: "default"}
+	if base.WarmNamespace != "" && base.WarmNamespace != base.Namespace {
+		namespaces[base.WarmNamespace] = "default"
+	}
+	prefixes := map[string]string{base.RedisPrefix: "default"}
+	claim := func(seen map[string]string, kind, value, name string) error {
+		if other, ok := seen[value]; ok {
+			return fmt.Errorf("environment %q: %s %q already used by %s", name, kind, value, other)
+		}
+		seen[value] = name
+		return nil
+	}
+
+	envs := make([]Environment, 0, len(raw))
+	for name, e := range raw {
+		if !nameRe.MatchString(name) {
+			return nil, fmt.Errorf("environment %q: name must be 1-20 lowercase alphanumerics or '-'", name)
+		}
+		e.Name = name
+		if e.Table == "" {
+			e.Table = base.Table + "-" + name
+		}
+		if e.Namespace == "" {
+			e.Namespace = base.Namespace + "-" + name
+		}
+		if e.RedisPrefix == "" {
+			e.RedisPrefix = name + ":"
+		}
+		if e.WarmNamespace == "" && base.WarmNamespace != "" {
+			e.WarmNamespace = base.WarmNamespace + "-" + name
+		}
+		if e.WarmPoolTarget != nil && *e.WarmPoolTarget < 0 {
+			return nil, fmt.Errorf("environment %q: warm_pool_target must be >= 0", name)
+		}
+		envs = append(envs, e)
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+
+	for _, e := range envs {
+		if err := claim(tables, "table", e.Table, e.Name); err != nil {
+			return nil, err
+		}
+		if e.MigrateTo != "" {
+			if err := claim(tables, "migrate_to table", e.MigrateTo, e.Name); err != nil {
+				return nil, err
+			}
+		}
+		if err := claim(namespaces, "namespace", e.Namespace, e.Name); err != nil {
+			return nil, err
+		}
+		// Each warm pool has its own warm-pool Deployment, so it can't
+		// share a namespace with another environment's pods
+		if e.WarmNamespace != "" && e.WarmNamespace != e.Namespace {
+			if err := claim(namespaces, "warm_namespace", e.WarmNamespace, e.Name); err != nil {
+				return nil, err
+			}
+		}
+		if err := claim(prefixes, "redis_prefix", e.RedisPrefix, e.Name); err != nil {
+			return nil, err
+		}
+	}
+	return envs, nil
+}
@@ -0,0 +1,81 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = Environment{Table: "tenant-registry", Namespace: "tenants"}
+
+func TestParse_Defaults(t *testing.T) {
+	envs, err := Parse(`{"staging":{},"dev":{"namespace":"dev-tenants","warm_pool_target":0}}`, base)
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+
+	dev, staging := envs[0], envs[1]
+	assert.Equal(t, "dev", dev.Name)
+	assert.Equal(t, "tenant-registry-dev", dev.Table)
+	assert.Equal(t, "dev-tenants", dev.Namespace)
+	assert.Equal(t, "dev:", dev.RedisPrefix)
+	if assert.NotNil(t, dev.WarmPoolTarget) {
+		assert.Equal(t, 0, *dev.WarmPoolTarget)
+	}
+
+	assert.Equal(t, "staging", staging.Name)
+	assert.Equal(t, "tenants-staging", staging.Namespace)
+	assert.Equal(t, "/env/staging", staging.PathPrefix())
+	assert.Equal(t, "staging/tenants/alice/", staging.S3Prefix("alice"))
+	assert.Equal(t, "staging/snapshots/alice/", staging.SnapshotPrefix("alice"))
+	assert.Equal(t, "bot-token/staging/alice", staging.SecretName("bot-token/", "alice"))
+	assert.Nil(t, staging.WarmPoolTarget)
+	assert.Equal(t, "tenants-staging", staging.WarmPoolNamespace(), "the warm pool shares the tenants' namespace by default")
+}
+
+func TestParse_WarmNamespace(t *testing.T) {
+	warmBase := Environment{Table: "tenant-registry", Namespace: "tenants", WarmNamespace: "warm"}
+	envs, err := Parse(`{"staging":{},"dev":{"warm_namespace":"dev-warm"}}`, warmBase)
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+	assert.Equal(t, "dev-warm", envs[0].WarmPoolNamespace())
+	assert.Equal(t, "warm-staging", envs[1].WarmPoolNamespace())
+	assert.Equal(t, "warm", warmBase.WarmPoolNamespace())
+
+	for _, s := range []string{
+		`{"dev":{"warm_namespace":"warm"}}`,
+		`{"dev":{"warm_namespace":"tenants"}}`,
+		`{"dev":{"warm_namespace":"tenants-qa"},"qa":{}}`,
+	} {
+		_, err := Parse(s, warmBase)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	envs, err := Parse("", base)
+	require.NoError(t, err)
+	assert.Empty(t, envs)
+
+	assert.Equal(t, "", base.PathPrefix())
+	assert.Equal(t, "tenants/alice/", base.S3Prefix("alice"))
+	assert.Equal(t, "snapshots/alice/", base.SnapshotPrefix("alice"))
+	assert.Equal(t, "bot-token/alice", base.SecretName("bot-token/", "alice"))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{
+		`not json`,
+		`{"Prod":{}}`,
+		`{"-dev":{}}`,
+		`{"dev":{"table":"tenant-registry"}}`,
+		`{"dev":{"namespace":"shared"},"qa":{"namespace":"shared"}}`,
+		`{"dev":{"redis_prefix":""},"qa":{"redis_prefix":"dev:"}}`,
+		`{"dev":{"warm_pool_target":-1}}`,
+		`{"dev":{"migrate_to":"tenant-registry"}}`,
+		`{"dev":{"migrate_to":"tenant-registry-qa"},"qa":{}}`,
+	} {
+		_, err := Parse(s, base)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,69 @@
+// Package envvar reads numeric settings from environment variables for the
+// orchestrator and ztm. A value that doesn't parse is fatal: falling back
+// to zero or the default would quietly change behaviour, e.g. a typo in a
+// grace period killing pods before they flush their state. So is a negative
+// one: every setting read here is a count, size, rate or period.
+package envvar
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// exit ends the process; tests replace it
+var exit = os.Exit
+
+// Int returns key's value as an int, or def if it is unset
+func Int(key string, def int) int {
+	return int(Int64(key, int64(def)))
+}
+
+// Int64 returns key's value as an int64, or def if it is unset
+func Int64(key string, def int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		fail(key, v, "a non-negative integer")
+		return def
+	}
+	return n
+}
+
+// Float returns key's value as a float64, or def if it is unset
+func Float(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		fail(key, v, "a non-negative number")
+		return def
+	}
+	return f
+}
+
+// Duration returns key's value as a duration such as 90s or 10m, or def if
+// it is unset
+func Duration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		fail(key, v, "a non-negative duration such as 90s or 10m")
+		return def
+	}
+	return d
+}
+
+func fail(key, value, want string) {
+	fmt.Fprintf(os.Stderr, "invalid %s=%q: want %s\n", key, value, want)
+	exit(1)
+}
@@ -0,0 +1,67 @@
+package envvar
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trapExit records exit codes instead of exiting
+func trapExit(t *testing.T) *[]int {
+	var codes []int
+	exit = func(code int) { codes = append(codes, code) }
+	t.Cleanup(func() { exit = os.Exit })
+	return &codes
+}
+
+func TestInt(t *testing.T) {
+	codes := trapExit(t)
+	assert.Equal(t, 5, Int("ENVVAR_TEST_INT", 5), "unset")
+
+	t.Setenv("ENVVAR_TEST_INT", "42")
+	assert.Equal(t, 42, Int("ENVVAR_TEST_INT", 5))
+	assert.Equal(t, int64(42), Int64("ENVVAR_TEST_INT", 5))
+	assert.Empty(t, *codes)
+
+	t.Setenv("ENVVAR_TEST_INT", "30s")
+	Int64("ENVVAR_TEST_INT", 5)
+	assert.Equal(t, []int{1}, *codes, "a malformed value exits")
+
+	t.Setenv("ENVVAR_TEST_INT", "0")
+	assert.Zero(t, Int("ENVVAR_TEST_INT", 5))
+	t.Setenv("ENVVAR_TEST_INT", "-3")
+	Int("ENVVAR_TEST_INT", 5)
+	assert.Equal(t, []int{1, 1}, *codes, "a negative value exits")
+}
+
+func TestFloat(t *testing.T) {
+	codes := trapExit(t)
+	t.Setenv("ENVVAR_TEST_FLOAT", "0.5")
+	assert.Equal(t, 0.5, Float("ENVVAR_TEST_FLOAT", 1))
+
+	t.Setenv("ENVVAR_TEST_FLOAT", "half")
+	Float("ENVVAR_TEST_FLOAT", 1)
+	assert.Equal(t, []int{1}, *codes)
+
+	t.Setenv("ENVVAR_TEST_FLOAT", "-0.5")
+	Float("ENVVAR_TEST_FLOAT", 1)
+	assert.Equal(t, []int{1, 1}, *codes)
+}
+
+func TestDuration(t *testing.T) {
+	codes := trapExit(t)
+	assert.Zero(t, Duration("ENVVAR_TEST_DURATION", 0))
+
+	t.Setenv("ENVVAR_TEST_DURATION", "10m")
+	assert.Equal(t, 10*time.Minute, Duration("ENVVAR_TEST_DURATION", 0))
+
+	t.Setenv("ENVVAR_TEST_DURATION", "600")
+	Duration("ENVVAR_TEST_DURATION", 0)
+	assert.Equal(t, []int{1}, *codes, "seconds need a unit")
+
+	t.Setenv("ENVVAR_TEST_DURATION", "-1m")
+	Duration("ENVVAR_TEST_DURATION", 0)
+	assert.Equal(t, []int{1, 1}, *codes)
+}
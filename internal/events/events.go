@@ -0,0 +1,151 @@
+// Package events publishes tenant lifecycle events (created, woken, idle,
+// deleted, failed wakes) to external systems such as billing or monitoring,
+// so they can react to transitions without polling the orchestrator API.
+//
+// Publishing never blocks the operation that caused the event: events are
+// queued and a Bus delivers them to every sink in the background, retrying
+// a failed delivery a few times before giving up on it. Each sink has its
+// own queue, so one that is slow or down doesn't hold up the others.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TenantCreated  = "tenant.created"
+	TenantWoken    = "tenant.woken"
+	TenantIdle     = "tenant.idle"    // pod stopped by the idle timeout or on request
+	TenantDeleted  = "tenant.deleted" // kept for a restore until purged
+	TenantRestored = "tenant.restored"
+	TenantPurged   = "tenant.purged"
+	WakeFailed     = "wake.failed"
+	TenantFailed   = "tenant.failed" // marked failed after too many failed wakes in a row
+)
+
+const (
+	queueSize       = 1000 // events waiting for a sink beyond this are dropped
+	deliverAttempts = 3
+)
+
+// retryBackoff is the pause before a sink's second delivery attempt; it
+// doubles for each one after
+var retryBackoff = time.Second
+
+// Event is one lifecycle transition, delivered to sinks as this JSON
+type Event struct {
+	// ID is unique per event, for receivers that see a delivery twice
+	ID       string         `json:"id"`
+	Type     string         `json:"type"`
+	TenantID string         `json:"tenant_id"`
+	Env      string         `json:"env,omitempty"` // empty for the default environment
+	Time     time.Time      `json:"time"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// Sink delivers events to one external system
+type Sink interface {
+	Publish(ctx context.Context, ev Event) error
+	// String names the sink in logs, without credentials
+	String() string
+}
+
+// Bus queues events and delivers them to its sinks. A nil *Bus accepts and
+// drops every event, so publishers need no check for events being off.
+type Bus struct {
+	sinks []sinkQueue
+}
+
+// sinkQueue holds the events waiting for one sink
+type sinkQueue struct {
+	sink  Sink
+	queue chan Event
+}
+
+// New returns a Bus delivering to sinks once Run is started
+func New(sinks ...Sink) *Bus {
+	b := &Bus{}
+	for _, s := range sinks {
+		b.sinks = append(b.sinks, sinkQueue{sink: s, queue: make(chan Event, queueSize)})
+	}
+	return b
+}
+
+// Publish queues an event of the given type, filling in its ID and time
+func (b *Bus) Publish(typ, env, tenantID string, data map[string]any) {
+	if b == nil || len(b.sinks) == 0 {
+		return
+	}
+	ev := Event{ID: newID(), Type: typ, TenantID: tenantID, Env: env, Time: time.Now().UTC(), Data: data}
+	for _, s := range b.sinks {
+		select {
+		case s.queue <- ev:
+		default:
+			slog.Warn("event queue full, event dropped", "sink", s.sink.String(), "type", typ, "tenant", tenantID)
+		}
+	}
+}
+
+// Run delivers queued events until ctx is cancelled, each sink from its own
+// goroutine and in order
+func (b *Bus) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, s := range b.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s sinkQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-s.queue:
+			if err := deliver(ctx, s.sink, ev); err != nil {
+				slog.Error("event delivery failed", "sink", s.sink.String(), "type", ev.Type, "tenant", ev.TenantID, "id", ev.ID, "err", err)
+			}
+		}
+	}
+}
+
+func deliver(ctx context.Context, s Sink, ev Event) error {
+	var errs []error
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := s.Publish(attemptCtx, ev)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+		if attempt == deliverAttempts {
+			return errors.Join(errs...)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_Webhook(t *testing.T) {
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = time.Second })
+
+	var calls atomic.Int32
+	got := make(chan Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign([]byte("s3cret"), body), r.Header.Get(SignatureHeader))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway) // retried
+			return
+		}
+		var ev Event
+		assert.NoError(t, json.Unmarshal(body, &ev))
+		got <- ev
+	}))
+	defer srv.Close()
+
+	sinks, err := ParseSinks(srv.URL+"/hooks", SinkOptions{WebhookSecret: []byte("s3cret")})
+	require.NoError(t, err)
+	bus := New(sinks...)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	bus.Publish(TenantWoken, "staging", "alice", map[string]any{"start": "warm"})
+	select {
+	case ev := <-got:
+		assert.Equal(t, TenantWoken, ev.Type)
+		assert.Equal(t, "alice", ev.TenantID)
+		assert.Equal(t, "staging", ev.Env)
+		assert.Equal(t, "warm", ev.Data["start"])
+		assert.Len(t, ev.ID, 32)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// sinkFunc adapts a function to Sink
+type sinkFunc func(ctx context.Context, ev Event) error
+
+func (f sinkFunc) Publish(ctx context.Context, ev Event) error { return f(ctx, ev) }
+func (f sinkFunc) String() string                              { return "func" }
+
+func TestBus_SlowSinkDoesNotBlockOthers(t *testing.T) {
+	stuck := sinkFunc(func(ctx context.Context, ev Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	got := make(chan Event, 2)
+	fast := sinkFunc(func(ctx context.Context, ev Event) error {
+		got <- ev
+		return nil
+	})
+	bus := New(stuck, fast)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	bus.Publish(TenantCreated, "", "alice", nil)
+	bus.Publish(TenantWoken, "", "alice", nil)
+	for _, want := range []string{TenantCreated, TenantWoken} {
+		select {
+		case ev := <-got:
+			assert.Equal(t, want, ev.Type, "each sink sees events in order")
+		case <-time.After(5 * time.Second):
+			t.Fatal("event held up behind a stuck sink")
+		}
+	}
+}
+
+func TestBus_NilAcceptsEvents(t *testing.T) {
+	var bus *Bus
+	bus.Publish(TenantCreated, "", "alice", nil)
+}
+
+func TestSNS_Publish(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/sns/aws4_request")
+		r.ParseForm()
+		form = r.PostForm
+	}))
+	defer srv.Close()
+
+	sinks, err := ParseSinks("sns:arn:aws:sns:eu-west-1:123456789012:tenant-events", SinkOptions{
+		AWS:         aws.Config{Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", "")},
+		SNSEndpoint: srv.URL,
+	})
+	require.NoError(t, err)
+	require.NoError(t, sinks[0].Publish(context.Background(), Event{Type: TenantDeleted, TenantID: "alice"}))
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, "arn:aws:sns:eu-west-1:123456789012:tenant-events", form.Get("TopicArn"))
+	assert.Equal(t, TenantDeleted, form.Get("MessageAttributes.entry.1.Value.StringValue"))
+	assert.Contains(t, form.Get("Message"), `"tenant_id":"alice"`)
+}
+
+func TestParseSinks(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	defer rdb.Close()
+	sinks, err := ParseSinks("https://example.com/hooks?token=x, redis:tenant-events,", SinkOptions{Redis: rdb})
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	assert.Equal(t, "webhook https://example.com/hooks", sinks[0].String(), "no query string in logs")
+	assert.Equal(t, "redis tenant-events", sinks[1].String())
+
+	sinks, err = ParseSinks("", SinkOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, sinks)
+
+	for _, spec := range []string{
+		"ftp://example.com",
+		"sns:not-an-arn",
+		"sns:arn:aws:sns:us-east-1:123456789012:t", // no AWS credentials
+		"redis:tenant-events",                      // no Redis client
+	} {
+		_, err := ParseSinks(spec, SinkOptions{})
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,176 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// SignatureHeader carries a webhook body's HMAC-SHA256, as "sha256=<hex>",
+// keyed with the webhook secret
+const SignatureHeader = "X-Event-Signature"
+
+// SinkOptions holds what ParseSinks needs to build each kind of sink
+type SinkOptions struct {
+	// WebhookSecret signs webhook bodies; empty sends them unsigned
+	WebhookSecret []byte
+	Redis         *redis.Client
+	AWS           aws.Config
+	// SNSEndpoint overrides the SNS endpoint, e.g. for a local emulator
+	SNSEndpoint string
+}
+
+// ParseSinks parses a comma-separated list of sinks (EVENT_SINKS):
+//
+//	https://billing.example.com/hooks/tenants  POST each event as JSON
+//	sns:arn:aws:sns:us-east-1:123456789012:tenant-events
+//	redis:tenant-events                        PUBLISH to a channel
+func ParseSinks(spec string, opts SinkOptions) ([]Sink, error) {
+	var sinks []Sink
+	for _, s := range strings.Split(spec, ",") {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			continue
+		case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+			if _, err := url.Parse(s); err != nil {
+				return nil, fmt.Errorf("event sink %q: %w", s, err)
+			}
+			sinks = append(sinks, &Webhook{url: s, secret: opts.WebhookSecret, client: &http.Client{}})
+		case strings.HasPrefix(s, "sns:"):
+			sink, err := newSNS(strings.TrimPrefix(s, "sns:"), opts)
+			if err != nil {
+				return nil, fmt.Errorf("event sink %q: %w", s, err)
+			}
+			sinks = append(sinks, sink)
+		case strings.HasPrefix(s, "redis:"):
+			if opts.Redis == nil {
+				return nil, fmt.Errorf("event sink %q: no Redis client", s)
+			}
+			sinks = append(sinks, &RedisChannel{rdb: opts.Redis, channel: strings.TrimPrefix(s, "redis:")})
+		default:
+			return nil, fmt.Errorf("event sink %q: want an http(s) URL, sns:<topic ARN> or redis:<channel>", s)
+		}
+	}
+	return sinks, nil
+}
+
+// Webhook POSTs each event as JSON to a URL. A 2xx response is a delivery.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func (w *Webhook) Publish(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *Webhook) String() string {
+	u, err := url.Parse(w.url)
+	if err != nil {
+		return "webhook"
+	}
+	return "webhook " + u.Scheme + "://" + u.Host + u.Path
+}
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret, body []byte) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// RedisChannel publishes each event as JSON on a Redis pub/sub channel.
+// Subscribers that aren't connected miss it.
+type RedisChannel struct {
+	rdb     *redis.Client
+	channel string
+}
+
+func (r *RedisChannel) Publish(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Publish(ctx, r.channel, body).Err()
+}
+
+func (r *RedisChannel) String() string { return "redis " + r.channel }
+
+// SNS publishes each event as JSON to an SNS topic, with the event type in
+// the "type" message attribute for subscription filter policies.
+type SNS struct {
+	topicARN string
+	client   *sns.Client
+}
+
+func newSNS(topicARN string, opts SinkOptions) (*SNS, error) {
+	// arn:aws:sns:<region>:<account>:<topic>
+	parts := strings.Split(topicARN, ":")
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "sns" || parts[3] == "" {
+		return nil, fmt.Errorf("not an SNS topic ARN")
+	}
+	if opts.AWS.Credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials")
+	}
+	client := sns.NewFromConfig(opts.AWS, func(o *sns.Options) {
+		o.Region = parts[3]
+		if opts.SNSEndpoint != "" {
+			o.BaseEndpoint = aws.String(opts.SNSEndpoint)
+		}
+	})
+	return &SNS{topicARN: topicARN, client: client}, nil
+}
+
+func (s *SNS) Publish(ctx context.Context, ev Event) error {
+	msg, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(s.topicARN),
+		Message:  aws.String(string(msg)),
+		MessageAttributes: map[string]snstypes.MessageAttributeValue{
+			"type": {DataType: aws.String("String"), StringValue: aws.String(ev.Type)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("sns Publish: %w", err)
+	}
+	return nil
+}
+
+func (s *SNS) String() string { return "sns " + s.topicARN }
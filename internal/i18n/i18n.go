@@ -0,0 +1,370 @@
+// Package i18n holds the catalog of user-facing system messages (the ones
+// the router and orchestrator send, not the agent's replies) and renders them
+// in a tenant's locale and time zone.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // tenant time zones must resolve in minimal images
+)
+
+// DefaultLocale is used for tenants without a locale and for messages a
+// locale doesn't translate.
+const DefaultLocale = "en"
+
+// Key identifies a message in the catalog.
+type Key string
+
+const (
+	StartingUp    Key = "starting_up"
+	StartFailed   Key = "start_failed"
+	ResetDone     Key = "reset_done"
+	ResetFailed   Key = "reset_failed"
+	SleepDone     Key = "sleep_done"
+	SleepFailed   Key = "sleep_failed"
+	AlreadyAsleep Key = "already_asleep"
+	// RestoringState tells a user the bot's pod is up but the agent is
+	// still restoring its saved state
+	RestoringState Key = "restoring_state"
+	// AgentErrors takes the tenant ID, the error line count and the local time
+	AgentErrors Key = "agent_errors"
+	// LinesOmitted takes the number of lines left out
+	LinesOmitted Key = "lines_omitted"
+	// AgentOOMKilled takes the tenant ID, the local time and the memory limit
+	AgentOOMKilled Key = "agent_oom_killed"
+	// AgentCrashLoop takes the tenant ID and the restart count
+	AgentCrashLoop Key = "agent_crash_loop"
+	// StorageFull tells a user the bot can't start because its storage quota is used up
+	StorageFull Key = "storage_full"
+	// BotPaused tells a user the bot's tenant is suspended
+	BotPaused Key = "bot_paused"
+	// Unavailable tells a user the bot is stopped by a kill switch
+	Unavailable Key = "unavailable"
+	// QuotaExceeded tells a user the bot can't start because its plan's run
+	// quota is used up
+	QuotaExceeded Key = "quota_exceeded"
+	// StaleMessages tells a user messages that arrived too late were skipped
+	StaleMessages Key = "stale_messages"
+	// SlowDown tells a user some of their messages were skipped for
+	// arriving faster than the bot's rate limit
+	SlowDown Key = "slow_down"
+	// ReplyCut ends a reply cut short for being too long
+	ReplyCut Key = "reply_cut"
+	// ReplyCutLink ends a reply cut short for being too long; it takes the
+	// link to the full reply
+	ReplyCutLink Key = "reply_cut_link"
+	// ReplyTooLarge replaces a reply too large to even read
+	ReplyTooLarge Key = "reply_too_large"
+	// StateQuotaWarning takes the tenant ID, the state size, the quota and the percentage used
+	StateQuotaWarning Key = "state_quota_warning"
+	// StateQuotaExceeded takes the tenant ID, the state size and the quota
+	StateQuotaExceeded Key = "state_quota_exceeded"
+	// StateQuotaReadOnly takes the tenant ID, the state size and the quota
+	StateQuotaReadOnly Key = "state_quota_read_only"
+	// DigestTitle takes the tenant ID and the first and last day of the week
+	DigestTitle Key = "digest_title"
+	// DigestActivity takes the message count and the wake count
+	DigestActivity Key = "digest_activity"
+	// DigestUptime takes the percentage of successful wakes and the running time
+	DigestUptime Key = "digest_uptime"
+	// DigestCost takes the estimated cost
+	DigestCost Key = "digest_cost"
+	// DigestErrors takes the failed wake count and the pod incident count
+	DigestErrors Key = "digest_errors"
+	// DigestNoErrors says the week had no failed wakes or pod incidents
+	DigestNoErrors Key = "digest_no_errors"
+)
+
+// catalog maps a base language to its messages. Format verbs use explicit
+// argument indexes so translations can reorder them.
+var catalog = map[string]map[Key]string{
+	"en": {
+		StartingUp:         "⏳ Starting up, please wait a moment...",
+		RestoringState:     "📂 Almost there, restoring my saved state...",
+		StartFailed:        "❌ Failed to start. Please try again.",
+		ResetDone:          "🧹 Conversation cleared. Starting fresh!",
+		ResetFailed:        "❌ Couldn't reset the conversation. Please try again.",
+		SleepDone:          "💤 Going to sleep. Send any message to wake me up.",
+		SleepFailed:        "❌ Couldn't go to sleep. Please try again.",
+		AlreadyAsleep:      "💤 Already asleep.",
+		AgentErrors:        "⚠️ %[1]s: %[2]d agent error line(s) at %[3]s",
+		LinesOmitted:       "… %[1]d more line(s) omitted",
+		AgentOOMKilled:     "⚠️ %[1]s ran out of memory (%[3]s) at %[2]s and was restarted, losing what it hadn't saved. If this keeps happening, a larger tier gives it more memory.",
+		AgentCrashLoop:     "⚠️ %[1]s keeps crashing (%[2]d restarts) and is waiting before the next retry.",
+		StorageFull:        "❌ I'm out of storage and can't start. Please ask my owner to free up space or upgrade.",
+		BotPaused:          "⏸️ This bot is paused. Please contact its owner.",
+		Unavailable:        "🚧 This bot is temporarily unavailable. Please try again later.",
+		QuotaExceeded:      "⏳ I've used up my running time for now, so I can't start. Please try again later.",
+		StaleMessages:      "⌛ Sorry, your messages reached me too late to act on safely, so I skipped them. Please send again anything you still need.",
+		SlowDown:           "🐢 You're sending messages faster than I can keep up with, so I skipped some. Please slow down and send again anything I missed.",
+		ReplyCut:           "✂️ This reply was too long for chat and was cut short.",
+		ReplyCutLink:       "✂️ This reply was too long for chat and was cut short. Full reply (link valid for 7 days): %[1]s",
+		ReplyTooLarge:      "❌ My reply was too large to deliver. Please ask for a shorter answer.",
+		StateQuotaWarning:  "💾 %[1]s is using %[2]s of its %[3]s storage (%[4]d%%). Clearing old state or a larger tier keeps it from filling up.",
+		StateQuotaExceeded: "⛔ %[1]s has used all of its %[3]s storage (%[2]s) and won't start until space is freed or it moves to a larger tier.",
+		StateQuotaReadOnly: "⛔ %[1]s has used all of its %[3]s storage (%[2]s). It still runs but can't save anything new until space is freed or it moves to a larger tier.",
+		DigestTitle:        "📊 %[1]s, week of %[2]s to %[3]s",
+		DigestActivity:     "💬 %[1]d message(s), %[2]d wake(s)",
+		DigestUptime:       "✅ Uptime %[1]s, running for %[2]s",
+		DigestCost:         "💰 Estimated cost: %[1]s",
+		DigestErrors:       "⚠️ %[1]d failed wake(s), %[2]d pod incident(s)",
+		DigestNoErrors:     "👍 No errors",
+	},
+	"es": {
+		StartingUp:         "⏳ Iniciando, espera un momento...",
+		RestoringState:     "📂 Casi listo, restaurando mi estado guardado...",
+		StartFailed:        "❌ No se pudo iniciar. Inténtalo de nuevo.",
+		ResetDone:          "🧹 Conversación borrada. ¡Empecemos de nuevo!",
+		ResetFailed:        "❌ No se pudo reiniciar la conversación. Inténtalo de nuevo.",
+		SleepDone:          "💤 Me voy a dormir. Envía cualquier mensaje para despertarme.",
+		SleepFailed:        "❌ No pude irme a dormir. Inténtalo de nuevo.",
+		AlreadyAsleep:      "💤 Ya estoy dormido.",
+		AgentErrors:        "⚠️ %[1]s: %[2]d línea(s) de error del agente a las %[3]s",
+		LinesOmitted:       "… %[1]d línea(s) más omitida(s)",
+		AgentOOMKilled:     "⚠️ %[1]s se quedó sin memoria (%[3]s) a las %[2]s y se reinició, perdiendo lo que no había guardado. Si se repite, un plan superior le da más memoria.",
+		AgentCrashLoop:     "⚠️ %[1]s sigue fallando (%[2]d reinicios) y espera antes del próximo intento.",
+		StorageFull:        "❌ Me quedé sin almacenamiento y no puedo iniciar. Pide a mi propietario que libere espacio o mejore el plan.",
+		BotPaused:          "⏸️ Este bot está en pausa. Contacta a su propietario.",
+		Unavailable:        "🚧 Este bot no está disponible temporalmente. Inténtalo más tarde.",
+		QuotaExceeded:      "⏳ Por ahora agoté mi tiempo de ejecución y no puedo iniciar. Inténtalo más tarde.",
+		StaleMessages:      "⌛ Lo siento, tus mensajes me llegaron demasiado tarde para atenderlos con seguridad, así que los omití. Vuelve a enviar lo que aún necesites.",
+		SlowDown:           "🐢 Envías mensajes más rápido de lo que puedo atender, así que omití algunos. Ve más despacio y vuelve a enviar lo que me haya faltado.",
+		ReplyCut:           "✂️ Esta respuesta era demasiado larga para el chat y se recortó.",
+		ReplyCutLink:       "✂️ Esta respuesta era demasiado larga para el chat y se recortó. Respuesta completa (enlace válido 7 días): %[1]s",
+		ReplyTooLarge:      "❌ Mi respuesta era demasiado grande para entregarla. Pide una respuesta más corta.",
+		StateQuotaWarning:  "💾 %[1]s usa %[2]s de sus %[3]s de almacenamiento (%[4]d%%). Borrar estado antiguo o un plan superior evita que se llene.",
+		StateQuotaExceeded: "⛔ %[1]s agotó sus %[3]s de almacenamiento (%[2]s) y no se iniciará hasta que se libere espacio o pase a un plan superior.",
+		StateQuotaReadOnly: "⛔ %[1]s agotó sus %[3]s de almacenamiento (%[2]s). Sigue funcionando, pero no puede guardar nada nuevo hasta que se libere espacio o pase a un plan superior.",
+		DigestTitle:        "📊 %[1]s, semana del %[2]s al %[3]s",
+		DigestActivity:     "💬 %[1]d mensaje(s), %[2]d arranque(s)",
+		DigestUptime:       "✅ Disponibilidad %[1]s, en ejecución durante %[2]s",
+		DigestCost:         "💰 Costo estimado: %[1]s",
+		DigestErrors:       "⚠️ %[1]d arranque(s) fallido(s), %[2]d incidente(s) del pod",
+		DigestNoErrors:     "👍 Sin errores",
+	},
+	"de": {
+		StartingUp:         "⏳ Wird gestartet, bitte einen Moment Geduld...",
+		RestoringState:     "📂 Fast fertig, mein gespeicherter Zustand wird wiederhergestellt...",
+		StartFailed:        "❌ Start fehlgeschlagen. Bitte versuche es erneut.",
+		ResetDone:          "🧹 Unterhaltung gelöscht. Neuer Anfang!",
+		ResetFailed:        "❌ Die Unterhaltung konnte nicht zurückgesetzt werden. Bitte versuche es erneut.",
+		SleepDone:          "💤 Ich lege mich schlafen. Schick eine beliebige Nachricht, um mich zu wecken.",
+		SleepFailed:        "❌ Konnte nicht schlafen gehen. Bitte versuche es erneut.",
+		AlreadyAsleep:      "💤 Schlafe bereits.",
+		AgentErrors:        "⚠️ %[1]s: %[2]d Agent-Fehlerzeile(n) um %[3]s",
+		LinesOmitted:       "… %[1]d weitere Zeile(n) ausgelassen",
+		AgentOOMKilled:     "⚠️ %[1]s hatte um %[2]s keinen Speicher mehr (%[3]s) und wurde neu gestartet; Ungespeichertes ging verloren. Passiert das öfter, bietet ein größerer Tarif mehr Speicher.",
+		AgentCrashLoop:     "⚠️ %[1]s stürzt wiederholt ab (%[2]d Neustarts) und wartet vor dem nächsten Versuch.",
+		StorageFull:        "❌ Mein Speicher ist voll, ich kann nicht starten. Bitte meinen Besitzer, Platz zu schaffen oder den Tarif zu erhöhen.",
+		BotPaused:          "⏸️ Dieser Bot ist pausiert. Bitte wende dich an seinen Besitzer.",
+		Unavailable:        "🚧 Dieser Bot ist vorübergehend nicht verfügbar. Bitte versuche es später erneut.",
+		QuotaExceeded:      "⏳ Mein Laufzeitkontingent ist vorerst aufgebraucht, ich kann nicht starten. Bitte versuche es später erneut.",
+		StaleMessages:      "⌛ Entschuldige, deine Nachrichten kamen zu spät an, um sie sicher zu bearbeiten, deshalb habe ich sie übersprungen. Bitte sende erneut, was du noch brauchst.",
+		SlowDown:           "🐢 Du sendest Nachrichten schneller, als ich sie bearbeiten kann, deshalb habe ich einige übersprungen. Bitte mach langsamer und sende erneut, was ich verpasst habe.",
+		ReplyCut:           "✂️ Diese Antwort war zu lang für den Chat und wurde gekürzt.",
+		ReplyCutLink:       "✂️ Diese Antwort war zu lang für den Chat und wurde gekürzt. Vollständige Antwort (Link 7 Tage gültig): %[1]s",
+		ReplyTooLarge:      "❌ Meine Antwort war zu groß, um sie zuzustellen. Bitte frag nach einer kürzeren Antwort.",
+		StateQuotaWarning:  "💾 %[1]s belegt %[2]s von %[3]s Speicher (%[4]d%%). Alten Zustand löschen oder ein größerer Tarif verhindert, dass er voll läuft.",
+		StateQuotaExceeded: "⛔ %[1]s hat seine %[3]s Speicher aufgebraucht (%[2]s) und startet erst wieder, wenn Platz frei wird oder er in einen größeren Tarif wechselt.",
+		StateQuotaReadOnly: "⛔ %[1]s hat seine %[3]s Speicher aufgebraucht (%[2]s). Er läuft weiter, kann aber nichts Neues speichern, bis Platz frei wird oder er in einen größeren Tarif wechselt.",
+		DigestTitle:        "📊 %[1]s, Woche vom %[2]s bis %[3]s",
+		DigestActivity:     "💬 %[1]d Nachricht(en), %[2]d Start(s)",
+		DigestUptime:       "✅ Verfügbarkeit %[1]s, Laufzeit %[2]s",
+		DigestCost:         "💰 Geschätzte Kosten: %[1]s",
+		DigestErrors:       "⚠️ %[1]d fehlgeschlagene(r) Start(s), %[2]d Pod-Vorfall/Vorfälle",
+		DigestNoErrors:     "👍 Keine Fehler",
+	},
+	"fr": {
+		StartingUp:         "⏳ Démarrage en cours, veuillez patienter un instant...",
+		RestoringState:     "📂 Presque prêt, restauration de mon état enregistré...",
+		StartFailed:        "❌ Échec du démarrage. Veuillez réessayer.",
+		ResetDone:          "🧹 Conversation effacée. On repart de zéro !",
+		ResetFailed:        "❌ Impossible de réinitialiser la conversation. Veuillez réessayer.",
+		SleepDone:          "💤 Je m'endors. Envoyez n'importe quel message pour me réveiller.",
+		SleepFailed:        "❌ Impossible de m'endormir. Veuillez réessayer.",
+		AlreadyAsleep:      "💤 Déjà endormi.",
+		AgentErrors:        "⚠️ %[1]s : %[2]d ligne(s) d'erreur de l'agent à %[3]s",
+		LinesOmitted:       "… %[1]d ligne(s) supplémentaire(s) omise(s)",
+		AgentOOMKilled:     "⚠️ %[1]s a manqué de mémoire (%[3]s) à %[2]s et a redémarré, perdant ce qui n'était pas enregistré. Si cela se répète, une offre supérieure lui donne plus de mémoire.",
+		AgentCrashLoop:     "⚠️ %[1]s plante à répétition (%[2]d redémarrages) et attend avant la prochaine tentative.",
+		StorageFull:        "❌ Je n'ai plus d'espace de stockage et ne peux pas démarrer. Demandez à mon propriétaire de libérer de l'espace ou de changer d'offre.",
+		BotPaused:          "⏸️ Ce bot est en pause. Veuillez contacter son propriétaire.",
+		Unavailable:        "🚧 Ce bot est temporairement indisponible. Veuillez réessayer plus tard.",
+		QuotaExceeded:      "⏳ J'ai épuisé mon temps d'exécution pour le moment et ne peux pas démarrer. Veuillez réessayer plus tard.",
+		StaleMessages:      "⌛ Désolé, vos messages me sont parvenus trop tard pour être traités sans risque, je les ai donc ignorés. Renvoyez ce dont vous avez encore besoin.",
+		SlowDown:           "🐢 Vous envoyez des messages plus vite que je ne peux les traiter, j'en ai donc ignoré certains. Ralentissez et renvoyez ce que j'ai manqué.",
+		ReplyCut:           "✂️ Cette réponse était trop longue pour le chat et a été tronquée.",
+		ReplyCutLink:       "✂️ Cette réponse était trop longue pour le chat et a été tronquée. Réponse complète (lien valable 7 jours) : %[1]s",
+		ReplyTooLarge:      "❌ Ma réponse était trop volumineuse pour être envoyée. Demandez une réponse plus courte.",
+		StateQuotaWarning:  "💾 %[1]s utilise %[2]s de ses %[3]s de stockage (%[4]d %%). Effacer l'état ancien ou une offre supérieure évite de le remplir.",
+		StateQuotaExceeded: "⛔ %[1]s a épuisé ses %[3]s de stockage (%[2]s) et ne démarrera pas tant que de l'espace n'est pas libéré ou qu'il ne passe pas à une offre supérieure.",
+		StateQuotaReadOnly: "⛔ %[1]s a épuisé ses %[3]s de stockage (%[2]s). Il fonctionne toujours mais ne peut rien enregistrer de nouveau tant que de l'espace n'est pas libéré ou qu'il ne passe pas à une offre supérieure.",
+		DigestTitle:        "📊 %[1]s, semaine du %[2]s au %[3]s",
+		DigestActivity:     "💬 %[1]d message(s), %[2]d réveil(s)",
+		DigestUptime:       "✅ Disponibilité %[1]s, en marche pendant %[2]s",
+		DigestCost:         "💰 Coût estimé : %[1]s",
+		DigestErrors:       "⚠️ %[1]d réveil(s) échoué(s), %[2]d incident(s) de pod",
+		DigestNoErrors:     "👍 Aucune erreur",
+	},
+	"pt": {
+		StartingUp:         "⏳ Iniciando, aguarde um momento...",
+		RestoringState:     "📂 Quase pronto, restaurando meu estado salvo...",
+		StartFailed:        "❌ Falha ao iniciar. Tente novamente.",
+		ResetDone:          "🧹 Conversa apagada. Começando do zero!",
+		ResetFailed:        "❌ Não foi possível redefinir a conversa. Tente novamente.",
+		SleepDone:          "💤 Vou dormir. Envie qualquer mensagem para me acordar.",
+		SleepFailed:        "❌ Não foi possível dormir. Tente novamente.",
+		AlreadyAsleep:      "💤 Já estou dormindo.",
+		AgentErrors:        "⚠️ %[1]s: %[2]d linha(s) de erro do agente às %[3]s",
+		LinesOmitted:       "… mais %[1]d linha(s) omitida(s)",
+		AgentOOMKilled:     "⚠️ %[1]s ficou sem memória (%[3]s) às %[2]s e foi reiniciado, perdendo o que não tinha salvo. Se isso se repetir, um plano maior oferece mais memória.",
+		AgentCrashLoop:     "⚠️ %[1]s continua falhando (%[2]d reinícios) e aguarda antes da próxima tentativa.",
+		StorageFull:        "❌ Fiquei sem armazenamento e não consigo iniciar. Peça ao meu dono para liberar espaço ou mudar de plano.",
+		BotPaused:          "⏸️ Este bot está pausado. Entre em contato com o dono.",
+		Unavailable:        "🚧 Este bot está temporariamente indisponível. Tente novamente mais tarde.",
+		QuotaExceeded:      "⏳ Esgotei meu tempo de execução por enquanto e não consigo iniciar. Tente novamente mais tarde.",
+		StaleMessages:      "⌛ Desculpe, suas mensagens chegaram tarde demais para serem atendidas com segurança, então eu as ignorei. Envie novamente o que ainda precisar.",
+		SlowDown:           "🐢 Você está enviando mensagens mais rápido do que consigo acompanhar, então ignorei algumas. Vá mais devagar e envie novamente o que eu perdi.",
+		ReplyCut:           "✂️ Esta resposta era longa demais para o chat e foi cortada.",
+		ReplyCutLink:       "✂️ Esta resposta era longa demais para o chat e foi cortada. Resposta completa (link válido por 7 dias): %[1]s",
+		ReplyTooLarge:      "❌ Minha resposta era grande demais para ser entregue. Peça uma resposta mais curta.",
+		StateQuotaWarning:  "💾 %[1]s está usando %[2]s dos seus %[3]s de armazenamento (%[4]d%%). Apagar estado antigo ou um plano maior evita que encha.",
+		StateQuotaExceeded: "⛔ %[1]s esgotou seus %[3]s de armazenamento (%[2]s) e não vai iniciar até que haja espaço livre ou ele mude para um plano maior.",
+		StateQuotaReadOnly: "⛔ %[1]s esgotou seus %[3]s de armazenamento (%[2]s). Continua funcionando, mas não pode salvar nada novo até que haja espaço livre ou ele mude para um plano maior.",
+		DigestTitle:        "📊 %[1]s, semana de %[2]s a %[3]s",
+		DigestActivity:     "💬 %[1]d mensagem(ns), %[2]d inicialização(ões)",
+		DigestUptime:       "✅ Disponibilidade %[1]s, em execução por %[2]s",
+		DigestCost:         "💰 Custo estimado: %[1]s",
+		DigestErrors:       "⚠️ %[1]d inicialização(ões) com falha, %[2]d incidente(s) do pod",
+		DigestNoErrors:     "👍 Nenhum erro",
+	},
+	"ja": {
+		StartingUp:         "⏳ 起動中です。少々お待ちください...",
+		RestoringState:     "📂 もうすぐです。保存した状態を復元しています...",
+		StartFailed:        "❌ 起動に失敗しました。もう一度お試しください。",
+		ResetDone:          "🧹 会話をクリアしました。新しく始めましょう！",
+		ResetFailed:        "❌ 会話をリセットできませんでした。もう一度お試しください。",
+		SleepDone:          "💤 スリープします。メッセージを送ると起動します。",
+		SleepFailed:        "❌ スリープできませんでした。もう一度お試しください。",
+		AlreadyAsleep:      "💤 すでにスリープ中です。",
+		AgentErrors:        "⚠️ %[1]s: %[3]s にエージェントのエラーが %[2]d 行",
+		LinesOmitted:       "… 他 %[1]d 行を省略",
+		AgentOOMKilled:     "⚠️ %[1]s は %[2]s にメモリ不足 (%[3]s) で再起動し、未保存の内容が失われました。繰り返す場合は、上位のプランでメモリを増やせます。",
+		AgentCrashLoop:     "⚠️ %[1]s がクラッシュを繰り返しています (再起動 %[2]d 回)。次の再試行を待っています。",
+		StorageFull:        "❌ ストレージが一杯のため起動できません。オーナーに空き容量の確保かプランの変更を依頼してください。",
+		BotPaused:          "⏸️ このボットは一時停止中です。オーナーにお問い合わせください。",
+		Unavailable:        "🚧 このボットは一時的に利用できません。しばらくしてからもう一度お試しください。",
+		QuotaExceeded:      "⏳ 現在、実行時間の上限に達したため起動できません。しばらくしてからもう一度お試しください。",
+		StaleMessages:      "⌛ 申し訳ありません。メッセージの到着が遅すぎて安全に処理できないため、スキップしました。必要な内容はもう一度送信してください。",
+		SlowDown:           "🐢 メッセージの送信が速すぎて処理が追いつかないため、一部をスキップしました。ゆっくり送信し、届かなかった内容はもう一度送ってください。",
+		ReplyCut:           "✂️ この返信はチャットには長すぎるため、途中で省略しました。",
+		ReplyCutLink:       "✂️ この返信はチャットには長すぎるため、途中で省略しました。全文 (リンクは7日間有効): %[1]s",
+		ReplyTooLarge:      "❌ 返信が大きすぎて送信できませんでした。短い回答を依頼してください。",
+		StateQuotaWarning:  "💾 %[1]s はストレージ %[3]s のうち %[2]s を使用しています (%[4]d%%)。古い状態を削除するか、上位のプランで一杯になるのを防げます。",
+		StateQuotaExceeded: "⛔ %[1]s はストレージ %[3]s を使い切りました (%[2]s)。空き容量を確保するか上位のプランに変更するまで起動しません。",
+		StateQuotaReadOnly: "⛔ %[1]s はストレージ %[3]s を使い切りました (%[2]s)。動作は続きますが、空き容量を確保するか上位のプランに変更するまで新しい内容を保存できません。",
+		DigestTitle:        "📊 %[1]s の週次レポート (%[2]s〜%[3]s)",
+		DigestActivity:     "💬 メッセージ %[1]d 件、起動 %[2]d 回",
+		DigestUptime:       "✅ 稼働率 %[1]s、稼働時間 %[2]s",
+		DigestCost:         "💰 推定コスト: %[1]s",
+		DigestErrors:       "⚠️ 起動失敗 %[1]d 回、Pod のインシデント %[2]d 件",
+		DigestNoErrors:     "👍 エラーなし",
+	},
+	"zh": {
+		StartingUp:         "⏳ 正在启动，请稍候...",
+		RestoringState:     "📂 即将就绪，正在恢复保存的状态...",
+		StartFailed:        "❌ 启动失败，请重试。",
+		ResetDone:          "🧹 对话已清除，重新开始！",
+		ResetFailed:        "❌ 无法重置对话，请重试。",
+		SleepDone:          "💤 进入休眠。发送任意消息即可唤醒。",
+		SleepFailed:        "❌ 无法进入休眠，请重试。",
+		AlreadyAsleep:      "💤 已在休眠中。",
+		AgentErrors:        "⚠️ %[1]s：%[3]s 出现 %[2]d 行代理错误",
+		LinesOmitted:       "… 另有 %[1]d 行已省略",
+		AgentOOMKilled:     "⚠️ %[1]s 于 %[2]s 内存不足（%[3]s）并已重启，未保存的内容已丢失。如果反复出现，升级到更高的套餐可获得更多内存。",
+		AgentCrashLoop:     "⚠️ %[1]s 反复崩溃（已重启 %[2]d 次），正在等待下一次重试。",
+		StorageFull:        "❌ 存储空间已满，无法启动。请联系机器人的所有者释放空间或升级套餐。",
+		BotPaused:          "⏸️ 此机器人已暂停。请联系其所有者。",
+		Unavailable:        "🚧 此机器人暂时无法使用，请稍后再试。",
+		QuotaExceeded:      "⏳ 我暂时用完了运行额度，无法启动。请稍后再试。",
+		StaleMessages:      "⌛ 抱歉，你的消息送达太晚，无法安全处理，已被跳过。如仍有需要，请重新发送。",
+		SlowDown:           "🐢 你发送消息的速度太快，我跟不上，所以跳过了一些。请放慢速度，并重新发送我漏掉的内容。",
+		ReplyCut:           "✂️ 此回复过长，无法在聊天中完整显示，已被截断。",
+		ReplyCutLink:       "✂️ 此回复过长，无法在聊天中完整显示，已被截断。完整回复（链接 7 天内有效）：%[1]s",
+		ReplyTooLarge:      "❌ 我的回复过大，无法发送。请要求更简短的回答。",
+		StateQuotaWarning:  "💾 %[1]s 已使用 %[3]s 存储中的 %[2]s（%[4]d%%）。清理旧状态或升级套餐可避免存储写满。",
+		StateQuotaExceeded: "⛔ %[1]s 已用完 %[3]s 存储（%[2]s），在释放空间或升级套餐之前将无法启动。",
+		StateQuotaReadOnly: "⛔ %[1]s 已用完 %[3]s 存储（%[2]s）。它仍在运行，但在释放空间或升级套餐之前无法保存新内容。",
+		DigestTitle:        "📊 %[1]s 周报（%[2]s 至 %[3]s）",
+		DigestActivity:     "💬 %[1]d 条消息，启动 %[2]d 次",
+		DigestUptime:       "✅ 可用率 %[1]s，运行时长 %[2]s",
+		DigestCost:         "💰 预估费用：%[1]s",
+		DigestErrors:       "⚠️ 启动失败 %[1]d 次，Pod 事件 %[2]d 起",
+		DigestNoErrors:     "👍 没有错误",
+	},
+}
+
+// base reduces a locale tag to the language the catalog is keyed by, e.g.
+// "pt-BR" and "pt_br" to "pt".
+func base(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	return lang
+}
+
+// Supported reports whether the catalog translates locale. Empty is
+// supported and means DefaultLocale.
+func Supported(locale string) bool {
+	if locale == "" {
+		return true
+	}
+	_, ok := catalog[base(locale)]
+	return ok
+}
+
+// Locales lists the catalog's languages.
+func Locales() []string {
+	var out []string
+	for l := range catalog {
+		out = append(out, l)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// T renders message key in locale, falling back to DefaultLocale.
+func T(locale string, key Key, args ...any) string {
+	msg, ok := catalog[base(locale)][key]
+	if !ok {
+		msg = catalog[DefaultLocale][key]
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// ValidateTimezone rejects names that aren't IANA time zones. Empty means UTC.
+func ValidateTimezone(tz string) error {
+	if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+		return fmt.Errorf("unknown timezone %q (want an IANA name such as Europe/Berlin)", tz)
+	}
+	return nil
+}
+
+// Location returns the time zone tz, or UTC if it is empty or unknown.
+func Location(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Clock renders t as a short local time in tz, e.g. "14:05 CET".
+func Clock(t time.Time, tz string) string {
+	return t.In(Location(tz)).Format("15:04 MST")
+}
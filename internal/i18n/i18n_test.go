@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCatalogComplete: every locale translates every message with the same
+// arguments as English
+func TestCatalogComplete(t *testing.T) {
+	args := map[Key][]any{
+		AgentErrors:        {"alice", 3, "14:05 CET"},
+		LinesOmitted:       {2},
+		ReplyCutLink:       {"https://example.com/full"},
+		AgentOOMKilled:     {"alice", "14:05 CET", "512Mi"},
+		AgentCrashLoop:     {"alice", 7},
+		StateQuotaWarning:  {"alice", "4.1 GiB", "5.0 GiB", 82},
+		StateQuotaExceeded: {"alice", "5.2 GiB", "5.0 GiB"},
+		StateQuotaReadOnly: {"alice", "5.2 GiB", "5.0 GiB"},
+		DigestTitle:        {"alice", "2026-10-05", "2026-10-11"},
+		DigestActivity:     {412, 9},
+		DigestUptime:       {"98.5%", "31h20m0s"},
+		DigestCost:         {"$4.20"},
+		DigestErrors:       {2, 1},
+	}
+	for key, en := range catalog[DefaultLocale] {
+		for _, locale := range Locales() {
+			msg, ok := catalog[locale][key]
+			if !assert.True(t, ok, "%s missing %s", locale, key) {
+				continue
+			}
+			out := fmt.Sprintf(msg, args[key]...)
+			assert.NotContains(t, out, "%!", "%s/%s: %q", locale, key, out)
+			for _, a := range args[key] {
+				assert.Contains(t, out, fmt.Sprint(a), "%s/%s", locale, key)
+			}
+		}
+		assert.NotEmpty(t, en)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "💤 Already asleep.", T("", AlreadyAsleep))
+	assert.Equal(t, "💤 Schlafe bereits.", T("de", AlreadyAsleep))
+	assert.Equal(t, "💤 Já estou dormindo.", T("pt-BR", AlreadyAsleep))
+	assert.Equal(t, "💤 Already asleep.", T("xx", AlreadyAsleep), "unknown locale falls back to English")
+	assert.Equal(t, "… 2 more line(s) omitted", T("en", LinesOmitted, 2))
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported(""))
+	assert.True(t, Supported("ja"))
+	assert.True(t, Supported("zh_CN"))
+	assert.False(t, Supported("klingon"))
+}
+
+func TestTimezone(t *testing.T) {
+	assert.NoError(t, ValidateTimezone(""))
+	assert.NoError(t, ValidateTimezone("Asia/Tokyo"))
+	assert.Error(t, ValidateTimezone("Mars/Olympus"))
+	assert.Error(t, ValidateTimezone("Local"))
+
+	at := time.Date(2026, 1, 15, 13, 5, 0, 0, time.UTC)
+	assert.Equal(t, "22:05 JST", Clock(at, "Asia/Tokyo"))
+	assert.Equal(t, "13:05 UTC", Clock(at, ""))
+	assert.True(t, strings.HasPrefix(Clock(at, "bogus"), "13:05"))
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http/httptest"
@@ -26,8 +27,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
-	"net/http"
 	"encoding/json"
+	"net/http"
 )
 
 const tableName = "tenant-registry-test"
@@ -61,10 +62,10 @@ func setupDynamoDB(ctx context.Context, t *testing.T) (*dynamodb.Client, func())
 
 	// Create table
 	_, err = db.CreateTable(ctx, &dynamodb.CreateTableInput{
-		TableName:   aws.String(tableName),
-		KeySchema:   []dynamotypes.KeySchemaElement{{AttributeName: aws.String("tenant_id"), KeyType: dynamotypes.KeyTypeHash}},
+		TableName:            aws.String(tableName),
+		KeySchema:            []dynamotypes.KeySchemaElement{{AttributeName: aws.String("tenant_id"), KeyType: dynamotypes.KeyTypeHash}},
 		AttributeDefinitions: []dynamotypes.AttributeDefinition{{AttributeName: aws.String("tenant_id"), AttributeType: dynamotypes.ScalarAttributeTypeS}},
-		BillingMode: dynamotypes.BillingModePayPerRequest,
+		BillingMode:          dynamotypes.BillingModePayPerRequest,
 	})
 	require.NoError(t, err)
 
@@ -204,3 +205,190 @@ func TestIntegration_WakeIdleWakeCycle(t *testing.T) {
 	assert.Equal(t, registry.StatusRunning, rec2.Status)
 	assert.Equal(t, "10.1.0.2", rec2.PodIP)
 }
+
+// TestIntegration_Bootstrap creates a fresh table, verifies access to it, and
+// checks that a second run leaves the existing table alone
+func TestIntegration_Bootstrap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	db, cleanup := setupDynamoDB(ctx, t)
+	defer cleanup()
+
+	created, err := registry.Bootstrap(ctx, db, "tenant-registry-bootstrap")
+	require.NoError(t, err)
+	assert.True(t, created)
+	require.NoError(t, registry.VerifyAccess(ctx, db, "tenant-registry-bootstrap"))
+
+	created, err = registry.Bootstrap(ctx, db, "tenant-registry-bootstrap")
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	// The probe item is removed and never shows up as a tenant
+	all, err := registry.New(db, "tenant-registry-bootstrap").ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestIntegration_WakeHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	db, cleanup := setupDynamoDB(ctx, t)
+	defer cleanup()
+	reg := registry.New(db, tableName)
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle}))
+
+	wakes, err := reg.ListWakes(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, wakes)
+
+	start := time.Now().UTC().Truncate(time.Second)
+	for i := range 5 {
+		require.NoError(t, reg.RecordWake(ctx, "alice", registry.WakeAttempt{
+			StartedAt:  start.Add(time.Duration(i) * time.Minute),
+			DurationMs: int64(i),
+			Start:      "warm",
+			Outcome:    registry.WakeOK,
+		}, 3))
+	}
+
+	// Only the newest 3 are kept, newest first, and they are not tenants
+	wakes, err = reg.ListWakes(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, wakes, 3)
+	assert.Equal(t, []int64{4, 3, 2}, []int64{wakes[0].DurationMs, wakes[1].DurationMs, wakes[2].DurationMs})
+	all, err := reg.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	require.NoError(t, reg.DeleteTenant(ctx, "alice"))
+	wakes, err = reg.ListWakes(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, wakes)
+}
+
+// TestIntegration_StatusIndexMigration lists tenants from a table created
+// without the status index (scan fallback), adds the index, and lists again
+// through it
+func TestIntegration_StatusIndexMigration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	db, cleanup := setupDynamoDB(ctx, t)
+	defer cleanup()
+	reg := registry.New(db, tableName)
+	old := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "stale", Status: registry.StatusRunning, LastActiveAt: old}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "busy", Status: registry.StatusRunning, LastActiveAt: time.Now().UTC()}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "asleep", Status: registry.StatusIdle, LastActiveAt: old}))
+
+	check := func() {
+		t.Helper()
+		idle, err := reg.ListIdleTenants(ctx, 30*time.Minute)
+		require.NoError(t, err)
+		require.Len(t, idle, 1)
+		assert.Equal(t, "stale", idle[0].TenantID)
+		running, err := reg.ListByStatus(ctx, registry.StatusRunning)
+		require.NoError(t, err)
+		assert.Len(t, running, 2)
+	}
+	check()
+
+	added, err := registry.MigrateStatusIndex(ctx, db, tableName)
+	require.NoError(t, err)
+	assert.True(t, added)
+	check()
+
+	added, err = registry.MigrateStatusIndex(ctx, db, tableName)
+	require.NoError(t, err)
+	assert.False(t, added)
+}
+
+func TestIntegration_DeleteConfirmToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	rdb, cleanRedis := setupRedis(ctx, t)
+	defer cleanRedis()
+
+	reg := registry.NewMock()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusIdle}))
+	h := api.New(reg, nil, lock.New(rdb), rdb, nil, api.Config{Namespace: "tenants", RequireConfirm: true})
+	srv := httptest.NewServer(h.Router())
+	defer srv.Close()
+
+	del := func(query string) *http.Response {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/tenants/alice"+query, nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := del("?dry_run=true")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var plan struct {
+		ConfirmToken string `json:"confirm_token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	resp.Body.Close()
+	require.NotEmpty(t, plan.ConfirmToken)
+
+	assert.Equal(t, http.StatusNoContent, del("?confirm_token="+plan.ConfirmToken).StatusCode)
+	tenant, _ := reg.GetTenant(ctx, "alice")
+	assert.Nil(t, tenant)
+
+	// Tokens are single-use
+	assert.Equal(t, http.StatusPreconditionRequired, del("?confirm_token="+plan.ConfirmToken).StatusCode)
+}
+
+// TestIntegration_ChatAnalytics counts distinct chats from activity reports
+// in Redis and reads them back from the DynamoDB usage items
+func TestIntegration_ChatAnalytics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	db, cleanDB := setupDynamoDB(ctx, t)
+	defer cleanDB()
+	rdb, cleanRedis := setupRedis(ctx, t)
+	defer cleanRedis()
+
+	reg := registry.New(db, tableName)
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Status: registry.StatusRunning}))
+	h := api.New(reg, nil, lock.New(rdb), rdb, nil, api.Config{Namespace: "tenants"})
+	srv := httptest.NewServer(h.Router())
+	defer srv.Close()
+
+	for _, chat := range []string{"1", "2", "1", "-100"} {
+		body, _ := json.Marshal(map[string]any{"chars_in": 5, "chat_id": chat})
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/tenants/alice/activity", bytes.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	resp, err := http.Get(srv.URL + "/tenants/alice/usage")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var usage struct {
+		Days   []registry.UsageDay   `json:"days"`
+		Months []registry.UsageMonth `json:"months"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&usage))
+	require.Len(t, usage.Days, 1)
+	assert.Equal(t, int64(4), usage.Days[0].Messages)
+	assert.Equal(t, int64(3), usage.Days[0].Chats)
+	require.Len(t, usage.Months, 1)
+	assert.Equal(t, registry.UsageMonth{Month: time.Now().UTC().Format(registry.UsageMonthFormat), Chats: 3, Messages: 4}, usage.Months[0])
+}
@@ -3,15 +3,21 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/shawn/agentic-tenancy/internal/contract"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 const (
@@ -25,6 +31,61 @@ type Config struct {
 	KataRuntimeClass string
 	ZeroClawImage    string
 	S3Bucket         string
+	Grace            GracePolicy
+	// TierDNS holds DNS settings for tenant pods on the given tier.
+	TierDNS map[string]DNSSettings
+	// Environment names the control-plane environment this client serves.
+	// PVs are cluster-scoped, so their names and CSI volume handles include it
+	// to keep same-named tenants in different environments apart.
+	Environment string
+	// WarmStagingVolume makes warm pods mount a shared read-only S3 volume
+	// (see EnsureWarmStagingVolume) so the CSI driver is already running on
+	// their node when a tenant pod is pinned there. Off by default.
+	WarmStagingVolume bool
+	// WarmPolicy limits which tenants may claim warm pods; see GetWarmPod.
+	WarmPolicy WarmPoolPolicy
+	// Startup configures the tenant container's startupProbe.
+	Startup StartupProbePolicy
+	// StateSync adds containers that restore and flush tenant state; see
+	// StateSyncPolicy. Off by default.
+	StateSync StateSyncPolicy
+	// REST is the API server connection ExecProxy dials. Nil disables exec
+	// sessions.
+	REST *rest.Config
+}
+
+// TenantPodOptions holds per-tenant settings for CreateTenantPod.
+type TenantPodOptions struct {
+	// NodeName pins the pod to a node (used when assigning from a warm pool
+	// pod to skip Karpenter provisioning). Empty means schedule normally.
+	NodeName string
+	// Tier selects the tenant's termination grace period and startup budget.
+	Tier string
+	// Image is the ZeroClaw image or bare tag to run. Empty means
+	// Config.ZeroClawImage.
+	Image string
+	// DNS overrides the tier's DNS settings for this tenant. Nil means use the tier's.
+	DNS *DNSSettings
+	// PodName overrides the default zeroclaw-<tenantID> name, so a
+	// replacement pod can run alongside the current one.
+	PodName string
+	// StateReadOnly mounts /s3-state read-only, for a tenant over its
+	// storage quota. The agent is told via STATE_READ_ONLY=true.
+	StateReadOnly bool
+	// KV gives the agent access to the tenant's key-value store on the
+	// orchestrator. Nil leaves AGENT_KV_URL and AGENT_KV_TOKEN unset.
+	KV *KVAccess
+	// ServiceAccount runs the pod as the tenant's own ServiceAccount (see
+	// EnsureTenantServiceAccount). Empty means the shared zeroclaw-tenant.
+	ServiceAccount string
+}
+
+// KVAccess is where a tenant pod reaches its key-value store and the token
+// it authenticates with. The token is kept in the tenant's Secret alongside
+// the bot token.
+type KVAccess struct {
+	URL   string
+	Token string
 }
 
 // Client wraps kubernetes.Interface with tenant-specific helpers
@@ -37,14 +98,44 @@ func New(cs kubernetes.Interface, cfg Config) *Client {
 	if cfg.KataRuntimeClass == "" {
 		cfg.KataRuntimeClass = defaultKataRuntime
 	}
+	cfg.Grace = cfg.Grace.withDefaults()
 	return &Client{cs: cs, cfg: cfg}
 }
 
-// CreateTenantPod creates the ZeroClaw pod for a tenant.
-// If nodeName is non-empty, the pod is pinned to that node (used when
-// assigning from a warm pool pod to skip Karpenter provisioning).
-func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcName, botToken, nodeName string) (*corev1.Pod, error) {
-	podName := podName(tenantID)
+// GracePeriod returns the termination grace period in seconds for op on a
+// tenant of the given tier.
+func (c *Client) GracePeriod(op TerminationOp, tier string) int64 {
+	return c.cfg.Grace.For(op, tier)
+}
+
+// CreateTenantPod creates the ZeroClaw pod for a tenant. The bot token (and
+// KV token, if any) is written to the tenant's Secret (see
+// BotTokenSecretName) and read from there, so it never appears in the pod
+// spec.
+func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcName, botToken string, opts TenantPodOptions) (*corev1.Pod, error) {
+	podName := contract.PodName(tenantID)
+	if opts.PodName != "" {
+		podName = opts.PodName
+	}
+	grace := c.cfg.Grace.PodGrace(opts.Tier)
+	dns := c.cfg.TierDNS[opts.Tier]
+	if opts.DNS != nil {
+		dns = *opts.DNS
+	}
+	if err := dns.Validate(); err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", tenantID, err)
+	}
+	kvToken := ""
+	if opts.KV != nil {
+		kvToken = opts.KV.Token
+	}
+	if err := c.ensureBotTokenSecret(ctx, tenantID, namespace, botToken, kvToken); err != nil {
+		return nil, fmt.Errorf("tenant %s: bot token secret: %w", tenantID, err)
+	}
+	serviceAccount := "zeroclaw-tenant"
+	if opts.ServiceAccount != "" {
+		serviceAccount = opts.ServiceAccount
+	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
@@ -57,8 +148,8 @@ func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcNa
 		Spec: corev1.PodSpec{
 			RuntimeClassName:   strPtr(c.cfg.KataRuntimeClass),
 			PriorityClassName:  defaultPriorityNorm,
-			ServiceAccountName: "zeroclaw-tenant",
-			NodeName:           nodeName, // pin to warm node if provided
+			ServiceAccountName: serviceAccount,
+			NodeName:           opts.NodeName, // pin to warm node if provided
 			NodeSelector: map[string]string{
 				"katacontainers.io/kata-runtime": "true",
 			},
@@ -73,10 +164,18 @@ func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcNa
 			Containers: []corev1.Container{
 				{
 					Name:  "zeroclaw",
-					Image: c.cfg.ZeroClawImage,
+					Image: c.imageRef(opts.Image),
 					Env: []corev1.EnvVar{
 						{Name: "TENANT_ID", Value: tenantID},
-						{Name: "TELEGRAM_BOT_TOKEN", Value: botToken},
+						{Name: "TELEGRAM_BOT_TOKEN", ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: contract.BotTokenSecretName(tenantID)},
+								Key:                  botTokenSecretKey,
+							},
+						}},
+						// Shutdown contract: on SIGTERM the agent has this many
+						// seconds to flush state to /s3-state before SIGKILL.
+						{Name: "SHUTDOWN_GRACE_PERIOD_S", Value: strconv.FormatInt(grace, 10)},
 					},
 					Resources: corev1.ResourceRequirements{
 						Requests: corev1.ResourceList{
@@ -92,6 +191,7 @@ func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcNa
 						{Name: "local-state", MountPath: "/zeroclaw-data"},
 						{Name: "s3-state", MountPath: "/s3-state"},
 					},
+					StartupProbe: c.cfg.Startup.Probe(opts.Tier),
 				},
 			},
 			Volumes: []corev1.Volume{
@@ -108,9 +208,31 @@ func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcNa
 					},
 				},
 			},
-			TerminationGracePeriodSeconds: int64Ptr(30),
+			TerminationGracePeriodSeconds: int64Ptr(grace),
 		},
 	}
+	dns.apply(&pod.Spec)
+	if opts.StateReadOnly {
+		agent := &pod.Spec.Containers[0]
+		for i := range agent.VolumeMounts {
+			if agent.VolumeMounts[i].Name == "s3-state" {
+				agent.VolumeMounts[i].ReadOnly = true
+			}
+		}
+		agent.Env = append(agent.Env, corev1.EnvVar{Name: "STATE_READ_ONLY", Value: "true"})
+	}
+	if opts.KV != nil {
+		agent := &pod.Spec.Containers[0]
+		agent.Env = append(agent.Env,
+			corev1.EnvVar{Name: "AGENT_KV_URL", Value: opts.KV.URL},
+			corev1.EnvVar{Name: "AGENT_KV_TOKEN", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: contract.BotTokenSecretName(tenantID)},
+					Key:                  kvTokenSecretKey,
+				},
+			}})
+	}
+	c.cfg.StateSync.apply(&pod.Spec, opts.StateReadOnly)
 
 	created, err := c.cs.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if errors.IsAlreadyExists(err) {
@@ -121,19 +243,32 @@ func (c *Client) CreateTenantPod(ctx context.Context, tenantID, namespace, pvcNa
 
 // WaitPodReady polls until the pod is Running and has a PodIP, returns the IP
 func (c *Client) WaitPodReady(ctx context.Context, tenantID, namespace string, timeout time.Duration) (string, error) {
-	name := podName(tenantID)
+	return c.WaitNamedPodReady(ctx, contract.PodName(tenantID), namespace, timeout)
+}
+
+// WaitNamedPodReady is WaitPodReady for a pod with an explicit name. A pod
+// whose state restore failed is returned at once as a *StateRestoreError;
+// the kubelet keeps retrying the restore, so a later wait may succeed.
+func (c *Client) WaitNamedPodReady(ctx context.Context, name, namespace string, timeout time.Duration) (string, error) {
 	var podIP string
+	var restoreErr *StateRestoreError
 	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
 		pod, err := c.cs.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
 			return false, nil
 		}
+		if restoreErr = stateRestoreFailure(pod); restoreErr != nil {
+			return false, restoreErr
+		}
 		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
 			podIP = pod.Status.PodIP
 			return true, nil
 		}
 		return false, nil
 	})
+	if restoreErr != nil {
+		return "", restoreErr
+	}
 	if err != nil {
 		return "", fmt.Errorf("pod %s not ready after %s: %w", name, timeout, err)
 	}
@@ -151,70 +286,207 @@ func (c *Client) DeletePod(ctx context.Context, podName, namespace string, grace
 	return err
 }
 
-// CreatePVC creates an S3 CSI PVC for a tenant (idempotent)
-func (c *Client) CreatePVC(ctx context.Context, tenantID, namespace string) error {
-	pvcName := PVCName(tenantID)
-	pvName := pvName(tenantID)
+// EnsureTenantVolume makes sure a tenant's S3 CSI PV and PVC exist and can
+// bind, recreating whatever is missing from the tenant's S3 prefix. It
+// returns a description of each repair made (empty when nothing was wrong).
+//
+// Deleting a PVC out-of-band leaves its Retain PV "Released" with a claimRef
+// to the old PVC UID, so a recreated PVC would stay Pending forever; the
+// stale claimRef is cleared so the new claim can bind.
+func (c *Client) EnsureTenantVolume(ctx context.Context, tenantID, namespace, s3Prefix string) ([]string, error) {
+	pvcName := contract.PVCName(tenantID)
+	pvName := c.pvName(tenantID)
 	storageClass := "s3-tenant-state"
+	subPath := strings.Trim(s3Prefix, "/")
+	if subPath == "" {
+		subPath = "tenants/" + tenantID
+		if c.cfg.Environment != "" {
+			subPath = c.cfg.Environment + "/" + subPath
+		}
+	}
+	var repaired []string
 
-	// Create PV first
-	pv := &corev1.PersistentVolume{
-		ObjectMeta: metav1.ObjectMeta{Name: pvName},
-		Spec: corev1.PersistentVolumeSpec{
-			Capacity: corev1.ResourceList{
-				corev1.ResourceStorage: resource.MustParse("1Gi"),
+	existing, err := c.cs.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvName},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+				AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				StorageClassName:              storageClass,
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       "s3.csi.aws.com",
+						VolumeHandle: c.volumeHandle(tenantID),
+						VolumeAttributes: map[string]string{
+							"bucketName": c.cfg.S3Bucket,
+							"subPath":    subPath,
+						},
+					},
+				},
+			},
+		}
+		_, err = c.cs.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return repaired, fmt.Errorf("create PV: %w", err)
+		}
+		repaired = append(repaired, "created PV")
+	case err != nil:
+		return repaired, fmt.Errorf("get PV: %w", err)
+	case existing.Status.Phase == corev1.VolumeReleased && existing.Spec.ClaimRef != nil:
+		existing.Spec.ClaimRef = nil
+		if _, err := c.cs.CoreV1().PersistentVolumes().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return repaired, fmt.Errorf("release PV claim: %w", err)
+		}
+		repaired = append(repaired, "cleared stale PV claim")
+	}
+
+	_, err = c.cs.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pvcName,
+				Namespace: namespace,
 			},
-			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
-			StorageClassName:              storageClass,
-			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
-			PersistentVolumeSource: corev1.PersistentVolumeSource{
-				CSI: &corev1.CSIPersistentVolumeSource{
-					Driver:       "s3.csi.aws.com",
-					VolumeHandle: "tenant-" + tenantID,
-					VolumeAttributes: map[string]string{
-						"bucketName": c.cfg.S3Bucket,
-						"subPath":    "tenants/" + tenantID,
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				StorageClassName: &storageClass,
+				VolumeName:       pvName,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
 					},
 				},
 			},
-		},
+		}
+		_, err = c.cs.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return repaired, fmt.Errorf("create PVC: %w", err)
+		}
+		repaired = append(repaired, "created PVC")
+	case err != nil:
+		return repaired, fmt.Errorf("get PVC: %w", err)
+	}
+	return repaired, nil
+}
+
+// TenantVolumeRepairs reports the repairs EnsureTenantVolume would make,
+// without making them.
+func (c *Client) TenantVolumeRepairs(ctx context.Context, tenantID, namespace string) ([]string, error) {
+	var repairs []string
+	pv, err := c.cs.CoreV1().PersistentVolumes().Get(ctx, c.pvName(tenantID), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		repairs = append(repairs, "create PV")
+	case err != nil:
+		return repairs, fmt.Errorf("get PV: %w", err)
+	case pv.Status.Phase == corev1.VolumeReleased && pv.Spec.ClaimRef != nil:
+		repairs = append(repairs, "clear stale PV claim")
 	}
-	_, err := c.cs.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return fmt.Errorf("create PV: %w", err)
+	_, err = c.cs.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, contract.PVCName(tenantID), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		repairs = append(repairs, "create PVC")
+	case err != nil:
+		return repairs, fmt.Errorf("get PVC: %w", err)
 	}
+	return repairs, nil
+}
+
+// Keys holding the bot token and KV token in a tenant's Secret
+const (
+	botTokenSecretKey = "telegram-bot-token"
+	kvTokenSecretKey  = "kv-token"
+)
 
-	// Create PVC
-	pvc := &corev1.PersistentVolumeClaim{
+// ensureBotTokenSecret creates or updates the Secret a tenant pod reads its
+// bot token, and KV token unless empty, from
+func (c *Client) ensureBotTokenSecret(ctx context.Context, tenantID, namespace, token, kvToken string) error {
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      pvcName,
+			Name:      contract.BotTokenSecretName(tenantID),
 			Namespace: namespace,
+			Labels: map[string]string{
+				"app":    "zeroclaw",
+				"tenant": tenantID,
+			},
 		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
-			StorageClassName: &storageClass,
-			VolumeName:       pvName,
-			Resources: corev1.VolumeResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse("1Gi"),
-				},
+		Data: map[string][]byte{botTokenSecretKey: []byte(token)},
+	}
+	if kvToken != "" {
+		secret.Data[kvTokenSecretKey] = []byte(kvToken)
+	}
+	_, err := c.cs.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		_, err = c.cs.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// DeleteBotTokenSecret deletes a tenant's bot token Secret
+func (c *Client) DeleteBotTokenSecret(ctx context.Context, tenantID, namespace string) error {
+	err := c.cs.CoreV1().Secrets(namespace).Delete(ctx, contract.BotTokenSecretName(tenantID), metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// EnsureTenantServiceAccount creates or updates the tenant's own
+// ServiceAccount, annotated for IRSA to assume roleARN
+func (c *Client) EnsureTenantServiceAccount(ctx context.Context, tenantID, namespace, roleARN string) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      contract.ServiceAccountName(tenantID),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":    "zeroclaw",
+				"tenant": tenantID,
 			},
+			Annotations: map[string]string{irsaRoleAnnotation: roleARN},
 		},
 	}
-	_, err = c.cs.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return fmt.Errorf("create PVC: %w", err)
+	_, err := c.cs.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		_, err = c.cs.CoreV1().ServiceAccounts(namespace).Update(ctx, sa, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// TenantServiceAccountExists reports whether the tenant's own ServiceAccount
+// exists with a role annotation
+func (c *Client) TenantServiceAccountExists(ctx context.Context, tenantID, namespace string) (bool, error) {
+	sa, err := c.cs.CoreV1().ServiceAccounts(namespace).Get(ctx, contract.ServiceAccountName(tenantID), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sa.Annotations[irsaRoleAnnotation] != "", nil
+}
+
+// DeleteTenantServiceAccount deletes a tenant's own ServiceAccount
+func (c *Client) DeleteTenantServiceAccount(ctx context.Context, tenantID, namespace string) error {
+	err := c.cs.CoreV1().ServiceAccounts(namespace).Delete(ctx, contract.ServiceAccountName(tenantID), metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
 	}
 	return nil
 }
 
 // DeletePVC deletes a tenant's PVC and PV
 func (c *Client) DeletePVC(ctx context.Context, tenantID, namespace string) error {
-	err := c.cs.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, PVCName(tenantID), metav1.DeleteOptions{})
+	err := c.cs.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, contract.PVCName(tenantID), metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
-	err = c.cs.CoreV1().PersistentVolumes().Delete(ctx, pvName(tenantID), metav1.DeleteOptions{})
+	err = c.cs.CoreV1().PersistentVolumes().Delete(ctx, c.pvName(tenantID), metav1.DeleteOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -267,6 +539,7 @@ func (c *Client) EnsureWarmPoolDeployment(ctx context.Context, namespace string,
 		},
 		TerminationGracePeriodSeconds: int64Ptr(10),
 	}
+	c.applyWarmStaging(&podSpec)
 
 	deploy := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -298,13 +571,19 @@ func (c *Client) EnsureWarmPoolDeployment(ctx context.Context, namespace string,
 		return err
 	}
 
-	// Update replicas and image if changed
+	// Update replicas, image and staging volume if changed
 	existing.Spec.Replicas = &replicas
 	existing.Spec.Template.Spec.Containers[0].Image = c.cfg.ZeroClawImage
+	c.applyWarmStaging(&existing.Spec.Template.Spec)
 	_, err = c.cs.AppsV1().Deployments(namespace).Update(ctx, existing, metav1.UpdateOptions{})
 	return err
 }
 
+// WarmStagingEnabled reports whether warm pods mount the staging volume
+func (c *Client) WarmStagingEnabled() bool {
+	return c.cfg.WarmStagingVolume
+}
+
 // ScaleWarmPool sets the warm-pool Deployment replica count.
 func (c *Client) ScaleWarmPool(ctx context.Context, namespace string, replicas int32) error {
 	existing, err := c.cs.AppsV1().Deployments(namespace).Get(ctx, "warm-pool", metav1.GetOptions{})
@@ -316,23 +595,25 @@ func (c *Client) ScaleWarmPool(ctx context.Context, namespace string, replicas i
 	return err
 }
 
-// GetWarmPod finds a running warm pod and atomically detaches it from the
-// Deployment by removing the "warm=true" label (so the Deployment no longer
-// manages it). Returns nil if no warm pod is available.
-func (c *Client) GetWarmPod(ctx context.Context, namespace string) (*corev1.Pod, error) {
-	list, err := c.cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: "app=warm-pool,warm=true",
-	})
+// GetWarmPod finds a running warm pod in namespace, the warm pool's, and
+// atomically detaches it from the Deployment by removing the "warm=true"
+// label (so the Deployment no longer manages it). Only the pod's node is
+// reused, so the tenant's pod may be in another namespace (claim.Namespace).
+// Returns nil if no warm pod is available, or ErrWarmCooldown /
+// ErrWarmReserved if Config.WarmPolicy denies the claim.
+func (c *Client) GetWarmPod(ctx context.Context, namespace string, claim WarmClaim) (*corev1.Pod, error) {
+	now := time.Now()
+	if c.inCooldown(ctx, claim, namespace, now) {
+		return nil, ErrWarmCooldown
+	}
+	ready, err := c.ReadyWarmPods(ctx, namespace)
 	if err != nil {
 		return nil, err
 	}
-	for i := range list.Items {
-		p := &list.Items[i]
-		if p.Status.Phase != corev1.PodRunning ||
-			p.Status.PodIP == "" ||
-			p.DeletionTimestamp != nil {
-			continue
-		}
+	if len(ready) > 0 && len(ready) <= c.cfg.WarmPolicy.reservedForOthers(claim.Tier) {
+		return nil, ErrWarmReserved
+	}
+	for _, p := range ready {
 		// Detach from Deployment by changing warm=true → warm=consuming
 		// The Deployment selector requires warm=true, so this pod is now orphaned.
 		pCopy := p.DeepCopy()
@@ -342,11 +623,54 @@ func (c *Client) GetWarmPod(ctx context.Context, namespace string) (*corev1.Pod,
 			// Another orchestrator replica claimed this pod first — try the next one
 			continue
 		}
+		// Best effort: the pod is ours either way, a lost stamp only skips
+		// the tenant's next cooldown
+		_ = c.recordClaim(ctx, claim, namespace, now)
 		return updated, nil
 	}
 	return nil, nil
 }
 
+// ReadyWarmPods returns the unclaimed warm pods that are running with an IP
+func (c *Client) ReadyWarmPods(ctx context.Context, namespace string) ([]*corev1.Pod, error) {
+	list, err := c.cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=warm-pool,warm=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ready []*corev1.Pod
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.Status.Phase != corev1.PodRunning ||
+			p.Status.PodIP == "" ||
+			p.DeletionTimestamp != nil {
+			continue
+		}
+		ready = append(ready, p)
+	}
+	return ready, nil
+}
+
+// RecycleWarmPod deletes an unclaimed warm pod so the Deployment replaces it.
+// The delete is conditional on the pod being unchanged, so a pod claimed
+// since it was listed is left to its tenant.
+func (c *Client) RecycleWarmPod(ctx context.Context, pod *corev1.Pod) error {
+	rv := pod.ResourceVersion
+	return c.cs.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &rv},
+	})
+}
+
+// CordonNode marks a node unschedulable, like kubectl cordon. Warm claims
+// pin tenant pods with NodeName, which bypasses this, so the node's warm pods
+// must be recycled as well.
+func (c *Client) CordonNode(ctx context.Context, name string) error {
+	_, err := c.cs.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType,
+		[]byte(`{"spec":{"unschedulable":true}}`), metav1.PatchOptions{})
+	return err
+}
+
 // CountWarmPods returns the number of active (not being consumed) warm pool pods.
 func (c *Client) CountWarmPods(ctx context.Context, namespace string) (int, error) {
 	list, err := c.cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
@@ -364,6 +688,50 @@ func (c *Client) CountWarmPods(ctx context.Context, namespace string) (int, erro
 	return count, nil
 }
 
+// StuckTerminatingPods returns the tenant pods still Terminating stuckFor
+// after their grace period ran out (a pod's deletion timestamp is when its
+// grace period ends). Kata pods can hang there when VM teardown does, and a
+// stuck pod's name blocks the tenant's next wake.
+func (c *Client) StuckTerminatingPods(ctx context.Context, namespace string, stuckFor time.Duration, now time.Time) ([]*corev1.Pod, error) {
+	list, err := c.cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=zeroclaw",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var stuck []*corev1.Pod
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.DeletionTimestamp != nil && now.Sub(p.DeletionTimestamp.Time) >= stuckFor {
+			stuck = append(stuck, p)
+		}
+	}
+	return stuck, nil
+}
+
+// ForceDeletePod clears a pod's finalizers and deletes it without a grace
+// period, like kubectl delete --force --grace-period=0. The API object goes
+// at once; the kubelet cleans up whatever still runs on the node.
+func (c *Client) ForceDeletePod(ctx context.Context, pod *corev1.Pod) error {
+	pods := c.cs.CoreV1().Pods(pod.Namespace)
+	if len(pod.Finalizers) > 0 {
+		_, err := pods.Patch(ctx, pod.Name, types.MergePatchType,
+			[]byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("clear finalizers: %w", err)
+		}
+	}
+	var zero int64
+	err := pods.Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &zero})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 // PodExists checks whether a pod with the given name exists in the namespace
 func (c *Client) PodExists(ctx context.Context, name, namespace string) (bool, error) {
 	_, err := c.cs.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -376,9 +744,83 @@ func (c *Client) PodExists(ctx context.Context, name, namespace string) (bool, e
 	return true, nil
 }
 
+// WaitPodGone polls until the named pod no longer exists, e.g. until a
+// stopped pod has flushed its state and terminated
+func (c *Client) WaitPodGone(ctx context.Context, name, namespace string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		exists, err := c.PodExists(ctx, name, namespace)
+		return err == nil && !exists, nil
+	})
+	if err != nil {
+		return fmt.Errorf("pod %s still exists after %s: %w", name, timeout, err)
+	}
+	return nil
+}
+
+// GetPod returns a pod, or nil if it doesn't exist
+func (c *Client) GetPod(ctx context.Context, name, namespace string) (*corev1.Pod, error) {
+	pod, err := c.cs.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return pod, err
+}
+
+// PodLogs returns up to limitBytes of a pod's container logs written after
+// since (all retained logs when since is zero).
+func (c *Client) PodLogs(ctx context.Context, name, namespace string, since time.Time, limitBytes int64) (string, error) {
+	opts := &corev1.PodLogOptions{Container: "zeroclaw", LimitBytes: &limitBytes}
+	if !since.IsZero() {
+		t := metav1.NewTime(since)
+		opts.SinceTime = &t
+	}
+	raw, err := c.cs.CoreV1().Pods(namespace).GetLogs(name, opts).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get logs %s: %w", name, err)
+	}
+	return string(raw), nil
+}
+
+// StreamPodLogs opens a pod's agent container logs from the last tail lines
+// (all retained logs when tail is 0). With follow the stream stays open for
+// new lines until ctx is done or the container exits. The caller closes it.
+func (c *Client) StreamPodLogs(ctx context.Context, name, namespace string, tail int64, follow bool) (io.ReadCloser, error) {
+	opts := &corev1.PodLogOptions{Container: "zeroclaw", Follow: follow}
+	if tail > 0 {
+		opts.TailLines = &tail
+	}
+	stream, err := c.cs.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("stream logs %s: %w", name, err)
+	}
+	return stream, nil
+}
+
+// ReplacementPodName returns a unique pod name for a blue/green replacement
+// of the tenant's current pod.
+func ReplacementPodName(tenantID string) string {
+	return contract.PodName(tenantID) + "-" + strconv.FormatInt(time.Now().UnixMilli(), 36)
+}
+
 // Helpers
-func podName(tenantID string) string  { return "zeroclaw-" + tenantID }
-func PVCName(tenantID string) string  { return "pvc-tenant-" + tenantID }
-func pvName(tenantID string) string   { return "pv-tenant-" + tenantID }
-func strPtr(s string) *string         { return &s }
-func int64Ptr(i int64) *int64         { return &i }
+func strPtr(s string) *string { return &s }
+func int64Ptr(i int64) *int64 { return &i }
+
+// irsaRoleAnnotation tells the EKS pod identity webhook which IAM role a
+// ServiceAccount's pods assume
+const irsaRoleAnnotation = "eks.amazonaws.com/role-arn"
+
+// pvName and volumeHandle are cluster-wide, so they carry the environment.
+func (c *Client) pvName(tenantID string) string {
+	if c.cfg.Environment != "" {
+		return "pv-tenant-" + c.cfg.Environment + "-" + tenantID
+	}
+	return "pv-tenant-" + tenantID
+}
+
+func (c *Client) volumeHandle(tenantID string) string {
+	if c.cfg.Environment != "" {
+		return "tenant-" + c.cfg.Environment + "-" + tenantID
+	}
+	return "tenant-" + tenantID
+}
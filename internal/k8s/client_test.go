@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateTenantPod_BotTokenFromSecret(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	c := New(cs, Config{})
+	ctx := context.Background()
+
+	pod, err := c.CreateTenantPod(ctx, "alice", "tenants", "pvc", "123:abc", TenantPodOptions{})
+	require.NoError(t, err)
+
+	var found bool
+	for _, env := range pod.Spec.Containers[0].Env {
+		if env.Name != "TELEGRAM_BOT_TOKEN" {
+			continue
+		}
+		found = true
+		assert.Empty(t, env.Value, "token must not be in the pod spec")
+		require.NotNil(t, env.ValueFrom)
+		assert.Equal(t, contract.BotTokenSecretName("alice"), env.ValueFrom.SecretKeyRef.Name)
+	}
+	assert.True(t, found)
+
+	secret, err := cs.CoreV1().Secrets("tenants").Get(ctx, contract.BotTokenSecretName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", string(secret.Data[botTokenSecretKey]))
+
+	// A replacement pod after a token change updates the Secret
+	_, err = c.CreateTenantPod(ctx, "alice", "tenants", "pvc", "456:def", TenantPodOptions{PodName: "zeroclaw-alice-2"})
+	require.NoError(t, err)
+	secret, err = cs.CoreV1().Secrets("tenants").Get(ctx, contract.BotTokenSecretName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "456:def", string(secret.Data[botTokenSecretKey]))
+
+	require.NoError(t, c.DeleteBotTokenSecret(ctx, "alice", "tenants"))
+	_, err = cs.CoreV1().Secrets("tenants").Get(ctx, contract.BotTokenSecretName("alice"), metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.NoError(t, c.DeleteBotTokenSecret(ctx, "alice", "tenants"), "already gone")
+}
+
+func TestCreateTenantPod_StateReadOnly(t *testing.T) {
+	c := New(fake.NewSimpleClientset(), Config{})
+	pod, err := c.CreateTenantPod(context.Background(), "alice", "tenants", "pvc", "123:abc", TenantPodOptions{StateReadOnly: true})
+	require.NoError(t, err)
+
+	agent := pod.Spec.Containers[0]
+	for _, m := range agent.VolumeMounts {
+		assert.Equal(t, m.Name == "s3-state", m.ReadOnly, m.Name)
+	}
+	assert.Contains(t, agent.Env, corev1.EnvVar{Name: "STATE_READ_ONLY", Value: "true"})
+}
+
+func TestCreateTenantPod_KV(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	c := New(cs, Config{})
+	ctx := context.Background()
+	pod, err := c.CreateTenantPod(ctx, "alice", "tenants", "pvc", "123:abc", TenantPodOptions{
+		KV: &KVAccess{URL: "http://orchestrator:8080/tenants/alice/kv", Token: "tok"},
+	})
+	require.NoError(t, err)
+
+	agent := pod.Spec.Containers[0]
+	assert.Contains(t, agent.Env, corev1.EnvVar{Name: "AGENT_KV_URL", Value: "http://orchestrator:8080/tenants/alice/kv"})
+	var found bool
+	for _, env := range agent.Env {
+		if env.Name == "AGENT_KV_TOKEN" {
+			found = true
+			assert.Empty(t, env.Value, "token must not be in the pod spec")
+			assert.Equal(t, kvTokenSecretKey, env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+	assert.True(t, found)
+	secret, err := cs.CoreV1().Secrets("tenants").Get(ctx, contract.BotTokenSecretName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "tok", string(secret.Data[kvTokenSecretKey]))
+}
+
+func TestTenantServiceAccount(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	c := New(cs, Config{})
+	ctx := context.Background()
+
+	ok, err := c.TenantServiceAccountExists(ctx, "alice", "tenants")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.EnsureTenantServiceAccount(ctx, "alice", "tenants", "arn:aws:iam::1:role/a"))
+	require.NoError(t, c.EnsureTenantServiceAccount(ctx, "alice", "tenants", "arn:aws:iam::1:role/b"))
+	sa, err := cs.CoreV1().ServiceAccounts("tenants").Get(ctx, contract.ServiceAccountName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::1:role/b", sa.Annotations[irsaRoleAnnotation])
+	ok, err = c.TenantServiceAccountExists(ctx, "alice", "tenants")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	pod, err := c.CreateTenantPod(ctx, "alice", "tenants", "pvc", "", TenantPodOptions{ServiceAccount: contract.ServiceAccountName("alice")})
+	require.NoError(t, err)
+	assert.Equal(t, "zeroclaw-alice", pod.Spec.ServiceAccountName)
+	pod, err = c.CreateTenantPod(ctx, "bob", "tenants", "pvc", "", TenantPodOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "zeroclaw-tenant", pod.Spec.ServiceAccountName, "the shared account by default")
+
+	require.NoError(t, c.DeleteTenantServiceAccount(ctx, "alice", "tenants"))
+	require.NoError(t, c.DeleteTenantServiceAccount(ctx, "alice", "tenants"), "deleting a missing account")
+	_, err = cs.CoreV1().ServiceAccounts("tenants").Get(ctx, contract.ServiceAccountName("alice"), metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Kubernetes limits for pod dnsConfig.
+const (
+	maxDNSNameservers = 3
+	maxDNSSearches    = 32
+)
+
+// DNSSettings customises a tenant pod's DNS, e.g. split-horizon resolution of
+// internal APIs. Policy is a Kubernetes dnsPolicy; the remaining fields map to
+// pod dnsConfig and are merged with the policy's resolv.conf (or replace it
+// entirely when Policy is "None").
+type DNSSettings struct {
+	Policy      string   `json:"policy,omitempty"`
+	Nameservers []string `json:"nameservers,omitempty"`
+	Searches    []string `json:"searches,omitempty"`
+	Options     []string `json:"options,omitempty"` // "name" or "name:value", e.g. "ndots:2"
+}
+
+// IsZero reports whether d leaves the pod's DNS at the cluster default.
+func (d DNSSettings) IsZero() bool {
+	return d.Policy == "" && len(d.Nameservers) == 0 && len(d.Searches) == 0 && len(d.Options) == 0
+}
+
+// Validate checks d against the constraints the API server enforces, so bad
+// settings are rejected up front instead of failing pod creation on wake.
+func (d DNSSettings) Validate() error {
+	switch corev1.DNSPolicy(d.Policy) {
+	case "", corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if len(d.Nameservers) == 0 {
+			return fmt.Errorf("dns policy None requires at least one nameserver")
+		}
+	default:
+		return fmt.Errorf("invalid dns policy %q: want ClusterFirst, ClusterFirstWithHostNet, Default or None", d.Policy)
+	}
+	if len(d.Nameservers) > maxDNSNameservers {
+		return fmt.Errorf("at most %d dns nameservers allowed", maxDNSNameservers)
+	}
+	for _, ns := range d.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return fmt.Errorf("invalid dns nameserver %q: must be an IP address", ns)
+		}
+	}
+	if len(d.Searches) > maxDNSSearches {
+		return fmt.Errorf("at most %d dns search domains allowed", maxDNSSearches)
+	}
+	for _, s := range d.Searches {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(s, ".")); len(errs) > 0 {
+			return fmt.Errorf("invalid dns search domain %q: %s", s, strings.Join(errs, "; "))
+		}
+	}
+	for _, o := range d.Options {
+		if name, _, _ := strings.Cut(o, ":"); name == "" {
+			return fmt.Errorf("invalid dns option %q: want name or name:value", o)
+		}
+	}
+	return nil
+}
+
+func (d DNSSettings) apply(spec *corev1.PodSpec) {
+	if d.Policy != "" {
+		spec.DNSPolicy = corev1.DNSPolicy(d.Policy)
+	}
+	if len(d.Nameservers) == 0 && len(d.Searches) == 0 && len(d.Options) == 0 {
+		return
+	}
+	cfg := &corev1.PodDNSConfig{Nameservers: d.Nameservers, Searches: d.Searches}
+	for _, o := range d.Options {
+		name, value, ok := strings.Cut(o, ":")
+		opt := corev1.PodDNSConfigOption{Name: name}
+		if ok {
+			opt.Value = strPtr(value)
+		}
+		cfg.Options = append(cfg.Options, opt)
+	}
+	spec.DNSConfig = cfg
+}
+
+// ParseTierDNS parses a JSON object of tier → DNSSettings, e.g.
+// {"internal":{"searches":["corp.internal"],"options":["ndots:2"]}}.
+func ParseTierDNS(s string) (map[string]DNSSettings, error) {
+	out := make(map[string]DNSSettings)
+	if strings.TrimSpace(s) == "" {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		return nil, fmt.Errorf("invalid tier dns: %w", err)
+	}
+	for tier, d := range out {
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("tier %q: %w", tier, err)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDNSSettings_Validate(t *testing.T) {
+	valid := []DNSSettings{
+		{},
+		{Policy: "ClusterFirst", Searches: []string{"corp.internal"}, Options: []string{"ndots:2", "edns0"}},
+		{Policy: "None", Nameservers: []string{"10.0.0.2", "fd00::53"}},
+	}
+	for _, d := range valid {
+		assert.NoError(t, d.Validate(), "%+v", d)
+	}
+
+	invalid := []DNSSettings{
+		{Policy: "Custom"},
+		{Policy: "None"},
+		{Nameservers: []string{"dns.corp.internal"}},
+		{Nameservers: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}},
+		{Searches: []string{"bad_domain"}},
+		{Options: []string{":2"}},
+	}
+	for _, d := range invalid {
+		assert.Error(t, d.Validate(), "%+v", d)
+	}
+}
+
+func TestParseTierDNS(t *testing.T) {
+	m, err := ParseTierDNS(`{"internal":{"searches":["corp.internal"],"options":["ndots:2"]}}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"corp.internal"}, m["internal"].Searches)
+
+	m, err = ParseTierDNS("")
+	require.NoError(t, err)
+	assert.Empty(t, m)
+
+	_, err = ParseTierDNS(`{"internal":{"policy":"None"}}`)
+	assert.Error(t, err)
+}
+
+func TestCreateTenantPod_DNS(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	c := New(cs, Config{
+		TierDNS: map[string]DNSSettings{
+			"internal": {Searches: []string{"corp.internal"}, Options: []string{"ndots:2"}},
+		},
+	})
+	ctx := context.Background()
+
+	// Tier settings apply when the tenant has no override
+	pod, err := c.CreateTenantPod(ctx, "alice", "tenants", "pvc", "token", TenantPodOptions{Tier: "internal"})
+	require.NoError(t, err)
+	require.NotNil(t, pod.Spec.DNSConfig)
+	assert.Equal(t, []string{"corp.internal"}, pod.Spec.DNSConfig.Searches)
+	assert.Equal(t, "ndots", pod.Spec.DNSConfig.Options[0].Name)
+	assert.Equal(t, "2", *pod.Spec.DNSConfig.Options[0].Value)
+
+	// Tenant override wins over the tier
+	pod, err = c.CreateTenantPod(ctx, "bob", "tenants", "pvc", "token", TenantPodOptions{
+		Tier: "internal",
+		DNS:  &DNSSettings{Policy: "None", Nameservers: []string{"10.0.0.2"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.DNSNone, pod.Spec.DNSPolicy)
+	assert.Equal(t, []string{"10.0.0.2"}, pod.Spec.DNSConfig.Nameservers)
+	assert.Empty(t, pod.Spec.DNSConfig.Searches)
+
+	// Invalid settings are rejected before reaching the API server
+	_, err = c.CreateTenantPod(ctx, "carol", "tenants", "pvc", "token", TenantPodOptions{
+		DNS: &DNSSettings{Policy: "None"},
+	})
+	assert.Error(t, err)
+}
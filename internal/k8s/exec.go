@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/proxy"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// ErrExecDisabled is returned by ExecProxy when Config.REST is unset
+var ErrExecDisabled = errors.New("exec sessions not configured")
+
+// ExecOptions is a command to run in a tenant container and the streams
+// the session attaches
+type ExecOptions struct {
+	Command []string
+	Stdin   bool
+	// TTY allocates a terminal; its output is merged into stdout
+	TTY bool
+}
+
+// ExecProxy returns a handler that relays an exec session into the pod's
+// zeroclaw container: the caller's SPDY upgrade request (as sent by
+// kubectl exec and client-go's remotecommand) is forwarded to the API
+// server's pods/exec subresource under the orchestrator's own credentials,
+// and the streams are copied both ways until either side closes. The
+// caller's Authorization header is dropped before forwarding.
+func (c *Client) ExecProxy(podName, namespace string, opts ExecOptions) (http.Handler, error) {
+	if c.cfg.REST == nil {
+		return nil, ErrExecDisabled
+	}
+	host := c.cfg.REST.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	loc, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("api server url: %w", err)
+	}
+	loc.Path = strings.TrimSuffix(loc.Path, "/") + "/api/v1/namespaces/" + namespace + "/pods/" + podName + "/exec"
+	q := url.Values{"container": {"zeroclaw"}, "command": opts.Command}
+	q.Set("stdout", "true")
+	q.Set("stdin", strconv.FormatBool(opts.Stdin))
+	q.Set("stderr", strconv.FormatBool(!opts.TTY))
+	q.Set("tty", strconv.FormatBool(opts.TTY))
+	loc.RawQuery = q.Encode()
+
+	rt, err := rest.TransportFor(c.cfg.REST)
+	if err != nil {
+		return nil, fmt.Errorf("api server transport: %w", err)
+	}
+	upgrade, err := upgradeTransport(c.cfg.REST)
+	if err != nil {
+		return nil, fmt.Errorf("api server transport: %w", err)
+	}
+	p := proxy.NewUpgradeAwareHandler(loc, rt, false, true, execResponder{})
+	p.UpgradeTransport = upgrade
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		r.Header.Del("Authorization")
+		for k := range r.Header {
+			if strings.HasPrefix(k, "Impersonate-") {
+				r.Header.Del(k)
+			}
+		}
+		p.ServeHTTP(w, r)
+	}), nil
+}
+
+// upgradeTransport dials the API server for an upgraded connection and
+// authenticates the request as cfg does, like kubectl proxy
+func upgradeTransport(cfg *rest.Config) (proxy.UpgradeRequestRoundTripper, error) {
+	tc, err := cfg.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := transport.TLSConfigFor(tc)
+	if err != nil {
+		return nil, err
+	}
+	rt := utilnet.SetOldTransportDefaults(&http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext:     (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	})
+	auth, err := transport.HTTPWrappersForConfig(tc, proxy.MirrorRequest)
+	if err != nil {
+		return nil, err
+	}
+	return proxy.NewUpgradeRequestRoundTripper(rt, auth), nil
+}
+
+// execResponder answers a session the API server couldn't be reached for
+type execResponder struct{}
+
+func (execResponder) Error(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, "exec unavailable: "+err.Error(), http.StatusBadGateway)
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// TerminationOp identifies why a pod is being terminated.
+type TerminationOp string
+
+const (
+	OpIdle      TerminationOp = "idle"       // lifecycle controller idle timeout
+	OpDelete    TerminationOp = "delete"     // tenant deletion
+	OpWarmClaim TerminationOp = "warm-claim" // warm pod freed for a tenant pod
+)
+
+const (
+	defaultIdleGraceS   = 30
+	defaultDeleteGraceS = 30
+)
+
+// GracePolicy decides termination grace periods per operation and tenant tier.
+//
+// Tenant pods receive SIGTERM and have the grace period to flush brain.db to
+// /s3-state before SIGKILL; the value is also passed to the container as
+// SHUTDOWN_GRACE_PERIOD_S so the agent can budget its shutdown work.
+type GracePolicy struct {
+	IdleS      int64
+	DeleteS    int64
+	WarmClaimS int64
+	// TierIdleS and TierDeleteS override IdleS and DeleteS for tenants on
+	// the given tier. Warm pods have no tenant, so WarmClaimS is never
+	// overridden.
+	TierIdleS   map[string]int64
+	TierDeleteS map[string]int64
+}
+
+func (p GracePolicy) withDefaults() GracePolicy {
+	if p.IdleS == 0 {
+		p.IdleS = defaultIdleGraceS
+	}
+	if p.DeleteS == 0 {
+		p.DeleteS = defaultDeleteGraceS
+	}
+	return p
+}
+
+// For returns the grace period in seconds for op on a tenant of the given tier.
+func (p GracePolicy) For(op TerminationOp, tier string) int64 {
+	switch op {
+	case OpWarmClaim:
+		return p.WarmClaimS
+	case OpDelete:
+		if s, ok := p.TierDeleteS[tier]; ok {
+			return s
+		}
+		return p.DeleteS
+	default:
+		if s, ok := p.TierIdleS[tier]; ok {
+			return s
+		}
+		return p.IdleS
+	}
+}
+
+// PodGrace returns the terminationGracePeriodSeconds for a tenant pod spec:
+// the longest grace any operation would grant, so evictions and node drains
+// are never shorter than an orchestrator-initiated shutdown.
+func (p GracePolicy) PodGrace(tier string) int64 {
+	idle, del := p.For(OpIdle, tier), p.For(OpDelete, tier)
+	if del > idle {
+		return del
+	}
+	return idle
+}
+
+// MergeTierGrace returns base with override's tiers replacing its own
+func MergeTierGrace(base, override map[string]int64) map[string]int64 {
+	out := maps.Clone(base)
+	if out == nil {
+		out = make(map[string]int64, len(override))
+	}
+	maps.Copy(out, override)
+	return out
+}
+
+// ParseTierGrace parses "tier=seconds,tier=seconds" (e.g. "premium=120,free=10").
+func ParseTierGrace(s string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tier, secs, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid tier grace %q: want tier=seconds", part)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(secs), 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid tier grace %q: seconds must be a non-negative integer", part)
+		}
+		out[strings.TrimSpace(tier)] = n
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracePolicy_Defaults(t *testing.T) {
+	p := GracePolicy{}.withDefaults()
+	assert.Equal(t, int64(30), p.For(OpIdle, ""))
+	assert.Equal(t, int64(30), p.For(OpDelete, ""))
+	assert.Equal(t, int64(0), p.For(OpWarmClaim, ""))
+}
+
+func TestGracePolicy_TierOverride(t *testing.T) {
+	premium := map[string]int64{"premium": 120}
+	p := GracePolicy{IdleS: 20, DeleteS: 45, TierIdleS: premium, TierDeleteS: premium}.withDefaults()
+	assert.Equal(t, int64(120), p.For(OpIdle, "premium"))
+	assert.Equal(t, int64(120), p.For(OpDelete, "premium"))
+	assert.Equal(t, int64(0), p.For(OpWarmClaim, "premium"))
+	assert.Equal(t, int64(20), p.For(OpIdle, "standard"))
+	assert.Equal(t, int64(45), p.PodGrace("standard"))
+}
+
+func TestGracePolicy_TierOverridePerOp(t *testing.T) {
+	tiers := map[string]int64{"premium": 120, "free": 10}
+	p := GracePolicy{
+		TierIdleS:   tiers,
+		TierDeleteS: MergeTierGrace(tiers, map[string]int64{"premium": 300}),
+	}.withDefaults()
+	assert.Equal(t, int64(120), p.For(OpIdle, "premium"))
+	assert.Equal(t, int64(300), p.For(OpDelete, "premium"), "delete has its own override")
+	assert.Equal(t, int64(10), p.For(OpDelete, "free"), "the shared one still applies")
+	assert.Equal(t, int64(300), p.PodGrace("premium"))
+	assert.Equal(t, map[string]int64{"premium": 120, "free": 10}, tiers, "merging leaves the base alone")
+}
+
+func TestParseTierGrace(t *testing.T) {
+	m, err := ParseTierGrace("premium=120, free=10,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"premium": 120, "free": 10}, m)
+
+	_, err = ParseTierGrace("premium")
+	assert.Error(t, err)
+	_, err = ParseTierGrace("free=-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package k8s
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// imageRef resolves a tenant image to a pullable reference. Empty means the
+// configured ZeroClaw image; a bare tag (no '/', ':' or '@') is applied to the
+// configured image's repository; anything else is used as-is.
+func (c *Client) imageRef(image string) string {
+	if image == "" {
+		return c.cfg.ZeroClawImage
+	}
+	if strings.ContainsAny(image, "/:@") {
+		return image
+	}
+	return withTag(c.cfg.ZeroClawImage, image)
+}
+
+// withTag replaces the tag or digest of image with tag.
+func withTag(image, tag string) string {
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo + ":" + tag
+}
+
+// RunsImage reports whether a tenant pod's zeroclaw container runs image, as
+// it would be passed in TenantPodOptions.Image
+func (c *Client) RunsImage(pod *corev1.Pod, image string) bool {
+	for _, ctr := range pod.Spec.Containers {
+		if ctr.Name == "zeroclaw" {
+			return ctr.Image == c.imageRef(image)
+		}
+	}
+	return false
+}
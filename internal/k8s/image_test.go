@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWithTag(t *testing.T) {
+	assert.Equal(t, "zeroclaw:v1.4", withTag("zeroclaw:latest", "v1.4"))
+	assert.Equal(t, "zeroclaw:v1.4", withTag("zeroclaw", "v1.4"))
+	assert.Equal(t, "registry:5000/zeroclaw:v1.4", withTag("registry:5000/zeroclaw", "v1.4"))
+	assert.Equal(t, "123.dkr.ecr.us-east-1.amazonaws.com/zeroclaw:v1.4",
+		withTag("123.dkr.ecr.us-east-1.amazonaws.com/zeroclaw@sha256:abc", "v1.4"))
+}
+
+func TestImageRef(t *testing.T) {
+	c := New(nil, Config{ZeroClawImage: "ecr.example.com/zeroclaw:latest"})
+
+	assert.Equal(t, "ecr.example.com/zeroclaw:latest", c.imageRef(""))
+	assert.Equal(t, "ecr.example.com/zeroclaw:v1.5.0", c.imageRef("v1.5.0"))
+	assert.Equal(t, "other/zeroclaw:beta", c.imageRef("other/zeroclaw:beta"))
+}
+
+func TestRunsImage(t *testing.T) {
+	c := New(nil, Config{ZeroClawImage: "ecr.example.com/zeroclaw:v2"})
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "zeroclaw", Image: "ecr.example.com/zeroclaw:v1"},
+	}}}
+
+	assert.False(t, c.RunsImage(pod, ""))
+	assert.True(t, c.RunsImage(pod, "v1"))
+	assert.False(t, c.RunsImage(&corev1.Pod{}, "v1"))
+}
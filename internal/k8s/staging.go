@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WarmStagingPVC is the claim warm pods mount when Config.WarmStagingVolume
+// is set. It points at a shared, read-only S3 prefix that holds no tenant data.
+const WarmStagingPVC = "pvc-warm-staging"
+
+// warmStagingMountPath is where warm pods mount the staging volume. Tenant
+// pods never see it.
+const warmStagingMountPath = "/s3-staging"
+
+// EnsureWarmStagingVolume creates the shared staging PV and PVC mounted by
+// warm pods. Mounting any S3 CSI volume makes the driver start its
+// Mountpoint process and cache the bucket's credentials on the node, so a
+// tenant pod later pinned to that node skips that work on its own mount.
+func (c *Client) EnsureWarmStagingVolume(ctx context.Context, namespace string) error {
+	storageClass := "s3-tenant-state"
+	pvName := "pv-warm-staging"
+	subPath := "warm-staging"
+	if c.cfg.Environment != "" {
+		pvName += "-" + c.cfg.Environment
+		subPath = c.cfg.Environment + "/" + subPath
+	}
+
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: pvName},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("1Gi"),
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany},
+			StorageClassName:              storageClass,
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       "s3.csi.aws.com",
+					VolumeHandle: pvName,
+					ReadOnly:     true,
+					VolumeAttributes: map[string]string{
+						"bucketName": c.cfg.S3Bucket,
+						"subPath":    subPath,
+					},
+				},
+			},
+		},
+	}
+	if _, err := c.cs.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create staging PV: %w", err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WarmStagingPVC,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany},
+			StorageClassName: &storageClass,
+			VolumeName:       pvName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+	if _, err := c.cs.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create staging PVC: %w", err)
+	}
+	return nil
+}
+
+// applyWarmStaging mounts the staging volume into a warm pod spec, or removes
+// it when the option is off, so toggling the option rolls the warm pool.
+func (c *Client) applyWarmStaging(spec *corev1.PodSpec) {
+	spec.Volumes = nil
+	spec.Containers[0].VolumeMounts = nil
+	if !c.cfg.WarmStagingVolume {
+		return
+	}
+	spec.Volumes = []corev1.Volume{{
+		Name: "s3-staging",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: WarmStagingPVC,
+				ReadOnly:  true,
+			},
+		},
+	}}
+	spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+		{Name: "s3-staging", MountPath: warmStagingMountPath, ReadOnly: true},
+	}
+}
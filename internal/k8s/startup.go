@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const defaultStartupPeriodS = 5
+
+// StartupProbePolicy configures the tenant container's startupProbe.
+//
+// A Kata pod boots a VM before the agent starts, and the agent then restores
+// its state from /s3-state, so a cold start takes far longer than a plain
+// container. The startupProbe gives it BudgetS seconds to start listening
+// before the kubelet restarts it, and holds off liveness and readiness probes
+// until it has, so they can be tuned for a running agent rather than a
+// booting one.
+type StartupProbePolicy struct {
+	// Path is probed with HTTP GET on the agent port; empty checks only that
+	// the port accepts connections.
+	Path string
+	// PeriodS is the interval between probes (default 5).
+	PeriodS int32
+	// BudgetS is how long the container may take to start. Zero disables the
+	// probe unless the tenant's tier sets a budget.
+	BudgetS int64
+	// TierS overrides BudgetS for tenants on the given tier.
+	TierS map[string]int64
+}
+
+// Probe returns the startupProbe for a tenant of the given tier, or nil if
+// it has no startup budget.
+func (p StartupProbePolicy) Probe(tier string) *corev1.Probe {
+	budget := p.BudgetS
+	if s, ok := p.TierS[tier]; ok {
+		budget = s
+	}
+	if budget <= 0 {
+		return nil
+	}
+	period := p.PeriodS
+	if period <= 0 {
+		period = defaultStartupPeriodS
+	}
+	handler := corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(contract.AgentPort)}}
+	if p.Path != "" {
+		handler = corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: p.Path, Port: intstr.FromInt32(contract.AgentPort)}}
+	}
+	return &corev1.Probe{
+		ProbeHandler:  handler,
+		PeriodSeconds: period,
+		// Round up so the container always gets at least the whole budget
+		FailureThreshold: int32((budget + int64(period) - 1) / int64(period)),
+	}
+}
+
+// ParseTierStartup parses "tier=seconds,tier=seconds" (e.g. "premium=300,free=120").
+func ParseTierStartup(s string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tier, secs, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid tier startup budget %q: want tier=seconds", part)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(secs), 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid tier startup budget %q: seconds must be a non-negative integer", part)
+		}
+		out[strings.TrimSpace(tier)] = n
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStartupProbePolicy(t *testing.T) {
+	p := StartupProbePolicy{BudgetS: 180, TierS: map[string]int64{"premium": 302, "free": 0}}
+
+	probe := p.Probe("standard")
+	require.NotNil(t, probe)
+	assert.Equal(t, int32(5), probe.PeriodSeconds)
+	assert.Equal(t, int32(36), probe.FailureThreshold)
+	require.NotNil(t, probe.TCPSocket)
+	assert.Equal(t, int32(contract.AgentPort), probe.TCPSocket.Port.IntVal)
+
+	assert.Equal(t, int32(61), p.Probe("premium").FailureThreshold, "rounded up to cover the whole budget")
+	assert.Nil(t, p.Probe("free"), "a zero tier budget disables the probe")
+	assert.Nil(t, StartupProbePolicy{}.Probe(""), "off by default")
+
+	probe = StartupProbePolicy{Path: "/health", PeriodS: 10, BudgetS: 60}.Probe("")
+	require.NotNil(t, probe.HTTPGet)
+	assert.Equal(t, "/health", probe.HTTPGet.Path)
+	assert.Equal(t, int32(6), probe.FailureThreshold)
+}
+
+func TestCreateTenantPod_StartupProbe(t *testing.T) {
+	c := New(fake.NewSimpleClientset(), Config{Startup: StartupProbePolicy{BudgetS: 120}})
+	pod, err := c.CreateTenantPod(context.Background(), "alice", "tenants", "pvc", "123:abc", TenantPodOptions{})
+	require.NoError(t, err)
+	probe := pod.Spec.Containers[0].StartupProbe
+	require.NotNil(t, probe)
+	assert.Equal(t, int32(24), probe.FailureThreshold)
+}
+
+func TestParseTierStartup(t *testing.T) {
+	m, err := ParseTierStartup("premium=300, free=120,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"premium": 300, "free": 120}, m)
+
+	_, err = ParseTierStartup("premium")
+	assert.Error(t, err)
+	_, err = ParseTierStartup("free=-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Containers StateSyncPolicy adds to a tenant pod
+const (
+	StateRestoreContainer = "state-restore"
+	StateSyncContainer    = "state-sync"
+)
+
+// StateSyncPolicy has the orchestrator, rather than the agent, move tenant
+// state between the pod's emptyDir (/zeroclaw-data) and its S3 mount
+// (/s3-state):
+//
+//   - restore-on-start: an init container copies /s3-state into the emptyDir
+//     before the agent starts. If the copy fails the agent doesn't start, so
+//     it never runs on (and later flushes) partial state.
+//   - flush-on-stop: a native sidecar copies the emptyDir back to /s3-state.
+//     The kubelet stops sidecars after the pod's other containers have
+//     exited, so the final copy sees everything the agent wrote. It needs
+//     Kubernetes 1.29 or later.
+//
+// The agent is told via STATE_SYNC=external and should then work on
+// /zeroclaw-data alone.
+type StateSyncPolicy struct {
+	// Image runs the sync containers and needs sh, cp, du, find and rm (e.g.
+	// busybox).
+	// Empty disables state sync: the agent copies its own state.
+	Image string
+	// IntervalS also flushes this often while the pod runs, bounding what a
+	// crash loses. A file the agent is writing may be copied half-written,
+	// which the next flush repairs. Zero flushes only on stop.
+	IntervalS int64
+}
+
+// Enabled reports whether tenant pods get the sync containers.
+func (p StateSyncPolicy) Enabled() bool {
+	return p.Image != ""
+}
+
+// restoreScript copies S3 state into the emptyDir. Its termination message
+// reports what was restored; on failure it is cp's error instead.
+const restoreScript = `set -e
+start=$(date +%s)
+cp -R /s3-state/. /zeroclaw-data/
+echo "restored $(du -sk /zeroclaw-data | cut -f1) KiB in $(($(date +%s) - start))s" > /dev/termination-log
+`
+
+// flushFunc mirrors the emptyDir to S3: it copies every file over, then
+// removes what the agent has deleted since the last flush, or deleted memory
+// would come back on the next restore. find -depth lists a directory's
+// contents before the directory itself.
+const flushFunc = `flush() {
+  cp -R /zeroclaw-data/. /s3-state/ || return 1
+  (cd /s3-state && find . -depth ! -name . | while read -r f; do
+    [ -e "/zeroclaw-data/$f" ] || [ -L "/zeroclaw-data/$f" ] || rm -rf "$f" || exit 1
+  done)
+}`
+
+// syncScript flushes the emptyDir to S3 on SIGTERM and, with an interval,
+// periodically. A failed periodic flush exits so the kubelet restarts the
+// container, which is how it shows up in the tenant's events.
+func (p StateSyncPolicy) syncScript() string {
+	periodic := ""
+	interval := int64(3600)
+	if p.IntervalS > 0 {
+		periodic = "\n  flush || exit 1"
+		interval = p.IntervalS
+	}
+	return fmt.Sprintf(`%s
+trap 'flush; exit $?' TERM
+while true; do
+  sleep %d & wait $!%s
+done
+`, flushFunc, interval, periodic)
+}
+
+// apply adds the sync containers to a tenant pod. A read-only /s3-state is
+// restored from but never flushed to.
+func (p StateSyncPolicy) apply(spec *corev1.PodSpec, stateReadOnly bool) {
+	if !p.Enabled() {
+		return
+	}
+	container := func(name, script string, s3ReadOnly bool) corev1.Container {
+		return corev1.Container{
+			Name:                     name,
+			Image:                    p.Image,
+			Command:                  []string{"sh", "-c", script},
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "local-state", MountPath: "/zeroclaw-data"},
+				{Name: "s3-state", MountPath: "/s3-state", ReadOnly: s3ReadOnly},
+			},
+		}
+	}
+	spec.InitContainers = append(spec.InitContainers, container(StateRestoreContainer, restoreScript, true))
+	if !stateReadOnly {
+		always := corev1.ContainerRestartPolicyAlways
+		sidecar := container(StateSyncContainer, p.syncScript(), false)
+		sidecar.RestartPolicy = &always
+		spec.InitContainers = append(spec.InitContainers, sidecar)
+	}
+	agent := &spec.Containers[0]
+	agent.Env = append(agent.Env, corev1.EnvVar{Name: "STATE_SYNC", Value: "external"})
+}
+
+// StateRestoreError is a tenant pod whose state restore failed, so its agent
+// can't start
+type StateRestoreError struct {
+	Pod     string
+	Message string
+}
+
+func (e *StateRestoreError) Error() string {
+	return fmt.Sprintf("pod %s: state restore failed: %s", e.Pod, e.Message)
+}
+
+// stateRestoreFailure returns the pod's failed state restore, or nil
+func stateRestoreFailure(pod *corev1.Pod) *StateRestoreError {
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name != StateRestoreContainer {
+			continue
+		}
+		t := s.State.Terminated
+		if t == nil {
+			t = s.LastTerminationState.Terminated
+		}
+		if t == nil || t.ExitCode == 0 {
+			return nil
+		}
+		msg := t.Message
+		if msg == "" {
+			msg = fmt.Sprintf("exit code %d", t.ExitCode)
+		}
+		return &StateRestoreError{Pod: pod.Name, Message: msg}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateTenantPod_StateSync(t *testing.T) {
+	c := New(fake.NewSimpleClientset(), Config{StateSync: StateSyncPolicy{Image: "busybox:1.36", IntervalS: 300}})
+	pod, err := c.CreateTenantPod(context.Background(), "alice", "tenants", "pvc", "123:abc", TenantPodOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, pod.Spec.InitContainers, 2)
+	restore, sync := pod.Spec.InitContainers[0], pod.Spec.InitContainers[1]
+	assert.Equal(t, StateRestoreContainer, restore.Name)
+	assert.Nil(t, restore.RestartPolicy, "runs to completion before the agent")
+	assert.True(t, restore.VolumeMounts[1].ReadOnly, "restoring never writes to S3")
+	assert.Contains(t, restore.Command[2], "cp -R /s3-state/. /zeroclaw-data/")
+
+	assert.Equal(t, StateSyncContainer, sync.Name)
+	require.NotNil(t, sync.RestartPolicy)
+	assert.Equal(t, corev1.ContainerRestartPolicyAlways, *sync.RestartPolicy, "a native sidecar, stopped after the agent")
+	assert.Contains(t, sync.Command[2], "sleep 300 & wait $!\n  flush || exit 1")
+	assert.Contains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "STATE_SYNC", Value: "external"})
+
+	pod, err = c.CreateTenantPod(context.Background(), "bob", "tenants", "pvc", "123:abc", TenantPodOptions{StateReadOnly: true})
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.InitContainers, 1, "read-only state is never flushed")
+	assert.Equal(t, StateRestoreContainer, pod.Spec.InitContainers[0].Name)
+
+	c = New(fake.NewSimpleClientset(), Config{})
+	pod, err = c.CreateTenantPod(context.Background(), "alice", "tenants", "pvc", "123:abc", TenantPodOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pod.Spec.InitContainers, "off by default")
+}
+
+func TestStateSyncPolicy_FlushOnStopOnly(t *testing.T) {
+	script := StateSyncPolicy{Image: "busybox"}.syncScript()
+	assert.Contains(t, script, "trap 'flush; exit $?' TERM")
+	assert.NotContains(t, script, "flush ||")
+}
+
+// TestStateSyncFlush_RemovesDeletedFiles runs the flush against two
+// directories standing in for the emptyDir and the S3 mount
+func TestStateSyncFlush_RemovesDeletedFiles(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	local, s3 := t.TempDir(), t.TempDir()
+	write := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+	}
+	write(filepath.Join(local, "brain.db"))
+	write(filepath.Join(local, "memory", "keep.md"))
+	// Deleted by the agent since the last flush
+	write(filepath.Join(s3, "memory", "forget.md"))
+	write(filepath.Join(s3, "old", "notes.md"))
+
+	script := strings.NewReplacer("/zeroclaw-data", local, "/s3-state", s3).Replace(flushFunc) + "\nflush\n"
+	out, err := exec.Command("sh", "-c", script).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	assert.FileExists(t, filepath.Join(s3, "brain.db"))
+	assert.FileExists(t, filepath.Join(s3, "memory", "keep.md"))
+	assert.NoFileExists(t, filepath.Join(s3, "memory", "forget.md"))
+	assert.NoDirExists(t, filepath.Join(s3, "old"))
+}
+
+func TestWaitPodReady_StateRestoreFailed(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-alice", Namespace: "tenants"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  StateRestoreContainer,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 1,
+					Message:  "cp: can't open '/s3-state/brain.db': Input/output error",
+				}},
+			}},
+		},
+	}
+	c := New(fake.NewSimpleClientset(pod), Config{})
+
+	start := time.Now()
+	_, err := c.WaitPodReady(context.Background(), "alice", "tenants", time.Minute)
+	var restoreErr *StateRestoreError
+	require.True(t, errors.As(err, &restoreErr), "got %v", err)
+	assert.Equal(t, "zeroclaw-alice", restoreErr.Pod)
+	assert.Contains(t, restoreErr.Message, "Input/output error")
+	assert.Less(t, time.Since(start), 10*time.Second, "doesn't wait out the timeout")
+
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+	assert.Nil(t, stateRestoreFailure(pod), "a retry succeeded")
+}
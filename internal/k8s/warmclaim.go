@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// warmClaimedAtAnnotation on a tenant's PVC records its last warm claim. The
+// PVC outlives the tenant's pods, so the cooldown survives sleep/wake cycles
+// and is shared by every orchestrator replica.
+const warmClaimedAtAnnotation = "agentic-tenancy/warm-claimed-at"
+
+var (
+	// ErrWarmReserved means the remaining warm pods are reserved for other tiers
+	ErrWarmReserved = errors.New("remaining warm pods are reserved for other tiers")
+	// ErrWarmCooldown means the tenant claimed a warm pod too recently
+	ErrWarmCooldown = errors.New("tenant is in its warm claim cooldown")
+)
+
+// WarmPoolPolicy keeps a few busy tenants from draining the warm pool. Zero
+// value: first come, first served.
+type WarmPoolPolicy struct {
+	// Reserve holds back this many warm pods per tier: a tenant may only
+	// claim a pod if more are available than the other tiers' reservations
+	// add up to.
+	Reserve map[string]int
+	// Cooldown is how long after a warm claim a tenant's next wake cold-starts
+	// instead, so a tenant that sleeps and wakes in a loop doesn't keep
+	// taking pods others are waiting for.
+	Cooldown time.Duration
+}
+
+// WarmClaim identifies the tenant asking GetWarmPod for a pod
+type WarmClaim struct {
+	TenantID string
+	Tier     string
+	// Namespace is the tenant's, where its PVC records its claims. Empty =
+	// the warm pool's namespace.
+	Namespace string
+}
+
+// pvcNamespace is where the claiming tenant's PVC lives
+func (claim WarmClaim) pvcNamespace(warmNamespace string) string {
+	if claim.Namespace != "" {
+		return claim.Namespace
+	}
+	return warmNamespace
+}
+
+// reservedForOthers is how many warm pods tier may not take
+func (p WarmPoolPolicy) reservedForOthers(tier string) int {
+	n := 0
+	for t, r := range p.Reserve {
+		if t != tier {
+			n += r
+		}
+	}
+	return n
+}
+
+// inCooldown reports whether the tenant's PVC records a warm claim within the
+// cooldown. A missing PVC or annotation means no recent claim.
+func (c *Client) inCooldown(ctx context.Context, claim WarmClaim, namespace string, now time.Time) bool {
+	if c.cfg.WarmPolicy.Cooldown <= 0 || claim.TenantID == "" {
+		return false
+	}
+	pvc, err := c.cs.CoreV1().PersistentVolumeClaims(claim.pvcNamespace(namespace)).Get(ctx, contract.PVCName(claim.TenantID), metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	last, err := time.Parse(time.RFC3339, pvc.Annotations[warmClaimedAtAnnotation])
+	return err == nil && now.Sub(last) < c.cfg.WarmPolicy.Cooldown
+}
+
+// recordClaim stamps the tenant's PVC with the time of a warm claim
+func (c *Client) recordClaim(ctx context.Context, claim WarmClaim, namespace string, now time.Time) error {
+	if c.cfg.WarmPolicy.Cooldown <= 0 || claim.TenantID == "" {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, warmClaimedAtAnnotation, now.UTC().Format(time.RFC3339))
+	_, err := c.cs.CoreV1().PersistentVolumeClaims(claim.pvcNamespace(namespace)).Patch(ctx, contract.PVCName(claim.TenantID), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// ParseTierReserve parses "tier=pods,tier=pods" (e.g. "standard=2,free=1").
+func ParseTierReserve(s string) (map[string]int, error) {
+	out := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tier, pods, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid warm pool reservation %q: want tier=pods", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(pods))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid warm pool reservation %q: pods must be a non-negative integer", part)
+		}
+		out[strings.TrimSpace(tier)] = n
+	}
+	return out, nil
+}
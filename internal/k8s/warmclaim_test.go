@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func warmPods(n int) []runtime.Object {
+	var objs []runtime.Object
+	for i := 0; i < n; i++ {
+		objs = append(objs, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("warm-%d", i), Namespace: "tenants",
+				Labels: map[string]string{"app": "warm-pool", "warm": "true"},
+			},
+			Spec:   corev1.PodSpec{NodeName: "node-1"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		})
+	}
+	return objs
+}
+
+// TestGetWarmPod_Reserve: the last warm pods are held for the tiers that
+// reserved them
+func TestGetWarmPod_Reserve(t *testing.T) {
+	c := New(fake.NewSimpleClientset(warmPods(2)...), Config{
+		WarmPolicy: WarmPoolPolicy{Reserve: map[string]int{"standard": 1}},
+	})
+	ctx := context.Background()
+
+	pod, err := c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "big", Tier: "premium"})
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+
+	_, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "big2", Tier: "premium"})
+	assert.ErrorIs(t, err, ErrWarmReserved)
+
+	pod, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "small", Tier: "standard"})
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+
+	pod, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "small2", Tier: "standard"})
+	assert.NoError(t, err, "an empty pool is a plain miss")
+	assert.Nil(t, pod)
+}
+
+// TestGetWarmPod_Cooldown: a tenant's second claim within the cooldown is
+// refused, based on the stamp on its PVC
+func TestGetWarmPod_Cooldown(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: contract.PVCName("alice"), Namespace: "tenants"}}
+	cs := fake.NewSimpleClientset(append(warmPods(3), pvc)...)
+	c := New(cs, Config{WarmPolicy: WarmPoolPolicy{Cooldown: time.Minute}})
+	ctx := context.Background()
+
+	pod, err := c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "alice", Tier: "standard"})
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+
+	got, err := cs.CoreV1().PersistentVolumeClaims("tenants").Get(ctx, contract.PVCName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.Annotations[warmClaimedAtAnnotation])
+
+	_, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "alice", Tier: "standard"})
+	assert.ErrorIs(t, err, ErrWarmCooldown)
+
+	pod, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "bob", Tier: "standard"})
+	require.NoError(t, err, "other tenants are unaffected")
+	assert.NotNil(t, pod)
+
+	// An expired stamp no longer blocks
+	got.Annotations[warmClaimedAtAnnotation] = time.Now().Add(-2 * time.Minute).UTC().Format(time.RFC3339)
+	_, err = cs.CoreV1().PersistentVolumeClaims("tenants").Update(ctx, got, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	pod, err = c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "alice", Tier: "standard"})
+	require.NoError(t, err)
+	assert.NotNil(t, pod)
+}
+
+func TestParseTierReserve(t *testing.T) {
+	m, err := ParseTierReserve("standard=2, free=1,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"standard": 2, "free": 1}, m)
+
+	_, err = ParseTierReserve("standard")
+	assert.Error(t, err)
+	_, err = ParseTierReserve("free=-1")
+	assert.Error(t, err)
+}
+
+// TestGetWarmPod_SeparateNamespace: warm pods are claimed from the warm
+// pool's namespace while the cooldown stamp goes on the PVC in the tenant's
+func TestGetWarmPod_SeparateNamespace(t *testing.T) {
+	objs := warmPods(1)
+	objs[0].(*corev1.Pod).Namespace = "warm"
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: contract.PVCName("alice"), Namespace: "tenants"}}
+	cs := fake.NewSimpleClientset(append(objs, pvc)...)
+	c := New(cs, Config{WarmPolicy: WarmPoolPolicy{Cooldown: time.Minute}})
+	ctx := context.Background()
+
+	pod, err := c.GetWarmPod(ctx, "tenants", WarmClaim{TenantID: "bob", Tier: "standard"})
+	require.NoError(t, err)
+	assert.Nil(t, pod, "no warm pods in the tenants' namespace")
+
+	pod, err = c.GetWarmPod(ctx, "warm", WarmClaim{TenantID: "alice", Tier: "standard", Namespace: "tenants"})
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+	assert.Equal(t, "warm", pod.Namespace)
+	assert.Equal(t, "node-1", pod.Spec.NodeName)
+
+	got, err := cs.CoreV1().PersistentVolumeClaims("tenants").Get(ctx, contract.PVCName("alice"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.Annotations[warmClaimedAtAnnotation])
+}
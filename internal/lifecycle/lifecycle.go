@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/shawn/agentic-tenancy/internal/events"
 	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
 	"github.com/shawn/agentic-tenancy/internal/registry"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +21,13 @@ type Controller struct {
 	cs        kubernetes.Interface
 	namespace string
 	leaderID  string
+	// leaderTasks run alongside the idle loop while this replica leads
+	leaderTasks []func(ctx context.Context)
+	// dryRun logs idle tenants instead of stopping them
+	dryRun bool
+	// events and env publish tenant.idle for each tenant stopped
+	events *events.Bus
+	env    string
 }
 
 // NewForTest creates a Controller for unit testing (no leader election)
@@ -42,6 +50,26 @@ func New(reg registry.Client, k8s *k8sclient.Client, cs kubernetes.Interface, na
 	}
 }
 
+// WithDryRun makes the idle loop log which tenants it would stop without
+// stopping them, e.g. when first enabling it against an existing fleet.
+func (c *Controller) WithDryRun(dryRun bool) *Controller {
+	c.dryRun = dryRun
+	return c
+}
+
+// WithEvents publishes a tenant.idle event, for environment env, for each
+// idle tenant stopped
+func (c *Controller) WithEvents(bus *events.Bus, env string) *Controller {
+	c.events, c.env = bus, env
+	return c
+}
+
+// RunWhileLeader registers fn to run whenever this replica becomes leader;
+// its context is cancelled when leadership is lost. Call before Run.
+func (c *Controller) RunWhileLeader(fn func(ctx context.Context)) {
+	c.leaderTasks = append(c.leaderTasks, fn)
+}
+
 // Run starts the leader election loop. Only the elected leader runs idle timeout.
 func (c *Controller) Run(ctx context.Context) {
 	lock := &resourcelock.LeaseLock{
@@ -63,7 +91,10 @@ func (c *Controller) Run(ctx context.Context) {
 		RetryPeriod:     2 * time.Second,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
-				slog.Info("leader election: became leader, starting idle timeout loop", "id", c.leaderID)
+				slog.Info("leader election: became leader, starting idle timeout loop", "id", c.leaderID, "dry_run", c.dryRun)
+				for _, fn := range c.leaderTasks {
+					go fn(ctx)
+				}
 				c.runIdleLoop(ctx)
 			},
 			OnStoppedLeading: func() {
@@ -101,6 +132,9 @@ func (c *Controller) checkIdleTenants(ctx context.Context) {
 		return
 	}
 	for _, t := range tenants {
+		if t.KeepWarm {
+			continue
+		}
 		timeout := time.Duration(t.IdleTimeoutS) * time.Second
 		if timeout == 0 {
 			timeout = 5 * time.Minute
@@ -108,13 +142,23 @@ func (c *Controller) checkIdleTenants(ctx context.Context) {
 		if time.Since(t.LastActiveAt) < timeout {
 			continue
 		}
+		if c.dryRun {
+			slog.Info("idle check: would terminate idle tenant (dry run)", "tenant", t.TenantID, "idle_for", time.Since(t.LastActiveAt))
+			continue
+		}
 		slog.Info("idle check: terminating idle tenant", "tenant", t.TenantID, "idle_for", time.Since(t.LastActiveAt))
-		if err := c.k8s.DeletePod(ctx, t.PodName, t.Namespace, 30); err != nil {
+		grace := c.k8s.GracePeriod(k8sclient.OpIdle, t.Tier)
+		if err := c.k8s.DeletePod(ctx, t.PodName, t.Namespace, grace); err != nil {
 			slog.Error("idle check: delete pod failed", "tenant", t.TenantID, "err", err)
 			continue
 		}
 		if err := c.reg.UpdateStatus(ctx, t.TenantID, registry.StatusIdle, "", ""); err != nil {
 			slog.Error("idle check: update status failed", "tenant", t.TenantID, "err", err)
+			continue
 		}
+		c.events.Publish(events.TenantIdle, c.env, t.TenantID, map[string]any{
+			"reason":     "idle_timeout",
+			"idle_for_s": int64(time.Since(t.LastActiveAt).Seconds()),
+		})
 	}
 }
@@ -12,7 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 // TestIdleTimeout_TerminatesIdlePod verifies the controller terminates pods
@@ -61,6 +63,33 @@ func TestIdleTimeout_TerminatesIdlePod(t *testing.T) {
 	assert.Empty(t, tenant.PodIP)
 }
 
+// TestIdleTimeout_DryRun: idle tenants are only logged
+func TestIdleTimeout_DryRun(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-sleepy", Namespace: "tenants"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	reg := registry.NewMock()
+	k8s := k8sclient.New(cs, k8sclient.Config{})
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     "sleepy",
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-sleepy",
+		PodIP:        "10.0.0.9",
+		Namespace:    "tenants",
+		LastActiveAt: time.Now().Add(-10 * time.Minute),
+		IdleTimeoutS: 300,
+	})
+
+	lifecycle.NewForTest(reg, k8s).WithDryRun(true).CheckIdleTenants(context.Background())
+
+	_, err := cs.CoreV1().Pods("tenants").Get(context.Background(), "zeroclaw-sleepy", metav1.GetOptions{})
+	assert.NoError(t, err, "pod should be left running")
+	tenant, err := reg.GetTenant(context.Background(), "sleepy")
+	require.NoError(t, err)
+	assert.Equal(t, registry.StatusRunning, tenant.Status)
+}
+
 // TestIdleTimeout_DoesNotTerminateActivePod verifies active pods are not touched
 func TestIdleTimeout_DoesNotTerminateActivePod(t *testing.T) {
 	cs := fake.NewSimpleClientset()
@@ -92,3 +121,128 @@ func TestIdleTimeout_DoesNotTerminateActivePod(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, pod)
 }
+
+// TestIdleTimeout_UsesTierGracePeriod verifies idle termination honours the
+// per-tier grace period from the k8s client's GracePolicy
+func TestIdleTimeout_UsesTierGracePeriod(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	reg := registry.NewMock()
+	k8s := k8sclient.New(cs, k8sclient.Config{
+		Grace: k8sclient.GracePolicy{TierIdleS: map[string]int64{"premium": 120}},
+	})
+
+	var grace int64 = -1
+	cs.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		grace = *action.(k8stesting.DeleteActionImpl).DeleteOptions.GracePeriodSeconds
+		return false, nil, nil
+	})
+
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     "premium-tenant",
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-premium-tenant",
+		Namespace:    "tenants",
+		LastActiveAt: time.Now().Add(-10 * time.Minute),
+		IdleTimeoutS: 300,
+		Tier:         "premium",
+	})
+
+	ctrl := lifecycle.NewForTest(reg, k8s)
+	ctrl.CheckIdleTenants(context.Background())
+
+	assert.Equal(t, int64(120), grace)
+}
+
+// TestIdleTimeout_SkipsKeepWarm verifies keep-warm tenants are never idled out
+func TestIdleTimeout_SkipsKeepWarm(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	reg := registry.NewMock()
+	k8s := k8sclient.New(cs, k8sclient.Config{})
+	namespace := "tenants"
+
+	reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:     "always-on",
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-always-on",
+		Namespace:    namespace,
+		LastActiveAt: time.Now().Add(-time.Hour),
+		IdleTimeoutS: 300,
+		KeepWarm:     true,
+	})
+	cs.CoreV1().Pods(namespace).Create(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeroclaw-always-on", Namespace: namespace},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}, metav1.CreateOptions{})
+
+	ctrl := lifecycle.NewForTest(reg, k8s)
+	ctrl.CheckIdleTenants(context.Background())
+
+	_, err := cs.CoreV1().Pods(namespace).Get(context.Background(), "zeroclaw-always-on", metav1.GetOptions{})
+	assert.NoError(t, err, "keep-warm pod should not be deleted")
+	tenant, _ := reg.GetTenant(context.Background(), "always-on")
+	assert.Equal(t, registry.StatusRunning, tenant.Status)
+}
+
+func TestUsageMeter_CountsRunningPods(t *testing.T) {
+	reg := registry.NewMock()
+	ctx := context.Background()
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "running", Status: registry.StatusRunning, PodName: "zeroclaw-running"})
+	reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "asleep", Status: registry.StatusIdle})
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	meter := lifecycle.NewUsageMeter(reg)
+	meter.Meter(ctx, now, time.Minute)
+	meter.Meter(ctx, now.Add(time.Minute), 61*time.Second)
+
+	days, err := reg.ListUsage(ctx, "running", now, now)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, int64(121), days[0].PodSeconds)
+
+	days, _ = reg.ListUsage(ctx, "asleep", now, now)
+	assert.Empty(t, days)
+}
+
+func TestStuckPodReaper_ForceDeletesStuckPods(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	reg := registry.NewMock()
+	k8s := k8sclient.New(cs, k8sclient.Config{})
+	ctx := context.Background()
+	now := time.Now()
+
+	newPod := func(name, tenantID string, deletedAt time.Time) {
+		ts := metav1.NewTime(deletedAt)
+		cs.CoreV1().Pods("tenants").Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "tenants",
+				Labels:            map[string]string{"app": "zeroclaw", "tenant": tenantID},
+				DeletionTimestamp: &ts,
+				Finalizers:        []string{"example.com/teardown"},
+			},
+		}, metav1.CreateOptions{})
+	}
+	newPod("zeroclaw-stuck", "stuck", now.Add(-10*time.Minute))
+	newPod("zeroclaw-recent", "recent", now.Add(-time.Minute))
+
+	lifecycle.NewStuckPodReaper(reg, k8s, "tenants", 5*time.Minute, 10).Check(ctx, now)
+
+	_, err := cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-stuck", metav1.GetOptions{})
+	assert.Error(t, err, "stuck pod should have been force-deleted")
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, "zeroclaw-recent", metav1.GetOptions{})
+	assert.NoError(t, err, "pod within the threshold is left alone")
+
+	events, _ := reg.ListEvents(ctx, "stuck")
+	require.Len(t, events, 1)
+	assert.Equal(t, registry.EventStuckTerminating, events[0].Kind)
+	assert.Equal(t, "zeroclaw-stuck", events[0].Pod)
+	assert.Contains(t, events[0].Message, "example.com/teardown")
+
+	var patched bool
+	for _, a := range cs.Actions() {
+		if a.GetVerb() == "patch" {
+			patched = true
+		}
+	}
+	assert.True(t, patched, "finalizers cleared before the delete")
+}
@@ -0,0 +1,99 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// stuckCheckInterval is how often tenant pods are checked for a hung
+// termination
+const stuckCheckInterval = 30 * time.Second
+
+// StuckPodReaper force-deletes tenant pods stuck Terminating, which happens
+// when a Kata VM's teardown hangs. Until the pod is gone the tenant can't be
+// woken, since its replacement would take the same name. Each force delete
+// is recorded in the tenant's event log. Run it on the lifecycle leader only
+// (RunWhileLeader).
+type StuckPodReaper struct {
+	reg       registry.Client
+	k8s       *k8sclient.Client
+	namespace string
+	after     time.Duration
+	keep      int
+	dryRun    bool
+}
+
+// NewStuckPodReaper creates a reaper for pods in namespace still Terminating
+// longer than after past the end of their grace period, keeping the last
+// keep events per tenant
+func NewStuckPodReaper(reg registry.Client, k8s *k8sclient.Client, namespace string, after time.Duration, keep int) *StuckPodReaper {
+	if keep <= 0 {
+		keep = 50
+	}
+	return &StuckPodReaper{reg: reg, k8s: k8s, namespace: namespace, after: after, keep: keep}
+}
+
+// WithDryRun makes the reaper log the pods it would force-delete without
+// deleting them
+func (r *StuckPodReaper) WithDryRun(dryRun bool) *StuckPodReaper {
+	r.dryRun = dryRun
+	return r
+}
+
+// Run checks for stuck pods every 30s until ctx is cancelled.
+func (r *StuckPodReaper) Run(ctx context.Context) {
+	slog.Info("stuck pod reaper: starting", "after", r.after, "dry_run", r.dryRun)
+	ticker := time.NewTicker(stuckCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Check(ctx, now)
+		}
+	}
+}
+
+// Check force-deletes the pods stuck Terminating at now
+func (r *StuckPodReaper) Check(ctx context.Context, now time.Time) {
+	pods, err := r.k8s.StuckTerminatingPods(ctx, r.namespace, r.after, now)
+	if err != nil {
+		slog.Error("stuck pod reaper: list pods failed", "err", err)
+		return
+	}
+	for _, pod := range pods {
+		tenantID := pod.Labels["tenant"]
+		stuckFor := now.Sub(pod.DeletionTimestamp.Time).Round(time.Second)
+		if r.dryRun {
+			slog.Info("stuck pod reaper: would force-delete pod (dry run)", "tenant", tenantID, "pod", pod.Name, "stuck_for", stuckFor)
+			continue
+		}
+		slog.Warn("stuck pod reaper: force-deleting pod", "tenant", tenantID, "pod", pod.Name, "stuck_for", stuckFor, "finalizers", pod.Finalizers)
+		if err := r.k8s.ForceDeletePod(ctx, pod); err != nil {
+			slog.Error("stuck pod reaper: force delete failed", "tenant", tenantID, "pod", pod.Name, "err", err)
+			continue
+		}
+		if tenantID == "" {
+			continue
+		}
+		msg := fmt.Sprintf("still terminating %s after its grace period; force-deleted", stuckFor)
+		if len(pod.Finalizers) > 0 {
+			msg += fmt.Sprintf(", finalizers %v cleared", pod.Finalizers)
+		}
+		ev := registry.TenantEvent{
+			Time:    now.UTC(),
+			Kind:    registry.EventStuckTerminating,
+			Pod:     pod.Name,
+			Message: msg,
+		}
+		if err := r.reg.RecordEvent(ctx, tenantID, ev, r.keep); err != nil {
+			slog.Error("stuck pod reaper: record event failed", "tenant", tenantID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// meterInterval is how often running pods' time is added to usage
+const meterInterval = time.Minute
+
+// UsageMeter adds the time each tenant's pod runs to its usage
+// (registry.Usage.PodSeconds). It samples running tenants every minute, so a
+// pod's metered time is accurate to about a minute per start and stop. Run it
+// on the lifecycle leader only (RunWhileLeader), so time is counted once.
+type UsageMeter struct {
+	reg registry.Client
+}
+
+// NewUsageMeter creates a meter recording into reg
+func NewUsageMeter(reg registry.Client) *UsageMeter {
+	return &UsageMeter{reg: reg}
+}
+
+// Run meters running pods every minute until ctx is cancelled. The time
+// between a leader stepping down and the next taking over goes unmetered.
+func (m *UsageMeter) Run(ctx context.Context) {
+	ticker := time.NewTicker(meterInterval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Meter(ctx, now, now.Sub(last))
+			last = now
+		}
+	}
+}
+
+// Meter adds elapsed to the pod time of every tenant running at now
+func (m *UsageMeter) Meter(ctx context.Context, now time.Time, elapsed time.Duration) {
+	secs := int64(elapsed.Round(time.Second) / time.Second)
+	if secs <= 0 {
+		return
+	}
+	tenants, err := m.reg.ListByStatus(ctx, registry.StatusRunning)
+	if err != nil {
+		slog.Error("usage meter: list tenants failed", "err", err)
+		return
+	}
+	for _, t := range tenants {
+		if t.PodName == "" {
+			continue
+		}
+		if err := m.reg.AddUsage(ctx, t.TenantID, now, registry.Usage{PodSeconds: secs}); err != nil {
+			slog.Warn("usage meter: record pod time failed", "tenant", t.TenantID, "err", err)
+		}
+	}
+}
@@ -18,17 +18,24 @@ type Locker interface {
 
 // RedisLocker implements Locker using Redis SET NX EX
 type RedisLocker struct {
-	rdb *redis.Client
+	rdb    *redis.Client
+	prefix string
 }
 
 func New(rdb *redis.Client) *RedisLocker {
 	return &RedisLocker{rdb: rdb}
 }
 
+// NewWithPrefix returns a locker whose keys are namespaced by prefix, so
+// environments sharing one Redis don't contend for each other's tenants.
+func NewWithPrefix(rdb *redis.Client, prefix string) *RedisLocker {
+	return &RedisLocker{rdb: rdb, prefix: prefix}
+}
+
 // AcquireWakeLock tries to acquire an exclusive wake lock for tenantID.
 // Returns true if acquired, false if already held by another replica.
 func (l *RedisLocker) AcquireWakeLock(ctx context.Context, tenantID string, ttl time.Duration) (bool, error) {
-	key := keyPrefix + tenantID
+	key := l.prefix + keyPrefix + tenantID
 	ok, err := l.rdb.SetNX(ctx, key, "1", ttl).Result()
 	if err != nil {
 		return false, fmt.Errorf("redis SetNX: %w", err)
@@ -38,7 +45,7 @@ func (l *RedisLocker) AcquireWakeLock(ctx context.Context, tenantID string, ttl
 
 // ReleaseWakeLock releases the wake lock for tenantID
 func (l *RedisLocker) ReleaseWakeLock(ctx context.Context, tenantID string) error {
-	key := keyPrefix + tenantID
+	key := l.prefix + keyPrefix + tenantID
 	return l.rdb.Del(ctx, key).Err()
 }
 
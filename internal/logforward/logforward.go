@@ -0,0 +1,175 @@
+// Package logforward tails running tenants' agent logs and forwards error
+// lines to owners who opted in (TenantRecord.LogForward) through the
+// notification service. It runs on the lifecycle leader only, so each error
+// is read and reported once.
+package logforward
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+const (
+	checkInterval = 30 * time.Second
+	maxLines      = 20       // lines per notification
+	maxLineLen    = 300      // characters per line
+	maxLogBytes   = 64 << 10 // log bytes read per tenant per check
+)
+
+// errorLineRe matches error-level lines in common agent log formats
+// (tracing/log "ERROR", logfmt level=error, JSON "level":"error", panics).
+var errorLineRe = regexp.MustCompile(`\b(ERROR|FATAL|PANIC)\b|(?i:level=(error|fatal)\b|"level"\s*:\s*"(error|fatal)")|^panic:`)
+
+// LogSource reads a pod's logs; *k8s.Client implements it.
+type LogSource interface {
+	PodLogs(ctx context.Context, name, namespace string, since time.Time, limitBytes int64) (string, error)
+}
+
+// Watcher forwards agent error logs, at most one notification per tenant per
+// window; lines seen while rate-limited are counted and reported as omitted.
+type Watcher struct {
+	reg       registry.Client
+	logs      LogSource
+	notifier  notify.Notifier
+	namespace string
+	window    time.Duration
+
+	cursors  map[string]time.Time // tenant → logs read up to
+	lastSent map[string]time.Time
+	dropped  map[string]int
+}
+
+func New(reg registry.Client, logs LogSource, notifier notify.Notifier, namespace string, window time.Duration) *Watcher {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &Watcher{
+		reg:       reg,
+		logs:      logs,
+		notifier:  notifier,
+		namespace: namespace,
+		window:    window,
+		cursors:   make(map[string]time.Time),
+		lastSent:  make(map[string]time.Time),
+		dropped:   make(map[string]int),
+	}
+}
+
+// Run checks logs every 30s until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	slog.Info("log forwarder: starting", "window", w.window)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx, time.Now())
+		}
+	}
+}
+
+// Check performs one pass over running tenants that opted in.
+func (w *Watcher) Check(ctx context.Context, now time.Time) {
+	tenants, err := w.reg.ListByStatus(ctx, registry.StatusRunning)
+	if err != nil {
+		slog.Error("log forwarder: list tenants failed", "err", err)
+		return
+	}
+
+	active := make(map[string]bool, len(tenants))
+	for _, t := range tenants {
+		if t.LogForward == nil || t.PodName == "" {
+			continue
+		}
+		active[t.TenantID] = true
+		w.checkTenant(ctx, t, now)
+	}
+
+	// Forget tenants that stopped running or opted out
+	for id := range w.cursors {
+		if !active[id] {
+			delete(w.cursors, id)
+			delete(w.dropped, id)
+		}
+	}
+	for id, last := range w.lastSent {
+		if !active[id] && now.Sub(last) >= w.window {
+			delete(w.lastSent, id)
+		}
+	}
+}
+
+func (w *Watcher) checkTenant(ctx context.Context, t *registry.TenantRecord, now time.Time) {
+	since, ok := w.cursors[t.TenantID]
+	if !ok {
+		// First sight (new pod or new leader): don't replay old history
+		since = now.Add(-checkInterval)
+	}
+	ns := t.Namespace
+	if ns == "" {
+		ns = w.namespace
+	}
+	logs, err := w.logs.PodLogs(ctx, t.PodName, ns, since, maxLogBytes)
+	if err != nil {
+		slog.Warn("log forwarder: read logs failed", "tenant", t.TenantID, "pod", t.PodName, "err", err)
+		return
+	}
+	w.cursors[t.TenantID] = now
+
+	lines := ErrorLines(logs)
+	if len(lines) == 0 {
+		return
+	}
+	if last, ok := w.lastSent[t.TenantID]; ok && now.Sub(last) < w.window {
+		w.dropped[t.TenantID] += len(lines)
+		return
+	}
+
+	n := notify.Notification{
+		TenantID: t.TenantID,
+		Kind:     "agent_errors",
+		Title:    i18n.T(t.Locale, i18n.AgentErrors, t.TenantID, len(lines)+w.dropped[t.TenantID], i18n.Clock(now, t.Timezone)),
+		Dropped:  w.dropped[t.TenantID],
+		Time:     now.UTC(),
+		Locale:   t.Locale,
+	}
+	if len(lines) > maxLines {
+		n.Dropped += len(lines) - maxLines
+		lines = lines[len(lines)-maxLines:] // most recent
+	}
+	n.Lines = lines
+
+	if err := w.notifier.Notify(ctx, t, n); err != nil {
+		slog.Warn("log forwarder: notify failed", "tenant", t.TenantID, "err", err)
+	}
+	// Count failed deliveries against the window too, so a broken webhook
+	// isn't retried every 30s
+	w.lastSent[t.TenantID] = now
+	w.dropped[t.TenantID] = 0
+}
+
+// ErrorLines returns the error-level lines in logs, each truncated to
+// maxLineLen characters.
+func ErrorLines(logs string) []string {
+	var out []string
+	for _, line := range strings.Split(logs, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !errorLineRe.MatchString(line) {
+			continue
+		}
+		if r := []rune(line); len(r) > maxLineLen {
+			line = string(r[:maxLineLen]) + "…"
+		}
+		out = append(out, line)
+	}
+	return out
+}
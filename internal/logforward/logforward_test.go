@@ -0,0 +1,102 @@
+package logforward
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLogs struct {
+	logs  string
+	since time.Time
+}
+
+func (f *fakeLogs) PodLogs(_ context.Context, _, _ string, since time.Time, _ int64) (string, error) {
+	f.since = since
+	return f.logs, nil
+}
+
+type fakeNotifier struct{ sent []notify.Notification }
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *registry.TenantRecord, n notify.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func newRunningTenant(t *testing.T, reg *registry.MockClient, id string, fwd *registry.LogForwardConfig) {
+	t.Helper()
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:   id,
+		Status:     registry.StatusRunning,
+		PodName:    "zeroclaw-" + id,
+		Namespace:  "tenants",
+		LogForward: fwd,
+	}))
+}
+
+func TestErrorLines(t *testing.T) {
+	logs := strings.Join([]string{
+		"2026-01-01T00:00:00Z  INFO zeroclaw: started",
+		"2026-01-01T00:00:01Z ERROR zeroclaw::provider: bedrock throttled",
+		`time=2026-01-01 level=error msg="tool failed"`,
+		`{"level":"error","msg":"db locked"}`,
+		"level=warn msg=\"retrying\" err=timeout",
+		"panic: runtime error: index out of range",
+		"user said: I got an error",
+		"",
+	}, "\n")
+
+	lines := ErrorLines(logs)
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "bedrock throttled")
+	assert.Contains(t, lines[3], "panic:")
+
+	long := ErrorLines("ERROR " + strings.Repeat("x", 1000))
+	require.Len(t, long, 1)
+	assert.Equal(t, maxLineLen+1, len([]rune(long[0])))
+}
+
+func TestCheck_ForwardsAndRateLimits(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice", &registry.LogForwardConfig{TelegramChatID: 42})
+	newRunningTenant(t, reg, "bob", nil) // not opted in
+	logs := &fakeLogs{logs: "ERROR one\nINFO fine\nERROR two\n"}
+	n := &fakeNotifier{}
+	w := New(reg, logs, n, "tenants", 5*time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+
+	w.Check(ctx, now)
+	require.Len(t, n.sent, 1)
+	assert.Equal(t, "alice", n.sent[0].TenantID)
+	assert.Equal(t, []string{"ERROR one", "ERROR two"}, n.sent[0].Lines)
+	assert.Equal(t, now.Add(-checkInterval), logs.since, "first check reads only the last interval")
+
+	// Within the window: counted, not sent
+	w.Check(ctx, now.Add(30*time.Second))
+	require.Len(t, n.sent, 1)
+	assert.Equal(t, now, logs.since)
+
+	// After the window: the omitted lines are reported
+	w.Check(ctx, now.Add(6*time.Minute))
+	require.Len(t, n.sent, 2)
+	assert.Equal(t, 2, n.sent[1].Dropped)
+}
+
+func TestCheck_CapsLines(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice", &registry.LogForwardConfig{WebhookURL: "https://example.com/hook"})
+	logs := &fakeLogs{logs: strings.Repeat("ERROR boom\n", maxLines+5)}
+	n := &fakeNotifier{}
+
+	New(reg, logs, n, "tenants", 0).Check(context.Background(), time.Now())
+	require.Len(t, n.sent, 1)
+	assert.Len(t, n.sent[0].Lines, maxLines)
+	assert.Equal(t, 5, n.sent[0].Dropped)
+}
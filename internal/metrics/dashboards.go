@@ -0,0 +1,247 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DashboardUID is stable so re-importing the dashboard replaces it
+const DashboardUID = "agentic-tenancy-router"
+
+// sel is the label selector every dashboard query filters on
+const sel = `env=~"$env",tenant=~"$tenant"`
+
+// Dashboard is the subset of Grafana's dashboard JSON model we emit
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a dashboard template variable
+type Variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label,omitempty"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Datasource *Datasource `json:"datasource,omitempty"`
+	IncludeAll bool        `json:"includeAll,omitempty"`
+	Multi      bool        `json:"multi,omitempty"`
+	AllValue   string      `json:"allValue,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+}
+
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type Panel struct {
+	ID          int         `json:"id"`
+	Type        string      `json:"type"`
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	Datasource  Datasource  `json:"datasource"`
+	GridPos     GridPos     `json:"gridPos"`
+	FieldConfig FieldConfig `json:"fieldConfig"`
+	Targets     []Target    `json:"targets"`
+}
+
+type GridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type FieldConfig struct {
+	Defaults FieldDefaults `json:"defaults"`
+}
+
+type FieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+type Target struct {
+	RefID        string     `json:"refId"`
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat,omitempty"`
+	Datasource   Datasource `json:"datasource"`
+}
+
+// promDS points panels at the datasource picked in the dashboard's
+// datasource variable
+var promDS = Datasource{Type: "prometheus", UID: "${datasource}"}
+
+// RouterDashboard builds the router dashboard: traffic, latency, wakes and
+// Telegram delivery, filterable by environment and tenant.
+func RouterDashboard() Dashboard {
+	panels := []struct {
+		title, desc, unit string
+		targets           [][2]string // expr, legend
+	}{
+		{"Requests by status", "Inbound webhook and relay requests per second", "reqps", [][2]string{
+			{fmt.Sprintf(`sum by (status) (rate(%s{%s}[5m]))`, RouterRequestsName, sel), "{{status}}"},
+		}},
+		{"Error ratio", "Share of requests answered with a 5xx", "percentunit", [][2]string{
+			{fmt.Sprintf(`sum(rate(%[1]s{%[2]s,status=~"5.."}[5m])) / sum(rate(%[1]s{%[2]s}[5m]))`, RouterRequestsName, sel), "5xx"},
+		}},
+		{"Forward latency", "Time for tenant pods to answer forwarded messages", "s", [][2]string{
+			{fmt.Sprintf(`histogram_quantile(0.5, sum by (le) (rate(%s_bucket{%s,result="ok"}[5m])))`, RouterForwardDurationName, sel), "p50"},
+			{fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(%s_bucket{%s,result="ok"}[5m])))`, RouterForwardDurationName, sel), "p95"},
+			{fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s_bucket{%s,result="ok"}[5m])))`, RouterForwardDurationName, sel), "p99"},
+		}},
+		{"Wakes", "Wakes triggered by inbound messages", "short", [][2]string{
+			{fmt.Sprintf(`sum by (result) (increase(%s{%s}[5m]))`, RouterWakesName, sel), "{{result}}"},
+		}},
+		{"Telegram send failures", "Replies Telegram didn't accept", "short", [][2]string{
+			{fmt.Sprintf(`sum by (tenant) (increase(%s{%s}[5m]))`, RouterTelegramFailuresName, sel), "{{tenant}}"},
+		}},
+		{"Cache hit ratio", "Endpoint and bot token lookups served from Redis", "percentunit", [][2]string{
+			{fmt.Sprintf(`sum by (cache) (rate(%[1]s{env=~"$env",result="hit"}[5m])) / sum by (cache) (rate(%[1]s{env=~"$env"}[5m]))`, RouterCacheLookupsName), "{{cache}}"},
+		}},
+		{"Cache invalidations and wasted wakes", "Cache entries dropped, and wakes that found the pod already running", "short", [][2]string{
+			{fmt.Sprintf(`sum by (cache, reason) (increase(%s{env=~"$env"}[5m]))`, RouterCacheInvalidationsName), "{{cache}} {{reason}}"},
+			{fmt.Sprintf(`sum(increase(%s{env=~"$env"}[5m]))`, RouterWakesRunningName), "wake, pod already running"},
+		}},
+		{"Busiest tenants", "Top 10 tenants by request rate", "reqps", [][2]string{
+			{fmt.Sprintf(`topk(10, sum by (tenant) (rate(%s{%s}[5m])))`, RouterRequestsName, sel), "{{tenant}}"},
+		}},
+		{"Rate-limited updates", "Updates dropped for exceeding the tenant's inbound rate limit", "short", [][2]string{
+			{fmt.Sprintf(`sum by (tenant) (increase(%s{%s}[5m])) > 0`, RouterRateLimitedName, sel), "{{tenant}}"},
+		}},
+		{"Forward errors by tenant", "Forwards that failed, e.g. the pod went away", "short", [][2]string{
+			{fmt.Sprintf(`sum by (tenant) (increase(%s_count{%s,result="error"}[5m])) > 0`, RouterForwardDurationName, sel), "{{tenant}}"},
+		}},
+	}
+
+	d := Dashboard{
+		UID:           DashboardUID,
+		Title:         "Agentic Tenancy / Router",
+		Tags:          []string{"agentic-tenancy"},
+		SchemaVersion: 39,
+		Refresh:       "1m",
+		Time:          TimeRange{From: "now-6h", To: "now"},
+		Templating: Templating{List: []Variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+			{Name: "env", Label: "Environment", Type: "query", Datasource: &promDS, Refresh: 2,
+				Query: fmt.Sprintf("label_values(%s, env)", RouterRequestsName), IncludeAll: true, Multi: true, AllValue: ".*"},
+			{Name: "tenant", Label: "Tenant", Type: "query", Datasource: &promDS, Refresh: 2,
+				Query: fmt.Sprintf(`label_values(%s{env=~"$env"}, tenant)`, RouterRequestsName), IncludeAll: true, Multi: true, AllValue: ".*"},
+		}},
+	}
+	for i, p := range panels {
+		panel := Panel{
+			ID:          i + 1,
+			Type:        "timeseries",
+			Title:       p.title,
+			Description: p.desc,
+			Datasource:  promDS,
+			// Two panels per row
+			GridPos:     GridPos{X: (i % 2) * 12, Y: (i / 2) * 8, W: 12, H: 8},
+			FieldConfig: FieldConfig{Defaults: FieldDefaults{Unit: p.unit}},
+		}
+		for j, t := range p.targets {
+			panel.Targets = append(panel.Targets, Target{
+				RefID:        string(rune('A' + j)),
+				Expr:         t[0],
+				LegendFormat: t[1],
+				Datasource:   promDS,
+			})
+		}
+		d.Panels = append(d.Panels, panel)
+	}
+	return d
+}
+
+// RuleFile is a Prometheus rule file
+type RuleFile struct {
+	Groups []RuleGroup `json:"groups"`
+}
+
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+type Rule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RouterAlertRules are alerts on the router metrics. Thresholds are starting
+// points; tune them to the deployment's traffic.
+func RouterAlertRules() RuleFile {
+	return RuleFile{Groups: []RuleGroup{{
+		Name: "agentic-tenancy-router",
+		Rules: []Rule{
+			{
+				Alert:  "RouterHighErrorRate",
+				Expr:   fmt.Sprintf(`sum by (env) (rate(%[1]s{status=~"5.."}[5m])) / sum by (env) (rate(%[1]s[5m])) > 0.05`, RouterRequestsName),
+				For:    "10m",
+				Labels: map[string]string{"severity": "critical"},
+				Annotations: map[string]string{
+					"summary":     "Router is failing over 5% of requests in env {{ $labels.env }}",
+					"description": "Check router logs and orchestrator health; Telegram retries failed webhooks, so users see delays.",
+				},
+			},
+			{
+				Alert:  "RouterWakeFailures",
+				Expr:   fmt.Sprintf(`sum by (env, tenant) (increase(%s{result="error"}[15m])) > 3`, RouterWakesName),
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Tenant {{ $labels.tenant }} failed to wake {{ $value }} times in 15 minutes",
+					"description": "Run `ztm tenant describe {{ $labels.tenant }}` for recent wake attempts.",
+				},
+			},
+			{
+				Alert:  "RouterSlowForwards",
+				Expr:   fmt.Sprintf(`histogram_quantile(0.95, sum by (env, le) (rate(%s_bucket{result="ok"}[10m]))) > 120`, RouterForwardDurationName),
+				For:    "15m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "p95 agent reply time in env {{ $labels.env }} is over 2 minutes",
+					"description": "Tenant pods are slow to answer; check LLM provider latency and node pressure.",
+				},
+			},
+			{
+				Alert:  "RouterTelegramSendFailures",
+				Expr:   fmt.Sprintf(`sum by (env, tenant) (increase(%s[15m])) > 5`, RouterTelegramFailuresName),
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Telegram is rejecting replies for tenant {{ $labels.tenant }}",
+					"description": "Usually a revoked bot token or a blocked chat; look for `telegram sendMessage failed` in router logs.",
+				},
+			},
+		},
+	}}}
+}
+
+// DashboardJSON renders RouterDashboard for import into Grafana
+func DashboardJSON() ([]byte, error) {
+	return json.MarshalIndent(RouterDashboard(), "", "  ")
+}
+
+// AlertRulesYAML renders RouterAlertRules as a Prometheus rule file
+func AlertRulesYAML() ([]byte, error) {
+	return yaml.Marshal(RouterAlertRules())
+}
@@ -0,0 +1,83 @@
+// Package metrics defines the Prometheus collectors the router exports and
+// the Grafana dashboard and alert rules built on them, so the names queried
+// by dashboards and alerts can't drift from the names emitted.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metric names. Per-tenant series carry the environment name ("" for the
+// default environment) because tenant IDs are only unique within one.
+const (
+	RouterRequestsName           = "router_requests_total"
+	RouterForwardDurationName    = "router_forward_duration_seconds"
+	RouterWakesName              = "router_wakes_total"
+	RouterTelegramFailuresName   = "router_telegram_send_failures_total"
+	RouterCacheLookupsName       = "router_cache_lookups_total"
+	RouterCacheInvalidationsName = "router_cache_invalidations_total"
+	RouterWakesRunningName       = "router_wakes_already_running_total"
+	RouterRateLimitedName        = "router_rate_limited_total"
+)
+
+var (
+	RouterRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterRequestsName,
+		Help: "Inbound requests by tenant, source (telegram, slack, relay, webchat) and response status.",
+	}, []string{"env", "tenant", "source", "status"})
+
+	RouterForwardDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: RouterForwardDurationName,
+		Help: "Time for a tenant's pod to answer a forwarded message, by result (ok, error).",
+		// Agent replies include LLM calls: 100ms up to ~7 minutes
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 13),
+	}, []string{"env", "tenant", "result"})
+
+	RouterWakes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterWakesName,
+		Help: "Wakes triggered by inbound messages on an endpoint cache miss, by result (ok, error).",
+	}, []string{"env", "tenant", "result"})
+
+	RouterTelegramFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterTelegramFailuresName,
+		Help: "Telegram sendMessage calls that failed or returned a non-2xx status.",
+	}, []string{"env", "tenant"})
+
+	RouterCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterCacheLookupsName,
+		Help: "Redis cache lookups by cache (endpoint, bot_token) and result (hit, miss).",
+	}, []string{"env", "cache", "result"})
+
+	RouterCacheInvalidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterCacheInvalidationsName,
+		Help: "Cache entries the router dropped, by cache (endpoint, bot_token) and reason (forward_failed, reset_failed, sleep, unauthorized).",
+	}, []string{"env", "cache", "reason"})
+
+	RouterWakesRunning = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterWakesRunningName,
+		Help: "Wakes that found the tenant's pod already running: the endpoint cache missed a live pod, and the user waited on the wake path for nothing.",
+	}, []string{"env"})
+
+	RouterRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RouterRateLimitedName,
+		Help: "Telegram updates dropped for exceeding the tenant's inbound rate limit.",
+	}, []string{"env", "tenant"})
+)
+
+// Router returns every router collector
+func Router() []prometheus.Collector {
+	return []prometheus.Collector{
+		RouterRequests,
+		RouterForwardDuration,
+		RouterWakes,
+		RouterTelegramFailures,
+		RouterCacheLookups,
+		RouterCacheInvalidations,
+		RouterWakesRunning,
+		RouterRateLimited,
+	}
+}
+
+// RegisterRouter registers the router collectors with r, typically
+// prometheus.DefaultRegisterer. It panics if any is already registered.
+func RegisterRouter(r prometheus.Registerer) {
+	r.MustRegister(Router()...)
+}
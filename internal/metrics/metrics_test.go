@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueriesUseEmittedNames: every metric a dashboard panel or alert queries
+// is one the router registers
+func TestQueriesUseEmittedNames(t *testing.T) {
+	emitted := map[string]bool{}
+	ch := make(chan *prometheus.Desc, 16)
+	go func() {
+		for _, c := range Router() {
+			c.Describe(ch)
+		}
+		close(ch)
+	}()
+	fqName := regexp.MustCompile(`fqName: "(\w+)"`)
+	for d := range ch {
+		m := fqName.FindStringSubmatch(d.String())
+		require.NotNil(t, m)
+		emitted[m[1]] = true
+	}
+
+	var exprs []string
+	for _, p := range RouterDashboard().Panels {
+		require.NotEmpty(t, p.Targets, p.Title)
+		for _, tg := range p.Targets {
+			exprs = append(exprs, tg.Expr)
+		}
+	}
+	for _, g := range RouterAlertRules().Groups {
+		for _, r := range g.Rules {
+			exprs = append(exprs, r.Expr)
+		}
+	}
+
+	metricRef := regexp.MustCompile(`\brouter_\w+`)
+	for _, expr := range exprs {
+		for _, name := range metricRef.FindAllString(expr, -1) {
+			base := name
+			for _, suffix := range []string{"_bucket", "_count", "_sum"} {
+				if strings.HasSuffix(name, suffix) && emitted[strings.TrimSuffix(name, suffix)] {
+					base = strings.TrimSuffix(name, suffix)
+				}
+			}
+			assert.True(t, emitted[base], "%s queries unknown metric %s", expr, name)
+		}
+	}
+}
+
+func TestRegisterRouter(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	RegisterRouter(reg)
+	assert.Panics(t, func() { RegisterRouter(reg) }, "collectors are registered once")
+}
@@ -0,0 +1,124 @@
+// Package notify delivers orchestrator-originated messages to tenant owners
+// over the channels they opted in to: a Telegram chat reached through the
+// tenant's own bot, and/or an HTTPS webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+)
+
+// telegramMaxLen stays under Telegram's 4096-character message limit.
+const telegramMaxLen = 4000
+
+// Notification is a message for one tenant's owner.
+type Notification struct {
+	TenantID string    `json:"tenant_id"`
+	Kind     string    `json:"kind"` // e.g. "agent_errors"
+	Title    string    `json:"title"`
+	Lines    []string  `json:"lines,omitempty"`
+	Dropped  int       `json:"dropped,omitempty"` // lines omitted by truncation or rate limiting
+	Time     time.Time `json:"time"`
+	Locale   string    `json:"-"` // renders Text; webhook receivers get the raw fields
+}
+
+// Text renders n as a plain-text chat message.
+func (n Notification) Text() string {
+	var b strings.Builder
+	b.WriteString(n.Title)
+	for _, l := range n.Lines {
+		b.WriteString("\n")
+		b.WriteString(l)
+	}
+	if n.Dropped > 0 {
+		b.WriteString("\n")
+		b.WriteString(i18n.T(n.Locale, i18n.LinesOmitted, n.Dropped))
+	}
+	s := b.String()
+	if len(s) > telegramMaxLen {
+		s = strings.ToValidUTF8(s[:telegramMaxLen], "") + "…"
+	}
+	return s
+}
+
+// Notifier delivers a notification to a tenant's owner. Service implements
+// it; the watchers that raise notifications accept it so tests can record
+// them instead.
+type Notifier interface {
+	Notify(ctx context.Context, rec *registry.TenantRecord, n Notification) error
+}
+
+// Service sends notifications to the targets in a tenant's LogForwardConfig.
+type Service struct {
+	tg         *telegram.Client
+	secrets    secrets.Store
+	httpClient *http.Client
+}
+
+func New(tg *telegram.Client) *Service {
+	return &Service{tg: tg, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WithSecrets sets the store bot tokens referenced by tenant records are
+// read from
+func (s *Service) WithSecrets(store secrets.Store) *Service {
+	s.secrets = store
+	return s
+}
+
+// Notify delivers n to every target configured on rec. A tenant without a
+// LogForward config is a no-op. Errors from individual targets are joined.
+func (s *Service) Notify(ctx context.Context, rec *registry.TenantRecord, n Notification) error {
+	cfg := rec.LogForward
+	if cfg == nil {
+		return nil
+	}
+	var errs []error
+	if cfg.TelegramChatID != 0 && rec.HasBot() {
+		token, err := secrets.BotToken(ctx, s.secrets, rec)
+		if err == nil {
+			err = s.tg.SendMessage(ctx, token, cfg.TelegramChatID, n.Text())
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("telegram: %w", err))
+		}
+	}
+	if cfg.WebhookURL != "" {
+		if err := s.postWebhook(ctx, cfg.WebhookURL, n); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) postWebhook(ctx context.Context, url string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
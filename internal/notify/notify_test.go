@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify_Webhook(t *testing.T) {
+	var got Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	s := New(nil)
+	rec := &registry.TenantRecord{
+		TenantID:   "alice",
+		LogForward: &registry.LogForwardConfig{WebhookURL: srv.URL},
+	}
+	err := s.Notify(context.Background(), rec, Notification{TenantID: "alice", Kind: "agent_errors", Lines: []string{"ERROR boom"}})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.TenantID)
+	assert.Equal(t, []string{"ERROR boom"}, got.Lines)
+}
+
+func TestNotify_WebhookFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	rec := &registry.TenantRecord{LogForward: &registry.LogForwardConfig{WebhookURL: srv.URL}}
+	err := New(nil).Notify(context.Background(), rec, Notification{})
+	assert.ErrorContains(t, err, "status 502")
+}
+
+func TestNotify_NotOptedIn(t *testing.T) {
+	assert.NoError(t, New(nil).Notify(context.Background(), &registry.TenantRecord{}, Notification{}))
+}
+
+func TestNotification_Text(t *testing.T) {
+	n := Notification{Title: "alice: 2 errors", Lines: []string{"a", "b"}, Dropped: 3}
+	assert.Equal(t, "alice: 2 errors\na\nb\n… 3 more line(s) omitted", n.Text())
+
+	n.Locale = "es"
+	assert.Equal(t, "alice: 2 errors\na\nb\n… 3 línea(s) más omitida(s)", n.Text())
+
+	long := Notification{Title: strings.Repeat("x", telegramMaxLen+10)}
+	assert.Len(t, long.Text(), telegramMaxLen+len("…"))
+}
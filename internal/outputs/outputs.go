@@ -0,0 +1,64 @@
+// Package outputs keeps agent replies too long to send in chat under the
+// tenant's S3 prefix, and links to them with presigned URLs so the chat can
+// point at the full reply.
+package outputs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// LinkTTL is how long links last: the longest S3 allows. Links signed with
+// temporary credentials (e.g. IRSA) stop working when those expire, which
+// may be sooner.
+const LinkTTL = 7 * 24 * time.Hour
+
+// Store saves replies and links to them
+type Store interface {
+	// Save stores body under prefix and returns a link to it and when the
+	// link expires
+	Save(ctx context.Context, prefix string, body []byte) (url string, expires time.Time, err error)
+}
+
+// S3 implements Store with the tenant state bucket, under each tenant's
+// outputs/ folder
+type S3 struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+}
+
+func NewS3(client *s3.Client, bucket string) *S3 {
+	return &S3{client: client, presign: s3.NewPresignClient(client), bucket: bucket}
+}
+
+func (s *S3) Save(ctx context.Context, prefix string, body []byte) (string, time.Time, error) {
+	b := make([]byte, 4)
+	rand.Read(b)
+	key := prefix + "outputs/" + time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b) + ".txt"
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("text/plain; charset=utf-8"),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("s3 PutObject: %w", err)
+	}
+	expires := time.Now().Add(LinkTTL)
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(LinkTTL))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("s3 presign GetObject: %w", err)
+	}
+	return req.URL, expires, nil
+}
@@ -0,0 +1,269 @@
+// Package podwatch surfaces agent containers that run out of memory or
+// crash-loop. Each occurrence is appended to the tenant's event log and, for
+// owners who opted in to notifications (TenantRecord.LogForward), sent to
+// them, so a tier with more memory can be suggested before users notice their
+// bot forgetting things. Failed state flushes are logged too, for operators.
+// It runs on the lifecycle leader only, so each event is recorded once.
+package podwatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	checkInterval = 30 * time.Second
+	// agentContainer is the tenant pod's ZeroClaw container
+	agentContainer = "zeroclaw"
+	// stateSyncContainer is the sidecar that flushes state to S3
+	// (k8s.StateSyncContainer)
+	stateSyncContainer = "state-sync"
+
+	reasonOOMKilled = "OOMKilled"
+	reasonCrashLoop = "CrashLoopBackOff"
+)
+
+// PodSource reads a pod; *k8s.Client implements it.
+type PodSource interface {
+	GetPod(ctx context.Context, name, namespace string) (*corev1.Pod, error)
+}
+
+// Watcher checks running tenants' pods for OOM kills and crash loops.
+type Watcher struct {
+	reg       registry.Client
+	pods      PodSource
+	notifier  notify.Notifier // nil records events without notifying owners
+	namespace string
+	keep      int
+
+	oomSeen  map[string]time.Time // tenant → newest OOM kill reported
+	looping  map[string]string    // tenant → pod whose crash loop was reported
+	syncSeen map[string]time.Time // tenant → newest failed flush reported
+}
+
+// New creates a watcher keeping the last keep events per tenant.
+func New(reg registry.Client, pods PodSource, notifier notify.Notifier, namespace string, keep int) *Watcher {
+	if keep <= 0 {
+		keep = 50
+	}
+	return &Watcher{
+		reg:       reg,
+		pods:      pods,
+		notifier:  notifier,
+		namespace: namespace,
+		keep:      keep,
+		oomSeen:   make(map[string]time.Time),
+		looping:   make(map[string]string),
+		syncSeen:  make(map[string]time.Time),
+	}
+}
+
+// Run checks pods every 30s until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	slog.Info("pod watcher: starting")
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx, time.Now())
+		}
+	}
+}
+
+// Check performs one pass over running tenants.
+func (w *Watcher) Check(ctx context.Context, now time.Time) {
+	tenants, err := w.reg.ListByStatus(ctx, registry.StatusRunning)
+	if err != nil {
+		slog.Error("pod watcher: list tenants failed", "err", err)
+		return
+	}
+	active := make(map[string]bool, len(tenants))
+	for _, t := range tenants {
+		if t.PodName == "" {
+			continue
+		}
+		active[t.TenantID] = true
+		w.checkTenant(ctx, t, now)
+	}
+	// Forget tenants that stopped running
+	for id := range w.oomSeen {
+		if !active[id] {
+			delete(w.oomSeen, id)
+		}
+	}
+	for id := range w.looping {
+		if !active[id] {
+			delete(w.looping, id)
+		}
+	}
+	for id := range w.syncSeen {
+		if !active[id] {
+			delete(w.syncSeen, id)
+		}
+	}
+}
+
+func (w *Watcher) checkTenant(ctx context.Context, t *registry.TenantRecord, now time.Time) {
+	ns := t.Namespace
+	if ns == "" {
+		ns = w.namespace
+	}
+	pod, err := w.pods.GetPod(ctx, t.PodName, ns)
+	if err != nil {
+		slog.Warn("pod watcher: get pod failed", "tenant", t.TenantID, "pod", t.PodName, "err", err)
+		return
+	}
+	if pod == nil {
+		return // the reconciler resets tenants whose pod is gone
+	}
+	w.checkStateSync(ctx, t, pod, now)
+
+	var status *corev1.ContainerStatus
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == agentContainer {
+			status = &pod.Status.ContainerStatuses[i]
+		}
+	}
+	if status == nil {
+		return
+	}
+
+	if term := oomKill(status); term != nil {
+		since, ok := w.oomSeen[t.TenantID]
+		if !ok {
+			// First sight (new pod or new leader): don't replay old kills
+			since = now.Add(-checkInterval)
+		}
+		if term.FinishedAt.After(since) {
+			w.oomSeen[t.TenantID] = term.FinishedAt.Time
+			limit := memoryLimit(pod)
+			w.report(ctx, t, registry.TenantEvent{
+				Time:     term.FinishedAt.UTC(),
+				Kind:     registry.EventOOMKilled,
+				Pod:      pod.Name,
+				Restarts: status.RestartCount,
+				Message:  fmt.Sprintf("exit code %d, memory limit %s", term.ExitCode, limit),
+			}, i18n.T(t.Locale, i18n.AgentOOMKilled, t.TenantID, i18n.Clock(term.FinishedAt.Time, t.Timezone), limit))
+		}
+	}
+
+	if waiting := status.State.Waiting; waiting != nil && waiting.Reason == reasonCrashLoop {
+		if w.looping[t.TenantID] != pod.Name {
+			w.looping[t.TenantID] = pod.Name
+			w.report(ctx, t, registry.TenantEvent{
+				Time:     now.UTC(),
+				Kind:     registry.EventCrashLoop,
+				Pod:      pod.Name,
+				Restarts: status.RestartCount,
+				Message:  lastExit(status),
+			}, i18n.T(t.Locale, i18n.AgentCrashLoop, t.TenantID, status.RestartCount))
+		}
+	} else if status.State.Running != nil {
+		delete(w.looping, t.TenantID)
+	}
+}
+
+// checkStateSync reports the state-sync sidecar's failed periodic flushes,
+// each of which restarts it. Owners aren't notified: the fix is an
+// operator's.
+func (w *Watcher) checkStateSync(ctx context.Context, t *registry.TenantRecord, pod *corev1.Pod, now time.Time) {
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name != stateSyncContainer {
+			continue
+		}
+		term := s.LastTerminationState.Terminated
+		if term == nil || term.ExitCode == 0 {
+			return
+		}
+		since, ok := w.syncSeen[t.TenantID]
+		if !ok {
+			since = now.Add(-checkInterval)
+		}
+		if !term.FinishedAt.After(since) {
+			return
+		}
+		w.syncSeen[t.TenantID] = term.FinishedAt.Time
+		msg := term.Message
+		if msg == "" {
+			msg = fmt.Sprintf("exit code %d", term.ExitCode)
+		}
+		w.record(ctx, t, registry.TenantEvent{
+			Time:     term.FinishedAt.UTC(),
+			Kind:     registry.EventStateSyncFailed,
+			Pod:      pod.Name,
+			Restarts: s.RestartCount,
+			Message:  msg,
+		})
+	}
+}
+
+// record appends ev to the tenant's event log
+func (w *Watcher) record(ctx context.Context, t *registry.TenantRecord, ev registry.TenantEvent) {
+	slog.Warn("pod watcher: "+ev.Kind, "tenant", t.TenantID, "pod", ev.Pod, "restarts", ev.Restarts, "detail", ev.Message)
+	if err := w.reg.RecordEvent(ctx, t.TenantID, ev, w.keep); err != nil {
+		slog.Error("pod watcher: record event failed", "tenant", t.TenantID, "kind", ev.Kind, "err", err)
+	}
+}
+
+// report records ev in the tenant's event log and notifies the owner
+func (w *Watcher) report(ctx context.Context, t *registry.TenantRecord, ev registry.TenantEvent, title string) {
+	w.record(ctx, t, ev)
+	if w.notifier == nil {
+		return
+	}
+	n := notify.Notification{
+		TenantID: t.TenantID,
+		Kind:     ev.Kind,
+		Title:    title,
+		Time:     ev.Time,
+		Locale:   t.Locale,
+	}
+	if err := w.notifier.Notify(ctx, t, n); err != nil {
+		slog.Warn("pod watcher: notify failed", "tenant", t.TenantID, "kind", ev.Kind, "err", err)
+	}
+}
+
+// oomKill returns the container's OOM termination, current or previous
+func oomKill(s *corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	if t := s.State.Terminated; t != nil && t.Reason == reasonOOMKilled {
+		return t
+	}
+	if t := s.LastTerminationState.Terminated; t != nil && t.Reason == reasonOOMKilled {
+		return t
+	}
+	return nil
+}
+
+// lastExit describes why the container last stopped
+func lastExit(s *corev1.ContainerStatus) string {
+	t := s.LastTerminationState.Terminated
+	if t == nil {
+		return ""
+	}
+	if t.Reason != "" {
+		return fmt.Sprintf("last exit: %s (code %d)", t.Reason, t.ExitCode)
+	}
+	return fmt.Sprintf("last exit: code %d", t.ExitCode)
+}
+
+// memoryLimit is the agent container's memory limit, e.g. "512Mi"
+func memoryLimit(pod *corev1.Pod) string {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == agentContainer {
+			if m, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+				return m.String()
+			}
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,192 @@
+package podwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakePods struct{ pods map[string]*corev1.Pod }
+
+func (f *fakePods) GetPod(_ context.Context, name, _ string) (*corev1.Pod, error) {
+	return f.pods[name], nil
+}
+
+type fakeNotifier struct{ sent []notify.Notification }
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *registry.TenantRecord, n notify.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func newRunningTenant(t *testing.T, reg *registry.MockClient, id string) {
+	t.Helper()
+	require.NoError(t, reg.CreateTenant(context.Background(), &registry.TenantRecord{
+		TenantID:  id,
+		Status:    registry.StatusRunning,
+		PodName:   "zeroclaw-" + id,
+		Namespace: "tenants",
+	}))
+}
+
+func agentPod(name string, status corev1.ContainerStatus) *corev1.Pod {
+	status.Name = agentContainer
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: agentContainer,
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			}},
+		}}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+func oomStatus(finished time.Time, restarts int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		RestartCount: restarts,
+		State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     reasonOOMKilled,
+			ExitCode:   137,
+			FinishedAt: metav1.NewTime(finished),
+		}},
+	}
+}
+
+func TestCheck_RecordsOOMKillOnce(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice")
+	now := time.Now().Truncate(time.Second)
+	pods := &fakePods{pods: map[string]*corev1.Pod{
+		"zeroclaw-alice": agentPod("zeroclaw-alice", oomStatus(now.Add(-10*time.Second), 1)),
+	}}
+	n := &fakeNotifier{}
+	w := New(reg, pods, n, "tenants", 10)
+	ctx := context.Background()
+
+	w.Check(ctx, now)
+	events, err := reg.ListEvents(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, registry.EventOOMKilled, events[0].Kind)
+	assert.Equal(t, int32(1), events[0].Restarts)
+	assert.Contains(t, events[0].Message, "512Mi")
+	require.Len(t, n.sent, 1)
+	assert.Equal(t, registry.EventOOMKilled, n.sent[0].Kind)
+	assert.Contains(t, n.sent[0].Title, "512Mi")
+
+	// The same kill isn't reported again
+	w.Check(ctx, now.Add(checkInterval))
+	events, _ = reg.ListEvents(ctx, "alice")
+	assert.Len(t, events, 1)
+
+	// A newer one is
+	pods.pods["zeroclaw-alice"] = agentPod("zeroclaw-alice", oomStatus(now.Add(time.Minute), 2))
+	w.Check(ctx, now.Add(2*time.Minute))
+	events, _ = reg.ListEvents(ctx, "alice")
+	require.Len(t, events, 2)
+	assert.Equal(t, int32(2), events[0].Restarts, "newest first")
+	assert.Len(t, n.sent, 2)
+}
+
+func TestCheck_SkipsOldOOMKillOnFirstSight(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice")
+	now := time.Now()
+	pods := &fakePods{pods: map[string]*corev1.Pod{
+		"zeroclaw-alice": agentPod("zeroclaw-alice", oomStatus(now.Add(-time.Hour), 1)),
+	}}
+	w := New(reg, pods, nil, "tenants", 10)
+
+	w.Check(context.Background(), now)
+	events, _ := reg.ListEvents(context.Background(), "alice")
+	assert.Empty(t, events, "a kill from before the watcher started isn't replayed")
+}
+
+func TestCheck_ReportsCrashLoopOncePerPod(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice")
+	looping := corev1.ContainerStatus{
+		RestartCount: 5,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason: reasonCrashLoop,
+		}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:   "Error",
+			ExitCode: 1,
+		}},
+	}
+	pods := &fakePods{pods: map[string]*corev1.Pod{"zeroclaw-alice": agentPod("zeroclaw-alice", looping)}}
+	n := &fakeNotifier{}
+	w := New(reg, pods, n, "tenants", 10)
+	ctx := context.Background()
+	now := time.Now()
+
+	w.Check(ctx, now)
+	w.Check(ctx, now.Add(checkInterval))
+	events, _ := reg.ListEvents(ctx, "alice")
+	require.Len(t, events, 1)
+	assert.Equal(t, registry.EventCrashLoop, events[0].Kind)
+	assert.Equal(t, "last exit: Error (code 1)", events[0].Message)
+	require.Len(t, n.sent, 1)
+
+	// Recovering and looping again is a new event
+	pods.pods["zeroclaw-alice"] = agentPod("zeroclaw-alice", corev1.ContainerStatus{
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	})
+	w.Check(ctx, now.Add(2*checkInterval))
+	pods.pods["zeroclaw-alice"] = agentPod("zeroclaw-alice", looping)
+	w.Check(ctx, now.Add(3*checkInterval))
+	events, _ = reg.ListEvents(ctx, "alice")
+	assert.Len(t, events, 2)
+}
+
+func TestCheck_RecordsFailedStateFlush(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice")
+	now := time.Now().Truncate(time.Second)
+	pod := agentPod("zeroclaw-alice", corev1.ContainerStatus{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}})
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:         stateSyncContainer,
+		RestartCount: 1,
+		State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode:   1,
+			Message:    "cp: write error: No space left on device",
+			FinishedAt: metav1.NewTime(now.Add(-5 * time.Second)),
+		}},
+	}}
+	pods := &fakePods{pods: map[string]*corev1.Pod{"zeroclaw-alice": pod}}
+	n := &fakeNotifier{}
+	w := New(reg, pods, n, "tenants", 10)
+	ctx := context.Background()
+
+	w.Check(ctx, now)
+	w.Check(ctx, now.Add(checkInterval))
+	events, err := reg.ListEvents(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, events, 1, "reported once")
+	assert.Equal(t, registry.EventStateSyncFailed, events[0].Kind)
+	assert.Equal(t, "cp: write error: No space left on device", events[0].Message)
+	assert.Empty(t, n.sent, "operators fix it, not owners")
+}
+
+func TestCheck_IgnoresMissingPod(t *testing.T) {
+	reg := registry.NewMock()
+	newRunningTenant(t, reg, "alice")
+	w := New(reg, &fakePods{}, nil, "tenants", 10)
+
+	w.Check(context.Background(), time.Now())
+	events, _ := reg.ListEvents(context.Background(), "alice")
+	assert.Empty(t, events)
+}
@@ -2,25 +2,33 @@ package reconciler
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/shawn/agentic-tenancy/internal/contract"
 	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
 	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/saga"
 )
 
 // Reconciler periodically checks for state drift between DynamoDB and k8s.
 // If a tenant is marked as "running" in DynamoDB but its pod no longer exists
 // in k8s, the reconciler resets the tenant state to "idle" and cleans up
-// stale Redis endpoint cache entries.
+// stale Redis endpoint cache entries. It also recreates PV/PVCs that were
+// deleted out-of-band so the tenant's next wake doesn't hang on an unbound
+// volume, and finishes undoing multi-step operations (sagas) that an
+// orchestrator crashed during or failed to compensate.
 type Reconciler struct {
 	reg       registry.Client
 	k8s       *k8sclient.Client
 	rdb       *redis.Client
 	namespace string
 	interval  time.Duration
+	keyPrefix string // environment Redis prefix
+	dryRun    bool   // log drift instead of repairing it
+	// resumeSaga compensates an unfinished saga; nil leaves sagas alone
+	resumeSaga func(ctx context.Context, s *registry.Saga) error
 }
 
 // New creates a new Reconciler.
@@ -34,9 +42,30 @@ func New(reg registry.Client, k8s *k8sclient.Client, rdb *redis.Client, namespac
 	}
 }
 
+// WithRedisPrefix namespaces the router cache keys the reconciler clears, for
+// environments sharing one Redis.
+func (r *Reconciler) WithRedisPrefix(prefix string) *Reconciler {
+	r.keyPrefix = prefix
+	return r
+}
+
+// WithDryRun makes the reconciler log the resets and volume repairs it would
+// make without making them.
+func (r *Reconciler) WithDryRun(dryRun bool) *Reconciler {
+	r.dryRun = dryRun
+	return r
+}
+
+// WithSagas makes the reconciler compensate abandoned sagas with resume,
+// typically the API handler's ResumeSaga.
+func (r *Reconciler) WithSagas(resume func(ctx context.Context, s *registry.Saga) error) *Reconciler {
+	r.resumeSaga = resume
+	return r
+}
+
 // Run starts the reconciliation loop. It blocks until ctx is cancelled.
 func (r *Reconciler) Run(ctx context.Context) {
-	slog.Info("reconciler: starting", "interval", r.interval, "namespace", r.namespace)
+	slog.Info("reconciler: starting", "interval", r.interval, "namespace", r.namespace, "dry_run", r.dryRun)
 
 	// Run immediately on startup, then on ticker
 	r.reconcile(ctx)
@@ -57,6 +86,75 @@ func (r *Reconciler) Run(ctx context.Context) {
 
 // reconcile performs a single reconciliation pass.
 func (r *Reconciler) reconcile(ctx context.Context) {
+	r.reconcileSagas(ctx)
+	r.reconcilePods(ctx)
+	r.reconcileVolumes(ctx)
+}
+
+// reconcileSagas compensates sagas that failed to undo themselves or were
+// abandoned mid-run. Sagas go first so that a half-created tenant is removed
+// rather than having its volume repaired.
+func (r *Reconciler) reconcileSagas(ctx context.Context) {
+	if r.resumeSaga == nil {
+		return
+	}
+	sagas, err := r.reg.ListSagas(ctx)
+	if err != nil {
+		slog.Error("reconciler: failed to list sagas", "err", err)
+		return
+	}
+
+	now := time.Now()
+	for _, s := range sagas {
+		if ctx.Err() != nil {
+			return
+		}
+		if !saga.Resumable(s, now) {
+			continue
+		}
+		if s.State == registry.SagaDone {
+			r.clearSaga(ctx, s)
+			continue
+		}
+		if r.dryRun {
+			slog.Warn("reconciler: would compensate saga (dry run)",
+				"saga", s.ID,
+				"tenant", s.TenantID,
+				"steps", s.Steps,
+			)
+			continue
+		}
+		if err := r.resumeSaga(ctx, s); err != nil {
+			slog.Error("reconciler: failed to compensate saga",
+				"saga", s.ID,
+				"tenant", s.TenantID,
+				"err", err,
+			)
+			continue
+		}
+		slog.Warn("reconciler: compensated saga",
+			"saga", s.ID,
+			"tenant", s.TenantID,
+		)
+	}
+}
+
+// clearSaga deletes the record of a saga whose steps all succeeded but whose
+// own delete failed. It is never compensated.
+func (r *Reconciler) clearSaga(ctx context.Context, s *registry.Saga) {
+	if r.dryRun {
+		slog.Info("reconciler: would clear finished saga (dry run)", "saga", s.ID, "tenant", s.TenantID)
+		return
+	}
+	if err := r.reg.DeleteSaga(ctx, s.ID); err != nil {
+		slog.Error("reconciler: failed to clear finished saga", "saga", s.ID, "tenant", s.TenantID, "err", err)
+		return
+	}
+	slog.Info("reconciler: cleared finished saga", "saga", s.ID, "tenant", s.TenantID)
+}
+
+// reconcilePods resets running tenants whose pod no longer exists.
+func (r *Reconciler) reconcilePods(ctx context.Context) {
 	tenants, err := r.reg.ListByStatus(ctx, registry.StatusRunning)
 	if err != nil {
 		slog.Error("reconciler: failed to list running tenants", "err", err)
@@ -74,7 +172,12 @@ func (r *Reconciler) reconcile(ctx context.Context) {
 			return
 		}
 
-		podName := fmt.Sprintf("zeroclaw-%s", t.TenantID)
+		// Blue/green restarts give replacement pods a unique name, so trust
+		// the registry over the default name.
+		podName := t.PodName
+		if podName == "" {
+			podName = contract.PodName(t.TenantID)
+		}
 		exists, err := r.k8s.PodExists(ctx, podName, r.namespace)
 		if err != nil {
 			slog.Error("reconciler: failed to check pod existence",
@@ -89,6 +192,14 @@ func (r *Reconciler) reconcile(ctx context.Context) {
 			continue
 		}
 
+		if r.dryRun {
+			slog.Warn("reconciler: pod missing, would reset state (dry run)",
+				"tenant", t.TenantID,
+				"pod", podName,
+			)
+			continue
+		}
+
 		// Pod is missing — reset state to idle
 		slog.Warn("reconciler: pod missing, resetting state",
 			"tenant", t.TenantID,
@@ -104,7 +215,7 @@ func (r *Reconciler) reconcile(ctx context.Context) {
 		}
 
 		// Clean up stale Redis endpoint cache
-		cacheKey := fmt.Sprintf("router:endpoint:%s", t.TenantID)
+		cacheKey := r.keyPrefix + contract.EndpointPrefix + t.TenantID
 		if err := r.rdb.Del(ctx, cacheKey).Err(); err != nil {
 			slog.Error("reconciler: failed to delete Redis cache",
 				"tenant", t.TenantID,
@@ -119,3 +230,55 @@ func (r *Reconciler) reconcile(ctx context.Context) {
 		}
 	}
 }
+
+// reconcileVolumes repairs missing or unbindable PV/PVCs for every
+// non-terminated tenant, rebuilding them from the registry's S3 prefix.
+func (r *Reconciler) reconcileVolumes(ctx context.Context) {
+	tenants, err := r.reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("reconciler: failed to list tenants", "err", err)
+		return
+	}
+
+	for _, t := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if t.Status == registry.StatusTerminated {
+			continue
+		}
+		ns := t.Namespace
+		if ns == "" {
+			ns = r.namespace
+		}
+		if r.dryRun {
+			repairs, err := r.k8s.TenantVolumeRepairs(ctx, t.TenantID, ns)
+			if err != nil {
+				slog.Error("reconciler: failed to check tenant volume",
+					"tenant", t.TenantID,
+					"err", err,
+				)
+			} else if len(repairs) > 0 {
+				slog.Warn("reconciler: would repair tenant volume (dry run)",
+					"tenant", t.TenantID,
+					"actions", repairs,
+				)
+			}
+			continue
+		}
+		repaired, err := r.k8s.EnsureTenantVolume(ctx, t.TenantID, ns, t.S3Prefix)
+		if err != nil {
+			slog.Error("reconciler: failed to repair tenant volume",
+				"tenant", t.TenantID,
+				"err", err,
+			)
+			continue
+		}
+		if len(repaired) > 0 {
+			slog.Warn("reconciler: repaired tenant volume",
+				"tenant", t.TenantID,
+				"actions", repaired,
+			)
+		}
+	}
+}
@@ -111,3 +111,166 @@ func TestReconcile_IdleTenantIgnored(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, registry.StatusIdle, tenant.Status)
 }
+
+func TestReconcile_ReplacementPodNameNotReset(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+
+	// Blue/green restarts leave the tenant on a suffixed pod name
+	fakeCS := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "zeroclaw-ghi789-m1abc",
+			Namespace: "tenants",
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	k8s := k8sclient.New(fakeCS, k8sclient.Config{})
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:59999"})
+
+	err := reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "ghi789",
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-ghi789-m1abc",
+		PodIP:        "10.0.0.3",
+		Namespace:    "tenants",
+		CreatedAt:    time.Now(),
+		LastActiveAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	rec := New(reg, k8s, rdb, "tenants")
+	rec.reconcile(ctx)
+
+	tenant, err := reg.GetTenant(ctx, "ghi789")
+	require.NoError(t, err)
+	assert.Equal(t, registry.StatusRunning, tenant.Status)
+}
+
+func TestReconcile_RecreatesMissingVolume(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	fakeCS := fake.NewSimpleClientset()
+	k8s := k8sclient.New(fakeCS, k8sclient.Config{S3Bucket: "state-bucket"})
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:59999"})
+
+	err := reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "vol1",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		S3Prefix:     "tenants/vol1/",
+		CreatedAt:    time.Now(),
+		LastActiveAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	rec := New(reg, k8s, rdb, "tenants")
+	rec.reconcile(ctx)
+
+	pv, err := fakeCS.CoreV1().PersistentVolumes().Get(ctx, "pv-tenant-vol1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "tenants/vol1", pv.Spec.CSI.VolumeAttributes["subPath"])
+	assert.Equal(t, "state-bucket", pv.Spec.CSI.VolumeAttributes["bucketName"])
+	_, err = fakeCS.CoreV1().PersistentVolumeClaims("tenants").Get(ctx, "pvc-tenant-vol1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestReconcile_ClearsReleasedPVClaim(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	// PVC was deleted out-of-band: the Retain PV is Released and still
+	// references the old claim, so a new PVC could never bind to it.
+	fakeCS := fake.NewSimpleClientset(&corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-tenant-vol2"},
+		Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{Namespace: "tenants", Name: "pvc-tenant-vol2", UID: "old-uid"},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+	})
+	k8s := k8sclient.New(fakeCS, k8sclient.Config{})
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:59999"})
+
+	err := reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "vol2",
+		Status:       registry.StatusIdle,
+		Namespace:    "tenants",
+		CreatedAt:    time.Now(),
+		LastActiveAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	rec := New(reg, k8s, rdb, "tenants")
+	rec.reconcile(ctx)
+
+	pv, err := fakeCS.CoreV1().PersistentVolumes().Get(ctx, "pv-tenant-vol2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, pv.Spec.ClaimRef)
+	_, err = fakeCS.CoreV1().PersistentVolumeClaims("tenants").Get(ctx, "pvc-tenant-vol2", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+// TestReconcile_DryRun: drift is logged, not repaired
+func TestReconcile_DryRun(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	fakeCS := fake.NewSimpleClientset()
+	k8s := k8sclient.New(fakeCS, k8sclient.Config{S3Bucket: "state-bucket"})
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:59999"})
+
+	err := reg.CreateTenant(ctx, &registry.TenantRecord{
+		TenantID:     "drift",
+		Status:       registry.StatusRunning,
+		PodName:      "zeroclaw-drift",
+		PodIP:        "10.0.0.1",
+		Namespace:    "tenants",
+		S3Prefix:     "tenants/drift/",
+		CreatedAt:    time.Now(),
+		LastActiveAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	repairs, err := k8s.TenantVolumeRepairs(ctx, "drift", "tenants")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create PV", "create PVC"}, repairs)
+
+	New(reg, k8s, rdb, "tenants").WithDryRun(true).reconcile(ctx)
+
+	tenant, err := reg.GetTenant(ctx, "drift")
+	require.NoError(t, err)
+	assert.Equal(t, registry.StatusRunning, tenant.Status, "state should not be reset")
+	_, err = fakeCS.CoreV1().PersistentVolumes().Get(ctx, "pv-tenant-drift", metav1.GetOptions{})
+	assert.Error(t, err, "PV should not be created")
+	_, err = fakeCS.CoreV1().PersistentVolumeClaims("tenants").Get(ctx, "pvc-tenant-drift", metav1.GetOptions{})
+	assert.Error(t, err, "PVC should not be created")
+}
+
+func TestReconcile_ResumesAbandonedSagas(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	k8s := k8sclient.New(fake.NewSimpleClientset(), k8sclient.Config{})
+
+	stale := &registry.Saga{ID: "create-tenant:a", Kind: "create-tenant", TenantID: "a", State: registry.SagaRunning, UpdatedAt: time.Now().Add(-time.Hour)}
+	fresh := &registry.Saga{ID: "create-tenant:b", Kind: "create-tenant", TenantID: "b", State: registry.SagaRunning, UpdatedAt: time.Now()}
+	done := &registry.Saga{ID: "create-tenant:c", Kind: "create-tenant", TenantID: "c", State: registry.SagaDone, UpdatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, reg.PutSaga(ctx, stale))
+	require.NoError(t, reg.PutSaga(ctx, fresh))
+	require.NoError(t, reg.PutSaga(ctx, done))
+
+	var resumed []string
+	resume := func(_ context.Context, s *registry.Saga) error {
+		resumed = append(resumed, s.ID)
+		return nil
+	}
+
+	New(reg, k8s, nil, "tenants").WithSagas(resume).WithDryRun(true).reconcileSagas(ctx)
+	assert.Empty(t, resumed, "dry run only logs")
+
+	New(reg, k8s, nil, "tenants").WithSagas(resume).reconcileSagas(ctx)
+	assert.Equal(t, []string{"create-tenant:a"}, resumed, "in-progress sagas are left alone")
+
+	// A finished saga's leftover record is deleted, never compensated
+	sagas, err := reg.ListSagas(ctx)
+	require.NoError(t, err)
+	for _, s := range sagas {
+		assert.NotEqual(t, "create-tenant:c", s.ID)
+	}
+}
@@ -0,0 +1,228 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// probeKey is written and removed by VerifyAccess. It has no status
+// attribute, so tenant scans never return it even if a probe is left behind.
+const probeKey = "bootstrap#probe"
+
+// StatusIndex is the GSI the lifecycle controller and reconciler list
+// tenants by: hash key status, range key last_active_at. Items without a
+// status (image aliases, wake history, probes) are not in it.
+const StatusIndex = "status-last_active_at"
+
+const (
+	// tableActiveTimeout bounds how long Bootstrap waits for a new table
+	tableActiveTimeout = 2 * time.Minute
+	// indexActiveTimeout bounds how long MigrateStatusIndex waits for
+	// DynamoDB to backfill the index from existing items
+	indexActiveTimeout = 30 * time.Minute
+	indexPollInterval  = 10 * time.Second
+)
+
+// Bootstrap creates the registry table, with StatusIndex, if it does not
+// exist and waits for it to become active. An existing table is left alone,
+// but its key schema is checked; MigrateStatusIndex adds the index to tables
+// created before it. The registry stores no expiring items, so the table has
+// no TTL attribute. Reports whether the table was created.
+func Bootstrap(ctx context.Context, db *dynamodb.Client, tableName string) (bool, error) {
+	out, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	if err == nil {
+		return false, checkKeySchema(out.Table)
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return false, fmt.Errorf("dynamodb DescribeTable: %w", err)
+	}
+
+	_, err = db.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("tenant_id"), KeyType: types.KeyTypeHash},
+		},
+		AttributeDefinitions: append([]types.AttributeDefinition{
+			{AttributeName: aws.String("tenant_id"), AttributeType: types.ScalarAttributeTypeS},
+		}, statusIndexAttributes...),
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{statusIndex(nil)},
+		BillingMode:            types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		return false, fmt.Errorf("dynamodb CreateTable: %w", err)
+	}
+	waiter := dynamodb.NewTableExistsWaiter(db)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, tableActiveTimeout); err != nil {
+		return true, fmt.Errorf("wait for table %s: %w", tableName, err)
+	}
+	return true, nil
+}
+
+// statusIndexAttributes are the key attributes of StatusIndex
+var statusIndexAttributes = []types.AttributeDefinition{
+	{AttributeName: aws.String("status"), AttributeType: types.ScalarAttributeTypeS},
+	{AttributeName: aws.String("last_active_at"), AttributeType: types.ScalarAttributeTypeS},
+}
+
+// statusIndex describes StatusIndex. It projects whole records, so listing
+// needs no follow-up reads. throughput is only set for provisioned tables.
+func statusIndex(throughput *types.ProvisionedThroughput) types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName: aws.String(StatusIndex),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("status"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("last_active_at"), KeyType: types.KeyTypeRange},
+		},
+		Projection:            &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		ProvisionedThroughput: throughput,
+	}
+}
+
+// MigrateStatusIndex adds StatusIndex to an existing table and waits for
+// DynamoDB to finish backfilling it, which takes a while on large tables.
+// Until then the registry keeps scanning. A table that already has the
+// index, finished or still building, is only waited on. Reports whether the
+// index was added.
+func MigrateStatusIndex(ctx context.Context, db *dynamodb.Client, tableName string) (bool, error) {
+	out, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	if err != nil {
+		return false, fmt.Errorf("dynamodb DescribeTable: %w", err)
+	}
+	added := false
+	if findIndex(out.Table, StatusIndex) == nil {
+		// Provisioned tables need capacity for the index too; give it the table's
+		var throughput *types.ProvisionedThroughput
+		if t := out.Table; (t.BillingModeSummary == nil || t.BillingModeSummary.BillingMode == types.BillingModeProvisioned) && t.ProvisionedThroughput != nil {
+			throughput = &types.ProvisionedThroughput{
+				ReadCapacityUnits:  t.ProvisionedThroughput.ReadCapacityUnits,
+				WriteCapacityUnits: t.ProvisionedThroughput.WriteCapacityUnits,
+			}
+		}
+		idx := statusIndex(throughput)
+		_, err := db.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            aws.String(tableName),
+			AttributeDefinitions: statusIndexAttributes,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:             idx.IndexName,
+					KeySchema:             idx.KeySchema,
+					Projection:            idx.Projection,
+					ProvisionedThroughput: idx.ProvisionedThroughput,
+				},
+			}},
+		})
+		if err != nil {
+			return false, fmt.Errorf("dynamodb UpdateTable: %w", err)
+		}
+		added = true
+	}
+	return added, waitIndexActive(ctx, db, tableName, StatusIndex)
+}
+
+// findIndex returns the table's GSI called name, or nil
+func findIndex(t *types.TableDescription, name string) *types.GlobalSecondaryIndexDescription {
+	for i := range t.GlobalSecondaryIndexes {
+		if aws.ToString(t.GlobalSecondaryIndexes[i].IndexName) == name {
+			return &t.GlobalSecondaryIndexes[i]
+		}
+	}
+	return nil
+}
+
+// waitIndexActive polls until the GSI has finished building
+func waitIndexActive(ctx context.Context, db *dynamodb.Client, tableName, index string) error {
+	ctx, cancel := context.WithTimeout(ctx, indexActiveTimeout)
+	defer cancel()
+	for {
+		out, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+		if err != nil {
+			return fmt.Errorf("dynamodb DescribeTable: %w", err)
+		}
+		if idx := findIndex(out.Table, index); idx != nil && idx.IndexStatus == types.IndexStatusActive {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for index %s on %s: %w", index, tableName, ctx.Err())
+		case <-time.After(indexPollInterval):
+		}
+	}
+}
+
+// checkKeySchema rejects a table whose primary key is not a string tenant_id
+func checkKeySchema(t *types.TableDescription) error {
+	if len(t.KeySchema) != 1 || aws.ToString(t.KeySchema[0].AttributeName) != "tenant_id" || t.KeySchema[0].KeyType != types.KeyTypeHash {
+		return fmt.Errorf("table %s: key schema must be a single hash key tenant_id", aws.ToString(t.TableName))
+	}
+	for _, def := range t.AttributeDefinitions {
+		if aws.ToString(def.AttributeName) == "tenant_id" && def.AttributeType != types.ScalarAttributeTypeS {
+			return fmt.Errorf("table %s: tenant_id must be a string attribute", aws.ToString(t.TableName))
+		}
+	}
+	return nil
+}
+
+// VerifyAccess exercises every DynamoDB action the orchestrator uses against
+// tableName with a throwaway item, so missing IAM permissions show up at
+// bootstrap rather than on the first tenant request. All failing actions are
+// reported together.
+func VerifyAccess(ctx context.Context, db *dynamodb.Client, tableName string) error {
+	table := aws.String(tableName)
+	key := map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: probeKey},
+	}
+	var errs []error
+	check := func(action string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dynamodb:%s: %w", action, err))
+		}
+	}
+
+	_, err := db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: table,
+		Item: map[string]types.AttributeValue{
+			"tenant_id":  &types.AttributeValueMemberS{Value: probeKey},
+			"updated_at": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	check("PutItem", err)
+	_, err = db.GetItem(ctx, &dynamodb.GetItemInput{TableName: table, Key: key})
+	check("GetItem", err)
+	_, err = db.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{tableName: {Keys: []map[string]types.AttributeValue{key}}},
+	})
+	check("BatchGetItem", err)
+	_, err = db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        table,
+		Key:              key,
+		UpdateExpression: aws.String("SET updated_at = :t"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":t": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	check("UpdateItem", err)
+	_, err = db.Scan(ctx, &dynamodb.ScanInput{TableName: table, Limit: aws.Int32(1)})
+	check("Scan", err)
+	_, err = db.Query(ctx, &dynamodb.QueryInput{
+		TableName:                table,
+		IndexName:                aws.String(StatusIndex),
+		KeyConditionExpression:   aws.String("#s = :s"),
+		ExpressionAttributeNames: map[string]string{"#s": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s": &types.AttributeValueMemberS{Value: string(StatusRunning)},
+		},
+		Limit: aws.Int32(1),
+	})
+	check("Query", err)
+	_, err = db.DeleteItem(ctx, &dynamodb.DeleteItemInput{TableName: table, Key: key})
+	check("DeleteItem", err)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,439 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Dual is a Client that writes to two registries and reads from one of
+// them, so tenants can be moved to a new backend (e.g. another table)
+// without downtime: run Dual with the new one as secondary, backfill it
+// with SyncTable, verify, switch reads over, and finally make it the only
+// registry.
+//
+// The primary stays authoritative throughout: a write that fails there fails
+// the call and isn't mirrored, while one that fails on the secondary is only
+// logged, leaving a difference for the next SyncTable to repair.
+type Dual struct {
+	primary, secondary Client
+	readSecondary      bool
+}
+
+// Read preferences for NewDual
+const (
+	ReadPrimary   = "primary"
+	ReadSecondary = "secondary"
+)
+
+// NewDual returns a Client that writes to both registries and reads from
+// the one readFrom names (ReadPrimary or ReadSecondary)
+func NewDual(primary, secondary Client, readFrom string) (*Dual, error) {
+	switch readFrom {
+	case ReadPrimary, ReadSecondary:
+	default:
+		return nil, fmt.Errorf("read preference must be %s or %s, got %q", ReadPrimary, ReadSecondary, readFrom)
+	}
+	return &Dual{primary: primary, secondary: secondary, readSecondary: readFrom == ReadSecondary}, nil
+}
+
+func (d *Dual) read() Client {
+	if d.readSecondary {
+		return d.secondary
+	}
+	return d.primary
+}
+
+// mirror logs a write the secondary missed
+func (d *Dual) mirror(op, key string, err error) {
+	if err != nil {
+		slog.Warn("registry dual-write to secondary failed", "op", op, "key", key, "err", err)
+	}
+}
+
+func (d *Dual) GetTenant(ctx context.Context, tenantID string) (*TenantRecord, error) {
+	return d.read().GetTenant(ctx, tenantID)
+}
+
+func (d *Dual) CreateTenant(ctx context.Context, record *TenantRecord) error {
+	if err := d.primary.CreateTenant(ctx, record); err != nil {
+		return err
+	}
+	d.mirror("CreateTenant", record.TenantID, d.secondary.CreateTenant(ctx, record))
+	return nil
+}
+
+func (d *Dual) UpdateStatus(ctx context.Context, tenantID string, status TenantStatus, podName, podIP string) error {
+	if err := d.primary.UpdateStatus(ctx, tenantID, status, podName, podIP); err != nil {
+		return err
+	}
+	d.mirror("UpdateStatus", tenantID, d.secondary.UpdateStatus(ctx, tenantID, status, podName, podIP))
+	return nil
+}
+
+func (d *Dual) ResumeTenant(ctx context.Context, tenantID string) error {
+	if err := d.primary.ResumeTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	d.mirror("ResumeTenant", tenantID, d.secondary.ResumeTenant(ctx, tenantID))
+	return nil
+}
+
+func (d *Dual) TerminateTenant(ctx context.Context, tenantID string, deletedAt, purgeAt time.Time) error {
+	if err := d.primary.TerminateTenant(ctx, tenantID, deletedAt, purgeAt); err != nil {
+		return err
+	}
+	d.mirror("TerminateTenant", tenantID, d.secondary.TerminateTenant(ctx, tenantID, deletedAt, purgeAt))
+	return nil
+}
+
+func (d *Dual) RestoreTenant(ctx context.Context, tenantID string) error {
+	if err := d.primary.RestoreTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	d.mirror("RestoreTenant", tenantID, d.secondary.RestoreTenant(ctx, tenantID))
+	return nil
+}
+
+func (d *Dual) RecordWakeFailure(ctx context.Context, tenantID, errMsg string, limit int) (bool, error) {
+	failed, err := d.primary.RecordWakeFailure(ctx, tenantID, errMsg, limit)
+	if err != nil {
+		return false, err
+	}
+	_, err = d.secondary.RecordWakeFailure(ctx, tenantID, errMsg, limit)
+	d.mirror("RecordWakeFailure", tenantID, err)
+	return failed, nil
+}
+
+func (d *Dual) ResetTenant(ctx context.Context, tenantID string) error {
+	if err := d.primary.ResetTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	d.mirror("ResetTenant", tenantID, d.secondary.ResetTenant(ctx, tenantID))
+	return nil
+}
+
+func (d *Dual) UpdateActivity(ctx context.Context, tenantID string) error {
+	if err := d.primary.UpdateActivity(ctx, tenantID); err != nil {
+		return err
+	}
+	d.mirror("UpdateActivity", tenantID, d.secondary.UpdateActivity(ctx, tenantID))
+	return nil
+}
+
+func (d *Dual) UpdateBotToken(ctx context.Context, tenantID, botToken string) error {
+	if err := d.primary.UpdateBotToken(ctx, tenantID, botToken); err != nil {
+		return err
+	}
+	d.mirror("UpdateBotToken", tenantID, d.secondary.UpdateBotToken(ctx, tenantID, botToken))
+	return nil
+}
+
+func (d *Dual) UpdateBotTokenRef(ctx context.Context, tenantID, ref string) error {
+	if err := d.primary.UpdateBotTokenRef(ctx, tenantID, ref); err != nil {
+		return err
+	}
+	d.mirror("UpdateBotTokenRef", tenantID, d.secondary.UpdateBotTokenRef(ctx, tenantID, ref))
+	return nil
+}
+
+func (d *Dual) UpdateWebhookSecret(ctx context.Context, tenantID, secret string) error {
+	if err := d.primary.UpdateWebhookSecret(ctx, tenantID, secret); err != nil {
+		return err
+	}
+	d.mirror("UpdateWebhookSecret", tenantID, d.secondary.UpdateWebhookSecret(ctx, tenantID, secret))
+	return nil
+}
+
+func (d *Dual) UpdateWebhookState(ctx context.Context, tenantID string, st WebhookState) error {
+	if err := d.primary.UpdateWebhookState(ctx, tenantID, st); err != nil {
+		return err
+	}
+	d.mirror("UpdateWebhookState", tenantID, d.secondary.UpdateWebhookState(ctx, tenantID, st))
+	return nil
+}
+
+func (d *Dual) UpdateIdleTimeout(ctx context.Context, tenantID string, timeoutS int64) error {
+	if err := d.primary.UpdateIdleTimeout(ctx, tenantID, timeoutS); err != nil {
+		return err
+	}
+	d.mirror("UpdateIdleTimeout", tenantID, d.secondary.UpdateIdleTimeout(ctx, tenantID, timeoutS))
+	return nil
+}
+
+func (d *Dual) UpdateBotUsername(ctx context.Context, tenantID, username string) error {
+	if err := d.primary.UpdateBotUsername(ctx, tenantID, username); err != nil {
+		return err
+	}
+	d.mirror("UpdateBotUsername", tenantID, d.secondary.UpdateBotUsername(ctx, tenantID, username))
+	return nil
+}
+
+func (d *Dual) UpdateTier(ctx context.Context, tenantID, tier string) error {
+	if err := d.primary.UpdateTier(ctx, tenantID, tier); err != nil {
+		return err
+	}
+	d.mirror("UpdateTier", tenantID, d.secondary.UpdateTier(ctx, tenantID, tier))
+	return nil
+}
+
+func (d *Dual) UpdateResize(ctx context.Context, tenantID string, op *ResizeOp) error {
+	if err := d.primary.UpdateResize(ctx, tenantID, op); err != nil {
+		return err
+	}
+	d.mirror("UpdateResize", tenantID, d.secondary.UpdateResize(ctx, tenantID, op))
+	return nil
+}
+
+func (d *Dual) UpdateImage(ctx context.Context, tenantID, image string) error {
+	if err := d.primary.UpdateImage(ctx, tenantID, image); err != nil {
+		return err
+	}
+	d.mirror("UpdateImage", tenantID, d.secondary.UpdateImage(ctx, tenantID, image))
+	return nil
+}
+
+func (d *Dual) UpdateLocale(ctx context.Context, tenantID, locale string) error {
+	if err := d.primary.UpdateLocale(ctx, tenantID, locale); err != nil {
+		return err
+	}
+	d.mirror("UpdateLocale", tenantID, d.secondary.UpdateLocale(ctx, tenantID, locale))
+	return nil
+}
+
+func (d *Dual) UpdateTimezone(ctx context.Context, tenantID, timezone string) error {
+	if err := d.primary.UpdateTimezone(ctx, tenantID, timezone); err != nil {
+		return err
+	}
+	d.mirror("UpdateTimezone", tenantID, d.secondary.UpdateTimezone(ctx, tenantID, timezone))
+	return nil
+}
+
+func (d *Dual) UpdateDNS(ctx context.Context, tenantID string, dns *DNSConfig) error {
+	if err := d.primary.UpdateDNS(ctx, tenantID, dns); err != nil {
+		return err
+	}
+	d.mirror("UpdateDNS", tenantID, d.secondary.UpdateDNS(ctx, tenantID, dns))
+	return nil
+}
+
+func (d *Dual) UpdateKeepWarm(ctx context.Context, tenantID string, keepWarm bool) error {
+	if err := d.primary.UpdateKeepWarm(ctx, tenantID, keepWarm); err != nil {
+		return err
+	}
+	d.mirror("UpdateKeepWarm", tenantID, d.secondary.UpdateKeepWarm(ctx, tenantID, keepWarm))
+	return nil
+}
+
+func (d *Dual) UpdateNoAnnouncements(ctx context.Context, tenantID string, optOut bool) error {
+	if err := d.primary.UpdateNoAnnouncements(ctx, tenantID, optOut); err != nil {
+		return err
+	}
+	d.mirror("UpdateNoAnnouncements", tenantID, d.secondary.UpdateNoAnnouncements(ctx, tenantID, optOut))
+	return nil
+}
+
+func (d *Dual) UpdateWeeklyDigest(ctx context.Context, tenantID string, on bool) error {
+	if err := d.primary.UpdateWeeklyDigest(ctx, tenantID, on); err != nil {
+		return err
+	}
+	d.mirror("UpdateWeeklyDigest", tenantID, d.secondary.UpdateWeeklyDigest(ctx, tenantID, on))
+	return nil
+}
+
+func (d *Dual) UpdateDigestSentAt(ctx context.Context, tenantID string, at time.Time) error {
+	if err := d.primary.UpdateDigestSentAt(ctx, tenantID, at); err != nil {
+		return err
+	}
+	d.mirror("UpdateDigestSentAt", tenantID, d.secondary.UpdateDigestSentAt(ctx, tenantID, at))
+	return nil
+}
+
+func (d *Dual) UpdateDisabled(ctx context.Context, tenantID string, disabled bool) error {
+	if err := d.primary.UpdateDisabled(ctx, tenantID, disabled); err != nil {
+		return err
+	}
+	d.mirror("UpdateDisabled", tenantID, d.secondary.UpdateDisabled(ctx, tenantID, disabled))
+	return nil
+}
+
+func (d *Dual) UpdateMaxMessageAge(ctx context.Context, tenantID string, maxAgeS int64) error {
+	if err := d.primary.UpdateMaxMessageAge(ctx, tenantID, maxAgeS); err != nil {
+		return err
+	}
+	d.mirror("UpdateMaxMessageAge", tenantID, d.secondary.UpdateMaxMessageAge(ctx, tenantID, maxAgeS))
+	return nil
+}
+
+func (d *Dual) UpdateMaxReplyBytes(ctx context.Context, tenantID string, maxBytes int64) error {
+	if err := d.primary.UpdateMaxReplyBytes(ctx, tenantID, maxBytes); err != nil {
+		return err
+	}
+	d.mirror("UpdateMaxReplyBytes", tenantID, d.secondary.UpdateMaxReplyBytes(ctx, tenantID, maxBytes))
+	return nil
+}
+
+func (d *Dual) UpdateLogForward(ctx context.Context, tenantID string, cfg *LogForwardConfig) error {
+	if err := d.primary.UpdateLogForward(ctx, tenantID, cfg); err != nil {
+		return err
+	}
+	d.mirror("UpdateLogForward", tenantID, d.secondary.UpdateLogForward(ctx, tenantID, cfg))
+	return nil
+}
+
+func (d *Dual) UpdateAllowedUpdates(ctx context.Context, tenantID string, types []string) error {
+	if err := d.primary.UpdateAllowedUpdates(ctx, tenantID, types); err != nil {
+		return err
+	}
+	d.mirror("UpdateAllowedUpdates", tenantID, d.secondary.UpdateAllowedUpdates(ctx, tenantID, types))
+	return nil
+}
+
+func (d *Dual) UpdateSlack(ctx context.Context, tenantID string, cfg *SlackConfig) error {
+	if err := d.primary.UpdateSlack(ctx, tenantID, cfg); err != nil {
+		return err
+	}
+	d.mirror("UpdateSlack", tenantID, d.secondary.UpdateSlack(ctx, tenantID, cfg))
+	return nil
+}
+
+func (d *Dual) UpdateStateSize(ctx context.Context, tenantID string, bytes int64, level string, at time.Time) error {
+	if err := d.primary.UpdateStateSize(ctx, tenantID, bytes, level, at); err != nil {
+		return err
+	}
+	d.mirror("UpdateStateSize", tenantID, d.secondary.UpdateStateSize(ctx, tenantID, bytes, level, at))
+	return nil
+}
+
+func (d *Dual) ListAll(ctx context.Context) ([]*TenantRecord, error) {
+	return d.read().ListAll(ctx)
+}
+
+func (d *Dual) ListByStatus(ctx context.Context, status TenantStatus) ([]*TenantRecord, error) {
+	return d.read().ListByStatus(ctx, status)
+}
+
+func (d *Dual) ListIdleTenants(ctx context.Context, olderThan time.Duration) ([]*TenantRecord, error) {
+	return d.read().ListIdleTenants(ctx, olderThan)
+}
+
+func (d *Dual) DeleteTenant(ctx context.Context, tenantID string) error {
+	if err := d.primary.DeleteTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	d.mirror("DeleteTenant", tenantID, d.secondary.DeleteTenant(ctx, tenantID))
+	return nil
+}
+
+func (d *Dual) RecordWake(ctx context.Context, tenantID string, attempt WakeAttempt, keep int) error {
+	if err := d.primary.RecordWake(ctx, tenantID, attempt, keep); err != nil {
+		return err
+	}
+	d.mirror("RecordWake", tenantID, d.secondary.RecordWake(ctx, tenantID, attempt, keep))
+	return nil
+}
+
+func (d *Dual) ListWakes(ctx context.Context, tenantID string) ([]WakeAttempt, error) {
+	return d.read().ListWakes(ctx, tenantID)
+}
+
+func (d *Dual) RecordEvent(ctx context.Context, tenantID string, ev TenantEvent, keep int) error {
+	if err := d.primary.RecordEvent(ctx, tenantID, ev, keep); err != nil {
+		return err
+	}
+	d.mirror("RecordEvent", tenantID, d.secondary.RecordEvent(ctx, tenantID, ev, keep))
+	return nil
+}
+
+func (d *Dual) ListEvents(ctx context.Context, tenantID string) ([]TenantEvent, error) {
+	return d.read().ListEvents(ctx, tenantID)
+}
+
+func (d *Dual) AddUsage(ctx context.Context, tenantID string, at time.Time, delta Usage) error {
+	if err := d.primary.AddUsage(ctx, tenantID, at, delta); err != nil {
+		return err
+	}
+	d.mirror("AddUsage", tenantID, d.secondary.AddUsage(ctx, tenantID, at, delta))
+	return nil
+}
+
+func (d *Dual) ListUsage(ctx context.Context, tenantID string, from, to time.Time) ([]UsageDay, error) {
+	return d.read().ListUsage(ctx, tenantID, from, to)
+}
+
+func (d *Dual) SetChats(ctx context.Context, tenantID, period string, chats int64) error {
+	if err := d.primary.SetChats(ctx, tenantID, period, chats); err != nil {
+		return err
+	}
+	d.mirror("SetChats", tenantID, d.secondary.SetChats(ctx, tenantID, period, chats))
+	return nil
+}
+
+func (d *Dual) ListChatMonths(ctx context.Context, tenantID string, from, to time.Time) ([]UsageMonth, error) {
+	return d.read().ListChatMonths(ctx, tenantID, from, to)
+}
+
+func (d *Dual) ListKV(ctx context.Context, tenantID string) (map[string]string, error) {
+	return d.read().ListKV(ctx, tenantID)
+}
+
+func (d *Dual) PutKV(ctx context.Context, tenantID, key, value string, maxKeys int) error {
+	if err := d.primary.PutKV(ctx, tenantID, key, value, maxKeys); err != nil {
+		return err
+	}
+	d.mirror("PutKV", tenantID, d.secondary.PutKV(ctx, tenantID, key, value, maxKeys))
+	return nil
+}
+
+func (d *Dual) DeleteKV(ctx context.Context, tenantID, key string) error {
+	if err := d.primary.DeleteKV(ctx, tenantID, key); err != nil {
+		return err
+	}
+	d.mirror("DeleteKV", tenantID, d.secondary.DeleteKV(ctx, tenantID, key))
+	return nil
+}
+
+func (d *Dual) PutSaga(ctx context.Context, s *Saga) error {
+	if err := d.primary.PutSaga(ctx, s); err != nil {
+		return err
+	}
+	d.mirror("PutSaga", s.ID, d.secondary.PutSaga(ctx, s))
+	return nil
+}
+
+func (d *Dual) DeleteSaga(ctx context.Context, id string) error {
+	if err := d.primary.DeleteSaga(ctx, id); err != nil {
+		return err
+	}
+	d.mirror("DeleteSaga", id, d.secondary.DeleteSaga(ctx, id))
+	return nil
+}
+
+func (d *Dual) ListSagas(ctx context.Context) ([]*Saga, error) {
+	return d.read().ListSagas(ctx)
+}
+
+func (d *Dual) GetImageAlias(ctx context.Context, alias string) (*ImageAlias, error) {
+	return d.read().GetImageAlias(ctx, alias)
+}
+
+func (d *Dual) PutImageAlias(ctx context.Context, alias *ImageAlias) error {
+	if err := d.primary.PutImageAlias(ctx, alias); err != nil {
+		return err
+	}
+	d.mirror("PutImageAlias", alias.Alias, d.secondary.PutImageAlias(ctx, alias))
+	return nil
+}
+
+func (d *Dual) ListImageAliases(ctx context.Context) ([]*ImageAlias, error) {
+	return d.read().ListImageAliases(ctx)
+}
+
+func (d *Dual) DeleteImageAlias(ctx context.Context, alias string) error {
+	if err := d.primary.DeleteImageAlias(ctx, alias); err != nil {
+		return err
+	}
+	d.mirror("DeleteImageAlias", alias, d.secondary.DeleteImageAlias(ctx, alias))
+	return nil
+}
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// eventLogKeyPrefix namespaces tenant event log items in the tenant table.
+// Like wake history items they carry no status attribute.
+const eventLogKeyPrefix = "events#"
+
+// Tenant event kinds
+const (
+	EventOOMKilled = "oom_killed" // the agent container exceeded its memory limit
+	EventCrashLoop = "crash_loop" // the agent container is in CrashLoopBackOff
+
+	EventStateQuotaWarning  = "state_quota_warning"  // S3 state reached 80% of the tier's quota
+	EventStateQuotaExceeded = "state_quota_exceeded" // S3 state reached the tier's quota
+
+	EventStuckTerminating = "stuck_terminating" // a pod hung in Terminating and was force-deleted
+
+	EventStateRestoreFailed = "state_restore_failed" // the state-restore container couldn't copy /s3-state
+	EventStateSyncFailed    = "state_sync_failed"    // the state-sync sidecar couldn't flush to /s3-state
+
+	EventPodModified = "pod_modified" // the pod was edited outside the orchestrator, e.g. with kubectl edit
+
+	EventStateRolledBack = "state_rolled_back" // the tenant's state was restored from a snapshot
+)
+
+// TenantEvent is one entry of a tenant's event log: something that happened
+// to its pod that the owner or an operator should know about
+type TenantEvent struct {
+	Time     time.Time `dynamodbav:"time" json:"time"`
+	Kind     string    `dynamodbav:"kind" json:"kind"`
+	Pod      string    `dynamodbav:"pod" json:"pod"`
+	Restarts int32     `dynamodbav:"restarts" json:"restarts"`
+	Message  string    `dynamodbav:"message,omitempty" json:"message,omitempty"`
+}
+
+// RecordEvent appends an event to the tenant's log, dropping the oldest
+// entries beyond keep
+func (c *DynamoClient) RecordEvent(ctx context.Context, tenantID string, ev TenantEvent, keep int) error {
+	av, err := attributevalue.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal tenant event: %w", err)
+	}
+	return c.appendBounded(ctx, eventLogKeyPrefix+tenantID, "events", av, keep)
+}
+
+// ListEvents returns the tenant's event log, newest first
+func (c *DynamoClient) ListEvents(ctx context.Context, tenantID string) ([]TenantEvent, error) {
+	out, err := c.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: eventLogKeyPrefix + tenantID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	var item struct {
+		Events []TenantEvent `dynamodbav:"events"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return nil, fmt.Errorf("unmarshal event log: %w", err)
+	}
+	return newestFirst(item.Events), nil
+}
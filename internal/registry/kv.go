@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// kvKeyPrefix namespaces tenant key-value store items in the tenant table.
+// Each tenant's pairs are one item's kv map, so its pod reads them all with
+// a single GetItem; like wake history items they carry no status attribute.
+const kvKeyPrefix = "kv#"
+
+// ErrKVFull is returned by PutKV for a new key when the tenant already has
+// the most keys allowed
+var ErrKVFull = errors.New("tenant key-value store is full")
+
+// ListKV returns the tenant's key-value pairs (empty if it has none)
+func (c *DynamoClient) ListKV(ctx context.Context, tenantID string) (map[string]string, error) {
+	out, err := c.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key:       kvItemKey(tenantID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	var item struct {
+		KV map[string]string `dynamodbav:"kv"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return nil, fmt.Errorf("unmarshal kv: %w", err)
+	}
+	if item.KV == nil {
+		item.KV = map[string]string{}
+	}
+	return item.KV, nil
+}
+
+// PutKV sets one of the tenant's keys. A new key fails with ErrKVFull if the
+// tenant already has maxKeys.
+func (c *DynamoClient) PutKV(ctx context.Context, tenantID, key, value string, maxKeys int) error {
+	// DynamoDB can't SET a path inside a map that doesn't exist yet, so the
+	// tenant's first key creates the map. A map created concurrently between
+	// the two means the key is worth setting once more.
+	for range 2 {
+		_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(c.tableName),
+			Key:                      kvItemKey(tenantID),
+			UpdateExpression:         aws.String("SET #kv.#k = :v"),
+			ConditionExpression:      aws.String("attribute_exists(#kv.#k) OR size(#kv) < :max"),
+			ExpressionAttributeNames: map[string]string{"#kv": "kv", "#k": key},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v":   &types.AttributeValueMemberS{Value: value},
+				":max": &types.AttributeValueMemberN{Value: strconv.Itoa(maxKeys)},
+			},
+		})
+		if !isConditionFailed(err) {
+			return wrapUpdateErr(err)
+		}
+		_, err = c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(c.tableName),
+			Key:                      kvItemKey(tenantID),
+			UpdateExpression:         aws.String("SET #kv = :m"),
+			ConditionExpression:      aws.String("attribute_not_exists(#kv)"),
+			ExpressionAttributeNames: map[string]string{"#kv": "kv"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":m": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					key: &types.AttributeValueMemberS{Value: value},
+				}},
+			},
+		})
+		if !isConditionFailed(err) {
+			return wrapUpdateErr(err)
+		}
+	}
+	return ErrKVFull
+}
+
+// DeleteKV removes one of the tenant's keys; a missing key is not an error
+func (c *DynamoClient) DeleteKV(ctx context.Context, tenantID, key string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                aws.String(c.tableName),
+		Key:                      kvItemKey(tenantID),
+		UpdateExpression:         aws.String("REMOVE #kv.#k"),
+		ConditionExpression:      aws.String("attribute_exists(#kv)"),
+		ExpressionAttributeNames: map[string]string{"#kv": "kv", "#k": key},
+	})
+	if isConditionFailed(err) {
+		return nil
+	}
+	return wrapUpdateErr(err)
+}
+
+func kvItemKey(tenantID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: kvKeyPrefix + tenantID},
+	}
+}
+
+func isConditionFailed(err error) bool {
+	var failed *types.ConditionalCheckFailedException
+	return errors.As(err, &failed)
+}
+
+func wrapUpdateErr(err error) error {
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
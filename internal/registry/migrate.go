@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableDiff is how a migration target table differs from its source, by
+// tenant_id key. Keys cover every item, including wake history, events,
+// key-value stores, sagas and image aliases.
+type TableDiff struct {
+	Missing []string // in the source only
+	Differ  []string // in both with different attributes
+	Extra   []string // in the target only
+}
+
+// Clean reports whether the tables hold the same items
+func (d TableDiff) Clean() bool {
+	return len(d.Missing)+len(d.Differ)+len(d.Extra) == 0
+}
+
+// SyncTable compares every item of table from with table to and, with
+// repair set, makes to match: missing and differing items are copied over
+// and extra ones deleted. With a Dual registry writing to both, writes that
+// land while it runs can leave new differences; run it again until it
+// reports a clean diff without repair.
+func SyncTable(ctx context.Context, db *dynamodb.Client, from, to string, repair bool) (TableDiff, error) {
+	// The target is scanned first: an item written to both in between is
+	// then at worst reported missing and copied again, never deleted
+	dst, err := scanItems(ctx, db, to)
+	if err != nil {
+		return TableDiff{}, err
+	}
+	src, err := scanItems(ctx, db, from)
+	if err != nil {
+		return TableDiff{}, err
+	}
+
+	var diff TableDiff
+	for key, item := range src {
+		other, ok := dst[key]
+		switch {
+		case !ok:
+			diff.Missing = append(diff.Missing, key)
+		case !sameItem(item, other):
+			diff.Differ = append(diff.Differ, key)
+		default:
+			continue
+		}
+		if repair {
+			if _, err := db.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(to), Item: item}); err != nil {
+				return diff, fmt.Errorf("dynamodb PutItem %s: %w", key, err)
+			}
+		}
+	}
+	for key := range dst {
+		if _, ok := src[key]; ok {
+			continue
+		}
+		diff.Extra = append(diff.Extra, key)
+		if repair {
+			_, err := db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(to),
+				Key:       map[string]types.AttributeValue{"tenant_id": &types.AttributeValueMemberS{Value: key}},
+			})
+			if err != nil {
+				return diff, fmt.Errorf("dynamodb DeleteItem %s: %w", key, err)
+			}
+		}
+	}
+	sort.Strings(diff.Missing)
+	sort.Strings(diff.Differ)
+	sort.Strings(diff.Extra)
+	return diff, nil
+}
+
+// scanItems reads a whole table, keyed by tenant_id
+func scanItems(ctx context.Context, db *dynamodb.Client, table string) (map[string]map[string]types.AttributeValue, error) {
+	items := map[string]map[string]types.AttributeValue{}
+	var start map[string]types.AttributeValue
+	for {
+		out, err := db.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(table),
+			ConsistentRead:    aws.Bool(true),
+			ExclusiveStartKey: start,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb Scan %s: %w", table, err)
+		}
+		for _, item := range out.Items {
+			if key, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+				items[key.Value] = item
+			}
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		start = out.LastEvaluatedKey
+	}
+}
+
+func sameItem(a, b map[string]types.AttributeValue) bool {
+	var av, bv map[string]any
+	if attributevalue.UnmarshalMap(a, &av) != nil || attributevalue.UnmarshalMap(b, &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
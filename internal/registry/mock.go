@@ -11,10 +11,26 @@ import (
 type MockClient struct {
 	mu      sync.RWMutex
 	tenants map[string]*TenantRecord
+	images  map[string]*ImageAlias
+	wakes   map[string][]WakeAttempt
+	events  map[string][]TenantEvent
+	kv      map[string]map[string]string
+	sagas   map[string]*Saga
+	usage   map[string]map[string]Usage // tenant → date → usage
+	chats   map[string]map[string]int64 // tenant → date or month → distinct chats
 }
 
 func NewMock() *MockClient {
-	return &MockClient{tenants: make(map[string]*TenantRecord)}
+	return &MockClient{
+		tenants: make(map[string]*TenantRecord),
+		images:  make(map[string]*ImageAlias),
+		wakes:   make(map[string][]WakeAttempt),
+		events:  make(map[string][]TenantEvent),
+		kv:      make(map[string]map[string]string),
+		sagas:   make(map[string]*Saga),
+		usage:   make(map[string]map[string]Usage),
+		chats:   make(map[string]map[string]int64),
+	}
 }
 
 func (m *MockClient) GetTenant(_ context.Context, tenantID string) (*TenantRecord, error) {
@@ -46,10 +62,91 @@ func (m *MockClient) UpdateStatus(_ context.Context, tenantID string, status Ten
 	if !ok {
 		return fmt.Errorf("tenant %s not found", tenantID)
 	}
+	if r.Status == StatusTerminated {
+		return ErrTerminated
+	}
+	if r.Status == StatusSuspended && status != StatusSuspended {
+		return ErrSuspended
+	}
 	r.Status = status
 	r.PodName = podName
 	r.PodIP = podIP
 	r.LastActiveAt = time.Now()
+	if status == StatusRunning {
+		r.WakeFailures = 0
+		r.LastError = ""
+	}
+	return nil
+}
+
+func (m *MockClient) ResumeTenant(_ context.Context, tenantID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok || r.Status != StatusSuspended {
+		return ErrNotSuspended
+	}
+	r.Status = StatusIdle
+	r.LastActiveAt = time.Now()
+	return nil
+}
+
+func (m *MockClient) TerminateTenant(_ context.Context, tenantID string, deletedAt, purgeAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Status = StatusTerminated
+	r.PodName = ""
+	r.PodIP = ""
+	r.DeletedAt, r.PurgeAt = deletedAt, purgeAt
+	return nil
+}
+
+func (m *MockClient) RestoreTenant(_ context.Context, tenantID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok || r.Status != StatusTerminated {
+		return ErrNotTerminated
+	}
+	r.Status = StatusIdle
+	r.DeletedAt, r.PurgeAt = time.Time{}, time.Time{}
+	r.LastActiveAt = time.Now()
+	return nil
+}
+
+func (m *MockClient) RecordWakeFailure(_ context.Context, tenantID, errMsg string, limit int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return false, fmt.Errorf("tenant %s not found", tenantID)
+	}
+	r.WakeFailures++
+	r.LastError = errMsg
+	if limit <= 0 || r.WakeFailures < limit || r.Status == StatusSuspended || r.Status == StatusTerminated {
+		return false, nil
+	}
+	r.Status = StatusFailed
+	r.PodName = ""
+	r.PodIP = ""
+	return true, nil
+}
+
+func (m *MockClient) ResetTenant(_ context.Context, tenantID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok || r.Status != StatusFailed {
+		return ErrNotFailed
+	}
+	r.Status = StatusIdle
+	r.WakeFailures = 0
+	r.LastError = ""
+	r.LastActiveAt = time.Now()
 	return nil
 }
 
@@ -75,6 +172,43 @@ func (m *MockClient) UpdateBotToken(_ context.Context, tenantID, botToken string
 	return nil
 }
 
+func (m *MockClient) UpdateBotTokenRef(_ context.Context, tenantID, ref string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.BotTokenRef = ref
+	r.BotToken = ""
+	return nil
+}
+
+func (m *MockClient) UpdateWebhookSecret(_ context.Context, tenantID, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.WebhookSecret = secret
+	return nil
+}
+
+func (m *MockClient) UpdateWebhookState(_ context.Context, tenantID string, st WebhookState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return fmt.Errorf("tenant %s not found", tenantID)
+	}
+	r.WebhookStatus = st.Status
+	r.WebhookError = st.Error
+	r.WebhookAttempts = st.Attempts
+	r.WebhookRetryAt = st.RetryAt
+	return nil
+}
+
 func (m *MockClient) UpdateIdleTimeout(_ context.Context, tenantID string, timeoutS int64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -86,6 +220,224 @@ func (m *MockClient) UpdateIdleTimeout(_ context.Context, tenantID string, timeo
 	return nil
 }
 
+func (m *MockClient) UpdateBotUsername(_ context.Context, tenantID, username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.BotUsername = username
+	return nil
+}
+
+func (m *MockClient) UpdateTier(_ context.Context, tenantID, tier string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Tier = tier
+	return nil
+}
+
+func (m *MockClient) UpdateResize(_ context.Context, tenantID string, op *ResizeOp) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	if op != nil {
+		cp := *op
+		op = &cp
+	}
+	r.Resize = op
+	return nil
+}
+
+func (m *MockClient) UpdateImage(_ context.Context, tenantID, image string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Image = image
+	return nil
+}
+
+func (m *MockClient) UpdateStateSize(_ context.Context, tenantID string, bytes int64, level string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.StateBytes, r.StateQuotaLevel, r.StateMeasuredAt = bytes, level, at
+	return nil
+}
+
+func (m *MockClient) UpdateLocale(_ context.Context, tenantID, locale string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Locale = locale
+	return nil
+}
+
+func (m *MockClient) UpdateTimezone(_ context.Context, tenantID, timezone string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Timezone = timezone
+	return nil
+}
+
+func (m *MockClient) UpdateDNS(_ context.Context, tenantID string, dns *DNSConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	if dns != nil {
+		cp := *dns
+		dns = &cp
+	}
+	r.DNS = dns
+	return nil
+}
+
+func (m *MockClient) UpdateLogForward(_ context.Context, tenantID string, cfg *LogForwardConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	if cfg != nil {
+		cp := *cfg
+		cfg = &cp
+	}
+	r.LogForward = cfg
+	return nil
+}
+
+func (m *MockClient) UpdateSlack(_ context.Context, tenantID string, cfg *SlackConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	if cfg != nil {
+		cp := *cfg
+		cfg = &cp
+	}
+	r.Slack = cfg
+	return nil
+}
+
+func (m *MockClient) UpdateAllowedUpdates(_ context.Context, tenantID string, types []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	if len(types) == 0 {
+		r.AllowedUpdates = nil
+	} else {
+		r.AllowedUpdates = append([]string(nil), types...)
+	}
+	return nil
+}
+
+func (m *MockClient) UpdateKeepWarm(_ context.Context, tenantID string, keepWarm bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.KeepWarm = keepWarm
+	return nil
+}
+
+func (m *MockClient) UpdateNoAnnouncements(_ context.Context, tenantID string, optOut bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.NoAnnouncements = optOut
+	return nil
+}
+
+func (m *MockClient) UpdateWeeklyDigest(_ context.Context, tenantID string, on bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.WeeklyDigest = on
+	return nil
+}
+
+func (m *MockClient) UpdateDigestSentAt(_ context.Context, tenantID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.DigestSentAt = at
+	return nil
+}
+
+func (m *MockClient) UpdateDisabled(_ context.Context, tenantID string, disabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.Disabled = disabled
+	return nil
+}
+
+func (m *MockClient) UpdateMaxMessageAge(_ context.Context, tenantID string, maxAgeS int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.MaxMessageAgeS = maxAgeS
+	return nil
+}
+
+func (m *MockClient) UpdateMaxReplyBytes(_ context.Context, tenantID string, maxBytes int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.tenants[tenantID]
+	if !ok {
+		return &ConditionalCheckFailed{}
+	}
+	r.MaxReplyBytes = maxBytes
+	return nil
+}
+
 func (m *MockClient) ListAll(_ context.Context) ([]*TenantRecord, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -128,9 +480,194 @@ func (m *MockClient) DeleteTenant(_ context.Context, tenantID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	delete(m.tenants, tenantID)
+	delete(m.wakes, tenantID)
+	delete(m.events, tenantID)
+	delete(m.kv, tenantID)
+	return nil
+}
+
+func (m *MockClient) RecordWake(_ context.Context, tenantID string, attempt WakeAttempt, keep int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wakes := append(m.wakes[tenantID], attempt)
+	if len(wakes) > keep {
+		wakes = wakes[len(wakes)-keep:]
+	}
+	m.wakes[tenantID] = wakes
+	return nil
+}
+
+func (m *MockClient) ListWakes(_ context.Context, tenantID string) ([]WakeAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return newestFirst(m.wakes[tenantID]), nil
+}
+
+func (m *MockClient) RecordEvent(_ context.Context, tenantID string, ev TenantEvent, keep int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := append(m.events[tenantID], ev)
+	if len(events) > keep {
+		events = events[len(events)-keep:]
+	}
+	m.events[tenantID] = events
+	return nil
+}
+
+func (m *MockClient) ListEvents(_ context.Context, tenantID string) ([]TenantEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return newestFirst(m.events[tenantID]), nil
+}
+
+func (m *MockClient) AddUsage(_ context.Context, tenantID string, at time.Time, delta Usage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.usage[tenantID] == nil {
+		m.usage[tenantID] = make(map[string]Usage)
+	}
+	date := at.UTC().Format(UsageDateFormat)
+	m.usage[tenantID][date] = m.usage[tenantID][date].Add(delta)
 	return nil
 }
 
+func (m *MockClient) ListUsage(_ context.Context, tenantID string, from, to time.Time) ([]UsageDay, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	days := usageDays(from, to)
+	if len(days) > MaxUsageDays {
+		return nil, fmt.Errorf("usage range of %d days exceeds %d", len(days), MaxUsageDays)
+	}
+	var result []UsageDay
+	for _, d := range days {
+		u, used := m.usage[tenantID][d]
+		chats, chatted := m.chats[tenantID][d]
+		if used || chatted {
+			result = append(result, UsageDay{Date: d, Usage: u, Chats: chats})
+		}
+	}
+	return result, nil
+}
+
+func (m *MockClient) SetChats(_ context.Context, tenantID, period string, chats int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.chats[tenantID] == nil {
+		m.chats[tenantID] = make(map[string]int64)
+	}
+	m.chats[tenantID][period] = chats
+	return nil
+}
+
+func (m *MockClient) ListChatMonths(_ context.Context, tenantID string, from, to time.Time) ([]UsageMonth, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []UsageMonth
+	for _, month := range usageMonths(from, to) {
+		if chats, ok := m.chats[tenantID][month]; ok {
+			result = append(result, UsageMonth{Month: month, Chats: chats})
+		}
+	}
+	return result, nil
+}
+
+func (m *MockClient) ListKV(_ context.Context, tenantID string) (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	kv := make(map[string]string, len(m.kv[tenantID]))
+	for k, v := range m.kv[tenantID] {
+		kv[k] = v
+	}
+	return kv, nil
+}
+
+func (m *MockClient) PutKV(_ context.Context, tenantID, key, value string, maxKeys int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kv := m.kv[tenantID]
+	if kv == nil {
+		kv = make(map[string]string)
+		m.kv[tenantID] = kv
+	}
+	if _, ok := kv[key]; !ok && len(kv) >= maxKeys {
+		return ErrKVFull
+	}
+	kv[key] = value
+	return nil
+}
+
+func (m *MockClient) DeleteKV(_ context.Context, tenantID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.kv[tenantID], key)
+	return nil
+}
+
+func (m *MockClient) GetImageAlias(_ context.Context, alias string) (*ImageAlias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.images[alias]
+	if !ok {
+		return nil, nil
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (m *MockClient) PutImageAlias(_ context.Context, alias *ImageAlias) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *alias
+	m.images[alias.Alias] = &cp
+	return nil
+}
+
+func (m *MockClient) ListImageAliases(_ context.Context) ([]*ImageAlias, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var aliases []*ImageAlias
+	for _, a := range m.images {
+		cp := *a
+		aliases = append(aliases, &cp)
+	}
+	return aliases, nil
+}
+
+func (m *MockClient) DeleteImageAlias(_ context.Context, alias string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.images, alias)
+	return nil
+}
+
+func (m *MockClient) PutSaga(_ context.Context, s *Saga) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *s
+	cp.Steps = append([]string(nil), s.Steps...)
+	m.sagas[s.ID] = &cp
+	return nil
+}
+
+func (m *MockClient) DeleteSaga(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sagas, id)
+	return nil
+}
+
+func (m *MockClient) ListSagas(_ context.Context) ([]*Saga, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var sagas []*Saga
+	for _, s := range m.sagas {
+		cp := *s
+		cp.Steps = append([]string(nil), s.Steps...)
+		sagas = append(sagas, &cp)
+	}
+	return sagas, nil
+}
+
 // ConditionalCheckFailed is returned when a conditional write fails
 type ConditionalCheckFailed struct {
 	TenantID string
@@ -2,13 +2,18 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
 )
 
 // TenantStatus represents the lifecycle state of a tenant
@@ -18,21 +23,211 @@ const (
 	StatusProvisioning TenantStatus = "provisioning"
 	StatusRunning      TenantStatus = "running"
 	StatusIdle         TenantStatus = "idle"
-	StatusTerminated   TenantStatus = "terminated"
+	// StatusTerminated marks a deleted tenant kept for restore until its
+	// PurgeAt: it has no pod and wakes are refused
+	StatusTerminated TenantStatus = "terminated"
+	// StatusSuspended holds a tenant (e.g. for billing) without deleting its
+	// state: it has no pod and wakes are refused until it is resumed
+	StatusSuspended TenantStatus = "suspended"
+	// StatusFailed marks a tenant whose wakes kept failing (see LastError):
+	// it has no pod and wakes are refused until an operator resets it
+	StatusFailed TenantStatus = "failed"
 )
 
+var (
+	// ErrSuspended is returned by UpdateStatus for a suspended tenant
+	ErrSuspended = errors.New("tenant is suspended")
+	// ErrNotSuspended is returned by ResumeTenant for a tenant that isn't
+	ErrNotSuspended = errors.New("tenant is not suspended")
+	// ErrNotFailed is returned by ResetTenant for a tenant that isn't failed
+	ErrNotFailed = errors.New("tenant is not failed")
+	// ErrTerminated is returned by UpdateStatus for a deleted tenant
+	ErrTerminated = errors.New("tenant is deleted")
+	// ErrNotTerminated is returned by RestoreTenant for a tenant that isn't
+	// deleted
+	ErrNotTerminated = errors.New("tenant is not deleted")
+)
+
+// WebhookStatus tracks a tenant's Telegram webhook registration
+type WebhookStatus string
+
+const (
+	WebhookRegistered WebhookStatus = "registered" // Telegram accepted setWebhook
+	WebhookPending    WebhookStatus = "pending"    // queued for a retry at webhook_retry_at
+	WebhookFailed     WebhookStatus = "failed"     // rejected, or retries exhausted; see webhook_error
+)
+
+// WebhookState is the webhook_* attributes of a tenant record
+type WebhookState struct {
+	Status   WebhookStatus
+	Error    string
+	Attempts int
+	RetryAt  time.Time // zero unless pending
+}
+
+// DefaultTier is the service tier assigned to tenants created without one
+const DefaultTier = "standard"
+
 // TenantRecord is the DynamoDB schema for a tenant
 type TenantRecord struct {
-	TenantID     string       `dynamodbav:"tenant_id"`
-	Status       TenantStatus `dynamodbav:"status"`
-	PodName      string       `dynamodbav:"pod_name,omitempty"`
-	PodIP        string       `dynamodbav:"pod_ip,omitempty"`
-	Namespace    string       `dynamodbav:"namespace"`
-	S3Prefix     string       `dynamodbav:"s3_prefix"`
-	BotToken     string       `dynamodbav:"bot_token,omitempty"`
-	CreatedAt    time.Time    `dynamodbav:"created_at"`
-	LastActiveAt time.Time    `dynamodbav:"last_active_at"`
-	IdleTimeoutS int64        `dynamodbav:"idle_timeout_s"`
+	TenantID  string       `dynamodbav:"tenant_id"`
+	Status    TenantStatus `dynamodbav:"status"`
+	PodName   string       `dynamodbav:"pod_name,omitempty"`
+	PodIP     string       `dynamodbav:"pod_ip,omitempty"`
+	Namespace string       `dynamodbav:"namespace"`
+	S3Prefix  string       `dynamodbav:"s3_prefix"`
+	// BotToken is the plaintext token of a tenant created before tokens
+	// moved to the secret store; see BotTokenRef
+	BotToken     string    `dynamodbav:"bot_token,omitempty"`
+	BotUsername  string    `dynamodbav:"bot_username,omitempty"`
+	CreatedAt    time.Time `dynamodbav:"created_at"`
+	LastActiveAt time.Time `dynamodbav:"last_active_at"`
+	IdleTimeoutS int64     `dynamodbav:"idle_timeout_s"`
+	Tier         string    `dynamodbav:"tier,omitempty"`
+	// Image pins the ZeroClaw version: a catalog alias or an explicit tag/image.
+	// Empty follows the orchestrator's default channel.
+	Image string `dynamodbav:"image,omitempty"`
+	// DNS overrides the tier's pod DNS settings. Nil uses the tier's.
+	DNS *DNSConfig `dynamodbav:"dns,omitempty"`
+	// KeepWarm exempts the tenant from idle termination; its pod is only
+	// replaced via blue/green restart.
+	KeepWarm bool `dynamodbav:"keep_warm,omitempty"`
+	// LogForward opts the tenant owner in to receiving agent error logs. Nil
+	// disables forwarding.
+	LogForward *LogForwardConfig `dynamodbav:"log_forward,omitempty"`
+	// HomeRegion is the only region allowed to run the tenant's pod in a
+	// multi-region deployment. Empty means any (single-region) orchestrator.
+	HomeRegion string `dynamodbav:"home_region,omitempty"`
+	// AllowedUpdates are the Telegram update types the tenant's bot webhook
+	// subscribes to. Empty means telegram.DefaultAllowedUpdates.
+	AllowedUpdates []string `dynamodbav:"allowed_updates,omitempty"`
+	// Slack connects the tenant to a Slack app whose events the router
+	// forwards to the same pod as Telegram updates. Nil means Telegram only.
+	Slack *SlackConfig `dynamodbav:"slack,omitempty"`
+	// WebhookSecret is the secret_token Telegram echoes in the
+	// X-Telegram-Bot-Api-Secret-Token header of every webhook call. Empty
+	// until the webhook is next registered; the router skips the check then.
+	WebhookSecret string `dynamodbav:"webhook_secret,omitempty"`
+	// WebhookStatus is where registering the bot's webhook stands; see
+	// WebhookState. Empty for tenants without a bot or registered before
+	// the status was tracked.
+	WebhookStatus   WebhookStatus `dynamodbav:"webhook_status,omitempty"`
+	WebhookError    string        `dynamodbav:"webhook_error,omitempty"`
+	WebhookAttempts int           `dynamodbav:"webhook_attempts,omitempty"`
+	WebhookRetryAt  time.Time     `dynamodbav:"webhook_retry_at,omitempty"`
+	// Locale selects the language of system messages sent to the tenant's
+	// users (see i18n). Empty means English.
+	Locale string `dynamodbav:"locale,omitempty"`
+	// Timezone is the IANA zone times in system messages are shown in.
+	// Empty means UTC.
+	Timezone string `dynamodbav:"timezone,omitempty"`
+	// BotTokenRef names the secret holding the bot token (see package
+	// secrets). Empty for tenants without a bot, or whose token is still
+	// stored in BotToken.
+	BotTokenRef string `dynamodbav:"bot_token_ref,omitempty"`
+	// StateBytes is the size of the tenant's S3 state when last measured at
+	// StateMeasuredAt (see package statesize). Zero if never measured.
+	StateBytes      int64     `dynamodbav:"state_bytes,omitempty"`
+	StateMeasuredAt time.Time `dynamodbav:"state_measured_at,omitempty"`
+	// StateQuotaLevel is the quota level the owner was last told about, so a
+	// crossing is reported once
+	StateQuotaLevel string `dynamodbav:"state_quota_level,omitempty"`
+	// Resize is the tenant's latest tier change and whether its pod has
+	// been moved to the new tier's resources yet. Nil if the tier was never
+	// changed after creation.
+	Resize *ResizeOp `dynamodbav:"resize,omitempty"`
+	// Disabled is the tenant's kill switch: the router stops forwarding its
+	// messages and wakes are refused until it is cleared
+	Disabled bool `dynamodbav:"disabled,omitempty"`
+	// MaxMessageAgeS overrides the router's MAX_MESSAGE_AGE_S for the
+	// tenant: updates older than this many seconds aren't forwarded. Zero
+	// uses the router's, negative forwards updates of any age.
+	MaxMessageAgeS int64 `dynamodbav:"max_message_age_s,omitempty"`
+	// MaxReplyBytes overrides the router's MAX_REPLY_BYTES for the tenant:
+	// longer agent replies are cut short, with a link to the full one. Zero
+	// uses the router's, negative sends replies of any length.
+	MaxReplyBytes int64 `dynamodbav:"max_reply_bytes,omitempty"`
+	// WakeFailures counts the wakes that failed since the tenant's pod last
+	// started, and LastError is the newest one's error. Both are cleared
+	// when a pod starts or the tenant is reset.
+	WakeFailures int    `dynamodbav:"wake_failures,omitempty"`
+	LastError    string `dynamodbav:"last_error,omitempty"`
+	// NoAnnouncements opts the tenant's owner out of fleet-wide
+	// announcements (POST /admin/broadcast). Notifications about the
+	// tenant itself are still sent.
+	NoAnnouncements bool `dynamodbav:"no_announcements,omitempty"`
+	// WeeklyDigest opts the tenant's owner in to a weekly summary of the
+	// tenant's messages, uptime, estimated cost and errors (package
+	// digest), delivered to the LogForward targets. DigestSentAt is when
+	// the last one was sent.
+	WeeklyDigest bool      `dynamodbav:"weekly_digest,omitempty"`
+	DigestSentAt time.Time `dynamodbav:"digest_sent_at,omitempty"`
+	// DeletedAt is when a terminated tenant was deleted, and PurgeAt when
+	// its record and S3 state are purged for good
+	DeletedAt time.Time `dynamodbav:"deleted_at,omitempty"`
+	PurgeAt   time.Time `dynamodbav:"purge_at,omitempty"`
+}
+
+// HasBot reports whether the tenant has a Telegram bot token, stored either way
+func (r *TenantRecord) HasBot() bool {
+	return r.BotToken != "" || r.BotTokenRef != ""
+}
+
+// SlackConfig holds a tenant's Slack app credentials: the signing secret
+// verifies Events API requests and the bot token posts replies.
+type SlackConfig struct {
+	SigningSecret string `dynamodbav:"signing_secret" json:"signing_secret,omitempty"`
+	BotToken      string `dynamodbav:"bot_token" json:"bot_token,omitempty"`
+}
+
+// LogForwardConfig says where a tenant's agent error logs are delivered. A
+// Telegram chat is messaged through the tenant's own bot; a webhook receives
+// a JSON POST. Either or both may be set.
+type LogForwardConfig struct {
+	TelegramChatID int64  `dynamodbav:"telegram_chat_id,omitempty" json:"telegram_chat_id,omitempty"`
+	WebhookURL     string `dynamodbav:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+}
+
+// DNSConfig is a tenant's pod dnsPolicy/dnsConfig override
+type DNSConfig struct {
+	Policy      string   `dynamodbav:"policy,omitempty" json:"policy,omitempty"`
+	Nameservers []string `dynamodbav:"nameservers,omitempty" json:"nameservers,omitempty"`
+	Searches    []string `dynamodbav:"searches,omitempty" json:"searches,omitempty"`
+	Options     []string `dynamodbav:"options,omitempty" json:"options,omitempty"`
+}
+
+// ResizeStatus is where moving a tenant's pod to a new tier stands
+type ResizeStatus string
+
+const (
+	// ResizePending waits for the tenant's next pod: the next wake, or a
+	// restart or rollout of a running pod
+	ResizePending ResizeStatus = "pending"
+	// ResizeReplacing is a blue/green replacement in progress
+	ResizeReplacing ResizeStatus = "replacing"
+	ResizeDone      ResizeStatus = "done"
+	ResizeFailed    ResizeStatus = "failed"
+)
+
+// ResizeOp is a tier change and the pod replacement that applies it
+type ResizeOp struct {
+	FromTier    string       `dynamodbav:"from_tier" json:"from_tier"`
+	ToTier      string       `dynamodbav:"to_tier" json:"to_tier"`
+	Status      ResizeStatus `dynamodbav:"status" json:"status"`
+	Error       string       `dynamodbav:"error,omitempty" json:"error,omitempty"`
+	RequestedAt time.Time    `dynamodbav:"requested_at" json:"requested_at"`
+	UpdatedAt   time.Time    `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// imageAliasKeyPrefix namespaces image catalog items in the tenant table.
+// They carry no status attribute, so tenant scans never return them.
+const imageAliasKeyPrefix = "image#"
+
+// ImageAlias maps a version channel (e.g. stable, beta, v1.4) to a ZeroClaw image
+type ImageAlias struct {
+	Alias     string    `dynamodbav:"alias" json:"alias"`
+	Image     string    `dynamodbav:"image" json:"image"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
 }
 
 // Client is the interface for tenant registry operations
@@ -40,13 +235,63 @@ type Client interface {
 	GetTenant(ctx context.Context, tenantID string) (*TenantRecord, error)
 	CreateTenant(ctx context.Context, record *TenantRecord) error
 	UpdateStatus(ctx context.Context, tenantID string, status TenantStatus, podName, podIP string) error
+	ResumeTenant(ctx context.Context, tenantID string) error
+	TerminateTenant(ctx context.Context, tenantID string, deletedAt, purgeAt time.Time) error
+	RestoreTenant(ctx context.Context, tenantID string) error
+	RecordWakeFailure(ctx context.Context, tenantID, errMsg string, limit int) (bool, error)
+	ResetTenant(ctx context.Context, tenantID string) error
 	UpdateActivity(ctx context.Context, tenantID string) error
 	UpdateBotToken(ctx context.Context, tenantID, botToken string) error
+	UpdateBotTokenRef(ctx context.Context, tenantID, ref string) error
+	UpdateWebhookSecret(ctx context.Context, tenantID, secret string) error
+	UpdateWebhookState(ctx context.Context, tenantID string, st WebhookState) error
 	UpdateIdleTimeout(ctx context.Context, tenantID string, timeoutS int64) error
+	UpdateBotUsername(ctx context.Context, tenantID, username string) error
+	UpdateTier(ctx context.Context, tenantID, tier string) error
+	UpdateResize(ctx context.Context, tenantID string, op *ResizeOp) error
+	UpdateImage(ctx context.Context, tenantID, image string) error
+	UpdateLocale(ctx context.Context, tenantID, locale string) error
+	UpdateTimezone(ctx context.Context, tenantID, timezone string) error
+	UpdateDNS(ctx context.Context, tenantID string, dns *DNSConfig) error
+	UpdateKeepWarm(ctx context.Context, tenantID string, keepWarm bool) error
+	UpdateNoAnnouncements(ctx context.Context, tenantID string, optOut bool) error
+	UpdateWeeklyDigest(ctx context.Context, tenantID string, on bool) error
+	UpdateDigestSentAt(ctx context.Context, tenantID string, at time.Time) error
+	UpdateDisabled(ctx context.Context, tenantID string, disabled bool) error
+	UpdateMaxMessageAge(ctx context.Context, tenantID string, maxAgeS int64) error
+	UpdateMaxReplyBytes(ctx context.Context, tenantID string, maxBytes int64) error
+	UpdateLogForward(ctx context.Context, tenantID string, cfg *LogForwardConfig) error
+	UpdateAllowedUpdates(ctx context.Context, tenantID string, types []string) error
+	UpdateSlack(ctx context.Context, tenantID string, cfg *SlackConfig) error
+	UpdateStateSize(ctx context.Context, tenantID string, bytes int64, level string, at time.Time) error
 	ListAll(ctx context.Context) ([]*TenantRecord, error)
 	ListByStatus(ctx context.Context, status TenantStatus) ([]*TenantRecord, error)
 	ListIdleTenants(ctx context.Context, olderThan time.Duration) ([]*TenantRecord, error)
 	DeleteTenant(ctx context.Context, tenantID string) error
+
+	RecordWake(ctx context.Context, tenantID string, attempt WakeAttempt, keep int) error
+	ListWakes(ctx context.Context, tenantID string) ([]WakeAttempt, error)
+
+	RecordEvent(ctx context.Context, tenantID string, ev TenantEvent, keep int) error
+	ListEvents(ctx context.Context, tenantID string) ([]TenantEvent, error)
+
+	AddUsage(ctx context.Context, tenantID string, at time.Time, delta Usage) error
+	ListUsage(ctx context.Context, tenantID string, from, to time.Time) ([]UsageDay, error)
+	SetChats(ctx context.Context, tenantID, period string, chats int64) error
+	ListChatMonths(ctx context.Context, tenantID string, from, to time.Time) ([]UsageMonth, error)
+
+	ListKV(ctx context.Context, tenantID string) (map[string]string, error)
+	PutKV(ctx context.Context, tenantID, key, value string, maxKeys int) error
+	DeleteKV(ctx context.Context, tenantID, key string) error
+
+	PutSaga(ctx context.Context, s *Saga) error
+	DeleteSaga(ctx context.Context, id string) error
+	ListSagas(ctx context.Context) ([]*Saga, error)
+
+	GetImageAlias(ctx context.Context, alias string) (*ImageAlias, error)
+	PutImageAlias(ctx context.Context, alias *ImageAlias) error
+	ListImageAliases(ctx context.Context) ([]*ImageAlias, error)
+	DeleteImageAlias(ctx context.Context, alias string) error
 }
 
 // DynamoClient implements Client using AWS DynamoDB
@@ -98,9 +343,14 @@ func (c *DynamoClient) CreateTenant(ctx context.Context, record *TenantRecord) e
 	return nil
 }
 
-// UpdateStatus updates tenant status, pod name, and pod IP atomically
+// UpdateStatus updates tenant status, pod name, and pod IP atomically. A
+// suspended tenant only leaves that status through ResumeTenant: moving it
+// anywhere else fails with ErrSuspended, so a wake, idle stop or reconciler
+// pass racing a suspend can't undo it. Likewise a deleted tenant only leaves
+// it through RestoreTenant, and fails with ErrTerminated. Marking a tenant
+// running clears its wake failures.
 func (c *DynamoClient) UpdateStatus(ctx context.Context, tenantID string, status TenantStatus, podName, podIP string) error {
-	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+	in := &dynamodb.UpdateItemInput{
 		TableName: aws.String(c.tableName),
 		Key: map[string]types.AttributeValue{
 			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
@@ -115,7 +365,194 @@ func (c *DynamoClient) UpdateStatus(ctx context.Context, tenantID string, status
 			":pi": &types.AttributeValueMemberS{Value: podIP},
 			":la": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
 		},
+	}
+	if status == StatusRunning {
+		in.UpdateExpression = aws.String(*in.UpdateExpression + " REMOVE wake_failures, last_error")
+	}
+	in.ConditionExpression = aws.String("attribute_not_exists(#s) OR #s <> :term")
+	in.ExpressionAttributeValues[":term"] = &types.AttributeValueMemberS{Value: string(StatusTerminated)}
+	if status != StatusSuspended {
+		in.ConditionExpression = aws.String("attribute_not_exists(#s) OR (#s <> :sus AND #s <> :term)")
+		in.ExpressionAttributeValues[":sus"] = &types.AttributeValueMemberS{Value: string(StatusSuspended)}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		// Rare enough that reading which hold it was is cheaper than
+		// returning the old item on every update
+		if cur, err := c.GetTenant(ctx, tenantID); err == nil && cur != nil && cur.Status == StatusTerminated {
+			return ErrTerminated
+		}
+		return ErrSuspended
+	}
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// ResumeTenant moves a suspended tenant back to idle; its next message wakes
+// it. It fails with ErrNotSuspended if the tenant isn't suspended.
+func (c *DynamoClient) ResumeTenant(ctx context.Context, tenantID string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET #s = :s, last_active_at = :la"),
+		ConditionExpression: aws.String("#s = :sus"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":   &types.AttributeValueMemberS{Value: string(StatusIdle)},
+			":sus": &types.AttributeValueMemberS{Value: string(StatusSuspended)},
+			":la":  &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
 	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return ErrNotSuspended
+	}
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// TerminateTenant marks a tenant deleted: it keeps its record, without a pod,
+// until purgeAt
+func (c *DynamoClient) TerminateTenant(ctx context.Context, tenantID string, deletedAt, purgeAt time.Time) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET #s = :s, pod_name = :empty, pod_ip = :empty, deleted_at = :d, purge_at = :p"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":     &types.AttributeValueMemberS{Value: string(StatusTerminated)},
+			":empty": &types.AttributeValueMemberS{Value: ""},
+			":d":     &types.AttributeValueMemberS{Value: deletedAt.UTC().Format(time.RFC3339Nano)},
+			":p":     &types.AttributeValueMemberS{Value: purgeAt.UTC().Format(time.RFC3339Nano)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// RestoreTenant moves a deleted tenant back to idle; its next message wakes
+// it. It fails with ErrNotTerminated if the tenant isn't deleted.
+func (c *DynamoClient) RestoreTenant(ctx context.Context, tenantID string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET #s = :s, last_active_at = :la REMOVE deleted_at, purge_at"),
+		ConditionExpression: aws.String("#s = :term"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":    &types.AttributeValueMemberS{Value: string(StatusIdle)},
+			":term": &types.AttributeValueMemberS{Value: string(StatusTerminated)},
+			":la":   &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return ErrNotTerminated
+	}
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// RecordWakeFailure counts a failed wake and keeps errMsg as the tenant's
+// last error. Once limit wakes in a row have failed (limit > 0) the tenant
+// is marked failed, which it reports; a suspended tenant stays suspended.
+func (c *DynamoClient) RecordWakeFailure(ctx context.Context, tenantID, errMsg string, limit int) (bool, error) {
+	out, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET last_error = :e ADD wake_failures :one"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":e":   &types.AttributeValueMemberS{Value: errMsg},
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return false, fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	var failures int
+	if err := attributevalue.Unmarshal(out.Attributes["wake_failures"], &failures); err != nil {
+		return false, fmt.Errorf("unmarshal wake_failures: %w", err)
+	}
+	if limit <= 0 || failures < limit {
+		return false, nil
+	}
+	_, err = c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET #s = :s, pod_name = :empty, pod_ip = :empty"),
+		ConditionExpression: aws.String("#s <> :sus AND #s <> :term"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":     &types.AttributeValueMemberS{Value: string(StatusFailed)},
+			":sus":   &types.AttributeValueMemberS{Value: string(StatusSuspended)},
+			":term":  &types.AttributeValueMemberS{Value: string(StatusTerminated)},
+			":empty": &types.AttributeValueMemberS{Value: ""},
+		},
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return true, nil
+}
+
+// ResetTenant moves a failed tenant back to idle and clears its wake
+// failures, so its next message wakes it again. It fails with ErrNotFailed
+// if the tenant isn't failed.
+func (c *DynamoClient) ResetTenant(ctx context.Context, tenantID string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("SET #s = :s, last_active_at = :la REMOVE wake_failures, last_error"),
+		ConditionExpression: aws.String("#s = :failed"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":      &types.AttributeValueMemberS{Value: string(StatusIdle)},
+			":failed": &types.AttributeValueMemberS{Value: string(StatusFailed)},
+			":la":     &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	var failed *types.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		return ErrNotFailed
+	}
 	if err != nil {
 		return fmt.Errorf("dynamodb UpdateItem: %w", err)
 	}
@@ -153,6 +590,83 @@ func (c *DynamoClient) UpdateBotToken(ctx context.Context, tenantID, botToken st
 	return err
 }
 
+// UpdateBotTokenRef points the tenant at the secret holding its bot token
+// and drops any plaintext token. An empty ref removes the token altogether.
+func (c *DynamoClient) UpdateBotTokenRef(ctx context.Context, tenantID, ref string) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE bot_token, bot_token_ref"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if ref != "" {
+		in.UpdateExpression = aws.String("SET bot_token_ref = :r REMOVE bot_token")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":r": &types.AttributeValueMemberS{Value: ref},
+		}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateWebhookSecret sets the secret_token the tenant's webhook was
+// registered with
+func (c *DynamoClient) UpdateWebhookSecret(ctx context.Context, tenantID, secret string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET webhook_secret = :s"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s": &types.AttributeValueMemberS{Value: secret},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateWebhookState records the outcome of a webhook registration attempt
+func (c *DynamoClient) UpdateWebhookState(ctx context.Context, tenantID string, st WebhookState) error {
+	set := []string{"webhook_status = :s", "webhook_attempts = :a"}
+	var remove []string
+	values := map[string]types.AttributeValue{
+		":s": &types.AttributeValueMemberS{Value: string(st.Status)},
+		":a": &types.AttributeValueMemberN{Value: strconv.Itoa(st.Attempts)},
+	}
+	if st.Error != "" {
+		set = append(set, "webhook_error = :e")
+		values[":e"] = &types.AttributeValueMemberS{Value: st.Error}
+	} else {
+		remove = append(remove, "webhook_error")
+	}
+	if !st.RetryAt.IsZero() {
+		set = append(set, "webhook_retry_at = :r")
+		values[":r"] = &types.AttributeValueMemberS{Value: st.RetryAt.UTC().Format(time.RFC3339Nano)}
+	} else {
+		remove = append(remove, "webhook_retry_at")
+	}
+	expr := "SET " + strings.Join(set, ", ")
+	if len(remove) > 0 {
+		expr += " REMOVE " + strings.Join(remove, ", ")
+	}
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:          aws.String(expr),
+		ExpressionAttributeValues: values,
+		ConditionExpression:       aws.String("attribute_exists(tenant_id)"),
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
 // UpdateIdleTimeout updates the idle_timeout_s for a tenant
 func (c *DynamoClient) UpdateIdleTimeout(ctx context.Context, tenantID string, timeoutS int64) error {
 	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
@@ -169,92 +683,527 @@ func (c *DynamoClient) UpdateIdleTimeout(ctx context.Context, tenantID string, t
 	return err
 }
 
-// ListAll returns all tenant records (excluding internal warm-pool metadata).
-func (c *DynamoClient) ListAll(ctx context.Context) ([]*TenantRecord, error) {
-	out, err := c.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        aws.String(c.tableName),
-		FilterExpression: aws.String("attribute_exists(#s) AND tenant_id <> :meta"),
-		ExpressionAttributeNames: map[string]string{
-			"#s": "status",
+// UpdateBotUsername updates the bot_username (resolved via Telegram getMe) for a tenant
+func (c *DynamoClient) UpdateBotUsername(ctx context.Context, tenantID, username string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
 		},
+		UpdateExpression: aws.String("SET bot_username = :u"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":meta": &types.AttributeValueMemberS{Value: "warm-pool-meta"},
+			":u": &types.AttributeValueMemberS{Value: username},
 		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
 	})
-	if err != nil {
-		return nil, err
+	return err
+}
+
+// UpdateTier updates the service tier for a tenant
+func (c *DynamoClient) UpdateTier(ctx context.Context, tenantID, tier string) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET tier = :t"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":t": &types.AttributeValueMemberS{Value: tier},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateResize records the state of a tenant's resize operation; nil removes it
+func (c *DynamoClient) UpdateResize(ctx context.Context, tenantID string, op *ResizeOp) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE resize"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
 	}
-	var records []*TenantRecord
-	for _, item := range out.Items {
-		var rec TenantRecord
-		if err := attributevalue.UnmarshalMap(item, &rec); err != nil {
-			continue
+	if op != nil {
+		av, err := attributevalue.Marshal(op)
+		if err != nil {
+			return fmt.Errorf("marshal resize: %w", err)
 		}
-		records = append(records, &rec)
+		in.UpdateExpression = aws.String("SET resize = :r")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{":r": av}
 	}
-	return records, nil
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
 }
 
-// ListByStatus returns all tenants with the given status
-func (c *DynamoClient) ListByStatus(ctx context.Context, status TenantStatus) ([]*TenantRecord, error) {
-	out, err := c.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        aws.String(c.tableName),
-		FilterExpression: aws.String("#s = :status"),
-		ExpressionAttributeNames: map[string]string{
-			"#s": "status",
+// UpdateImage pins a tenant to an image alias or tag; empty unpins it
+func (c *DynamoClient) UpdateImage(ctx context.Context, tenantID, image string) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE image"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if image != "" {
+		in.UpdateExpression = aws.String("SET image = :i")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":i": &types.AttributeValueMemberS{Value: image},
+		}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateStateSize records a measurement of the tenant's S3 state and the
+// quota level it puts the tenant at
+func (c *DynamoClient) UpdateStateSize(ctx context.Context, tenantID string, bytes int64, level string, at time.Time) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
 		},
+		UpdateExpression:    aws.String("SET state_bytes = :b, state_quota_level = :l, state_measured_at = :t"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":status": &types.AttributeValueMemberS{Value: string(status)},
+			":b": &types.AttributeValueMemberN{Value: strconv.FormatInt(bytes, 10)},
+			":l": &types.AttributeValueMemberS{Value: level},
+			":t": &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339Nano)},
 		},
 	})
-	if err != nil {
-		return nil, fmt.Errorf("dynamodb Scan: %w", err)
+	return err
+}
+
+// UpdateLocale sets a tenant's system message locale; empty removes it
+func (c *DynamoClient) UpdateLocale(ctx context.Context, tenantID, locale string) error {
+	return c.setOrRemove(ctx, tenantID, "locale", locale)
+}
+
+// UpdateTimezone sets a tenant's time zone; empty removes it
+func (c *DynamoClient) UpdateTimezone(ctx context.Context, tenantID, timezone string) error {
+	return c.setOrRemove(ctx, tenantID, "timezone", timezone)
+}
+
+// setOrRemove sets a string attribute on an existing tenant, or removes it
+// if value is empty
+func (c *DynamoClient) setOrRemove(ctx context.Context, tenantID, attr, value string) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:         aws.String("REMOVE #a"),
+		ConditionExpression:      aws.String("attribute_exists(tenant_id)"),
+		ExpressionAttributeNames: map[string]string{"#a": attr},
+	}
+	if value != "" {
+		in.UpdateExpression = aws.String("SET #a = :v")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberS{Value: value},
+		}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateDNS sets a tenant's pod DNS override; nil removes it
+func (c *DynamoClient) UpdateDNS(ctx context.Context, tenantID string, dns *DNSConfig) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE dns"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if dns != nil {
+		av, err := attributevalue.Marshal(dns)
+		if err != nil {
+			return fmt.Errorf("marshal dns: %w", err)
+		}
+		in.UpdateExpression = aws.String("SET dns = :d")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{":d": av}
 	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateLogForward sets a tenant's log forwarding target; nil removes it
+func (c *DynamoClient) UpdateLogForward(ctx context.Context, tenantID string, cfg *LogForwardConfig) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE log_forward"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if cfg != nil {
+		av, err := attributevalue.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("marshal log_forward: %w", err)
+		}
+		in.UpdateExpression = aws.String("SET log_forward = :l")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{":l": av}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateAllowedUpdates sets a tenant's webhook update types; empty removes them
+func (c *DynamoClient) UpdateAllowedUpdates(ctx context.Context, tenantID string, updateTypes []string) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE allowed_updates"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if len(updateTypes) > 0 {
+		av, err := attributevalue.Marshal(updateTypes)
+		if err != nil {
+			return fmt.Errorf("marshal allowed_updates: %w", err)
+		}
+		in.UpdateExpression = aws.String("SET allowed_updates = :u")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{":u": av}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateSlack sets a tenant's Slack app credentials; nil removes them
+func (c *DynamoClient) UpdateSlack(ctx context.Context, tenantID string, cfg *SlackConfig) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE slack"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if cfg != nil {
+		av, err := attributevalue.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("marshal slack: %w", err)
+		}
+		in.UpdateExpression = aws.String("SET slack = :s")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{":s": av}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateKeepWarm sets whether a tenant is exempt from idle termination
+func (c *DynamoClient) UpdateKeepWarm(ctx context.Context, tenantID string, keepWarm bool) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET keep_warm = :k"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":k": &types.AttributeValueMemberBOOL{Value: keepWarm},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateNoAnnouncements sets whether a tenant's owner is left out of
+// broadcasts
+func (c *DynamoClient) UpdateNoAnnouncements(ctx context.Context, tenantID string, optOut bool) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET no_announcements = :n"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n": &types.AttributeValueMemberBOOL{Value: optOut},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateWeeklyDigest sets whether a tenant's owner gets the weekly digest
+func (c *DynamoClient) UpdateWeeklyDigest(ctx context.Context, tenantID string, on bool) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET weekly_digest = :d"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":d": &types.AttributeValueMemberBOOL{Value: on},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateDigestSentAt records when a tenant's weekly digest was last sent
+func (c *DynamoClient) UpdateDigestSentAt(ctx context.Context, tenantID string, at time.Time) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET digest_sent_at = :t"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":t": &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339Nano)},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// UpdateMaxMessageAge sets a tenant's maximum update age; zero removes it
+func (c *DynamoClient) UpdateMaxMessageAge(ctx context.Context, tenantID string, maxAgeS int64) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE max_message_age_s"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if maxAgeS != 0 {
+		in.UpdateExpression = aws.String("SET max_message_age_s = :a")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":a": &types.AttributeValueMemberN{Value: strconv.FormatInt(maxAgeS, 10)},
+		}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateMaxReplyBytes sets a tenant's maximum reply length; zero removes it
+func (c *DynamoClient) UpdateMaxReplyBytes(ctx context.Context, tenantID string, maxBytes int64) error {
+	in := &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression:    aws.String("REMOVE max_reply_bytes"),
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	}
+	if maxBytes != 0 {
+		in.UpdateExpression = aws.String("SET max_reply_bytes = :b")
+		in.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":b": &types.AttributeValueMemberN{Value: strconv.FormatInt(maxBytes, 10)},
+		}
+	}
+	_, err := c.db.UpdateItem(ctx, in)
+	return err
+}
+
+// UpdateDisabled sets or clears a tenant's kill switch
+func (c *DynamoClient) UpdateDisabled(ctx context.Context, tenantID string, disabled bool) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+		},
+		UpdateExpression: aws.String("SET disabled = :d"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":d": &types.AttributeValueMemberBOOL{Value: disabled},
+		},
+		ConditionExpression: aws.String("attribute_exists(tenant_id)"),
+	})
+	return err
+}
+
+// ListAll returns all tenant records (excluding internal warm-pool metadata).
+// It scans the whole table, a page of up to 1MB per request.
+func (c *DynamoClient) ListAll(ctx context.Context) ([]*TenantRecord, error) {
+	return c.scanStatus(ctx, "attribute_exists(#s) AND tenant_id <> :meta",
+		map[string]string{"#s": "status"},
+		map[string]types.AttributeValue{
+			":meta": &types.AttributeValueMemberS{Value: "warm-pool-meta"},
+		})
+}
+
+// ListByStatus returns all tenants with the given status
+func (c *DynamoClient) ListByStatus(ctx context.Context, status TenantStatus) ([]*TenantRecord, error) {
+	return c.queryStatus(ctx, "#s = :status", map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: string(status)},
+	})
+}
+
+// ListIdleTenants returns running tenants whose last_active_at is older than olderThan
+func (c *DynamoClient) ListIdleTenants(ctx context.Context, olderThan time.Duration) ([]*TenantRecord, error) {
+	cutoff := time.Now().UTC().Add(-olderThan).Format(time.RFC3339)
+	return c.queryStatus(ctx, "#s = :running AND last_active_at < :cutoff", map[string]types.AttributeValue{
+		":running": &types.AttributeValueMemberS{Value: string(StatusRunning)},
+		":cutoff":  &types.AttributeValueMemberS{Value: cutoff},
+	})
+}
+
+// queryStatus returns the tenants matching cond, a condition on status and
+// optionally last_active_at, by querying StatusIndex. Tables that predate the
+// index (see MigrateStatusIndex) are scanned with cond as a filter instead.
+func (c *DynamoClient) queryStatus(ctx context.Context, cond string, values map[string]types.AttributeValue) ([]*TenantRecord, error) {
+	names := map[string]string{"#s": "status"}
 	var records []*TenantRecord
-	for _, item := range out.Items {
+	var start map[string]types.AttributeValue
+	for {
+		out, err := c.db.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(c.tableName),
+			IndexName:                 aws.String(StatusIndex),
+			KeyConditionExpression:    aws.String(cond),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         start,
+		})
+		if isMissingIndex(err) {
+			slog.Warn("registry: status index missing, scanning; run orchestrator --bootstrap to add it", "table", c.tableName)
+			return c.scanStatus(ctx, cond, names, values)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb Query: %w", err)
+		}
+		records = appendRecords(records, out.Items)
+		if len(out.LastEvaluatedKey) == 0 {
+			return records, nil
+		}
+		start = out.LastEvaluatedKey
+	}
+}
+
+// scanStatus is queryStatus without the index: a scan, page by page, for
+// the records matching cond
+func (c *DynamoClient) scanStatus(ctx context.Context, cond string, names map[string]string, values map[string]types.AttributeValue) ([]*TenantRecord, error) {
+	var records []*TenantRecord
+	var start map[string]types.AttributeValue
+	for {
+		out, err := c.db.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(c.tableName),
+			FilterExpression:          aws.String(cond),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         start,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb Scan: %w", err)
+		}
+		records = appendRecords(records, out.Items)
+		if len(out.LastEvaluatedKey) == 0 {
+			return records, nil
+		}
+		start = out.LastEvaluatedKey
+	}
+}
+
+// appendRecords unmarshals items onto records, skipping malformed ones
+func appendRecords(records []*TenantRecord, items []map[string]types.AttributeValue) []*TenantRecord {
+	for _, item := range items {
 		var rec TenantRecord
 		if err := attributevalue.UnmarshalMap(item, &rec); err != nil {
 			continue
 		}
 		records = append(records, &rec)
 	}
-	return records, nil
+	return records
 }
 
-// ListIdleTenants returns running tenants whose last_active_at is older than olderThan
-func (c *DynamoClient) ListIdleTenants(ctx context.Context, olderThan time.Duration) ([]*TenantRecord, error) {
-	cutoff := time.Now().UTC().Add(-olderThan).Format(time.RFC3339)
+// isMissingIndex reports whether err is DynamoDB rejecting a query on an
+// index the table doesn't have or is still backfilling
+func isMissingIndex(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		return false
+	}
+	msg := apiErr.ErrorMessage()
+	return strings.Contains(msg, "specified index") || strings.Contains(msg, "backfilling")
+}
+
+// DeleteTenant removes a tenant record
+func (c *DynamoClient) DeleteTenant(ctx context.Context, tenantID string) error {
+	for _, key := range []string{tenantID, wakeHistoryKeyPrefix + tenantID, eventLogKeyPrefix + tenantID, kvKeyPrefix + tenantID} {
+		_, err := c.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(c.tableName),
+			Key: map[string]types.AttributeValue{
+				"tenant_id": &types.AttributeValueMemberS{Value: key},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetImageAlias fetches an image catalog entry (nil if the alias is not defined)
+func (c *DynamoClient) GetImageAlias(ctx context.Context, alias string) (*ImageAlias, error) {
+	out, err := c.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: imageAliasKeyPrefix + alias},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+	var a ImageAlias
+	if err := attributevalue.UnmarshalMap(out.Item, &a); err != nil {
+		return nil, fmt.Errorf("unmarshal image alias: %w", err)
+	}
+	return &a, nil
+}
+
+// PutImageAlias creates or repoints an image catalog entry
+func (c *DynamoClient) PutImageAlias(ctx context.Context, alias *ImageAlias) error {
+	item, err := attributevalue.MarshalMap(alias)
+	if err != nil {
+		return fmt.Errorf("marshal image alias: %w", err)
+	}
+	item["tenant_id"] = &types.AttributeValueMemberS{Value: imageAliasKeyPrefix + alias.Alias}
+	_, err = c.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(c.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb PutItem: %w", err)
+	}
+	return nil
+}
+
+// ListImageAliases returns all image catalog entries
+func (c *DynamoClient) ListImageAliases(ctx context.Context) ([]*ImageAlias, error) {
 	out, err := c.db.Scan(ctx, &dynamodb.ScanInput{
 		TableName:        aws.String(c.tableName),
-		FilterExpression: aws.String("#s = :running AND last_active_at < :cutoff"),
-		ExpressionAttributeNames: map[string]string{
-			"#s": "status",
-		},
+		FilterExpression: aws.String("begins_with(tenant_id, :p)"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":running": &types.AttributeValueMemberS{Value: string(StatusRunning)},
-			":cutoff":  &types.AttributeValueMemberS{Value: cutoff},
+			":p": &types.AttributeValueMemberS{Value: imageAliasKeyPrefix},
 		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("dynamodb Scan: %w", err)
 	}
-	var records []*TenantRecord
+	var aliases []*ImageAlias
 	for _, item := range out.Items {
-		var rec TenantRecord
-		if err := attributevalue.UnmarshalMap(item, &rec); err != nil {
+		var a ImageAlias
+		if err := attributevalue.UnmarshalMap(item, &a); err != nil {
 			continue
 		}
-		records = append(records, &rec)
+		aliases = append(aliases, &a)
 	}
-	return records, nil
+	return aliases, nil
 }
 
-// DeleteTenant removes a tenant record
-func (c *DynamoClient) DeleteTenant(ctx context.Context, tenantID string) error {
+// DeleteImageAlias removes an image catalog entry
+func (c *DynamoClient) DeleteImageAlias(ctx context.Context, alias string) error {
 	_, err := c.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(c.tableName),
 		Key: map[string]types.AttributeValue{
-			"tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+			"tenant_id": &types.AttributeValueMemberS{Value: imageAliasKeyPrefix + alias},
 		},
 	})
 	return err
@@ -2,9 +2,16 @@ package registry_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/shawn/agentic-tenancy/internal/registry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -126,3 +133,150 @@ func TestMock_DeleteNonExistent(t *testing.T) {
 	// Should not error
 	assert.NoError(t, m.DeleteTenant(context.Background(), "ghost"))
 }
+
+func TestMock_ImageAliases(t *testing.T) {
+	m := registry.NewMock()
+	ctx := context.Background()
+
+	require.NoError(t, m.PutImageAlias(ctx, &registry.ImageAlias{Alias: "stable", Image: "zeroclaw:v1.4.0"}))
+	require.NoError(t, m.PutImageAlias(ctx, &registry.ImageAlias{Alias: "stable", Image: "zeroclaw:v1.5.0"}))
+
+	got, err := m.GetImageAlias(ctx, "stable")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "zeroclaw:v1.5.0", got.Image, "put should repoint an existing alias")
+
+	aliases, err := m.ListImageAliases(ctx)
+	require.NoError(t, err)
+	assert.Len(t, aliases, 1)
+
+	require.NoError(t, m.DeleteImageAlias(ctx, "stable"))
+	got, err = m.GetImageAlias(ctx, "stable")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestMock_WakeHistory(t *testing.T) {
+	m := registry.NewMock()
+	ctx := context.Background()
+	require.NoError(t, m.CreateTenant(ctx, newRecord("tenant-w")))
+
+	for i := range 4 {
+		require.NoError(t, m.RecordWake(ctx, "tenant-w", registry.WakeAttempt{DurationMs: int64(i), Outcome: registry.WakeOK}, 3))
+	}
+	wakes, err := m.ListWakes(ctx, "tenant-w")
+	require.NoError(t, err)
+	require.Len(t, wakes, 3)
+	assert.Equal(t, int64(3), wakes[0].DurationMs, "newest first")
+	assert.Equal(t, int64(1), wakes[2].DurationMs, "oldest beyond keep dropped")
+
+	require.NoError(t, m.DeleteTenant(ctx, "tenant-w"))
+	wakes, _ = m.ListWakes(ctx, "tenant-w")
+	assert.Empty(t, wakes)
+}
+
+func TestMock_Usage(t *testing.T) {
+	m := registry.NewMock()
+	ctx := context.Background()
+	day1 := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+
+	require.NoError(t, m.AddUsage(ctx, "tenant-u", day1, registry.Usage{Messages: 1}))
+	require.NoError(t, m.AddUsage(ctx, "tenant-u", day1, registry.Usage{Wakes: 1, PodSeconds: 60}))
+	require.NoError(t, m.AddUsage(ctx, "tenant-u", day2, registry.Usage{Messages: 2}))
+
+	days, err := m.ListUsage(ctx, "tenant-u", day1, day2)
+	require.NoError(t, err)
+	assert.Equal(t, []registry.UsageDay{
+		{Date: "2026-03-01", Usage: registry.Usage{Messages: 1, Wakes: 1, PodSeconds: 60}},
+		{Date: "2026-03-02", Usage: registry.Usage{Messages: 2}},
+	}, days)
+
+	days, _ = m.ListUsage(ctx, "tenant-u", day2, day2)
+	assert.Len(t, days, 1)
+
+	require.NoError(t, m.SetChats(ctx, "tenant-u", "2026-03-02", 4))
+	require.NoError(t, m.SetChats(ctx, "tenant-u", "2026-02", 9))
+	require.NoError(t, m.SetChats(ctx, "tenant-u", "2026-03", 5))
+	days, _ = m.ListUsage(ctx, "tenant-u", day2, day2)
+	assert.Equal(t, int64(4), days[0].Chats)
+	months, err := m.ListChatMonths(ctx, "tenant-u", day1.AddDate(0, -1, 0), day2)
+	require.NoError(t, err)
+	assert.Equal(t, []registry.UsageMonth{{Month: "2026-02", Chats: 9}, {Month: "2026-03", Chats: 5}}, months)
+
+	_, err = m.ListUsage(ctx, "tenant-u", day1.AddDate(-2, 0, 0), day1)
+	assert.Error(t, err, "range beyond MaxUsageDays")
+
+	// Usage outlives the tenant, so its last period can be billed
+	require.NoError(t, m.DeleteTenant(ctx, "tenant-u"))
+	days, _ = m.ListUsage(ctx, "tenant-u", day1, day2)
+	assert.Len(t, days, 2)
+}
+
+func TestDual(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := registry.NewMock(), registry.NewMock()
+	d, err := registry.NewDual(primary, secondary, registry.ReadPrimary)
+	require.NoError(t, err)
+
+	require.NoError(t, d.CreateTenant(ctx, newRecord("tenant-d")))
+	require.NoError(t, d.UpdateTier(ctx, "tenant-d", "premium"))
+	for _, m := range []*registry.MockClient{primary, secondary} {
+		got, err := m.GetTenant(ctx, "tenant-d")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "premium", got.Tier)
+	}
+
+	// A write the primary refuses isn't mirrored
+	assert.Error(t, d.CreateTenant(ctx, newRecord("tenant-d")))
+	assert.Error(t, d.UpdateTier(ctx, "missing", "free"))
+
+	// One only the secondary misses succeeds, leaving the tables to sync
+	require.NoError(t, primary.CreateTenant(ctx, newRecord("tenant-p")))
+	require.NoError(t, d.UpdateTier(ctx, "tenant-p", "free"))
+	got, _ := d.GetTenant(ctx, "tenant-p")
+	require.NotNil(t, got)
+
+	d, err = registry.NewDual(primary, secondary, registry.ReadSecondary)
+	require.NoError(t, err)
+	got, err = d.GetTenant(ctx, "tenant-p")
+	require.NoError(t, err)
+	assert.Nil(t, got, "reads come from the secondary")
+
+	_, err = registry.NewDual(primary, secondary, "both")
+	assert.Error(t, err)
+}
+
+// TestDynamo_ListAllPages: a scan larger than one page is read to the end
+func TestDynamo_ListAllPages(t *testing.T) {
+	var starts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "DynamoDB_20120810.Scan", r.Header.Get("X-Amz-Target"))
+		var in struct {
+			ExclusiveStartKey map[string]map[string]string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if in.ExclusiveStartKey == nil {
+			starts = append(starts, "")
+			fmt.Fprint(w, `{"Items":[{"tenant_id":{"S":"alice"},"status":{"S":"idle"}}],"LastEvaluatedKey":{"tenant_id":{"S":"alice"}}}`)
+			return
+		}
+		starts = append(starts, in.ExclusiveStartKey["tenant_id"]["S"])
+		fmt.Fprint(w, `{"Items":[{"tenant_id":{"S":"bob"},"status":{"S":"running"}}]}`)
+	}))
+	defer srv.Close()
+
+	db := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+	})
+	records, err := registry.New(db, "tenants").ListAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "alice", records[0].TenantID)
+	assert.Equal(t, "bob", records[1].TenantID)
+	assert.Equal(t, []string{"", "alice"}, starts)
+}
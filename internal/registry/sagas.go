@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// sagaKeyPrefix namespaces saga progress items in the tenant table. Like
+// image catalog items they carry no status attribute, so tenant scans and
+// the status index never return them.
+const sagaKeyPrefix = "saga#"
+
+// SagaState is where a saga is in its life
+type SagaState string
+
+const (
+	SagaRunning      SagaState = "running"      // steps are being run
+	SagaCompensating SagaState = "compensating" // a step failed; started steps are being undone
+	SagaDone         SagaState = "done"         // every step succeeded; the record only awaits deletion
+)
+
+// Saga records the progress of a multi-step operation (see package saga) so
+// that a crashed or failed one can be undone later. An item exists only
+// while the operation is unfinished.
+type Saga struct {
+	ID       string    `dynamodbav:"saga_id" json:"saga_id"`
+	Kind     string    `dynamodbav:"kind" json:"kind"`
+	TenantID string    `dynamodbav:"saga_tenant" json:"tenant_id"`
+	State    SagaState `dynamodbav:"saga_state" json:"state"`
+	// Steps are the names of the steps started so far, in order
+	Steps []string `dynamodbav:"steps,omitempty" json:"steps,omitempty"`
+	// Error is the step or undo failure that stopped the saga
+	Error     string    `dynamodbav:"error,omitempty" json:"error,omitempty"`
+	StartedAt time.Time `dynamodbav:"started_at" json:"started_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// PutSaga creates or updates a saga's progress
+func (c *DynamoClient) PutSaga(ctx context.Context, s *Saga) error {
+	item, err := attributevalue.MarshalMap(s)
+	if err != nil {
+		return fmt.Errorf("marshal saga: %w", err)
+	}
+	item["tenant_id"] = &types.AttributeValueMemberS{Value: sagaKeyPrefix + s.ID}
+	_, err = c.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(c.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb PutItem: %w", err)
+	}
+	return nil
+}
+
+// DeleteSaga removes a finished saga
+func (c *DynamoClient) DeleteSaga(ctx context.Context, id string) error {
+	_, err := c.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: sagaKeyPrefix + id},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb DeleteItem: %w", err)
+	}
+	return nil
+}
+
+// ListSagas returns every unfinished saga
+func (c *DynamoClient) ListSagas(ctx context.Context) ([]*Saga, error) {
+	var sagas []*Saga
+	var start map[string]types.AttributeValue
+	for {
+		out, err := c.db.Scan(ctx, &dynamodb.ScanInput{
+			TableName:        aws.String(c.tableName),
+			FilterExpression: aws.String("begins_with(tenant_id, :p)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":p": &types.AttributeValueMemberS{Value: sagaKeyPrefix},
+			},
+			ExclusiveStartKey: start,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb Scan: %w", err)
+		}
+		for _, item := range out.Items {
+			var s Saga
+			if err := attributevalue.UnmarshalMap(item, &s); err != nil {
+				continue
+			}
+			sagas = append(sagas, &s)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return sagas, nil
+		}
+		start = out.LastEvaluatedKey
+	}
+}
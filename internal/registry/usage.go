@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// usageKeyPrefix namespaces usage items in the tenant table: one per tenant
+// per UTC day, "usage#<tenant>#<YYYY-MM-DD>". Like wake history items they
+// carry no status attribute. They are kept when the tenant is deleted, so
+// its last period can still be billed.
+const usageKeyPrefix = "usage#"
+
+// UsageDateFormat is the layout of UsageDay.Date
+const UsageDateFormat = "2006-01-02"
+
+// UsageMonthFormat is the layout of UsageMonth.Month. Month items share the
+// day items' key scheme, "usage#<tenant>#<YYYY-MM>", and hold only chats.
+const UsageMonthFormat = "2006-01"
+
+// MaxUsageDays bounds the days ListUsage reads in one call
+const MaxUsageDays = 366
+
+// batchGetMax is the most keys a DynamoDB BatchGetItem accepts
+const batchGetMax = 100
+
+// charsPerToken is the rough number of characters in an LLM token, for
+// English text. Tenants' models have their own tokenizers, so the token
+// counts are only estimates.
+const charsPerToken = 4
+
+// Usage is what a tenant consumed, for billing. Characters are Unicode code
+// points of message text; attachments aren't counted.
+type Usage struct {
+	Messages   int64 `dynamodbav:"messages" json:"messages"`       // updates delivered to the agent
+	Wakes      int64 `dynamodbav:"wakes" json:"wakes"`             // successful wakes
+	PodSeconds int64 `dynamodbav:"pod_seconds" json:"pod_seconds"` // time a pod was running
+	CharsIn    int64 `dynamodbav:"chars_in" json:"chars_in"`       // message text sent to the agent
+	CharsOut   int64 `dynamodbav:"chars_out" json:"chars_out"`     // reply text from the agent
+	TokensIn   int64 `dynamodbav:"tokens_in" json:"tokens_in"`     // estimated from CharsIn
+	TokensOut  int64 `dynamodbav:"tokens_out" json:"tokens_out"`   // estimated from CharsOut
+}
+
+// Add returns the sum of u and o
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		Messages:   u.Messages + o.Messages,
+		Wakes:      u.Wakes + o.Wakes,
+		PodSeconds: u.PodSeconds + o.PodSeconds,
+		CharsIn:    u.CharsIn + o.CharsIn,
+		CharsOut:   u.CharsOut + o.CharsOut,
+		TokensIn:   u.TokensIn + o.TokensIn,
+		TokensOut:  u.TokensOut + o.TokensOut,
+	}
+}
+
+// MessageUsage returns the usage of one delivered message with charsIn
+// characters of text and a reply of charsOut, estimating its tokens. Each
+// message is rounded up to whole tokens.
+func MessageUsage(charsIn, charsOut int64) Usage {
+	return Usage{
+		Messages:  1,
+		CharsIn:   charsIn,
+		CharsOut:  charsOut,
+		TokensIn:  (charsIn + charsPerToken - 1) / charsPerToken,
+		TokensOut: (charsOut + charsPerToken - 1) / charsPerToken,
+	}
+}
+
+// UsageDay is a tenant's usage on one UTC day
+type UsageDay struct {
+	Date string `dynamodbav:"date" json:"date"` // UsageDateFormat
+	Usage
+	// Chats is the distinct chats that messaged the agent that day; unlike
+	// the rest of Usage it doesn't add up across days (see UsageMonth)
+	Chats int64 `dynamodbav:"chats" json:"chats"`
+}
+
+// UsageMonth is the distinct chats that messaged a tenant's agent in one
+// UTC calendar month: its monthly active chats
+type UsageMonth struct {
+	Month string `dynamodbav:"date" json:"month"` // UsageMonthFormat
+	Chats int64  `dynamodbav:"chats" json:"chats"`
+	// Messages is the messages delivered that month, filled in by the API
+	// from the days it reports
+	Messages int64 `dynamodbav:"-" json:"messages"`
+}
+
+// usageDays returns the UTC dates from from to to inclusive, oldest first
+func usageDays(from, to time.Time) []string {
+	var days []string
+	for d := from.UTC().Truncate(24 * time.Hour); !d.After(to.UTC()); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(UsageDateFormat))
+	}
+	return days
+}
+
+// usageMonths returns the UTC months from from to to inclusive, oldest first
+func usageMonths(from, to time.Time) []string {
+	var months []string
+	first := time.Date(from.UTC().Year(), from.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := first; !m.After(to.UTC()); m = m.AddDate(0, 1, 0) {
+		months = append(months, m.Format(UsageMonthFormat))
+	}
+	return months
+}
+
+func usageItemKey(tenantID, date string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: usageKeyPrefix + tenantID + "#" + date},
+	}
+}
+
+// AddUsage adds delta to the tenant's usage for the UTC day of at
+func (c *DynamoClient) AddUsage(ctx context.Context, tenantID string, at time.Time, delta Usage) error {
+	date := at.UTC().Format(UsageDateFormat)
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(c.tableName),
+		Key:       usageItemKey(tenantID, date),
+		UpdateExpression: aws.String("SET #d = :d ADD messages :m, wakes :w, pod_seconds :p, " +
+			"chars_in :ci, chars_out :co, tokens_in :ti, tokens_out :to"),
+		ExpressionAttributeNames: map[string]string{
+			"#d": "date",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":d":  &types.AttributeValueMemberS{Value: date},
+			":m":  &types.AttributeValueMemberN{Value: fmt.Sprint(delta.Messages)},
+			":w":  &types.AttributeValueMemberN{Value: fmt.Sprint(delta.Wakes)},
+			":p":  &types.AttributeValueMemberN{Value: fmt.Sprint(delta.PodSeconds)},
+			":ci": &types.AttributeValueMemberN{Value: fmt.Sprint(delta.CharsIn)},
+			":co": &types.AttributeValueMemberN{Value: fmt.Sprint(delta.CharsOut)},
+			":ti": &types.AttributeValueMemberN{Value: fmt.Sprint(delta.TokensIn)},
+			":to": &types.AttributeValueMemberN{Value: fmt.Sprint(delta.TokensOut)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// SetChats records the distinct chats that messaged the tenant's agent in
+// period, a UTC day (UsageDateFormat) or month (UsageMonthFormat). The count
+// is kept by the caller, so it replaces the stored one.
+func (c *DynamoClient) SetChats(ctx context.Context, tenantID, period string, chats int64) error {
+	_, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(c.tableName),
+		Key:              usageItemKey(tenantID, period),
+		UpdateExpression: aws.String("SET #d = :d, chats = :c"),
+		ExpressionAttributeNames: map[string]string{
+			"#d": "date",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":d": &types.AttributeValueMemberS{Value: period},
+			":c": &types.AttributeValueMemberN{Value: fmt.Sprint(chats)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// ListUsage returns the tenant's usage for each UTC day from from to to
+// inclusive on which it used anything, oldest first. The range may span at
+// most MaxUsageDays days.
+func (c *DynamoClient) ListUsage(ctx context.Context, tenantID string, from, to time.Time) ([]UsageDay, error) {
+	days := usageDays(from, to)
+	if len(days) > MaxUsageDays {
+		return nil, fmt.Errorf("usage range of %d days exceeds %d", len(days), MaxUsageDays)
+	}
+	var result []UsageDay
+	err := c.getUsageItems(ctx, tenantID, days, func(item map[string]types.AttributeValue) error {
+		var day UsageDay
+		if err := attributevalue.UnmarshalMap(item, &day); err != nil {
+			return fmt.Errorf("unmarshal usage: %w", err)
+		}
+		result = append(result, day)
+		return nil
+	})
+	return result, err
+}
+
+// ListChatMonths returns the distinct chats of each UTC month from from's
+// to to's on which the tenant's agent was messaged, oldest first
+func (c *DynamoClient) ListChatMonths(ctx context.Context, tenantID string, from, to time.Time) ([]UsageMonth, error) {
+	var result []UsageMonth
+	err := c.getUsageItems(ctx, tenantID, usageMonths(from, to), func(item map[string]types.AttributeValue) error {
+		var month UsageMonth
+		if err := attributevalue.UnmarshalMap(item, &month); err != nil {
+			return fmt.Errorf("unmarshal usage: %w", err)
+		}
+		result = append(result, month)
+		return nil
+	})
+	return result, err
+}
+
+// getUsageItems calls fn with the tenant's usage item of each of periods
+// that exists, in the order of periods
+func (c *DynamoClient) getUsageItems(ctx context.Context, tenantID string, periods []string,
+	fn func(map[string]types.AttributeValue) error) error {
+	found := make(map[string]map[string]types.AttributeValue, len(periods))
+	for start := 0; start < len(periods); start += batchGetMax {
+		var keys []map[string]types.AttributeValue
+		for _, p := range periods[start:min(start+batchGetMax, len(periods))] {
+			keys = append(keys, usageItemKey(tenantID, p))
+		}
+		req := map[string]types.KeysAndAttributes{c.tableName: {Keys: keys}}
+		// Throttled keys come back unprocessed; ask again until none are
+		for len(req) > 0 {
+			out, err := c.db.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: req})
+			if err != nil {
+				return fmt.Errorf("dynamodb BatchGetItem: %w", err)
+			}
+			for _, item := range out.Responses[c.tableName] {
+				if d, ok := item["date"].(*types.AttributeValueMemberS); ok {
+					found[d.Value] = item
+				}
+			}
+			req = out.UnprocessedKeys
+		}
+	}
+	for _, p := range periods {
+		if item, ok := found[p]; ok {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
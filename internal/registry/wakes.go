@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// wakeHistoryKeyPrefix namespaces wake history items in the tenant table.
+// Like image catalog items they carry no status attribute, so tenant scans
+// never return them, and keeping them apart keeps tenant records small.
+const wakeHistoryKeyPrefix = "wakes#"
+
+// Wake outcomes
+const (
+	WakeOK     = "ok"
+	WakeFailed = "failed"
+)
+
+// WakeAttempt is one entry of a tenant's wake history
+type WakeAttempt struct {
+	StartedAt  time.Time `dynamodbav:"started_at" json:"started_at"`
+	DurationMs int64     `dynamodbav:"duration_ms" json:"duration_ms"`
+	// Start is "warm" if a warm pod's node was claimed, "cold" otherwise, and
+	// empty if the wake failed before reaching the warm pool.
+	Start   string `dynamodbav:"start,omitempty" json:"start,omitempty"`
+	Outcome string `dynamodbav:"outcome" json:"outcome"`
+	Error   string `dynamodbav:"error,omitempty" json:"error,omitempty"`
+}
+
+// RecordWake appends a wake attempt to the tenant's history, dropping the
+// oldest entries beyond keep. Concurrent appends for one tenant (rare, since
+// wakes hold the wake lock) may trim one entry too many.
+func (c *DynamoClient) RecordWake(ctx context.Context, tenantID string, attempt WakeAttempt, keep int) error {
+	av, err := attributevalue.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("marshal wake attempt: %w", err)
+	}
+	return c.appendBounded(ctx, wakeHistoryKeyPrefix+tenantID, "wakes", av, keep)
+}
+
+// appendBounded appends av to the list attribute attr of the item keyed id,
+// then trims the list's oldest entries beyond keep
+func (c *DynamoClient) appendBounded(ctx context.Context, id, attr string, av types.AttributeValue, keep int) error {
+	key := map[string]types.AttributeValue{
+		"tenant_id": &types.AttributeValueMemberS{Value: id},
+	}
+	out, err := c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(c.tableName),
+		Key:              key,
+		UpdateExpression: aws.String("SET #l = list_append(if_not_exists(#l, :empty), :v)"),
+		ExpressionAttributeNames: map[string]string{
+			"#l": attr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":empty": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":v":     &types.AttributeValueMemberL{Value: []types.AttributeValue{av}},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	list, _ := out.Attributes[attr].(*types.AttributeValueMemberL)
+	if list == nil || len(list.Value) <= keep {
+		return nil
+	}
+	var drop []string
+	for i := range len(list.Value) - keep {
+		drop = append(drop, fmt.Sprintf("#l[%d]", i))
+	}
+	_, err = c.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(c.tableName),
+		Key:              key,
+		UpdateExpression: aws.String("REMOVE " + strings.Join(drop, ", ")),
+		ExpressionAttributeNames: map[string]string{
+			"#l": attr,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb UpdateItem: %w", err)
+	}
+	return nil
+}
+
+// ListWakes returns the tenant's wake history, newest first
+func (c *DynamoClient) ListWakes(ctx context.Context, tenantID string) ([]WakeAttempt, error) {
+	out, err := c.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]types.AttributeValue{
+			"tenant_id": &types.AttributeValueMemberS{Value: wakeHistoryKeyPrefix + tenantID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb GetItem: %w", err)
+	}
+	var item struct {
+		Wakes []WakeAttempt `dynamodbav:"wakes"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return nil, fmt.Errorf("unmarshal wake history: %w", err)
+	}
+	return newestFirst(item.Wakes), nil
+}
+
+// newestFirst reverses a history stored oldest first
+func newestFirst[T any](history []T) []T {
+	out := make([]T, len(history))
+	for i, e := range history {
+		out[len(history)-1-i] = e
+	}
+	return out
+}
@@ -0,0 +1,181 @@
+// Package saga runs operations that span the registry, Kubernetes and
+// Telegram as a sequence of undoable steps. None of those systems share a
+// transaction, so instead each step has a compensating Undo: if a step fails,
+// every step started so far is undone in reverse order. Progress is recorded
+// in the registry before each step, so an operation interrupted by a crash,
+// or whose undo failed, can be compensated later by the reconciler.
+package saga
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// StaleAfter is how long a saga may go without progress before it is
+// considered abandoned by a crashed orchestrator
+const StaleAfter = 10 * time.Minute
+
+// clearAttempts and clearBackoff bound the retries of deleting a finished
+// saga's record
+const (
+	clearAttempts = 3
+	clearBackoff  = 200 * time.Millisecond
+)
+
+// Step is one part of a saga
+type Step struct {
+	Name string
+	Do   func(ctx context.Context) error
+	// Undo reverses Do. It also runs for the step that failed and for a step
+	// interrupted by a crash, so it must be idempotent and cope with Do
+	// having had no effect. Nil if there is nothing to undo.
+	Undo func(ctx context.Context) error
+}
+
+// StepError is returned by Run when a step fails
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string { return e.Step + ": " + e.Err.Error() }
+func (e *StepError) Unwrap() error { return e.Err }
+
+// Store persists saga progress; registry.Client satisfies it
+type Store interface {
+	PutSaga(ctx context.Context, s *registry.Saga) error
+	DeleteSaga(ctx context.Context, id string) error
+}
+
+// New starts recording a saga of the given kind for a tenant. The ID is
+// derived from both, so a tenant has at most one saga of each kind.
+func New(kind, tenantID string) *registry.Saga {
+	now := time.Now().UTC()
+	return &registry.Saga{
+		ID:        kind + ":" + tenantID,
+		Kind:      kind,
+		TenantID:  tenantID,
+		State:     registry.SagaRunning,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Run runs steps in order. If one fails, the steps started so far are
+// compensated and a *StepError is returned; if compensation fails too, the
+// saga is left in the store for the reconciler to finish.
+func Run(ctx context.Context, store Store, s *registry.Saga, steps []Step) error {
+	for _, step := range steps {
+		s.Steps = append(s.Steps, step.Name)
+		s.UpdatedAt = time.Now().UTC()
+		if err := store.PutSaga(ctx, s); err != nil {
+			// Nothing of this step has happened yet
+			s.Steps = s.Steps[:len(s.Steps)-1]
+			err = fmt.Errorf("record saga: %w", err)
+			return compensateAfter(ctx, store, s, steps, err)
+		}
+		if err := step.Do(ctx); err != nil {
+			return compensateAfter(ctx, store, s, steps, &StepError{Step: step.Name, Err: err})
+		}
+	}
+	finish(context.WithoutCancel(ctx), store, s)
+	return nil
+}
+
+// finish clears the record of a saga whose steps all succeeded. It is marked
+// done first, so a record a failed delete leaves behind is only ever
+// cleared, never compensated.
+func finish(ctx context.Context, store Store, s *registry.Saga) {
+	s.State = registry.SagaDone
+	s.UpdatedAt = time.Now().UTC()
+	marked := store.PutSaga(ctx, s) == nil
+	var err error
+	for attempt := 1; attempt <= clearAttempts; attempt++ {
+		if err = store.DeleteSaga(ctx, s.ID); err == nil {
+			return
+		}
+		if attempt < clearAttempts {
+			time.Sleep(time.Duration(attempt) * clearBackoff)
+		}
+	}
+	if marked {
+		slog.Warn("saga: failed to clear finished saga, reconciler will retry", "saga", s.ID, "err", err)
+		return
+	}
+	// Left as running, the reconciler would undo an operation that succeeded
+	slog.Error("saga: failed to clear or mark finished saga", "saga", s.ID, "tenant", s.TenantID, "err", err)
+}
+
+// compensateAfter undoes a failed run and returns its cause. The undo runs
+// even if the caller has gone away, e.g. an HTTP client disconnected.
+func compensateAfter(ctx context.Context, store Store, s *registry.Saga, steps []Step, cause error) error {
+	s.Error = cause.Error()
+	if err := Compensate(context.WithoutCancel(ctx), store, s, steps); err != nil {
+		slog.Error("saga: compensation failed, reconciler will retry", "saga", s.ID, "tenant", s.TenantID, "err", err)
+	}
+	return cause
+}
+
+// Compensate undoes the saga's started steps, newest first. steps supplies
+// each Undo by name. The record shrinks as steps are undone and is deleted
+// once none are left; on the first failed undo it is saved with the error
+// and the steps still to undo, and the error is returned. A done saga is
+// only deleted: its steps all succeeded and are never undone.
+func Compensate(ctx context.Context, store Store, s *registry.Saga, steps []Step) error {
+	if s.State == registry.SagaDone {
+		if err := store.DeleteSaga(ctx, s.ID); err != nil {
+			return fmt.Errorf("clear finished saga: %w", err)
+		}
+		return nil
+	}
+	byName := make(map[string]Step, len(steps))
+	for _, step := range steps {
+		byName[step.Name] = step
+	}
+	s.State = registry.SagaCompensating
+	for len(s.Steps) > 0 {
+		name := s.Steps[len(s.Steps)-1]
+		step, ok := byName[name]
+		if !ok {
+			return save(ctx, store, s, fmt.Errorf("undo %s: unknown step", name))
+		}
+		if step.Undo != nil {
+			if err := step.Undo(ctx); err != nil {
+				return save(ctx, store, s, fmt.Errorf("undo %s: %w", name, err))
+			}
+		}
+		s.Steps = s.Steps[:len(s.Steps)-1]
+	}
+	if err := store.DeleteSaga(ctx, s.ID); err != nil {
+		return fmt.Errorf("clear compensated saga: %w", err)
+	}
+	return nil
+}
+
+// save records a failed compensation so it can be resumed, returning err
+func save(ctx context.Context, store Store, s *registry.Saga, err error) error {
+	s.Error = err.Error()
+	s.UpdatedAt = time.Now().UTC()
+	if perr := store.PutSaga(ctx, s); perr != nil {
+		slog.Error("saga: failed to record compensation progress", "saga", s.ID, "err", perr)
+	}
+	return err
+}
+
+// Resumable reports whether the reconciler should compensate s: its own
+// compensation failed, or it has made no progress for StaleAfter, meaning the
+// orchestrator running it died. A done saga is always resumable, which for
+// Compensate means deleting its leftover record.
+func Resumable(s *registry.Saga, now time.Time) bool {
+	if s.State == registry.SagaDone {
+		return true
+	}
+	if s.State == registry.SagaCompensating && s.Error != "" {
+		return true
+	}
+	return now.Sub(s.UpdatedAt) > StaleAfter
+}
@@ -0,0 +1,132 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder builds steps that log their Do and Undo calls
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) step(name string, doErr, undoErr error) saga.Step {
+	return saga.Step{
+		Name: name,
+		Do: func(context.Context) error {
+			r.calls = append(r.calls, "do "+name)
+			return doErr
+		},
+		Undo: func(context.Context) error {
+			r.calls = append(r.calls, "undo "+name)
+			return undoErr
+		},
+	}
+}
+
+func TestRun_Success(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	r := &recorder{}
+
+	s := saga.New("create-tenant", "alice")
+	require.NoError(t, saga.Run(ctx, reg, s, []saga.Step{r.step("a", nil, nil), r.step("b", nil, nil)}))
+
+	assert.Equal(t, []string{"do a", "do b"}, r.calls)
+	sagas, _ := reg.ListSagas(ctx)
+	assert.Empty(t, sagas, "finished sagas are cleared")
+}
+
+func TestRun_FailureCompensatesInReverse(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	r := &recorder{}
+	boom := errors.New("boom")
+
+	s := saga.New("create-tenant", "alice")
+	err := saga.Run(ctx, reg, s, []saga.Step{
+		r.step("a", nil, nil), r.step("b", boom, nil), r.step("c", nil, nil),
+	})
+
+	var stepErr *saga.StepError
+	require.ErrorAs(t, err, &stepErr)
+	assert.Equal(t, "b", stepErr.Step)
+	assert.ErrorIs(t, err, boom)
+	// The failed step is undone too; the step never started is not
+	assert.Equal(t, []string{"do a", "do b", "undo b", "undo a"}, r.calls)
+	sagas, _ := reg.ListSagas(ctx)
+	assert.Empty(t, sagas)
+}
+
+func TestRun_FailedUndoIsLeftForResume(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	r := &recorder{}
+
+	s := saga.New("create-tenant", "alice")
+	steps := []saga.Step{r.step("a", nil, nil), r.step("b", nil, errors.New("unreachable")), r.step("c", errors.New("boom"), nil)}
+	require.Error(t, saga.Run(ctx, reg, s, steps))
+	assert.Equal(t, []string{"do a", "do b", "do c", "undo c", "undo b"}, r.calls)
+
+	sagas, _ := reg.ListSagas(ctx)
+	require.Len(t, sagas, 1)
+	left := sagas[0]
+	assert.Equal(t, registry.SagaCompensating, left.State)
+	assert.Equal(t, []string{"a", "b"}, left.Steps, "steps still to undo")
+	assert.Contains(t, left.Error, "undo b")
+	assert.True(t, saga.Resumable(left, time.Now()))
+
+	// Resuming with working undos finishes the job
+	r.calls = nil
+	require.NoError(t, saga.Compensate(ctx, reg, left, []saga.Step{r.step("a", nil, nil), r.step("b", nil, nil)}))
+	assert.Equal(t, []string{"undo b", "undo a"}, r.calls)
+	sagas, _ = reg.ListSagas(ctx)
+	assert.Empty(t, sagas)
+}
+
+// failingDelete is a store whose deletes fail
+type failingDelete struct {
+	*registry.MockClient
+}
+
+func (failingDelete) DeleteSaga(context.Context, string) error { return errors.New("throttled") }
+
+func TestRun_FinishedSagaIsNeverCompensated(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	r := &recorder{}
+
+	s := saga.New("create-tenant", "alice")
+	steps := []saga.Step{r.step("a", nil, nil), r.step("b", nil, nil)}
+	require.NoError(t, saga.Run(ctx, failingDelete{reg}, s, steps))
+
+	// The record a failed delete leaves behind is marked done
+	sagas, _ := reg.ListSagas(ctx)
+	require.Len(t, sagas, 1)
+	left := sagas[0]
+	assert.Equal(t, registry.SagaDone, left.State)
+	assert.True(t, saga.Resumable(left, time.Now()), "cleared on the next pass")
+
+	// Resuming it only clears the record
+	r.calls = nil
+	require.NoError(t, saga.Compensate(ctx, reg, left, steps))
+	assert.Empty(t, r.calls, "nothing undone")
+	sagas, _ = reg.ListSagas(ctx)
+	assert.Empty(t, sagas)
+}
+
+func TestResumable(t *testing.T) {
+	now := time.Now()
+	running := &registry.Saga{State: registry.SagaRunning, UpdatedAt: now.Add(-time.Minute)}
+	assert.False(t, saga.Resumable(running, now), "may still be in progress")
+
+	running.UpdatedAt = now.Add(-saga.StaleAfter - time.Second)
+	assert.True(t, saga.Resumable(running, now), "abandoned")
+}
@@ -0,0 +1,166 @@
+// Package secrets keeps tenant credentials, such as Telegram bot tokens, out
+// of the registry table. The registry stores only a reference (the secret's
+// name); the value lives in AWS Secrets Manager, or in memory for local runs
+// and tests.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// ErrNotFound is returned by Get for a secret that doesn't exist
+var ErrNotFound = errors.New("secret not found")
+
+// Store holds secret values by name
+type Store interface {
+	Get(ctx context.Context, name string) (string, error)
+	// Put creates the secret or replaces its value
+	Put(ctx context.Context, name, value string) error
+	// Delete removes the secret; deleting a missing one is not an error
+	Delete(ctx context.Context, name string) error
+}
+
+// AWS implements Store with AWS Secrets Manager
+type AWS struct {
+	sm *secretsmanager.Client
+}
+
+func NewAWS(sm *secretsmanager.Client) *AWS {
+	return &AWS{sm: sm}
+}
+
+// Get returns the secret's current string value
+func (s *AWS) Get(ctx context.Context, name string) (string, error) {
+	out, err := s.sm.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		var nf *types.ResourceNotFoundException
+		if errors.As(err, &nf) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secretsmanager GetSecretValue: %w", err)
+	}
+	return aws.ToString(out.SecretString), nil
+}
+
+// Put creates the secret, or adds a new version if it already exists
+func (s *AWS) Put(ctx context.Context, name, value string) error {
+	_, err := s.sm.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+	})
+	var exists *types.ResourceExistsException
+	if errors.As(err, &exists) {
+		_, err = s.sm.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:     aws.String(name),
+			SecretString: aws.String(value),
+		})
+		if err != nil {
+			return fmt.Errorf("secretsmanager PutSecretValue: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("secretsmanager CreateSecret: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the secret immediately. Skipping the recovery window lets a
+// tenant with the same ID be created again straight away.
+func (s *AWS) Delete(ctx context.Context, name string) error {
+	_, err := s.sm.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(name),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	var nf *types.ResourceNotFoundException
+	if err != nil && !errors.As(err, &nf) {
+		return fmt.Errorf("secretsmanager DeleteSecret: %w", err)
+	}
+	return nil
+}
+
+// Memory is an in-process Store for local mode and tests
+type Memory struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func NewMemory() *Memory {
+	return &Memory{values: make(map[string]string)}
+}
+
+func (m *Memory) Get(_ context.Context, name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *Memory) Put(_ context.Context, name, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[name] = value
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, name)
+	return nil
+}
+
+// BotToken returns a tenant's bot token: read from store when the record
+// holds a reference, otherwise the plaintext token of a record written before
+// tokens moved to the store. A record with a reference but no store is an
+// error rather than an empty token.
+func BotToken(ctx context.Context, store Store, rec *registry.TenantRecord) (string, error) {
+	if rec.BotTokenRef == "" {
+		return rec.BotToken, nil
+	}
+	if store == nil {
+		return "", fmt.Errorf("bot token is in secret %s but no secret store is configured", rec.BotTokenRef)
+	}
+	token, err := store.Get(ctx, rec.BotTokenRef)
+	if err != nil {
+		return "", fmt.Errorf("bot token: %w", err)
+	}
+	return token, nil
+}
+
+// MigrateBotTokens moves the plaintext bot tokens in reg into store, naming
+// each secret with name(tenantID), and returns how many were moved. A tenant
+// whose secret was written but whose record wasn't updated is simply moved
+// again on the next run.
+func MigrateBotTokens(ctx context.Context, reg registry.Client, store Store, name func(tenantID string) string) (int, error) {
+	tenants, err := reg.ListAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list tenants: %w", err)
+	}
+	moved := 0
+	for _, rec := range tenants {
+		if rec.BotToken == "" || rec.BotTokenRef != "" {
+			continue
+		}
+		ref := name(rec.TenantID)
+		if err := store.Put(ctx, ref, rec.BotToken); err != nil {
+			return moved, fmt.Errorf("tenant %s: %w", rec.TenantID, err)
+		}
+		if err := reg.UpdateBotTokenRef(ctx, rec.TenantID, ref); err != nil {
+			return moved, fmt.Errorf("tenant %s: %w", rec.TenantID, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
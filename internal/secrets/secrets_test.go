@@ -0,0 +1,56 @@
+package secrets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/shawn/agentic-tenancy/internal/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotToken(t *testing.T) {
+	ctx := context.Background()
+	store := secrets.NewMemory()
+	require.NoError(t, store.Put(ctx, "bot/alice", "tok-a"))
+
+	token, err := secrets.BotToken(ctx, store, &registry.TenantRecord{BotTokenRef: "bot/alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "tok-a", token)
+
+	// Records from before the store keep working
+	token, err = secrets.BotToken(ctx, nil, &registry.TenantRecord{BotToken: "tok-legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, "tok-legacy", token)
+
+	_, err = secrets.BotToken(ctx, store, &registry.TenantRecord{BotTokenRef: "bot/missing"})
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+	_, err = secrets.BotToken(ctx, nil, &registry.TenantRecord{BotTokenRef: "bot/alice"})
+	assert.Error(t, err, "a reference without a store")
+}
+
+func TestMigrateBotTokens(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	store := secrets.NewMemory()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", BotToken: "tok-a"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "carol", BotTokenRef: "bot/carol"}))
+
+	name := func(tenantID string) string { return "bot/" + tenantID }
+	moved, err := secrets.MigrateBotTokens(ctx, reg, store, name)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	alice, _ := reg.GetTenant(ctx, "alice")
+	assert.Empty(t, alice.BotToken)
+	assert.Equal(t, "bot/alice", alice.BotTokenRef)
+	token, err := store.Get(ctx, "bot/alice")
+	require.NoError(t, err)
+	assert.Equal(t, "tok-a", token)
+
+	moved, err = secrets.MigrateBotTokens(ctx, reg, store, name)
+	require.NoError(t, err)
+	assert.Zero(t, moved, "nothing left to move")
+}
@@ -0,0 +1,251 @@
+// Package snapshot keeps point-in-time copies of tenants' S3 state, so a
+// tenant whose agent corrupted its own memory can be rolled back. A snapshot
+// is a server-side copy of the tenant's prefix to
+// <snapshot prefix>/<snapshot ID>/, outside the prefix the tenant's pod can
+// reach.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/shawn/agentic-tenancy/internal/statesize"
+)
+
+// idLayout formats snapshot IDs: their creation time, so they sort in order
+const idLayout = "20060102T150405.000Z"
+
+// NewID returns the ID of a snapshot taken at t
+func NewID(t time.Time) string {
+	return t.UTC().Format(idLayout)
+}
+
+// ValidID reports whether id is a snapshot ID
+func ValidID(id string) bool {
+	_, err := time.Parse(idLayout, id)
+	return err == nil
+}
+
+// Snapshot is one copy of a tenant's state
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Objects   int       `json:"objects"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// Object is one object of a tenant's state
+type Object struct {
+	Key  string `json:"key"` // relative to the state prefix
+	Size int64  `json:"size"`
+}
+
+// Store copies, lists and deletes state in the tenant state bucket
+type Store interface {
+	// Copy copies every object under src to the same key under dst, and
+	// reports how many objects and bytes it copied
+	Copy(ctx context.Context, src, dst string) (int, int64, error)
+	// List returns the snapshots under prefix, newest first
+	List(ctx context.Context, prefix string) ([]Snapshot, error)
+	// Objects returns the objects under prefix, sorted by key
+	Objects(ctx context.Context, prefix string) ([]Object, error)
+	// Delete removes the objects under prefix and reports how many it
+	// removed
+	Delete(ctx context.Context, prefix string) (int, error)
+}
+
+// collect groups objects under prefix into snapshots by the first path
+// segment after it, skipping keys that aren't under a snapshot ID
+type collect map[string]*Snapshot
+
+func (c collect) add(prefix, key string, size int64) {
+	id, _, ok := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+	if !ok || !ValidID(id) {
+		return
+	}
+	s := c[id]
+	if s == nil {
+		created, _ := time.Parse(idLayout, id)
+		s = &Snapshot{ID: id, CreatedAt: created}
+		c[id] = s
+	}
+	s.Objects++
+	s.Bytes += size
+}
+
+func (c collect) sorted() []Snapshot {
+	out := make([]Snapshot, 0, len(c))
+	for _, s := range c {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out
+}
+
+// S3 implements Store on the tenant state bucket, deleting as statesize.S3
+// does. Objects are copied one at a time with CopyObject, which takes
+// objects of up to 5 GB.
+type S3 struct {
+	*statesize.S3
+	client *s3.Client
+	bucket string
+}
+
+func NewS3(client *s3.Client, bucket string) *S3 {
+	return &S3{S3: statesize.NewS3(client, bucket), client: client, bucket: bucket}
+}
+
+func (s *S3) each(ctx context.Context, prefix string, fn func(s3types.Object) error) error {
+	p := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("s3 ListObjectsV2: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *S3) Copy(ctx context.Context, src, dst string) (int, int64, error) {
+	var objects int
+	var bytes int64
+	err := s.each(ctx, src, func(obj s3types.Object) error {
+		key := aws.ToString(obj.Key)
+		_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(s.bucket),
+			CopySource: aws.String(url.PathEscape(s.bucket + "/" + key)),
+			Key:        aws.String(dst + strings.TrimPrefix(key, src)),
+		})
+		if err != nil {
+			return fmt.Errorf("s3 CopyObject %s: %w", key, err)
+		}
+		objects++
+		bytes += aws.ToInt64(obj.Size)
+		return nil
+	})
+	return objects, bytes, err
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]Snapshot, error) {
+	c := collect{}
+	err := s.each(ctx, prefix, func(obj s3types.Object) error {
+		c.add(prefix, aws.ToString(obj.Key), aws.ToInt64(obj.Size))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.sorted(), nil
+}
+
+func (s *S3) Objects(ctx context.Context, prefix string) ([]Object, error) {
+	var out []Object
+	err := s.each(ctx, prefix, func(obj s3types.Object) error {
+		out = append(out, Object{Key: strings.TrimPrefix(aws.ToString(obj.Key), prefix), Size: aws.ToInt64(obj.Size)})
+		return nil
+	})
+	return out, err
+}
+
+// Memory implements Store with object sizes set by hand, for local runs and
+// tests
+type Memory struct {
+	mu    sync.Mutex
+	sizes map[string]int64 // object key → size
+}
+
+func NewMemory() *Memory {
+	return &Memory{sizes: make(map[string]int64)}
+}
+
+// Put records an object of the given size
+func (m *Memory) Put(key string, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes[key] = size
+}
+
+// Keys returns the keys under prefix, sorted
+func (m *Memory) Keys(prefix string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *Memory) Copy(_ context.Context, src, dst string) (int, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := make(map[string]int64)
+	for key, size := range m.sizes {
+		if strings.HasPrefix(key, src) {
+			copied[dst+strings.TrimPrefix(key, src)] = size
+		}
+	}
+	var bytes int64
+	for key, size := range copied {
+		m.sizes[key] = size
+		bytes += size
+	}
+	return len(copied), bytes, nil
+}
+
+func (m *Memory) List(_ context.Context, prefix string) ([]Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := collect{}
+	for key, size := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			c.add(prefix, key, size)
+		}
+	}
+	return c.sorted(), nil
+}
+
+func (m *Memory) Objects(_ context.Context, prefix string) ([]Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Object
+	for key, size := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			out = append(out, Object{Key: strings.TrimPrefix(key, prefix), Size: size})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (m *Memory) Delete(_ context.Context, prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := 0
+	for key := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.sizes, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
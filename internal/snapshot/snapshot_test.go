@@ -0,0 +1,44 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_CopyAndList(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	m.Put("tenants/alice/memory.db", 100)
+	m.Put("tenants/alice/skills/a.md", 20)
+	m.Put("tenants/alicia/memory.db", 7)
+
+	older := NewID(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	newer := NewID(time.Date(2026, 10, 2, 12, 0, 0, 500e6, time.UTC))
+	assert.Equal(t, "20261002T120000.500Z", newer)
+	for _, id := range []string{older, newer} {
+		objects, bytes, err := m.Copy(ctx, "tenants/alice/", "snapshots/alice/"+id+"/")
+		require.NoError(t, err)
+		assert.Equal(t, 2, objects)
+		assert.EqualValues(t, 120, bytes)
+	}
+	m.Put("snapshots/alice/notes.txt", 1) // not under a snapshot ID
+
+	snaps, err := m.List(ctx, "snapshots/alice/")
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Equal(t, Snapshot{ID: newer, CreatedAt: time.Date(2026, 10, 2, 12, 0, 0, 500e6, time.UTC), Objects: 2, Bytes: 120}, snaps[0])
+	assert.Equal(t, older, snaps[1].ID)
+	assert.Equal(t, []string{"snapshots/alice/" + older + "/memory.db", "snapshots/alice/" + older + "/skills/a.md"},
+		m.Keys("snapshots/alice/"+older+"/"))
+
+	objects, err := m.Objects(ctx, "tenants/alice/")
+	require.NoError(t, err)
+	assert.Equal(t, []Object{{Key: "memory.db", Size: 100}, {Key: "skills/a.md", Size: 20}}, objects)
+
+	assert.True(t, ValidID(older))
+	assert.False(t, ValidID("latest"))
+}
@@ -0,0 +1,108 @@
+package statesize
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WarnPercent is the share of its quota at which a tenant's owner is warned
+const WarnPercent = 80
+
+// Level is where a tenant's state size stands against its quota
+type Level string
+
+const (
+	LevelOK       Level = "ok"
+	LevelWarning  Level = "warning"  // at or above WarnPercent of the quota
+	LevelExceeded Level = "exceeded" // at or above the quota
+)
+
+// Action is what happens to a tenant over its quota when it wakes
+type Action string
+
+const (
+	ActionRefuse   Action = "refuse"   // the wake fails; the pod isn't started
+	ActionReadOnly Action = "readonly" // the pod starts with /s3-state mounted read-only
+)
+
+// QuotaPolicy holds the per-tier storage quotas
+type QuotaPolicy struct {
+	// Bytes is the quota of tenants whose tier has none in TierBytes. Zero
+	// means unlimited.
+	Bytes int64
+	// TierBytes overrides Bytes for tenants on the given tier; zero makes
+	// the tier unlimited.
+	TierBytes map[string]int64
+	// Action is applied to tenants over quota (default ActionRefuse)
+	Action Action
+}
+
+// Limit returns the quota in bytes for a tier, or 0 if it is unlimited
+func (p QuotaPolicy) Limit(tier string) int64 {
+	if b, ok := p.TierBytes[tier]; ok {
+		return b
+	}
+	return p.Bytes
+}
+
+// Check returns where size stands against the tier's quota, and the quota
+func (p QuotaPolicy) Check(size int64, tier string) (Level, int64) {
+	limit := p.Limit(tier)
+	switch {
+	case limit <= 0:
+		return LevelOK, 0
+	case size >= limit:
+		return LevelExceeded, limit
+	case size*100 >= limit*WarnPercent:
+		return LevelWarning, limit
+	default:
+		return LevelOK, limit
+	}
+}
+
+// ParseAction parses STATE_QUOTA_ACTION; empty means ActionRefuse
+func ParseAction(s string) (Action, error) {
+	switch Action(s) {
+	case "", ActionRefuse:
+		return ActionRefuse, nil
+	case ActionReadOnly:
+		return ActionReadOnly, nil
+	}
+	return "", fmt.Errorf("invalid quota action %q: want refuse or readonly", s)
+}
+
+// ParseBytes parses a size such as "5Gi" or "500M"; empty means 0
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil || q.Sign() < 0 {
+		return 0, fmt.Errorf("invalid size %q: want e.g. 5Gi or 500M", s)
+	}
+	return q.Value(), nil
+}
+
+// ParseTierQuota parses "tier=size,tier=size" (e.g. "free=1Gi,premium=20Gi").
+func ParseTierQuota(s string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tier, size, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid tier quota %q: want tier=size", part)
+		}
+		n, err := ParseBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tier quota %q: %w", part, err)
+		}
+		out[strings.TrimSpace(tier)] = n
+	}
+	return out, nil
+}
@@ -0,0 +1,135 @@
+// Package statesize measures how much S3 state each tenant keeps and holds it
+// to a per-tier quota. Sizes are measured periodically by the lifecycle
+// leader and cached on the tenant record, since listing a large prefix is too
+// slow to do on every wake. It also deletes a purged tenant's state.
+package statesize
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Sizer reports the total size in bytes of the objects under a key prefix
+type Sizer interface {
+	Size(ctx context.Context, prefix string) (int64, error)
+}
+
+// Deleter removes the objects under a key prefix and reports how many it
+// removed
+type Deleter interface {
+	Delete(ctx context.Context, prefix string) (int, error)
+}
+
+// S3 implements Sizer and Deleter on the tenant state bucket
+type S3 struct {
+	client *s3.Client
+	bucket string
+}
+
+func NewS3(client *s3.Client, bucket string) *S3 {
+	return &S3{client: client, bucket: bucket}
+}
+
+// Size sums the sizes of all objects under prefix
+func (s *S3) Size(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	p := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("s3 ListObjectsV2: %w", err)
+		}
+		for _, obj := range page.Contents {
+			total += aws.ToInt64(obj.Size)
+		}
+	}
+	return total, nil
+}
+
+// Delete removes all objects under prefix, a listed page at a time
+func (s *S3) Delete(ctx context.Context, prefix string) (int, error) {
+	deleted := 0
+	p := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("s3 ListObjectsV2: %w", err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]s3types.ObjectIdentifier, len(page.Contents))
+		for i, obj := range page.Contents {
+			objects[i] = s3types.ObjectIdentifier{Key: obj.Key}
+		}
+		out, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &s3types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("s3 DeleteObjects: %w", err)
+		}
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			return deleted, fmt.Errorf("s3 DeleteObjects: %d object(s) not deleted, e.g. %s: %s",
+				len(out.Errors), aws.ToString(e.Key), aws.ToString(e.Message))
+		}
+		deleted += len(objects)
+	}
+	return deleted, nil
+}
+
+// Memory implements Sizer and Deleter with sizes set by hand, for local runs
+// and tests
+type Memory struct {
+	mu    sync.Mutex
+	sizes map[string]int64 // object key → size
+}
+
+func NewMemory() *Memory {
+	return &Memory{sizes: make(map[string]int64)}
+}
+
+// Put records an object of the given size
+func (m *Memory) Put(key string, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes[key] = size
+}
+
+func (m *Memory) Size(_ context.Context, prefix string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total int64
+	for key, size := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			total += size
+		}
+	}
+	return total, nil
+}
+
+func (m *Memory) Delete(_ context.Context, prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := 0
+	for key := range m.sizes {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.sizes, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,108 @@
+package statesize
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1 << 30
+
+type fakeNotifier struct{ sent []notify.Notification }
+
+func (f *fakeNotifier) Notify(_ context.Context, _ *registry.TenantRecord, n notify.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestQuotaPolicy_Check(t *testing.T) {
+	p := QuotaPolicy{Bytes: 10 * gib, TierBytes: map[string]int64{"premium": 0}}
+
+	level, limit := p.Check(7*gib, "standard")
+	assert.Equal(t, LevelOK, level)
+	assert.Equal(t, int64(10*gib), limit)
+	level, _ = p.Check(8*gib, "standard")
+	assert.Equal(t, LevelWarning, level)
+	level, _ = p.Check(10*gib, "standard")
+	assert.Equal(t, LevelExceeded, level)
+
+	level, limit = p.Check(100*gib, "premium")
+	assert.Equal(t, LevelOK, level, "a zero tier quota is unlimited")
+	assert.Zero(t, limit)
+}
+
+func TestParseTierQuota(t *testing.T) {
+	m, err := ParseTierQuota("free=1Gi, premium=500M,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"free": gib, "premium": 500_000_000}, m)
+
+	for _, bad := range []string{"free", "free=lots", "free=-1Gi", "=1Gi"} {
+		_, err := ParseTierQuota(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	a, err := ParseAction("")
+	require.NoError(t, err)
+	assert.Equal(t, ActionRefuse, a)
+	a, err = ParseAction("readonly")
+	require.NoError(t, err)
+	assert.Equal(t, ActionReadOnly, a)
+	_, err = ParseAction("delete")
+	assert.Error(t, err)
+}
+
+func TestWatcher_RecordsSizesAndNotifiesOnCrossing(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMock()
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "alice", Tier: "free", S3Prefix: "tenants/alice/"}))
+	require.NoError(t, reg.CreateTenant(ctx, &registry.TenantRecord{TenantID: "bob", Tier: "free", S3Prefix: "tenants/bob/"}))
+	sizer := NewMemory()
+	sizer.Put("tenants/alice/brain.db", 850)
+	sizer.Put("tenants/bob/brain.db", 100)
+	n := &fakeNotifier{}
+	w := NewWatcher(reg, sizer, QuotaPolicy{TierBytes: map[string]int64{"free": 1000}}, n, 10, time.Hour)
+	now := time.Now()
+
+	w.Check(ctx, now)
+	alice, _ := reg.GetTenant(ctx, "alice")
+	assert.Equal(t, int64(850), alice.StateBytes)
+	assert.Equal(t, string(LevelWarning), alice.StateQuotaLevel)
+	assert.WithinDuration(t, now, alice.StateMeasuredAt, time.Second)
+	bob, _ := reg.GetTenant(ctx, "bob")
+	assert.Equal(t, int64(100), bob.StateBytes)
+	require.Len(t, n.sent, 1)
+	assert.Equal(t, registry.EventStateQuotaWarning, n.sent[0].Kind)
+	assert.Contains(t, n.sent[0].Title, "85%")
+
+	// Still at warning: no repeat
+	w.Check(ctx, now.Add(time.Hour))
+	assert.Len(t, n.sent, 1)
+
+	sizer.Put("tenants/alice/history.db", 200)
+	w.Check(ctx, now.Add(2*time.Hour))
+	require.Len(t, n.sent, 2)
+	assert.Equal(t, registry.EventStateQuotaExceeded, n.sent[1].Kind)
+	events, _ := reg.ListEvents(ctx, "alice")
+	require.Len(t, events, 2)
+	assert.Equal(t, registry.EventStateQuotaExceeded, events[0].Kind)
+	assert.Equal(t, "1.0 KiB of 1000 B", events[0].Message)
+
+	report := w.policy.Report(mustGet(t, reg, "alice"))
+	assert.Equal(t, LevelExceeded, report.Level)
+	assert.Equal(t, 105, report.UsedPercent)
+}
+
+func mustGet(t *testing.T, reg registry.Client, id string) *registry.TenantRecord {
+	t.Helper()
+	rec, err := reg.GetTenant(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	return rec
+}
@@ -0,0 +1,135 @@
+package statesize
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/bytesize"
+	"github.com/shawn/agentic-tenancy/internal/i18n"
+	"github.com/shawn/agentic-tenancy/internal/notify"
+	"github.com/shawn/agentic-tenancy/internal/registry"
+)
+
+// Report is a tenant's state size against its quota, as served by
+// GET /tenants/{id}/state
+type Report struct {
+	TenantID    string    `json:"tenant_id"`
+	Bytes       int64     `json:"bytes"`
+	QuotaBytes  int64     `json:"quota_bytes,omitempty"` // 0 = unlimited
+	UsedPercent int       `json:"used_percent,omitempty"`
+	Level       Level     `json:"level"`
+	MeasuredAt  time.Time `json:"measured_at,omitempty"` // zero if never measured
+}
+
+// Report describes the tenant's last measured state size under p
+func (p QuotaPolicy) Report(rec *registry.TenantRecord) Report {
+	level, limit := p.Check(rec.StateBytes, rec.Tier)
+	r := Report{
+		TenantID:   rec.TenantID,
+		Bytes:      rec.StateBytes,
+		QuotaBytes: limit,
+		Level:      level,
+		MeasuredAt: rec.StateMeasuredAt,
+	}
+	if limit > 0 {
+		r.UsedPercent = int(rec.StateBytes * 100 / limit)
+	}
+	return r
+}
+
+// Watcher measures every tenant's state on an interval, records the sizes in
+// the registry and tells owners when their tenant crosses into the warning
+// or exceeded level. It runs on the lifecycle leader only.
+type Watcher struct {
+	reg      registry.Client
+	sizer    Sizer
+	policy   QuotaPolicy
+	notifier notify.Notifier // nil records events without notifying owners
+	keep     int             // event log entries kept per tenant
+	interval time.Duration
+}
+
+// NewWatcher creates a watcher measuring every interval
+func NewWatcher(reg registry.Client, sizer Sizer, policy QuotaPolicy, notifier notify.Notifier, keep int, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Watcher{reg: reg, sizer: sizer, policy: policy, notifier: notifier, keep: keep, interval: interval}
+}
+
+// Run measures once, then every interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	slog.Info("state size watcher: starting", "interval", w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.Check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check measures every tenant once
+func (w *Watcher) Check(ctx context.Context, now time.Time) {
+	tenants, err := w.reg.ListAll(ctx)
+	if err != nil {
+		slog.Error("state size watcher: list tenants failed", "err", err)
+		return
+	}
+	for _, t := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if t.S3Prefix == "" || t.Status == registry.StatusTerminated {
+			continue
+		}
+		w.measure(ctx, t, now)
+	}
+}
+
+func (w *Watcher) measure(ctx context.Context, t *registry.TenantRecord, now time.Time) {
+	size, err := w.sizer.Size(ctx, t.S3Prefix)
+	if err != nil {
+		slog.Warn("state size watcher: measure failed", "tenant", t.TenantID, "err", err)
+		return
+	}
+	level, limit := w.policy.Check(size, t.Tier)
+	if err := w.reg.UpdateStateSize(ctx, t.TenantID, size, string(level), now.UTC()); err != nil {
+		slog.Warn("state size watcher: record size failed", "tenant", t.TenantID, "err", err)
+		return
+	}
+	if !worse(level, Level(t.StateQuotaLevel)) {
+		return
+	}
+
+	used, quota := bytesize.Format(size), bytesize.Format(limit)
+	kind, title := registry.EventStateQuotaWarning, i18n.T(t.Locale, i18n.StateQuotaWarning, t.TenantID, used, quota, int(size*100/limit))
+	if level == LevelExceeded {
+		kind, title = registry.EventStateQuotaExceeded, i18n.T(t.Locale, i18n.StateQuotaExceeded, t.TenantID, used, quota)
+		if w.policy.Action == ActionReadOnly {
+			title = i18n.T(t.Locale, i18n.StateQuotaReadOnly, t.TenantID, used, quota)
+		}
+	}
+	slog.Warn("state size watcher: "+kind, "tenant", t.TenantID, "bytes", size, "quota_bytes", limit)
+	ev := registry.TenantEvent{Time: now.UTC(), Kind: kind, Pod: t.PodName, Message: used + " of " + quota}
+	if err := w.reg.RecordEvent(ctx, t.TenantID, ev, w.keep); err != nil {
+		slog.Error("state size watcher: record event failed", "tenant", t.TenantID, "kind", kind, "err", err)
+	}
+	if w.notifier == nil {
+		return
+	}
+	n := notify.Notification{TenantID: t.TenantID, Kind: kind, Title: title, Time: ev.Time, Locale: t.Locale}
+	if err := w.notifier.Notify(ctx, t, n); err != nil {
+		slog.Warn("state size watcher: notify failed", "tenant", t.TenantID, "kind", kind, "err", err)
+	}
+}
+
+// worse reports whether level is more severe than prev
+func worse(level, prev Level) bool {
+	rank := map[Level]int{LevelOK: 0, "": 0, LevelWarning: 1, LevelExceeded: 2}
+	return rank[level] > rank[prev]
+}
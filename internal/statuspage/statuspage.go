@@ -0,0 +1,134 @@
+// Package statuspage issues and checks the signed links that let anyone
+// holding one see a tenant's public status page on the router, without
+// platform credentials.
+//
+// A token names the tenant, its environment and an expiry, and carries an
+// HMAC-SHA256 over them keyed with STATUS_PAGE_SECRET, which the
+// orchestrator (issuing) and the router (serving) share. Tokens aren't
+// stored anywhere: they stop working when they expire or the secret is
+// rotated.
+//
+// Web chat links (SignChat, VerifyChat) are tokens of the same form keyed
+// with WEB_CHAT_SECRET and signed for a different purpose, so a status link
+// never opens a tenant's chat page even if both secrets are the same.
+package statuspage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("invalid status link")
+	ErrExpired = errors.New("status link expired")
+)
+
+// purposes a token is signed for; status links predate the others and sign
+// none, so links already issued keep working
+const (
+	purposeStatus = ""
+	purposeChat   = "chat"
+)
+
+// Window is how far back a status page reports wakes and incidents
+const Window = 7 * 24 * time.Hour
+
+// Sign returns a token for the tenant's status page in env ("" for the
+// default environment), valid until expires.
+func Sign(secret []byte, env, tenantID string, expires time.Time) string {
+	return sign(secret, purposeStatus, env, tenantID, expires)
+}
+
+// Verify checks a token for env and returns the tenant it was issued for.
+func Verify(secret []byte, env, token string, now time.Time) (string, error) {
+	return verify(secret, purposeStatus, env, token, now)
+}
+
+// SignChat returns a token for the tenant's web chat page in env, valid
+// until expires.
+func SignChat(secret []byte, env, tenantID string, expires time.Time) string {
+	return sign(secret, purposeChat, env, tenantID, expires)
+}
+
+// VerifyChat checks a web chat token for env and returns the tenant it was
+// issued for.
+func VerifyChat(secret []byte, env, token string, now time.Time) (string, error) {
+	return verify(secret, purposeChat, env, token, now)
+}
+
+func sign(secret []byte, purpose, env, tenantID string, expires time.Time) string {
+	id := base64.RawURLEncoding.EncodeToString([]byte(tenantID))
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return id + "." + exp + "." + base64.RawURLEncoding.EncodeToString(mac(secret, purpose, env, tenantID, exp))
+}
+
+func verify(secret []byte, purpose, env, token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalid
+	}
+	id, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalid
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac(secret, purpose, env, string(id), parts[1])) {
+		return "", ErrInvalid
+	}
+	if !now.Before(time.Unix(exp, 0)) {
+		return "", ErrExpired
+	}
+	return string(id), nil
+}
+
+func mac(secret []byte, purpose, env, tenantID, exp string) []byte {
+	m := hmac.New(sha256.New, secret)
+	if purpose != purposeStatus {
+		m.Write([]byte(purpose + "\n"))
+	}
+	m.Write([]byte(env + "\n" + tenantID + "\n" + exp))
+	return m.Sum(nil)
+}
+
+// State is what a status page says about the agent
+type State string
+
+const (
+	// StateOnline means the agent's pod is running
+	StateOnline State = "online"
+	// StateSleeping means the pod was stopped while idle; the next message
+	// wakes it
+	StateSleeping State = "sleeping"
+	StatePaused   State = "paused"
+	StateStarting State = "starting"
+	// StateDown means the agent kept failing to start and an operator has
+	// to step in
+	StateDown State = "down"
+)
+
+// Status is the public view of a tenant: nothing that identifies the pod,
+// the owner or their configuration
+type Status struct {
+	TenantID     string    `json:"tenant_id"`
+	BotUsername  string    `json:"bot_username,omitempty"`
+	State        State     `json:"state"`
+	LastActiveAt time.Time `json:"last_active_at,omitempty"`
+	// Wakes and FailedWakes count the wake attempts within Window, and
+	// UptimePercent is the share of them that brought the agent up (100
+	// without any)
+	Wakes         int     `json:"wakes"`
+	FailedWakes   int     `json:"failed_wakes"`
+	UptimePercent float64 `json:"uptime_percent"`
+	// Incidents counts the agent's OOM kills and crash loops within Window
+	Incidents int       `json:"incidents"`
+	CheckedAt time.Time `json:"checked_at"`
+}
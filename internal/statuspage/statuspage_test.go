@@ -0,0 +1,55 @@
+package statuspage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Now()
+	token := Sign(secret, "", "alice", now.Add(time.Hour))
+
+	tenant, err := Verify(secret, "", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", tenant)
+
+	_, err = Verify(secret, "", token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrExpired)
+	_, err = Verify([]byte("other"), "", token, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = Verify(secret, "staging", token, now)
+	assert.ErrorIs(t, err, ErrInvalid, "a link only works in its own environment")
+
+	// Pointing the token at another tenant breaks the signature
+	forged := "Ym9i" + token[strings.Index(token, "."):] // base64 "bob"
+	_, err = Verify(secret, "", forged, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	for _, bad := range []string{"", "a.b", "!!.1.x", "YWxpY2U.x.x"} {
+		_, err = Verify(secret, "", bad, now)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+}
+
+func TestSignVerifyChat(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Now()
+	token := SignChat(secret, "", "alice", now.Add(time.Hour))
+
+	tenant, err := VerifyChat(secret, "", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", tenant)
+	_, err = VerifyChat(secret, "", token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrExpired)
+
+	// Status and chat links don't open each other's pages
+	_, err = Verify(secret, "", token, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = VerifyChat(secret, "", Sign(secret, "", "alice", now.Add(time.Hour)), now)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
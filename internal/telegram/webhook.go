@@ -2,10 +2,14 @@ package telegram
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,30 +18,133 @@ import (
 type Client struct {
 	httpClient    *http.Client
 	routerBaseURL string // e.g. https://zeroclaw-router.example.com
+	apiBase       string // Bot API server
 }
 
+// DefaultAPIBase is Telegram's hosted Bot API server
+const DefaultAPIBase = "https://api.telegram.org"
+
 // New creates a new Telegram client.
 // routerBaseURL is the public URL of the Router (no trailing slash).
 func New(routerBaseURL string) *Client {
 	return &Client{
 		httpClient:    &http.Client{Timeout: 10 * time.Second},
 		routerBaseURL: strings.TrimRight(routerBaseURL, "/"),
+		apiBase:       DefaultAPIBase,
 	}
 }
 
+// WithAPIBase points the client at another Bot API server, e.g. a
+// self-hosted one or a test server.
+func (c *Client) WithAPIBase(base string) *Client {
+	c.apiBase = strings.TrimRight(base, "/")
+	return c
+}
+
+// DefaultAllowedUpdates is what a tenant's bot is subscribed to unless the
+// tenant enables more: plain messages are all an agent needs to chat.
+var DefaultAllowedUpdates = []string{"message"}
+
+// supportedUpdates are the update types the router can turn into an agent
+// message. Subscribing to anything else would only queue updates it drops.
+var supportedUpdates = map[string]bool{
+	"message":        true,
+	"edited_message": true,
+	"channel_post":   true,
+	"callback_query": true,
+	// Telegram Business: messages in chats a business account connected the
+	// bot to; replies go out through the same connection
+	"business_message":        true,
+	"edited_business_message": true,
+}
+
+// AllowedUpdates returns the update types to subscribe a bot to; empty means
+// DefaultAllowedUpdates.
+func AllowedUpdates(types []string) []string {
+	if len(types) == 0 {
+		return DefaultAllowedUpdates
+	}
+	return types
+}
+
+// ValidateAllowedUpdates rejects update types the router cannot deliver.
+func ValidateAllowedUpdates(types []string) error {
+	for _, t := range types {
+		if !supportedUpdates[t] {
+			return fmt.Errorf("unsupported update type %q (supported: business_message, callback_query, channel_post, edited_business_message, edited_message, message)", t)
+		}
+	}
+	return nil
+}
+
+// SecretTokenHeader carries the secret_token a webhook was registered with
+// on every update Telegram delivers to it.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// NewWebhookSecret returns a random secret_token (Telegram allows 1-256
+// characters from A-Z, a-z, 0-9, _ and -).
+func NewWebhookSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 type apiResponse struct {
 	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
 	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// APIError is a request the Bot API answered with ok=false
+type APIError struct {
+	Code        int // HTTP-like error_code, e.g. 401, 429
+	Description string
+	// RetryAfter is how long Telegram asks flood-limited callers to wait
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string { return "telegram error: " + e.Description }
+
+func (r *apiResponse) err() error {
+	return &APIError{
+		Code:        r.ErrorCode,
+		Description: r.Description,
+		RetryAfter:  time.Duration(r.Parameters.RetryAfter) * time.Second,
+	}
+}
+
+// Retryable reports whether a failed call may succeed later: Telegram was
+// unreachable, flood-limited the caller (429) or failed itself (5xx). Errors
+// about the request, such as a revoked bot token, are not retryable.
+func Retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500
+	}
+	return err != nil
+}
+
+// WebhookURL is where the tenant's bot is pointed: {routerBaseURL}/tg/{tenantID}
+func (c *Client) WebhookURL(tenantID string) string {
+	return fmt.Sprintf("%s/tg/%s", c.routerBaseURL, tenantID)
 }
 
-// RegisterWebhook calls setWebhook for the given bot token and tenant ID.
-// The webhook URL will be: {routerBaseURL}/tg/{tenantID}
-func (c *Client) RegisterWebhook(ctx context.Context, botToken, tenantID string) error {
-	webhookURL := fmt.Sprintf("%s/tg/%s", c.routerBaseURL, tenantID)
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/setWebhook", botToken)
+// RegisterWebhook calls setWebhook for the given bot token and tenant ID,
+// subscribing the bot to allowedUpdates (see AllowedUpdates). Telegram sends
+// secretToken back in SecretTokenHeader.
+// The webhook URL will be WebhookURL(tenantID).
+func (c *Client) RegisterWebhook(ctx context.Context, botToken, tenantID string, allowedUpdates []string, secretToken string) error {
+	webhookURL := c.WebhookURL(tenantID)
+	apiURL := fmt.Sprintf("%s/bot%s/setWebhook", c.apiBase, botToken)
+	allowed, _ := json.Marshal(AllowedUpdates(allowedUpdates))
 
 	form := url.Values{}
 	form.Set("url", webhookURL)
+	form.Set("allowed_updates", string(allowed))
+	form.Set("secret_token", secretToken)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL,
 		strings.NewReader(form.Encode()))
@@ -57,7 +164,7 @@ func (c *Client) RegisterWebhook(ctx context.Context, botToken, tenantID string)
 		return fmt.Errorf("decode response: %w", err)
 	}
 	if !result.OK {
-		return fmt.Errorf("telegram error: %s", result.Description)
+		return result.err()
 	}
 	return nil
 }
@@ -67,7 +174,7 @@ func (c *Client) DeleteWebhook(ctx context.Context, botToken string) error {
 	if botToken == "" {
 		return nil // nothing to delete
 	}
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/deleteWebhook", botToken)
+	apiURL := fmt.Sprintf("%s/bot%s/deleteWebhook", c.apiBase, botToken)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL,
 		strings.NewReader("drop_pending_updates=false"))
 	if err != nil {
@@ -81,6 +188,107 @@ func (c *Client) DeleteWebhook(ctx context.Context, botToken string) error {
 	}
 	defer resp.Body.Close()
 
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if !result.OK {
+		return result.err()
+	}
+	return nil
+}
+
+// WebhookInfo is Telegram's view of a bot's webhook (getWebhookInfo). An
+// empty URL means none is set.
+type WebhookInfo struct {
+	URL                string   `json:"url"`
+	PendingUpdateCount int      `json:"pending_update_count"`
+	LastErrorDate      int64    `json:"last_error_date"` // Unix time; 0 if none
+	LastErrorMessage   string   `json:"last_error_message"`
+	MaxConnections     int      `json:"max_connections"`
+	AllowedUpdates     []string `json:"allowed_updates"`
+	IPAddress          string   `json:"ip_address"`
+}
+
+// GetWebhookInfo returns the bot's webhook as Telegram sees it: where it
+// points, how many updates wait for delivery and the last delivery error
+func (c *Client) GetWebhookInfo(ctx context.Context, botToken string) (*WebhookInfo, error) {
+	apiURL := fmt.Sprintf("%s/bot%s/getWebhookInfo", c.apiBase, botToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("getWebhookInfo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		apiResponse
+		Result WebhookInfo `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if !result.OK {
+		return nil, result.err()
+	}
+	return &result.Result, nil
+}
+
+// GetMe returns the bot's username (without the leading @).
+func (c *Client) GetMe(ctx context.Context, botToken string) (string, error) {
+	apiURL := fmt.Sprintf("%s/bot%s/getMe", c.apiBase, botToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("getMe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		apiResponse
+		Result struct {
+			Username string `json:"username"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("telegram error: %s", result.Description)
+	}
+	return result.Result.Username, nil
+}
+
+// SendMessage sends a plain-text message from the bot to chatID.
+func (c *Client) SendMessage(ctx context.Context, botToken string, chatID int64, text string) error {
+	apiURL := fmt.Sprintf("%s/bot%s/sendMessage", c.apiBase, botToken)
+
+	form := url.Values{}
+	form.Set("chat_id", strconv.FormatInt(chatID, 10))
+	form.Set("text", text)
+	form.Set("disable_web_page_preview", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendMessage: %w", err)
+	}
+	defer resp.Body.Close()
+
 	var result apiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return fmt.Errorf("decode response: %w", err)
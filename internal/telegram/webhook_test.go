@@ -0,0 +1,57 @@
+package telegram_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterWebhook_FloodLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bottoken/setWebhook", r.URL.Path)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+	}))
+	defer srv.Close()
+
+	c := telegram.New("https://router.example.com").WithAPIBase(srv.URL)
+	err := c.RegisterWebhook(context.Background(), "token", "alice", nil, "secret")
+
+	var apiErr *telegram.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 429, apiErr.Code)
+	assert.Equal(t, 7*time.Second, apiErr.RetryAfter)
+	assert.True(t, telegram.Retryable(err))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.False(t, telegram.Retryable(nil))
+	assert.True(t, telegram.Retryable(errors.New("connection refused")))
+	assert.True(t, telegram.Retryable(&telegram.APIError{Code: 502}))
+	assert.False(t, telegram.Retryable(&telegram.APIError{Code: 401, Description: "Unauthorized"}))
+	assert.False(t, telegram.Retryable(&telegram.APIError{Code: 400, Description: "Bad Request: bad webhook"}))
+}
+
+func TestGetWebhookInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bottoken/getWebhookInfo", r.URL.Path)
+		w.Write([]byte(`{"ok":true,"result":{"url":"https://router.example.com/tg/alice","pending_update_count":3,"last_error_date":1700000000,"last_error_message":"Connection timed out","allowed_updates":["message"]}}`))
+	}))
+	defer srv.Close()
+
+	c := telegram.New("https://router.example.com").WithAPIBase(srv.URL)
+	info, err := c.GetWebhookInfo(context.Background(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, c.WebhookURL("alice"), info.URL)
+	assert.Equal(t, 3, info.PendingUpdateCount)
+	assert.Equal(t, int64(1700000000), info.LastErrorDate)
+	assert.Equal(t, "Connection timed out", info.LastErrorMessage)
+	assert.Equal(t, []string{"message"}, info.AllowedUpdates)
+}
@@ -0,0 +1,272 @@
+// Package tenantrole gives each tenant pod its own AWS identity: an IAM role
+// that only the tenant's Kubernetes ServiceAccount may assume (IRSA), allowed
+// to reach no S3 state but the tenant's own prefix. Without it every tenant
+// pod runs as the shared zeroclaw-tenant ServiceAccount, with one set of
+// permissions for all tenants.
+package tenantrole
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// maxRoleName is IAM's limit on role name length
+const maxRoleName = 64
+
+// policyName is the role's inline policy granting its S3 access
+const policyName = "tenant-state"
+
+// Role describes a tenant's role: who may assume it and what it may reach
+type Role struct {
+	// Key identifies the tenant across environments, e.g. "alice" or
+	// "staging.alice"; the role is named after it.
+	Key      string
+	TenantID string
+	// Namespace and ServiceAccount name the only identity that may assume
+	// the role
+	Namespace      string
+	ServiceAccount string
+	// S3Prefix is the key prefix of the state bucket the role may use
+	S3Prefix string
+}
+
+// Provisioner creates and deletes tenants' roles
+type Provisioner interface {
+	// Ensure creates the role, or brings an existing one's trust and S3
+	// policies up to date, and returns its ARN
+	Ensure(ctx context.Context, role Role) (string, error)
+	// Delete removes the role of the tenant with the given key; deleting a
+	// missing one is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// RoleName is the IAM role name for a tenant key: prefix + key, or a
+// truncated key and a hash of it when that would be too long
+func RoleName(prefix, key string) string {
+	name := prefix + key
+	if len(name) <= maxRoleName {
+		return name
+	}
+	sum := sha256.Sum256([]byte(key))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	return name[:maxRoleName-len(suffix)] + suffix
+}
+
+// IAMConfig configures the IAM provisioner
+type IAMConfig struct {
+	// OIDCProviderARN is the cluster's IAM OIDC identity provider, e.g.
+	// arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE
+	OIDCProviderARN string
+	// Bucket is the tenant state bucket
+	Bucket string
+	// RolePrefix starts every tenant role's name, e.g. "zeroclaw-tenant-"
+	RolePrefix string
+	// PolicyARNs are managed policies attached to every tenant role, for
+	// what all tenants need (e.g. Bedrock InvokeModel)
+	PolicyARNs []string
+	// PermissionsBoundary, if set, is attached to every tenant role
+	PermissionsBoundary string
+}
+
+// IAM implements Provisioner with AWS IAM
+type IAM struct {
+	client *iam.Client
+	cfg    IAMConfig
+}
+
+func NewIAM(client *iam.Client, cfg IAMConfig) *IAM {
+	return &IAM{client: client, cfg: cfg}
+}
+
+// Ensure creates the role if needed, then sets its trust policy and inline
+// S3 policy, so roles created before a config change are brought up to date.
+// An empty S3Prefix is refused: the policy would grant the whole bucket.
+func (p *IAM) Ensure(ctx context.Context, role Role) (string, error) {
+	if role.S3Prefix == "" {
+		return "", fmt.Errorf("role %s: empty S3 prefix", role.Key)
+	}
+	name := RoleName(p.cfg.RolePrefix, role.Key)
+	trust, err := p.trustPolicy(role)
+	if err != nil {
+		return "", err
+	}
+	var arn string
+	in := &iam.CreateRoleInput{
+		RoleName:                 aws.String(name),
+		AssumeRolePolicyDocument: aws.String(trust),
+		Description:              aws.String("ZeroClaw tenant " + role.TenantID),
+		Tags: []types.Tag{
+			{Key: aws.String("zeroclaw/tenant"), Value: aws.String(role.TenantID)},
+		},
+	}
+	if p.cfg.PermissionsBoundary != "" {
+		in.PermissionsBoundary = aws.String(p.cfg.PermissionsBoundary)
+	}
+	out, err := p.client.CreateRole(ctx, in)
+	var exists *types.EntityAlreadyExistsException
+	switch {
+	case errors.As(err, &exists):
+		if _, err := p.client.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(name),
+			PolicyDocument: aws.String(trust),
+		}); err != nil {
+			return "", fmt.Errorf("iam UpdateAssumeRolePolicy: %w", err)
+		}
+		got, err := p.client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+		if err != nil {
+			return "", fmt.Errorf("iam GetRole: %w", err)
+		}
+		arn = aws.ToString(got.Role.Arn)
+	case err != nil:
+		return "", fmt.Errorf("iam CreateRole: %w", err)
+	default:
+		arn = aws.ToString(out.Role.Arn)
+	}
+
+	if _, err := p.client.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+		RoleName:       aws.String(name),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(s3Policy(p.cfg.Bucket, role.S3Prefix)),
+	}); err != nil {
+		return "", fmt.Errorf("iam PutRolePolicy: %w", err)
+	}
+	for _, policyARN := range p.cfg.PolicyARNs {
+		if _, err := p.client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			return "", fmt.Errorf("iam AttachRolePolicy %s: %w", policyARN, err)
+		}
+	}
+	return arn, nil
+}
+
+// Delete detaches the role's managed policies and removes its inline one,
+// which IAM requires first, then deletes the role
+func (p *IAM) Delete(ctx context.Context, key string) error {
+	name := RoleName(p.cfg.RolePrefix, key)
+	var nf *types.NoSuchEntityException
+	// List rather than use PolicyARNs, which may have changed since the
+	// role was created
+	attached := iam.NewListAttachedRolePoliciesPaginator(p.client, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(name)})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if errors.As(err, &nf) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("iam ListAttachedRolePolicies: %w", err)
+		}
+		for _, pol := range page.AttachedPolicies {
+			if _, err := p.client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				RoleName:  aws.String(name),
+				PolicyArn: pol.PolicyArn,
+			}); err != nil && !errors.As(err, &nf) {
+				return fmt.Errorf("iam DetachRolePolicy: %w", err)
+			}
+		}
+	}
+	_, err := p.client.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(name),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil && !errors.As(err, &nf) {
+		return fmt.Errorf("iam DeleteRolePolicy: %w", err)
+	}
+	_, err = p.client.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(name)})
+	if err != nil && !errors.As(err, &nf) {
+		return fmt.Errorf("iam DeleteRole: %w", err)
+	}
+	return nil
+}
+
+// trustPolicy lets only the tenant's ServiceAccount assume the role through
+// the cluster's OIDC provider
+func (p *IAM) trustPolicy(role Role) (string, error) {
+	_, issuer, ok := strings.Cut(p.cfg.OIDCProviderARN, ":oidc-provider/")
+	if !ok {
+		return "", fmt.Errorf("invalid OIDC provider ARN %q", p.cfg.OIDCProviderARN)
+	}
+	return policy(map[string]any{
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Federated": p.cfg.OIDCProviderARN},
+		"Action":    "sts:AssumeRoleWithWebIdentity",
+		"Condition": map[string]any{
+			"StringEquals": map[string]string{
+				issuer + ":sub": "system:serviceaccount:" + role.Namespace + ":" + role.ServiceAccount,
+				issuer + ":aud": "sts.amazonaws.com",
+			},
+		},
+	}), nil
+}
+
+// s3Policy allows the objects under prefix, and listing only that prefix
+func s3Policy(bucket, prefix string) string {
+	return policy(
+		map[string]any{
+			"Effect":   "Allow",
+			"Action":   []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject", "s3:AbortMultipartUpload"},
+			"Resource": "arn:aws:s3:::" + bucket + "/" + prefix + "*",
+		},
+		map[string]any{
+			"Effect":   "Allow",
+			"Action":   "s3:ListBucket",
+			"Resource": "arn:aws:s3:::" + bucket,
+			"Condition": map[string]any{
+				"StringLike": map[string]string{"s3:prefix": prefix + "*"},
+			},
+		},
+	)
+}
+
+func policy(statements ...map[string]any) string {
+	doc, _ := json.Marshal(map[string]any{"Version": "2012-10-17", "Statement": statements})
+	return string(doc)
+}
+
+// Memory implements Provisioner without AWS, for local runs and tests
+type Memory struct {
+	mu    sync.Mutex
+	roles map[string]Role // key → role
+}
+
+func NewMemory() *Memory {
+	return &Memory{roles: make(map[string]Role)}
+}
+
+func (m *Memory) Ensure(_ context.Context, role Role) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roles[role.Key] = role
+	return MemoryARN(role.Key), nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.roles, key)
+	return nil
+}
+
+// Get returns the role ensured for key, if any
+func (m *Memory) Get(key string) (Role, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	role, ok := m.roles[key]
+	return role, ok
+}
+
+// MemoryARN is the ARN Memory reports for the role of key
+func MemoryARN(key string) string {
+	return "arn:aws:iam::000000000000:role/" + RoleName("zeroclaw-tenant-", key)
+}
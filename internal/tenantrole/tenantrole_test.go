@@ -0,0 +1,55 @@
+package tenantrole
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleName(t *testing.T) {
+	assert.Equal(t, "zeroclaw-tenant-staging.alice", RoleName("zeroclaw-tenant-", "staging.alice"))
+
+	long := strings.Repeat("a", 80)
+	name := RoleName("zeroclaw-tenant-", long)
+	assert.Len(t, name, maxRoleName)
+	assert.NotEqual(t, name, RoleName("zeroclaw-tenant-", long+"b"), "truncated names stay distinct")
+}
+
+func TestTrustPolicy(t *testing.T) {
+	p := &IAM{cfg: IAMConfig{OIDCProviderARN: "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EX"}}
+	doc, err := p.trustPolicy(Role{Namespace: "tenants", ServiceAccount: "zeroclaw-alice"})
+	require.NoError(t, err)
+
+	var got struct {
+		Statement []struct {
+			Principal map[string]string
+			Condition map[string]map[string]string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(doc), &got))
+	require.Len(t, got.Statement, 1)
+	assert.Equal(t, p.cfg.OIDCProviderARN, got.Statement[0].Principal["Federated"])
+	assert.Equal(t, "system:serviceaccount:tenants:zeroclaw-alice",
+		got.Statement[0].Condition["StringEquals"]["oidc.eks.us-west-2.amazonaws.com/id/EX:sub"])
+
+	p.cfg.OIDCProviderARN = "oidc.eks.us-west-2.amazonaws.com/id/EX"
+	_, err = p.trustPolicy(Role{})
+	assert.Error(t, err)
+}
+
+func TestS3Policy(t *testing.T) {
+	doc := s3Policy("state", "staging/tenants/alice/")
+	assert.Contains(t, doc, `"arn:aws:s3:::state/staging/tenants/alice/*"`)
+	assert.Contains(t, doc, `"s3:prefix":"staging/tenants/alice/*"`)
+	assert.NotContains(t, doc, `"arn:aws:s3:::state/*"`)
+}
+
+func TestEnsure_RejectsEmptyPrefix(t *testing.T) {
+	p := NewIAM(nil, IAMConfig{Bucket: "state"})
+	_, err := p.Ensure(context.Background(), Role{Key: "alice", TenantID: "alice"})
+	assert.ErrorContains(t, err, "empty S3 prefix")
+}
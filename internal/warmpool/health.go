@@ -0,0 +1,171 @@
+package warmpool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shawn/agentic-tenancy/internal/contract"
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Probe checks that a warm pod's agent answers. An error means the pod, and
+// most likely its node's Kata runtime, can't serve a tenant.
+type Probe func(ctx context.Context, pod *corev1.Pod) error
+
+// AgentProbe checks the agent port the way the tenant startupProbe does:
+// path is fetched with HTTP GET (any 2xx/3xx passes), or with an empty path
+// the port only has to accept a connection.
+func AgentProbe(path string, timeout time.Duration) Probe {
+	return agentProbe(path, contract.AgentPort, timeout)
+}
+
+func agentProbe(path string, port int, timeout time.Duration) Probe {
+	client := &http.Client{
+		Timeout: timeout,
+		// A redirect passes, as for the kubelet; don't follow it off the pod
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return func(ctx context.Context, pod *corev1.Pod) error {
+		addr := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port))
+		if path == "" {
+			d := net.Dialer{Timeout: timeout}
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+path, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("GET %s: status %d", path, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// HealthChecker probes warm pods and takes nodes whose warm pods stop
+// answering out of the pool. A warm pod can be Running while its node's Kata
+// runtime is degraded, and a tenant that claims it is pinned to that node.
+//
+// After a pod fails `failures` probes in a row, its node is cordoned and the
+// node's warm pods are recycled, so the Deployment starts replacements on
+// healthy nodes (Karpenter brings up a new one if needed). Tenant pods
+// already on the node are left alone. Run it on the leader only.
+type HealthChecker struct {
+	k8s       *k8sclient.Client
+	namespace string
+	probe     Probe
+	failures  int
+	// grace is how long a warm pod may take to start answering: the Kata VM
+	// boot plus agent init, as for the startupProbe
+	grace    time.Duration
+	interval time.Duration
+	// dryRun logs unhealthy nodes instead of cordoning them
+	dryRun bool
+
+	failed map[string]int // consecutive failed probes per warm pod
+}
+
+func NewHealthChecker(k8s *k8sclient.Client, namespace string, probe Probe, failures int, grace time.Duration) *HealthChecker {
+	return &HealthChecker{
+		k8s:       k8s,
+		namespace: namespace,
+		probe:     probe,
+		failures:  failures,
+		grace:     grace,
+		interval:  30 * time.Second,
+		failed:    make(map[string]int),
+	}
+}
+
+// WithDryRun makes the checker log the nodes it would cordon without
+// touching them.
+func (h *HealthChecker) WithDryRun(dryRun bool) *HealthChecker {
+	h.dryRun = dryRun
+	return h
+}
+
+// Run probes the warm pool every interval until ctx is cancelled.
+func (h *HealthChecker) Run(ctx context.Context) {
+	slog.Info("warm pool health: starting", "failures", h.failures, "namespace", h.namespace, "dry_run", h.dryRun)
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.check(ctx, time.Now())
+		}
+	}
+}
+
+func (h *HealthChecker) check(ctx context.Context, now time.Time) {
+	pods, err := h.k8s.ReadyWarmPods(ctx, h.namespace)
+	if err != nil {
+		slog.Error("warm pool health: list warm pods failed", "err", err)
+		return
+	}
+
+	seen := make(map[string]bool, len(pods))
+	unhealthy := make(map[string]string) // node → the pod that failed
+	for _, pod := range pods {
+		seen[pod.Name] = true
+		if pod.Status.StartTime != nil && now.Sub(pod.Status.StartTime.Time) < h.grace {
+			continue
+		}
+		if err := h.probe(ctx, pod); err != nil {
+			h.failed[pod.Name]++
+			slog.Warn("warm pool health: probe failed", "pod", pod.Name, "node", pod.Spec.NodeName,
+				"failures", h.failed[pod.Name], "err", err)
+			if h.failed[pod.Name] >= h.failures && pod.Spec.NodeName != "" {
+				unhealthy[pod.Spec.NodeName] = pod.Name
+			}
+			continue
+		}
+		delete(h.failed, pod.Name)
+	}
+	// Forget pods that were claimed, recycled or replaced
+	for name := range h.failed {
+		if !seen[name] {
+			delete(h.failed, name)
+		}
+	}
+
+	for node, failedPod := range unhealthy {
+		if h.dryRun {
+			slog.Info("warm pool health: would cordon unhealthy node (dry run)", "node", node, "pod", failedPod)
+			continue
+		}
+		if err := h.k8s.CordonNode(ctx, node); err != nil {
+			slog.Error("warm pool health: cordon node failed", "node", node, "err", err)
+			continue
+		}
+		slog.Warn("warm pool health: cordoned unhealthy node", "node", node, "pod", failedPod)
+		for _, pod := range pods {
+			if pod.Spec.NodeName != node {
+				continue
+			}
+			if err := h.k8s.RecycleWarmPod(ctx, pod); err != nil {
+				slog.Warn("warm pool health: recycle warm pod failed", "pod", pod.Name, "node", node, "err", err)
+				continue
+			}
+			delete(h.failed, pod.Name)
+			slog.Info("warm pool health: recycled warm pod", "pod", pod.Name, "node", node)
+		}
+	}
+}
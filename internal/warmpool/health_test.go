@@ -0,0 +1,114 @@
+package warmpool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	k8sclient "github.com/shawn/agentic-tenancy/internal/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func warmPod(name, node string, started time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenants",
+			Labels: map[string]string{"app": "warm-pool", "warm": "true"}},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1",
+			StartTime: &metav1.Time{Time: started}},
+	}
+}
+
+// TestHealthChecker_CordonsUnhealthyNode: a node is cordoned and its warm
+// pods recycled only after `failures` failed probes in a row
+func TestHealthChecker_CordonsUnhealthyNode(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cs := fake.NewSimpleClientset()
+	for _, node := range []string{"node-bad", "node-good"} {
+		_, err := cs.CoreV1().Nodes().Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: node}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	for _, pod := range []*corev1.Pod{
+		warmPod("warm-a", "node-bad", now.Add(-time.Hour)),
+		warmPod("warm-b", "node-bad", now.Add(-time.Minute)), // still booting
+		warmPod("warm-c", "node-good", now.Add(-time.Hour)),
+	} {
+		_, err := cs.CoreV1().Pods("tenants").Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	var probed []string
+	probe := func(ctx context.Context, pod *corev1.Pod) error {
+		probed = append(probed, pod.Name)
+		if pod.Spec.NodeName == "node-bad" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	h := NewHealthChecker(k8sclient.New(cs, k8sclient.Config{}), "tenants", probe, 2, 5*time.Minute)
+
+	h.check(ctx, now)
+	assert.ElementsMatch(t, []string{"warm-a", "warm-c"}, probed)
+	node, _ := cs.CoreV1().Nodes().Get(ctx, "node-bad", metav1.GetOptions{})
+	assert.False(t, node.Spec.Unschedulable, "one failure is not enough")
+
+	h.check(ctx, now)
+	node, _ = cs.CoreV1().Nodes().Get(ctx, "node-bad", metav1.GetOptions{})
+	assert.True(t, node.Spec.Unschedulable)
+	node, _ = cs.CoreV1().Nodes().Get(ctx, "node-good", metav1.GetOptions{})
+	assert.False(t, node.Spec.Unschedulable)
+
+	pods, err := cs.CoreV1().Pods("tenants").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "warm-c", pods.Items[0].Name)
+}
+
+// TestHealthChecker_DryRun: nothing is cordoned or deleted
+func TestHealthChecker_DryRun(t *testing.T) {
+	ctx := context.Background()
+	cs := fake.NewSimpleClientset()
+	_, err := cs.CoreV1().Nodes().Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-bad"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = cs.CoreV1().Pods("tenants").Create(ctx, warmPod("warm-a", "node-bad", time.Now().Add(-time.Hour)), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	probe := func(context.Context, *corev1.Pod) error { return errors.New("timeout") }
+	h := NewHealthChecker(k8sclient.New(cs, k8sclient.Config{}), "tenants", probe, 1, 0).WithDryRun(true)
+	h.check(ctx, time.Now())
+
+	node, _ := cs.CoreV1().Nodes().Get(ctx, "node-bad", metav1.GetOptions{})
+	assert.False(t, node.Spec.Unschedulable)
+	_, err = cs.CoreV1().Pods("tenants").Get(ctx, "warm-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestAgentProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	host, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	pod := &corev1.Pod{Status: corev1.PodStatus{PodIP: host}}
+	ctx := context.Background()
+
+	assert.NoError(t, agentProbe("", port, time.Second)(ctx, pod))
+	assert.NoError(t, agentProbe("/health", port, time.Second)(ctx, pod))
+	assert.Error(t, agentProbe("/ready", port, time.Second)(ctx, pod))
+
+	srv.Close()
+	assert.Error(t, agentProbe("", port, time.Second)(ctx, pod))
+}
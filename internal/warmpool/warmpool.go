@@ -49,6 +49,11 @@ func (m *Manager) Run(ctx context.Context) {
 }
 
 func (m *Manager) reconcile(ctx context.Context) {
+	if m.k8s.WarmStagingEnabled() {
+		if err := m.k8s.EnsureWarmStagingVolume(ctx, m.namespace); err != nil {
+			slog.Error("warm pool: ensure staging volume failed", "err", err)
+		}
+	}
 	if err := m.k8s.EnsureWarmPoolDeployment(ctx, m.namespace, m.target); err != nil {
 		slog.Error("warm pool: ensure deployment failed", "err", err)
 	}
@@ -42,3 +42,26 @@ func TestWarmPool_IdempotentReconcile(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int32(1), *deploy.Spec.Replicas)
 }
+
+// TestWarmPool_StagingVolume verifies warm pods mount the staging volume when enabled
+func TestWarmPool_StagingVolume(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	k8s := k8sclient.New(cs, k8sclient.Config{
+		KataRuntimeClass:  "kata-qemu",
+		ZeroClawImage:     "zeroclaw:test",
+		S3Bucket:          "test-bucket",
+		WarmStagingVolume: true,
+	})
+
+	wp := New(k8s, "tenants", 1)
+	wp.reconcile(context.Background())
+
+	_, err := cs.CoreV1().PersistentVolumeClaims("tenants").Get(context.Background(), k8sclient.WarmStagingPVC, metav1.GetOptions{})
+	assert.NoError(t, err)
+	deploy, err := cs.AppsV1().Deployments("tenants").Get(context.Background(), "warm-pool", metav1.GetOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, deploy.Spec.Template.Spec.Volumes, 1) {
+		assert.Equal(t, k8sclient.WarmStagingPVC, deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	}
+	assert.True(t, deploy.Spec.Template.Spec.Containers[0].VolumeMounts[0].ReadOnly)
+}
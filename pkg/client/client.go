@@ -0,0 +1,674 @@
+// Package client is a typed Go client for the orchestrator's HTTP API, whose
+// routes are described in docs/openapi.json. The router and ztm share its
+// types instead of each decoding responses into structs of their own.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls one orchestrator environment
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// New returns a client for the orchestrator at baseURL, e.g.
+// http://orchestrator:8080, or http://orchestrator:8080/env/staging for a
+// named environment. A nil httpClient uses http.DefaultClient; API keys are
+// added by its transport.
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient}
+}
+
+// Error is a response outside 2xx. Message is the body, usually the
+// orchestrator's plain-text reason.
+type Error struct {
+	StatusCode int
+	Header     http.Header
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("orchestrator returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("orchestrator returned %d: %s", e.StatusCode, e.Message)
+}
+
+// StatusCode returns the HTTP status of an *Error in err's chain, or 0 if
+// the orchestrator wasn't reached
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// do sends a request and decodes a 2xx response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.send(ctx, method, path, nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// send sends a request, returning a 2xx response for the caller to close
+// and anything else as an *Error. A body other than an io.Reader is sent as
+// JSON.
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body any) (*http.Response, error) {
+	var r io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		r, contentType = b, "text/plain; charset=utf-8"
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		r, contentType = bytes.NewReader(data), "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &Error{StatusCode: resp.StatusCode, Header: resp.Header, Message: strings.TrimSpace(string(msg))}
+	}
+	return resp, nil
+}
+
+func tenantPath(tenantID, sub string) string {
+	return "/tenants/" + url.PathEscape(tenantID) + sub
+}
+
+// CreateTenant calls POST /tenants
+func (c *Client) CreateTenant(ctx context.Context, req *CreateTenantRequest) (*Tenant, error) {
+	var t Tenant
+	if err := c.do(ctx, http.MethodPost, "/tenants", req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTenants calls GET /tenants
+func (c *Client) ListTenants(ctx context.Context, opts ListOptions) ([]Tenant, error) {
+	query := url.Values{}
+	if opts.Sort != "" {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Order != "" {
+		query.Set("order", opts.Order)
+	}
+	path := "/tenants"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var tenants []Tenant
+	if err := c.do(ctx, http.MethodGet, path, nil, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// WatchTenants calls GET /tenants/watch, or for one tenant with a
+// non-empty tenantID, and calls fn with each change until ctx is done, the
+// orchestrator ends the stream, or fn returns an error, which is returned.
+// The current tenants come first, as added.
+func (c *Client) WatchTenants(ctx context.Context, tenantID string, fn func(TenantChange) error) error {
+	path := "/tenants/watch"
+	if tenantID != "" {
+		path += "?" + url.Values{"tenant_id": {tenantID}}.Encode()
+	}
+	resp, err := c.send(ctx, http.MethodGet, path, http.Header{"Accept": {"text/event-stream"}}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue // blank separators and keepalive comments
+		}
+		var change TenantChange
+		if err := json.Unmarshal([]byte(data), &change); err != nil {
+			return fmt.Errorf("decode tenant change: %w", err)
+		}
+		if err := fn(change); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// GetTenant calls GET /tenants/{id}
+func (c *Client) GetTenant(ctx context.Context, tenantID string) (*Tenant, error) {
+	var t Tenant
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, ""), nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateTenant calls PATCH /tenants/{id}
+func (c *Client) UpdateTenant(ctx context.Context, tenantID string, req *UpdateTenantRequest) (*Tenant, error) {
+	var t Tenant
+	if err := c.do(ctx, http.MethodPatch, tenantPath(tenantID, ""), req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetUsage calls GET /tenants/{id}/usage for the days from and to
+// (YYYY-MM-DD, inclusive); empty means the orchestrator's default
+func (c *Client) GetUsage(ctx context.Context, tenantID, from, to string) (*UsageReport, error) {
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	path := tenantPath(tenantID, "/usage")
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var u UsageReport
+	if err := c.do(ctx, http.MethodGet, path, nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// CreateTenants calls POST /tenants/bulk; the response reports each
+// tenant's outcome
+func (c *Client) CreateTenants(ctx context.Context, reqs []CreateTenantRequest) (*BulkCreateResponse, error) {
+	var res BulkCreateResponse
+	if err := c.do(ctx, http.MethodPost, "/tenants/bulk", reqs, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteTenant calls DELETE /tenants/{id}, confirming it with X-Confirm as
+// the orchestrator may require (REQUIRE_CONFIRM)
+func (c *Client) DeleteTenant(ctx context.Context, tenantID string) error {
+	resp, err := c.send(ctx, http.MethodDelete, tenantPath(tenantID, ""), http.Header{"X-Confirm": {tenantID}}, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PlanDeleteTenant calls DELETE /tenants/{id}?dry_run=true
+func (c *Client) PlanDeleteTenant(ctx context.Context, tenantID string) (*DeletePlan, error) {
+	var plan DeletePlan
+	if err := c.do(ctx, http.MethodDelete, tenantPath(tenantID, "?dry_run=true"), nil, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// PurgeTenant calls DELETE /tenants/{id}?purge=true, deleting the tenant
+// and its data at once, or purging an already deleted tenant early
+func (c *Client) PurgeTenant(ctx context.Context, tenantID string) error {
+	resp, err := c.send(ctx, http.MethodDelete, tenantPath(tenantID, "?purge=true"), http.Header{"X-Confirm": {tenantID}}, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PlanPurgeTenant calls DELETE /tenants/{id}?purge=true&dry_run=true
+func (c *Client) PlanPurgeTenant(ctx context.Context, tenantID string) (*DeletePlan, error) {
+	var plan DeletePlan
+	if err := c.do(ctx, http.MethodDelete, tenantPath(tenantID, "?purge=true&dry_run=true"), nil, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// RestoreTenant calls POST /tenants/{id}/restore. A tenant that isn't
+// deleted is a 409 Error.
+func (c *Client) RestoreTenant(ctx context.Context, tenantID string) (*Tenant, error) {
+	var t Tenant
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/restore"), nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// RestartTenant calls POST /tenants/{id}/restart
+func (c *Client) RestartTenant(ctx context.Context, tenantID string) (*RestartResult, error) {
+	var res RestartResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/restart"), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SleepTenant calls POST /tenants/{id}/sleep. A tenant that isn't running
+// is a 409 Error.
+func (c *Client) SleepTenant(ctx context.Context, tenantID string) (*SleepResult, error) {
+	var res SleepResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/sleep"), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SuspendTenant calls POST /tenants/{id}/suspend
+func (c *Client) SuspendTenant(ctx context.Context, tenantID string) (*SuspendResult, error) {
+	var res SuspendResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/suspend"), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ResumeTenant calls POST /tenants/{id}/resume
+func (c *Client) ResumeTenant(ctx context.Context, tenantID string) (*SuspendResult, error) {
+	var res SuspendResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/resume"), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ResetTenant calls POST /tenants/{id}/reset
+func (c *Client) ResetTenant(ctx context.Context, tenantID string) (*SuspendResult, error) {
+	var res SuspendResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/reset"), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SendTestMessage calls POST /tenants/{id}/test-message: it wakes the
+// tenant and returns its agent's reply to text
+func (c *Client) SendTestMessage(ctx context.Context, tenantID, text string) (*TestMessageResult, error) {
+	var res TestMessageResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/test-message"), TestMessageRequest{Text: text}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListWakes calls GET /tenants/{id}/wakes
+func (c *Client) ListWakes(ctx context.Context, tenantID string) ([]WakeAttempt, error) {
+	var wakes []WakeAttempt
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/wakes"), nil, &wakes); err != nil {
+		return nil, err
+	}
+	return wakes, nil
+}
+
+// ListEvents calls GET /tenants/{id}/events
+func (c *Client) ListEvents(ctx context.Context, tenantID string) ([]TenantEvent, error) {
+	var events []TenantEvent
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/events"), nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetState calls GET /tenants/{id}/state
+func (c *Client) GetState(ctx context.Context, tenantID string) (*TenantState, error) {
+	var st TenantState
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/state"), nil, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// CreateSnapshot calls POST /tenants/{id}/snapshots. With quiesce a running
+// pod is stopped first.
+func (c *Client) CreateSnapshot(ctx context.Context, tenantID string, quiesce bool) (*SnapshotResult, error) {
+	var res SnapshotResult
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/snapshots"), SnapshotRequest{Quiesce: quiesce}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListSnapshots calls GET /tenants/{id}/snapshots
+func (c *Client) ListSnapshots(ctx context.Context, tenantID string) ([]Snapshot, error) {
+	var snaps []Snapshot
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/snapshots"), nil, &snaps); err != nil {
+		return nil, err
+	}
+	return snaps, nil
+}
+
+// RestoreSnapshot calls POST /tenants/{id}/snapshots/{snapshotID}/restore,
+// confirmed with X-Confirm
+func (c *Client) RestoreSnapshot(ctx context.Context, tenantID, snapshotID string) (*SnapshotRestoreResult, error) {
+	path := tenantPath(tenantID, "/snapshots/"+url.PathEscape(snapshotID)+"/restore")
+	resp, err := c.send(ctx, http.MethodPost, path, http.Header{"X-Confirm": {tenantID}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var res SnapshotRestoreResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("decode POST %s: %w", path, err)
+	}
+	return &res, nil
+}
+
+// DeleteSnapshot calls DELETE /tenants/{id}/snapshots/{snapshotID}
+func (c *Client) DeleteSnapshot(ctx context.Context, tenantID, snapshotID string) error {
+	return c.do(ctx, http.MethodDelete, tenantPath(tenantID, "/snapshots/"+url.PathEscape(snapshotID)), nil, nil)
+}
+
+// ExportTenant calls GET /tenants/{id}/export
+func (c *Client) ExportTenant(ctx context.Context, tenantID string) (*TenantBundle, error) {
+	var b TenantBundle
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/export"), nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ImportTenant calls POST /tenants/import
+func (c *Client) ImportTenant(ctx context.Context, req ImportTenantRequest) (*ImportResult, error) {
+	var res ImportResult
+	if err := c.do(ctx, http.MethodPost, "/tenants/import", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StreamLogs calls GET /tenants/{id}/logs, returning the agent's log lines
+// as plain text for the caller to close. A tail of 0 uses the orchestrator's
+// default; with follow the stream stays open until ctx is done or the pod
+// stops.
+func (c *Client) StreamLogs(ctx context.Context, tenantID string, tail int, follow bool) (io.ReadCloser, error) {
+	query := url.Values{}
+	if tail > 0 {
+		query.Set("tail", strconv.Itoa(tail))
+	}
+	if follow {
+		query.Set("follow", "true")
+	}
+	path := tenantPath(tenantID, "/logs")
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	resp, err := c.send(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// LookupChat calls GET /lookup/chat/{chatID}
+func (c *Client) LookupChat(ctx context.Context, chatID string) ([]ChatTenant, error) {
+	var entries []ChatTenant
+	if err := c.do(ctx, http.MethodGet, "/lookup/chat/"+url.PathEscape(chatID), nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func linkPath(tenantID, sub, start string) string {
+	path := tenantPath(tenantID, sub)
+	if start != "" {
+		path += "?start=" + url.QueryEscape(start)
+	}
+	return path
+}
+
+// GetLink calls GET /tenants/{id}/link; start is the deep link's payload
+func (c *Client) GetLink(ctx context.Context, tenantID, start string) (*DeepLink, error) {
+	var link DeepLink
+	if err := c.do(ctx, http.MethodGet, linkPath(tenantID, "/link", start), nil, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetLinkQR calls GET /tenants/{id}/link/qr, returning the PNG
+func (c *Client) GetLinkQR(ctx context.Context, tenantID, start string) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodGet, linkPath(tenantID, "/link/qr", start), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// CreateStatusLink calls POST /tenants/{id}/status-link. A ttl of 0 uses
+// the orchestrator's default.
+func (c *Client) CreateStatusLink(ctx context.Context, tenantID string, ttl time.Duration) (*StatusLink, error) {
+	var link StatusLink
+	req := StatusLinkRequest{TTLS: int64(ttl.Seconds())}
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/status-link"), req, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CreateChatLink calls POST /tenants/{id}/chat-link. A ttl of 0 uses the
+// orchestrator's default.
+func (c *Client) CreateChatLink(ctx context.Context, tenantID string, ttl time.Duration) (*ChatLink, error) {
+	var link ChatLink
+	req := ChatLinkRequest{TTLS: int64(ttl.Seconds())}
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/chat-link"), req, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListImages calls GET /images
+func (c *Client) ListImages(ctx context.Context) ([]ImageAlias, error) {
+	var aliases []ImageAlias
+	if err := c.do(ctx, http.MethodGet, "/images", nil, &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// SetImage calls POST /images
+func (c *Client) SetImage(ctx context.Context, alias, image string) (*ImageAlias, error) {
+	var out ImageAlias
+	if err := c.do(ctx, http.MethodPost, "/images", ImageAlias{Alias: alias, Image: image}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteImage calls DELETE /images/{alias}
+func (c *Client) DeleteImage(ctx context.Context, alias string) error {
+	return c.do(ctx, http.MethodDelete, "/images/"+url.PathEscape(alias), nil, nil)
+}
+
+// StartRollout calls POST /admin/rollout. A maxUnavailable of 0 uses the
+// orchestrator's default.
+func (c *Client) StartRollout(ctx context.Context, maxUnavailable int) (*Rollout, error) {
+	var ro Rollout
+	if err := c.do(ctx, http.MethodPost, "/admin/rollout", StartRolloutRequest{MaxUnavailable: maxUnavailable}, &ro); err != nil {
+		return nil, err
+	}
+	return &ro, nil
+}
+
+// GetRollout calls GET /admin/rollout
+func (c *Client) GetRollout(ctx context.Context) (*Rollout, error) {
+	var ro Rollout
+	if err := c.do(ctx, http.MethodGet, "/admin/rollout", nil, &ro); err != nil {
+		return nil, err
+	}
+	return &ro, nil
+}
+
+// StartBroadcast calls POST /admin/broadcast
+func (c *Client) StartBroadcast(ctx context.Context, text string) (*Broadcast, error) {
+	var bc Broadcast
+	if err := c.do(ctx, http.MethodPost, "/admin/broadcast", StartBroadcastRequest{Text: text}, &bc); err != nil {
+		return nil, err
+	}
+	return &bc, nil
+}
+
+// GetBroadcast calls GET /admin/broadcast
+func (c *Client) GetBroadcast(ctx context.Context) (*Broadcast, error) {
+	var bc Broadcast
+	if err := c.do(ctx, http.MethodGet, "/admin/broadcast", nil, &bc); err != nil {
+		return nil, err
+	}
+	return &bc, nil
+}
+
+// SetKillSwitch calls POST /admin/killswitch
+func (c *Client) SetKillSwitch(ctx context.Context, req *SetKillSwitchRequest) (*KillSwitch, error) {
+	var ks KillSwitch
+	if err := c.do(ctx, http.MethodPost, "/admin/killswitch", req, &ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
+
+// GetKillSwitch calls GET /admin/killswitch
+func (c *Client) GetKillSwitch(ctx context.Context) (*KillSwitch, error) {
+	var ks KillSwitch
+	if err := c.do(ctx, http.MethodGet, "/admin/killswitch", nil, &ks); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
+
+// GetWebhookInfo calls GET /tenants/{id}/webhook
+func (c *Client) GetWebhookInfo(ctx context.Context, tenantID string) (*WebhookInfo, error) {
+	var info WebhookInfo
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/webhook"), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DeleteWebhook calls DELETE /tenants/{id}/webhook
+func (c *Client) DeleteWebhook(ctx context.Context, tenantID string) error {
+	return c.do(ctx, http.MethodDelete, tenantPath(tenantID, "/webhook"), nil, nil)
+}
+
+// GetBotToken calls GET /tenants/{id}/bot_token (internal)
+func (c *Client) GetBotToken(ctx context.Context, tenantID string) (*BotToken, error) {
+	var t BotToken
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/bot_token"), nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetSlack calls GET /tenants/{id}/slack (internal). A tenant without Slack
+// has an empty config.
+func (c *Client) GetSlack(ctx context.Context, tenantID string) (*SlackConfig, error) {
+	var cfg SlackConfig
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/slack"), nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// PutWebhookSecret calls PUT /tenants/{id}/webhook_secret (internal)
+func (c *Client) PutWebhookSecret(ctx context.Context, tenantID, secret string) error {
+	return c.do(ctx, http.MethodPut, tenantPath(tenantID, "/webhook_secret"),
+		WebhookSecretRequest{WebhookSecret: secret}, nil)
+}
+
+// UpdateActivity calls PUT /tenants/{id}/activity (internal)
+func (c *Client) UpdateActivity(ctx context.Context, tenantID string, a Activity) error {
+	return c.do(ctx, http.MethodPut, tenantPath(tenantID, "/activity"), a, nil)
+}
+
+// SaveOutput calls POST /tenants/{id}/outputs (internal)
+func (c *Client) SaveOutput(ctx context.Context, tenantID, text string) (*Output, error) {
+	var out Output
+	if err := c.do(ctx, http.MethodPost, tenantPath(tenantID, "/outputs"), strings.NewReader(text), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPublicStatus calls GET /tenants/{id}/public-status (internal)
+func (c *Client) GetPublicStatus(ctx context.Context, tenantID string) (*PublicStatus, error) {
+	var st PublicStatus
+	if err := c.do(ctx, http.MethodGet, tenantPath(tenantID, "/public-status"), nil, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// Wake calls POST /wake/{id} (internal), returning once the pod is ready
+func (c *Client) Wake(ctx context.Context, tenantID string) (*WakeJob, error) {
+	return c.wake(ctx, "/wake/"+url.PathEscape(tenantID))
+}
+
+// WakeAsync calls POST /wake/{id}?async=true (internal): the job returned is
+// ready if the pod was running, else polled with GetWakeJob. Orchestrators
+// without async wakes answer like Wake, with no job ID.
+func (c *Client) WakeAsync(ctx context.Context, tenantID string) (*WakeJob, error) {
+	return c.wake(ctx, "/wake/"+url.PathEscape(tenantID)+"?async=true")
+}
+
+func (c *Client) wake(ctx context.Context, path string) (*WakeJob, error) {
+	var job WakeJob
+	if err := c.do(ctx, http.MethodPost, path, nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetWakeJob calls GET /wake-jobs/{jobID} (internal)
+func (c *Client) GetWakeJob(ctx context.Context, jobID string) (*WakeJob, error) {
+	var job WakeJob
+	if err := c.do(ctx, http.MethodGet, "/wake-jobs/"+url.PathEscape(jobID), nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shawn/agentic-tenancy/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetTenant(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/env/staging/tenants/alice", r.URL.Path)
+		w.Write([]byte(`{"TenantID":"alice","Status":"running","AllowedUpdates":["message"],"MaxReplyBytes":4096}`))
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL+"/env/staging/", nil)
+	rec, err := c.GetTenant(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", rec.TenantID)
+	assert.Equal(t, []string{"message"}, rec.AllowedUpdates)
+	assert.Equal(t, 4096, rec.MaxReplyBytes)
+}
+
+func TestClient_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Kill-Switch", "global")
+		http.Error(w, "tenant disabled", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := client.New(srv.URL, nil).WakeAsync(context.Background(), "alice")
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "global", apiErr.Header.Get("X-Kill-Switch"))
+	assert.EqualError(t, err, "orchestrator returned 503: tenant disabled")
+	assert.Equal(t, http.StatusServiceUnavailable, client.StatusCode(err))
+	assert.Zero(t, client.StatusCode(io.EOF))
+}
+
+func TestClient_Bodies(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+		switch r.URL.Path {
+		case "/tenants/alice/outputs":
+			json.NewEncoder(w).Encode(map[string]string{"url": "https://example.com/o/1"})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	c := client.New(srv.URL, nil)
+	ctx := context.Background()
+
+	require.NoError(t, c.PutWebhookSecret(ctx, "alice", "s3cret"))
+	require.NoError(t, c.UpdateActivity(ctx, "alice", client.Activity{CharsIn: 5, CharsOut: 1}))
+	out, err := c.SaveOutput(ctx, "alice", "a long reply")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/o/1", out.URL)
+	tenants, err := c.ListTenants(ctx, client.ListOptions{Sort: "status", Order: "desc"})
+	require.NoError(t, err)
+	assert.Empty(t, tenants)
+
+	assert.Equal(t, []string{
+		`PUT /tenants/alice/webhook_secret application/json {"webhook_secret":"s3cret"}`,
+		`PUT /tenants/alice/activity application/json {"chars_in":5,"chars_out":1}`,
+		`POST /tenants/alice/outputs text/plain; charset=utf-8 a long reply`,
+		`GET /tenants?order=desc&sort=status  `,
+	}, got)
+}
@@ -0,0 +1,551 @@
+package client
+
+import (
+	"time"
+)
+
+// Tenant is a tenant's registry record as GET /tenants/{id} returns it.
+// The record is encoded with its Go field names, unlike the snake_case
+// request bodies. Credentials (BotToken, WebhookSecret, Slack's) are
+// redacted.
+type Tenant struct {
+	TenantID       string            `json:"TenantID"`
+	Status         string            `json:"Status"`
+	PodName        string            `json:"PodName,omitempty"`
+	PodIP          string            `json:"PodIP,omitempty"`
+	Namespace      string            `json:"Namespace,omitempty"`
+	BotToken       string            `json:"BotToken,omitempty"` // Redacted in most responses
+	BotUsername    string            `json:"BotUsername,omitempty"`
+	IdleTimeoutS   int               `json:"IdleTimeoutS"`
+	Tier           string            `json:"Tier,omitempty"`
+	Image          string            `json:"Image,omitempty"`
+	DNS            *DNSConfig        `json:"DNS,omitempty"`
+	KeepWarm       bool              `json:"KeepWarm,omitempty"`
+	LogForward     *LogForwardConfig `json:"LogForward,omitempty"`
+	HomeRegion     string            `json:"HomeRegion,omitempty"`
+	AllowedUpdates []string          `json:"AllowedUpdates,omitempty"`
+	Slack          *SlackConfig      `json:"Slack,omitempty"` // credentials redacted; non-nil means connected
+	WebhookStatus  string            `json:"WebhookStatus,omitempty"`
+	WebhookError   string            `json:"WebhookError,omitempty"`
+	Locale         string            `json:"Locale,omitempty"`
+	Timezone       string            `json:"Timezone,omitempty"`
+	Resize         *ResizeOp         `json:"Resize,omitempty"`
+	Disabled       bool              `json:"Disabled,omitempty"`
+	MaxMessageAgeS int               `json:"MaxMessageAgeS,omitempty"`
+	MaxReplyBytes  int               `json:"MaxReplyBytes,omitempty"`
+	WakeFailures   int               `json:"WakeFailures,omitempty"` // failed wakes since the pod last started
+	LastError      string            `json:"LastError,omitempty"`
+	// NoAnnouncements leaves the owner out of broadcasts
+	NoAnnouncements bool `json:"NoAnnouncements,omitempty"`
+	// WeeklyDigest sends the owner a weekly usage summary
+	WeeklyDigest bool      `json:"WeeklyDigest,omitempty"`
+	DigestSentAt time.Time `json:"DigestSentAt,omitempty"`
+	LastActiveAt time.Time `json:"LastActiveAt,omitempty"`
+	CreatedAt    time.Time `json:"CreatedAt,omitempty"`
+	// DeletedAt and PurgeAt are set on a deleted (terminated) tenant, which
+	// can be restored until PurgeAt
+	DeletedAt time.Time `json:"DeletedAt,omitempty"`
+	PurgeAt   time.Time `json:"PurgeAt,omitempty"`
+}
+
+type CreateTenantRequest struct {
+	TenantID       string            `json:"tenant_id"`
+	BotToken       string            `json:"bot_token"`
+	IdleTimeoutS   int               `json:"idle_timeout_s"`
+	Tier           string            `json:"tier,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	DNS            *DNSConfig        `json:"dns,omitempty"`
+	KeepWarm       bool              `json:"keep_warm,omitempty"`
+	LogForward     *LogForwardConfig `json:"log_forward,omitempty"`
+	HomeRegion     string            `json:"home_region,omitempty"`
+	AllowedUpdates []string          `json:"allowed_updates,omitempty"`
+	Slack          *SlackConfig      `json:"slack,omitempty"`
+	Locale         string            `json:"locale,omitempty"`
+	Timezone       string            `json:"timezone,omitempty"`
+}
+
+// BulkCreateResult is one tenant's outcome in a bulk create: the HTTP
+// status a single create would have got, with the tenant or the error
+type BulkCreateResult struct {
+	TenantID string  `json:"tenant_id"`
+	Status   int     `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Tenant   *Tenant `json:"tenant,omitempty"`
+}
+
+type BulkCreateResponse struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []BulkCreateResult `json:"results"`
+}
+
+// ListOptions controls ordering of ListTenants results.
+// Sort is one of tenant_id, last_active_at, created_at, status; Order is asc or desc.
+type ListOptions struct {
+	Sort  string
+	Order string
+}
+
+type UpdateTenantRequest struct {
+	BotToken     *string    `json:"bot_token,omitempty"`
+	IdleTimeoutS *int       `json:"idle_timeout_s,omitempty"`
+	Tier         *string    `json:"tier,omitempty"`
+	Image        *string    `json:"image,omitempty"`
+	DNS          *DNSConfig `json:"dns,omitempty"` // empty object clears the override
+	KeepWarm     *bool      `json:"keep_warm,omitempty"`
+	// true leaves the owner out of POST /admin/broadcast announcements
+	NoAnnouncements *bool `json:"no_announcements,omitempty"`
+	// true sends the owner a weekly digest via the log forwarding targets
+	WeeklyDigest   *bool             `json:"weekly_digest,omitempty"`
+	LogForward     *LogForwardConfig `json:"log_forward,omitempty"`     // empty object disables forwarding
+	AllowedUpdates *[]string         `json:"allowed_updates,omitempty"` // empty list restores the default
+	Slack          *SlackConfig      `json:"slack,omitempty"`           // empty object disconnects Slack
+	Locale         *string           `json:"locale,omitempty"`          // empty restores the default (en)
+	Timezone       *string           `json:"timezone,omitempty"`        // empty restores the default (UTC)
+	Resize         bool              `json:"resize,omitempty"`          // with Tier: replace a running keep-warm pod now
+	Disabled       *bool             `json:"disabled,omitempty"`        // the tenant's kill switch
+	// 0 restores the router's MAX_MESSAGE_AGE_S, -1 turns it off
+	MaxMessageAgeS *int `json:"max_message_age_s,omitempty"`
+	// 0 restores the router's MAX_REPLY_BYTES, -1 allows any length
+	MaxReplyBytes *int `json:"max_reply_bytes,omitempty"`
+}
+
+// ResizeOp is a tenant's latest tier change and whether its pod runs the new
+// tier yet: pending (next wake), replacing, done or failed
+type ResizeOp struct {
+	FromTier    string    `json:"from_tier"`
+	ToTier      string    `json:"to_tier"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DNSConfig is a tenant's pod DNS override (dnsPolicy + dnsConfig)
+type DNSConfig struct {
+	Policy      string   `json:"policy,omitempty"`
+	Nameservers []string `json:"nameservers,omitempty"`
+	Searches    []string `json:"searches,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// IsZero reports whether c sets no DNS fields
+func (c DNSConfig) IsZero() bool {
+	return c.Policy == "" && len(c.Nameservers) == 0 && len(c.Searches) == 0 && len(c.Options) == 0
+}
+
+// LogForwardConfig selects where a tenant's agent error logs are forwarded
+type LogForwardConfig struct {
+	TelegramChatID int64  `json:"telegram_chat_id,omitempty"`
+	WebhookURL     string `json:"webhook_url,omitempty"`
+}
+
+// IsZero reports whether c sets no forwarding target
+func (c LogForwardConfig) IsZero() bool {
+	return c.TelegramChatID == 0 && c.WebhookURL == ""
+}
+
+// SlackConfig is a tenant's Slack app credentials
+type SlackConfig struct {
+	SigningSecret string `json:"signing_secret,omitempty"`
+	BotToken      string `json:"bot_token,omitempty"`
+}
+
+// IsZero reports whether c sets no credentials
+func (c SlackConfig) IsZero() bool {
+	return c.SigningSecret == "" && c.BotToken == ""
+}
+
+type WakeResponse struct {
+	PodIP        string `json:"pod_ip"`
+	IdleTimeoutS int    `json:"idle_timeout_s"`
+	// AlreadyRunning is set when an async wake found the pod running
+	AlreadyRunning bool `json:"already_running,omitempty"`
+}
+
+type RestartResult struct {
+	TenantID    string `json:"tenant_id"`
+	PodName     string `json:"pod_name"`
+	PodIP       string `json:"pod_ip"`
+	PreviousPod string `json:"previous_pod"`
+}
+
+type SleepResult struct {
+	TenantID string `json:"tenant_id"`
+	PodName  string `json:"pod_name"`
+}
+
+// SuspendResult is the response of POST /tenants/{id}/suspend and /resume
+type SuspendResult struct {
+	TenantID string `json:"tenant_id"`
+	Status   string `json:"status"`
+	PodName  string `json:"pod_name,omitempty"` // the pod stopped by a suspend
+}
+
+// WakeAttempt is one entry of a tenant's wake history
+type WakeAttempt struct {
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Start      string    `json:"start,omitempty"` // warm, cold, or empty if it failed first
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// TenantChange is an event of GET /tenants/watch: a tenant added (first
+// seen), modified or deleted. A deleted tenant carries only its TenantID.
+type TenantChange struct {
+	Type   string `json:"type"`
+	Tenant Tenant `json:"tenant"`
+}
+
+// TenantEvent is one entry of a tenant's event log, e.g. an OOM kill
+type TenantEvent struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"` // oom_killed, crash_loop, state_quota_warning, state_quota_exceeded, stuck_terminating, state_restore_failed, state_sync_failed, pod_modified or state_rolled_back
+	Pod      string    `json:"pod"`
+	Restarts int32     `json:"restarts"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// TenantState is a tenant's S3 state size against its storage quota
+type TenantState struct {
+	TenantID    string    `json:"tenant_id"`
+	Bytes       int64     `json:"bytes"`
+	QuotaBytes  int64     `json:"quota_bytes,omitempty"` // 0 = unlimited
+	UsedPercent int       `json:"used_percent,omitempty"`
+	Level       string    `json:"level"` // ok, warning or exceeded
+	MeasuredAt  time.Time `json:"measured_at,omitempty"`
+}
+
+// Snapshot is a point-in-time copy of a tenant's S3 state
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Objects   int       `json:"objects"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// SnapshotRequest is the optional body of POST /tenants/{id}/snapshots
+type SnapshotRequest struct {
+	// Quiesce stops a running pod first so the copy is consistent
+	Quiesce bool `json:"quiesce,omitempty"`
+}
+
+// SnapshotResult is the response of POST /tenants/{id}/snapshots
+type SnapshotResult struct {
+	TenantID string   `json:"tenant_id"`
+	Snapshot Snapshot `json:"snapshot"`
+	PodName  string   `json:"pod_name,omitempty"` // the pod stopped to quiesce the tenant
+}
+
+// SnapshotRestoreResult is the response of
+// POST /tenants/{id}/snapshots/{snapshotID}/restore
+type SnapshotRestoreResult struct {
+	TenantID string `json:"tenant_id"`
+	Snapshot string `json:"snapshot"`
+	Backup   string `json:"backup,omitempty"` // snapshot of the state the restore replaced
+	Objects  int    `json:"objects"`
+	PodName  string `json:"pod_name,omitempty"` // the pod stopped for the restore
+}
+
+// TenantBundle is a portable copy of a tenant from GET /tenants/{id}/export,
+// which POST /tenants/import recreates it from in another cluster
+type TenantBundle struct {
+	Version     int       `json:"version"`
+	ExportedAt  time.Time `json:"exported_at"`
+	Environment string    `json:"environment,omitempty"`
+	Region      string    `json:"region,omitempty"`
+	Tenant      *Tenant   `json:"tenant"` // settings only, without credentials
+	// Secrets names the credentials left out: bot_token and/or slack
+	Secrets []string          `json:"secrets,omitempty"`
+	KV      map[string]string `json:"kv,omitempty"`
+	State   *StateManifest    `json:"state,omitempty"` // nil without S3
+}
+
+// StateManifest lists the objects under a tenant's S3 prefix
+type StateManifest struct {
+	Prefix  string        `json:"prefix"`
+	Objects []StateObject `json:"objects"`
+	Bytes   int64         `json:"bytes"`
+}
+
+// StateObject is one object of a tenant's state
+type StateObject struct {
+	Key  string `json:"key"` // relative to the prefix
+	Size int64  `json:"size"`
+}
+
+// ImportTenantRequest is the body of POST /tenants/import
+type ImportTenantRequest struct {
+	Bundle TenantBundle `json:"bundle"`
+	// BotToken and Slack replace the credentials the bundle left out
+	BotToken string       `json:"bot_token,omitempty"`
+	Slack    *SlackConfig `json:"slack,omitempty"`
+	// HomeRegion defaults to the importing orchestrator's region
+	HomeRegion string `json:"home_region,omitempty"`
+	// AllowMissingState imports even if the state here doesn't match the
+	// bundle's manifest
+	AllowMissingState bool `json:"allow_missing_state,omitempty"`
+}
+
+// ImportResult is the response of POST /tenants/import
+type ImportResult struct {
+	Tenant         *Tenant  `json:"tenant"`
+	StateObjects   int      `json:"state_objects"`
+	MissingState   []string `json:"missing_state,omitempty"`
+	MissingSecrets []string `json:"missing_secrets,omitempty"`
+}
+
+// DeletePlan is what deleting a tenant would remove (a dry run), and what
+// it keeps for a restore until PurgeAt
+type DeletePlan struct {
+	TenantID     string    `json:"tenant_id"`
+	Deletes      []string  `json:"deletes"`
+	Keeps        []string  `json:"keeps,omitempty"`
+	PurgeAt      time.Time `json:"purge_at,omitempty"`
+	ConfirmToken string    `json:"confirm_token,omitempty"`
+	ExpiresInS   int       `json:"expires_in_s,omitempty"`
+}
+
+// WebhookInfo is Telegram's view of a tenant bot's webhook. ExpectedURL is
+// where the router wants it; URL is where it actually points ("" if unset).
+type WebhookInfo struct {
+	TenantID           string     `json:"tenant_id"`
+	URL                string     `json:"url"`
+	ExpectedURL        string     `json:"expected_url"`
+	PendingUpdateCount int        `json:"pending_update_count"`
+	LastErrorAt        *time.Time `json:"last_error_at,omitempty"`
+	LastErrorMessage   string     `json:"last_error_message,omitempty"`
+	AllowedUpdates     []string   `json:"allowed_updates,omitempty"`
+	MaxConnections     int        `json:"max_connections,omitempty"`
+	IPAddress          string     `json:"ip_address,omitempty"`
+}
+
+type ChatTenant struct {
+	TenantID   string    `json:"tenant_id"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+type DeepLink struct {
+	TenantID    string `json:"tenant_id"`
+	BotUsername string `json:"bot_username"`
+	URL         string `json:"url"`
+}
+
+// StatusLink is a signed, expiring link to a tenant's public status page
+type StatusLink struct {
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ChatLink is a signed, expiring link to a tenant's web chat page
+type ChatLink struct {
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ImageAlias struct {
+	Alias     string    `json:"alias"`
+	Image     string    `json:"image"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Rollout is a rolling restart of running tenants onto their current image
+type Rollout struct {
+	ID             string    `json:"rollout_id"`
+	Status         string    `json:"status"` // running, done or failed
+	MaxUnavailable int       `json:"max_unavailable"`
+	Total          int       `json:"total"` // running tenants on an outdated image
+	Restarted      []string  `json:"restarted"`
+	Skipped        []string  `json:"skipped,omitempty"`
+	Failed         []string  `json:"failed,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Broadcast is an announcement to every tenant's owner and its delivery
+// report
+type Broadcast struct {
+	ID        string             `json:"broadcast_id"`
+	Status    string             `json:"status"` // running, done or failed
+	Text      string             `json:"text"`
+	Total     int                `json:"total"` // tenants in the environment
+	Sent      []string           `json:"sent"`
+	OptedOut  []string           `json:"opted_out,omitempty"`  // no_announcements is set
+	NoChannel []string           `json:"no_channel,omitempty"` // no log_forward target
+	Failed    []BroadcastFailure `json:"failed,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	StartedAt time.Time          `json:"started_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// BroadcastFailure is a tenant an announcement couldn't be delivered to
+type BroadcastFailure struct {
+	TenantID string `json:"tenant_id"`
+	Error    string `json:"error"`
+}
+
+// StartBroadcastRequest is POST /admin/broadcast's body
+type StartBroadcastRequest struct {
+	Text string `json:"text"`
+}
+
+// KillSwitch is the environment-wide kill switch
+type KillSwitch struct {
+	Active      bool      `json:"active"`
+	Reason      string    `json:"reason,omitempty"`
+	Message     string    `json:"message,omitempty"` // replaces the default outage reply
+	ActivatedBy string    `json:"activated_by,omitempty"`
+	ActivatedAt time.Time `json:"activated_at,omitempty"`
+}
+
+// SetKillSwitchRequest turns the kill switch on (with a reason) or off
+type SetKillSwitchRequest struct {
+	Active  bool   `json:"active"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// BotToken is GET /tenants/{id}/bot_token, for the router alone
+type BotToken struct {
+	BotToken      string `json:"BotToken"`
+	WebhookSecret string `json:"WebhookSecret"`
+}
+
+// Activity is PUT /tenants/{id}/activity's body: the text a delivered
+// message carried each way, in characters, and the chat it came from, which
+// the orchestrator counts among the tenant's distinct chats
+type Activity struct {
+	CharsIn  int64  `json:"chars_in"`
+	CharsOut int64  `json:"chars_out"`
+	ChatID   string `json:"chat_id,omitempty"`
+}
+
+// WakeJob is an async wake (POST /wake/{id}?async=true, GET
+// /wake-jobs/{jobID}): pending, provisioning, restoring (the pod runs but
+// the agent is still restoring its state), ready or failed. A synchronous
+// wake answers only PodIP and IdleTimeoutS.
+type WakeJob struct {
+	ID              string    `json:"job_id,omitempty"`
+	TenantID        string    `json:"tenant_id,omitempty"`
+	Status          string    `json:"status,omitempty"`
+	PodIP           string    `json:"pod_ip,omitempty"`
+	IdleTimeoutS    int64     `json:"idle_timeout_s,omitempty"`
+	RestoreProgress int       `json:"restore_progress,omitempty"` // percent
+	RestoreMessage  string    `json:"restore_message,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	CreatedAt       time.Time `json:"created_at,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at,omitempty"`
+}
+
+// Output is a long reply saved with POST /tenants/{id}/outputs
+type Output struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Usage is a tenant's metered usage over a day or a range of days
+type Usage struct {
+	Messages   int64 `json:"messages"`
+	Wakes      int64 `json:"wakes"`
+	PodSeconds int64 `json:"pod_seconds"`
+	CharsIn    int64 `json:"chars_in"`
+	CharsOut   int64 `json:"chars_out"`
+	TokensIn   int64 `json:"tokens_in"`  // estimated from CharsIn
+	TokensOut  int64 `json:"tokens_out"` // estimated from CharsOut
+}
+
+// UsageDay is one day of a UsageReport
+type UsageDay struct {
+	Date string `json:"date"` // YYYY-MM-DD, UTC
+	Usage
+	Chats int64 `json:"chats"` // distinct chats that day
+}
+
+// UsageMonth is a calendar month of a UsageReport: its distinct chats over
+// the whole month, and its messages within the report's days
+type UsageMonth struct {
+	Month    string `json:"month"` // YYYY-MM, UTC
+	Chats    int64  `json:"chats"`
+	Messages int64  `json:"messages"`
+}
+
+// UsageReport is GET /tenants/{id}/usage: per-day usage from From to To
+// (inclusive), their total, and the months they fall in
+type UsageReport struct {
+	TenantID string       `json:"tenant_id"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Days     []UsageDay   `json:"days"`
+	Total    Usage        `json:"total"`
+	Months   []UsageMonth `json:"months"`
+}
+
+// PublicStatus is the data behind a tenant's public status page
+type PublicStatus struct {
+	TenantID      string    `json:"tenant_id"`
+	BotUsername   string    `json:"bot_username,omitempty"`
+	State         string    `json:"state"` // online, sleeping, paused or starting
+	LastActiveAt  time.Time `json:"last_active_at,omitempty"`
+	Wakes         int       `json:"wakes"`
+	FailedWakes   int       `json:"failed_wakes"`
+	UptimePercent float64   `json:"uptime_percent"`
+	Incidents     int       `json:"incidents"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// TestMessageRequest is POST /tenants/{id}/test-message's body
+type TestMessageRequest struct {
+	Text string `json:"text"`
+}
+
+// TestMessageResult is the agent's reply to a test message, with how long
+// the wake and the reply took
+type TestMessageResult struct {
+	TenantID  string `json:"tenant_id"`
+	PodIP     string `json:"pod_ip"`
+	ColdStart bool   `json:"cold_start"` // the pod had to be started
+	WakeMs    int64  `json:"wake_ms"`
+	ReplyMs   int64  `json:"reply_ms"`
+	Reply     string `json:"reply"`
+}
+
+// StatusLinkRequest is POST /tenants/{id}/status-link's optional body
+type StatusLinkRequest struct {
+	TTLS int64 `json:"ttl_s,omitempty"` // 0 means the orchestrator's default
+}
+
+// ChatLinkRequest is POST /tenants/{id}/chat-link's optional body
+type ChatLinkRequest struct {
+	TTLS int64 `json:"ttl_s,omitempty"` // 0 means the orchestrator's default
+}
+
+// StartRolloutRequest is POST /admin/rollout's optional body
+type StartRolloutRequest struct {
+	MaxUnavailable int `json:"max_unavailable,omitempty"`
+}
+
+// WebhookSecretRequest is PUT /tenants/{id}/webhook_secret's body
+type WebhookSecretRequest struct {
+	WebhookSecret string `json:"webhook_secret"`
+}
+
+// KVList is GET /tenants/{id}/kv
+type KVList struct {
+	TenantID string            `json:"tenant_id"`
+	KV       map[string]string `json:"kv"`
+}
+
+// KVPair is GET /tenants/{id}/kv/{key}
+type KVPair struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PutKVRequest is PUT /tenants/{id}/kv/{key}'s body
+type PutKVRequest struct {
+	Value string `json:"value"`
+}
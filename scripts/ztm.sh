@@ -15,6 +15,7 @@
 #   ZTM_ROUTER_URL         Router public base URL (default: https://<YOUR_ROUTER_DOMAIN>)
 #   ZTM_NAMESPACE          Kubernetes namespace (default: tenants)
 #   ZTM_KUBE_CONTEXT       kubectl context (default: current context)
+#   ZTM_API_KEY            Orchestrator API key (when API_KEYS/API_KEYS_TABLE is set)
 
 set -euo pipefail
 
@@ -24,6 +25,7 @@ ORCHESTRATOR_URL="${ZTM_ORCHESTRATOR_URL:-}"
 ROUTER_URL="${ZTM_ROUTER_URL:-}"
 NAMESPACE="${ZTM_NAMESPACE:-tenants}"
 KUBE_CONTEXT="${ZTM_KUBE_CONTEXT:-}"
+API_KEY="${ZTM_API_KEY:-}"
 
 # ── Helpers ───────────────────────────────────────────────────────────────────
 
@@ -80,18 +82,21 @@ orch_api() {
   if [[ -n "$ORCHESTRATOR_URL" ]]; then
     # Direct HTTP call
     local url="${ORCHESTRATOR_URL}${path}"
+    local curl_args=()
+    [[ -n "$API_KEY" ]] && curl_args+=(-H "Authorization: Bearer $API_KEY")
     if [[ -n "$data" ]]; then
-      curl -sf -X "$method" "$url" \
+      curl -sf -X "$method" "$url" ${curl_args[@]+"${curl_args[@]}"} \
         -H "Content-Type: application/json" \
         -d "$data"
     else
-      curl -sf -X "$method" "$url"
+      curl -sf -X "$method" "$url" ${curl_args[@]+"${curl_args[@]}"}
     fi
   else
     # In-cluster via kubectl exec
     local wget_args=()
     wget_args+=(--method="$method")
     wget_args+=(--header="Content-Type: application/json")
+    [[ -n "$API_KEY" ]] && wget_args+=(--header="Authorization: Bearer $API_KEY")
     [[ -n "$data" ]] && wget_args+=(--body-data="$data")
     kubectl_exec wget -qO- "${wget_args[@]}" "http://localhost:8080${path}"
   fi